@@ -8,12 +8,22 @@ type queryTraceIDCmd struct {
 	APIEndpoint string `arg:"" help:"tempo api endpoint"`
 	TraceID     string `arg:"" help:"trace ID to retrieve"`
 
-	OrgID string `help:"optional orgID"`
+	OrgID   string `help:"optional orgID"`
+	Summary bool   `help:"return a summary of the trace structure instead of the trace"`
 }
 
 func (cmd *queryTraceIDCmd) Run(_ *globalOptions) error {
 	client := util.NewClient(cmd.APIEndpoint, cmd.OrgID)
 
+	if cmd.Summary {
+		summary, err := client.QueryTraceSummary(cmd.TraceID)
+		if err != nil {
+			return err
+		}
+
+		return printAsJSON(summary)
+	}
+
 	// util.QueryTrace will only add orgID header if len(orgID) > 0
 	trace, err := client.QueryTrace(cmd.TraceID)
 	if err != nil {
@@ -10,7 +10,10 @@ import (
 
 	"github.com/grafana/tempo/modules/frontend/transport"
 	v1 "github.com/grafana/tempo/modules/frontend/v1"
+	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/usagestats"
+	"github.com/grafana/tempo/tempodb/backend/cache/memcached"
+	"github.com/grafana/tempo/tempodb/backend/cache/redis"
 )
 
 var (
@@ -27,13 +30,23 @@ type Config struct {
 type SearchConfig struct {
 	Sharder SearchSharderConfig `yaml:",inline"`
 	SLO     SLOConfig           `yaml:",inline"`
+	Cache   SearchCacheConfig   `yaml:"cache"`
+}
+
+type SearchCacheConfig struct {
+	Backend         string                  `yaml:"backend"`
+	MinBlockAge     time.Duration           `yaml:"min_block_age"`
+	BackgroundCache *cache.BackgroundConfig `yaml:"background_cache"`
+	Memcached       *memcached.Config       `yaml:"memcached"`
+	Redis           *redis.Config           `yaml:"redis"`
 }
 
 type TraceByIDConfig struct {
-	QueryShards      int           `yaml:"query_shards,omitempty"`
-	ConcurrentShards int           `yaml:"concurrent_shards,omitempty"`
-	Hedging          HedgingConfig `yaml:",inline"`
-	SLO              SLOConfig     `yaml:",inline"`
+	QueryShards         int           `yaml:"query_shards,omitempty"`
+	ConcurrentShards    int           `yaml:"concurrent_shards,omitempty"`
+	MaxTraceIDsPerBatch int           `yaml:"max_trace_ids_per_batch,omitempty"`
+	Hedging             HedgingConfig `yaml:",inline"`
+	SLO                 SLOConfig     `yaml:",inline"`
 }
 
 type HedgingConfig struct {
@@ -56,19 +69,29 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	cfg.MaxRetries = 2
 	cfg.Search = SearchConfig{
 		Sharder: SearchSharderConfig{
-			QueryBackendAfter:     15 * time.Minute,
-			QueryIngestersUntil:   30 * time.Minute,
-			DefaultLimit:          20,
-			MaxLimit:              0,
-			MaxDuration:           168 * time.Hour, // 1 week
-			ConcurrentRequests:    defaultConcurrentRequests,
-			TargetBytesPerRequest: defaultTargetBytesPerRequest,
+			QueryBackendAfter:        15 * time.Minute,
+			QueryIngestersUntil:      30 * time.Minute,
+			DefaultLimit:             20,
+			MaxLimit:                 0,
+			MaxDuration:              168 * time.Hour, // 1 week
+			ConcurrentRequests:       defaultConcurrentRequests,
+			TargetBytesPerRequest:    defaultTargetBytesPerRequest,
+			MinTargetBytesPerRequest: defaultMinTargetBytesPerRequest,
+			MaxTargetBytesPerRequest: defaultMaxTargetBytesPerRequest,
 		},
 		SLO: slo,
+		Cache: SearchCacheConfig{
+			MinBlockAge: time.Hour,
+			BackgroundCache: &cache.BackgroundConfig{
+				WriteBackGoroutines: 10,
+				WriteBackBuffer:     10000,
+			},
+		},
 	}
 	cfg.TraceByID = TraceByIDConfig{
-		QueryShards: 50,
-		SLO:         slo,
+		QueryShards:         50,
+		MaxTraceIDsPerBatch: 100,
+		SLO:                 slo,
 		Hedging: HedgingConfig{
 			HedgeRequestsAt:   2 * time.Second,
 			HedgeRequestsUpTo: 2,
@@ -0,0 +1,50 @@
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+)
+
+// newCriticalPathHandler returns a round tripper that loads a trace through the trace by id round tripper
+// and responds with its critical path.
+func newCriticalPathHandler(traces http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		span, ctx := opentracing.StartSpanFromContext(r.Context(), "frontend.CriticalPath")
+		defer span.Finish()
+		r = r.WithContext(ctx)
+
+		if _, err := api.ParseTraceID(r); err != nil {
+			return badRequest(err.Error()), nil
+		}
+
+		t, resp, err := fetchTrace(traces, r, mux.Vars(r)[api.URLParamTraceID])
+		if err != nil || resp != nil {
+			return resp, err
+		}
+
+		path := trace.FindCriticalPath(t)
+		span.SetTag("spans", len(path.Spans))
+
+		body, err := json.Marshal(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				api.HeaderContentType: {api.HeaderAcceptJSON},
+			},
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+		}, nil
+	})
+}
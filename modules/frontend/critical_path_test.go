@@ -0,0 +1,62 @@
+package frontend
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestCriticalPathHandler(t *testing.T) {
+	traceID := []byte{0x0a}
+
+	// the root waits on c, which waits on d. b overlaps c and isn't on the path
+	tr := makeFakeTrace(traceID,
+		makeFakeSpan(traceID, 0x01, 0x00, "a", 0, 100),
+		makeFakeSpan(traceID, 0x02, 0x01, "b", 10, 40),
+		makeFakeSpan(traceID, 0x03, 0x01, "c", 30, 80),
+		makeFakeSpan(traceID, 0x04, 0x03, "d", 40, 60),
+	)
+	rt := newCriticalPathHandler(newTraceByIDFake(t, "/tempo", map[string]*tempopb.Trace{"0a": tr}))
+
+	request := func(traceID string) *http.Response {
+		return roundTripVars(t, rt, "/tempo/api/traces/"+traceID+"/critical-path", map[string]string{api.URLParamTraceID: traceID})
+	}
+
+	resp := request("0a")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	actual := &trace.CriticalPath{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(actual))
+	require.Equal(t, uint64(100), actual.DurationNanos)
+
+	type expectedSpan struct {
+		id       string
+		name     string
+		self     uint64
+		blocking uint64
+	}
+	expected := []expectedSpan{
+		{id: "0000000000000001", name: "a", self: 50, blocking: 50},
+		{id: "0000000000000003", name: "c", self: 30, blocking: 20},
+		{id: "0000000000000004", name: "d", self: 20},
+	}
+	require.Len(t, actual.Spans, len(expected))
+	for i, e := range expected {
+		require.Equal(t, e.id, actual.Spans[i].SpanID)
+		require.Equal(t, e.name, actual.Spans[i].Name)
+		require.Equal(t, e.self, actual.Spans[i].SelfTimeNanos, e.id)
+		require.Equal(t, e.blocking, actual.Spans[i].BlockingTimeNanos, e.id)
+	}
+
+	// errors of the trace by id request are passed on
+	resp = request("0b")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = request("zz")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -89,6 +89,17 @@ func (s spanIDDeduper) RoundTrip(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+// dedupeSpanIDs dedupes the span IDs of a trace in place like the deduper middleware. It's used by
+// requests that return more than one trace.
+func dedupeSpanIDs(t *tempopb.Trace) {
+	if t == nil {
+		return
+	}
+
+	s := spanIDDeduper{trace: t}
+	s.dedupe()
+}
+
 func (s *spanIDDeduper) dedupe() {
 	s.groupSpansByID()
 	s.dedupeSpanIDs()
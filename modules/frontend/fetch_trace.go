@@ -0,0 +1,51 @@
+package frontend
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto" //nolint:all //deprecated
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// fetchTrace loads a trace through the trace by id round tripper. The request is built from the parent,
+// keeping its prefix, query parameters and headers. If the trace by id request fails, its response is returned
+// to be passed on to the client.
+func fetchTrace(traces http.RoundTripper, parent *http.Request, traceID string) (*tempopb.Trace, *http.Response, error) {
+	prefix := parent.URL.Path
+	if i := strings.Index(prefix, "/api/"); i >= 0 {
+		prefix = prefix[:i]
+	}
+
+	req := parent.Clone(parent.Context())
+	req.URL.Path = prefix + strings.Replace(api.PathTraces, "{"+api.URLParamTraceID+"}", traceID, 1)
+	req.RequestURI = req.URL.RequestURI()
+	req.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+	req = mux.SetURLVars(req, map[string]string{api.URLParamTraceID: traceID})
+
+	resp, err := traces.RoundTrip(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error reading trace by id response body")
+	}
+
+	t := &tempopb.Trace{}
+	if err := proto.Unmarshal(body, t); err != nil {
+		return nil, nil, err
+	}
+
+	return t, nil, nil
+}
@@ -0,0 +1,88 @@
+package frontend
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+// newTraceByIDFake returns a trace by id round tripper that responds with the proto encoded traces keyed by the
+// trace id in the url. Requests are expected under the given path prefix, unknown traces are not found.
+func newTraceByIDFake(t *testing.T, prefix string, traces map[string]*tempopb.Trace) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		id := mux.Vars(r)[api.URLParamTraceID]
+		require.Equal(t, prefix+"/api/traces/"+id, r.URL.Path)
+		require.Equal(t, api.HeaderAcceptProtobuf, r.Header.Get(api.HeaderAccept))
+
+		tr, ok := traces[id]
+		if !ok {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader("trace not found")),
+			}, nil
+		}
+
+		body, err := proto.Marshal(tr)
+		require.NoError(t, err)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}, nil
+	})
+}
+
+// roundTripVars sends a GET request for the path with the mux vars set to rt
+func roundTripVars(t *testing.T, rt http.RoundTripper, path string, vars map[string]string) *http.Response {
+	req := httptest.NewRequest("GET", path, nil)
+	req = mux.SetURLVars(req, vars)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func makeFakeTrace(traceID []byte, spans ...*v1.Span) *tempopb.Trace {
+	batch := test.MakeBatch(1, traceID)
+	batch.ScopeSpans = batch.ScopeSpans[:1]
+	batch.ScopeSpans[0].Spans = spans
+	return &tempopb.Trace{Batches: []*v1.ResourceSpans{batch}}
+}
+
+func makeFakeSpan(traceID []byte, id, parent byte, name string, start, end uint64) *v1.Span {
+	s := test.MakeSpan(traceID)
+	s.SpanId = []byte{0, 0, 0, 0, 0, 0, 0, id}
+	s.ParentSpanId = []byte{0, 0, 0, 0, 0, 0, 0, parent}
+	s.Name = name
+	s.StartTimeUnixNano = start
+	s.EndTimeUnixNano = end
+	return s
+}
+
+func TestFetchTrace(t *testing.T) {
+	traceID := []byte{0x0a}
+	tr := makeFakeTrace(traceID, makeFakeSpan(traceID, 0x01, 0x00, "root", 0, 100))
+	traces := newTraceByIDFake(t, "/tempo", map[string]*tempopb.Trace{"0a": tr})
+
+	parent := httptest.NewRequest("GET", "/tempo/api/traces/0b/critical-path", nil)
+	actual, resp, err := fetchTrace(traces, parent, "0a")
+	require.NoError(t, err)
+	require.Nil(t, resp)
+	require.True(t, proto.Equal(tr, actual))
+
+	// the response of a failed request is returned as is
+	actual, resp, err = fetchTrace(traces, parent, "0c")
+	require.NoError(t, err)
+	require.Nil(t, actual)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -26,17 +26,24 @@ import (
 )
 
 const (
-	traceByIDOp = "traces"
-	searchOp    = "search"
-	metricsOp   = "metrics"
+	traceByIDOp    = "traces"
+	tracesByIDOp   = "traces_by_id"
+	criticalPathOp = "critical_path"
+	traceDiffOp    = "trace_diff"
+	traceSummaryOp = "trace_summary"
+	searchOp       = "search"
+	metricsOp      = "metrics"
+	queryRangeOp   = "query_range"
+	explainOp      = "explain"
 )
 
 type streamingSearchHandler func(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error
 
 type QueryFrontend struct {
-	TraceByIDHandler, SearchHandler, SpanMetricsSummaryHandler http.Handler
-	streamingSearch                                            streamingSearchHandler
-	logger                                                     log.Logger
+	TraceByIDHandler, TracesByIDHandler, CriticalPathHandler, TraceDiffHandler, TraceSummaryHandler, SearchHandler, SearchExplainHandler, SpanMetricsSummaryHandler, QueryRangeHandler http.Handler
+	streamingSearch                                                                                                                                                                    streamingSearchHandler
+	streamingTraceByID                                                                                                                                                                 streamingTraceByIDHandler
+	logger                                                                                                                                                                             log.Logger
 }
 
 // New returns a new QueryFrontend
@@ -55,10 +62,19 @@ func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempo
 		return nil, fmt.Errorf("frontend search target bytes per request should be greater than 0")
 	}
 
+	if cfg.Search.Sharder.MaxTargetBytesPerRequest > 0 && cfg.Search.Sharder.MinTargetBytesPerRequest > cfg.Search.Sharder.MaxTargetBytesPerRequest {
+		return nil, fmt.Errorf("frontend search min target bytes per request should be less than or equal to max target bytes per request")
+	}
+
 	if cfg.Search.Sharder.QueryIngestersUntil < cfg.Search.Sharder.QueryBackendAfter {
 		return nil, fmt.Errorf("query backend after should be less than or equal to query ingester until")
 	}
 
+	searchCache, err := newSearchJobCache(cfg.Search.Cache, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	queriesPerTenant := promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
 		Namespace: "tempo",
 		Name:      "query_frontend_queries_total",
@@ -69,21 +85,40 @@ func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempo
 
 	// tracebyid middleware
 	traceByIDMiddleware := MergeMiddlewares(newTraceByIDMiddleware(cfg, logger), retryWare)
-	searchMiddleware := MergeMiddlewares(newSearchMiddleware(cfg, o, reader, logger), retryWare)
+	tracesByIDMiddleware := MergeMiddlewares(newTracesByIDMiddleware(cfg, logger), retryWare)
+	traceSummaryMiddleware := MergeMiddlewares(newTraceSummaryMiddleware(), retryWare)
+	searchMiddleware := MergeMiddlewares(newSearchMiddleware(cfg, o, reader, searchCache, logger), retryWare)
 	spanMetricsMiddleware := MergeMiddlewares(newSpanMetricsMiddleware(cfg, o, reader, logger), retryWare)
+	queryRangeMiddleware := MergeMiddlewares(newQueryRangeSharder(reader, o, cfg.Search.Sharder, logger), retryWare)
 
 	traceByIDCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": traceByIDOp})
+	tracesByIDCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": tracesByIDOp})
+	criticalPathCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": criticalPathOp})
+	traceDiffCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": traceDiffOp})
+	traceSummaryCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": traceSummaryOp})
 	searchCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": searchOp})
 	spanMetricsCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": metricsOp})
+	queryRangeCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": queryRangeOp})
+	explainCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": explainOp})
 
 	traces := traceByIDMiddleware.Wrap(next)
+	tracesByID := tracesByIDMiddleware.Wrap(next)
+	traceSummary := traceSummaryMiddleware.Wrap(next)
 	search := searchMiddleware.Wrap(next)
 	metrics := spanMetricsMiddleware.Wrap(next)
+	queryRange := queryRangeMiddleware.Wrap(next)
 	return &QueryFrontend{
 		TraceByIDHandler:          newHandler(traces, traceByIDCounter, logger),
+		TracesByIDHandler:         newHandler(tracesByID, tracesByIDCounter, logger),
+		CriticalPathHandler:       newHandler(newCriticalPathHandler(traces), criticalPathCounter, logger),
+		TraceDiffHandler:          newHandler(newTraceDiffHandler(traces), traceDiffCounter, logger),
+		TraceSummaryHandler:       newHandler(traceSummary, traceSummaryCounter, logger),
 		SearchHandler:             newHandler(search, searchCounter, logger),
+		SearchExplainHandler:      newHandler(newSearchExplainer(reader, o, cfg.Search.Sharder, logger), explainCounter, logger),
 		SpanMetricsSummaryHandler: newHandler(metrics, spanMetricsCounter, logger),
-		streamingSearch:           newSearchStreamingHandler(cfg, o, retryWare.Wrap(next), reader, apiPrefix, logger),
+		QueryRangeHandler:         newHandler(queryRange, queryRangeCounter, logger),
+		streamingSearch:           newSearchStreamingHandler(cfg, o, searchCache, retryWare.Wrap(next), reader, apiPrefix, logger),
+		streamingTraceByID:        newTraceByIDStreamingHandler(cfg, retryWare.Wrap(next), apiPrefix, logger),
 		logger:                    logger,
 	}, nil
 }
@@ -92,6 +127,10 @@ func (q *QueryFrontend) Search(req *tempopb.SearchRequest, srv tempopb.Streaming
 	return q.streamingSearch(req, srv)
 }
 
+func (q *QueryFrontend) TraceByID(req *tempopb.TraceByIDStreamRequest, srv tempopb.StreamingQuerier_TraceByIDServer) error {
+	return q.streamingTraceByID(req, srv)
+}
+
 // newTraceByIDMiddleware creates a new frontend middleware responsible for handling get traces requests.
 func newTraceByIDMiddleware(cfg Config, logger log.Logger) Middleware {
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
@@ -129,8 +168,11 @@ func newTraceByIDMiddleware(cfg Config, logger log.Logger) Middleware {
 
 			// check marshalling format
 			marshallingFormat := api.HeaderAcceptJSON
-			if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
-				marshallingFormat = api.HeaderAcceptProtobuf
+			if accept := r.Header.Get(api.HeaderAccept); accept == api.HeaderAcceptProtobuf || accept == api.HeaderAcceptOTLPProtobuf {
+				marshallingFormat = accept
+			} else if accept == api.HeaderAcceptOTLPJSON && !api.IsTraceByIDV2(r) {
+				// v2 json already encodes the trace as otlp/json
+				marshallingFormat = accept
 			}
 
 			// enforce all communication internal to Tempo to be in protobuf bytes
@@ -151,21 +193,104 @@ func newTraceByIDMiddleware(cfg Config, logger log.Logger) Middleware {
 					return nil, err
 				}
 
-				if marshallingFormat == api.HeaderAcceptJSON {
-					var jsonTrace bytes.Buffer
-					marshaller := &jsonpb.Marshaler{}
-					err = marshaller.Marshal(&jsonTrace, responseObject.Trace)
-					if err != nil {
-						return nil, err
-					}
-					resp.Body = io.NopCloser(bytes.NewReader(jsonTrace.Bytes()))
-				} else {
-					traceBuffer, err := proto.Marshal(responseObject.Trace)
-					if err != nil {
-						return nil, err
-					}
-					resp.Body = io.NopCloser(bytes.NewReader(traceBuffer))
+				body, err = marshalTraceByIDResponse(responseObject, marshallingFormat, api.IsTraceByIDV2(r))
+				if err != nil {
+					return nil, err
+				}
+				resp.Body = io.NopCloser(bytes.NewReader(body))
+				resp.ContentLength = int64(len(body))
+
+				if resp.Header != nil {
+					resp.Header.Set(api.HeaderContentType, marshallingFormat)
+				}
+			}
+			span := opentracing.SpanFromContext(r.Context())
+			if span != nil {
+				span.SetTag("contentType", marshallingFormat)
+			}
+
+			return resp, err
+		})
+	})
+}
+
+// marshalTraceByIDResponse encodes the response of the trace by id endpoints in the requested format. The
+// v1 endpoint returns only the trace, the v2 endpoint the trace alongside metrics and partial status. Traces
+// are OTLP encoded except for the v1 json and protobuf formats.
+func marshalTraceByIDResponse(resp *tempopb.TraceByIDResponse, format string, v2 bool) ([]byte, error) {
+	switch {
+	case format == api.HeaderAcceptOTLPProtobuf:
+		return api.MarshalTraceOTLPProto(resp.Trace)
+	case format == api.HeaderAcceptOTLPJSON && !v2:
+		return api.MarshalTraceOTLPJSON(resp.Trace)
+	case format == api.HeaderAcceptProtobuf && v2:
+		return proto.Marshal(resp)
+	case format == api.HeaderAcceptProtobuf:
+		return proto.Marshal(resp.Trace)
+	case v2:
+		return api.MarshalTraceByIDResponseV2JSON(resp)
+	}
+
+	var jsonTrace bytes.Buffer
+	marshaller := &jsonpb.Marshaler{}
+	if err := marshaller.Marshal(&jsonTrace, resp.Trace); err != nil {
+		return nil, err
+	}
+	return jsonTrace.Bytes(), nil
+}
+
+// newTracesByIDMiddleware creates a new frontend middleware responsible for handling batch trace by id requests.
+func newTracesByIDMiddleware(cfg Config, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		// Hedged requests of the trace by id middleware are not used, they would share the body of the
+		// request. The sharder dedupes the span IDs of every trace instead of the deduper.
+		rt := NewRoundTripper(
+			next,
+			newTracesByIDSharder(&cfg.TraceByID, logger),
+		)
+
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			// validate start and end parameter
+			_, _, _, _, _, reqErr := api.ValidateAndSanitizeRequest(r)
+			if reqErr != nil {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       io.NopCloser(strings.NewReader(reqErr.Error())),
+					Header:     http.Header{},
+				}, nil
+			}
+
+			// check marshalling format
+			marshallingFormat := api.HeaderAcceptJSON
+			if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
+				marshallingFormat = api.HeaderAcceptProtobuf
+			}
+
+			// enforce all communication internal to Tempo to be in protobuf bytes
+			r.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+
+			resp, err := rt.RoundTrip(r)
+
+			if resp != nil && resp.StatusCode == http.StatusOK && marshallingFormat == api.HeaderAcceptJSON {
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					return nil, errors.Wrap(err, "error reading response body at query frontend")
+				}
+				responseObject := &tempopb.TracesByIDResponse{}
+				err = proto.Unmarshal(body, responseObject)
+				if err != nil {
+					return nil, err
+				}
+
+				var jsonTraces bytes.Buffer
+				marshaller := &jsonpb.Marshaler{}
+				err = marshaller.Marshal(&jsonTraces, responseObject)
+				if err != nil {
+					return nil, err
 				}
+				resp.Body = io.NopCloser(bytes.NewReader(jsonTraces.Bytes()))
+				resp.ContentLength = int64(jsonTraces.Len())
 
 				if resp.Header != nil {
 					resp.Header.Set(api.HeaderContentType, marshallingFormat)
@@ -181,13 +306,43 @@ func newTraceByIDMiddleware(cfg Config, logger log.Logger) Middleware {
 	})
 }
 
+// newTraceSummaryMiddleware creates a new frontend middleware that proxies trace summary requests to a single
+// querier. The querier combines the trace from the ingesters and the blocks and only returns its summary.
+func newTraceSummaryMiddleware() Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if _, err := api.ParseTraceID(r); err != nil {
+				return badRequest(err.Error()), nil
+			}
+
+			// validate start and end parameter
+			if _, _, _, _, _, err := api.ValidateAndSanitizeRequest(r); err != nil {
+				return badRequest(err.Error()), nil
+			}
+
+			orgID, _ := user.ExtractOrgID(r.Context())
+
+			r.Header.Set(user.OrgIDHeaderName, orgID)
+			r.RequestURI = buildUpstreamRequestURI(r.RequestURI, nil)
+
+			return next.RoundTrip(r)
+		})
+	})
+}
+
 // newSearchMiddleware creates a new frontend middleware to handle search and search tags requests.
-func newSearchMiddleware(cfg Config, o overrides.Interface, reader tempodb.Reader, logger log.Logger) Middleware {
+func newSearchMiddleware(cfg Config, o overrides.Interface, reader tempodb.Reader, cache *searchJobCache, logger log.Logger) Middleware {
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
 		ingesterSearchRT := next
-		backendSearchRT := NewRoundTripper(next, newSearchSharder(reader, o, cfg.Search.Sharder, cfg.Search.SLO, newSearchProgress, logger))
+		backendSearchRT := NewRoundTripper(next, newSearchSharder(reader, o, cache, cfg.Search.Sharder, cfg.Search.SLO, newSearchProgress, logger))
+		searchTagsRT := NewRoundTripper(next, newSearchTagsSharder(reader, o, cfg.Search.Sharder, logger))
 
 		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			// tags and tag values are searched in the backend blocks of the requested range as well
+			if api.IsSearchTags(r) {
+				return searchTagsRT.RoundTrip(r)
+			}
+
 			// backend search queries require sharding so we pass through a special roundtripper
 			if api.IsBackendSearch(r) {
 				return backendSearchRT.RoundTrip(r)
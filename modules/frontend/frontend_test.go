@@ -9,8 +9,14 @@ import (
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
 )
 
 type mockNextTripperware struct{}
@@ -130,3 +136,61 @@ func TestFrontendBadConfigFails(t *testing.T) {
 	assert.EqualError(t, err, "query backend after should be less than or equal to query ingester until")
 	assert.Nil(t, f)
 }
+
+func TestTraceSummaryMiddleware(t *testing.T) {
+	var upstreamURI string
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		upstreamURI = r.RequestURI
+		require.Equal(t, "blerg", r.Header.Get(user.OrgIDHeaderName))
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader([]byte("summary"))),
+		}, nil
+	})
+	rt := newTraceSummaryMiddleware().Wrap(next)
+
+	request := func(traceID, query string) *http.Response {
+		req := httptest.NewRequest("GET", "/api/traces/"+traceID+"/summary"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{api.URLParamTraceID: traceID})
+		req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// the request is passed on to a single querier
+	resp := request("0a", "?start=1&end=2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "/querier/api/traces/0a/summary?start=1&end=2", upstreamURI)
+
+	resp = request("zz", "")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = request("0a", "?start=foo")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestMarshalTraceByIDResponse(t *testing.T) {
+	resp := &tempopb.TraceByIDResponse{
+		Trace:   test.MakeTrace(1, []byte{0x0a}),
+		Metrics: &tempopb.TraceByIDMetrics{TotalJobs: 1},
+	}
+
+	// v1 returns the trace alone in tempo's encoding unless otlp/json is requested
+	buff, err := marshalTraceByIDResponse(resp, api.HeaderAcceptJSON, false)
+	require.NoError(t, err)
+	require.Contains(t, string(buff), `"batches"`)
+
+	buff, err = marshalTraceByIDResponse(resp, api.HeaderAcceptOTLPJSON, false)
+	require.NoError(t, err)
+	require.Contains(t, string(buff), `"resourceSpans"`)
+	require.NotContains(t, string(buff), `"metrics"`)
+
+	// v2 always returns otlp/json alongside the metrics
+	for _, format := range []string{api.HeaderAcceptJSON, api.HeaderAcceptOTLPJSON} {
+		buff, err = marshalTraceByIDResponse(resp, format, true)
+		require.NoError(t, err)
+		require.Contains(t, string(buff), `"resourceSpans"`)
+		require.Contains(t, string(buff), `"metrics"`)
+	}
+}
@@ -0,0 +1,327 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/jsonpb" //nolint:all deprecated
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/traceqlmetrics"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type queryRangeSharder struct {
+	next   http.RoundTripper
+	search searchSharder
+
+	cfg    SearchSharderConfig
+	logger log.Logger
+}
+
+// newQueryRangeSharder creates a sharding middleware for metrics query range requests. It shares the
+// search sharding config.
+func newQueryRangeSharder(reader tempodb.Reader, o overrides.Interface, cfg SearchSharderConfig, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return queryRangeSharder{
+			next: next,
+			search: searchSharder{
+				reader:    reader,
+				overrides: o,
+				cfg:       cfg,
+				logger:    logger,
+			},
+			cfg:    cfg,
+			logger: logger,
+		}
+	})
+}
+
+// RoundTrip implements http.RoundTripper
+// executes the query on the ingesters and all backend blocks in the range, up to concurrentRequests
+// simultaneously, and combines the results into a Prometheus range query response.
+func (s queryRangeSharder) RoundTrip(r *http.Request) (*http.Response, error) {
+	req, err := api.ParseQueryRangeRequest(r)
+	if err != nil {
+		return badRequest(err.Error()), nil
+	}
+
+	// align start to the step so the intervals don't shift between refreshes
+	req.Start = req.Start / req.Step * req.Step
+
+	combiner, err := traceqlmetrics.NewQueryRangeCombiner(req)
+	if err != nil {
+		return badRequest(err.Error()), nil
+	}
+
+	ctx := r.Context()
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return badRequest(err.Error()), nil
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, "frontend.ShardQueryRange")
+	defer span.Finish()
+
+	reqStart := time.Now()
+	// sub context to cancel in-progress sub requests
+	subCtx, subCancel := context.WithCancel(ctx)
+	defer subCancel()
+
+	// calculate and enforce max search duration
+	start, end := req.Start/uint64(time.Second), req.End/uint64(time.Second)
+	maxDuration := s.search.maxDuration(tenantID)
+	if maxDuration != 0 && time.Duration(req.End-req.Start) > maxDuration {
+		return badRequest(fmt.Sprintf("range specified by start and end exceeds %s. received start=%d end=%d", maxDuration, start, end)), nil
+	}
+
+	// spans that start before the cut are read from the backend and the rest from the ingesters
+	cut := s.cut(req, time.Now())
+
+	var blocks []*backend.BlockMeta
+	if cut > req.Start {
+		blocks = s.search.blockMetas(int64(start), int64(cut/uint64(time.Second)), tenantID)
+	}
+	span.SetTag("block-count", len(blocks))
+
+	backendReq := *req
+	backendReq.End = cut
+	reqs, err := s.backendRequests(subCtx, tenantID, r, &backendReq, blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	if cut < req.End {
+		ingesterReq := *req
+		ingesterReq.Start = cut
+		reqs = append(reqs, s.ingesterRequest(subCtx, tenantID, r, &ingesterReq))
+	}
+	span.SetTag("request-count", len(reqs))
+
+	var (
+		mtx        sync.Mutex
+		overallErr error
+		statusCode = http.StatusOK
+		statusMsg  string
+	)
+
+	wg := boundedwaitgroup.New(uint(s.cfg.ConcurrentRequests))
+	for _, subR := range reqs {
+		mtx.Lock()
+		quit := overallErr != nil || statusCode != http.StatusOK
+		mtx.Unlock()
+		if quit {
+			break
+		}
+
+		wg.Add(1)
+		go func(innerR *http.Request) {
+			defer wg.Done()
+
+			resp, err := s.next.RoundTrip(innerR)
+			if err != nil {
+				// context cancelled error happens when we exit early.
+				if errors.Is(err, context.Canceled) {
+					_ = level.Debug(s.logger).Log("msg", "exiting early from sharded query", "url", innerR.RequestURI, "err", err)
+					return
+				}
+
+				_ = level.Error(s.logger).Log("msg", "error executing sharded query", "url", innerR.RequestURI, "err", err)
+				mtx.Lock()
+				overallErr = err
+				mtx.Unlock()
+				subCancel()
+				return
+			}
+
+			// if the status code is anything but happy, save the error and pass it down the line
+			if resp.StatusCode != http.StatusOK {
+				bytesMsg, err := io.ReadAll(resp.Body)
+				if err != nil {
+					_ = level.Error(s.logger).Log("msg", "error reading response body status != ok", "url", innerR.RequestURI, "err", err)
+				}
+				mtx.Lock()
+				statusCode = resp.StatusCode
+				statusMsg = fmt.Sprintf("upstream: (%d) %s", resp.StatusCode, string(bytesMsg))
+				mtx.Unlock()
+				subCancel()
+				return
+			}
+
+			// successful query, read the body
+			results := &prompb.QueryResult{}
+			err = (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(resp.Body, results)
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error reading response body status == ok", "url", innerR.RequestURI, "err", err)
+				mtx.Lock()
+				overallErr = err
+				mtx.Unlock()
+				subCancel()
+				return
+			}
+
+			mtx.Lock()
+			combiner.Combine(results.Timeseries)
+			mtx.Unlock()
+		}(subR)
+	}
+	wg.Wait()
+
+	level.Info(s.logger).Log(
+		"msg", "sharded query range request stats",
+		"query", req.Query,
+		"duration_seconds", time.Since(reqStart),
+		"total_blocks", len(blocks),
+		"total_requests", len(reqs))
+
+	if overallErr != nil {
+		return nil, overallErr
+	}
+
+	if statusCode != http.StatusOK {
+		// translate all non-200s into 500s. if, for instance, we get a 400 back from an internal component
+		// it means that we created a bad request. 400 should not be propagated back to the user b/c
+		// the bad request was due to a bug on our side, so return 500 instead.
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(statusMsg)),
+		}, nil
+	}
+
+	body, err := json.Marshal(newPromMatrixResponse(combiner.Results()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptJSON},
+		},
+		Body:          io.NopCloser(strings.NewReader(string(body))),
+		ContentLength: int64(len(body)),
+	}, nil
+}
+
+// cut returns the time that splits a range query between the backend and the ingesters. Unlike search
+// results, metrics can't be deduplicated so the two never overlap. It's based on query_backend_after and
+// aligned to the step so both return the same intervals. If that is older than query_ingesters_until it's
+// rounded up instead, because the ingesters may no longer hold the spans.
+func (s *queryRangeSharder) cut(req *traceqlmetrics.QueryRangeRequest, now time.Time) uint64 {
+	backendAfter := uint64(now.Add(-s.cfg.QueryBackendAfter).UnixNano())
+	ingestersUntil := uint64(now.Add(-s.cfg.QueryIngestersUntil).UnixNano())
+
+	cut := backendAfter / req.Step * req.Step
+	if cut < ingestersUntil {
+		cut += req.Step
+	}
+
+	if cut < req.Start {
+		return req.Start
+	}
+	if cut > req.End {
+		return req.End
+	}
+	return cut
+}
+
+// ingesterRequest returns a request that queries the ingesters
+func (s *queryRangeSharder) ingesterRequest(ctx context.Context, tenantID string, parent *http.Request, req *traceqlmetrics.QueryRangeRequest) *http.Request {
+	subR := parent.Clone(ctx)
+	subR.Header.Set(user.OrgIDHeaderName, tenantID)
+
+	subR = api.BuildQueryRangeRequest(subR, req)
+	subR.RequestURI = buildUpstreamRequestURI(parent.URL.Path, subR.URL.Query())
+	return subR
+}
+
+// backendRequests returns a slice of requests that cover all pages of the passed blocks
+func (s *queryRangeSharder) backendRequests(ctx context.Context, tenantID string, parent *http.Request, req *traceqlmetrics.QueryRangeRequest, metas []*backend.BlockMeta) ([]*http.Request, error) {
+	reqs := []*http.Request{}
+	for _, m := range metas {
+		shards, err := blockShards(m, s.cfg.TargetBytesPerRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, shard := range shards {
+			subR := parent.Clone(ctx)
+			subR.Header.Set(user.OrgIDHeaderName, tenantID)
+
+			subR = api.BuildQueryRangeBlockRequest(subR, req, shard)
+			subR.RequestURI = buildUpstreamRequestURI(parent.URL.Path, subR.URL.Query())
+			reqs = append(reqs, subR)
+		}
+	}
+
+	return reqs, nil
+}
+
+// promMatrixResponse is the response body of the Prometheus range query api
+// https://prometheus.io/docs/prometheus/latest/querying/api/#range-queries
+type promMatrixResponse struct {
+	Status string         `json:"status"`
+	Data   promMatrixData `json:"data"`
+}
+
+type promMatrixData struct {
+	ResultType string             `json:"resultType"`
+	Result     []promMatrixSeries `json:"result"`
+}
+
+type promMatrixSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values [][]interface{}   `json:"values"`
+}
+
+func newPromMatrixResponse(series []*prompb.TimeSeries) *promMatrixResponse {
+	resp := &promMatrixResponse{
+		Status: "success",
+		Data: promMatrixData{
+			ResultType: "matrix",
+			Result:     make([]promMatrixSeries, 0, len(series)),
+		},
+	}
+
+	for _, s := range series {
+		metric := make(map[string]string, len(s.Labels))
+		for _, l := range s.Labels {
+			metric[l.Name] = l.Value
+		}
+
+		values := make([][]interface{}, 0, len(s.Samples))
+		for _, sample := range s.Samples {
+			values = append(values, []interface{}{
+				float64(sample.Timestamp) / 1000,
+				strconv.FormatFloat(sample.Value, 'f', -1, 64),
+			})
+		}
+
+		resp.Data.Result = append(resp.Data.Result, promMatrixSeries{Metric: metric, Values: values})
+	}
+
+	return resp
+}
+
+func badRequest(msg string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body:       io.NopCloser(strings.NewReader(msg)),
+	}
+}
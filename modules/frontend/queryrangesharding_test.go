@@ -0,0 +1,290 @@
+package frontend
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/traceqlmetrics"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestQueryRangeSharderRoundTrip(t *testing.T) {
+	tests := []struct {
+		name             string
+		status1          int
+		status2          int
+		expectedStatus   int
+		expectedResponse string
+	}{
+		{
+			name:             "ok",
+			status1:          200,
+			status2:          200,
+			expectedStatus:   200,
+			expectedResponse: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"span.foo":"bar"},"values":[[1000,"0.15"],[1010,"0.2"]]}]}}`,
+		},
+		{
+			name:           "upstream 400",
+			status1:        200,
+			status2:        400,
+			expectedStatus: 500,
+		},
+		{
+			name:           "upstream 500",
+			status1:        500,
+			status2:        200,
+			expectedStatus: 500,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mtx sync.Mutex
+			pages := []string{}
+
+			next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				_, blockReq, err := api.ParseQueryRangeBlockRequest(r)
+				require.NoError(t, err)
+
+				mtx.Lock()
+				pages = append(pages, fmt.Sprint(blockReq.StartPage))
+				mtx.Unlock()
+
+				statusCode := tc.status1
+				body := `{"timeseries":[{"labels":[{"name":"span.foo","value":"bar"}],"samples":[{"timestamp":"1000000","value":1}]}]}`
+				if blockReq.StartPage == 1 {
+					statusCode = tc.status2
+					body = `{"timeseries":[{"labels":[{"name":"span.foo","value":"bar"}],"samples":[{"timestamp":"1000000","value":0.5},{"timestamp":"1010000","value":2}]}]}`
+				}
+
+				return &http.Response{
+					Body:       io.NopCloser(strings.NewReader(body)),
+					StatusCode: statusCode,
+				}, nil
+			})
+
+			o, err := overrides.NewOverrides(overrides.Limits{})
+			require.NoError(t, err)
+
+			sharder := newQueryRangeSharder(&mockReader{
+				metas: []*backend.BlockMeta{ // one block with 2 records that are each the target bytes per request will force 2 sub queries
+					{
+						StartTime:    time.Unix(1000, 0),
+						EndTime:      time.Unix(1200, 0),
+						Size:         defaultTargetBytesPerRequest * 2,
+						TotalRecords: 2,
+						BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+						Version:      "vParquet2",
+					},
+					{ // outside of the range
+						StartTime:    time.Unix(2000, 0),
+						EndTime:      time.Unix(2100, 0),
+						Size:         defaultTargetBytesPerRequest,
+						TotalRecords: 1,
+						BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+					},
+				},
+			}, o, SearchSharderConfig{
+				ConcurrentRequests:    1, // 1 concurrent request to force order
+				TargetBytesPerRequest: defaultTargetBytesPerRequest,
+			}, log.NewNopLogger())
+			testRT := NewRoundTripper(next, sharder)
+
+			// start is aligned to the step
+			req := httptest.NewRequest("GET", "/?q="+url.QueryEscape("{ } | rate() by (span.foo)")+"&start=1005&end=1020&step=10", nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+			resp, err := testRT.RoundTrip(req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, []string{"0", "1"}, pages)
+				assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.JSONEq(t, tc.expectedResponse, string(body))
+			}
+		})
+	}
+}
+
+func TestQueryRangeSharderRoundTripBadRequest(t *testing.T) {
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	sharder := newQueryRangeSharder(&mockReader{}, o, SearchSharderConfig{
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+		MaxDuration:           5 * time.Minute,
+	}, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	q := url.QueryEscape("{ } | rate()")
+
+	// no org id
+	req := httptest.NewRequest("GET", "/?q="+q+"&start=1000&end=1100&step=10", nil)
+	resp, err := testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "no org id")
+
+	// start/end outside of max duration
+	req = httptest.NewRequest("GET", "/?q="+q+"&start=1000&end=1500&step=10", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "range specified by start and end exceeds 5m0s. received start=1000 end=1500")
+
+	// not a metrics query
+	req = httptest.NewRequest("GET", "/?q="+url.QueryEscape("{ }")+"&start=1000&end=1100&step=10", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "compiling query: not a metrics query: { }")
+}
+
+func TestQueryRangeSharderIngesterRequest(t *testing.T) {
+	var mtx sync.Mutex
+	var ingesterReqs, blockReqs []*traceqlmetrics.QueryRangeRequest
+
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		if api.IsSearchBlock(r) {
+			req, _, err := api.ParseQueryRangeBlockRequest(r)
+			require.NoError(t, err)
+			blockReqs = append(blockReqs, req)
+		} else {
+			req, err := api.ParseQueryRangeRequest(r)
+			require.NoError(t, err)
+			ingesterReqs = append(ingesterReqs, req)
+		}
+
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader(`{"timeseries":[]}`)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	sharder := newQueryRangeSharder(&mockReader{
+		metas: []*backend.BlockMeta{
+			{
+				StartTime:    now.Add(-time.Hour),
+				EndTime:      now.Add(-30 * time.Minute),
+				Size:         defaultTargetBytesPerRequest,
+				TotalRecords: 1,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+				Version:      "vParquet2",
+			},
+		},
+	}, o, SearchSharderConfig{
+		ConcurrentRequests:    1,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+		QueryBackendAfter:     15 * time.Minute,
+		QueryIngestersUntil:   30 * time.Minute,
+	}, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	start, end := now.Add(-time.Hour).Unix(), now.Unix()
+	req := httptest.NewRequest("GET", fmt.Sprintf("/?q=%s&start=%d&end=%d&step=60", url.QueryEscape("{ } | rate()"), start, end), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+	resp, err := testRT.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Len(t, ingesterReqs, 1)
+	require.Len(t, blockReqs, 1)
+
+	// the backend and the ingesters split the range at a step aligned time without overlapping
+	cut := ingesterReqs[0].Start
+	assert.Equal(t, cut, blockReqs[0].End)
+	assert.Equal(t, blockReqs[0].Start, blockReqs[0].Start/uint64(time.Minute)*uint64(time.Minute))
+	assert.Equal(t, uint64(0), cut%uint64(time.Minute))
+	assert.Equal(t, uint64(end)*uint64(time.Second), ingesterReqs[0].End)
+	assert.LessOrEqual(t, cut, uint64(now.Add(-15*time.Minute).UnixNano()))
+	assert.Greater(t, cut, uint64(now.Add(-16*time.Minute).UnixNano()))
+}
+
+func TestQueryRangeSharderCut(t *testing.T) {
+	now := time.Unix(10000, 0)
+	step := uint64(100 * time.Second)
+
+	tests := []struct {
+		name          string
+		backendAfter  time.Duration
+		ingesterUntil time.Duration
+		start, end    int64
+		expected      int64
+	}{
+		{
+			name:          "rounded down",
+			backendAfter:  150 * time.Second,
+			ingesterUntil: 300 * time.Second,
+			start:         9000,
+			end:           10000,
+			expected:      9800, // now - 150s = 9850
+		},
+		{
+			name:          "rounded up when older than query_ingesters_until",
+			backendAfter:  150 * time.Second,
+			ingesterUntil: 160 * time.Second,
+			start:         9000,
+			end:           10000,
+			expected:      9900,
+		},
+		{
+			name:          "before start",
+			backendAfter:  150 * time.Second,
+			ingesterUntil: 300 * time.Second,
+			start:         9900,
+			end:           10000,
+			expected:      9900,
+		},
+		{
+			name:          "after end",
+			backendAfter:  150 * time.Second,
+			ingesterUntil: 300 * time.Second,
+			start:         9000,
+			end:           9500,
+			expected:      9500,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &queryRangeSharder{cfg: SearchSharderConfig{
+				QueryBackendAfter:   tc.backendAfter,
+				QueryIngestersUntil: tc.ingesterUntil,
+			}}
+			req := &traceqlmetrics.QueryRangeRequest{
+				Start: uint64(tc.start) * uint64(time.Second),
+				End:   uint64(tc.end) * uint64(time.Second),
+				Step:  step,
+			}
+			assert.Equal(t, uint64(tc.expected)*uint64(time.Second), s.cut(req, now))
+		})
+	}
+}
@@ -53,6 +53,15 @@ func (r retryWare) RoundTrip(req *http.Request) (*http.Response, error) {
 			return nil, ctx.Err()
 		}
 
+		// the body of the previous try has been read, requests with a body have to rewind it
+		if tries > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
 		resp, err := r.next.RoundTrip(req)
 
 		// do not retry if no error and response is not HTTP 5xx
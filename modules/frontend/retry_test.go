@@ -3,8 +3,10 @@ package frontend
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -158,3 +160,21 @@ func TestRetry_CancelledRequest(t *testing.T) {
 	require.Equal(t, int32(1), try.Load())
 	require.Equal(t, ctx.Err(), err)
 }
+
+func TestRetry_RewindsBody(t *testing.T) {
+	var bodies []string
+
+	req, err := http.NewRequest("POST", "http://example.com", strings.NewReader("body"))
+	require.NoError(t, err)
+
+	_, err = newRetryWare(3, prometheus.NewRegistry()).
+		Wrap(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			b, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(b))
+			return &http.Response{StatusCode: 500}, nil
+		})).RoundTrip(req)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"body", "body", "body"}, bodies)
+}
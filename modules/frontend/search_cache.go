@@ -0,0 +1,194 @@
+package frontend
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/cache/memcached"
+	"github.com/grafana/tempo/tempodb/backend/cache/redis"
+)
+
+const searchCacheName = "frontend-search"
+
+var (
+	searchCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_search_cache_hits_total",
+		Help:      "Total search jobs per tenant whose results were found in the search cache.",
+	}, []string{"tenant"})
+
+	searchCacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_search_cache_misses_total",
+		Help:      "Total search jobs per tenant whose results were not found in the search cache.",
+	}, []string{"tenant"})
+)
+
+// searchJobContext returns the context of the backend job that searches shard of block m
+type searchJobContext func(ctx context.Context, m *backend.BlockMeta, shard *tempopb.SearchBlockRequest) context.Context
+
+// noJobCache leaves the context of a job as is, so the job isn't cached
+func noJobCache(ctx context.Context, _ *backend.BlockMeta, _ *tempopb.SearchBlockRequest) context.Context {
+	return ctx
+}
+
+type searchCacheJobKey struct{}
+
+// searchCacheJob identifies the cached results of a backend job
+type searchCacheJob struct {
+	tenantID string
+	key      string
+}
+
+// searchJobCache caches the results of backend search jobs. Blocks are immutable, so a job searching the
+// same pages of a block with the same query always finds the same traces.
+type searchJobCache struct {
+	cache       cache.Cache
+	minBlockAge time.Duration
+	logger      log.Logger
+}
+
+// newSearchJobCache returns the search cache of the config or nil if no cache backend is configured.
+func newSearchJobCache(cfg SearchCacheConfig, logger log.Logger) (*searchJobCache, error) {
+	var c cache.Cache
+
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case "redis":
+		if cfg.Redis == nil {
+			return nil, fmt.Errorf("frontend search cache backend redis requires a redis config")
+		}
+		c = redis.NewClient(searchCacheName, cfg.Redis, cfg.BackgroundCache, logger)
+	case "memcached":
+		if cfg.Memcached == nil {
+			return nil, fmt.Errorf("frontend search cache backend memcached requires a memcached config")
+		}
+		c = memcached.NewClient(searchCacheName, cfg.Memcached, cfg.BackgroundCache, logger)
+	default:
+		return nil, fmt.Errorf("unknown frontend search cache backend %s", cfg.Backend)
+	}
+
+	return &searchJobCache{
+		cache:       c,
+		minBlockAge: cfg.MinBlockAge,
+		logger:      logger,
+	}, nil
+}
+
+// jobContexts returns a function that adds the cache key of a backend job of the search to the job's
+// context. Jobs of blocks that ended less than min_block_age before now are not cached: recent blocks are
+// soon replaced by compaction, so their results would rarely be read again.
+func (c *searchJobCache) jobContexts(tenantID string, searchReq *tempopb.SearchRequest, now time.Time) searchJobContext {
+	normalized := normalizeSearchRequest(searchReq)
+
+	return func(ctx context.Context, m *backend.BlockMeta, shard *tempopb.SearchBlockRequest) context.Context {
+		if now.Sub(m.EndTime) < c.minBlockAge {
+			return ctx
+		}
+
+		return context.WithValue(ctx, searchCacheJobKey{}, searchCacheJob{
+			tenantID: tenantID,
+			key:      searchJobCacheKey(tenantID, normalized, m, shard),
+		})
+	}
+}
+
+// fetch returns the cached results of the job or nil if they aren't cached.
+func (c *searchJobCache) fetch(ctx context.Context, job searchCacheJob) *tempopb.SearchResponse {
+	found, bufs, _ := c.cache.Fetch(ctx, []string{job.key})
+	if len(found) == 0 {
+		searchCacheMisses.WithLabelValues(job.tenantID).Inc()
+		return nil
+	}
+
+	res := &tempopb.SearchResponse{}
+	if err := res.Unmarshal(bufs[0]); err != nil {
+		_ = level.Error(c.logger).Log("msg", "error unmarshalling cached search job results", "key", job.key, "err", err)
+		searchCacheMisses.WithLabelValues(job.tenantID).Inc()
+		return nil
+	}
+
+	searchCacheHits.WithLabelValues(job.tenantID).Inc()
+	return res
+}
+
+// store caches the results of the job.
+func (c *searchJobCache) store(ctx context.Context, job searchCacheJob, res *tempopb.SearchResponse) {
+	buf, err := res.Marshal()
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "error marshalling search job results", "key", job.key, "err", err)
+		return
+	}
+
+	c.cache.Store(ctx, []string{job.key}, [][]byte{buf})
+}
+
+// normalizeSearchRequest returns a copy of the search with the TraceQL query in its canonical form, so
+// equivalent queries share cached results.
+func normalizeSearchRequest(searchReq *tempopb.SearchRequest) *tempopb.SearchRequest {
+	normalized := *searchReq
+	if normalized.Query != "" {
+		if expr, err := traceql.Parse(normalized.Query); err == nil {
+			normalized.Query = expr.String()
+		}
+	}
+	return &normalized
+}
+
+// searchJobCacheKey returns the cache key of a job searching a range of pages of a block. The time range
+// of the search is left out if the block is entirely within it, because it doesn't change the results then.
+// This lets searches of relative time ranges, like the ones of reloaded dashboards, share cached results.
+func searchJobCacheKey(tenantID string, searchReq *tempopb.SearchRequest, m *backend.BlockMeta, shard *tempopb.SearchBlockRequest) string {
+	start, end := searchReq.Start, searchReq.End
+	if m.StartTime.Unix() >= int64(start) && m.EndTime.Unix() <= int64(end) {
+		start, end = 0, 0
+	}
+
+	tags := make([]string, 0, len(searchReq.Tags))
+	for k := range searchReq.Tags {
+		tags = append(tags, k)
+	}
+	sort.Strings(tags)
+
+	h := sha256.New()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	write(tenantID)
+	write(shard.BlockID)
+	write(strconv.FormatUint(uint64(shard.StartPage), 10))
+	write(strconv.FormatUint(uint64(shard.PagesToSearch), 10))
+	write(searchReq.Query)
+	for _, k := range tags {
+		write(k)
+		write(searchReq.Tags[k])
+	}
+	write(strconv.FormatUint(uint64(searchReq.MinDurationMs), 10))
+	write(strconv.FormatUint(uint64(searchReq.MaxDurationMs), 10))
+	write(strconv.FormatUint(uint64(searchReq.Limit), 10))
+	write(strconv.FormatUint(uint64(searchReq.SpansPerSpanSet), 10))
+	write(strconv.FormatUint(uint64(start), 10))
+	write(strconv.FormatUint(uint64(end), 10))
+	write(strconv.FormatBool(searchReq.Paginated))
+	write(strconv.FormatUint(searchReq.AfterStartTimeUnixNano, 10))
+	write(searchReq.AfterTraceID)
+
+	// memcached keys are limited to 250 bytes, so the key is hashed
+	return "search:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,183 @@
+package frontend
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/golang/protobuf/jsonpb" //nolint:all deprecated
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSearchSharderCache(t *testing.T) {
+	now := time.Now()
+	oldBlock := &backend.BlockMeta{
+		BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+		StartTime:    now.Add(-3 * time.Hour),
+		EndTime:      now.Add(-2 * time.Hour),
+		Size:         defaultTargetBytesPerRequest,
+		TotalRecords: 1,
+	}
+	recentBlock := &backend.BlockMeta{
+		BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		StartTime:    now.Add(-40 * time.Minute),
+		EndTime:      now.Add(-20 * time.Minute),
+		Size:         defaultTargetBytesPerRequest,
+		TotalRecords: 1,
+	}
+
+	tests := []struct {
+		name                 string
+		tenant               string
+		limits               overrides.Limits
+		expectedSecondSearch []string
+		expectedHits         float64
+	}{
+		{
+			name:                 "old blocks are cached",
+			tenant:               "cached",
+			limits:               overrides.Limits{SearchCacheEnabled: true},
+			expectedSecondSearch: []string{"ingester", recentBlock.BlockID.String()},
+			expectedHits:         1,
+		},
+		{
+			name:                 "cache not enabled for tenant",
+			tenant:               "disabled",
+			expectedSecondSearch: []string{"ingester", oldBlock.BlockID.String(), recentBlock.BlockID.String()},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mtx := sync.Mutex{}
+			var actualReqs []string
+			next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				blockID := r.URL.Query().Get("blockID")
+				if blockID == "" {
+					blockID = "ingester"
+				}
+				mtx.Lock()
+				actualReqs = append(actualReqs, blockID)
+				mtx.Unlock()
+
+				resString, err := (&jsonpb.Marshaler{}).MarshalToString(&tempopb.SearchResponse{
+					Traces:  []*tempopb.TraceSearchMetadata{{TraceID: blockID}},
+					Metrics: &tempopb.SearchMetrics{InspectedTraces: 1},
+				})
+				require.NoError(t, err)
+
+				return &http.Response{
+					Body:       io.NopCloser(strings.NewReader(resString)),
+					StatusCode: http.StatusOK,
+				}, nil
+			})
+
+			o, err := overrides.NewOverrides(tc.limits)
+			require.NoError(t, err)
+
+			searchCache := &searchJobCache{
+				cache:       cache.NewMockCache(),
+				minBlockAge: time.Hour,
+				logger:      log.NewNopLogger(),
+			}
+			sharder := newSearchSharder(&mockReader{
+				metas: []*backend.BlockMeta{oldBlock, recentBlock},
+			}, o, searchCache, SearchSharderConfig{
+				ConcurrentRequests:    1, // 1 concurrent request to force order
+				TargetBytesPerRequest: defaultTargetBytesPerRequest,
+				QueryBackendAfter:     15 * time.Minute,
+				QueryIngestersUntil:   30 * time.Minute,
+				DefaultLimit:          20,
+			}, testSLOcfg, newSearchProgress, log.NewNopLogger())
+			testRT := NewRoundTripper(next, sharder)
+
+			search := func(query string, start time.Time) *tempopb.SearchResponse {
+				params := url.Values{}
+				params.Set("q", query)
+				params.Set("start", strconv.Itoa(int(start.Unix())))
+				params.Set("end", strconv.Itoa(int(now.Unix())))
+				req := httptest.NewRequest("GET", "/?"+params.Encode(), nil)
+				req = req.WithContext(user.InjectOrgID(req.Context(), tc.tenant))
+
+				resp, err := testRT.RoundTrip(req)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				res := &tempopb.SearchResponse{}
+				require.NoError(t, (&jsonpb.Unmarshaler{}).Unmarshal(resp.Body, res))
+				return res
+			}
+
+			first := search(`{ .foo = "bar" }`, now.Add(-4*time.Hour))
+			require.Equal(t, []string{"ingester", oldBlock.BlockID.String(), recentBlock.BlockID.String()}, actualReqs)
+
+			// an equivalent query over a range that covers the same blocks finds the same traces
+			actualReqs = nil
+			second := search(`{.foo="bar"}`, now.Add(-5*time.Hour))
+			require.Equal(t, tc.expectedSecondSearch, actualReqs)
+			require.Equal(t, first.Traces, second.Traces)
+
+			require.Equal(t, tc.expectedHits, counterValue(t, searchCacheHits, tc.tenant))
+		})
+	}
+}
+
+func TestSearchJobCacheKey(t *testing.T) {
+	m := &backend.BlockMeta{
+		StartTime: time.Unix(100, 0),
+		EndTime:   time.Unix(200, 0),
+	}
+	shard := &tempopb.SearchBlockRequest{BlockID: "block", StartPage: 0, PagesToSearch: 10}
+	searchReq := &tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 20, Start: 50, End: 250}
+	key := searchJobCacheKey("tenant", normalizeSearchRequest(searchReq), m, shard)
+
+	// the time range doesn't matter if it contains the block
+	require.Equal(t, key, searchJobCacheKey("tenant", normalizeSearchRequest(&tempopb.SearchRequest{Query: `{.foo="bar"}`, Limit: 20, Start: 0, End: 300}), m, shard))
+	require.NotEqual(t, key, searchJobCacheKey("tenant", normalizeSearchRequest(&tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 20, Start: 150, End: 300}), m, shard))
+
+	require.NotEqual(t, key, searchJobCacheKey("other", normalizeSearchRequest(searchReq), m, shard))
+	require.NotEqual(t, key, searchJobCacheKey("tenant", normalizeSearchRequest(&tempopb.SearchRequest{Query: `{ .foo = "baz" }`, Limit: 20, Start: 50, End: 250}), m, shard))
+	require.NotEqual(t, key, searchJobCacheKey("tenant", normalizeSearchRequest(&tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 10, Start: 50, End: 250}), m, shard))
+	require.NotEqual(t, key, searchJobCacheKey("tenant", normalizeSearchRequest(searchReq), m, &tempopb.SearchBlockRequest{BlockID: "block", StartPage: 10, PagesToSearch: 10}))
+
+	// the jobs of every page of a paginated search have their own results
+	page := &tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 20, Start: 50, End: 250, Paginated: true}
+	require.NotEqual(t, key, searchJobCacheKey("tenant", normalizeSearchRequest(page), m, shard))
+	nextPage := *page
+	nextPage.AfterStartTimeUnixNano, nextPage.AfterTraceID = 150, "0a"
+	require.NotEqual(t, searchJobCacheKey("tenant", normalizeSearchRequest(page), m, shard), searchJobCacheKey("tenant", normalizeSearchRequest(&nextPage), m, shard))
+}
+
+func TestNewSearchJobCache(t *testing.T) {
+	c, err := newSearchJobCache(SearchCacheConfig{}, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Nil(t, c)
+
+	_, err = newSearchJobCache(SearchCacheConfig{Backend: "memcached"}, log.NewNopLogger())
+	require.Error(t, err)
+
+	_, err = newSearchJobCache(SearchCacheConfig{Backend: "blerg"}, log.NewNopLogger())
+	require.Error(t, err)
+}
+
+func counterValue(t *testing.T, c *prometheus.CounterVec, tenant string) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, c.WithLabelValues(tenant).Write(m))
+	return m.GetCounter().GetValue()
+}
@@ -0,0 +1,187 @@
+package frontend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+)
+
+// searchExplainResponse describes how a TraceQL search would be executed
+type searchExplainResponse struct {
+	Query                string             `json:"query"`
+	AllConditions        bool               `json:"allConditions"`
+	Conditions           []explainCondition `json:"conditions"`
+	SecondPassConditions []explainCondition `json:"secondPassConditions"`
+	QueryIngesters       bool               `json:"queryIngesters"`
+	Blocks               int                `json:"blocks"`
+	BlockBytes           uint64             `json:"blockBytes"`
+	Jobs                 int                `json:"jobs"`
+}
+
+type explainCondition struct {
+	Condition string                     `json:"condition"`
+	Columns   map[string][]explainColumn `json:"columns"` // by block version
+}
+
+// explainColumn is a column that is read for a condition. PushedDown is true if the condition is
+// evaluated while the column is read, otherwise all values are read and the engine filters them.
+type explainColumn struct {
+	Column     string `json:"column"`
+	PushedDown bool   `json:"pushedDown"`
+}
+
+// searchExplainer returns the plan of a TraceQL search without executing it. It uses the same logic as
+// the searchSharder to find the blocks and jobs of the search.
+type searchExplainer struct {
+	sharder searchSharder
+}
+
+func newSearchExplainer(reader tempodb.Reader, o overrides.Interface, cfg SearchSharderConfig, logger log.Logger) http.RoundTripper {
+	return searchExplainer{
+		sharder: searchSharder{
+			reader:    reader,
+			overrides: o,
+			cfg:       cfg,
+			logger:    logger,
+		},
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (s searchExplainer) RoundTrip(r *http.Request) (*http.Response, error) {
+	searchReq, err := api.ParseSearchRequest(r)
+	if err != nil {
+		return invalidSearchRequest(err), nil
+	}
+	if searchReq.Query == "" {
+		return badRequest("explain requires a TraceQL query"), nil
+	}
+
+	rootExpr, fetchReq, err := traceql.NewEngine().CompileSearch(searchReq)
+	if err != nil {
+		return invalidSearchRequest(err), nil
+	}
+
+	ctx := r.Context()
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return badRequest(err.Error()), nil
+	}
+
+	resp := &searchExplainResponse{
+		Query:          rootExpr.String(),
+		AllConditions:  fetchReq.AllConditions,
+		QueryIngesters: true,
+	}
+
+	var blocks []*backend.BlockMeta
+	if api.IsBackendSearch(r) {
+		maxDuration := s.sharder.maxDuration(tenantID)
+		if maxDuration != 0 && time.Duration(searchReq.End-searchReq.Start)*time.Second > maxDuration {
+			return badRequest(fmt.Sprintf("range specified by start and end exceeds %s. received start=%d end=%d", maxDuration, searchReq.Start, searchReq.End)), nil
+		}
+
+		now := time.Now()
+		ingesterReq, err := s.sharder.ingesterRequest(ctx, tenantID, r, *searchReq, now)
+		if err != nil {
+			return nil, err
+		}
+		resp.QueryIngesters = ingesterReq != nil
+
+		start, end := s.sharder.backendRange(searchReq, now)
+		if start != end {
+			blocks = s.sharder.blockMetas(int64(start), int64(end), tenantID)
+		}
+	}
+
+	for _, m := range blocks {
+		shards, err := blockShards(m, s.sharder.cfg.TargetBytesPerRequest)
+		if err != nil {
+			return nil, err
+		}
+		resp.Blocks++
+		resp.BlockBytes += m.Size
+		resp.Jobs += len(shards)
+	}
+
+	versions := explainVersions(blocks)
+	resp.Conditions = explainConditions(fetchReq.Conditions, fetchReq.AllConditions, versions)
+	resp.SecondPassConditions = explainConditions(fetchReq.SecondPassConditions, false, versions)
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptJSON},
+		},
+		Body:          io.NopCloser(strings.NewReader(string(body))),
+		ContentLength: int64(len(body)),
+	}, nil
+}
+
+// explainVersions returns the distinct versions of the blocks. If there are no blocks all versions
+// that support TraceQL are returned.
+func explainVersions(blocks []*backend.BlockMeta) []string {
+	versions := []string{}
+	seen := map[string]struct{}{}
+	for _, m := range blocks {
+		if _, ok := seen[m.Version]; ok {
+			continue
+		}
+		seen[m.Version] = struct{}{}
+		versions = append(versions, m.Version)
+	}
+
+	if len(versions) == 0 {
+		for _, e := range encoding.AllEncodings() {
+			if _, err := encoding.ConditionColumns(e.Version(), traceql.Condition{}, false); err == nil {
+				versions = append(versions, e.Version())
+			}
+		}
+	}
+
+	return versions
+}
+
+// explainConditions returns the columns that blocks of each version read for the conditions. They are
+// derived from the iterators the blocks build to fetch them.
+func explainConditions(conds []traceql.Condition, allConditions bool, versions []string) []explainCondition {
+	explained := make([]explainCondition, 0, len(conds))
+	for _, cond := range conds {
+		e := explainCondition{
+			Condition: cond.String(),
+			Columns:   map[string][]explainColumn{},
+		}
+		for _, v := range versions {
+			columns, err := encoding.ConditionColumns(v, cond, allConditions)
+			if err != nil {
+				continue
+			}
+
+			explainColumns := make([]explainColumn, 0, len(columns))
+			for _, c := range columns {
+				explainColumns = append(explainColumns, explainColumn{Column: c.Column, PushedDown: c.PushedDown})
+			}
+			e.Columns[v] = explainColumns
+		}
+		explained = append(explained, e)
+	}
+	return explained
+}
@@ -0,0 +1,133 @@
+package frontend
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSearchExplain(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	explainer := newSearchExplainer(&mockReader{
+		metas: []*backend.BlockMeta{
+			{
+				StartTime:    time.Unix(1100, 0),
+				EndTime:      time.Unix(1200, 0),
+				Size:         defaultTargetBytesPerRequest * 2,
+				TotalRecords: 2,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+				Version:      "vParquet2",
+			},
+			{
+				StartTime:    time.Unix(1300, 0),
+				EndTime:      time.Unix(1400, 0),
+				Size:         defaultTargetBytesPerRequest,
+				TotalRecords: 1,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+				Version:      "vParquet2",
+			},
+			{ // outside of the range
+				StartTime:    time.Unix(2000, 0),
+				EndTime:      time.Unix(2100, 0),
+				Size:         defaultTargetBytesPerRequest,
+				TotalRecords: 1,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+				Version:      "vParquet2",
+			},
+		},
+	}, o, SearchSharderConfig{
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, log.NewNopLogger())
+
+	q := url.QueryEscape(`{ span.http.method = "GET" && .foo = "bar" } | select(resource.service.name)`)
+	req := httptest.NewRequest("GET", "/?q="+q+"&start=1000&end=1500", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+	resp, err := explainer.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	actual := &searchExplainResponse{}
+	require.NoError(t, json.Unmarshal(body, actual))
+
+	assert.Equal(t, "{ (span.http.method = `GET`) && (.foo = `bar`) }|select(resource.service.name)", actual.Query)
+	assert.True(t, actual.AllConditions)
+	assert.False(t, actual.QueryIngesters)
+	assert.Equal(t, 2, actual.Blocks)
+	assert.Equal(t, uint64(defaultTargetBytesPerRequest*3), actual.BlockBytes)
+	assert.Equal(t, 3, actual.Jobs)
+
+	assert.Equal(t, []explainCondition{
+		{
+			Condition: "span.http.method = `GET`",
+			Columns: map[string][]explainColumn{
+				"vParquet2": {{Column: "rs.list.element.ss.list.element.Spans.list.element.HttpMethod", PushedDown: true}},
+			},
+		},
+		{
+			// generic attributes are found by key and value
+			Condition: ".foo = `bar`",
+			Columns: map[string][]explainColumn{
+				"vParquet2": {
+					{Column: "rs.list.element.Resource.Attrs.list.element.Key", PushedDown: true},
+					{Column: "rs.list.element.Resource.Attrs.list.element.Value", PushedDown: true},
+					{Column: "rs.list.element.ss.list.element.Spans.list.element.Attrs.list.element.Key", PushedDown: true},
+					{Column: "rs.list.element.ss.list.element.Spans.list.element.Attrs.list.element.Value", PushedDown: true},
+				},
+			},
+		},
+	}, actual.Conditions)
+
+	require.NotEmpty(t, actual.SecondPassConditions)
+	assert.Equal(t, explainCondition{
+		Condition: "resource.service.name",
+		Columns: map[string][]explainColumn{
+			"vParquet2": {{Column: "rs.list.element.Resource.ServiceName", PushedDown: false}},
+		},
+	}, actual.SecondPassConditions[0])
+}
+
+func TestSearchExplainBadRequest(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	explainer := newSearchExplainer(&mockReader{}, o, SearchSharderConfig{
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+		MaxDuration:           5 * time.Minute,
+	}, log.NewNopLogger())
+
+	// no query
+	req := httptest.NewRequest("GET", "/?start=1000&end=1100", nil)
+	resp, err := explainer.RoundTrip(req)
+	testBadRequest(t, resp, err, "explain requires a TraceQL query")
+
+	// invalid query
+	req = httptest.NewRequest("GET", "/?q="+url.QueryEscape("{ .foo = }"), nil)
+	resp, err = explainer.RoundTrip(req)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.NoError(t, err)
+
+	// start/end outside of max duration
+	req = httptest.NewRequest("GET", "/?q="+url.QueryEscape("{ }")+"&start=1000&end=1500", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = explainer.RoundTrip(req)
+	testBadRequest(t, resp, err, "range specified by start and end exceeds 5m0s. received start=1000 end=1500")
+}
@@ -0,0 +1,247 @@
+package frontend
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// searchPageJob is a job of a page of a paginated search. maxStartTimeUnixNano bounds the start times of the
+// traces the job can find, all of them start before it.
+type searchPageJob struct {
+	req                  *http.Request
+	maxStartTimeUnixNano uint64
+}
+
+// searchPage returns the progress of a page of a paginated search. Pages are sorted by start time with the
+// most recent traces first and by trace id. Every job returns the first limit traces after the last trace of
+// the previous page and the frontend keeps the first limit traces of all jobs.
+// Ingester and backend ranges are calculated at the time of the first page, so all pages of a search split
+// it the same way. Blocks that start after the last trace of the previous page are left out, all their
+// traces were on previous pages.
+func (s *searchSharder) searchPage(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, hints *traceql.Hints, token *tempopb.SearchPageToken) (*searchPageProgress, []*backend.BlockMeta, error) {
+	from := token
+	if from == nil {
+		from = &tempopb.SearchPageToken{
+			Now:        time.Now().Unix(),
+			SearchHash: searchPageHash(searchReq),
+		}
+	}
+	now := time.Unix(from.Now, 0)
+
+	jobReq := *searchReq
+	jobReq.Paginated = true
+	jobReq.AfterStartTimeUnixNano = from.LastStartTimeUnixNano
+	jobReq.AfterTraceID = from.LastTraceID
+	jobParent, err := api.BuildSearchRequest(parent.Clone(ctx), &jobReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the ingesters can hold traces of any start time
+	var jobs []searchPageJob
+	if searchIngesters, ok := hints.GetBool(traceql.HintIngesters); !ok || searchIngesters {
+		req, err := s.ingesterRequest(ctx, tenantID, jobParent, jobReq, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		if req != nil {
+			jobs = append(jobs, searchPageJob{req: req, maxStartTimeUnixNano: math.MaxUint64})
+		}
+	}
+
+	var blocks []*backend.BlockMeta
+	start, end := s.backendRange(searchReq, now)
+	if start != end {
+		for _, m := range s.blockMetas(int64(start), int64(end), tenantID) {
+			if token != nil && m.StartTime.UnixNano() > int64(token.LastStartTimeUnixNano) {
+				continue
+			}
+			blocks = append(blocks, m)
+		}
+
+		// the most recent blocks first so the page can complete without searching older blocks
+		sort.Slice(blocks, func(i, j int) bool {
+			if !blocks[i].EndTime.Equal(blocks[j].EndTime) {
+				return blocks[i].EndTime.After(blocks[j].EndTime)
+			}
+			return blocks[i].BlockID.String() < blocks[j].BlockID.String()
+		})
+	}
+
+	jobContext := s.jobContexts(tenantID, &jobReq, now)
+	for _, m := range blocks {
+		shards, err := blockShards(m, s.targetBytesPerRequest(hints))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// block times are in seconds, its traces start before the second after its end
+		maxStart := uint64(m.EndTime.Add(time.Second).UnixNano())
+		for _, shard := range shards {
+			req, err := backendRequest(jobContext(ctx, m, shard), tenantID, jobParent, shard)
+			if err != nil {
+				return nil, nil, err
+			}
+			jobs = append(jobs, searchPageJob{req: req, maxStartTimeUnixNano: maxStart})
+		}
+	}
+
+	progress := newSearchPageProgress(ctx, int(searchReq.Limit), jobs, from.Now, from.SearchHash, len(blocks), int(totalBlockBytes(blocks)))
+	return progress, blocks, nil
+}
+
+// searchPageHash returns a hash of the parameters that select the traces of a search. A page token can
+// only be used with the search it was created for, the limit can change between pages.
+func searchPageHash(searchReq *tempopb.SearchRequest) uint64 {
+	tags := make([]string, 0, len(searchReq.Tags))
+	for k := range searchReq.Tags {
+		tags = append(tags, k)
+	}
+	sort.Strings(tags)
+
+	h := fnv.New64a()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	write(searchReq.Query)
+	for _, k := range tags {
+		write(k)
+		write(searchReq.Tags[k])
+	}
+	write(strconv.FormatUint(uint64(searchReq.MinDurationMs), 10))
+	write(strconv.FormatUint(uint64(searchReq.MaxDurationMs), 10))
+	write(strconv.FormatUint(uint64(searchReq.Start), 10))
+	write(strconv.FormatUint(uint64(searchReq.End), 10))
+
+	return h.Sum64()
+}
+
+// searchPageProgress tracks the responses of a paginated search. It keeps the first limit traces found by
+// all jobs. Jobs are ordered by the start time of the most recent trace they can find, the page is complete
+// once it has limit traces and the remaining jobs can only find traces sorted after them.
+// Responses are added with addJobResponse.
+type searchPageProgress struct {
+	*searchProgress
+
+	jobs []searchPageJob
+	now  int64
+	hash uint64
+
+	jobDone []bool
+	// index of the first job that isn't done
+	pendingJob int
+	// last trace of the page so far, set once limit traces were found
+	last *tempopb.TraceSearchMetadata
+	// more is set once a trace after the page was found or a job stopped at the limit
+	more bool
+}
+
+func newSearchPageProgress(ctx context.Context, limit int, jobs []searchPageJob, now int64, hash uint64, totalBlocks, totalBlockBytes int) *searchPageProgress {
+	// pages are sorted like unordered results, most recent first
+	order := &traceql.OrderBy{Key: traceql.OrderKeyStartTime, Desc: true}
+
+	return &searchPageProgress{
+		searchProgress: newSearchProgress(ctx, limit, order, len(jobs), totalBlocks, totalBlockBytes).(*searchProgress),
+		jobs:           jobs,
+		now:            now,
+		hash:           hash,
+		jobDone:        make([]bool, len(jobs)),
+	}
+}
+
+func (p *searchPageProgress) requests() []*http.Request {
+	reqs := make([]*http.Request, 0, len(p.jobs))
+	for _, j := range p.jobs {
+		reqs = append(reqs, j.req)
+	}
+	return reqs
+}
+
+func (p *searchPageProgress) addJobResponse(i int, res *tempopb.SearchResponse) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if len(res.Traces) >= p.limit {
+		p.more = true
+	}
+	p.internalAddResponse(res)
+
+	if len(p.resultsMap) >= p.limit {
+		p.keepPageTraces()
+	}
+
+	p.jobDone[i] = true
+	for p.pendingJob < len(p.jobs) && p.jobDone[p.pendingJob] {
+		p.pendingJob++
+	}
+}
+
+// keepPageTraces drops the traces sorted after the first limit ones, they are on a later page
+func (p *searchPageProgress) keepPageTraces() {
+	traces := make([]*tempopb.TraceSearchMetadata, 0, len(p.resultsMap))
+	for _, t := range p.resultsMap {
+		traces = append(traces, t)
+	}
+	traceql.SortTraces(traces, nil)
+
+	for _, t := range traces[p.limit:] {
+		delete(p.resultsMap, t.TraceID)
+		p.more = true
+	}
+	p.last = traces[p.limit-1]
+}
+
+// shouldQuit locks and checks if we should quit from current execution or not
+func (p *searchPageProgress) shouldQuit() bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	return p.internalShouldQuit() || p.complete()
+}
+
+// complete returns true if none of the remaining jobs can find a trace sorted before the last one of the
+// page. Jobs are ordered by the start time bound of their traces, so only the first remaining one is checked.
+func (p *searchPageProgress) complete() bool {
+	if p.last == nil {
+		return false
+	}
+	return p.pendingJob == len(p.jobs) || p.jobs[p.pendingJob].maxStartTimeUnixNano <= p.last.StartTimeUnixNano
+}
+
+func (p *searchPageProgress) result() *shardedSearchResults {
+	res := p.searchProgress.result()
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	// jobs that weren't searched may find traces of the next pages
+	more := p.more || p.pendingJob < len(p.jobs)
+	if !more || len(res.response.Traces) == 0 {
+		return res
+	}
+
+	last := res.response.Traces[len(res.response.Traces)-1]
+	token, err := api.EncodeSearchPageToken(&tempopb.SearchPageToken{
+		Now:                   p.now,
+		SearchHash:            p.hash,
+		LastStartTimeUnixNano: last.StartTimeUnixNano,
+		LastTraceID:           last.TraceID,
+	})
+	if err != nil && res.err == nil {
+		res.err = err
+	}
+	res.response.NextPageToken = token
+
+	return res
+}
@@ -0,0 +1,241 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/golang/protobuf/jsonpb" //nolint:all deprecated
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSearchSharderPagination(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	blockStart := now.Add(-20 * time.Minute)
+	var metas []*backend.BlockMeta
+	for i := 0; i < 3; i++ {
+		metas = append(metas, &backend.BlockMeta{
+			StartTime:    blockStart,
+			EndTime:      now.Add(time.Duration(-15+i) * time.Minute),
+			Size:         2 * defaultTargetBytesPerRequest,
+			TotalRecords: 2,
+			BlockID:      uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i)),
+		})
+	}
+
+	// every job finds 5 traces, more than the limit of a page and some of them with the same start time.
+	// all jobs also find a trace that is in every block. like the queriers, jobs return the first limit
+	// traces after the cursor
+	var (
+		mtx  sync.Mutex
+		jobs int
+	)
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mtx.Lock()
+		jobs++
+		mtx.Unlock()
+
+		searchReq, err := api.ParseSearchRequest(r)
+		require.NoError(t, err)
+		require.True(t, searchReq.Paginated)
+
+		job, end := "ingester", now
+		if blockID := r.URL.Query().Get("blockID"); blockID != "" {
+			job = blockID + "-" + r.URL.Query().Get("startPage")
+			for _, m := range metas {
+				if m.BlockID.String() == blockID {
+					end = m.EndTime
+				}
+			}
+		}
+
+		traces := []*tempopb.TraceSearchMetadata{{
+			TraceID:           "everywhere",
+			StartTimeUnixNano: uint64(blockStart.UnixNano()),
+		}}
+		for i := 0; i < 5; i++ {
+			traces = append(traces, &tempopb.TraceSearchMetadata{
+				TraceID:           fmt.Sprintf("%s-%d", job, i),
+				StartTimeUnixNano: uint64(end.Add(-time.Duration(i/2) * time.Second).UnixNano()),
+			})
+		}
+
+		res := &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{}}
+		for _, tr := range traces {
+			if traceql.AfterSearchCursor(searchReq, tr) {
+				res.Traces = append(res.Traces, tr)
+			}
+		}
+		res.Traces = traceql.TopTraces(res.Traces, nil, int(searchReq.Limit))
+
+		resString, err := (&jsonpb.Marshaler{}).MarshalToString(res)
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader(resString)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	sharder := newSearchSharder(&mockReader{
+		metas: metas,
+	}, o, nil, SearchSharderConfig{
+		ConcurrentRequests:    1,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+		QueryIngestersUntil:   30 * time.Minute,
+	}, testSLOcfg, newSearchProgress, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	search := func(params url.Values) (*http.Response, *tempopb.SearchResponse) {
+		params.Set("start", strconv.Itoa(int(now.Add(-30*time.Minute).Unix())))
+		params.Set("end", strconv.Itoa(int(now.Unix())))
+		params.Set("limit", "2")
+		req := httptest.NewRequest("GET", "/?"+params.Encode(), nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+		resp, err := testRT.RoundTrip(req)
+		require.NoError(t, err)
+		if resp.StatusCode != http.StatusOK {
+			return resp, nil
+		}
+
+		res := &tempopb.SearchResponse{}
+		require.NoError(t, jsonpb.Unmarshal(resp.Body, res))
+		return resp, res
+	}
+	page := func(query, token string) (*http.Response, *tempopb.SearchResponse) {
+		params := url.Values{}
+		params.Set("q", query)
+		params.Set("paginate", "true")
+		if token != "" {
+			params.Set("pageToken", token)
+		}
+		return search(params)
+	}
+
+	var found []*tempopb.TraceSearchMetadata
+	pages := 0
+	token := ""
+	for {
+		jobs = 0
+		_, res := page("{}", token)
+		require.NotNil(t, res)
+		require.LessOrEqual(t, len(res.Traces), 2)
+		if pages == 0 {
+			// the ingester traces are the most recent, the first page is complete before all blocks are searched
+			require.Less(t, jobs, 7)
+		}
+
+		// requesting the same page again returns the same traces
+		_, again := page("{}", token)
+		require.Equal(t, res.Traces, again.Traces)
+		require.Equal(t, res.NextPageToken, again.NextPageToken)
+
+		found = append(found, res.Traces...)
+		pages++
+
+		if res.NextPageToken == "" {
+			break
+		}
+		token = res.NextPageToken
+		require.Less(t, pages, 30)
+	}
+
+	// ingester + 2 jobs per block, 5 traces each and the trace in every job. every trace is returned
+	// once, in order
+	require.Len(t, found, 36)
+	sorted := append([]*tempopb.TraceSearchMetadata(nil), found...)
+	traceql.SortTraces(sorted, nil)
+	require.Equal(t, sorted, found)
+	ids := map[string]struct{}{}
+	for _, tr := range found {
+		ids[tr.TraceID] = struct{}{}
+	}
+	require.Len(t, ids, 36)
+
+	// the token can't be used with another search
+	resp, _ := page("{ .foo = `bar` }", token)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// ordered searches can't be paginated
+	resp, _ = page("{} | order by duration", "")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// neither can searches by tags
+	resp, _ = search(url.Values{"tags": {"foo=bar"}, "paginate": {"true"}})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestSearchPageProgress(t *testing.T) {
+	jobs := []searchPageJob{
+		{maxStartTimeUnixNano: math.MaxUint64},
+		{maxStartTimeUnixNano: 100},
+		{maxStartTimeUnixNano: 50},
+	}
+	response := func(starts ...uint64) *tempopb.SearchResponse {
+		res := &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{}}
+		for _, s := range starts {
+			res.Traces = append(res.Traces, &tempopb.TraceSearchMetadata{TraceID: strconv.FormatUint(s, 10), StartTimeUnixNano: s})
+		}
+		return res
+	}
+
+	// the first job finds more traces than the limit and the others can't find more recent ones
+	p := newSearchPageProgress(context.Background(), 2, jobs, 0, 0, 0, 0)
+	p.addJobResponse(0, response(120, 200, 150))
+	require.True(t, p.shouldQuit())
+
+	res := p.result()
+	require.Len(t, res.response.Traces, 2)
+	require.Equal(t, "200", res.response.Traces[0].TraceID)
+	require.Equal(t, "150", res.response.Traces[1].TraceID)
+	require.NotEmpty(t, res.response.NextPageToken)
+
+	// a job that may find a trace of the page is waited for
+	p = newSearchPageProgress(context.Background(), 2, jobs, 0, 0, 0, 0)
+	p.addJobResponse(0, response(90))
+	require.False(t, p.shouldQuit())
+	p.addJobResponse(2, response(40, 30))
+	require.False(t, p.shouldQuit())
+	p.addJobResponse(1, response(95))
+	require.True(t, p.shouldQuit())
+
+	res = p.result()
+	require.Len(t, res.response.Traces, 2)
+	require.Equal(t, "95", res.response.Traces[0].TraceID)
+	require.Equal(t, "90", res.response.Traces[1].TraceID)
+	token, err := api.EncodeSearchPageToken(&tempopb.SearchPageToken{LastStartTimeUnixNano: 90, LastTraceID: "90"})
+	require.NoError(t, err)
+	require.Equal(t, token, res.response.NextPageToken)
+
+	// the last page has no token
+	p = newSearchPageProgress(context.Background(), 2, jobs, 0, 0, 0, 0)
+	p.addJobResponse(0, response(90))
+	p.addJobResponse(1, response())
+	p.addJobResponse(2, response(40))
+	require.True(t, p.shouldQuit())
+	res = p.result()
+	require.Len(t, res.response.Traces, 2)
+	require.Empty(t, res.response.NextPageToken)
+}
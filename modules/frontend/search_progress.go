@@ -3,16 +3,16 @@ package frontend
 import (
 	"context"
 	"net/http"
-	"sort"
 	"sync"
 
 	"github.com/grafana/tempo/pkg/search"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
 )
 
 // searchProgressFactory is used to provide a way to construct a shardedSearchProgress to the searchSharder. It exists
 // so that streaming search can inject and track it's own special progress object
-type searchProgressFactory func(ctx context.Context, limit, totalJobs, totalBlocks, totalBlockBytes int) shardedSearchProgress
+type searchProgressFactory func(ctx context.Context, limit int, order *traceql.OrderBy, totalJobs, totalBlocks, totalBlockBytes int) shardedSearchProgress
 
 // shardedSearchProgress is an interface that allows us to get progress
 // events from the search sharding handler.
@@ -48,14 +48,18 @@ type searchProgress struct {
 	finishedRequests int
 
 	limit int
+	// order is set for TraceQL searches with an order by stage. they can't stop at the first limit
+	// results. all traces are combined and the first limit traces by the order are picked in result()
+	order *traceql.OrderBy
 	mtx   sync.Mutex
 }
 
-func newSearchProgress(ctx context.Context, limit, totalJobs, totalBlocks, totalBlockBytes int) shardedSearchProgress {
+func newSearchProgress(ctx context.Context, limit int, order *traceql.OrderBy, totalJobs, totalBlocks, totalBlockBytes int) shardedSearchProgress {
 	return &searchProgress{
 		ctx:              ctx,
 		statusCode:       http.StatusOK,
 		limit:            limit,
+		order:            order,
 		finishedRequests: 0,
 		resultsMetrics: &tempopb.SearchMetrics{
 			TotalBlocks:     uint32(totalBlocks),
@@ -85,6 +89,12 @@ func (r *searchProgress) addResponse(res *tempopb.SearchResponse) {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
+	r.internalAddResponse(res)
+}
+
+// internalAddResponse adds the response without locking
+// NOTE: only use internally where we already hold lock on searchResponse
+func (r *searchProgress) internalAddResponse(res *tempopb.SearchResponse) {
 	for _, t := range res.Traces {
 		if _, ok := r.resultsMap[t.TraceID]; !ok {
 			r.resultsMap[t.TraceID] = t
@@ -126,7 +136,7 @@ func (r *searchProgress) internalShouldQuit() bool {
 	if r.statusCode/100 != 2 {
 		return true
 	}
-	if len(r.resultsMap) > r.limit {
+	if r.order == nil && len(r.resultsMap) > r.limit {
 		return true
 	}
 
@@ -159,9 +169,11 @@ func (r *searchProgress) result() *shardedSearchResults {
 	for _, t := range r.resultsMap {
 		searchRes.Traces = append(searchRes.Traces, t)
 	}
-	sort.Slice(searchRes.Traces, func(i, j int) bool {
-		return searchRes.Traces[i].StartTimeUnixNano > searchRes.Traces[j].StartTimeUnixNano
-	})
+	if r.order != nil {
+		searchRes.Traces = traceql.TopTraces(searchRes.Traces, r.order, r.limit)
+	} else {
+		traceql.SortTraces(searchRes.Traces, nil)
+	}
 
 	res.response = searchRes
 
@@ -3,10 +3,12 @@ package frontend
 import (
 	"context"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,36 +16,36 @@ func TestSearchProgressShouldQuit(t *testing.T) {
 	ctx := context.Background()
 
 	// brand-new response should not quit
-	sr := newSearchProgress(ctx, 10, 0, 0, 0)
+	sr := newSearchProgress(ctx, 10, nil, 0, 0, 0)
 	assert.False(t, sr.shouldQuit())
 
 	// errored response should quit
-	sr = newSearchProgress(ctx, 10, 0, 0, 0)
+	sr = newSearchProgress(ctx, 10, nil, 0, 0, 0)
 	sr.setError(errors.New("blerg"))
 	assert.True(t, sr.shouldQuit())
 
 	// happy status code should not quit
-	sr = newSearchProgress(ctx, 10, 0, 0, 0)
+	sr = newSearchProgress(ctx, 10, nil, 0, 0, 0)
 	sr.setStatus(200, "")
 	assert.False(t, sr.shouldQuit())
 
 	// sad status code should quit
-	sr = newSearchProgress(ctx, 10, 0, 0, 0)
+	sr = newSearchProgress(ctx, 10, nil, 0, 0, 0)
 	sr.setStatus(400, "")
 	assert.True(t, sr.shouldQuit())
 
-	sr = newSearchProgress(ctx, 10, 0, 0, 0)
+	sr = newSearchProgress(ctx, 10, nil, 0, 0, 0)
 	sr.setStatus(500, "")
 	assert.True(t, sr.shouldQuit())
 
 	// cancelled context should quit
 	cancellableContext, cancel := context.WithCancel(ctx)
-	sr = newSearchProgress(cancellableContext, 10, 0, 0, 0)
+	sr = newSearchProgress(cancellableContext, 10, nil, 0, 0, 0)
 	cancel()
 	assert.True(t, sr.shouldQuit())
 
 	// limit reached should quit
-	sr = newSearchProgress(ctx, 2, 0, 0, 0)
+	sr = newSearchProgress(ctx, 2, nil, 0, 0, 0)
 	sr.addResponse(&tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -83,7 +85,7 @@ func TestSearchProgressCombineResults(t *testing.T) {
 	start := time.Date(1, 2, 3, 4, 5, 6, 7, time.UTC)
 	traceID := "traceID"
 
-	sr := newSearchProgress(context.Background(), 10, 0, 0, 0)
+	sr := newSearchProgress(context.Background(), 10, nil, 0, 0, 0)
 	sr.addResponse(&tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -121,3 +123,48 @@ func TestSearchProgressCombineResults(t *testing.T) {
 	assert.Equal(t, expected, sr.result())
 
 }
+
+func TestSearchProgressOrderBy(t *testing.T) {
+	sr := newSearchProgress(context.Background(), 2, &traceql.OrderBy{Key: traceql.OrderKeyDuration, Desc: true}, 0, 0, 0)
+
+	// ordered searches don't quit at the limit, a slower trace can still be found
+	for i, durationMs := range []uint32{10, 50, 20, 40, 30, 5} {
+		sr.addResponse(&tempopb.SearchResponse{
+			Traces: []*tempopb.TraceSearchMetadata{
+				{
+					TraceID:    strconv.Itoa(i),
+					DurationMs: durationMs,
+				},
+			},
+			Metrics: &tempopb.SearchMetrics{},
+		})
+		assert.False(t, sr.shouldQuit())
+	}
+
+	assert.Equal(t, []*tempopb.TraceSearchMetadata{
+		{TraceID: "1", DurationMs: 50},
+		{TraceID: "3", DurationMs: 40},
+	}, sr.result().response.Traces)
+}
+
+func TestSearchProgressOrderByCombinesTraces(t *testing.T) {
+	sr := newSearchProgress(context.Background(), 1, &traceql.OrderBy{Key: traceql.OrderKeyStartTime}, 0, 0, 0)
+
+	// the root span of trace 1 is only found by the first response and its earliest span by the last one
+	for _, tr := range []*tempopb.TraceSearchMetadata{
+		{TraceID: "1", StartTimeUnixNano: 50, RootServiceName: "foo"},
+		{TraceID: "2", StartTimeUnixNano: 40},
+		{TraceID: "3", StartTimeUnixNano: 30},
+		{TraceID: "4", StartTimeUnixNano: 20},
+		{TraceID: "1", StartTimeUnixNano: 10},
+	} {
+		sr.addResponse(&tempopb.SearchResponse{
+			Traces:  []*tempopb.TraceSearchMetadata{tr},
+			Metrics: &tempopb.SearchMetrics{},
+		})
+	}
+
+	assert.Equal(t, []*tempopb.TraceSearchMetadata{
+		{TraceID: "1", StartTimeUnixNano: 10, RootServiceName: "foo"},
+	}, sr.result().response.Traces)
+}
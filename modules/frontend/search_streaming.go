@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/tempodb"
 )
 
@@ -32,10 +33,10 @@ type diffSearchProgress struct {
 	mtx        sync.Mutex
 }
 
-func newDiffSearchProgress(ctx context.Context, limit, totalJobs, totalBlocks, totalBlockBytes int) *diffSearchProgress {
+func newDiffSearchProgress(ctx context.Context, limit int, order *traceql.OrderBy, totalJobs, totalBlocks, totalBlockBytes int) *diffSearchProgress {
 	return &diffSearchProgress{
 		seenTraces: map[string]struct{}{},
-		progress:   newSearchProgress(ctx, limit, totalJobs, totalBlocks, totalBlockBytes),
+		progress:   newSearchProgress(ctx, limit, order, totalJobs, totalBlocks, totalBlockBytes),
 	}
 }
 
@@ -93,7 +94,7 @@ func (p *diffSearchProgress) finalResult() *shardedSearchResults {
 }
 
 // newSearchStreamingHandler returns a handler that streams results from the HTTP handler
-func newSearchStreamingHandler(cfg Config, o overrides.Interface, downstream http.RoundTripper, reader tempodb.Reader, apiPrefix string, logger log.Logger) streamingSearchHandler {
+func newSearchStreamingHandler(cfg Config, o overrides.Interface, cache *searchJobCache, downstream http.RoundTripper, reader tempodb.Reader, apiPrefix string, logger log.Logger) streamingSearchHandler {
 	downstreamPath := path.Join(apiPrefix, api.PathSearch)
 	return func(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error {
 		// build search request and propagate context
@@ -119,13 +120,13 @@ func newSearchStreamingHandler(cfg Config, o overrides.Interface, downstream htt
 		}
 
 		progress := atomic.NewPointer[*diffSearchProgress](nil)
-		fn := func(ctx context.Context, limit, totalJobs, totalBlocks, totalBlockBytes int) shardedSearchProgress {
-			p := newDiffSearchProgress(ctx, limit, totalJobs, totalBlocks, totalBlockBytes)
+		fn := func(ctx context.Context, limit int, order *traceql.OrderBy, totalJobs, totalBlocks, totalBlockBytes int) shardedSearchProgress {
+			p := newDiffSearchProgress(ctx, limit, order, totalJobs, totalBlocks, totalBlockBytes)
 			progress.Store(&p)
 			return p
 		}
 		// build roundtripper
-		rt := NewRoundTripper(downstream, newSearchSharder(reader, o, cfg.Search.Sharder, cfg.Search.SLO, fn, logger))
+		rt := NewRoundTripper(downstream, newSearchSharder(reader, o, cache, cfg.Search.Sharder, cfg.Search.SLO, fn, logger))
 
 		type roundTripResult struct {
 			resp *http.Response
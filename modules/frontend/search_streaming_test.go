@@ -219,7 +219,7 @@ func testHandler(t *testing.T, next http.RoundTripper) streamingSearchHandler {
 				TargetBytesPerRequest: defaultTargetBytesPerRequest,
 			},
 		},
-	}, o, next, &mockReader{
+	}, o, nil, next, &mockReader{
 		metas: []*backend.BlockMeta{ // one block with 2 records that are each the target bytes per request will force 2 sub queries
 			{
 				StartTime:    time.Unix(1100, 0),
@@ -236,7 +236,7 @@ func testHandler(t *testing.T, next http.RoundTripper) streamingSearchHandler {
 
 func TestDiffSearchProgress(t *testing.T) {
 	ctx := context.Background()
-	diffProgress := newDiffSearchProgress(ctx, 0, 0, 0, 0)
+	diffProgress := newDiffSearchProgress(ctx, 0, nil, 0, 0, 0)
 
 	// first request should be empty
 	require.Equal(t, &tempopb.SearchResponse{
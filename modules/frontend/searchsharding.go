@@ -1,12 +1,15 @@
 package frontend
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,13 +25,16 @@ import (
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/boundedwaitgroup"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 )
 
 const (
-	defaultTargetBytesPerRequest = 100 * 1024 * 1024
-	defaultConcurrentRequests    = 1000
+	defaultTargetBytesPerRequest    = 100 * 1024 * 1024
+	defaultMinTargetBytesPerRequest = 10 * 1024 * 1024
+	defaultMaxTargetBytesPerRequest = 1024 * 1024 * 1024
+	defaultConcurrentRequests       = 1000
 )
 
 var (
@@ -55,6 +61,7 @@ type searchSharder struct {
 	next      http.RoundTripper
 	reader    tempodb.Reader
 	overrides overrides.Interface
+	cache     *searchJobCache
 	progress  searchProgressFactory
 
 	cfg    SearchSharderConfig
@@ -63,22 +70,27 @@ type searchSharder struct {
 }
 
 type SearchSharderConfig struct {
-	ConcurrentRequests    int           `yaml:"concurrent_jobs,omitempty"`
-	TargetBytesPerRequest int           `yaml:"target_bytes_per_job,omitempty"`
-	DefaultLimit          uint32        `yaml:"default_result_limit"`
-	MaxLimit              uint32        `yaml:"max_result_limit"`
-	MaxDuration           time.Duration `yaml:"max_duration"`
-	QueryBackendAfter     time.Duration `yaml:"query_backend_after,omitempty"`
-	QueryIngestersUntil   time.Duration `yaml:"query_ingesters_until,omitempty"`
+	ConcurrentRequests    int `yaml:"concurrent_jobs,omitempty"`
+	TargetBytesPerRequest int `yaml:"target_bytes_per_job,omitempty"`
+	// MinTargetBytesPerRequest and MaxTargetBytesPerRequest bound the job_size query hint
+	MinTargetBytesPerRequest int           `yaml:"min_target_bytes_per_job,omitempty"`
+	MaxTargetBytesPerRequest int           `yaml:"max_target_bytes_per_job,omitempty"`
+	DefaultLimit             uint32        `yaml:"default_result_limit"`
+	MaxLimit                 uint32        `yaml:"max_result_limit"`
+	MaxDuration              time.Duration `yaml:"max_duration"`
+	QueryBackendAfter        time.Duration `yaml:"query_backend_after,omitempty"`
+	QueryIngestersUntil      time.Duration `yaml:"query_ingesters_until,omitempty"`
 }
 
-// newSearchSharder creates a sharding middleware for search
-func newSearchSharder(reader tempodb.Reader, o overrides.Interface, cfg SearchSharderConfig, sloCfg SLOConfig, progress searchProgressFactory, logger log.Logger) Middleware {
+// newSearchSharder creates a sharding middleware for search. The results of backend jobs are cached in
+// cache, if it's not nil.
+func newSearchSharder(reader tempodb.Reader, o overrides.Interface, cache *searchJobCache, cfg SearchSharderConfig, sloCfg SLOConfig, progress searchProgressFactory, logger log.Logger) Middleware {
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
 		return searchSharder{
 			next:      next,
 			reader:    reader,
 			overrides: o,
+			cache:     cache,
 			cfg:       cfg,
 			sloCfg:    sloCfg,
 			logger:    logger,
@@ -96,12 +108,15 @@ func newSearchSharder(reader tempodb.Reader, o overrides.Interface, cfg SearchSh
 // start=<unix epoch seconds>
 // end=<unix epoch seconds>
 func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
-	searchReq, err := api.ParseSearchRequest(r)
+	searchReq, rootExpr, err := api.ParseSearchRequestWithExpr(r)
 	if err != nil {
-		return &http.Response{
-			StatusCode: http.StatusBadRequest,
-			Body:       io.NopCloser(strings.NewReader(err.Error())),
-		}, nil
+		return invalidSearchRequest(err), nil
+	}
+	hints, order := rootExpr.SearchHints(), rootExpr.SearchOrder()
+
+	paginate, pageToken, err := api.ParseSearchPageToken(r)
+	if err != nil {
+		return badRequest(err.Error()), nil
 	}
 
 	// adjust limit based on config
@@ -132,48 +147,164 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 		}, nil
 	}
 
-	// build request to search ingester based on query_ingesters_until config and time range
-	// pass subCtx in requests so we can cancel and exit early
-	ingesterReq, err := s.ingesterRequest(subCtx, tenantID, r, *searchReq)
+	var (
+		reqs        []*http.Request
+		blocks      []*backend.BlockMeta
+		progress    shardedSearchProgress
+		addResponse func(i int, res *tempopb.SearchResponse)
+	)
+	if paginate {
+		if order != nil {
+			return badRequest("pagination is not supported for searches with an order by"), nil
+		}
+		if !api.IsTraceQLQuery(searchReq) {
+			return badRequest("pagination is only supported for TraceQL queries"), nil
+		}
+		if pageToken != nil && pageToken.SearchHash != searchPageHash(searchReq) {
+			return badRequest("invalid pageToken: the token belongs to a different search"), nil
+		}
+
+		var page *searchPageProgress
+		page, blocks, err = s.searchPage(subCtx, tenantID, r, searchReq, hints, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		reqs, progress, addResponse = page.requests(), page, page.addJobResponse
+	} else {
+		reqs, blocks, err = s.searchRequests(subCtx, tenantID, r, searchReq, hints)
+		if err != nil {
+			return nil, err
+		}
+		progress = s.progress(ctx, int(searchReq.Limit), order, len(reqs), len(blocks), int(totalBlockBytes(blocks)))
+		addResponse = func(_ int, res *tempopb.SearchResponse) { progress.addResponse(res) }
+	}
+	span.SetTag("block-count", len(blocks))
+	span.SetTag("request-count", len(reqs))
+
+	startedReqs := s.executeRequests(reqs, progress, addResponse, subCancel)
+
+	// print out request metrics
+	overallResponse := progress.result()
+
+	cancelledReqs := startedReqs - overallResponse.finishedRequests
+	reqTime := time.Since(reqStart)
+	throughput := float64(overallResponse.response.Metrics.InspectedBytes) / reqTime.Seconds()
+	searchThroughput.WithLabelValues(tenantID).Observe(throughput)
+
+	query, _ := url.PathUnescape(r.URL.RawQuery)
+	span.SetTag("query", query)
+	level.Info(s.logger).Log(
+		"msg", "sharded search query request stats and SearchMetrics",
+		"query", query,
+		"duration_seconds", reqTime,
+		"request_throughput", throughput,
+		"total_requests", len(reqs),
+		"started_requests", startedReqs,
+		"cancelled_requests", cancelledReqs,
+		"finished_requests", overallResponse.finishedRequests,
+		"totalBlocks", overallResponse.response.Metrics.TotalBlocks,
+		"inspectedBytes", overallResponse.response.Metrics.InspectedBytes,
+		"inspectedTraces", overallResponse.response.Metrics.InspectedTraces,
+		"totalBlockBytes", overallResponse.response.Metrics.TotalBlockBytes)
+
+	// all goroutines have finished, we can safely access searchResults fields directly now
+	span.SetTag("totalBlocks", overallResponse.response.Metrics.TotalBlocks)
+	span.SetTag("inspectedBytes", overallResponse.response.Metrics.InspectedBytes)
+	span.SetTag("inspectedTraces", overallResponse.response.Metrics.InspectedTraces)
+	span.SetTag("totalBlockBytes", overallResponse.response.Metrics.TotalBlockBytes)
+
+	if overallResponse.err != nil {
+		return nil, overallResponse.err
+	}
+
+	if overallResponse.statusCode != http.StatusOK {
+		// translate all non-200s into 500s. if, for instance, we get a 400 back from an internal component
+		// it means that we created a bad request. 400 should not be propagated back to the user b/c
+		// the bad request was due to a bug on our side, so return 500 instead.
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(overallResponse.statusMsg)),
+		}, nil
+	}
+
+	m := &jsonpb.Marshaler{}
+	bodyString, err := m.MarshalToString(overallResponse.response)
 	if err != nil {
 		return nil, err
 	}
 
+	// only record metric when it's enabled and within slo
+	if s.sloCfg.DurationSLO != 0 && s.sloCfg.ThroughputBytesSLO != 0 {
+		if reqTime < s.sloCfg.DurationSLO || throughput > s.sloCfg.ThroughputBytesSLO {
+			// query is within SLO if query returned 200 within DurationSLO seconds OR
+			// processed ThroughputBytesSLO bytes/s data
+			sloSearchCounter.WithLabelValues(tenantID).Inc()
+		}
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptJSON},
+		},
+		Body:          io.NopCloser(strings.NewReader(bodyString)),
+		ContentLength: int64(len([]byte(bodyString))),
+	}, nil
+}
+
+// searchRequests returns the ingester and backend requests of a search. the ingester request is the first
+// one so that it is prioritized over the possibly enormous number of backend requests.
+func (s *searchSharder) searchRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, hints *traceql.Hints) ([]*http.Request, []*backend.BlockMeta, error) {
+	now := time.Now()
+
+	// build request to search ingester based on query_ingesters_until config and time range
+	var ingesterReq *http.Request
+	if searchIngesters, ok := hints.GetBool(traceql.HintIngesters); !ok || searchIngesters {
+		var err error
+		ingesterReq, err = s.ingesterRequest(ctx, tenantID, parent, *searchReq, now)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// calculate duration (start and end) to search the backend blocks
-	start, end := s.backendRange(searchReq)
+	start, end := s.backendRange(searchReq, now)
 
 	// get block metadata of blocks in start, end duration
 	blocks := s.blockMetas(int64(start), int64(end), tenantID)
-	span.SetTag("block-count", len(blocks))
+
+	// search the newest blocks first so the most recent traces are found early
+	if mostRecent, _ := hints.GetBool(traceql.HintMostRecent); mostRecent {
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].EndTime.After(blocks[j].EndTime)
+		})
+	}
 
 	var reqs []*http.Request
 	// add backend requests if we need them
 	if start != end {
-		// pass subCtx in requests so we can cancel and exit early
-		reqs, err = s.backendRequests(subCtx, tenantID, r, blocks)
+		var err error
+		reqs, err = s.backendRequests(ctx, tenantID, parent, blocks, s.targetBytesPerRequest(hints), s.jobContexts(tenantID, searchReq, now))
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
-	// add ingester request if we have one. it's important to add the ingester request to
-	// the beginning of the slice so that it is prioritized over the possibly enormous
-	// number of backend requests
 	if ingesterReq != nil {
 		reqs = append([]*http.Request{ingesterReq}, reqs...)
 	}
-	span.SetTag("request-count", len(reqs))
 
-	// execute requests
-	wg := boundedwaitgroup.New(uint(s.cfg.ConcurrentRequests))
+	return reqs, blocks, nil
+}
 
-	totalBlockBytes := uint64(0)
-	for _, b := range blocks {
-		totalBlockBytes += b.Size
-	}
-	progress := s.progress(ctx, int(searchReq.Limit), len(reqs), len(blocks), int(totalBlockBytes))
+// executeRequests executes up to ConcurrentRequests requests simultaneously until the progress says to quit.
+// addResponse is called with the index of the request for every successful response. It returns the number
+// of started requests.
+func (s *searchSharder) executeRequests(reqs []*http.Request, progress shardedSearchProgress, addResponse func(i int, res *tempopb.SearchResponse), cancel context.CancelFunc) int {
+	wg := boundedwaitgroup.New(uint(s.cfg.ConcurrentRequests))
 
 	startedReqs := 0
-	for _, req := range reqs {
+	for i, req := range reqs {
 		// if shouldQuit is true, terminate and abandon requests
 		if progress.shouldQuit() {
 			break
@@ -183,14 +314,23 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 		wg.Add(1)
 		startedReqs++
 
-		go func(innerR *http.Request) {
+		go func(i int, innerR *http.Request) {
 			defer func() {
 				if progress.shouldQuit() {
-					subCancel()
+					cancel()
 				}
 				wg.Done()
 			}()
 
+			// the results of cached jobs may already be known
+			job, cached := innerR.Context().Value(searchCacheJobKey{}).(searchCacheJob)
+			if cached {
+				if results := s.cache.fetch(innerR.Context(), job); results != nil {
+					addResponse(i, results)
+					return
+				}
+			}
+
 			resp, err := s.next.RoundTrip(innerR)
 			if err != nil {
 				// context cancelled error happens when we exit early.
@@ -226,82 +366,19 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 				return
 			}
 
+			if cached {
+				s.cache.store(innerR.Context(), job, results)
+			}
+
 			// happy path
-			progress.addResponse(results)
-		}(req)
+			addResponse(i, results)
+		}(i, req)
 	}
 
 	// wait for all goroutines running in wg to finish or cancelled
 	wg.Wait()
 
-	// print out request metrics
-	overallResponse := progress.result()
-
-	cancelledReqs := startedReqs - overallResponse.finishedRequests
-	reqTime := time.Since(reqStart)
-	throughput := float64(overallResponse.response.Metrics.InspectedBytes) / reqTime.Seconds()
-	searchThroughput.WithLabelValues(tenantID).Observe(throughput)
-
-	query, _ := url.PathUnescape(r.URL.RawQuery)
-	span.SetTag("query", query)
-	level.Info(s.logger).Log(
-		"msg", "sharded search query request stats and SearchMetrics",
-		"query", query,
-		"duration_seconds", reqTime,
-		"request_throughput", throughput,
-		"total_requests", len(reqs),
-		"started_requests", startedReqs,
-		"cancelled_requests", cancelledReqs,
-		"finished_requests", overallResponse.finishedRequests,
-		"totalBlocks", overallResponse.response.Metrics.TotalBlocks,
-		"inspectedBytes", overallResponse.response.Metrics.InspectedBytes,
-		"inspectedTraces", overallResponse.response.Metrics.InspectedTraces,
-		"totalBlockBytes", overallResponse.response.Metrics.TotalBlockBytes)
-
-	// all goroutines have finished, we can safely access searchResults fields directly now
-	span.SetTag("totalBlocks", overallResponse.response.Metrics.TotalBlocks)
-	span.SetTag("inspectedBytes", overallResponse.response.Metrics.InspectedBytes)
-	span.SetTag("inspectedTraces", overallResponse.response.Metrics.InspectedTraces)
-	span.SetTag("totalBlockBytes", overallResponse.response.Metrics.TotalBlockBytes)
-
-	if overallResponse.err != nil {
-		return nil, overallResponse.err
-	}
-
-	if overallResponse.statusCode != http.StatusOK {
-		// translate all non-200s into 500s. if, for instance, we get a 400 back from an internal component
-		// it means that we created a bad request. 400 should not be propagated back to the user b/c
-		// the bad request was due to a bug on our side, so return 500 instead.
-		return &http.Response{
-			StatusCode: http.StatusInternalServerError,
-			Header:     http.Header{},
-			Body:       io.NopCloser(strings.NewReader(overallResponse.statusMsg)),
-		}, nil
-	}
-
-	m := &jsonpb.Marshaler{}
-	bodyString, err := m.MarshalToString(overallResponse.response)
-	if err != nil {
-		return nil, err
-	}
-
-	// only record metric when it's enabled and within slo
-	if s.sloCfg.DurationSLO != 0 && s.sloCfg.ThroughputBytesSLO != 0 {
-		if reqTime < s.sloCfg.DurationSLO || throughput > s.sloCfg.ThroughputBytesSLO {
-			// query is within SLO if query returned 200 within DurationSLO seconds OR
-			// processed ThroughputBytesSLO bytes/s data
-			sloSearchCounter.WithLabelValues(tenantID).Inc()
-		}
-	}
-
-	return &http.Response{
-		StatusCode: http.StatusOK,
-		Header: http.Header{
-			api.HeaderContentType: {api.HeaderAcceptJSON},
-		},
-		Body:          io.NopCloser(strings.NewReader(bodyString)),
-		ContentLength: int64(len([]byte(bodyString))),
-	}, nil
+	return startedReqs
 }
 
 // blockMetas returns all relevant blockMetas given a start/end
@@ -321,45 +398,19 @@ func (s *searchSharder) blockMetas(start, end int64, tenantID string) []*backend
 
 // backendRequests returns a slice of requests that cover all blocks in the store
 // that are covered by start/end.
-func (s *searchSharder) backendRequests(ctx context.Context, tenantID string, parent *http.Request, metas []*backend.BlockMeta) ([]*http.Request, error) {
+func (s *searchSharder) backendRequests(ctx context.Context, tenantID string, parent *http.Request, metas []*backend.BlockMeta, targetBytesPerRequest int, jobContext searchJobContext) ([]*http.Request, error) {
 	reqs := []*http.Request{}
 	for _, m := range metas {
-		if m.Size == 0 || m.TotalRecords == 0 {
-			continue
-		}
-
-		bytesPerPage := m.Size / uint64(m.TotalRecords)
-		if bytesPerPage == 0 {
-			return nil, fmt.Errorf("block %s has an invalid 0 bytes per page", m.BlockID)
-		}
-		pagesPerQuery := s.cfg.TargetBytesPerRequest / int(bytesPerPage)
-		if pagesPerQuery == 0 {
-			pagesPerQuery = 1 // have to have at least 1 page per query
+		shards, err := blockShards(m, targetBytesPerRequest)
+		if err != nil {
+			return nil, err
 		}
 
-		blockID := m.BlockID.String()
-		for startPage := 0; startPage < int(m.TotalRecords); startPage += pagesPerQuery {
-			subR := parent.Clone(ctx)
-			subR.Header.Set(user.OrgIDHeaderName, tenantID)
-
-			subR, err := api.BuildSearchBlockRequest(subR, &tempopb.SearchBlockRequest{
-				BlockID:       blockID,
-				StartPage:     uint32(startPage),
-				PagesToSearch: uint32(pagesPerQuery),
-				Encoding:      m.Encoding.String(),
-				IndexPageSize: m.IndexPageSize,
-				TotalRecords:  m.TotalRecords,
-				DataEncoding:  m.DataEncoding,
-				Version:       m.Version,
-				Size_:         m.Size,
-				FooterSize:    m.FooterSize,
-			})
-
+		for _, shard := range shards {
+			subR, err := backendRequest(jobContext(ctx, m, shard), tenantID, parent, shard)
 			if err != nil {
 				return nil, err
 			}
-
-			subR.RequestURI = buildUpstreamRequestURI(parent.URL.Path, subR.URL.Query())
 			reqs = append(reqs, subR)
 		}
 	}
@@ -367,12 +418,61 @@ func (s *searchSharder) backendRequests(ctx context.Context, tenantID string, pa
 	return reqs, nil
 }
 
+// backendRequest returns the request that searches a range of pages of a block
+func backendRequest(ctx context.Context, tenantID string, parent *http.Request, shard *tempopb.SearchBlockRequest) (*http.Request, error) {
+	subR := parent.Clone(ctx)
+	subR.Header.Set(user.OrgIDHeaderName, tenantID)
+
+	subR, err := api.BuildSearchBlockRequest(subR, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	subR.RequestURI = buildUpstreamRequestURI(parent.URL.Path, subR.URL.Query())
+	return subR, nil
+}
+
+// blockShards splits a block into ranges of pages of roughly targetBytesPerRequest each
+func blockShards(m *backend.BlockMeta, targetBytesPerRequest int) ([]*tempopb.SearchBlockRequest, error) {
+	if m.Size == 0 || m.TotalRecords == 0 {
+		return nil, nil
+	}
+
+	bytesPerPage := m.Size / uint64(m.TotalRecords)
+	if bytesPerPage == 0 {
+		return nil, fmt.Errorf("block %s has an invalid 0 bytes per page", m.BlockID)
+	}
+	pagesPerQuery := targetBytesPerRequest / int(bytesPerPage)
+	if pagesPerQuery == 0 {
+		pagesPerQuery = 1 // have to have at least 1 page per query
+	}
+
+	shards := []*tempopb.SearchBlockRequest{}
+	blockID := m.BlockID.String()
+	for startPage := 0; startPage < int(m.TotalRecords); startPage += pagesPerQuery {
+		shards = append(shards, &tempopb.SearchBlockRequest{
+			BlockID:         blockID,
+			StartPage:       uint32(startPage),
+			PagesToSearch:   uint32(pagesPerQuery),
+			Encoding:        m.Encoding.String(),
+			IndexPageSize:   m.IndexPageSize,
+			TotalRecords:    m.TotalRecords,
+			DataEncoding:    m.DataEncoding,
+			Version:         m.Version,
+			Size_:           m.Size,
+			FooterSize:      m.FooterSize,
+			CompactionLevel: uint32(m.CompactionLevel),
+		})
+	}
+
+	return shards, nil
+}
+
 // queryIngesterWithin returns a new start and end time range for the backend as well as an http request
-// that covers the ingesters. If nil is returned for the http.Request then there is no ingesters query.
+// that covers the ingesters at the time now. If nil is returned for the http.Request then there is no ingesters query.
 // since this function modifies searchReq.Start and End we are taking a value instead of a pointer to prevent it from
 // unexpectedly changing the passed searchReq.
-func (s *searchSharder) ingesterRequest(ctx context.Context, tenantID string, parent *http.Request, searchReq tempopb.SearchRequest) (*http.Request, error) {
-	now := time.Now()
+func (s *searchSharder) ingesterRequest(ctx context.Context, tenantID string, parent *http.Request, searchReq tempopb.SearchRequest, now time.Time) (*http.Request, error) {
 	ingesterUntil := uint32(now.Add(-s.cfg.QueryIngestersUntil).Unix())
 
 	// if there's no overlap between the query and ingester range just return nil
@@ -408,9 +508,8 @@ func (s *searchSharder) ingesterRequest(ctx context.Context, tenantID string, pa
 }
 
 // backendRange returns a new start/end range for the backend based on the config parameter
-// query_backend_after. If the returned start == the returned end then backend querying is not necessary.
-func (s *searchSharder) backendRange(searchReq *tempopb.SearchRequest) (uint32, uint32) {
-	now := time.Now()
+// query_backend_after at the time now. If the returned start == the returned end then backend querying is not necessary.
+func (s *searchSharder) backendRange(searchReq *tempopb.SearchRequest, now time.Time) (uint32, uint32) {
 	backendAfter := uint32(now.Add(-s.cfg.QueryBackendAfter).Unix())
 
 	start := searchReq.Start
@@ -428,6 +527,50 @@ func (s *searchSharder) backendRange(searchReq *tempopb.SearchRequest) (uint32,
 	return start, end
 }
 
+// invalidSearchRequest returns a bad request response with the error as JSON body, so that clients can
+// point to TraceQL syntax errors.
+func invalidSearchRequest(err error) *http.Response {
+	body, marshalErr := json.Marshal(api.NewErrorResponse(err))
+	if marshalErr != nil {
+		return badRequest(err.Error())
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptJSON},
+		},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
+
+// targetBytesPerRequest returns the size of backend jobs. The job_size hint takes precedence over the config,
+// within the bounds set by the operator.
+func (s *searchSharder) targetBytesPerRequest(hints *traceql.Hints) int {
+	jobSize, ok := hints.GetInt(traceql.HintJobSize)
+	if !ok {
+		return s.cfg.TargetBytesPerRequest
+	}
+
+	if s.cfg.MinTargetBytesPerRequest > 0 && jobSize < s.cfg.MinTargetBytesPerRequest {
+		return s.cfg.MinTargetBytesPerRequest
+	}
+	if s.cfg.MaxTargetBytesPerRequest > 0 && jobSize > s.cfg.MaxTargetBytesPerRequest {
+		return s.cfg.MaxTargetBytesPerRequest
+	}
+	return jobSize
+}
+
+// totalBlockBytes returns the summed size of the blocks
+func totalBlockBytes(blocks []*backend.BlockMeta) uint64 {
+	total := uint64(0)
+	for _, b := range blocks {
+		total += b.Size
+	}
+	return total
+}
+
 // adjusts the limit based on provided config
 func adjustLimit(limit, defaultLimit, maxLimit uint32) uint32 {
 	if limit == 0 {
@@ -441,6 +584,15 @@ func adjustLimit(limit, defaultLimit, maxLimit uint32) uint32 {
 	return limit
 }
 
+// jobContexts returns the function that sets up the contexts of the backend jobs of a search, so that their
+// results are cached if the cache is enabled for the tenant.
+func (s *searchSharder) jobContexts(tenantID string, searchReq *tempopb.SearchRequest, now time.Time) searchJobContext {
+	if s.cache == nil || !s.overrides.SearchCacheEnabled(tenantID) {
+		return noJobCache
+	}
+	return s.cache.jobContexts(tenantID, searchReq, now)
+}
+
 // maxDuration returns the max search duration allowed for this tenant.
 func (s *searchSharder) maxDuration(tenantID string) time.Duration {
 	// check overrides first, if no overrides then grab from our config
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,6 +29,7 @@ import (
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/blocklist"
 	"github.com/grafana/tempo/tempodb/encoding/common"
@@ -47,6 +49,10 @@ func (m *mockReader) Find(ctx context.Context, tenantID string, id common.ID, bl
 	return nil, nil, nil
 }
 
+func (m *mockReader) FindFunc(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, cb tempodb.FindCallback) ([]error, error) {
+	return nil, nil
+}
+
 func (m *mockReader) BlockMetas(tenantID string) []*backend.BlockMeta {
 	return m.metas
 }
@@ -59,6 +65,22 @@ func (m *mockReader) Fetch(ctx context.Context, meta *backend.BlockMeta, req tra
 	return traceql.FetchSpansResponse{}, nil
 }
 
+func (m *mockReader) SearchTags(ctx context.Context, meta *backend.BlockMeta, scope traceql.AttributeScope, cb common.TagCallback, opts common.SearchOptions) error {
+	return nil
+}
+
+func (m *mockReader) SearchTagValues(ctx context.Context, meta *backend.BlockMeta, tag string, cb common.TagCallback, opts common.SearchOptions) error {
+	return nil
+}
+
+func (m *mockReader) SearchTagValuesV2(ctx context.Context, meta *backend.BlockMeta, tag traceql.Attribute, cb common.TagCallbackV2, opts common.SearchOptions) error {
+	return nil
+}
+
+func (m *mockReader) SearchTagValueCounts(ctx context.Context, meta *backend.BlockMeta, tag traceql.Attribute, cb common.TagValueCountCallback, opts common.SearchOptions) error {
+	return nil
+}
+
 func (m *mockReader) EnablePolling(sharder blocklist.JobSharder) {}
 func (m *mockReader) Shutdown()                                  {}
 
@@ -186,7 +208,7 @@ func TestBackendRequests(t *testing.T) {
 		}
 		req := httptest.NewRequest("GET", "/?k=test&v=test&start=10&end=20", nil)
 
-		reqs, err := s.backendRequests(context.Background(), "test", req, tc.metas)
+		reqs, err := s.backendRequests(context.Background(), "test", req, tc.metas, s.cfg.TargetBytesPerRequest, noJobCache)
 		if tc.expectedError != nil {
 			assert.Equal(t, tc.expectedError, err)
 			continue
@@ -268,7 +290,7 @@ func TestIngesterRequest(t *testing.T) {
 		searchReq, err := api.ParseSearchRequest(req)
 		require.NoError(t, err)
 
-		actualReq, err := s.ingesterRequest(context.Background(), "test", req, *searchReq)
+		actualReq, err := s.ingesterRequest(context.Background(), "test", req, *searchReq, time.Now())
 		if tc.expectedError != nil {
 			assert.Equal(t, tc.expectedError, err)
 			continue
@@ -356,7 +378,7 @@ func TestBackendRange(t *testing.T) {
 		searchReq, err := api.ParseSearchRequest(req)
 		require.NoError(t, err)
 
-		actualStart, actualEnd := s.backendRange(searchReq)
+		actualStart, actualEnd := s.backendRange(searchReq, time.Now())
 		assert.Equal(t, int(tc.expectedStart), int(actualStart))
 		assert.Equal(t, int(tc.expectedEnd), int(actualEnd))
 	}
@@ -555,7 +577,7 @@ func TestSearchSharderRoundTrip(t *testing.T) {
 						BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
 					},
 				},
-			}, o, SearchSharderConfig{
+			}, o, nil, SearchSharderConfig{
 				ConcurrentRequests:    1, // 1 concurrent request to force order
 				TargetBytesPerRequest: defaultTargetBytesPerRequest,
 			}, testSLOcfg, newSearchProgress, log.NewNopLogger())
@@ -592,6 +614,137 @@ func TestSearchSharderRoundTrip(t *testing.T) {
 	}
 }
 
+func TestSearchSharderHints(t *testing.T) {
+	now := time.Now()
+	metas := []*backend.BlockMeta{
+		{
+			StartTime:    now.Add(-20 * time.Minute),
+			EndTime:      now.Add(-15 * time.Minute),
+			Size:         defaultTargetBytesPerRequest,
+			TotalRecords: 2,
+			BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+		},
+		{
+			StartTime:    now.Add(-10 * time.Minute),
+			EndTime:      now.Add(-5 * time.Minute),
+			Size:         defaultTargetBytesPerRequest,
+			TotalRecords: 2,
+			BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		},
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedReqs []string
+	}{
+		{
+			name:  "no hints",
+			query: "{}",
+			expectedReqs: []string{
+				"ingester",
+				"00000000-0000-0000-0000-000000000000",
+				"00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			name:  "most recent",
+			query: "{} with (most_recent=true)",
+			expectedReqs: []string{
+				"ingester",
+				"00000000-0000-0000-0000-000000000001",
+				"00000000-0000-0000-0000-000000000000",
+			},
+		},
+		{
+			name:  "skip ingesters",
+			query: "{} with (ingesters=false)",
+			expectedReqs: []string{
+				"00000000-0000-0000-0000-000000000000",
+				"00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			name:  "job size",
+			query: fmt.Sprintf("{} with (ingesters=false, job_size=%d)", defaultTargetBytesPerRequest/2),
+			expectedReqs: []string{
+				"00000000-0000-0000-0000-000000000000",
+				"00000000-0000-0000-0000-000000000000",
+				"00000000-0000-0000-0000-000000000001",
+				"00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			name:  "job size below min",
+			query: "{} with (ingesters=false, job_size=1)",
+			expectedReqs: []string{
+				"00000000-0000-0000-0000-000000000000",
+				"00000000-0000-0000-0000-000000000000",
+				"00000000-0000-0000-0000-000000000001",
+				"00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			name:  "job size above max",
+			query: fmt.Sprintf("{} with (ingesters=false, job_size=%d)", defaultTargetBytesPerRequest*10),
+			expectedReqs: []string{
+				"00000000-0000-0000-0000-000000000000",
+				"00000000-0000-0000-0000-000000000001",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mtx := sync.Mutex{}
+			actualReqs := []string{}
+			next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				blockID := r.URL.Query().Get("blockID")
+				if blockID == "" {
+					blockID = "ingester"
+				}
+				mtx.Lock()
+				actualReqs = append(actualReqs, blockID)
+				mtx.Unlock()
+
+				resString, err := (&jsonpb.Marshaler{}).MarshalToString(&tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{}})
+				require.NoError(t, err)
+
+				return &http.Response{
+					Body:       io.NopCloser(strings.NewReader(resString)),
+					StatusCode: 200,
+				}, nil
+			})
+
+			o, err := overrides.NewOverrides(overrides.Limits{})
+			require.NoError(t, err)
+
+			sharder := newSearchSharder(&mockReader{
+				metas: metas,
+			}, o, nil, SearchSharderConfig{
+				ConcurrentRequests:       1, // 1 concurrent request to force order
+				TargetBytesPerRequest:    defaultTargetBytesPerRequest,
+				MinTargetBytesPerRequest: defaultTargetBytesPerRequest / 2,
+				MaxTargetBytesPerRequest: defaultTargetBytesPerRequest,
+				QueryIngestersUntil:      30 * time.Minute,
+			}, testSLOcfg, newSearchProgress, log.NewNopLogger())
+			testRT := NewRoundTripper(next, sharder)
+
+			params := url.Values{}
+			params.Set("q", tc.query)
+			params.Set("start", strconv.Itoa(int(now.Add(-30*time.Minute).Unix())))
+			params.Set("end", strconv.Itoa(int(now.Unix())))
+			req := httptest.NewRequest("GET", "/?"+params.Encode(), nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+			resp, err := testRT.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, tc.expectedReqs, actualReqs)
+		})
+	}
+}
+
 func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
 		return nil, nil
@@ -600,7 +753,7 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	o, err := overrides.NewOverrides(overrides.Limits{})
 	require.NoError(t, err)
 
-	sharder := newSearchSharder(&mockReader{}, o, SearchSharderConfig{
+	sharder := newSearchSharder(&mockReader{}, o, nil, SearchSharderConfig{
 		ConcurrentRequests:    defaultConcurrentRequests,
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		MaxDuration:           5 * time.Minute,
@@ -621,7 +774,17 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	// bad request
 	req = httptest.NewRequest("GET", "/?start=asdf&end=1500", nil)
 	resp, err = testRT.RoundTrip(req)
-	testBadRequest(t, resp, err, "invalid start: strconv.ParseInt: parsing \"asdf\": invalid syntax")
+	testBadRequest(t, resp, err, `{"error":"invalid start: strconv.ParseInt: parsing \"asdf\": invalid syntax"}`)
+
+	// invalid traceql query
+	req = httptest.NewRequest("GET", "/?q="+url.QueryEscape("{ durration > 1s }"), nil)
+	resp, err = testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, `{"error":"invalid TraceQL query: parse error at line 1, col 3: syntax error: unexpected IDENTIFIER","line":1,"column":3,"endColumn":12,"token":"durration","suggestions":["duration"]}`)
+
+	// metrics queries are only run by the query range api
+	req = httptest.NewRequest("GET", "/api/search?q="+url.QueryEscape("{ } | rate()"), nil)
+	resp, err = testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, `{"error":"invalid TraceQL query: parse error at line 1, col 1: metrics queries are not supported by search, use the query range api","line":1,"column":1,"endColumn":13}`)
 
 	// test max duration error with overrides
 	o, err = overrides.NewOverrides(overrides.Limits{
@@ -629,7 +792,7 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	sharder = newSearchSharder(&mockReader{}, o, SearchSharderConfig{
+	sharder = newSearchSharder(&mockReader{}, o, nil, SearchSharderConfig{
 		ConcurrentRequests:    defaultConcurrentRequests,
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		MaxDuration:           5 * time.Minute,
@@ -754,7 +917,7 @@ func TestSubRequestsCancelled(t *testing.T) {
 				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
 			},
 		},
-	}, o, SearchSharderConfig{
+	}, o, nil, SearchSharderConfig{
 		ConcurrentRequests:    10,
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		DefaultLimit:          2,
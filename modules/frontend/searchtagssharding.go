@@ -0,0 +1,507 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/jsonpb" //nolint:all deprecated
+	"github.com/gogo/protobuf/proto"
+	"github.com/opentracing/opentracing-go"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/search"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type searchTagsSharder struct {
+	next      http.RoundTripper
+	search    searchSharder
+	overrides overrides.Interface
+
+	cfg    SearchSharderConfig
+	logger log.Logger
+}
+
+// newSearchTagsSharder creates a sharding middleware for the tags and tag values endpoints. Requests with
+// a start and end search the ingesters and every backend block in the range. Requests without only
+// search the ingesters.
+func newSearchTagsSharder(reader tempodb.Reader, o overrides.Interface, cfg SearchSharderConfig, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return searchTagsSharder{
+			next: next,
+			search: searchSharder{
+				reader:    reader,
+				overrides: o,
+				cfg:       cfg,
+				logger:    logger,
+			},
+			overrides: o,
+			cfg:       cfg,
+			logger:    logger,
+		}
+	})
+}
+
+// RoundTrip implements http.RoundTripper
+// executes one job per backend block in the range, up to concurrentRequests simultaneously, and
+// combines the distinct tags or tag values until max_bytes_per_tag_values_query is exceeded.
+func (s searchTagsSharder) RoundTrip(r *http.Request) (*http.Response, error) {
+	start, end, err := api.ParseSearchTagsRange(r)
+	if err != nil {
+		return badRequest(err.Error()), nil
+	}
+
+	ctx := r.Context()
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return badRequest(err.Error()), nil
+	}
+
+	// without a time range only the recent data in the ingesters is searched. the stats of the
+	// ingesters still have to be combined to apply the limit
+	recentOnly := start == 0 && end == 0
+	if recentOnly && !api.IsSearchTagValueStats(r) {
+		r.Header.Set(user.OrgIDHeaderName, tenantID)
+		r.RequestURI = buildUpstreamRequestURI(r.RequestURI, nil)
+
+		return s.next.RoundTrip(r)
+	}
+
+	combiner, err := newTagsCombiner(r, s.overrides.MaxBytesPerTagValuesQuery(tenantID))
+	if err != nil {
+		return badRequest(err.Error()), nil
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "frontend.ShardSearchTags")
+	defer span.Finish()
+
+	reqStart := time.Now()
+	// sub context to cancel in-progress sub requests
+	subCtx, subCancel := context.WithCancel(ctx)
+	defer subCancel()
+
+	// calculate and enforce max search duration
+	maxDuration := s.search.maxDuration(tenantID)
+	if !recentOnly && maxDuration != 0 && time.Duration(end-start)*time.Second > maxDuration {
+		return badRequest(fmt.Sprintf("range specified by start and end exceeds %s. received start=%d end=%d", maxDuration, start, end)), nil
+	}
+
+	var reqs []*http.Request
+
+	// the ingesters return everything they hold, so they are searched if the range overlaps with the
+	// ingester window at all
+	if recentOnly || end >= uint32(time.Now().Add(-s.cfg.QueryIngestersUntil).Unix()) {
+		subR := r.Clone(subCtx)
+		subR.Header.Set(user.OrgIDHeaderName, tenantID)
+		subR.RequestURI = buildUpstreamRequestURI(r.URL.Path, r.URL.Query())
+		reqs = append(reqs, subR)
+	}
+
+	backendStart, backendEnd := s.search.backendRange(&tempopb.SearchRequest{Start: start, End: end}, time.Now())
+	var blocks []*backend.BlockMeta
+	if !recentOnly && backendStart != backendEnd {
+		blocks = s.blocks(tenantID, backendStart, backendEnd)
+	}
+	span.SetTag("block-count", len(blocks))
+
+	for _, m := range blocks {
+		blockReq := blockRequest(m)
+		if blockReq == nil {
+			continue
+		}
+
+		subR := r.Clone(subCtx)
+		subR.Header.Set(user.OrgIDHeaderName, tenantID)
+
+		subR = api.BuildSearchTagsBlockRequest(subR, blockReq)
+		subR.RequestURI = buildUpstreamRequestURI(r.URL.Path, subR.URL.Query())
+		reqs = append(reqs, subR)
+	}
+	span.SetTag("request-count", len(reqs))
+
+	var (
+		mtx        sync.Mutex
+		overallErr error
+		statusCode = http.StatusOK
+		statusMsg  string
+	)
+
+	wg := boundedwaitgroup.New(uint(s.cfg.ConcurrentRequests))
+	for _, subR := range reqs {
+		// wg.Add blocks until a slot is free, so check for early exit afterwards to see the latest results
+		wg.Add(1)
+
+		mtx.Lock()
+		quit := overallErr != nil || statusCode != http.StatusOK || combiner.exceeded()
+		mtx.Unlock()
+		if quit {
+			wg.Done()
+			break
+		}
+
+		go func(innerR *http.Request) {
+			defer wg.Done()
+
+			resp, err := s.next.RoundTrip(innerR)
+			if err != nil {
+				// context cancelled error happens when we exit early.
+				if errors.Is(err, context.Canceled) {
+					_ = level.Debug(s.logger).Log("msg", "exiting early from sharded query", "url", innerR.RequestURI, "err", err)
+					return
+				}
+
+				_ = level.Error(s.logger).Log("msg", "error executing sharded query", "url", innerR.RequestURI, "err", err)
+				mtx.Lock()
+				overallErr = err
+				mtx.Unlock()
+				subCancel()
+				return
+			}
+
+			// if the status code is anything but happy, save the error and pass it down the line
+			if resp.StatusCode != http.StatusOK {
+				bytesMsg, err := io.ReadAll(resp.Body)
+				if err != nil {
+					_ = level.Error(s.logger).Log("msg", "error reading response body status != ok", "url", innerR.RequestURI, "err", err)
+				}
+				mtx.Lock()
+				statusCode = resp.StatusCode
+				statusMsg = fmt.Sprintf("upstream: (%d) %s", resp.StatusCode, string(bytesMsg))
+				mtx.Unlock()
+				subCancel()
+				return
+			}
+
+			// successful query, read the body
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			err = combiner.addResponse(resp.Body)
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error reading response body status == ok", "url", innerR.RequestURI, "err", err)
+				overallErr = err
+				subCancel()
+				return
+			}
+
+			// stop searching once the limit is reached. the results are partial from here on
+			if combiner.exceeded() {
+				subCancel()
+			}
+		}(subR)
+	}
+	wg.Wait()
+
+	level.Info(s.logger).Log(
+		"msg", "sharded search tags request stats",
+		"path", r.URL.Path,
+		"duration_seconds", time.Since(reqStart),
+		"total_blocks", len(blocks),
+		"total_requests", len(reqs),
+		"exceeded", combiner.exceeded())
+
+	if overallErr != nil {
+		return nil, overallErr
+	}
+
+	if statusCode != http.StatusOK {
+		// translate all non-200s into 500s. if, for instance, we get a 400 back from an internal component
+		// it means that we created a bad request. 400 should not be propagated back to the user b/c
+		// the bad request was due to a bug on our side, so return 500 instead.
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(statusMsg)),
+		}, nil
+	}
+
+	if combiner.exceeded() {
+		level.Warn(s.logger).Log("msg", "size of tags or tag values exceeded limit, reduce cardinality or size of tags", "tenant", tenantID, "path", r.URL.Path, "limit", s.overrides.MaxBytesPerTagValuesQuery(tenantID))
+	}
+
+	bodyString, err := (&jsonpb.Marshaler{}).MarshalToString(combiner.response())
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptJSON},
+		},
+		Body:          io.NopCloser(strings.NewReader(bodyString)),
+		ContentLength: int64(len(bodyString)),
+	}, nil
+}
+
+// blocks returns the blocks in the range, newest first and limited to max_blocks_per_tag_values_query
+func (s *searchTagsSharder) blocks(tenantID string, start, end uint32) []*backend.BlockMeta {
+	blocks := s.search.blockMetas(int64(start), int64(end), tenantID)
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].EndTime.After(blocks[j].EndTime)
+	})
+
+	if limit := s.overrides.MaxBlocksPerTagValuesQuery(tenantID); limit > 0 && len(blocks) > limit {
+		blocks = blocks[:limit]
+	}
+
+	return blocks
+}
+
+// blockRequest returns a request that covers all pages of the block. Tags are searched per
+// block so there is no point in splitting it further.
+func blockRequest(m *backend.BlockMeta) *tempopb.SearchBlockRequest {
+	if m.Size == 0 || m.TotalRecords == 0 {
+		return nil
+	}
+
+	return &tempopb.SearchBlockRequest{
+		BlockID:         m.BlockID.String(),
+		StartPage:       0,
+		PagesToSearch:   m.TotalRecords,
+		Encoding:        m.Encoding.String(),
+		IndexPageSize:   m.IndexPageSize,
+		TotalRecords:    m.TotalRecords,
+		DataEncoding:    m.DataEncoding,
+		Version:         m.Version,
+		Size_:           m.Size,
+		FooterSize:      m.FooterSize,
+		CompactionLevel: uint32(m.CompactionLevel),
+	}
+}
+
+// tagsCombiner combines the responses of one of the tags or tag values endpoints
+type tagsCombiner interface {
+	addResponse(body io.Reader) error
+	exceeded() bool
+	response() proto.Message
+}
+
+// newTagsCombiner returns the combiner for the endpoint of the request. The request is validated so
+// bad requests are not sharded.
+func newTagsCombiner(r *http.Request, limit int) (tagsCombiner, error) {
+	v2 := api.IsSearchTagsV2(r)
+
+	if api.IsSearchTagValueStats(r) {
+		if _, err := api.ParseSearchTagValueStatsRequest(r); err != nil {
+			return nil, err
+		}
+
+		n, err := api.ParseSearchTagValueStatsLimit(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return &tagValueStatsCombiner{stats: search.NewTagValueStatsCombiner(limit), limit: n}, nil
+	}
+
+	if api.IsSearchTagValues(r) {
+		var err error
+		if v2 {
+			_, err = api.ParseSearchTagValuesRequestV2(r)
+		} else {
+			_, err = api.ParseSearchTagValuesRequest(r)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if v2 {
+			return &tagValuesV2Combiner{
+				values: util.NewDistinctValueCollector(limit, func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) }),
+			}, nil
+		}
+		return &tagValuesCombiner{values: util.NewDistinctStringCollector(limit)}, nil
+	}
+
+	if _, err := api.ParseSearchTagsRequest(r); err != nil {
+		return nil, err
+	}
+
+	if v2 {
+		return &tagsV2Combiner{limit: limit, scopes: map[string]*util.DistinctStringCollector{}}, nil
+	}
+	return &tagsV1Combiner{tags: util.NewDistinctStringCollector(limit)}, nil
+}
+
+type tagsV1Combiner struct {
+	tags *util.DistinctStringCollector
+}
+
+func (c *tagsV1Combiner) addResponse(body io.Reader) error {
+	resp := &tempopb.SearchTagsResponse{}
+	err := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(body, resp)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range resp.TagNames {
+		c.tags.Collect(t)
+	}
+	return nil
+}
+
+func (c *tagsV1Combiner) exceeded() bool {
+	return c.tags.Exceeded()
+}
+
+func (c *tagsV1Combiner) response() proto.Message {
+	return &tempopb.SearchTagsResponse{
+		TagNames: c.tags.Strings(),
+	}
+}
+
+type tagsV2Combiner struct {
+	limit  int
+	scopes map[string]*util.DistinctStringCollector
+}
+
+func (c *tagsV2Combiner) addResponse(body io.Reader) error {
+	resp := &tempopb.SearchTagsV2Response{}
+	err := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(body, resp)
+	if err != nil {
+		return err
+	}
+
+	for _, scope := range resp.Scopes {
+		dvc := c.scopes[scope.Name]
+		if dvc == nil {
+			dvc = util.NewDistinctStringCollector(c.limit)
+			c.scopes[scope.Name] = dvc
+		}
+
+		for _, t := range scope.Tags {
+			dvc.Collect(t)
+		}
+	}
+	return nil
+}
+
+func (c *tagsV2Combiner) exceeded() bool {
+	for _, dvc := range c.scopes {
+		if dvc.Exceeded() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *tagsV2Combiner) response() proto.Message {
+	resp := &tempopb.SearchTagsV2Response{}
+	for name, dvc := range c.scopes {
+		resp.Scopes = append(resp.Scopes, &tempopb.SearchTagsV2Scope{
+			Name: name,
+			Tags: dvc.Strings(),
+		})
+	}
+
+	// keep the order of the scopes stable
+	sort.Slice(resp.Scopes, func(i, j int) bool {
+		return resp.Scopes[i].Name < resp.Scopes[j].Name
+	})
+
+	return resp
+}
+
+type tagValuesCombiner struct {
+	values *util.DistinctStringCollector
+}
+
+func (c *tagValuesCombiner) addResponse(body io.Reader) error {
+	resp := &tempopb.SearchTagValuesResponse{}
+	err := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(body, resp)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range resp.TagValues {
+		c.values.Collect(v)
+	}
+	return nil
+}
+
+func (c *tagValuesCombiner) exceeded() bool {
+	return c.values.Exceeded()
+}
+
+func (c *tagValuesCombiner) response() proto.Message {
+	return &tempopb.SearchTagValuesResponse{
+		TagValues: c.values.Strings(),
+	}
+}
+
+type tagValuesV2Combiner struct {
+	values *util.DistinctValueCollector[tempopb.TagValue]
+}
+
+func (c *tagValuesV2Combiner) addResponse(body io.Reader) error {
+	resp := &tempopb.SearchTagValuesV2Response{}
+	err := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(body, resp)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range resp.TagValues {
+		c.values.Collect(*v)
+	}
+	return nil
+}
+
+func (c *tagValuesV2Combiner) exceeded() bool {
+	return c.values.Exceeded()
+}
+
+func (c *tagValuesV2Combiner) response() proto.Message {
+	resp := &tempopb.SearchTagValuesV2Response{}
+	for _, v := range c.values.Values() {
+		v2 := v
+		resp.TagValues = append(resp.TagValues, &v2)
+	}
+
+	return resp
+}
+
+type tagValueStatsCombiner struct {
+	stats *search.TagValueStatsCombiner
+	limit int
+}
+
+func (c *tagValueStatsCombiner) addResponse(body io.Reader) error {
+	resp := &tempopb.SearchTagValueStatsResponse{}
+	err := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(body, resp)
+	if err != nil {
+		return err
+	}
+
+	return c.stats.Combine(resp)
+}
+
+// exceeded never stops the search. values over the limit are not counted, but every response is
+// still needed to estimate the cardinality
+func (c *tagValueStatsCombiner) exceeded() bool {
+	return false
+}
+
+func (c *tagValueStatsCombiner) response() proto.Message {
+	resp := c.stats.Response(c.limit)
+	// the sketch is only used to combine responses
+	resp.Sketch = nil
+
+	return resp
+}
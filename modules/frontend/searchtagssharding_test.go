@@ -0,0 +1,296 @@
+package frontend
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSearchTagsSharderRoundTrip(t *testing.T) {
+	now := time.Now()
+	metas := []*backend.BlockMeta{
+		{
+			StartTime:    now.Add(-3 * time.Hour),
+			EndTime:      now.Add(-2 * time.Hour),
+			Size:         1000,
+			TotalRecords: 10,
+			BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+		},
+		{
+			StartTime:    now.Add(-2 * time.Hour),
+			EndTime:      now.Add(-time.Hour),
+			Size:         1000,
+			TotalRecords: 10,
+			BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		},
+		{
+			StartTime:    now.Add(-time.Hour),
+			EndTime:      now.Add(-30 * time.Minute),
+			Size:         1000,
+			TotalRecords: 10,
+			BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+		},
+	}
+
+	// each job returns a value unique to the block or ingester and one shared value
+	tagValues := func(source string) []*tempopb.TagValue {
+		return []*tempopb.TagValue{
+			{Type: "string", Value: source},
+			{Type: "string", Value: "shared"},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		path             string
+		tagName          string
+		params           string
+		limits           overrides.Limits
+		expectedStatus   int
+		expectedReqs     []string
+		expectedResponse proto.Message
+	}{
+		{
+			name:             "no range only searches ingesters",
+			path:             "/api/v2/search/tag/span.foo/values",
+			tagName:          "span.foo",
+			expectedStatus:   http.StatusOK,
+			expectedReqs:     []string{"ingester"},
+			expectedResponse: &tempopb.SearchTagValuesV2Response{TagValues: tagValues("ingester")},
+		},
+		{
+			name:           "range searches ingesters and blocks",
+			path:           "/api/v2/search/tag/span.foo/values",
+			tagName:        "span.foo",
+			params:         "start=" + strconv.Itoa(int(now.Add(-4*time.Hour).Unix())) + "&end=" + strconv.Itoa(int(now.Unix())),
+			expectedStatus: http.StatusOK,
+			expectedReqs: []string{
+				"ingester",
+				"00000000-0000-0000-0000-000000000002",
+				"00000000-0000-0000-0000-000000000001",
+				"00000000-0000-0000-0000-000000000000",
+			},
+			expectedResponse: &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{
+				{Type: "string", Value: "00000000-0000-0000-0000-000000000000"},
+				{Type: "string", Value: "00000000-0000-0000-0000-000000000001"},
+				{Type: "string", Value: "00000000-0000-0000-0000-000000000002"},
+				{Type: "string", Value: "ingester"},
+				{Type: "string", Value: "shared"},
+			}},
+		},
+		{
+			name:           "old range skips ingesters",
+			path:           "/api/v2/search/tag/span.foo/values",
+			tagName:        "span.foo",
+			params:         "start=" + strconv.Itoa(int(now.Add(-4*time.Hour).Unix())) + "&end=" + strconv.Itoa(int(now.Add(-150*time.Minute).Unix())),
+			expectedStatus: http.StatusOK,
+			expectedReqs:   []string{"00000000-0000-0000-0000-000000000000"},
+			expectedResponse: &tempopb.SearchTagValuesV2Response{TagValues: []*tempopb.TagValue{
+				{Type: "string", Value: "00000000-0000-0000-0000-000000000000"},
+				{Type: "string", Value: "shared"},
+			}},
+		},
+		{
+			name:           "max blocks keeps the newest blocks",
+			path:           "/api/search/tag/foo/values",
+			tagName:        "foo",
+			params:         "start=" + strconv.Itoa(int(now.Add(-4*time.Hour).Unix())) + "&end=" + strconv.Itoa(int(now.Unix())),
+			limits:         overrides.Limits{MaxBlocksPerTagValuesQuery: 1},
+			expectedStatus: http.StatusOK,
+			expectedReqs: []string{
+				"ingester",
+				"00000000-0000-0000-0000-000000000002",
+			},
+			expectedResponse: &tempopb.SearchTagValuesResponse{TagValues: []string{
+				"00000000-0000-0000-0000-000000000002",
+				"ingester",
+				"shared",
+			}},
+		},
+		{
+			name:           "max bytes stops early",
+			path:           "/api/search/tags",
+			params:         "start=" + strconv.Itoa(int(now.Add(-4*time.Hour).Unix())) + "&end=" + strconv.Itoa(int(now.Unix())),
+			limits:         overrides.Limits{MaxBytesPerTagValuesQuery: 10},
+			expectedStatus: http.StatusOK,
+			expectedReqs:   []string{"ingester"},
+			expectedResponse: &tempopb.SearchTagsResponse{TagNames: []string{
+				"ingester",
+			}},
+		},
+		{
+			name:           "tags v2 combines scopes",
+			path:           "/api/v2/search/tags",
+			params:         "start=" + strconv.Itoa(int(now.Add(-4*time.Hour).Unix())) + "&end=" + strconv.Itoa(int(now.Add(-150*time.Minute).Unix())),
+			expectedStatus: http.StatusOK,
+			expectedReqs:   []string{"00000000-0000-0000-0000-000000000000"},
+			expectedResponse: &tempopb.SearchTagsV2Response{Scopes: []*tempopb.SearchTagsV2Scope{
+				{Name: "resource", Tags: []string{"shared"}},
+				{Name: "span", Tags: []string{"00000000-0000-0000-0000-000000000000", "shared"}},
+			}},
+		},
+		{
+			name:           "stats without range are combined",
+			path:           "/api/v2/search/tag/span.foo/stats",
+			tagName:        "span.foo",
+			expectedStatus: http.StatusOK,
+			expectedReqs:   []string{"ingester"},
+			expectedResponse: &tempopb.SearchTagValueStatsResponse{
+				Values: []*tempopb.TagValueCount{
+					{Type: "string", Value: "shared", Count: 2},
+					{Type: "string", Value: "ingester", Count: 1},
+				},
+				Cardinality: 2,
+			},
+		},
+		{
+			name:           "stats sum counts and keep the top values",
+			path:           "/api/v2/search/tag/span.foo/stats",
+			tagName:        "span.foo",
+			params:         "limit=2&start=" + strconv.Itoa(int(now.Add(-4*time.Hour).Unix())) + "&end=" + strconv.Itoa(int(now.Unix())),
+			expectedStatus: http.StatusOK,
+			expectedReqs: []string{
+				"ingester",
+				"00000000-0000-0000-0000-000000000002",
+				"00000000-0000-0000-0000-000000000001",
+				"00000000-0000-0000-0000-000000000000",
+			},
+			expectedResponse: &tempopb.SearchTagValueStatsResponse{
+				Values: []*tempopb.TagValueCount{
+					{Type: "string", Value: "shared", Count: 8},
+					{Type: "string", Value: "00000000-0000-0000-0000-000000000000", Count: 1},
+				},
+				Cardinality: 5,
+			},
+		},
+		{
+			name:           "invalid range",
+			path:           "/api/search/tags",
+			params:         "start=20&end=10",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid scope",
+			path:           "/api/search/tags",
+			params:         "scope=blerg&start=10&end=20",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mtx := sync.Mutex{}
+			actualReqs := []string{}
+			next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				source := r.URL.Query().Get("blockID")
+				if source == "" {
+					source = "ingester"
+				}
+				mtx.Lock()
+				actualReqs = append(actualReqs, source)
+				mtx.Unlock()
+
+				require.True(t, strings.HasPrefix(r.RequestURI, "/querier"+tc.path))
+
+				var resp proto.Message
+				switch tc.expectedResponse.(type) {
+				case *tempopb.SearchTagsResponse:
+					resp = &tempopb.SearchTagsResponse{TagNames: []string{source, "shared"}}
+				case *tempopb.SearchTagsV2Response:
+					resp = &tempopb.SearchTagsV2Response{Scopes: []*tempopb.SearchTagsV2Scope{
+						{Name: "span", Tags: []string{source, "shared"}},
+						{Name: "resource", Tags: []string{"shared"}},
+					}}
+				case *tempopb.SearchTagValuesResponse:
+					resp = &tempopb.SearchTagValuesResponse{TagValues: []string{source, "shared"}}
+				case *tempopb.SearchTagValuesV2Response:
+					resp = &tempopb.SearchTagValuesV2Response{TagValues: tagValues(source)}
+				case *tempopb.SearchTagValueStatsResponse:
+					resp = &tempopb.SearchTagValueStatsResponse{
+						Values: []*tempopb.TagValueCount{
+							{Type: "string", Value: source, Count: 1},
+							{Type: "string", Value: "shared", Count: 2},
+						},
+						Cardinality: 2,
+					}
+				}
+
+				body, err := (&jsonpb.Marshaler{}).MarshalToString(resp)
+				require.NoError(t, err)
+
+				return &http.Response{
+					Body:       io.NopCloser(strings.NewReader(body)),
+					StatusCode: http.StatusOK,
+				}, nil
+			})
+
+			o, err := overrides.NewOverrides(tc.limits)
+			require.NoError(t, err)
+
+			sharder := newSearchTagsSharder(&mockReader{
+				metas: metas,
+			}, o, SearchSharderConfig{
+				ConcurrentRequests:  1, // 1 concurrent request to force order
+				QueryBackendAfter:   15 * time.Minute,
+				QueryIngestersUntil: 2 * time.Hour,
+			}, log.NewNopLogger())
+			testRT := NewRoundTripper(next, sharder)
+
+			uri := tc.path
+			if tc.params != "" {
+				uri += "?" + tc.params
+			}
+			req := httptest.NewRequest("GET", uri, nil)
+			if tc.tagName != "" {
+				req = mux.SetURLVars(req, map[string]string{"tagName": tc.tagName})
+			}
+			req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+			resp, err := testRT.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedStatus, resp.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			require.Equal(t, tc.expectedReqs, actualReqs)
+
+			actualResp := proto.Clone(tc.expectedResponse)
+			actualResp.Reset()
+			err = (&jsonpb.Unmarshaler{}).Unmarshal(resp.Body, actualResp)
+			require.NoError(t, err)
+
+			// distinct values are unordered
+			switch r := actualResp.(type) {
+			case *tempopb.SearchTagsResponse:
+				sort.Strings(r.TagNames)
+			case *tempopb.SearchTagsV2Response:
+				for _, s := range r.Scopes {
+					sort.Strings(s.Tags)
+				}
+			case *tempopb.SearchTagValuesResponse:
+				sort.Strings(r.TagValues)
+			case *tempopb.SearchTagValuesV2Response:
+				sort.Slice(r.TagValues, func(i, j int) bool { return r.TagValues[i].Value < r.TagValues[j].Value })
+			}
+			require.Equal(t, tc.expectedResponse, actualResp)
+		})
+	}
+}
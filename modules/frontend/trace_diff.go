@@ -0,0 +1,80 @@
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// newTraceDiffHandler returns a round tripper that loads two traces through the trace by id round tripper
+// and responds with their differences.
+func newTraceDiffHandler(traces http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		span, ctx := opentracing.StartSpanFromContext(r.Context(), "frontend.TraceDiff")
+		defer span.Finish()
+		r = r.WithContext(ctx)
+
+		vars := mux.Vars(r)
+		traceIDs := []string{vars[api.URLParamTraceID], vars[api.URLParamCompareTraceID]}
+		for _, id := range traceIDs {
+			if _, err := util.HexStringToTraceID(id); err != nil {
+				return badRequest(err.Error()), nil
+			}
+		}
+
+		// load both traces at once
+		wg := sync.WaitGroup{}
+		results := make([]*tempopb.Trace, len(traceIDs))
+		responses := make([]*http.Response, len(traceIDs))
+		errs := make([]error, len(traceIDs))
+		for i, id := range traceIDs {
+			wg.Add(1)
+			go func(i int, id string) {
+				defer wg.Done()
+				results[i], responses[i], errs[i] = fetchTrace(traces, r, id)
+			}(i, id)
+		}
+		wg.Wait()
+
+		for i := range traceIDs {
+			if errs[i] != nil || responses[i] != nil {
+				// only the failed response is passed on, the other one is dropped
+				for j, resp := range responses {
+					if j != i && resp != nil {
+						resp.Body.Close()
+					}
+				}
+				return responses[i], errs[i]
+			}
+		}
+
+		diff := trace.Diff(results[0], results[1])
+		span.SetTag("added", len(diff.Added))
+		span.SetTag("missing", len(diff.Missing))
+		span.SetTag("changed", len(diff.Changed))
+
+		body, err := json.Marshal(diff)
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				api.HeaderContentType: {api.HeaderAcceptJSON},
+			},
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+		}, nil
+	})
+}
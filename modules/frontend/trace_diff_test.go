@@ -0,0 +1,108 @@
+package frontend
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestTraceDiffHandler(t *testing.T) {
+	baseID, compareID := []byte{0x0a}, []byte{0x0b}
+
+	// the compared trace is slower in db and calls the queue instead of the cache
+	traces := map[string]*tempopb.Trace{
+		"0a": makeFakeTrace(baseID,
+			makeFakeSpan(baseID, 0x01, 0x00, "GET", 0, 100),
+			makeFakeSpan(baseID, 0x02, 0x01, "db", 10, 30),
+			makeFakeSpan(baseID, 0x03, 0x01, "cache", 40, 50),
+		),
+		"0b": makeFakeTrace(compareID,
+			makeFakeSpan(compareID, 0x11, 0x00, "GET", 0, 150),
+			makeFakeSpan(compareID, 0x12, 0x11, "db", 10, 90),
+			makeFakeSpan(compareID, 0x13, 0x11, "queue", 100, 110),
+		),
+	}
+	rt := newTraceDiffHandler(newTraceByIDFake(t, "", traces))
+
+	request := func(traceID, compareTraceID string) *http.Response {
+		return roundTripVars(t, rt, "/api/traces/"+traceID+"/diff/"+compareTraceID, map[string]string{
+			api.URLParamTraceID:        traceID,
+			api.URLParamCompareTraceID: compareTraceID,
+		})
+	}
+
+	resp := request("0a", "0b")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	actual := &trace.TraceDiff{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(actual))
+	require.Equal(t, int64(50), actual.DurationDeltaNanos)
+
+	require.Len(t, actual.Added, 1)
+	require.Equal(t, "0000000000000013", actual.Added[0].SpanID)
+	require.Len(t, actual.Missing, 1)
+	require.Equal(t, "0000000000000003", actual.Missing[0].SpanID)
+
+	// largest duration delta first
+	require.Len(t, actual.Changed, 2)
+	require.Equal(t, "0000000000000002", actual.Changed[0].BaseSpanID)
+	require.Equal(t, "0000000000000012", actual.Changed[0].CompareSpanID)
+	require.Equal(t, int64(60), actual.Changed[0].DurationDeltaNanos)
+	require.Equal(t, "0000000000000001", actual.Changed[1].BaseSpanID)
+	require.Equal(t, "0000000000000011", actual.Changed[1].CompareSpanID)
+	require.Equal(t, int64(50), actual.Changed[1].DurationDeltaNanos)
+
+	// errors of the trace by id requests are passed on
+	resp = request("0a", "0c")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = request("0a", "zz")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+type closeTrackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeTrackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestTraceDiffHandlerClosesDroppedResponses(t *testing.T) {
+	var mtx sync.Mutex
+	bodies := map[string]*closeTrackingBody{}
+
+	// neither trace exists, only one of the responses is passed on
+	rt := newTraceDiffHandler(RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		body := &closeTrackingBody{Reader: strings.NewReader("trace not found")}
+		mtx.Lock()
+		bodies[mux.Vars(r)[api.URLParamTraceID]] = body
+		mtx.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       body,
+		}, nil
+	}))
+
+	resp := roundTripVars(t, rt, "/api/traces/0a/diff/0b", map[string]string{
+		api.URLParamTraceID:        "0a",
+		api.URLParamCompareTraceID: "0b",
+	})
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	require.Len(t, bodies, 2)
+	for _, b := range bodies {
+		require.Equal(t, b != resp.Body, b.closed)
+	}
+}
@@ -0,0 +1,215 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	protoio "github.com/gogo/protobuf/io"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+var errTraceStreamEnded = errors.New("trace stream ended before its last message")
+
+type streamingTraceByIDHandler func(req *tempopb.TraceByIDStreamRequest, srv tempopb.StreamingQuerier_TraceByIDServer) error
+
+// newTraceByIDStreamingHandler returns a handler that streams the batches of a trace as the queriers find
+// them. Every shard of the trace by id request is streamed by a querier as its ingesters respond and its
+// blocks are read, spans that were already sent by another shard are dropped. The last message contains
+// the metrics and the partial status of the trace.
+func newTraceByIDStreamingHandler(cfg Config, downstream http.RoundTripper, apiPrefix string, logger log.Logger) streamingTraceByIDHandler {
+	sharder := shardQuery{
+		next:            downstream,
+		cfg:             &cfg.TraceByID,
+		logger:          logger,
+		blockBoundaries: createBlockBoundaries(cfg.TraceByID.QueryShards - 1), // one shard will be used to query ingesters
+	}
+
+	return func(req *tempopb.TraceByIDStreamRequest, srv tempopb.StreamingQuerier_TraceByIDServer) error {
+		ctx := srv.Context()
+
+		if len(req.TraceID) == 0 {
+			return status.Error(codes.InvalidArgument, "please provide a traceID")
+		}
+		if req.End < req.Start {
+			return status.Error(codes.InvalidArgument, "end must be after start")
+		}
+
+		// sub context to cancel in-progress shards once the stream failed
+		subCtx, subCancel := context.WithCancel(ctx)
+		defer subCancel()
+
+		downstreamPath := path.Join(apiPrefix, strings.Replace(api.PathTraceStream, "{"+api.URLParamTraceID+"}", util.TraceIDToHexString(req.TraceID), 1))
+		params := url.Values{}
+		if req.Start != 0 || req.End != 0 {
+			params.Set("start", strconv.FormatUint(uint64(req.Start), 10))
+			params.Set("end", strconv.FormatUint(uint64(req.End), 10))
+		}
+		httpReq := (&http.Request{
+			URL: &url.URL{
+				Path:     downstreamPath,
+				RawQuery: params.Encode(),
+			},
+			Header: http.Header{},
+			Body:   io.NopCloser(bytes.NewReader([]byte{})),
+		}).WithContext(subCtx)
+		httpReq.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+
+		reqs, err := sharder.buildShardedRequests(httpReq)
+		if err != nil {
+			return err
+		}
+
+		concurrentShards := uint(cfg.TraceByID.QueryShards)
+		if cfg.TraceByID.ConcurrentShards > 0 {
+			concurrentShards = uint(cfg.TraceByID.ConcurrentShards)
+		}
+		wg := boundedwaitgroup.New(concurrentShards)
+		mtx := sync.Mutex{}
+
+		var overallError error
+		deduper := trace.NewSpanDeduper()
+		found := false
+		failedJobs := 0
+		partialMsg := ""
+
+		// fail stops the request, in-progress shards are cancelled
+		fail := func(r *http.Request, err error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			if overallError != nil || ctx.Err() != nil {
+				return
+			}
+			_ = level.Error(logger).Log("msg", "trace by id streaming: error querying proxy target", "url", r.RequestURI, "err", err)
+			overallError = err
+			subCancel()
+		}
+		failShard := func(msg string) {
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			failedJobs++
+			partialMsg = msg
+		}
+		// send sends the spans of a part that weren't sent yet and returns false once the request failed
+		send := func(part *tempopb.Trace) bool {
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			if overallError != nil || ctx.Err() != nil {
+				return false
+			}
+			if deduper.Dedupe(part) == 0 {
+				return true
+			}
+			found = true
+
+			if err := srv.Send(&tempopb.TraceByIDResponse{Trace: part}); err != nil {
+				_ = level.Error(logger).Log("msg", "trace by id streaming: send failed", "err", err)
+				overallError = fmt.Errorf("trace by id streaming send failed: %w", err)
+				subCancel()
+				return false
+			}
+			return true
+		}
+
+		for _, r := range reqs {
+			wg.Add(1)
+			go func(innerR *http.Request) {
+				defer wg.Done()
+
+				resp, err := downstream.RoundTrip(innerR)
+				if err != nil {
+					fail(innerR, err)
+					return
+				}
+				defer resp.Body.Close()
+
+				if resp.StatusCode == http.StatusNotFound {
+					return
+				}
+				// failed shards make the trace partial
+				if resp.StatusCode != http.StatusOK {
+					buff, _ := io.ReadAll(resp.Body)
+					failShard(string(buff))
+					return
+				}
+
+				// messages are bounded by the grpc messages that carry the stream from the querier
+				reader := protoio.NewDelimitedReader(resp.Body, math.MaxInt32)
+				for {
+					msg := &tempopb.TraceByIDResponse{}
+					if err := reader.ReadMsg(msg); err != nil {
+						// the stream of a shard ends with its metrics or a partial status, a stream without
+						// them was cut short
+						if errors.Is(err, io.EOF) {
+							err = errTraceStreamEnded
+						}
+						failShard(err.Error())
+						return
+					}
+
+					switch {
+					case msg.Status == tempopb.PartialStatus_PARTIAL:
+						failShard(msg.Message)
+						return
+					case msg.Metrics != nil:
+						return
+					}
+
+					if !send(msg.Trace) {
+						return
+					}
+				}
+			}(r)
+		}
+		wg.Wait()
+
+		if overallError != nil {
+			return overallError
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if !found {
+			if failedJobs > 0 {
+				// nothing was found, but the trace may be in the failed shards
+				return status.Error(codes.Internal, partialMsg)
+			}
+			return status.Error(codes.NotFound, "trace not found")
+		}
+
+		final := &tempopb.TraceByIDResponse{
+			Metrics: &tempopb.TraceByIDMetrics{
+				TotalJobs:  uint32(len(reqs)),
+				FailedJobs: uint32(failedJobs),
+			},
+		}
+		if failedJobs > 0 {
+			final.Status = tempopb.PartialStatus_PARTIAL
+			final.Message = partialMsg
+		}
+
+		return srv.Send(final)
+	}
+}
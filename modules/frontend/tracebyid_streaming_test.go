@@ -0,0 +1,267 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	protoio "github.com/gogo/protobuf/io"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+type mockTraceByIDStreamingServer struct {
+	ctx       context.Context
+	mtx       sync.Mutex
+	responses []*tempopb.TraceByIDResponse
+}
+
+func (m *mockTraceByIDStreamingServer) Send(r *tempopb.TraceByIDResponse) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.responses = append(m.responses, proto.Clone(r).(*tempopb.TraceByIDResponse))
+	return nil
+}
+func (m *mockTraceByIDStreamingServer) Context() context.Context     { return m.ctx }
+func (m *mockTraceByIDStreamingServer) SendHeader(metadata.MD) error { return nil }
+func (m *mockTraceByIDStreamingServer) SetHeader(metadata.MD) error  { return nil }
+func (m *mockTraceByIDStreamingServer) SendMsg(interface{}) error    { return nil }
+func (m *mockTraceByIDStreamingServer) RecvMsg(interface{}) error    { return nil }
+func (m *mockTraceByIDStreamingServer) SetTrailer(metadata.MD)       {}
+
+// traceStream returns the body of a trace streamed by a querier
+func traceStream(t *testing.T, msgs ...*tempopb.TraceByIDResponse) []byte {
+	buff := &bytes.Buffer{}
+	w := protoio.NewDelimitedWriter(buff)
+	for _, msg := range msgs {
+		require.NoError(t, w.WriteMsg(msg))
+	}
+	return buff.Bytes()
+}
+
+func TestTraceByIDStreamingHandler(t *testing.T) {
+	// every block shard finds the whole trace in two parts, the ingesters find a part of it
+	fullTrace := test.MakeTrace(10, []byte{0x0a})
+	ingesterTrace := &tempopb.Trace{Batches: fullTrace.Batches[:5]}
+
+	spanCount := 0
+	for _, b := range fullTrace.Batches {
+		for _, ss := range b.ScopeSpans {
+			spanCount += len(ss.Spans)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		notFound       bool
+		failingShard   func() *http.Response
+		expectedCode   codes.Code
+		expectedStatus tempopb.PartialStatus
+		expectedFailed uint32
+	}{
+		{
+			name:           "trace is streamed",
+			expectedStatus: tempopb.PartialStatus_COMPLETE,
+		},
+		{
+			name: "failed shards make the trace partial",
+			failingShard: func() *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       io.NopCloser(strings.NewReader("shard failed")),
+				}
+			},
+			expectedStatus: tempopb.PartialStatus_PARTIAL,
+			expectedFailed: 1,
+		},
+		{
+			name: "shards that fail while streaming make the trace partial",
+			failingShard: func() *http.Response {
+				body := traceStream(t,
+					&tempopb.TraceByIDResponse{Trace: &tempopb.Trace{Batches: fullTrace.Batches[:2]}},
+					&tempopb.TraceByIDResponse{Status: tempopb.PartialStatus_PARTIAL, Message: "shard failed"},
+				)
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}
+			},
+			expectedStatus: tempopb.PartialStatus_PARTIAL,
+			expectedFailed: 1,
+		},
+		{
+			name: "shard streams without their last message make the trace partial",
+			failingShard: func() *http.Response {
+				body := traceStream(t, &tempopb.TraceByIDResponse{Trace: &tempopb.Trace{Batches: fullTrace.Batches[:2]}})
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}
+			},
+			expectedStatus: tempopb.PartialStatus_PARTIAL,
+			expectedFailed: 1,
+		},
+		{
+			name:         "trace not found",
+			notFound:     true,
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				uri, err := url.Parse(r.RequestURI)
+				require.NoError(t, err)
+				require.Equal(t, "/querier/tempo/api/traces/a/stream", uri.Path)
+				require.Equal(t, "10", uri.Query().Get("start"))
+				require.Equal(t, api.HeaderAcceptProtobuf, r.Header.Get(api.HeaderAccept))
+
+				if tc.notFound {
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       io.NopCloser(strings.NewReader("trace not found")),
+					}, nil
+				}
+
+				var parts []*tempopb.Trace
+				switch {
+				case uri.Query().Get(api.QueryModeKey) == api.QueryModeIngesters:
+					parts = append(parts, ingesterTrace)
+				case tc.failingShard != nil && uri.Query().Get(api.BlockStartKey) == "00000000000000000000000000000000":
+					return tc.failingShard(), nil
+				default:
+					parts = append(parts, &tempopb.Trace{Batches: fullTrace.Batches[:5]}, &tempopb.Trace{Batches: fullTrace.Batches[5:]})
+				}
+
+				var msgs []*tempopb.TraceByIDResponse
+				for _, p := range parts {
+					msgs = append(msgs, &tempopb.TraceByIDResponse{Trace: proto.Clone(p).(*tempopb.Trace)})
+				}
+				msgs = append(msgs, &tempopb.TraceByIDResponse{Metrics: &tempopb.TraceByIDMetrics{}})
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader(traceStream(t, msgs...))),
+				}, nil
+			})
+
+			handler := newTraceByIDStreamingHandler(Config{
+				TraceByID: TraceByIDConfig{
+					QueryShards: 4,
+				},
+			}, next, "/tempo", log.NewNopLogger())
+
+			srv := &mockTraceByIDStreamingServer{ctx: user.InjectOrgID(context.Background(), "blerg")}
+			err := handler(&tempopb.TraceByIDStreamRequest{
+				TraceID: []byte{0x0a},
+				Start:   10,
+				End:     20,
+			}, srv)
+			if tc.expectedCode != codes.OK {
+				require.Equal(t, tc.expectedCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+
+			// every span is sent once
+			require.Greater(t, len(srv.responses), 1)
+			seen := map[string]struct{}{}
+			for _, resp := range srv.responses[:len(srv.responses)-1] {
+				require.Nil(t, resp.Metrics)
+				for _, b := range resp.Trace.Batches {
+					for _, ss := range b.ScopeSpans {
+						for _, s := range ss.Spans {
+							_, ok := seen[string(s.SpanId)]
+							require.False(t, ok)
+							seen[string(s.SpanId)] = struct{}{}
+						}
+					}
+				}
+			}
+			require.Len(t, seen, spanCount)
+
+			final := srv.responses[len(srv.responses)-1]
+			require.Nil(t, final.Trace)
+			require.Equal(t, tc.expectedStatus, final.Status)
+			require.Equal(t, &tempopb.TraceByIDMetrics{TotalJobs: 4, FailedJobs: tc.expectedFailed}, final.Metrics)
+		})
+	}
+}
+
+func TestTraceByIDStreamingHandlerCancelsShardsOnError(t *testing.T) {
+	var (
+		mtx       sync.Mutex
+		bodies    []*closeTrackingBody
+		cancelled int
+	)
+
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		uri, err := url.Parse(r.RequestURI)
+		require.NoError(t, err)
+
+		switch {
+		case uri.Query().Get(api.QueryModeKey) == api.QueryModeIngesters:
+			body := traceStream(t,
+				&tempopb.TraceByIDResponse{Trace: test.MakeTrace(2, []byte{0x0a})},
+				&tempopb.TraceByIDResponse{Metrics: &tempopb.TraceByIDMetrics{}},
+			)
+			b := &closeTrackingBody{Reader: bytes.NewReader(body)}
+			mtx.Lock()
+			bodies = append(bodies, b)
+			mtx.Unlock()
+			return &http.Response{StatusCode: http.StatusOK, Body: b}, nil
+		case uri.Query().Get(api.BlockStartKey) == "00000000000000000000000000000000":
+			return nil, errors.New("shard failed")
+		}
+
+		// the remaining shards only return once they are cancelled
+		select {
+		case <-r.Context().Done():
+			mtx.Lock()
+			cancelled++
+			mtx.Unlock()
+			return nil, r.Context().Err()
+		case <-time.After(5 * time.Second):
+			return nil, errors.New("shard was not cancelled")
+		}
+	})
+
+	handler := newTraceByIDStreamingHandler(Config{
+		TraceByID: TraceByIDConfig{
+			QueryShards: 4,
+		},
+	}, next, "/tempo", log.NewNopLogger())
+
+	srv := &mockTraceByIDStreamingServer{ctx: user.InjectOrgID(context.Background(), "blerg")}
+	err := handler(&tempopb.TraceByIDStreamRequest{TraceID: []byte{0x0a}}, srv)
+	require.EqualError(t, err, "shard failed")
+
+	require.Equal(t, 2, cancelled)
+	require.Len(t, bodies, 1)
+	require.True(t, bodies[0].closed)
+}
+
+func TestTraceByIDStreamingHandlerInvalidRequest(t *testing.T) {
+	handler := newTraceByIDStreamingHandler(Config{
+		TraceByID: TraceByIDConfig{
+			QueryShards: 2,
+		},
+	}, nil, "", log.NewNopLogger())
+	srv := &mockTraceByIDStreamingServer{ctx: user.InjectOrgID(context.Background(), "blerg")}
+
+	err := handler(&tempopb.TraceByIDStreamRequest{}, srv)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = handler(&tempopb.TraceByIDStreamRequest{TraceID: []byte{0x0a}, Start: 20, End: 10}, srv)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
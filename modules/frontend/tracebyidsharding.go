@@ -84,6 +84,11 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 	statusCode := http.StatusNotFound
 	statusMsg := "trace not found"
 
+	// the v2 endpoint returns the parts of the trace that were found if some shards fail
+	allowPartial := api.IsTraceByIDV2(r)
+	failedJobs := 0
+	partialMsg := ""
+
 	for _, req := range reqs {
 		wg.Add(1)
 		go func(innerR *http.Request) {
@@ -111,11 +116,16 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 			// if the status code is anything but happy, save the error and pass it down the line
 			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
 				// todo: if we cancel the parent context here will it shortcircuit the other queries and fail fast?
-				statusCode = resp.StatusCode
 				bytesMsg, err := io.ReadAll(resp.Body)
 				if err != nil {
 					_ = level.Error(s.logger).Log("msg", "error reading response body status != ok", "url", innerR.RequestURI, "err", err)
 				}
+				if allowPartial {
+					failedJobs++
+					partialMsg = string(bytesMsg)
+					return
+				}
+				statusCode = resp.StatusCode
 				statusMsg = string(bytesMsg)
 				return
 			}
@@ -158,6 +168,11 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 	}
 
 	overallTrace, _ := combiner.Result()
+	if failedJobs > 0 && (overallTrace == nil || statusCode != http.StatusOK) {
+		// nothing was found, but the trace may be in the failed shards
+		statusCode = http.StatusInternalServerError
+		statusMsg = partialMsg
+	}
 	if overallTrace == nil || statusCode != http.StatusOK {
 		// translate non-404s into 500s. if, for instance, we get a 400 back from an internal component
 		// it means that we created a bad request. 400 should not be propagated back to the user b/c
@@ -173,10 +188,19 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 		}, nil
 	}
 
-	buff, err := proto.Marshal(&tempopb.TraceByIDResponse{
-		Trace:   overallTrace,
-		Metrics: &tempopb.TraceByIDMetrics{},
-	})
+	traceResp := &tempopb.TraceByIDResponse{
+		Trace: overallTrace,
+		Metrics: &tempopb.TraceByIDMetrics{
+			TotalJobs:  uint32(len(reqs)),
+			FailedJobs: uint32(failedJobs),
+		},
+	}
+	if failedJobs > 0 {
+		traceResp.Status = tempopb.PartialStatus_PARTIAL
+		traceResp.Message = partialMsg
+	}
+
+	buff, err := proto.Marshal(traceResp)
 	if err != nil {
 		_ = level.Error(s.logger).Log("msg", "error marshalling response to proto", "err", err)
 		return nil, err
@@ -376,3 +376,103 @@ func TestConcurrentShards(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, sawMaxConcurrncy.Load())
 }
+
+func TestShardingWarePartialTrace(t *testing.T) {
+	trace1 := test.MakeTrace(10, []byte{0x01, 0x02})
+
+	tests := []struct {
+		name           string
+		status1        int
+		status2        int
+		expectedStatus int
+		expectedResp   *tempopb.TraceByIDResponse
+	}{
+		{
+			name:           "200+500",
+			status1:        200,
+			status2:        500,
+			expectedStatus: 200,
+			expectedResp: &tempopb.TraceByIDResponse{
+				Trace:   trace1,
+				Metrics: &tempopb.TraceByIDMetrics{TotalJobs: 2, FailedJobs: 1},
+				Status:  tempopb.PartialStatus_PARTIAL,
+				Message: "error occurred",
+			},
+		},
+		{
+			name:           "200+404",
+			status1:        200,
+			status2:        404,
+			expectedStatus: 200,
+			expectedResp: &tempopb.TraceByIDResponse{
+				Trace:   trace1,
+				Metrics: &tempopb.TraceByIDMetrics{TotalJobs: 2},
+			},
+		},
+		{
+			name:           "404+500",
+			status1:        404,
+			status2:        500,
+			expectedStatus: 500,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sharder := newTraceByIDSharder(&TraceByIDConfig{
+				QueryShards: 2,
+				SLO:         testSLOcfg,
+			}, log.NewNopLogger())
+
+			next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				statusCode := tc.status2
+				if r.RequestURI == "/querier/api/v2/traces/1234?mode=ingesters" {
+					statusCode = tc.status1
+				}
+
+				resBytes := []byte("error occurred")
+				switch statusCode {
+				case 200:
+					var err error
+					resBytes, err = proto.Marshal(&tempopb.TraceByIDResponse{
+						Trace:   proto.Clone(trace1).(*tempopb.Trace),
+						Metrics: &tempopb.TraceByIDMetrics{},
+					})
+					require.NoError(t, err)
+				case 404:
+					var err error
+					resBytes, err = proto.Marshal(&tempopb.TraceByIDResponse{
+						Metrics: &tempopb.TraceByIDMetrics{},
+					})
+					require.NoError(t, err)
+				}
+
+				return &http.Response{
+					Body:       io.NopCloser(bytes.NewReader(resBytes)),
+					StatusCode: statusCode,
+				}, nil
+			})
+
+			testRT := NewRoundTripper(next, sharder)
+
+			req := httptest.NewRequest("GET", "/api/v2/traces/1234", nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+			resp, err := testRT.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedStatus, resp.StatusCode)
+			if tc.expectedResp == nil {
+				return
+			}
+
+			bytesResp, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			actualResp := &tempopb.TraceByIDResponse{}
+			require.NoError(t, proto.Unmarshal(bytesResp, actualResp))
+
+			trace.SortTrace(tc.expectedResp.Trace)
+			trace.SortTrace(actualResp.Trace)
+			require.True(t, proto.Equal(tc.expectedResp, actualResp))
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package frontend
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/golang/protobuf/proto" //nolint:all //deprecated
+	"github.com/opentracing/opentracing-go"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// newTracesByIDSharder shards a batch trace by id request like a single trace by id request. Every shard
+// looks up all traces of the batch, so the block id space is only split once for the whole batch.
+func newTracesByIDSharder(cfg *TraceByIDConfig, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return shardBatchQuery{
+			shardQuery: shardQuery{
+				next:            next,
+				cfg:             cfg,
+				logger:          logger,
+				blockBoundaries: createBlockBoundaries(cfg.QueryShards - 1), // one shard will be used to query ingesters
+			},
+		}
+	})
+}
+
+type shardBatchQuery struct {
+	shardQuery
+}
+
+// RoundTrip implements http.RoundTripper
+func (s shardBatchQuery) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+	span, ctx := opentracing.StartSpanFromContext(ctx, "frontend.ShardBatchQuery")
+	defer span.Finish()
+
+	if _, err := user.ExtractOrgID(ctx); err != nil {
+		return badRequest(err.Error()), nil
+	}
+
+	tracesReq, err := api.ParseTracesByIDRequest(r)
+	if err != nil {
+		return badRequest(err.Error()), nil
+	}
+	span.SetTag("traceIDs", len(tracesReq.TraceIDs))
+
+	if s.cfg.MaxTraceIDsPerBatch > 0 && len(tracesReq.TraceIDs) > s.cfg.MaxTraceIDsPerBatch {
+		return badRequest(fmt.Sprintf("too many trace ids, max is %d. received %d", s.cfg.MaxTraceIDsPerBatch, len(tracesReq.TraceIDs))), nil
+	}
+
+	reqStart := time.Now()
+
+	// context propagation
+	r = r.WithContext(ctx)
+	reqs, err := s.buildShardedRequests(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reqs {
+		reqs[i], err = api.BuildTracesByIDRequest(reqs[i], tracesReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// execute requests
+	concurrentShards := uint(s.cfg.QueryShards)
+	if s.cfg.ConcurrentShards > 0 {
+		concurrentShards = uint(s.cfg.ConcurrentShards)
+	}
+	wg := boundedwaitgroup.New(concurrentShards)
+	mtx := sync.Mutex{}
+
+	var overallError error
+	combiners := map[string]*trace.Combiner{}
+	statusCode := http.StatusOK
+	statusMsg := ""
+
+	for _, req := range reqs {
+		wg.Add(1)
+		go func(innerR *http.Request) {
+			defer wg.Done()
+
+			resp, err := s.next.RoundTrip(innerR)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				overallError = err
+			}
+
+			if shouldQuit(r.Context(), statusCode, overallError) {
+				return
+			}
+
+			// check http error
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error querying proxy target", "url", innerR.RequestURI, "err", err)
+				overallError = err
+				return
+			}
+
+			// if the status code is anything but happy, save the error and pass it down the line
+			if resp.StatusCode != http.StatusOK {
+				statusCode = resp.StatusCode
+				bytesMsg, err := io.ReadAll(resp.Body)
+				if err != nil {
+					_ = level.Error(s.logger).Log("msg", "error reading response body status != ok", "url", innerR.RequestURI, "err", err)
+				}
+				statusMsg = string(bytesMsg)
+				return
+			}
+
+			// read the body
+			buff, err := io.ReadAll(resp.Body)
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error reading response body status == ok", "url", innerR.RequestURI, "err", err)
+				overallError = err
+				return
+			}
+
+			tracesResp := &tempopb.TracesByIDResponse{}
+			err = proto.Unmarshal(buff, tracesResp)
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error unmarshalling response", "url", innerR.RequestURI, "err", err)
+				overallError = err
+				return
+			}
+
+			for id, t := range tracesResp.Traces {
+				c, ok := combiners[id]
+				if !ok {
+					c = trace.NewCombiner()
+					combiners[id] = c
+				}
+				c.Consume(t)
+			}
+		}(req)
+	}
+	wg.Wait()
+
+	if overallError != nil {
+		return nil, overallError
+	}
+
+	if statusCode != http.StatusOK {
+		// translate all non-200s into 500s. if, for instance, we get a 400 back from an internal component
+		// it means that we created a bad request. 400 should not be propagated back to the user b/c
+		// the bad request was due to a bug on our side, so return 500 instead.
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       io.NopCloser(strings.NewReader(statusMsg)),
+			Header:     http.Header{},
+		}, nil
+	}
+
+	resp := &tempopb.TracesByIDResponse{
+		Traces: make(map[string]*tempopb.Trace, len(combiners)),
+	}
+	for _, id := range tracesReq.TraceIDs {
+		c, ok := combiners[id]
+		if !ok {
+			resp.NotFound = append(resp.NotFound, id)
+			continue
+		}
+
+		t, _ := c.Result()
+		dedupeSpanIDs(t)
+		resp.Traces[id] = t
+	}
+
+	level.Info(s.logger).Log(
+		"msg", "sharded batch trace by id request stats",
+		"duration_seconds", time.Since(reqStart),
+		"trace_ids", len(tracesReq.TraceIDs),
+		"found", len(resp.Traces))
+
+	buff, err := proto.Marshal(resp)
+	if err != nil {
+		_ = level.Error(s.logger).Log("msg", "error marshalling response to proto", "err", err)
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptProtobuf},
+		},
+		Body:          io.NopCloser(bytes.NewReader(buff)),
+		ContentLength: int64(len(buff)),
+	}, nil
+}
@@ -0,0 +1,227 @@
+package frontend
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestTracesByIDSharder(t *testing.T) {
+	// trace a is split between the ingesters and the blocks, trace b is only in the blocks
+	traceA := test.MakeTrace(10, []byte{0x0a})
+	ingesterA := &tempopb.Trace{}
+	blockA := &tempopb.Trace{}
+	for _, b := range traceA.Batches {
+		if rand.Int()%2 == 0 {
+			ingesterA.Batches = append(ingesterA.Batches, b)
+		} else {
+			blockA.Batches = append(blockA.Batches, b)
+		}
+	}
+	traceB := test.MakeTrace(10, []byte{0x0b})
+
+	// the sharder combines the parts of trace a
+	c := trace.NewCombiner()
+	c.Consume(proto.Clone(ingesterA).(*tempopb.Trace))
+	c.Consume(proto.Clone(blockA).(*tempopb.Trace))
+	traceA, _ = c.Result()
+
+	tests := []struct {
+		name             string
+		body             string
+		maxTraceIDs      int
+		blockStatus      int
+		expectedStatus   int
+		expectedResponse *tempopb.TracesByIDResponse
+	}{
+		{
+			name:           "traces are combined",
+			body:           `{"traceIDs": ["0a", "0b", "0c", "0A", "0000000000000000000000000000000a"]}`,
+			blockStatus:    http.StatusOK,
+			expectedStatus: http.StatusOK,
+			expectedResponse: &tempopb.TracesByIDResponse{
+				Traces: map[string]*tempopb.Trace{
+					"a": traceA,
+					"b": traceB,
+				},
+				NotFound: []string{"c"},
+			},
+		},
+		{
+			name:           "too many ids",
+			body:           `{"traceIDs": ["0a", "0b", "0c"]}`,
+			maxTraceIDs:    2,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid id",
+			body:           `{"traceIDs": ["zz"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no ids",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "upstream error",
+			body:           `{"traceIDs": ["0a"]}`,
+			blockStatus:    http.StatusBadRequest,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mtx := sync.Mutex{}
+			var requested []*tempopb.TracesByIDRequest
+
+			next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				require.True(t, strings.HasPrefix(r.RequestURI, "/querier/api/traces?"))
+
+				req, err := api.ParseTracesByIDRequest(r)
+				require.NoError(t, err)
+
+				mtx.Lock()
+				requested = append(requested, req)
+				mtx.Unlock()
+
+				resp := &tempopb.TracesByIDResponse{Traces: map[string]*tempopb.Trace{}}
+				statusCode := http.StatusOK
+				// the sharded parameters are only part of the request uri
+				uri, err := url.Parse(r.RequestURI)
+				require.NoError(t, err)
+				if uri.Query().Get(api.QueryModeKey) == api.QueryModeIngesters {
+					resp.Traces["a"] = ingesterA
+				} else {
+					statusCode = tc.blockStatus
+					resp.Traces["a"] = blockA
+					resp.Traces["b"] = traceB
+				}
+
+				body, err := proto.Marshal(resp)
+				require.NoError(t, err)
+
+				return &http.Response{
+					Body:       io.NopCloser(bytes.NewReader(body)),
+					StatusCode: statusCode,
+				}, nil
+			})
+
+			sharder := newTracesByIDSharder(&TraceByIDConfig{
+				QueryShards:         2,
+				MaxTraceIDsPerBatch: tc.maxTraceIDs,
+			}, log.NewNopLogger())
+			testRT := NewRoundTripper(next, sharder)
+
+			req := httptest.NewRequest("POST", "/api/traces", strings.NewReader(tc.body))
+			req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+			resp, err := testRT.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedStatus, resp.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			// one request per shard, each with all deduped ids
+			require.Len(t, requested, 2)
+			for _, r := range requested {
+				require.Equal(t, []string{"a", "b", "c"}, r.TraceIDs)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			actual := &tempopb.TracesByIDResponse{}
+			require.NoError(t, proto.Unmarshal(body, actual))
+
+			require.Equal(t, tc.expectedResponse.NotFound, actual.NotFound)
+			require.Len(t, actual.Traces, len(tc.expectedResponse.Traces))
+			for id, expected := range tc.expectedResponse.Traces {
+				trace.SortTrace(expected)
+				trace.SortTrace(actual.Traces[id])
+				require.True(t, proto.Equal(expected, actual.Traces[id]))
+			}
+		})
+	}
+}
+
+func TestTracesByIDSharderDedupesSpanIDs(t *testing.T) {
+	// a zipkin client and server span sharing the span id
+	shared := &tempopb.Trace{
+		Batches: []*v1.ResourceSpans{
+			{
+				ScopeSpans: []*v1.ScopeSpans{
+					{
+						Spans: []*v1.Span{
+							{
+								SpanId: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+								Kind:   v1.Span_SPAN_KIND_CLIENT,
+							},
+							{
+								SpanId: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+								Kind:   v1.Span_SPAN_KIND_SERVER,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		resp := &tempopb.TracesByIDResponse{Traces: map[string]*tempopb.Trace{}}
+		uri, err := url.Parse(r.RequestURI)
+		require.NoError(t, err)
+		if uri.Query().Get(api.QueryModeKey) == api.QueryModeIngesters {
+			resp.Traces["a"] = shared
+		}
+
+		body, err := proto.Marshal(resp)
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			StatusCode: http.StatusOK,
+		}, nil
+	})
+
+	sharder := newTracesByIDSharder(&TraceByIDConfig{QueryShards: 2}, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	req := httptest.NewRequest("POST", "/api/traces", strings.NewReader(`{"traceIDs": ["0a"]}`))
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+	resp, err := testRT.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	actual := &tempopb.TracesByIDResponse{}
+	require.NoError(t, proto.Unmarshal(body, actual))
+
+	// the server span gets a new id and becomes the child of the client span
+	spans := actual.Traces["a"].Batches[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, spans[0].SpanId)
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02}, spans[1].SpanId)
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, spans[1].ParentSpanId)
+}
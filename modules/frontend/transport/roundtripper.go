@@ -11,8 +11,9 @@ import (
 )
 
 // GrpcRoundTripper is similar to http.RoundTripper, but works with HTTP requests converted to protobuf messages.
+// Responses that are streamed in several parts return a reader of the body after the first part.
 type GrpcRoundTripper interface {
-	RoundTripGRPC(context.Context, *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error)
+	RoundTripGRPC(context.Context, *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, io.ReadCloser, error)
 }
 
 func AdaptGrpcRoundTripperToHTTPRoundTripper(r GrpcRoundTripper) http.RoundTripper {
@@ -33,13 +34,23 @@ func (b *buffer) Bytes() []byte {
 	return b.buff
 }
 
+// streamBody is the body of a response that is streamed in several parts
+type streamBody struct {
+	io.Reader
+	stream io.Closer
+}
+
+func (b *streamBody) Close() error {
+	return b.stream.Close()
+}
+
 func (a *grpcRoundTripperAdapter) RoundTrip(r *http.Request) (*http.Response, error) {
 	req, err := server.HTTPRequest(r)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := a.roundTripper.RoundTripGRPC(r.Context(), req)
+	resp, stream, err := a.roundTripper.RoundTripGRPC(r.Context(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -53,5 +64,14 @@ func (a *grpcRoundTripperAdapter) RoundTrip(r *http.Request) (*http.Response, er
 	for _, h := range resp.Headers {
 		httpResp.Header[h.Key] = h.Values
 	}
+
+	// the body of a streamed response continues with the parts after the first one
+	if stream != nil {
+		httpResp.Body = &streamBody{
+			Reader: io.MultiReader(bytes.NewReader(resp.Body), stream),
+			stream: stream,
+		}
+		httpResp.ContentLength = -1
+	}
 	return httpResp, nil
 }
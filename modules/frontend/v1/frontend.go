@@ -4,7 +4,9 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/go-kit/log"
@@ -76,6 +78,8 @@ type request struct {
 	request  *httpgrpc.HTTPRequest
 	err      chan error
 	response chan *httpgrpc.HTTPResponse
+	// body of a streamed response after its first part, set before the first part is sent to response
+	stream *responseStream
 }
 
 // New creates a new frontend. Frontend implements service, and must be started and stopped.
@@ -149,8 +153,9 @@ func (f *Frontend) cleanupInactiveUserMetrics(user string) {
 	f.discardedRequests.DeleteLabelValues(user)
 }
 
-// RoundTripGRPC round trips a proto (instead of a HTTP request).
-func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error) {
+// RoundTripGRPC round trips a proto (instead of a HTTP request). If the querier streams the response, the
+// returned reader reads the body of the parts after the first one.
+func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, io.ReadCloser, error) {
 	// Propagate trace context in gRPC too - this will be ignored if using HTTP.
 	tracer, span := opentracing.GlobalTracer(), opentracing.SpanFromContext(ctx)
 	if tracer != nil && span != nil {
@@ -161,7 +166,7 @@ func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest)
 		}
 		err := tracer.Inject(span.Context(), opentracing.TextMap, carrier)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -177,18 +182,21 @@ func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest)
 	}
 
 	if err := f.queueRequest(ctx, &request); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	select {
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return nil, nil, ctx.Err()
 
 	case resp := <-request.response:
-		return resp, nil
+		if request.stream != nil {
+			return resp, request.stream, nil
+		}
+		return resp, nil, nil
 
 	case err := <-request.err:
-		return nil, err
+		return nil, nil, err
 	}
 }
 
@@ -245,50 +253,161 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 		resps := make(chan *frontendv1pb.ClientToFrontend, 1)
 		errs := make(chan error, 1)
 		go func() {
-			err = server.Send(&frontendv1pb.FrontendToClient{
-				Type:         frontendv1pb.Type_HTTP_REQUEST,
-				HttpRequest:  req.request,
-				StatsEnabled: stats.IsEnabled(req.originalCtx),
+			err := server.Send(&frontendv1pb.FrontendToClient{
+				Type:             frontendv1pb.Type_HTTP_REQUEST,
+				HttpRequest:      req.request,
+				StatsEnabled:     stats.IsEnabled(req.originalCtx),
+				StreamingEnabled: true,
 			})
 			if err != nil {
 				errs <- err
 				return
 			}
 
-			resp, err := server.Recv()
-			if err != nil {
-				errs <- err
-				return
+			// a streamed response is received in several parts
+			for {
+				resp, err := server.Recv()
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				select {
+				case resps <- resp:
+				case <-server.Context().Done():
+					return
+				}
+				if !resp.More {
+					return
+				}
 			}
-
-			resps <- resp
 		}()
 
+		if err := forwardResponse(req, resps, errs); err != nil {
+			return err
+		}
+	}
+}
+
+// forwardResponse passes the response of the querier to the request. The parts of a streamed response after
+// the first one are passed to the reader of the body as they are received.
+func forwardResponse(req *request, resps <-chan *frontendv1pb.ClientToFrontend, errs <-chan error) error {
+	var first *httpgrpc.HTTPResponse
+
+	for {
 		select {
 		// If the upstream request is cancelled, we need to cancel the
 		// downstream req.  Only way we can do that is to close the stream.
 		// The worker client is expecting this semantics.
 		case <-req.originalCtx.Done():
+			if req.stream != nil {
+				req.stream.finish(req.originalCtx.Err())
+			}
 			return req.originalCtx.Err()
 
 		// Is there was an error handling this request due to network IO,
 		// then error out this upstream request _and_ stream.
 		case err := <-errs:
-			req.err <- err
+			if req.stream != nil {
+				req.stream.finish(err)
+			} else {
+				req.err <- err
+			}
 			return err
 
 		// Happy path: merge the stats and propagate the response.
 		case resp := <-resps:
-			if stats.ShouldTrackHTTPGRPCResponse(resp.HttpResponse) {
+			if first == nil {
+				first = resp.HttpResponse
+			}
+			// parts after the first one only contain the body, stats are tracked based on the first one
+			if stats.ShouldTrackHTTPGRPCResponse(first) {
 				stats := stats.FromContext(req.originalCtx)
 				stats.Merge(resp.Stats) // Safe if stats is nil.
 			}
 
-			req.response <- resp.HttpResponse
+			switch {
+			case req.stream != nil:
+				req.stream.send(resp.HttpResponse.GetBody())
+			case resp.More:
+				req.stream = newResponseStream(req.originalCtx)
+				req.response <- resp.HttpResponse
+			default:
+				req.response <- resp.HttpResponse
+			}
+
+			if !resp.More {
+				if req.stream != nil {
+					req.stream.finish(nil)
+				}
+				return nil
+			}
 		}
 	}
 }
 
+// responseStream is the body of a streamed response after its first part.
+type responseStream struct {
+	ctx context.Context
+
+	// parts is closed after the last part, err is set before
+	parts     chan []byte
+	err       error
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	buff []byte
+}
+
+func newResponseStream(ctx context.Context) *responseStream {
+	return &responseStream{
+		ctx:    ctx,
+		parts:  make(chan []byte),
+		closed: make(chan struct{}),
+	}
+}
+
+func (s *responseStream) Read(p []byte) (int, error) {
+	for len(s.buff) == 0 {
+		select {
+		case part, ok := <-s.parts:
+			if !ok {
+				if s.err != nil {
+					return 0, s.err
+				}
+				return 0, io.EOF
+			}
+			s.buff = part
+		case <-s.ctx.Done():
+			return 0, s.ctx.Err()
+		}
+	}
+
+	n := copy(p, s.buff)
+	s.buff = s.buff[n:]
+	return n, nil
+}
+
+func (s *responseStream) Close() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	return nil
+}
+
+// send passes the next part to the reader. Parts are dropped once the reader closed the body, the remaining
+// parts are still received to keep the stream of the querier in lock step.
+func (s *responseStream) send(part []byte) {
+	select {
+	case s.parts <- part:
+	case <-s.closed:
+	case <-s.ctx.Done():
+	}
+}
+
+func (s *responseStream) finish(err error) {
+	s.err = err
+	close(s.parts)
+}
+
 func (f *Frontend) NotifyClientShutdown(_ context.Context, req *frontendv1pb.NotifyClientShutdownRequest) (*frontendv1pb.NotifyClientShutdownResponse, error) {
 	level.Info(f.log).Log("msg", "received shutdown notification from querier", "querier", req.GetClientID())
 	f.requestQueue.NotifyQuerierShutdown(req.GetClientID())
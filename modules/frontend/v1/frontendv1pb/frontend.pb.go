@@ -63,6 +63,8 @@ type FrontendToClient struct {
 	// Whether query statistics tracking should be enabled. The response will include
 	// statistics only when this option is enabled.
 	StatsEnabled bool `protobuf:"varint,3,opt,name=statsEnabled,proto3" json:"statsEnabled,omitempty"`
+	// Whether the client can stream the response in several ClientToFrontend messages.
+	StreamingEnabled bool `protobuf:"varint,4,opt,name=streamingEnabled,proto3" json:"streamingEnabled,omitempty"`
 }
 
 func (m *FrontendToClient) Reset()         { *m = FrontendToClient{} }
@@ -119,10 +121,20 @@ func (m *FrontendToClient) GetStatsEnabled() bool {
 	return false
 }
 
+func (m *FrontendToClient) GetStreamingEnabled() bool {
+	if m != nil {
+		return m.StreamingEnabled
+	}
+	return false
+}
+
 type ClientToFrontend struct {
 	HttpResponse *httpgrpc.HTTPResponse `protobuf:"bytes,1,opt,name=httpResponse,proto3" json:"httpResponse,omitempty"`
 	ClientID     string                 `protobuf:"bytes,2,opt,name=clientID,proto3" json:"clientID,omitempty"`
 	Stats        *stats.Stats           `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`
+	// Set if more parts of a streamed response follow. Parts after the first one only
+	// contain the next part of the body.
+	More bool `protobuf:"varint,4,opt,name=more,proto3" json:"more,omitempty"`
 }
 
 func (m *ClientToFrontend) Reset()         { *m = ClientToFrontend{} }
@@ -179,6 +191,13 @@ func (m *ClientToFrontend) GetStats() *stats.Stats {
 	return nil
 }
 
+func (m *ClientToFrontend) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
 type NotifyClientShutdownRequest struct {
 	ClientID string `protobuf:"bytes,1,opt,name=clientID,proto3" json:"clientID,omitempty"`
 }
@@ -272,36 +291,37 @@ func init() {
 }
 
 var fileDescriptor_8e6c94795ed772cd = []byte{
-	// 457 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x93, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0xc7, 0xbd, 0x50, 0x4a, 0x98, 0x58, 0x95, 0xb5, 0x2a, 0x28, 0x32, 0xc8, 0x0a, 0x2b, 0xa8,
-	0x22, 0x0e, 0x5e, 0x1a, 0x90, 0x10, 0x1c, 0xa1, 0xa1, 0xf4, 0x82, 0xca, 0xc6, 0x5c, 0xb8, 0x54,
-	0x89, 0xb3, 0x4d, 0x22, 0x12, 0x8f, 0xeb, 0x5d, 0x27, 0xca, 0x4b, 0x20, 0x24, 0xde, 0x86, 0x27,
-	0xe0, 0xd8, 0x23, 0x47, 0x94, 0xbc, 0x08, 0xf2, 0x6e, 0xe2, 0x26, 0x51, 0x04, 0x97, 0xd5, 0xcc,
-	0xfe, 0xe7, 0xe3, 0x37, 0x63, 0x2f, 0xf0, 0x31, 0xf6, 0xf2, 0x91, 0x54, 0xfc, 0x32, 0xc3, 0x44,
-	0xcb, 0xa4, 0xc7, 0x27, 0xc7, 0xa5, 0x3d, 0x39, 0x4e, 0xbb, 0xa5, 0x13, 0xa6, 0x19, 0x6a, 0xa4,
-	0x95, 0x95, 0xef, 0x1f, 0xf6, 0xb1, 0x8f, 0xe6, 0x92, 0x17, 0x96, 0xd5, 0xfd, 0x97, 0xfd, 0xa1,
-	0x1e, 0xe4, 0xdd, 0x30, 0xc6, 0x31, 0x9f, 0xca, 0xce, 0x44, 0x4e, 0x31, 0xfb, 0xaa, 0x78, 0x8c,
-	0xe3, 0x31, 0x26, 0x7c, 0xa0, 0x75, 0xda, 0xcf, 0xd2, 0xb8, 0x34, 0x96, 0x59, 0x8f, 0x57, 0x18,
-	0x57, 0xb9, 0xcc, 0x86, 0x32, 0xe3, 0x4a, 0x77, 0xb4, 0xb2, 0xa7, 0x0d, 0x61, 0x3f, 0x08, 0x78,
-	0xef, 0x97, 0xbd, 0x23, 0x7c, 0x37, 0x1a, 0xca, 0x44, 0xd3, 0x57, 0x50, 0x2d, 0x2a, 0x09, 0x79,
-	0x95, 0x4b, 0xa5, 0x6b, 0xa4, 0x4e, 0x1a, 0xd5, 0xe6, 0xfd, 0xb0, 0xac, 0xfe, 0x21, 0x8a, 0xce,
-	0x97, 0xa2, 0x58, 0x8f, 0xa4, 0x0c, 0xf6, 0xf4, 0x2c, 0x95, 0xb5, 0x5b, 0x75, 0xd2, 0x38, 0x68,
-	0x1e, 0x84, 0xe5, 0x94, 0xd1, 0x2c, 0x95, 0xc2, 0x68, 0x94, 0x81, 0x6b, 0x00, 0x5a, 0x49, 0xa7,
-	0x3b, 0x92, 0xbd, 0xda, 0xed, 0x3a, 0x69, 0x54, 0xc4, 0xc6, 0x1d, 0xfb, 0x46, 0xc0, 0xb3, 0x2c,
-	0x11, 0xae, 0xe8, 0xe8, 0x1b, 0x70, 0x6d, 0x2f, 0x95, 0x62, 0xa2, 0xe4, 0x12, 0xeb, 0xc1, 0x36,
-	0x96, 0x55, 0xc5, 0x46, 0x2c, 0xf5, 0xa1, 0x12, 0x9b, 0x7a, 0x67, 0x27, 0x06, 0xee, 0x9e, 0x28,
-	0x7d, 0xca, 0xe0, 0x8e, 0x69, 0x6e, 0x48, 0xaa, 0x4d, 0x37, 0xb4, 0xfb, 0x69, 0x17, 0xa7, 0xb0,
-	0x12, 0x7b, 0x0d, 0x0f, 0x3f, 0xa2, 0x1e, 0x5e, 0xce, 0x2c, 0x55, 0x7b, 0x90, 0xeb, 0x1e, 0x4e,
-	0x93, 0xd5, 0xdc, 0xeb, 0xe5, 0xc9, 0x66, 0x79, 0x16, 0xc0, 0xa3, 0xdd, 0xa9, 0x16, 0xed, 0xd9,
-	0x13, 0xd8, 0x2b, 0xb6, 0x43, 0x3d, 0x70, 0x8b, 0x01, 0x2e, 0x44, 0xeb, 0xd3, 0xe7, 0x56, 0x3b,
-	0xf2, 0x1c, 0x0a, 0xb0, 0x7f, 0xda, 0x8a, 0x2e, 0xce, 0x4e, 0x3c, 0xd2, 0xfc, 0x49, 0xa0, 0x52,
-	0x6e, 0xe2, 0x14, 0xee, 0x9e, 0x67, 0x18, 0x4b, 0xa5, 0xa8, 0x7f, 0xb3, 0xe3, 0xed, 0x85, 0xf9,
-	0x6b, 0xda, 0xf6, 0x27, 0x66, 0x4e, 0x83, 0x3c, 0x27, 0x54, 0xc2, 0xe1, 0x2e, 0x36, 0xfa, 0xf4,
-	0x26, 0xf3, 0x1f, 0x63, 0xfb, 0x47, 0xff, 0x0b, 0xb3, 0x23, 0xbe, 0x3d, 0xfa, 0x35, 0x0f, 0xc8,
-	0xf5, 0x3c, 0x20, 0x7f, 0xe6, 0x01, 0xf9, 0xbe, 0x08, 0x9c, 0xeb, 0x45, 0xe0, 0xfc, 0x5e, 0x04,
-	0xce, 0x17, 0x77, 0xfd, 0x51, 0x74, 0xf7, 0xcd, 0x3f, 0xf9, 0xe2, 0x6f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x80, 0xae, 0x78, 0xb5, 0x3f, 0x03, 0x00, 0x00,
+	// 478 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0xf5, 0x42, 0x28, 0x61, 0x62, 0x55, 0xd6, 0xaa, 0xa0, 0xc8, 0x20, 0x2b, 0x58, 0x50, 0x45,
+	0x3d, 0xd8, 0x34, 0x20, 0x21, 0x38, 0x42, 0x43, 0xe9, 0x05, 0x15, 0xc7, 0x5c, 0xb8, 0x54, 0x89,
+	0xb3, 0x75, 0x2c, 0x62, 0x8f, 0xeb, 0x5d, 0x27, 0xca, 0x5f, 0xf0, 0x0d, 0x7c, 0x06, 0x17, 0xae,
+	0x1c, 0x7b, 0xe4, 0x88, 0x92, 0x1f, 0x41, 0xde, 0xb5, 0xdd, 0x24, 0x44, 0x70, 0x59, 0xcd, 0xec,
+	0x7b, 0x33, 0xf3, 0xde, 0x78, 0x0d, 0x6e, 0x8c, 0xe3, 0x7c, 0xca, 0xb8, 0x7b, 0x99, 0x61, 0x22,
+	0x58, 0x32, 0x76, 0x67, 0xc7, 0x75, 0x3c, 0x3b, 0x4e, 0x47, 0x75, 0xe2, 0xa4, 0x19, 0x0a, 0xa4,
+	0xcd, 0x2a, 0x37, 0x0f, 0x42, 0x0c, 0x51, 0x5e, 0xba, 0x45, 0xa4, 0x70, 0xf3, 0x45, 0x18, 0x89,
+	0x49, 0x3e, 0x72, 0x02, 0x8c, 0xdd, 0x39, 0x1b, 0xce, 0xd8, 0x1c, 0xb3, 0x2f, 0xdc, 0x0d, 0x30,
+	0x8e, 0x31, 0x71, 0x27, 0x42, 0xa4, 0x61, 0x96, 0x06, 0x75, 0x50, 0x56, 0x3d, 0xae, 0x64, 0x5c,
+	0xe5, 0x2c, 0x8b, 0x58, 0xe6, 0x72, 0x31, 0x14, 0x5c, 0x9d, 0x8a, 0x62, 0xff, 0x20, 0x60, 0xbc,
+	0x2b, 0x67, 0xfb, 0xf8, 0x76, 0x1a, 0xb1, 0x44, 0xd0, 0x97, 0xd0, 0x2a, 0x3a, 0x79, 0xec, 0x2a,
+	0x67, 0x5c, 0xb4, 0x49, 0x87, 0x74, 0x5b, 0xbd, 0xfb, 0x4e, 0xdd, 0xfd, 0xbd, 0xef, 0x9f, 0x97,
+	0xa0, 0xb7, 0xce, 0xa4, 0x36, 0x34, 0xc4, 0x22, 0x65, 0xed, 0x5b, 0x1d, 0xd2, 0xdd, 0xef, 0xed,
+	0x3b, 0xb5, 0x4b, 0x7f, 0x91, 0x32, 0x4f, 0x62, 0xd4, 0x06, 0x5d, 0x0a, 0xe8, 0x27, 0xc3, 0xd1,
+	0x94, 0x8d, 0xdb, 0xb7, 0x3b, 0xa4, 0xdb, 0xf4, 0x36, 0xee, 0xe8, 0x11, 0x18, 0x5c, 0x64, 0x6c,
+	0x18, 0x47, 0x49, 0x58, 0xf1, 0x1a, 0x92, 0xf7, 0xd7, 0xbd, 0xfd, 0x8d, 0x80, 0xa1, 0x74, 0xfb,
+	0x58, 0x39, 0xa1, 0xaf, 0x41, 0x57, 0xba, 0x78, 0x8a, 0x09, 0x67, 0xa5, 0x85, 0x07, 0xdb, 0x16,
+	0x14, 0xea, 0x6d, 0x70, 0xa9, 0x09, 0xcd, 0x40, 0xf6, 0x3b, 0x3b, 0x91, 0x46, 0xee, 0x79, 0x75,
+	0x4e, 0x6d, 0xb8, 0x23, 0x85, 0x4a, 0xd5, 0xad, 0x9e, 0xee, 0xc8, 0xcc, 0x19, 0x14, 0xa7, 0xa7,
+	0x20, 0x4a, 0xa1, 0x11, 0x63, 0xc6, 0x4a, 0xc1, 0x32, 0xb6, 0x5f, 0xc1, 0xc3, 0x0f, 0x28, 0xa2,
+	0xcb, 0x85, 0x52, 0x3a, 0x98, 0xe4, 0x62, 0x8c, 0xf3, 0xa4, 0xda, 0xdb, 0xfa, 0x48, 0xb2, 0x39,
+	0xd2, 0xb6, 0xe0, 0xd1, 0xee, 0x52, 0x25, 0xf7, 0xe8, 0x09, 0x34, 0x8a, 0xed, 0x52, 0x03, 0xf4,
+	0xc2, 0xd4, 0x85, 0xd7, 0xff, 0xf8, 0xa9, 0x3f, 0xf0, 0x0d, 0x8d, 0x02, 0xec, 0x9d, 0xf6, 0xfd,
+	0x8b, 0xb3, 0x13, 0x83, 0xf4, 0xbe, 0x13, 0x68, 0xd6, 0xdb, 0x39, 0x85, 0xbb, 0xe7, 0x19, 0x06,
+	0x8c, 0x73, 0x6a, 0xde, 0x7c, 0xa3, 0xed, 0x25, 0x9a, 0x6b, 0xd8, 0xf6, 0x13, 0xb1, 0xb5, 0x2e,
+	0x79, 0x46, 0x28, 0x83, 0x83, 0x5d, 0xda, 0xe8, 0xd3, 0x9b, 0xca, 0x7f, 0xd8, 0x36, 0x0f, 0xff,
+	0x47, 0x53, 0x16, 0xdf, 0x1c, 0xfe, 0x5c, 0x5a, 0xe4, 0x7a, 0x69, 0x91, 0xdf, 0x4b, 0x8b, 0x7c,
+	0x5d, 0x59, 0xda, 0xf5, 0xca, 0xd2, 0x7e, 0xad, 0x2c, 0xed, 0xb3, 0xbe, 0xfe, 0x53, 0x8d, 0xf6,
+	0xe4, 0x9b, 0x7e, 0xfe, 0x67, 0x00, 0x72, 0xd0, 0x81, 0xd1, 0x7f, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -479,6 +499,16 @@ func (m *FrontendToClient) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.StreamingEnabled {
+		i--
+		if m.StreamingEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.StatsEnabled {
 		i--
 		if m.StatsEnabled {
@@ -529,6 +559,16 @@ func (m *ClientToFrontend) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.More {
+		i--
+		if m.More {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.Stats != nil {
 		{
 			size, err := m.Stats.MarshalToSizedBuffer(dAtA[:i])
@@ -643,6 +683,9 @@ func (m *FrontendToClient) Size() (n int) {
 	if m.StatsEnabled {
 		n += 2
 	}
+	if m.StreamingEnabled {
+		n += 2
+	}
 	return n
 }
 
@@ -664,6 +707,9 @@ func (m *ClientToFrontend) Size() (n int) {
 		l = m.Stats.Size()
 		n += 1 + l + sovFrontend(uint64(l))
 	}
+	if m.More {
+		n += 2
+	}
 	return n
 }
 
@@ -799,6 +845,26 @@ func (m *FrontendToClient) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.StatsEnabled = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StreamingEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.StreamingEnabled = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFrontend(dAtA[iNdEx:])
@@ -953,6 +1019,26 @@ func (m *ClientToFrontend) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field More", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFrontend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.More = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFrontend(dAtA[iNdEx:])
@@ -610,6 +610,11 @@ func filterBatch(batch *v1.ResourceSpans) *v1.ResourceSpans {
 }
 
 func toStaticProto(static traceql.Static) *tempopb.TraceQLStatic {
+	// the proto has no array type, arrays are sent as their string encoding
+	if static.Type == traceql.TypeArray {
+		static = traceql.NewStaticString(static.EncodeToString(false))
+	}
+
 	return &tempopb.TraceQLStatic{
 		Type:   int32(static.Type),
 		N:      int64(static.N),
@@ -26,9 +26,15 @@ type Config struct {
 	CompleteBlockTimeout time.Duration `yaml:"complete_block_timeout"`
 	OverrideRingKey      string        `yaml:"override_ring_key"`
 
+	// ConcurrentTagValuesSearches is the number of blocks searched at the same time by a tag values request.
+	// defaultConcurrentTagValuesSearches is used if it's not set.
+	ConcurrentTagValuesSearches int `yaml:"concurrent_tag_values_searches"`
+
 	AutocompleteFilteringEnabled bool `yaml:"-"`
 }
 
+const defaultConcurrentTagValuesSearches = 20
+
 // RegisterFlagsAndApplyDefaults registers the flags.
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	// apply generic defaults and then overlay tempo default
@@ -40,6 +46,7 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.ConcurrentFlushes = 4
 	cfg.FlushCheckPeriod = 10 * time.Second
 	cfg.FlushOpTimeout = 5 * time.Minute
+	cfg.ConcurrentTagValuesSearches = defaultConcurrentTagValuesSearches
 
 	f.DurationVar(&cfg.MaxTraceIdle, prefix+".trace-idle-period", 10*time.Second, "Duration after which to consider a trace complete if no spans have been received")
 	f.DurationVar(&cfg.MaxBlockDuration, prefix+".max-block-duration", 30*time.Minute, "Maximum duration which the head block can be appended to before cutting it.")
@@ -98,6 +98,42 @@ func (i *Ingester) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 	return res, nil
 }
 
+func (i *Ingester) SearchTagValueStats(ctx context.Context, req *tempopb.SearchTagValuesRequest) (*tempopb.SearchTagValueStatsResponse, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inst, ok := i.getInstanceByID(instanceID)
+	if !ok || inst == nil {
+		return &tempopb.SearchTagValueStatsResponse{}, nil
+	}
+
+	res, err := inst.SearchTagValueStats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (i *Ingester) QueryRange(ctx context.Context, req *tempopb.QueryRangeRequest) (*tempopb.QueryRangeResponse, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inst, ok := i.getInstanceByID(instanceID)
+	if !ok || inst == nil {
+		return &tempopb.QueryRangeResponse{}, nil
+	}
+
+	res, err := inst.QueryRange(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // SearchBlock only exists here to fulfill the protobuf interface. The ingester will never support
 // backend search
 func (i *Ingester) SearchBlock(context.Context, *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
@@ -118,9 +118,14 @@ type instance struct {
 	hash hash.Hash32
 
 	autocompleteFilteringEnabled bool
+	concurrentTagValuesSearches  int
 }
 
-func newInstance(instanceID string, limiter *Limiter, writer tempodb.Writer, l *local.Backend, autocompleteFiltering bool) (*instance, error) {
+func newInstance(instanceID string, limiter *Limiter, writer tempodb.Writer, l *local.Backend, autocompleteFiltering bool, concurrentTagValuesSearches int) (*instance, error) {
+	if concurrentTagValuesSearches <= 0 {
+		concurrentTagValuesSearches = defaultConcurrentTagValuesSearches
+	}
+
 	i := &instance{
 		traces:     map[uint32]*liveTrace{},
 		traceSizes: map[uint32]uint32{},
@@ -138,6 +143,7 @@ func newInstance(instanceID string, limiter *Limiter, writer tempodb.Writer, l *
 		hash: fnv.New32(),
 
 		autocompleteFilteringEnabled: autocompleteFiltering,
+		concurrentTagValuesSearches:  concurrentTagValuesSearches,
 	}
 	err := i.resetHeadBlock()
 	if err != nil {
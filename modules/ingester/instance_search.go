@@ -3,18 +3,19 @@ package ingester
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strings"
 	"sync"
 
 	"regexp"
 
 	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/boundedwaitgroup"
 	"github.com/grafana/tempo/pkg/search"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/traceqlmetrics"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/encoding/common"
@@ -36,6 +37,9 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 	if maxResults == 0 {
 		maxResults = 20
 	}
+	// ordered searches have to look at every trace. all of them are kept until the end so the values of
+	// a trace found in several blocks are combined before the top traces are picked
+	order := traceql.SearchRequestOrder(req, traceql.ParseSearch(req))
 
 	span.LogFields(ot_log.String("SearchRequest", req.String()))
 
@@ -70,7 +74,7 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 			resultsMap[result.TraceID] = result
 		}
 
-		if len(resultsMap) >= maxResults {
+		if order == nil && len(resultsMap) >= maxResults {
 			sr.Close() // signal pending workers to exit
 			break
 		}
@@ -87,9 +91,11 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 	}
 
 	// Sort
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].StartTimeUnixNano > results[j].StartTimeUnixNano
-	})
+	if order != nil {
+		results = traceql.TopTraces(results, order, maxResults)
+	} else {
+		traceql.SortTraces(results, nil)
+	}
 
 	return &tempopb.SearchResponse{
 		Traces: results,
@@ -394,40 +400,12 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 	distinctValues := util.NewDistinctValueCollector[tempopb.TagValue](limit, func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) })
 
 	cb := func(v traceql.Static) bool {
-		tv := tempopb.TagValue{}
-
-		switch v.Type {
-		case traceql.TypeString:
-			tv.Type = "string"
-			tv.Value = v.S // avoid formatting
-
-		case traceql.TypeBoolean:
-			tv.Type = "bool"
-			tv.Value = v.String()
-
-		case traceql.TypeInt:
-			tv.Type = "int"
-			tv.Value = v.String()
-
-		case traceql.TypeFloat:
-			tv.Type = "float"
-			tv.Value = v.String()
-
-		case traceql.TypeDuration:
-			tv.Type = "duration"
-			tv.Value = v.String()
-
-		case traceql.TypeStatus:
-			tv.Type = "keyword"
-			tv.Value = v.String()
-		}
-
-		return distinctValues.Collect(tv)
+		return distinctValues.Collect(search.TagValueFromStatic(v))
 	}
 
 	engine := traceql.NewEngine()
 
-	wg := boundedwaitgroup.New(20) // TODO: Make configurable
+	wg := boundedwaitgroup.New(uint(i.concurrentTagValuesSearches))
 	var anyErr atomic.Error
 	var inspectedBlocks atomic.Int32
 	var maxBlocks int32
@@ -529,6 +507,181 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 	return resp, nil
 }
 
+// SearchTagValueStats counts the values of a tag in all blocks of the instance and estimates their cardinality
+func (i *instance) SearchTagValueStats(ctx context.Context, req *tempopb.SearchTagValuesRequest) (*tempopb.SearchTagValueStatsResponse, error) {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := traceql.ParseIdentifier(req.TagName)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := i.limiter.limits.MaxBytesPerTagValuesQuery(userID)
+	stats := search.NewTagValueStatsCombiner(limit)
+
+	cb := func(v traceql.Static, count uint64) bool {
+		stats.Collect(search.TagValueFromStatic(v), count)
+		return false
+	}
+
+	wg := boundedwaitgroup.New(uint(i.concurrentTagValuesSearches))
+	var anyErr atomic.Error
+	var inspectedBlocks atomic.Int32
+	var maxBlocks int32
+	if limit := i.limiter.limits.MaxBlocksPerTagValuesQuery(userID); limit > 0 {
+		maxBlocks = int32(limit)
+	}
+
+	searchBlock := func(s common.Searcher) error {
+		if anyErr.Load() != nil {
+			return nil // Early exit if any error has occurred
+		}
+
+		if maxBlocks > 0 && inspectedBlocks.Inc() > maxBlocks {
+			return nil
+		}
+
+		return s.SearchTagValueCounts(ctx, tag, cb, common.DefaultSearchOptions())
+	}
+
+	i.blocksMtx.RLock()
+	defer i.blocksMtx.RUnlock()
+
+	// completed blocks
+	for _, b := range i.completeBlocks {
+		wg.Add(1)
+		go func(b *localBlock) {
+			defer wg.Done()
+			if err := searchBlock(b); err != nil {
+				anyErr.Store(fmt.Errorf("unexpected error searching complete block (%s): %w", b.BlockMeta().BlockID, err))
+			}
+		}(b)
+	}
+
+	// completing blocks. a block is kept in both lists for a moment after it's completed, skip it here
+	// so its values aren't counted twice
+	for _, b := range i.completingBlocks {
+		if isBlockCompleted(i.completeBlocks, b.BlockMeta().BlockID) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(b common.WALBlock) {
+			defer wg.Done()
+			if err := searchBlock(b); err != nil {
+				anyErr.Store(fmt.Errorf("unexpected error searching completing block (%s): %w", b.BlockMeta().BlockID, err))
+			}
+		}(b)
+	}
+
+	// head block
+	if i.headBlock != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := searchBlock(i.headBlock); err != nil {
+				anyErr.Store(fmt.Errorf("unexpected error searching head block (%s): %w", i.headBlock.BlockMeta().BlockID, err))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := anyErr.Load(); err != nil {
+		return nil, err
+	}
+
+	if stats.Exceeded() {
+		level.Warn(log.Logger).Log("msg", "size of tag values in instance exceeded limit, reduce cardinality or size of tags", "tag", req.TagName, "userID", userID, "limit", limit)
+	}
+
+	return stats.Response(0), nil
+}
+
+// QueryRange evaluates a TraceQL metrics query on all blocks of the instance and returns the raw counts
+func (i *instance) QueryRange(ctx context.Context, req *tempopb.QueryRangeRequest) (*tempopb.QueryRangeResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "instance.QueryRange")
+	defer span.Finish()
+
+	queryRangeReq := traceqlmetrics.QueryRangeRequestFromProto(req)
+	if err := queryRangeReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		mtx      sync.Mutex
+		combiner = traceqlmetrics.NewRawSeriesCombiner()
+		anyErr   atomic.Error
+		wg       sync.WaitGroup
+	)
+
+	queryBlock := func(s common.Searcher) {
+		defer wg.Done()
+
+		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+			return s.Fetch(ctx, req, common.DefaultSearchOptions())
+		})
+
+		series, err := traceqlmetrics.QueryRange(ctx, queryRangeReq, fetcher)
+		if err == common.ErrUnsupported {
+			return
+		}
+		if err != nil {
+			anyErr.Store(err)
+			return
+		}
+
+		mtx.Lock()
+		combiner.Combine(series)
+		mtx.Unlock()
+	}
+
+	i.blocksMtx.RLock()
+	defer i.blocksMtx.RUnlock()
+
+	for _, b := range i.completeBlocks {
+		wg.Add(1)
+		go queryBlock(b)
+	}
+
+	// a block is kept in both lists for a moment after it's completed, skip it here so its spans aren't counted twice
+	for _, b := range i.completingBlocks {
+		if isBlockCompleted(i.completeBlocks, b.BlockMeta().BlockID) {
+			continue
+		}
+
+		wg.Add(1)
+		go queryBlock(b)
+	}
+
+	if i.headBlock != nil {
+		wg.Add(1)
+		go queryBlock(i.headBlock)
+	}
+
+	wg.Wait()
+
+	if err := anyErr.Load(); err != nil {
+		return nil, err
+	}
+
+	return &tempopb.QueryRangeResponse{
+		Series: traceqlmetrics.SeriesToProto(combiner.Results()),
+	}, nil
+}
+
+func isBlockCompleted(completeBlocks []*localBlock, id uuid.UUID) bool {
+	for _, c := range completeBlocks {
+		if c.BlockMeta().BlockID == id {
+			return true
+		}
+	}
+	return false
+}
+
 // Regex to extract matchers from a query string
 // This regular expression matches a string that contains three groups separated by operators.
 // The first group is a string of alphabetical characters, dots, and underscores.
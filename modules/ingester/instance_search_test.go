@@ -282,6 +282,98 @@ func testSearchTagsAndValuesV2(t *testing.T, ctx context.Context, i *instance, t
 	assert.Equal(t, expectedTagValues, tagValues)
 }
 
+func TestInstanceSearchTagValueStats(t *testing.T) {
+	i, _ := defaultInstance(t)
+
+	tagKey := "foo"
+	tagValue := "bar"
+
+	ids, _ := writeTracesForSearch(t, i, tagKey, tagValue, false)
+
+	userCtx := user.InjectOrgID(context.Background(), "fake")
+
+	// Test after appending to WAL
+	testSearchTagValueStats(t, userCtx, i, tagKey, tagValue, uint64(len(ids)))
+
+	// Test after cutting new headblock
+	blockID, err := i.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+	assert.NotEqual(t, blockID, uuid.Nil)
+
+	testSearchTagValueStats(t, userCtx, i, tagKey, tagValue, uint64(len(ids)))
+
+	// Test after completing a block
+	err = i.CompleteBlock(blockID)
+	require.NoError(t, err)
+
+	testSearchTagValueStats(t, userCtx, i, tagKey, tagValue, uint64(len(ids)))
+}
+
+// nolint:revive
+func testSearchTagValueStats(t *testing.T, ctx context.Context, i *instance, tagName, tagValue string, expectedCount uint64) {
+	resp, err := i.SearchTagValueStats(ctx, &tempopb.SearchTagValuesRequest{
+		TagName: fmt.Sprintf(".%s", tagName),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*tempopb.TagValueCount{{Type: "string", Value: tagValue, Count: expectedCount}}, resp.Values)
+	require.Equal(t, uint64(1), resp.Cardinality)
+	require.NotEmpty(t, resp.Sketch)
+}
+
+func TestInstanceQueryRange(t *testing.T) {
+	i, _ := defaultInstance(t)
+
+	traces, _ := pushTracesToInstance(t, i, 10)
+	require.NoError(t, i.CutCompleteTraces(0, true))
+
+	expected := 0
+	for _, tr := range traces {
+		for _, b := range tr.Batches {
+			for _, ss := range b.ScopeSpans {
+				expected += len(ss.Spans)
+			}
+		}
+	}
+
+	now := time.Now()
+	req := &tempopb.QueryRangeRequest{
+		Query: "{ } | count_over_time()",
+		Start: uint64(now.Add(-time.Hour).UnixNano()),
+		End:   uint64(now.Add(time.Hour).UnixNano()),
+		Step:  uint64(time.Hour),
+	}
+
+	testQueryRange := func() {
+		resp, err := i.QueryRange(context.Background(), req)
+		require.NoError(t, err)
+
+		actual := 0.0
+		for _, s := range resp.Series {
+			for _, sample := range s.Samples {
+				actual += sample.Value
+			}
+		}
+		require.Equal(t, float64(expected), actual)
+	}
+
+	// Test after appending to WAL
+	testQueryRange()
+
+	// Test after cutting new headblock
+	blockID, err := i.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+	assert.NotEqual(t, blockID, uuid.Nil)
+
+	testQueryRange()
+
+	// Test after completing a block, it's both in the complete and completing blocks
+	err = i.CompleteBlock(blockID)
+	require.NoError(t, err)
+
+	testQueryRange()
+}
+
 // TestInstanceSearchTagsSpecialCases tess that SearchTags errors on an unknown scope and
 // returns known instrinics for the "intrinsic" scope
 func TestInstanceSearchTagsSpecialCases(t *testing.T) {
@@ -294,7 +386,7 @@ func TestInstanceSearchTagsSpecialCases(t *testing.T) {
 
 	resp, err = i.SearchTags(userCtx, "intrinsic")
 	require.NoError(t, err)
-	require.Equal(t, []string{"duration", "kind", "name", "status"}, resp.TagNames)
+	require.Equal(t, []string{"duration", "kind", "name", "status", "traceDuration", "rootServiceName", "rootName"}, resp.TagNames)
 }
 
 // TestInstanceSearchMaxBytesPerTagValuesQueryReturnsPartial confirms that SearchTagValues returns
@@ -576,6 +576,12 @@ func TestSortByteSlices(t *testing.T) {
 	assert.Equal(t, traceBytes, traceBytes2)
 }
 
+func TestInstanceConcurrentTagValuesSearchesDefault(t *testing.T) {
+	// the test ingester config doesn't set the concurrency
+	i, _ := defaultInstance(t)
+	require.Equal(t, defaultConcurrentTagValuesSearches, i.concurrentTagValuesSearches)
+}
+
 func defaultInstance(t testing.TB) (*instance, *Ingester) {
 	instance, ingester, _ := defaultInstanceAndTmpDir(t)
 	return instance, ingester
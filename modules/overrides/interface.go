@@ -54,4 +54,5 @@ type Interface interface {
 	MetricsGeneratorProcessorSpanMetricsEnableTargetInfo(userID string) bool
 	BlockRetention(userID string) time.Duration
 	MaxSearchDuration(userID string) time.Duration
+	SearchCacheEnabled(userID string) bool
 }
@@ -91,7 +91,8 @@ type Limits struct {
 	MaxBlocksPerTagValuesQuery int `yaml:"max_blocks_per_tag_values_query" json:"max_blocks_per_tag_values_query"`
 
 	// QueryFrontend enforced limits
-	MaxSearchDuration model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
+	MaxSearchDuration  model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
+	SearchCacheEnabled bool           `yaml:"search_cache_enabled" json:"search_cache_enabled"`
 
 	// MaxBytesPerTrace is enforced in the Ingester, Compactor, Querier (Search) and Serverless (Search). It
 	//  is not used when doing a trace by id lookup.
@@ -389,6 +389,11 @@ func (o *overrides) MaxSearchDuration(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).MaxSearchDuration)
 }
 
+// SearchCacheEnabled controls whether the query frontend caches search job results for this tenant.
+func (o *overrides) SearchCacheEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).SearchCacheEnabled
+}
+
 func (o *overrides) getOverridesForUser(userID string) *Limits {
 	if tenantOverrides := o.tenantOverrides(); tenantOverrides != nil {
 		l := tenantOverrides.forUser(userID)
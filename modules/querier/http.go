@@ -7,12 +7,19 @@ import (
 	"net/http"
 	"time"
 
+	protoio "github.com/gogo/protobuf/io"
+	gogojsonpb "github.com/gogo/protobuf/jsonpb"
 	"github.com/golang/protobuf/jsonpb" //nolint:all //deprecated
 	"github.com/golang/protobuf/proto"  //nolint:all //ProtoReflect
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/opentracing/opentracing-go"
 	ot_log "github.com/opentracing/opentracing-go/log"
+	"github.com/prometheus/prometheus/prompb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -23,6 +30,9 @@ const (
 	QueryModeIngesters = "ingesters"
 	QueryModeBlocks    = "blocks"
 	QueryModeAll       = "all"
+
+	// traceSummaryLimit is the number of gaps and largest spans returned in a trace summary
+	traceSummaryLimit = 10
 )
 
 // TraceByIDHandler is a http.HandlerFunc to retrieve traces
@@ -87,6 +97,262 @@ func (q *Querier) TraceByIDHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptOTLPProtobuf {
+		span.SetTag("contentType", api.HeaderAcceptOTLPProtobuf)
+		b, err := api.MarshalTraceOTLPProto(resp.Trace)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptOTLPProtobuf)
+		_, err = w.Write(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		return
+	}
+
+	if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptOTLPJSON && !api.IsTraceByIDV2(r) {
+		span.SetTag("contentType", api.HeaderAcceptOTLPJSON)
+		b, err := api.MarshalTraceOTLPJSON(resp.Trace)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptOTLPJSON)
+		_, err = w.Write(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	span.SetTag("contentType", api.HeaderAcceptJSON)
+	if api.IsTraceByIDV2(r) {
+		b, err := api.MarshalTraceByIDResponseV2JSON(resp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+		_, err = w.Write(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	marshaller := &jsonpb.Marshaler{}
+	err = marshaller.Marshal(w, resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+}
+
+// TraceByIDStreamHandler streams the parts of a trace found by TraceByID as length delimited TraceByIDResponse
+// messages, every message is flushed as soon as it is written. Errors after the first message end the stream
+// with a partial message instead of the metrics of a complete one.
+func (q *Querier) TraceByIDStreamHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.TraceByID.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.TraceByIDStreamHandler")
+	defer span.Finish()
+
+	byteID, err := api.ParseTraceID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// validate request
+	blockStart, blockEnd, queryMode, timeStart, timeEnd, err := api.ValidateAndSanitizeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stream := &httpTraceByIDStream{ctx: ctx, w: w, writer: protoio.NewDelimitedWriter(w)}
+	err = q.TraceByID(&tempopb.TraceByIDStreamRequest{
+		TraceID:    byteID,
+		Start:      uint32(timeStart),
+		End:        uint32(timeEnd),
+		BlockStart: blockStart,
+		BlockEnd:   blockEnd,
+		QueryMode:  queryMode,
+	}, stream)
+	if err == nil {
+		return
+	}
+
+	if !stream.started {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case codes.NotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			handleError(w, err)
+		}
+		return
+	}
+
+	_ = stream.Send(&tempopb.TraceByIDResponse{
+		Status:  tempopb.PartialStatus_PARTIAL,
+		Message: err.Error(),
+	})
+}
+
+// httpTraceByIDStream writes the messages of a streamed trace to a http response
+type httpTraceByIDStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	w       http.ResponseWriter
+	writer  protoio.Writer
+	started bool
+}
+
+func (s *httpTraceByIDStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *httpTraceByIDStream) Send(resp *tempopb.TraceByIDResponse) error {
+	if !s.started {
+		s.w.Header().Set(api.HeaderContentType, api.HeaderAcceptProtobuf)
+		s.started = true
+	}
+	if err := s.writer.WriteMsg(resp); err != nil {
+		return err
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// TraceSummaryHandler is a http.HandlerFunc to retrieve the summary of a trace. The trace is combined from
+// the ingesters and the blocks and summarized here, so it doesn't need to be sent to the frontend.
+func (q *Querier) TraceSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.TraceByID.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.TraceSummaryHandler")
+	defer span.Finish()
+
+	byteID, err := api.ParseTraceID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// validate request
+	blockStart, blockEnd, queryMode, timeStart, timeEnd, err := api.ValidateAndSanitizeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+		TraceID:    byteID,
+		BlockStart: blockStart,
+		BlockEnd:   blockEnd,
+		QueryMode:  queryMode,
+	}, timeStart, timeEnd)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if resp.Trace == nil || len(resp.Trace.Batches) == 0 {
+		http.Error(w, "trace not found", http.StatusNotFound)
+		return
+	}
+
+	summary := trace.Summarize(resp.Trace, traceSummaryLimit)
+	span.SetTag("spans", summary.SpanCount)
+
+	if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
+		span.SetTag("contentType", api.HeaderAcceptProtobuf)
+		b, err := proto.Marshal(summary)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptProtobuf)
+		_, err = w.Write(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	span.SetTag("contentType", api.HeaderAcceptJSON)
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	marshaller := &jsonpb.Marshaler{}
+	err = marshaller.Marshal(w, summary)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// TracesByIDHandler is a http.HandlerFunc to retrieve several traces at once
+func (q *Querier) TracesByIDHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.TraceByID.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.TracesByIDHandler")
+	defer span.Finish()
+
+	req, err := api.ParseTracesByIDRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// validate request
+	blockStart, blockEnd, queryMode, timeStart, timeEnd, err := api.ValidateAndSanitizeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	span.LogFields(
+		ot_log.String("msg", "validated request"),
+		ot_log.Int("traceIDs", len(req.TraceIDs)),
+		ot_log.String("blockStart", blockStart),
+		ot_log.String("blockEnd", blockEnd),
+		ot_log.String("queryMode", queryMode),
+		ot_log.String("timeStart", fmt.Sprint(timeStart)),
+		ot_log.String("timeEnd", fmt.Sprint(timeEnd)))
+
+	resp, err := q.FindTracesByID(ctx, req, blockStart, blockEnd, queryMode, timeStart, timeEnd)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
+		span.SetTag("contentType", api.HeaderAcceptProtobuf)
+		b, err := proto.Marshal(resp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptProtobuf)
+		_, err = w.Write(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		return
+	}
+
 	span.SetTag("contentType", api.HeaderAcceptJSON)
 	marshaller := &jsonpb.Marshaler{}
 	err = marshaller.Marshal(w, resp)
@@ -114,7 +380,7 @@ func (q *Querier) SearchHandler(w http.ResponseWriter, r *http.Request) {
 	if !isSearchBlock {
 		req, err := api.ParseSearchRequest(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			api.WriteError(w, err, http.StatusBadRequest)
 			return
 		}
 
@@ -128,7 +394,7 @@ func (q *Querier) SearchHandler(w http.ResponseWriter, r *http.Request) {
 	} else {
 		req, err := api.ParseSearchBlockRequest(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			api.WriteError(w, err, http.StatusBadRequest)
 			return
 		}
 
@@ -158,20 +424,35 @@ func (q *Querier) SearchTagsHandler(w http.ResponseWriter, r *http.Request) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.SearchTagsHandler")
 	defer span.Finish()
 
-	req, err := api.ParseSearchTagsRequest(r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
+	var resp *tempopb.SearchTagsResponse
+	if api.IsSearchBlock(r) {
+		req, blockReq, err := api.ParseSearchTagsBlockRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-	resp, err := q.SearchTags(ctx, req)
-	if err != nil {
-		handleError(w, err)
-		return
+		resp, err = q.SearchTagsBlock(ctx, req, blockReq)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	} else {
+		req, err := api.ParseSearchTagsRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err = q.SearchTags(ctx, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
 	}
 
 	marshaller := &jsonpb.Marshaler{}
-	err = marshaller.Marshal(w, resp)
+	err := marshaller.Marshal(w, resp)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -187,20 +468,35 @@ func (q *Querier) SearchTagsV2Handler(w http.ResponseWriter, r *http.Request) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.SearchTagsHandler")
 	defer span.Finish()
 
-	req, err := api.ParseSearchTagsRequest(r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
+	var resp *tempopb.SearchTagsV2Response
+	if api.IsSearchBlock(r) {
+		req, blockReq, err := api.ParseSearchTagsBlockRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-	resp, err := q.SearchTagsV2(ctx, req)
-	if err != nil {
-		handleError(w, err)
-		return
+		resp, err = q.SearchTagsBlockV2(ctx, req, blockReq)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	} else {
+		req, err := api.ParseSearchTagsRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err = q.SearchTagsV2(ctx, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
 	}
 
 	marshaller := &jsonpb.Marshaler{}
-	err = marshaller.Marshal(w, resp)
+	err := marshaller.Marshal(w, resp)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -216,20 +512,35 @@ func (q *Querier) SearchTagValuesHandler(w http.ResponseWriter, r *http.Request)
 	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.SearchTagValuesHandler")
 	defer span.Finish()
 
-	req, err := api.ParseSearchTagValuesRequest(r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
+	var resp *tempopb.SearchTagValuesResponse
+	if api.IsSearchBlock(r) {
+		req, blockReq, err := api.ParseSearchTagValuesBlockRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-	resp, err := q.SearchTagValues(ctx, req)
-	if err != nil {
-		handleError(w, err)
-		return
+		resp, err = q.SearchTagValuesBlock(ctx, req, blockReq)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	} else {
+		req, err := api.ParseSearchTagValuesRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err = q.SearchTagValues(ctx, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
 	}
 
 	marshaller := &jsonpb.Marshaler{}
-	err = marshaller.Marshal(w, resp)
+	err := marshaller.Marshal(w, resp)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -245,20 +556,79 @@ func (q *Querier) SearchTagValuesV2Handler(w http.ResponseWriter, r *http.Reques
 	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.SearchTagValuesHandler")
 	defer span.Finish()
 
-	req, err := api.ParseSearchTagValuesRequestV2(r)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	var resp *tempopb.SearchTagValuesV2Response
+	if api.IsSearchBlock(r) {
+		req, blockReq, err := api.ParseSearchTagValuesBlockRequestV2(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err = q.SearchTagValuesBlockV2(ctx, req, blockReq)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	} else {
+		req, err := api.ParseSearchTagValuesRequestV2(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err = q.SearchTagValuesV2(ctx, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
 	}
 
-	resp, err := q.SearchTagValuesV2(ctx, req)
+	marshaller := &jsonpb.Marshaler{}
+	err := marshaller.Marshal(w, resp)
 	if err != nil {
-		handleError(w, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+}
+
+func (q *Querier) SearchTagValueStatsHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.Search.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.SearchTagValueStatsHandler")
+	defer span.Finish()
+
+	var resp *tempopb.SearchTagValueStatsResponse
+	if api.IsSearchBlock(r) {
+		req, blockReq, err := api.ParseSearchTagValueStatsBlockRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err = q.SearchTagValueStatsBlock(ctx, req, blockReq)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	} else {
+		req, err := api.ParseSearchTagValueStatsRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err = q.SearchTagValueStats(ctx, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	}
 
 	marshaller := &jsonpb.Marshaler{}
-	err = marshaller.Marshal(w, resp)
+	err := marshaller.Marshal(w, resp)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -295,6 +665,58 @@ func (q *Querier) SpanMetricsSummaryHandler(w http.ResponseWriter, r *http.Reque
 	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
 }
 
+// QueryRangeHandler evaluates a TraceQL metrics query on the ingesters or on a range of pages of a backend
+// block. The query frontend shards range queries into these requests.
+func (q *Querier) QueryRangeHandler(w http.ResponseWriter, r *http.Request) {
+	isSearchBlock := api.IsSearchBlock(r)
+
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.Search.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.QueryRangeHandler")
+	defer span.Finish()
+
+	span.SetTag("requestURI", r.RequestURI)
+	span.SetTag("isSearchBlock", isSearchBlock)
+
+	var series []*prompb.TimeSeries
+	if !isSearchBlock {
+		req, err := api.ParseQueryRangeRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		series, err = q.QueryRangeRecent(ctx, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	} else {
+		req, blockReq, err := api.ParseQueryRangeBlockRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		series, err = q.QueryRange(ctx, req, blockReq)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	}
+
+	// prompb is generated with gogoproto
+	marshaller := &gogojsonpb.Marshaler{}
+	err := marshaller.Marshal(w, &prompb.QueryResult{Timeseries: series})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+}
+
 func handleError(w http.ResponseWriter, err error) {
 	if errors.Is(err, context.Canceled) {
 		// ignore this error. we regularly cancel context once queries are complete
@@ -7,7 +7,7 @@ import (
 	"io"
 	"math/rand"
 	"net/http"
-	"sort"
+	"sync"
 	"time"
 
 	"github.com/cristalhq/hedgedhttp"
@@ -22,10 +22,12 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	httpgrpc_server "github.com/weaveworks/common/httpgrpc/server"
+	"github.com/prometheus/prometheus/prompb"
 	"github.com/weaveworks/common/user"
 	"go.uber.org/multierr"
 	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
@@ -33,6 +35,7 @@ import (
 	"github.com/grafana/tempo/modules/querier/worker"
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
 	"github.com/grafana/tempo/pkg/hedgedmetrics"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/search"
@@ -42,6 +45,7 @@ import (
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/pkg/validation"
+	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 )
@@ -72,6 +76,15 @@ var (
 	)
 )
 
+const (
+	// maxConcurrentTraceLookups is the number of traces of a batch trace by id request that are looked up at once
+	maxConcurrentTraceLookups = 10
+
+	// traceByIDStreamMessageBytes is the size of the batches sent in one message of a streamed trace. Larger
+	// parts of a trace are sent in several messages, so a trace doesn't hit message limits.
+	traceByIDStreamMessageBytes = 1 << 20
+)
+
 // Querier handlers queries.
 type Querier struct {
 	services.Service
@@ -171,7 +184,7 @@ func (q *Querier) CreateAndRegisterWorker(handler http.Handler) error {
 	q.cfg.Worker.MaxConcurrentRequests = q.cfg.MaxConcurrentQueries
 	worker, err := worker.NewQuerierWorker(
 		q.cfg.Worker,
-		httpgrpc_server.NewServer(handler),
+		worker.NewHTTPHandler(handler),
 		log.Logger,
 		nil,
 	)
@@ -246,14 +259,7 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 	combiner := trace.NewCombiner()
 	var spanCount, spanCountTotal, traceCountTotal int
 	if req.QueryMode == QueryModeIngesters || req.QueryMode == QueryModeAll {
-		var replicationSet ring.ReplicationSet
-		var err error
-		if q.cfg.QueryRelevantIngesters {
-			traceKey := util.TokenFor(userID, req.TraceID)
-			replicationSet, err = q.ingesterRing.Get(traceKey, ring.Read, nil, nil, nil)
-		} else {
-			replicationSet, err = q.ingesterRing.GetReplicationSetForOperation(ring.Read)
-		}
+		replicationSet, err := q.traceByIDReplicationSet(userID, req.TraceID)
 		if err != nil {
 			return nil, errors.Wrap(err, "error finding ingesters in Querier.FindTraceByID")
 		}
@@ -318,6 +324,203 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 	}, nil
 }
 
+// TraceByID streams the parts of a trace as the ingesters respond and the blocks are read. Spans that were
+// already sent are dropped from the parts found later. The last message contains the metrics.
+func (q *Querier) TraceByID(req *tempopb.TraceByIDStreamRequest, srv tempopb.StreamingQuerier_TraceByIDServer) error {
+	if !validation.ValidTraceID(req.TraceID) {
+		return status.Error(codes.InvalidArgument, "invalid trace id")
+	}
+
+	ctx := srv.Context()
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error extracting org id in Querier.TraceByID")
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.TraceByID")
+	defer span.Finish()
+
+	queryMode := req.QueryMode
+	if queryMode == "" {
+		queryMode = QueryModeAll
+	}
+	span.SetTag("queryMode", queryMode)
+
+	stream := newTraceByIDStream(srv)
+	defer stream.close()
+
+	if queryMode == QueryModeIngesters || queryMode == QueryModeAll {
+		replicationSet, err := q.traceByIDReplicationSet(userID, req.TraceID)
+		if err != nil {
+			return errors.Wrap(err, "error finding ingesters in Querier.TraceByID")
+		}
+
+		span.LogFields(ot_log.String("msg", "searching ingesters"))
+
+		// every ingester sends its part of the trace as soon as it responds
+		forEachFunc := func(funcCtx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+			resp, err := client.FindTraceByID(funcCtx, &tempopb.TraceByIDRequest{
+				TraceID:   req.TraceID,
+				QueryMode: QueryModeIngesters,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return nil, stream.send(resp.Trace)
+		}
+
+		if _, err := q.forGivenIngesters(ctx, replicationSet, forEachFunc); err != nil {
+			return errors.Wrap(err, "error querying ingesters in Querier.TraceByID")
+		}
+	}
+
+	if queryMode == QueryModeBlocks || queryMode == QueryModeAll {
+		blockStart, blockEnd := req.BlockStart, req.BlockEnd
+		if blockStart == "" {
+			blockStart = tempodb.BlockIDMin
+		}
+		if blockEnd == "" {
+			blockEnd = tempodb.BlockIDMax
+		}
+
+		span.LogFields(ot_log.String("msg", "searching store"))
+		blockErrs, err := q.store.FindFunc(ctx, userID, req.TraceID, blockStart, blockEnd, int64(req.Start), int64(req.End), stream.send)
+		if err != nil {
+			return errors.Wrap(err, "error querying store in Querier.TraceByID")
+		}
+		if len(blockErrs) > 0 {
+			return multierr.Combine(blockErrs...)
+		}
+	}
+
+	// nothing is sent after the stream is closed, the final message is the last one
+	if !stream.close() {
+		return status.Error(codes.NotFound, "trace not found")
+	}
+	return srv.Send(&tempopb.TraceByIDResponse{
+		Metrics: &tempopb.TraceByIDMetrics{},
+	})
+}
+
+// traceByIDStream sends the parts of a trace found by TraceByID. Parts are sent one at a time in messages of
+// up to traceByIDStreamMessageBytes, spans that were already sent are dropped. Ingesters that respond after
+// the stream was closed are ignored.
+type traceByIDStream struct {
+	mtx     sync.Mutex
+	srv     tempopb.StreamingQuerier_TraceByIDServer
+	deduper *trace.SpanDeduper
+	found   bool
+	closed  bool
+}
+
+func newTraceByIDStream(srv tempopb.StreamingQuerier_TraceByIDServer) *traceByIDStream {
+	return &traceByIDStream{
+		srv:     srv,
+		deduper: trace.NewSpanDeduper(),
+	}
+}
+
+func (s *traceByIDStream) send(partialTrace *tempopb.Trace) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.closed || s.deduper.Dedupe(partialTrace) == 0 {
+		return nil
+	}
+	s.found = true
+
+	for _, batches := range trace.SplitBatches(partialTrace.Batches, traceByIDStreamMessageBytes) {
+		err := s.srv.Send(&tempopb.TraceByIDResponse{
+			Trace: &tempopb.Trace{Batches: batches},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close stops sending parts and returns whether any part was sent
+func (s *traceByIDStream) close() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.closed = true
+	return s.found
+}
+
+// FindTracesByID looks up several traces at once. Every trace is searched like in FindTraceByID, so the
+// block id range and bloom filters of the backend blocks are checked for each of them.
+func (q *Querier) FindTracesByID(ctx context.Context, req *tempopb.TracesByIDRequest, blockStart, blockEnd, queryMode string, timeStart int64, timeEnd int64) (*tempopb.TracesByIDResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.FindTracesByID")
+	defer span.Finish()
+
+	span.SetTag("traceIDs", len(req.TraceIDs))
+
+	var (
+		mtx    sync.Mutex
+		anyErr error
+		resp   = &tempopb.TracesByIDResponse{
+			Traces: map[string]*tempopb.Trace{},
+		}
+	)
+
+	wg := boundedwaitgroup.New(maxConcurrentTraceLookups)
+	for _, id := range req.TraceIDs {
+		byteID, err := util.HexStringToTraceID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		wg.Add(1)
+		go func(id string, byteID []byte) {
+			defer wg.Done()
+
+			traceResp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+				TraceID:    byteID,
+				BlockStart: blockStart,
+				BlockEnd:   blockEnd,
+				QueryMode:  queryMode,
+			}, timeStart, timeEnd)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			if err != nil {
+				anyErr = err
+				return
+			}
+
+			if traceResp.Trace != nil && len(traceResp.Trace.Batches) > 0 {
+				resp.Traces[id] = traceResp.Trace
+			}
+		}(id, byteID)
+	}
+	wg.Wait()
+
+	if anyErr != nil {
+		return nil, anyErr
+	}
+
+	// keep the order of the request
+	for _, id := range req.TraceIDs {
+		if _, ok := resp.Traces[id]; !ok {
+			resp.NotFound = append(resp.NotFound, id)
+		}
+	}
+
+	return resp, nil
+}
+
+// traceByIDReplicationSet returns the ingesters that may hold the trace
+func (q *Querier) traceByIDReplicationSet(userID string, traceID []byte) (ring.ReplicationSet, error) {
+	if q.cfg.QueryRelevantIngesters {
+		traceKey := util.TokenFor(userID, traceID)
+		return q.ingesterRing.Get(traceKey, ring.Read, nil, nil, nil)
+	}
+	return q.ingesterRing.GetReplicationSetForOperation(ring.Read)
+}
+
 // forGivenIngesters runs f, in parallel, for given ingesters
 func (q *Querier) forGivenIngesters(ctx context.Context, replicationSet ring.ReplicationSet, f func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
 	if ctx.Err() != nil {
@@ -602,6 +805,55 @@ func (q *Querier) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTagV
 	return valuesToV2Response(distinctValues), nil
 }
 
+// SearchTagValueStats counts the values of a tag in the ingesters and estimates their cardinality
+func (q *Querier) SearchTagValueStats(ctx context.Context, req *tempopb.SearchTagValuesRequest) (*tempopb.SearchTagValueStatsResponse, error) {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.SearchTagValueStats")
+	}
+
+	limit := q.limits.MaxBytesPerTagValuesQuery(userID)
+	stats := search.NewTagValueStatsCombiner(limit)
+
+	// Get results from all ingesters
+	replicationSet, err := q.ingesterRing.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding ingesters in Querier.SearchTagValueStats")
+	}
+	lookupResults, err := q.forGivenIngesters(ctx, replicationSet, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+		return client.SearchTagValueStats(ctx, req)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying ingesters in Querier.SearchTagValueStats")
+	}
+	for _, resp := range lookupResults {
+		err = stats.Combine(resp.response.(*tempopb.SearchTagValueStatsResponse))
+		if err != nil {
+			return nil, errors.Wrap(err, "error combining ingester responses in Querier.SearchTagValueStats")
+		}
+	}
+
+	if stats.Exceeded() {
+		level.Warn(log.Logger).Log("msg", "size of tag values in instance exceeded limit, reduce cardinality or size of tags", "tag", req.TagName, "userID", userID, "limit", limit)
+	}
+
+	// every span is pushed to several ingesters
+	resp := stats.Response(0)
+	q.scaleReplicatedCounts(resp)
+
+	return resp, nil
+}
+
+// scaleReplicatedCounts scales the counts of values that are stored once per replica back to an
+// approximate number of occurrences
+func (q *Querier) scaleReplicatedCounts(resp *tempopb.SearchTagValueStatsResponse) {
+	if rf := uint64(q.ingesterRing.ReplicationFactor()); rf > 1 {
+		for _, v := range resp.Values {
+			v.Count = (v.Count + rf - 1) / rf
+		}
+	}
+}
+
 func (q *Querier) SpanMetricsSummary(
 	ctx context.Context,
 	req *tempopb.SpanMetricsSummaryRequest,
@@ -700,6 +952,176 @@ func valuesToV2Response(distinctValues *util.DistinctValueCollector[tempopb.TagV
 	return resp
 }
 
+// SearchTagsBlock searches a backend block for tag names. The results of all blocks are combined in the
+// query frontend.
+func (q *Querier) SearchTagsBlock(ctx context.Context, req *tempopb.SearchTagsRequest, blockReq *tempopb.SearchBlockRequest) (*tempopb.SearchTagsResponse, error) {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.SearchTagsBlock")
+	}
+
+	// the intrinsic scope is not stored in the blocks
+	if req.Scope == api.ParamScopeIntrinsic {
+		return &tempopb.SearchTagsResponse{
+			TagNames: search.GetVirtualIntrinsicValues(),
+		}, nil
+	}
+
+	meta, err := blockMetaFromRequest(userID, blockReq)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := q.limits.MaxBytesPerTagValuesQuery(userID)
+	distinctValues := util.NewDistinctStringCollector(limit)
+
+	err = q.store.SearchTags(ctx, meta, traceql.AttributeScopeFromString(req.Scope), distinctValues.Collect, common.DefaultSearchOptions())
+	if err != nil && err != common.ErrUnsupported {
+		return nil, errors.Wrap(err, "error searching block in Querier.SearchTagsBlock")
+	}
+
+	return &tempopb.SearchTagsResponse{
+		TagNames: distinctValues.Strings(),
+	}, nil
+}
+
+// SearchTagsBlockV2 searches a backend block for the tag names of each scope.
+func (q *Querier) SearchTagsBlockV2(ctx context.Context, req *tempopb.SearchTagsRequest, blockReq *tempopb.SearchBlockRequest) (*tempopb.SearchTagsV2Response, error) {
+	scopes := []string{req.Scope}
+	if req.Scope == "" {
+		atts := traceql.AllAttributeScopes()
+		scopes = make([]string, 0, len(atts)+1) // +1 for intrinsic
+
+		scopes = append(scopes, api.ParamScopeIntrinsic)
+		for _, att := range atts {
+			scopes = append(scopes, att.String())
+		}
+	}
+
+	resp := &tempopb.SearchTagsV2Response{}
+	for _, scope := range scopes {
+		scopeResp, err := q.SearchTagsBlock(ctx, &tempopb.SearchTagsRequest{Scope: scope}, blockReq)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Scopes = append(resp.Scopes, &tempopb.SearchTagsV2Scope{
+			Name: scope,
+			Tags: scopeResp.TagNames,
+		})
+	}
+
+	return resp, nil
+}
+
+// SearchTagValuesBlock searches a backend block for the values of a tag.
+func (q *Querier) SearchTagValuesBlock(ctx context.Context, req *tempopb.SearchTagValuesRequest, blockReq *tempopb.SearchBlockRequest) (*tempopb.SearchTagValuesResponse, error) {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.SearchTagValuesBlock")
+	}
+
+	meta, err := blockMetaFromRequest(userID, blockReq)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := q.limits.MaxBytesPerTagValuesQuery(userID)
+	distinctValues := util.NewDistinctStringCollector(limit)
+
+	// Virtual tags values. Get these first.
+	for _, v := range search.GetVirtualTagValues(req.TagName) {
+		distinctValues.Collect(v)
+	}
+
+	err = q.store.SearchTagValues(ctx, meta, req.TagName, distinctValues.Collect, common.DefaultSearchOptions())
+	if err != nil && err != common.ErrUnsupported {
+		return nil, errors.Wrap(err, "error searching block in Querier.SearchTagValuesBlock")
+	}
+
+	return &tempopb.SearchTagValuesResponse{
+		TagValues: distinctValues.Strings(),
+	}, nil
+}
+
+// SearchTagValuesBlockV2 searches a backend block for the typed values of a tag.
+func (q *Querier) SearchTagValuesBlockV2(ctx context.Context, req *tempopb.SearchTagValuesRequest, blockReq *tempopb.SearchBlockRequest) (*tempopb.SearchTagValuesV2Response, error) {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.SearchTagValuesBlockV2")
+	}
+
+	limit := q.limits.MaxBytesPerTagValuesQuery(userID)
+	distinctValues := util.NewDistinctValueCollector(limit, func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) })
+
+	// virtual tag values are complete, there's no need to search the block
+	for _, v := range search.GetVirtualTagValuesV2(req.TagName) {
+		distinctValues.Collect(v)
+	}
+	if distinctValues.TotalDataSize() > 0 {
+		return valuesToV2Response(distinctValues), nil
+	}
+
+	tag, err := traceql.ParseIdentifier(req.TagName)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := blockMetaFromRequest(userID, blockReq)
+	if err != nil {
+		return nil, err
+	}
+
+	cb := func(v traceql.Static) bool {
+		return distinctValues.Collect(search.TagValueFromStatic(v))
+	}
+
+	err = q.store.SearchTagValuesV2(ctx, meta, tag, cb, common.DefaultSearchOptions())
+	if err != nil && err != common.ErrUnsupported {
+		return nil, errors.Wrap(err, "error searching block in Querier.SearchTagValuesBlockV2")
+	}
+
+	return valuesToV2Response(distinctValues), nil
+}
+
+// SearchTagValueStatsBlock counts the values of a tag in a backend block and estimates their cardinality.
+func (q *Querier) SearchTagValueStatsBlock(ctx context.Context, req *tempopb.SearchTagValuesRequest, blockReq *tempopb.SearchBlockRequest) (*tempopb.SearchTagValueStatsResponse, error) {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.SearchTagValueStatsBlock")
+	}
+
+	tag, err := traceql.ParseIdentifier(req.TagName)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := blockMetaFromRequest(userID, blockReq)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := search.NewTagValueStatsCombiner(q.limits.MaxBytesPerTagValuesQuery(userID))
+	cb := func(v traceql.Static, count uint64) bool {
+		stats.Collect(search.TagValueFromStatic(v), count)
+		return false
+	}
+
+	err = q.store.SearchTagValueCounts(ctx, meta, tag, cb, common.DefaultSearchOptions())
+	if err != nil && err != common.ErrUnsupported {
+		return nil, errors.Wrap(err, "error searching block in Querier.SearchTagValueStatsBlock")
+	}
+
+	// level 0 blocks are flushed by every ingester that received the trace and are only deduplicated
+	// by compaction
+	resp := stats.Response(0)
+	if blockReq.CompactionLevel == 0 {
+		q.scaleReplicatedCounts(resp)
+	}
+
+	return resp, nil
+}
+
 // SearchBlock searches the specified subset of the block for the passed tags.
 func (q *Querier) SearchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
 	// if we have no external configuration always search in the querier
@@ -730,6 +1152,106 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 		return nil, errors.Wrap(err, "error extracting org id in Querier.BackendSearch")
 	}
 
+	meta, err := blockMetaFromRequest(tenantID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := common.DefaultSearchOptions()
+	opts.StartPage = int(req.StartPage)
+	opts.TotalPages = int(req.PagesToSearch)
+	opts.MaxBytes = q.limits.MaxBytesPerTrace(tenantID)
+
+	if api.IsTraceQLQuery(req.SearchReq) {
+		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+			return q.store.Fetch(ctx, meta, req, opts)
+		})
+
+		return q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
+	}
+
+	return q.store.Search(ctx, meta, req.SearchReq, opts)
+}
+
+// QueryRangeRecent evaluates a TraceQL metrics query on the ingesters. The returned series hold raw counts
+// and are combined in the query frontend.
+func (q *Querier) QueryRangeRecent(ctx context.Context, req *traceqlmetrics.QueryRangeRequest) ([]*prompb.TimeSeries, error) {
+	_, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.QueryRangeRecent")
+	}
+
+	replicationSet, err := q.ingesterRing.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding ingesters in Querier.QueryRangeRecent")
+	}
+
+	protoReq := req.Proto()
+	lookupResults, err := q.forGivenIngesters(ctx, replicationSet, func(ctx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+		return client.QueryRange(ctx, protoReq)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying ingesters in Querier.QueryRangeRecent")
+	}
+
+	combiner := traceqlmetrics.NewRawSeriesCombiner()
+	for _, resp := range lookupResults {
+		combiner.Combine(traceqlmetrics.SeriesFromProto(resp.response.(*tempopb.QueryRangeResponse).Series))
+	}
+
+	// every span is pushed to several ingesters
+	series := combiner.Results()
+	q.scaleReplicatedSeries(series)
+
+	return series, nil
+}
+
+// QueryRange evaluates a TraceQL metrics query on a range of pages of a backend block. The returned
+// series hold raw counts and are combined in the query frontend.
+func (q *Querier) QueryRange(ctx context.Context, req *traceqlmetrics.QueryRangeRequest, blockReq *tempopb.SearchBlockRequest) ([]*prompb.TimeSeries, error) {
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.QueryRange")
+	}
+
+	meta, err := blockMetaFromRequest(tenantID, blockReq)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := common.DefaultSearchOptions()
+	opts.StartPage = int(blockReq.StartPage)
+	opts.TotalPages = int(blockReq.PagesToSearch)
+	opts.MaxBytes = q.limits.MaxBytesPerTrace(tenantID)
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return q.store.Fetch(ctx, meta, req, opts)
+	})
+
+	series, err := traceqlmetrics.QueryRange(ctx, req, fetcher)
+	if err != nil {
+		return nil, err
+	}
+
+	// level 0 blocks are flushed by every ingester that received the trace and are only deduplicated
+	// by compaction
+	if blockReq.CompactionLevel == 0 {
+		q.scaleReplicatedSeries(series)
+	}
+
+	return series, nil
+}
+
+// scaleReplicatedSeries scales the counts of data that is stored once per replica back to a number
+// of spans
+func (q *Querier) scaleReplicatedSeries(series []*prompb.TimeSeries) {
+	if rf := q.ingesterRing.ReplicationFactor(); rf > 1 {
+		traceqlmetrics.ScaleSeries(series, 1/float64(rf))
+	}
+}
+
+// blockMetaFromRequest builds the meta of the block identified by a block request
+func blockMetaFromRequest(tenantID string, req *tempopb.SearchBlockRequest) (*backend.BlockMeta, error) {
 	blockID, err := uuid.Parse(req.BlockID)
 	if err != nil {
 		return nil, err
@@ -740,7 +1262,7 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 		return nil, err
 	}
 
-	meta := &backend.BlockMeta{
+	return &backend.BlockMeta{
 		Version:       req.Version,
 		TenantID:      tenantID,
 		Encoding:      enc,
@@ -750,22 +1272,7 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 		BlockID:       blockID,
 		DataEncoding:  req.DataEncoding,
 		FooterSize:    req.FooterSize,
-	}
-
-	opts := common.DefaultSearchOptions()
-	opts.StartPage = int(req.StartPage)
-	opts.TotalPages = int(req.PagesToSearch)
-	opts.MaxBytes = q.limits.MaxBytesPerTrace(tenantID)
-
-	if api.IsTraceQLQuery(req.SearchReq) {
-		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
-			return q.store.Fetch(ctx, meta, req, opts)
-		})
-
-		return q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
-	}
-
-	return q.store.Search(ctx, meta, req.SearchReq, opts)
+	}, nil
 }
 
 func (q *Querier) postProcessIngesterSearchResults(req *tempopb.SearchRequest, rr []responseFromIngesters) *tempopb.SearchResponse {
@@ -797,12 +1304,7 @@ func (q *Querier) postProcessIngesterSearchResults(req *tempopb.SearchRequest, r
 	}
 
 	// Sort and limit results
-	sort.Slice(response.Traces, func(i, j int) bool {
-		return response.Traces[i].StartTimeUnixNano > response.Traces[j].StartTimeUnixNano
-	})
-	if req.Limit != 0 && int(req.Limit) < len(response.Traces) {
-		response.Traces = response.Traces[:req.Limit]
-	}
+	response.Traces = traceql.TopTraces(response.Traces, traceql.SearchRequestOrder(req, traceql.ParseSearch(req)), int(req.Limit))
 
 	return response
 }
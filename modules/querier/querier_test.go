@@ -2,15 +2,26 @@ package querier
 
 import (
 	"context"
+	"errors"
+	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	protoio "github.com/gogo/protobuf/io"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/mux"
 	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/modules/storage"
+	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/atomic"
 	"github.com/weaveworks/common/user"
@@ -79,3 +90,128 @@ func TestQuerierUsesSearchExternalEndpoint(t *testing.T) {
 		require.Equal(t, tc.externalExpected, numExternalRequests.Load())
 	}
 }
+
+// mockStore finds the parts of a trace in its blocks, the other methods of the store aren't implemented
+type mockStore struct {
+	storage.Store
+	parts []*tempopb.Trace
+	err   error
+}
+
+func (m *mockStore) FindFunc(_ context.Context, _ string, _ common.ID, _, _ string, _, _ int64, cb tempodb.FindCallback) ([]error, error) {
+	for _, p := range m.parts {
+		if err := cb(proto.Clone(p).(*tempopb.Trace)); err != nil {
+			return nil, err
+		}
+	}
+	if m.err != nil {
+		return []error{m.err}, nil
+	}
+	return nil, nil
+}
+
+func TestTraceByIDStreamHandler(t *testing.T) {
+	tr := test.MakeTrace(10, []byte{0x0a})
+
+	tests := []struct {
+		name           string
+		store          *mockStore
+		expectedCode   int
+		expectedStatus tempopb.PartialStatus
+	}{
+		{
+			name: "parts of every block are streamed",
+			store: &mockStore{parts: []*tempopb.Trace{
+				{Batches: tr.Batches[:6]},
+				{Batches: tr.Batches[4:]},
+			}},
+			expectedCode:   http.StatusOK,
+			expectedStatus: tempopb.PartialStatus_COMPLETE,
+		},
+		{
+			name: "errors after the first part end the stream with a partial message",
+			store: &mockStore{
+				parts: []*tempopb.Trace{tr},
+				err:   errors.New("block failed"),
+			},
+			expectedCode:   http.StatusOK,
+			expectedStatus: tempopb.PartialStatus_PARTIAL,
+		},
+		{
+			name:         "errors before the first part are returned",
+			store:        &mockStore{err: errors.New("block failed")},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "trace not found",
+			store:        &mockStore{},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := overrides.NewOverrides(overrides.Limits{})
+			require.NoError(t, err)
+
+			q, err := New(Config{TraceByID: TraceByIDConfig{QueryTimeout: time.Minute}}, ingester_client.Config{}, nil, generator_client.Config{}, nil, tc.store, o)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("GET", "/api/traces/0a/stream?mode=blocks", nil)
+			req = mux.SetURLVars(req, map[string]string{api.URLParamTraceID: "0a"})
+			req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+			w := httptest.NewRecorder()
+
+			q.TraceByIDStreamHandler(w, req)
+			require.Equal(t, tc.expectedCode, w.Code)
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+			require.True(t, w.Flushed)
+
+			var msgs []*tempopb.TraceByIDResponse
+			reader := protoio.NewDelimitedReader(w.Body, math.MaxInt32)
+			for {
+				msg := &tempopb.TraceByIDResponse{}
+				if err := reader.ReadMsg(msg); errors.Is(err, io.EOF) {
+					break
+				} else {
+					require.NoError(t, err)
+				}
+				msgs = append(msgs, msg)
+			}
+
+			// every span is sent once and the last message ends the stream
+			require.Greater(t, len(msgs), 1)
+			var expected, sent []string
+			for _, b := range tr.Batches {
+				for _, ss := range b.ScopeSpans {
+					for _, s := range ss.Spans {
+						expected = append(expected, string(s.SpanId))
+					}
+				}
+			}
+			for _, msg := range msgs[:len(msgs)-1] {
+				require.NotNil(t, msg.Trace)
+				for _, b := range msg.Trace.Batches {
+					for _, ss := range b.ScopeSpans {
+						for _, s := range ss.Spans {
+							sent = append(sent, string(s.SpanId))
+						}
+					}
+				}
+			}
+			require.ElementsMatch(t, expected, sent)
+
+			final := msgs[len(msgs)-1]
+			require.Nil(t, final.Trace)
+			require.Equal(t, tc.expectedStatus, final.Status)
+			if tc.expectedStatus == tempopb.PartialStatus_PARTIAL {
+				require.Equal(t, "block failed", final.Message)
+				require.Nil(t, final.Metrics)
+			} else {
+				require.NotNil(t, final.Metrics)
+			}
+		})
+	}
+}
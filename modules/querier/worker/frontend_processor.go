@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -22,6 +23,8 @@ var (
 		MinBackoff: 50 * time.Millisecond,
 		MaxBackoff: 1 * time.Second,
 	}
+
+	errResponseEnded = errors.New("the response already ended")
 )
 
 func newFrontendProcessor(cfg Config, handler RequestHandler, log log.Logger) processor {
@@ -95,10 +98,11 @@ func (fp *frontendProcessor) process(c frontendv1pb.Frontend_ProcessClient) erro
 			// and cancel the query.  We don't actually handle queries in parallel
 			// here, as we're running in lock step with the server - each Recv is
 			// paired with a Send.
-			go fp.runRequest(ctx, request.HttpRequest, request.StatsEnabled, func(response *httpgrpc.HTTPResponse, stats *stats.Stats) error {
+			go fp.runRequest(ctx, request.HttpRequest, request.StatsEnabled, request.StreamingEnabled, func(response *httpgrpc.HTTPResponse, stats *stats.Stats, more bool) error {
 				return c.Send(&frontendv1pb.ClientToFrontend{
 					HttpResponse: response,
 					Stats:        stats,
+					More:         more,
 				})
 			})
 
@@ -114,35 +118,94 @@ func (fp *frontendProcessor) process(c frontendv1pb.Frontend_ProcessClient) erro
 	}
 }
 
-func (fp *frontendProcessor) runRequest(ctx context.Context, request *httpgrpc.HTTPRequest, statsEnabled bool, sendHTTPResponse func(response *httpgrpc.HTTPResponse, stats *stats.Stats) error) {
+func (fp *frontendProcessor) runRequest(ctx context.Context, request *httpgrpc.HTTPRequest, statsEnabled, streamingEnabled bool, sendHTTPResponse func(response *httpgrpc.HTTPResponse, stats *stats.Stats, more bool) error) {
 	var stats *querier_stats.Stats
 	if statsEnabled {
 		stats, ctx = querier_stats.ContextWithEmptyStats(ctx)
 	}
 
+	if handler, ok := fp.handler.(StreamingRequestHandler); ok && streamingEnabled {
+		fp.runStreamingRequest(ctx, handler, request, stats, sendHTTPResponse)
+		return
+	}
+
 	response, err := fp.handler.Handle(ctx, request)
 	if err != nil {
-		var ok bool
-		response, ok = httpgrpc.HTTPResponseFromError(err)
-		if !ok {
-			response = &httpgrpc.HTTPResponse{
-				Code: http.StatusInternalServerError,
-				Body: []byte(err.Error()),
-			}
-		}
+		response = errorResponse(err)
 	}
 
 	// Ensure responses that are too big are not retried.
 	if len(response.Body) >= fp.maxMessageSize {
-		errMsg := fmt.Sprintf("response larger than the max (%d vs %d)", len(response.Body), fp.maxMessageSize)
-		response = &httpgrpc.HTTPResponse{
-			Code: http.StatusRequestEntityTooLarge,
-			Body: []byte(errMsg),
+		response = fp.tooLargeResponse(response)
+	}
+
+	if err := sendHTTPResponse(response, stats, false); err != nil {
+		level.Error(fp.log).Log("msg", "error processing requests", "err", err)
+	}
+}
+
+// runStreamingRequest sends the response in the parts the handler streams. Stats are sent with the last part.
+// If a part after the first one is too big, the response ends without it and the following parts, the
+// format of a streamed body has to tell if it's complete.
+func (fp *frontendProcessor) runStreamingRequest(ctx context.Context, handler StreamingRequestHandler, request *httpgrpc.HTTPRequest, stats *querier_stats.Stats, sendHTTPResponse func(response *httpgrpc.HTTPResponse, stats *stats.Stats, more bool) error) {
+	first, done := true, false
+	send := func(part *httpgrpc.HTTPResponse, more bool) error {
+		if done {
+			return errResponseEnded
+		}
+
+		if len(part.Body) >= fp.maxMessageSize {
+			if first {
+				part = fp.tooLargeResponse(part)
+			} else {
+				part = &httpgrpc.HTTPResponse{}
+			}
+			more = false
 		}
-		level.Error(fp.log).Log("msg", "error processing query", "err", errMsg)
+		first = false
+		done = !more
+
+		var partStats *querier_stats.Stats
+		if !more {
+			partStats = stats
+		}
+		return sendHTTPResponse(part, partStats, more)
 	}
 
-	if err := sendHTTPResponse(response, stats); err != nil {
+	err := handler.HandleStream(ctx, request, send)
+	if done {
+		if err != nil && err != errResponseEnded {
+			level.Error(fp.log).Log("msg", "error processing requests", "err", err)
+		}
+		return
+	}
+
+	// the handler failed before the last part was sent
+	response := &httpgrpc.HTTPResponse{}
+	if first {
+		response = errorResponse(err)
+	}
+	if err := sendHTTPResponse(response, stats, false); err != nil {
 		level.Error(fp.log).Log("msg", "error processing requests", "err", err)
 	}
 }
+
+func errorResponse(err error) *httpgrpc.HTTPResponse {
+	response, ok := httpgrpc.HTTPResponseFromError(err)
+	if !ok {
+		response = &httpgrpc.HTTPResponse{
+			Code: http.StatusInternalServerError,
+			Body: []byte(err.Error()),
+		}
+	}
+	return response
+}
+
+func (fp *frontendProcessor) tooLargeResponse(response *httpgrpc.HTTPResponse) *httpgrpc.HTTPResponse {
+	errMsg := fmt.Sprintf("response larger than the max (%d vs %d)", len(response.Body), fp.maxMessageSize)
+	level.Error(fp.log).Log("msg", "error processing query", "err", errMsg)
+	return &httpgrpc.HTTPResponse{
+		Code: http.StatusRequestEntityTooLarge,
+		Body: []byte(errMsg),
+	}
+}
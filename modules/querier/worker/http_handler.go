@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/httpgrpc/server"
+)
+
+// NewHTTPHandler returns a StreamingRequestHandler that serves requests with the http.Handler. Responses of
+// handlers that flush are streamed, every flush sends the body written since the previous one.
+func NewHTTPHandler(handler http.Handler) StreamingRequestHandler {
+	return &httpHandler{
+		Server:  server.NewServer(handler),
+		handler: handler,
+	}
+}
+
+type httpHandler struct {
+	*server.Server
+	handler http.Handler
+}
+
+func (h *httpHandler) HandleStream(ctx context.Context, r *httpgrpc.HTTPRequest, send func(part *httpgrpc.HTTPResponse, more bool) error) error {
+	req, err := http.NewRequest(r.Method, r.Url, bytes.NewReader(r.Body))
+	if err != nil {
+		return err
+	}
+	for _, h := range r.Headers {
+		req.Header[h.Key] = h.Values
+	}
+	req = req.WithContext(ctx)
+	req.RequestURI = r.Url
+	req.ContentLength = int64(len(r.Body))
+
+	w := &streamingResponseWriter{
+		header: http.Header{},
+		send:   send,
+	}
+	h.handler.ServeHTTP(w, req)
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.sendPart(false)
+}
+
+// streamingResponseWriter sends the response in a part on every flush. The first part contains the status
+// code and headers.
+type streamingResponseWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+
+	send func(part *httpgrpc.HTTPResponse, more bool) error
+	sent bool
+	// err of a failed part, the remaining writes fail with it
+	err error
+}
+
+func (w *streamingResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *streamingResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *streamingResponseWriter) Write(b []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+func (w *streamingResponseWriter) Flush() {
+	if w.err != nil {
+		return
+	}
+	w.err = w.sendPart(true)
+}
+
+func (w *streamingResponseWriter) sendPart(more bool) error {
+	part := &httpgrpc.HTTPResponse{
+		Body: bytes.Clone(w.body.Bytes()),
+	}
+	w.body.Reset()
+
+	if !w.sent {
+		w.WriteHeader(http.StatusOK)
+		part.Code = int32(w.code)
+		for k, v := range w.header {
+			part.Headers = append(part.Headers, &httpgrpc.Header{Key: k, Values: v})
+		}
+		w.sent = true
+	}
+
+	return w.send(part, more)
+}
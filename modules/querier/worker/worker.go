@@ -56,6 +56,13 @@ type RequestHandler interface {
 	Handle(context.Context, *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error)
 }
 
+// StreamingRequestHandler is a RequestHandler that can also stream responses in several parts. Parts after the
+// first one only contain the next part of the body.
+type StreamingRequestHandler interface {
+	RequestHandler
+	HandleStream(ctx context.Context, req *httpgrpc.HTTPRequest, send func(part *httpgrpc.HTTPResponse, more bool) error) error
+}
+
 // Single processor handles all streaming operations to query-frontend or query-scheduler to fetch queries
 // and process them.
 type processor interface {
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/traceqlmetrics"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -22,6 +25,8 @@ import (
 
 const (
 	URLParamTraceID = "traceID"
+	// trace diff
+	URLParamCompareTraceID = "compareTraceID"
 	// search
 	urlParamQuery           = "q"
 	urlParamTags            = "tags"
@@ -31,6 +36,11 @@ const (
 	urlParamStart           = "start"
 	urlParamEnd             = "end"
 	urlParamSpansPerSpanSet = "spss"
+	urlParamPaginate        = "paginate"
+	urlParamPageToken       = "pageToken"
+	// cursor of the jobs of a paginated search (querier/serverless)
+	urlParamAfterStartTime = "afterStartTime"
+	urlParamAfterTraceID   = "afterTraceID"
 
 	// backend search (querier/serverless)
 	urlParamStartPage     = "startPage"
@@ -43,6 +53,8 @@ const (
 	urlParamVersion       = "version"
 	urlParamSize          = "size"
 	urlParamFooterSize    = "footerSize"
+	// urlParamCompactionLevel is optional and defaults to 0
+	urlParamCompactionLevel = "compactionLevel"
 
 	// maxBytes (serverless only)
 	urlParamMaxBytes = "maxBytes"
@@ -53,26 +65,47 @@ const (
 	// generator summary
 	urlParamGroupBy = "groupBy"
 
+	// query range
+	urlParamPromQuery = "query" // prometheus compatible alternative to q
+	urlParamStep      = "step"
+
 	HeaderAccept         = "Accept"
 	HeaderContentType    = "Content-Type"
 	HeaderAcceptProtobuf = "application/protobuf"
 	HeaderAcceptJSON     = "application/json"
+	// HeaderAcceptOTLPProtobuf is the content type of the OTLP/HTTP protobuf encoding
+	HeaderAcceptOTLPProtobuf = "application/x-protobuf"
+	// HeaderAcceptOTLPJSON requests the OTLP/JSON encoding, which differs from the json encoding of Tempo's protos
+	HeaderAcceptOTLPJSON = "application/otlp+json"
 
 	PathPrefixQuerier   = "/querier"
 	PathPrefixGenerator = "/generator"
 
 	PathTraces             = "/api/traces/{traceID}"
+	PathTracesByID         = "/api/traces"
 	PathSearch             = "/api/search"
 	PathSearchTags         = "/api/search/tags"
+	PathSearchExplain      = "/api/search/explain"
 	PathSearchTagValues    = "/api/search/tag/{" + muxVarTagName + "}/values"
 	PathEcho               = "/api/echo"
 	PathUsageStats         = "/status/usage-stats"
 	PathSpanMetrics        = "/api/metrics"
 	PathSpanMetricsSummary = "/api/metrics/summary"
+	PathQueryRange         = "/api/metrics/query_range"
 
 	PathSearchTagValuesV2 = "/api/v2/search/tag/{" + muxVarTagName + "}/values"
 	PathSearchTagsV2      = "/api/v2/search/tags"
 
+	PathSearchTagValueStats = "/api/v2/search/tag/{" + muxVarTagName + "}/stats"
+
+	PathTracesV2 = "/api/v2/traces/{traceID}"
+
+	PathTraceCriticalPath = "/api/traces/{traceID}/critical-path"
+	PathTraceDiff         = "/api/traces/{traceID}/diff/{" + URLParamCompareTraceID + "}"
+	PathTraceSummary      = "/api/traces/{traceID}/summary"
+	// PathTraceStream is only served by the queriers, it streams the parts of a trace to the query frontend
+	PathTraceStream = "/api/traces/{traceID}/stream"
+
 	QueryModeKey       = "mode"
 	QueryModeIngesters = "ingesters"
 	QueryModeBlocks    = "blocks"
@@ -82,6 +115,9 @@ const (
 
 	defaultLimit           = 20
 	defaultSpansPerSpanSet = 3
+
+	// defaultQueryRangeIntervals is used to compute the step if none is given
+	defaultQueryRangeIntervals = 100
 )
 
 func ParseTraceID(r *http.Request) ([]byte, error) {
@@ -101,6 +137,13 @@ func ParseTraceID(r *http.Request) ([]byte, error) {
 
 // ParseSearchRequest takes an http.Request and decodes query params to create a tempopb.SearchRequest
 func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
+	req, _, err := ParseSearchRequestWithExpr(r)
+	return req, err
+}
+
+// ParseSearchRequestWithExpr is ParseSearchRequest that also returns the parsed TraceQL query, so callers
+// don't have to parse it again. The expression is nil if the search has no query or the query is {}.
+func ParseSearchRequestWithExpr(r *http.Request) (*tempopb.SearchRequest, *traceql.RootExpr, error) {
 	req := &tempopb.SearchRequest{
 		Tags:            map[string]string{},
 		Limit:           defaultLimit,
@@ -110,7 +153,7 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	if s, ok := extractQueryParam(r, urlParamStart); ok {
 		start, err := strconv.ParseInt(s, 10, 32)
 		if err != nil {
-			return nil, fmt.Errorf("invalid start: %w", err)
+			return nil, nil, fmt.Errorf("invalid start: %w", err)
 		}
 		req.Start = uint32(start)
 	}
@@ -118,18 +161,20 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	if s, ok := extractQueryParam(r, urlParamEnd); ok {
 		end, err := strconv.ParseInt(s, 10, 32)
 		if err != nil {
-			return nil, fmt.Errorf("invalid end: %w", err)
+			return nil, nil, fmt.Errorf("invalid end: %w", err)
 		}
 		req.End = uint32(end)
 	}
 
+	var expr *traceql.RootExpr
 	query, queryFound := extractQueryParam(r, urlParamQuery)
 	if queryFound {
 		// TODO hacky fix: we don't validate {} since this isn't handled correctly yet
 		if query != "{}" {
-			_, err := traceql.Parse(query)
+			var err error
+			expr, err = traceql.ParseAndValidateSearch(query)
 			if err != nil {
-				return nil, fmt.Errorf("invalid TraceQL query: %w", err)
+				return nil, nil, fmt.Errorf("invalid TraceQL query: %w", err)
 			}
 		}
 		req.Query = query
@@ -139,7 +184,7 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	if tagsFound {
 		// tags and traceQL API are mutually exclusive
 		if queryFound {
-			return nil, fmt.Errorf("invalid request: can't specify tags and q in the same query")
+			return nil, nil, fmt.Errorf("invalid request: can't specify tags and q in the same query")
 		}
 
 		decoder := logfmt.NewDecoder(strings.NewReader(encodedTags))
@@ -148,7 +193,7 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 			for decoder.ScanKeyval() {
 				key := string(decoder.Key())
 				if _, ok := req.Tags[key]; ok {
-					return nil, fmt.Errorf("invalid tags: tag %s has been set twice", key)
+					return nil, nil, fmt.Errorf("invalid tags: tag %s has been set twice", key)
 				}
 				req.Tags[key] = string(decoder.Value())
 			}
@@ -156,9 +201,9 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 
 		if err := decoder.Err(); err != nil {
 			if syntaxErr, ok := err.(*logfmt.SyntaxError); ok {
-				return nil, fmt.Errorf("invalid tags: %s at pos %d", syntaxErr.Msg, syntaxErr.Pos)
+				return nil, nil, fmt.Errorf("invalid tags: %s at pos %d", syntaxErr.Msg, syntaxErr.Pos)
 			}
-			return nil, fmt.Errorf("invalid tags: %w", err)
+			return nil, nil, fmt.Errorf("invalid tags: %w", err)
 		}
 	}
 
@@ -171,7 +216,8 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 		// As Grafana gets updated and/or versions using this get old we can remove this section.
 		for k, v := range r.URL.Query() {
 			// Skip reserved keywords
-			if k == urlParamQuery || k == urlParamTags || k == urlParamMinDuration || k == urlParamMaxDuration || k == urlParamLimit || k == urlParamSpansPerSpanSet {
+			if k == urlParamQuery || k == urlParamTags || k == urlParamMinDuration || k == urlParamMaxDuration || k == urlParamLimit || k == urlParamSpansPerSpanSet ||
+				k == urlParamPaginate || k == urlParamPageToken || k == urlParamAfterStartTime || k == urlParamAfterTraceID {
 				continue
 			}
 
@@ -184,7 +230,7 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	if s, ok := extractQueryParam(r, urlParamMinDuration); ok {
 		dur, err := time.ParseDuration(s)
 		if err != nil {
-			return nil, fmt.Errorf("invalid minDuration: %w", err)
+			return nil, nil, fmt.Errorf("invalid minDuration: %w", err)
 		}
 		req.MinDurationMs = uint32(dur.Milliseconds())
 	}
@@ -192,47 +238,121 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	if s, ok := extractQueryParam(r, urlParamMaxDuration); ok {
 		dur, err := time.ParseDuration(s)
 		if err != nil {
-			return nil, fmt.Errorf("invalid maxDuration: %w", err)
+			return nil, nil, fmt.Errorf("invalid maxDuration: %w", err)
 		}
 		req.MaxDurationMs = uint32(dur.Milliseconds())
 
 		if req.MinDurationMs != 0 && req.MinDurationMs > req.MaxDurationMs {
-			return nil, errors.New("invalid maxDuration: must be greater than minDuration")
+			return nil, nil, errors.New("invalid maxDuration: must be greater than minDuration")
 		}
 	}
 
 	if s, ok := extractQueryParam(r, urlParamLimit); ok {
 		limit, err := strconv.Atoi(s)
 		if err != nil {
-			return nil, fmt.Errorf("invalid limit: %w", err)
+			return nil, nil, fmt.Errorf("invalid limit: %w", err)
 		}
 		if limit <= 0 {
-			return nil, errors.New("invalid limit: must be a positive number")
+			return nil, nil, errors.New("invalid limit: must be a positive number")
 		}
 		req.Limit = uint32(limit)
 	}
 
+	// a limit stage in the query takes precedence over the limit parameter
+	if expr != nil && expr.Limit > 0 {
+		req.Limit = uint32(expr.Limit)
+	}
+
 	if s, ok := extractQueryParam(r, urlParamSpansPerSpanSet); ok {
 		spansPerSpanSet, err := strconv.Atoi(s)
 		if err != nil {
-			return nil, fmt.Errorf("invalid spss: %w", err)
+			return nil, nil, fmt.Errorf("invalid spss: %w", err)
 		}
 		if spansPerSpanSet <= 0 {
-			return nil, errors.New("invalid spss: must be a positive number")
+			return nil, nil, errors.New("invalid spss: must be a positive number")
 		}
 		req.SpansPerSpanSet = uint32(spansPerSpanSet)
 	}
 
+	// hints in the query take precedence over the spss parameter
+	if spss, ok := expr.SearchHints().GetInt(traceql.HintSpss); ok {
+		req.SpansPerSpanSet = uint32(spss)
+	}
+
+	if s, ok := extractQueryParam(r, urlParamPaginate); ok {
+		paginated, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid paginate: %w", err)
+		}
+		req.Paginated = paginated
+	}
+
+	if s, ok := extractQueryParam(r, urlParamAfterStartTime); ok {
+		afterStartTime, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid afterStartTime: %w", err)
+		}
+		req.AfterStartTimeUnixNano = afterStartTime
+	}
+
+	if s, ok := extractQueryParam(r, urlParamAfterTraceID); ok {
+		req.AfterTraceID = s
+	}
+
 	// start and end == 0 is fine
 	if req.End == 0 && req.Start == 0 {
-		return req, nil
+		return req, expr, nil
 	}
 
 	// if start or end are non-zero do some checks
 	if req.End <= req.Start {
-		return nil, fmt.Errorf("http parameter start must be before end. received start=%d end=%d", req.Start, req.End)
+		return nil, nil, fmt.Errorf("http parameter start must be before end. received start=%d end=%d", req.Start, req.End)
 	}
-	return req, nil
+	return req, expr, nil
+}
+
+// ErrorResponse is the JSON body the search endpoints return for invalid requests. The position, token
+// and suggestion fields are only set for TraceQL syntax errors.
+type ErrorResponse struct {
+	Error       string   `json:"error"`
+	Line        int      `json:"line,omitempty"`
+	Column      int      `json:"column,omitempty"`
+	EndColumn   int      `json:"endColumn,omitempty"`
+	Token       string   `json:"token,omitempty"`
+	Expected    []string `json:"expected,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// NewErrorResponse returns the ErrorResponse for err
+func NewErrorResponse(err error) *ErrorResponse {
+	resp := &ErrorResponse{
+		Error: err.Error(),
+	}
+
+	var parseErr traceql.ParseError
+	if errors.As(err, &parseErr) {
+		resp.Line = parseErr.Line()
+		resp.Column = parseErr.Column()
+		resp.EndColumn = parseErr.EndColumn()
+		resp.Token = parseErr.Token()
+		resp.Expected = parseErr.Expected()
+		resp.Suggestions = parseErr.Suggestions()
+	}
+
+	return resp
+}
+
+// WriteError writes err as a JSON ErrorResponse with the given status code
+func WriteError(w http.ResponseWriter, err error, statusCode int) {
+	body, marshalErr := json.Marshal(NewErrorResponse(err))
+	if marshalErr != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	w.Header().Set(HeaderContentType, HeaderAcceptJSON)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
 }
 
 // ParseSearchBlockRequest parses all http parameters necessary to perform a block search.
@@ -247,9 +367,18 @@ func ParseSearchBlockRequest(r *http.Request) (*tempopb.SearchBlockRequest, erro
 		return nil, errors.New("start and end required")
 	}
 
-	req := &tempopb.SearchBlockRequest{
-		SearchReq: searchReq,
+	req, err := parseBlockParams(r)
+	if err != nil {
+		return nil, err
 	}
+	req.SearchReq = searchReq
+
+	return req, nil
+}
+
+// parseBlockParams parses the http parameters that identify a range of pages of a block.
+func parseBlockParams(r *http.Request) (*tempopb.SearchBlockRequest, error) {
+	req := &tempopb.SearchBlockRequest{}
 
 	s := r.URL.Query().Get(urlParamStartPage)
 	startPage, err := strconv.ParseInt(s, 10, 32)
@@ -330,6 +459,14 @@ func ParseSearchBlockRequest(r *http.Request) (*tempopb.SearchBlockRequest, erro
 	}
 	req.FooterSize = uint32(footerSize)
 
+	if s := r.URL.Query().Get(urlParamCompactionLevel); s != "" {
+		compactionLevel, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid compactionLevel %s: %w", s, err)
+		}
+		req.CompactionLevel = uint32(compactionLevel)
+	}
+
 	return req, nil
 }
 
@@ -404,6 +541,13 @@ func BuildSearchRequest(req *http.Request, searchReq *tempopb.SearchRequest) (*h
 	if len(searchReq.Query) > 0 {
 		q.Set(urlParamQuery, searchReq.Query)
 	}
+	if searchReq.Paginated {
+		q.Set(urlParamPaginate, "true")
+	}
+	if searchReq.AfterTraceID != "" {
+		q.Set(urlParamAfterStartTime, strconv.FormatUint(searchReq.AfterStartTimeUnixNano, 10))
+		q.Set(urlParamAfterTraceID, searchReq.AfterTraceID)
+	}
 
 	if len(searchReq.Tags) > 0 {
 		builder := &strings.Builder{}
@@ -438,6 +582,11 @@ func BuildSearchBlockRequest(req *http.Request, searchReq *tempopb.SearchBlockRe
 		return nil, err
 	}
 
+	return buildBlockParams(req, searchReq), nil
+}
+
+// buildBlockParams adds the http parameters that identify a range of pages of a block.
+func buildBlockParams(req *http.Request, searchReq *tempopb.SearchBlockRequest) *http.Request {
 	q := req.URL.Query()
 	q.Set(urlParamSize, strconv.FormatUint(searchReq.Size_, 10))
 	q.Set(urlParamBlockID, searchReq.BlockID)
@@ -449,12 +598,161 @@ func BuildSearchBlockRequest(req *http.Request, searchReq *tempopb.SearchBlockRe
 	q.Set(urlParamDataEncoding, searchReq.DataEncoding)
 	q.Set(urlParamVersion, searchReq.Version)
 	q.Set(urlParamFooterSize, strconv.FormatUint(uint64(searchReq.FooterSize), 10))
+	if searchReq.CompactionLevel > 0 {
+		q.Set(urlParamCompactionLevel, strconv.FormatUint(uint64(searchReq.CompactionLevel), 10))
+	}
 
 	req.URL.RawQuery = q.Encode()
 
+	return req
+}
+
+// ParseQueryRangeRequest parses a TraceQL metrics range query. The parameters are compatible
+// with the Prometheus query_range api: start and end are unix epoch seconds or RFC3339 and step
+// is a duration or a number of seconds.
+func ParseQueryRangeRequest(r *http.Request) (*traceqlmetrics.QueryRangeRequest, error) {
+	req := &traceqlmetrics.QueryRangeRequest{}
+
+	query, ok := extractQueryParam(r, urlParamQuery)
+	if !ok {
+		query, ok = extractQueryParam(r, urlParamPromQuery)
+	}
+	if !ok {
+		return nil, errors.New("query required")
+	}
+	req.Query = query
+
+	s, ok := extractQueryParam(r, urlParamStart)
+	if !ok {
+		return nil, errors.New("start required")
+	}
+	start, err := parseTime(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+	req.Start = start
+
+	s, ok = extractQueryParam(r, urlParamEnd)
+	if !ok {
+		return nil, errors.New("end required")
+	}
+	end, err := parseTime(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end: %w", err)
+	}
+	req.End = end
+
+	if req.End <= req.Start {
+		return nil, fmt.Errorf("http parameter start must be before end. received start=%s end=%s", r.URL.Query().Get(urlParamStart), r.URL.Query().Get(urlParamEnd))
+	}
+
+	if s, ok := extractQueryParam(r, urlParamStep); ok {
+		step, err := parseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step: %w", err)
+		}
+		if step <= 0 {
+			return nil, errors.New("invalid step: must be a positive duration")
+		}
+		req.Step = uint64(step)
+	} else {
+		req.Step = defaultQueryRangeStep(req.Start, req.End)
+	}
+
 	return req, nil
 }
 
+// ParseQueryRangeBlockRequest parses a range query for a range of pages of a block.
+func ParseQueryRangeBlockRequest(r *http.Request) (*traceqlmetrics.QueryRangeRequest, *tempopb.SearchBlockRequest, error) {
+	req, err := ParseQueryRangeRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blockReq, err := parseBlockParams(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return req, blockReq, nil
+}
+
+// BuildQueryRangeRequest takes a traceqlmetrics.QueryRangeRequest and populates the passed http.Request
+// with the appropriate params. If no http.Request is provided a new one is created. Times are
+// passed with nanosecond precision so all shards use exactly the same intervals.
+func BuildQueryRangeRequest(req *http.Request, queryRangeReq *traceqlmetrics.QueryRangeRequest) *http.Request {
+	if req == nil {
+		req = &http.Request{
+			URL: &url.URL{},
+		}
+	}
+
+	if queryRangeReq == nil {
+		return req
+	}
+
+	q := req.URL.Query()
+	q.Del(urlParamPromQuery)
+	q.Set(urlParamQuery, queryRangeReq.Query)
+	q.Set(urlParamStart, time.Unix(0, int64(queryRangeReq.Start)).UTC().Format(time.RFC3339Nano))
+	q.Set(urlParamEnd, time.Unix(0, int64(queryRangeReq.End)).UTC().Format(time.RFC3339Nano))
+	q.Set(urlParamStep, time.Duration(queryRangeReq.Step).String())
+
+	req.URL.RawQuery = q.Encode()
+
+	return req
+}
+
+// BuildQueryRangeBlockRequest builds a range query for a range of pages of a block.
+func BuildQueryRangeBlockRequest(req *http.Request, queryRangeReq *traceqlmetrics.QueryRangeRequest, blockReq *tempopb.SearchBlockRequest) *http.Request {
+	req = BuildQueryRangeRequest(req, queryRangeReq)
+	return buildBlockParams(req, blockReq)
+}
+
+// parseTime parses unix epoch seconds with an optional fraction or RFC3339 to unix epoch nanoseconds
+func parseTime(s string) (uint64, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		if secs < 0 {
+			return 0, fmt.Errorf("cannot be negative: %s", s)
+		}
+		whole, frac := math.Modf(secs)
+		return uint64(whole)*uint64(time.Second) + uint64(math.Round(frac*float64(time.Second))), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+	}
+	if t.UnixNano() < 0 {
+		return 0, fmt.Errorf("cannot be negative: %s", s)
+	}
+	return uint64(t.UnixNano()), nil
+}
+
+// parseDuration parses a duration like 15s or a number of seconds
+func parseDuration(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+	}
+	return d, nil
+}
+
+// defaultQueryRangeStep returns a step that splits the range in defaultQueryRangeIntervals, rounded
+// to seconds
+func defaultQueryRangeStep(start, end uint64) uint64 {
+	step := (end - start) / defaultQueryRangeIntervals
+	step = step / uint64(time.Second) * uint64(time.Second)
+	if step == 0 {
+		step = uint64(time.Second)
+	}
+	return step
+}
+
 // AddServerlessParams takes an already existing http.Request and adds maxBytes
 // to it
 func AddServerlessParams(req *http.Request, maxBytes int) *http.Request {
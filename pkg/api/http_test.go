@@ -6,12 +6,14 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/cmd/tempo-query/tempo"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceqlmetrics"
 )
 
 // For licensing reasons these strings exist in two packages. This test exists to make sure they don't
@@ -96,11 +98,41 @@ func TestQuerierParseSearchRequest(t *testing.T) {
 				SpansPerSpanSet: defaultSpansPerSpanSet,
 			},
 		},
+		{
+			name:     "traceql query with limit",
+			urlQuery: "q=" + url.QueryEscape(`{ .foo="bar" } | order by duration desc | limit 5`) + "&limit=50",
+			expected: &tempopb.SearchRequest{
+				Query:           `{ .foo="bar" } | order by duration desc | limit 5`,
+				Tags:            map[string]string{},
+				Limit:           5,
+				SpansPerSpanSet: defaultSpansPerSpanSet,
+			},
+		},
+		{
+			name:     "traceql query with spss hint",
+			urlQuery: "q=" + url.QueryEscape(`{ .foo="bar" } with (spss=10)`) + "&spss=5",
+			expected: &tempopb.SearchRequest{
+				Query:           `{ .foo="bar" } with (spss=10)`,
+				Tags:            map[string]string{},
+				Limit:           defaultLimit,
+				SpansPerSpanSet: 10,
+			},
+		},
+		{
+			name:     "invalid traceql hint",
+			urlQuery: "q=" + url.QueryEscape(`{ .foo="bar" } with (spss=0)`),
+			err:      "invalid TraceQL query: parse error at line 1, col 1: hint spss must be a positive integer: spss=0",
+		},
 		{
 			name:     "invalid traceql query",
 			urlQuery: "q=" + url.QueryEscape(`{ .foo="bar" `),
 			err:      "invalid TraceQL query: parse error at line 1, col 14: syntax error: unexpected $end",
 		},
+		{
+			name:     "traceql metrics query",
+			urlQuery: "q=" + url.QueryEscape(`{ .foo="bar" } | rate()`),
+			err:      "invalid TraceQL query: parse error at line 1, col 1: metrics queries are not supported by search, use the query range api",
+		},
 		{
 			name:     "traceql query and tags",
 			urlQuery: "q=" + url.QueryEscape(`{ .foo="bar" }`) + "&tags=" + url.QueryEscape("service.name=foo"),
@@ -208,6 +240,29 @@ func TestQuerierParseSearchRequest(t *testing.T) {
 				SpansPerSpanSet: 7,
 			},
 		},
+		{
+			name:     "paginated job",
+			urlQuery: "q=" + url.QueryEscape("{}") + "&paginate=true&afterStartTime=1000000000&afterTraceID=0a",
+			expected: &tempopb.SearchRequest{
+				Tags:                   map[string]string{},
+				Query:                  "{}",
+				Limit:                  defaultLimit,
+				SpansPerSpanSet:        defaultSpansPerSpanSet,
+				Paginated:              true,
+				AfterStartTimeUnixNano: 1000000000,
+				AfterTraceID:           "0a",
+			},
+		},
+		{
+			name:     "invalid paginate",
+			urlQuery: "paginate=blerg",
+			err:      "invalid paginate: strconv.ParseBool: parsing \"blerg\": invalid syntax",
+		},
+		{
+			name:     "invalid afterStartTime",
+			urlQuery: "afterStartTime=-1",
+			err:      "invalid afterStartTime: strconv.ParseUint: parsing \"-1\": invalid syntax",
+		},
 	}
 
 	for _, tt := range tests {
@@ -279,6 +334,35 @@ func TestQuerierParseSearchRequestTagsError(t *testing.T) {
 	}
 }
 
+func TestNewErrorResponse(t *testing.T) {
+	_, err := ParseSearchRequest(httptest.NewRequest("GET", "/?q="+url.QueryEscape("{ spn.foo = 1 }"), nil))
+	require.Error(t, err)
+	assert.Equal(t, &ErrorResponse{
+		Error:       "invalid TraceQL query: parse error at line 1, col 3: syntax error: unexpected IDENTIFIER",
+		Line:        1,
+		Column:      3,
+		EndColumn:   6,
+		Token:       "spn",
+		Suggestions: []string{"span."},
+	}, NewErrorResponse(err))
+
+	// validation errors have the position of the query
+	_, err = ParseSearchRequest(httptest.NewRequest("GET", "/?q="+url.QueryEscape("{ 1 = `foo` }"), nil))
+	require.Error(t, err)
+	assert.Equal(t, &ErrorResponse{
+		Error:     "invalid TraceQL query: parse error at line 1, col 1: binary operations must operate on the same type: 1 = `foo`",
+		Line:      1,
+		Column:    1,
+		EndColumn: 14,
+	}, NewErrorResponse(err))
+
+	_, err = ParseSearchRequest(httptest.NewRequest("GET", "/?start=asdf", nil))
+	require.Error(t, err)
+	assert.Equal(t, &ErrorResponse{
+		Error: "invalid start: strconv.ParseInt: parsing \"asdf\": invalid syntax",
+	}, NewErrorResponse(err))
+}
+
 func TestParseSearchBlockRequest(t *testing.T) {
 	tests := []struct {
 		url           string
@@ -443,6 +527,117 @@ func TestBuildSearchBlockRequest(t *testing.T) {
 	}
 }
 
+func TestParseQueryRangeRequest(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected *traceqlmetrics.QueryRangeRequest
+		err      string
+	}{
+		{
+			query: "?q={}|rate()&start=10&end=20&step=2s",
+			expected: &traceqlmetrics.QueryRangeRequest{
+				Query: "{}|rate()",
+				Start: uint64(10 * time.Second),
+				End:   uint64(20 * time.Second),
+				Step:  uint64(2 * time.Second),
+			},
+		},
+		{
+			// prometheus style
+			query: "?query={}|rate()&start=10.5&end=2023-01-01T00:00:00Z&step=0.5",
+			expected: &traceqlmetrics.QueryRangeRequest{
+				Query: "{}|rate()",
+				Start: uint64(10500 * time.Millisecond),
+				End:   uint64(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()),
+				Step:  uint64(500 * time.Millisecond),
+			},
+		},
+		{
+			// default step
+			query: "?q={}|rate()&start=0&end=3600",
+			expected: &traceqlmetrics.QueryRangeRequest{
+				Query: "{}|rate()",
+				Start: 0,
+				End:   uint64(time.Hour),
+				Step:  uint64(36 * time.Second),
+			},
+		},
+		{
+			query: "?start=10&end=20",
+			err:   "query required",
+		},
+		{
+			query: "?q={}|rate()&end=20",
+			err:   "start required",
+		},
+		{
+			query: "?q={}|rate()&start=20&end=10",
+			err:   "http parameter start must be before end. received start=20 end=10",
+		},
+		{
+			query: "?q={}|rate()&start=foo&end=10",
+			err:   "invalid start: cannot parse \"foo\" to a valid timestamp",
+		},
+		{
+			query: "?q={}|rate()&start=10&end=20&step=-1s",
+			err:   "invalid step: must be a positive duration",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/metrics/query_range"+tc.query, nil)
+			actual, err := ParseQueryRangeRequest(r)
+
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestBuildQueryRangeBlockRequest(t *testing.T) {
+	req := &traceqlmetrics.QueryRangeRequest{
+		Query: "{ } | rate()",
+		Start: uint64(10*time.Second) + 1,
+		End:   uint64(20 * time.Second),
+		Step:  uint64(1500 * time.Millisecond),
+	}
+	blockReq := &tempopb.SearchBlockRequest{
+		StartPage:     0,
+		PagesToSearch: 10,
+		BlockID:       "b92ec614-3fd7-4299-b6db-f657e7025a9b",
+		Encoding:      "none",
+		IndexPageSize: 10,
+		TotalRecords:  11,
+		DataEncoding:  "v1",
+		Version:       "vParquet2",
+		Size_:         1000,
+		FooterSize:    2000,
+	}
+
+	r := BuildQueryRangeBlockRequest(httptest.NewRequest("GET", "/api/metrics/query_range?query=foo", nil), req, blockReq)
+	assert.Equal(t, "/api/metrics/query_range?blockID=b92ec614-3fd7-4299-b6db-f657e7025a9b&dataEncoding=v1&encoding=none&end=1970-01-01T00%3A00%3A20Z&footerSize=2000&indexPageSize=10&pagesToSearch=10&q=%7B+%7D+%7C+rate%28%29&size=1000&start=1970-01-01T00%3A00%3A10.000000001Z&startPage=0&step=1.5s&totalRecords=11&version=vParquet2", r.URL.String())
+
+	// round trip
+	actualReq, actualBlockReq, err := ParseQueryRangeBlockRequest(r)
+	require.NoError(t, err)
+	require.Equal(t, req, actualReq)
+	require.Equal(t, blockReq, actualBlockReq)
+
+	// compaction level is only sent for compacted blocks
+	blockReq.CompactionLevel = 1
+	r = BuildQueryRangeBlockRequest(httptest.NewRequest("GET", "/api/metrics/query_range?query=foo", nil), req, blockReq)
+	assert.Equal(t, "1", r.URL.Query().Get(urlParamCompactionLevel))
+
+	_, actualBlockReq, err = ParseQueryRangeBlockRequest(r)
+	require.NoError(t, err)
+	require.Equal(t, blockReq, actualBlockReq)
+}
+
 func TestValidateAndSanitizeRequest(t *testing.T) {
 	tests := []struct {
 		httpReq       *http.Request
@@ -577,6 +772,18 @@ func TestBuildSearchRequest(t *testing.T) {
 			},
 			query: "?end=20&maxDuration=40ms&minDuration=30ms&start=10",
 		},
+		{
+			req: &tempopb.SearchRequest{
+				Query:                  "{}",
+				Start:                  10,
+				End:                    20,
+				Limit:                  50,
+				Paginated:              true,
+				AfterStartTimeUnixNano: 1000000000,
+				AfterTraceID:           "0a",
+			},
+			query: "?afterStartTime=1000000000&afterTraceID=0a&end=20&limit=50&paginate=true&q=%7B%7D&start=10",
+		},
 	}
 
 	for _, tc := range tests {
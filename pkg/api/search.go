@@ -1,7 +1,13 @@
 package api
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+
+	"github.com/gogo/protobuf/proto"
 
 	"github.com/grafana/tempo/pkg/tempopb"
 )
@@ -24,3 +30,46 @@ func IsSearchBlock(r *http.Request) bool {
 func IsTraceQLQuery(r *tempopb.SearchRequest) bool {
 	return len(r.Query) > 0
 }
+
+// ParseSearchPageToken returns whether a search is paginated and the position it continues at. A search
+// is paginated if the paginate parameter is true or it has a page token. The token is nil for the first page.
+func ParseSearchPageToken(r *http.Request) (bool, *tempopb.SearchPageToken, error) {
+	s, ok := extractQueryParam(r, urlParamPageToken)
+	if !ok {
+		paginate, ok := extractQueryParam(r, urlParamPaginate)
+		if !ok {
+			return false, nil, nil
+		}
+
+		v, err := strconv.ParseBool(paginate)
+		if err != nil {
+			return false, nil, fmt.Errorf("invalid paginate: %w", err)
+		}
+		return v, nil, nil
+	}
+
+	buff, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return false, nil, fmt.Errorf("invalid pageToken: %w", err)
+	}
+
+	token := &tempopb.SearchPageToken{}
+	if err := proto.Unmarshal(buff, token); err != nil {
+		return false, nil, fmt.Errorf("invalid pageToken: %w", err)
+	}
+	if token.LastTraceID == "" {
+		return false, nil, errors.New("invalid pageToken: no position")
+	}
+
+	return true, token, nil
+}
+
+// EncodeSearchPageToken returns the opaque string clients pass as pageToken to get the next page of a search.
+func EncodeSearchPageToken(token *tempopb.SearchPageToken) (string, error) {
+	buff, err := proto.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buff), nil
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/grafana/tempo/pkg/tempopb"
@@ -14,8 +16,62 @@ const (
 	muxVarTagName = "tagName"
 
 	ParamScopeIntrinsic = "intrinsic"
+
+	defaultTagValueStatsLimit = 10
 )
 
+// IsSearchTags returns true if the request is for one of the tags or tag values endpoints
+func IsSearchTags(r *http.Request) bool {
+	return strings.Contains(r.URL.Path, "/search/tag")
+}
+
+// IsSearchTagValues returns true if the request is for the tag values of a tag
+func IsSearchTagValues(r *http.Request) bool {
+	return IsSearchTags(r) && strings.HasSuffix(r.URL.Path, "/values")
+}
+
+// IsSearchTagValueStats returns true if the request is for the value stats of a tag
+func IsSearchTagValueStats(r *http.Request) bool {
+	return IsSearchTags(r) && strings.HasSuffix(r.URL.Path, "/stats")
+}
+
+// IsSearchTagsV2 returns true if the request is for one of the v2 tags or tag values endpoints
+func IsSearchTagsV2(r *http.Request) bool {
+	return strings.Contains(r.URL.Path, "/v2/search/tag")
+}
+
+// ParseSearchTagsRange parses the optional start and end of a tags or tag values request in unix epoch
+// seconds. Zeroes are returned if they are not set, in which case only the ingesters are searched.
+func ParseSearchTagsRange(r *http.Request) (uint32, uint32, error) {
+	var start, end uint32
+
+	if s, ok := extractQueryParam(r, urlParamStart); ok {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid start: %w", err)
+		}
+		start = uint32(v)
+	}
+
+	if s, ok := extractQueryParam(r, urlParamEnd); ok {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid end: %w", err)
+		}
+		end = uint32(v)
+	}
+
+	if start == 0 && end == 0 {
+		return 0, 0, nil
+	}
+
+	if end <= start {
+		return 0, 0, fmt.Errorf("http parameter start must be before end. received start=%d end=%d", start, end)
+	}
+
+	return start, end, nil
+}
+
 // ParseSearchTagValuesRequest handles parsing of requests from /api/search/tags/{tagName}/values and /api/v2/search/tags/{tagName}/values
 func ParseSearchTagValuesRequest(r *http.Request) (*tempopb.SearchTagValuesRequest, error) {
 	return parseSearchTagValuesRequest(r, false)
@@ -53,6 +109,30 @@ func parseSearchTagValuesRequest(r *http.Request, enforceTraceQL bool) (*tempopb
 	return req, nil
 }
 
+// ParseSearchTagValueStatsRequest handles parsing of requests from /api/v2/search/tag/{tagName}/stats
+func ParseSearchTagValueStatsRequest(r *http.Request) (*tempopb.SearchTagValuesRequest, error) {
+	return parseSearchTagValuesRequest(r, true)
+}
+
+// ParseSearchTagValueStatsLimit parses the number of values returned by the tag value stats
+// endpoint. 0 returns all values.
+func ParseSearchTagValueStatsLimit(r *http.Request) (int, error) {
+	s, ok := extractQueryParam(r, urlParamLimit)
+	if !ok {
+		return defaultTagValueStatsLimit, nil
+	}
+
+	limit, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid limit: %w", err)
+	}
+	if limit < 0 {
+		return 0, errors.New("limit must be a non-negative number")
+	}
+
+	return limit, nil
+}
+
 func ParseSearchTagsRequest(r *http.Request) (*tempopb.SearchTagsRequest, error) {
 	scope, _ := extractQueryParam(r, urlParamScope)
 
@@ -65,3 +145,53 @@ func ParseSearchTagsRequest(r *http.Request) (*tempopb.SearchTagsRequest, error)
 		Scope: scope,
 	}, nil
 }
+
+// ParseSearchTagsBlockRequest parses a tags request for a backend block
+func ParseSearchTagsBlockRequest(r *http.Request) (*tempopb.SearchTagsRequest, *tempopb.SearchBlockRequest, error) {
+	req, err := ParseSearchTagsRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blockReq, err := parseBlockParams(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return req, blockReq, nil
+}
+
+// ParseSearchTagValuesBlockRequest parses a tag values request for a backend block
+func ParseSearchTagValuesBlockRequest(r *http.Request) (*tempopb.SearchTagValuesRequest, *tempopb.SearchBlockRequest, error) {
+	return parseSearchTagValuesBlockRequest(r, false)
+}
+
+// ParseSearchTagValuesBlockRequestV2 parses a v2 tag values request for a backend block
+func ParseSearchTagValuesBlockRequestV2(r *http.Request) (*tempopb.SearchTagValuesRequest, *tempopb.SearchBlockRequest, error) {
+	return parseSearchTagValuesBlockRequest(r, true)
+}
+
+// ParseSearchTagValueStatsBlockRequest parses a tag value stats request for a backend block
+func ParseSearchTagValueStatsBlockRequest(r *http.Request) (*tempopb.SearchTagValuesRequest, *tempopb.SearchBlockRequest, error) {
+	return parseSearchTagValuesBlockRequest(r, true)
+}
+
+func parseSearchTagValuesBlockRequest(r *http.Request, enforceTraceQL bool) (*tempopb.SearchTagValuesRequest, *tempopb.SearchBlockRequest, error) {
+	req, err := parseSearchTagValuesRequest(r, enforceTraceQL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blockReq, err := parseBlockParams(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return req, blockReq, nil
+}
+
+// BuildSearchTagsBlockRequest adds the parameters that identify a backend block to a tags or tag
+// values request. The tag name is part of the path and the remaining parameters are kept as is.
+func BuildSearchTagsBlockRequest(req *http.Request, blockReq *tempopb.SearchBlockRequest) *http.Request {
+	return buildBlockParams(req, blockReq)
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
 )
 
 // TestParseSearchTagValues tests the SearchTagValues function
@@ -98,3 +100,119 @@ func TestParseSearchTagsRequest(t *testing.T) {
 		require.Equal(t, tc.scope, req.Scope)
 	}
 }
+
+func TestParseSearchTagsRange(t *testing.T) {
+	tcs := []struct {
+		url           string
+		expectedStart uint32
+		expectedEnd   uint32
+		expectError   bool
+	}{
+		{
+			url: "/api/search/tags",
+		},
+		{
+			url:           "/api/search/tags?start=10&end=20",
+			expectedStart: 10,
+			expectedEnd:   20,
+		},
+		{
+			url:         "/api/search/tags?start=20&end=10",
+			expectError: true,
+		},
+		{
+			url:         "/api/search/tags?start=10",
+			expectError: true,
+		},
+		{
+			url:         "/api/search/tags?start=foo&end=20",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.url, func(t *testing.T) {
+			start, end, err := ParseSearchTagsRange(httptest.NewRequest("GET", tc.url, nil))
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedStart, start)
+			require.Equal(t, tc.expectedEnd, end)
+		})
+	}
+}
+
+func TestSearchTagValuesBlockRequestRoundTrip(t *testing.T) {
+	blockReq := &tempopb.SearchBlockRequest{
+		BlockID:       "b92ec614-3fd7-4299-b6db-f657e7025a9b",
+		StartPage:     0,
+		PagesToSearch: 10,
+		Encoding:      "none",
+		IndexPageSize: 0,
+		TotalRecords:  10,
+		DataEncoding:  "",
+		Version:       "vParquet3",
+		Size_:         1000,
+		FooterSize:    100,
+	}
+
+	httpReq := httptest.NewRequest("GET", "/api/v2/search/tag/span.foo/values?start=10&end=20", nil)
+	httpReq = BuildSearchTagsBlockRequest(httpReq, blockReq)
+	httpReq = mux.SetURLVars(httpReq, map[string]string{muxVarTagName: "span.foo"})
+
+	require.True(t, IsSearchBlock(httpReq))
+	require.True(t, IsSearchTagValues(httpReq))
+	require.True(t, IsSearchTagsV2(httpReq))
+
+	req, actualBlockReq, err := ParseSearchTagValuesBlockRequestV2(httpReq)
+	require.NoError(t, err)
+	require.Equal(t, "span.foo", req.TagName)
+	require.Equal(t, blockReq, actualBlockReq)
+}
+
+func TestParseSearchTagValueStatsLimit(t *testing.T) {
+	tcs := []struct {
+		url           string
+		expectedLimit int
+		expectError   bool
+	}{
+		{
+			url:           "/api/v2/search/tag/span.foo/stats",
+			expectedLimit: defaultTagValueStatsLimit,
+		},
+		{
+			url:           "/api/v2/search/tag/span.foo/stats?limit=3",
+			expectedLimit: 3,
+		},
+		{
+			url:           "/api/v2/search/tag/span.foo/stats?limit=0",
+			expectedLimit: 0,
+		},
+		{
+			url:         "/api/v2/search/tag/span.foo/stats?limit=-1",
+			expectError: true,
+		},
+		{
+			url:         "/api/v2/search/tag/span.foo/stats?limit=foo",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.url, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tc.url, nil)
+			require.True(t, IsSearchTagValueStats(r))
+			require.False(t, IsSearchTagValues(r))
+
+			limit, err := ParseSearchTagValueStatsLimit(r)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedLimit, limit)
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"math"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
 )
 
 func TestIsBackendSearch(t *testing.T) {
@@ -27,3 +31,43 @@ func TestIsSearchBlock(t *testing.T) {
 	assert.True(t, IsSearchBlock(httptest.NewRequest("GET", "/querier/api/search?blockID=blerg", nil)))
 	assert.True(t, IsSearchBlock(httptest.NewRequest("GET", "/querier/api/search/?blockID=blerg", nil)))
 }
+
+func TestParseSearchPageToken(t *testing.T) {
+	token := &tempopb.SearchPageToken{
+		Now:                   100,
+		SearchHash:            math.MaxUint64,
+		LastStartTimeUnixNano: 10,
+		LastTraceID:           "0a",
+	}
+	encoded, err := EncodeSearchPageToken(token)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		url              string
+		expectedPaginate bool
+		expectedToken    *tempopb.SearchPageToken
+		expectedErr      bool
+	}{
+		{url: "/api/search"},
+		{url: "/api/search?paginate=false"},
+		{url: "/api/search?paginate=true", expectedPaginate: true},
+		{url: "/api/search?paginate=blerg", expectedErr: true},
+		{url: "/api/search?pageToken=" + encoded, expectedPaginate: true, expectedToken: token},
+		{url: "/api/search?paginate=false&pageToken=" + encoded, expectedPaginate: true, expectedToken: token},
+		{url: "/api/search?pageToken=!!", expectedErr: true},
+		{url: "/api/search?pageToken=Cg", expectedErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.url, func(t *testing.T) {
+			paginate, actual, err := ParseSearchPageToken(httptest.NewRequest("GET", tc.url, nil))
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPaginate, paginate)
+			require.Equal(t, tc.expectedToken, actual)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// ParseTracesByIDRequest parses the body of a batch trace by id request. The body is a json or, if the
+// content type says so, a protobuf encoded tempopb.TracesByIDRequest. The ids are normalized to lowercase hex
+// without leading zeros, like util.TraceIDToHexString, and duplicates are removed.
+func ParseTracesByIDRequest(r *http.Request) (*tempopb.TracesByIDRequest, error) {
+	if r.Body == nil {
+		return nil, errors.New("please provide a list of trace ids")
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading body: %w", err)
+	}
+
+	req := &tempopb.TracesByIDRequest{}
+	if r.Header.Get(HeaderContentType) == HeaderAcceptProtobuf {
+		err = proto.Unmarshal(body, req)
+	} else {
+		err = (&jsonpb.Unmarshaler{}).Unmarshal(bytes.NewReader(body), req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+
+	if len(req.TraceIDs) == 0 {
+		return nil, errors.New("please provide a list of trace ids")
+	}
+
+	seen := make(map[string]struct{}, len(req.TraceIDs))
+	ids := req.TraceIDs[:0]
+	for _, id := range req.TraceIDs {
+		byteID, err := util.HexStringToTraceID(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trace id %s: %w", id, err)
+		}
+
+		id = util.TraceIDToHexString(byteID)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	req.TraceIDs = ids
+
+	return req, nil
+}
+
+// BuildTracesByIDRequest sets the protobuf encoded tempopb.TracesByIDRequest as the body of the passed
+// http.Request. The request can be sent several times.
+func BuildTracesByIDRequest(req *http.Request, tracesReq *tempopb.TracesByIDRequest) (*http.Request, error) {
+	body, err := proto.Marshal(tracesReq)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set(HeaderContentType, HeaderAcceptProtobuf)
+
+	return req, nil
+}
+
+// IsTraceByIDV2 returns true if the request is for the v2 trace by id endpoint
+func IsTraceByIDV2(r *http.Request) bool {
+	return strings.Contains(r.URL.Path, "/v2/traces/")
+}
+
+// MarshalTraceOTLPProto marshals a trace as an OTLP ExportTraceServiceRequest
+func MarshalTraceOTLPProto(t *tempopb.Trace) ([]byte, error) {
+	td, err := otlpTraces(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+}
+
+// MarshalTraceOTLPJSON marshals a trace in the OTLP/JSON encoding: spans are grouped in resourceSpans
+// and trace and span ids are hex encoded.
+func MarshalTraceOTLPJSON(t *tempopb.Trace) ([]byte, error) {
+	td, err := otlpTraces(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+}
+
+func otlpTraces(t *tempopb.Trace) (ptrace.Traces, error) {
+	if t == nil {
+		return ptrace.NewTraces(), nil
+	}
+
+	// tempopb.Trace has the same wire format as an ExportTraceServiceRequest
+	buff, err := proto.Marshal(t)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+
+	return (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(buff)
+}
+
+// MarshalTraceByIDResponseV2JSON marshals the json body of the v2 trace by id endpoint. The trace is OTLP/JSON
+// encoded and returned alongside the metrics and partial status of the query, which are encoded with jsonpb.
+func MarshalTraceByIDResponseV2JSON(resp *tempopb.TraceByIDResponse) ([]byte, error) {
+	var buff bytes.Buffer
+	err := (&jsonpb.Marshaler{}).Marshal(&buff, &tempopb.TraceByIDResponse{
+		Metrics: resp.Metrics,
+		Status:  resp.Status,
+		Message: resp.Message,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]json.RawMessage{}
+	if err := json.Unmarshal(buff.Bytes(), &res); err != nil {
+		return nil, err
+	}
+
+	// jsonpb omits the default status but it's part of every response
+	res["status"] = json.RawMessage(strconv.Quote(resp.Status.String()))
+
+	if resp.Trace != nil {
+		trace, err := MarshalTraceOTLPJSON(resp.Trace)
+		if err != nil {
+			return nil, err
+		}
+		res["trace"] = trace
+	}
+
+	return json.Marshal(res)
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestParseTracesByIDRequest(t *testing.T) {
+	tcs := []struct {
+		name        string
+		body        string
+		expectedIDs []string
+		expectError bool
+	}{
+		{
+			name:        "ids",
+			body:        `{"traceIDs": ["0a", "000000000000000000000000000000ff"]}`,
+			expectedIDs: []string{"a", "ff"},
+		},
+		{
+			name:        "duplicates are removed",
+			body:        `{"traceIDs": ["0a", "0b", "0a"]}`,
+			expectedIDs: []string{"a", "b"},
+		},
+		{
+			name:        "duplicates are removed after normalizing",
+			body:        `{"traceIDs": ["0A", "a", "0000000000000000000000000000000a", "B"]}`,
+			expectedIDs: []string{"a", "b"},
+		},
+		{
+			name:        "no ids",
+			body:        `{"traceIDs": []}`,
+			expectError: true,
+		},
+		{
+			name:        "empty body",
+			expectError: true,
+		},
+		{
+			name:        "invalid id",
+			body:        `{"traceIDs": ["0a", "xyz"]}`,
+			expectError: true,
+		},
+		{
+			name:        "invalid json",
+			body:        `["0a"]`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/traces", strings.NewReader(tc.body))
+
+			req, err := ParseTracesByIDRequest(r)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedIDs, req.TraceIDs)
+		})
+	}
+}
+
+func TestBuildTracesByIDRequestRoundTrip(t *testing.T) {
+	expected := &tempopb.TracesByIDRequest{TraceIDs: []string{"a", "b"}}
+
+	r, err := BuildTracesByIDRequest(httptest.NewRequest("POST", "/api/traces", bytes.NewReader(nil)), expected)
+	require.NoError(t, err)
+	require.Equal(t, HeaderAcceptProtobuf, r.Header.Get(HeaderContentType))
+
+	// the body can be read more than once
+	for i := 0; i < 2; i++ {
+		body, err := r.GetBody()
+		require.NoError(t, err)
+		r.Body = body
+
+		actual, err := ParseTracesByIDRequest(r)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+}
+
+func TestMarshalTraceOTLP(t *testing.T) {
+	tr := test.MakeTrace(3, []byte{0x0a, 0x0b})
+
+	// the protobuf encoding is an ExportTraceServiceRequest with the spans of the trace
+	buff, err := MarshalTraceOTLPProto(tr)
+	require.NoError(t, err)
+	req := ptraceotlp.NewExportRequest()
+	require.NoError(t, req.UnmarshalProto(buff))
+	require.Equal(t, countSpans(tr), req.Traces().SpanCount())
+
+	// the json encoding has resourceSpans and hex encoded ids
+	buff, err = MarshalTraceOTLPJSON(tr)
+	require.NoError(t, err)
+	require.Contains(t, string(buff), `"resourceSpans"`)
+	require.Contains(t, string(buff), `"traceId":"0a0b0000000000000000000000000000"`)
+
+	td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(buff)
+	require.NoError(t, err)
+	require.Equal(t, countSpans(tr), td.SpanCount())
+
+	// no trace
+	buff, err = MarshalTraceOTLPJSON(nil)
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(buff))
+}
+
+func TestMarshalTraceByIDResponseV2JSON(t *testing.T) {
+	buff, err := MarshalTraceByIDResponseV2JSON(&tempopb.TraceByIDResponse{
+		Metrics: &tempopb.TraceByIDMetrics{TotalJobs: 2, FailedJobs: 1},
+		Status:  tempopb.PartialStatus_PARTIAL,
+		Message: "error occurred",
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"metrics":{"totalJobs":2,"failedJobs":1},"status":"PARTIAL","message":"error occurred"}`, string(buff))
+
+	buff, err = MarshalTraceByIDResponseV2JSON(&tempopb.TraceByIDResponse{
+		Trace:   test.MakeTrace(1, []byte{0x0a}),
+		Metrics: &tempopb.TraceByIDMetrics{TotalJobs: 2},
+	})
+	require.NoError(t, err)
+
+	res := map[string]json.RawMessage{}
+	require.NoError(t, json.Unmarshal(buff, &res))
+	require.Equal(t, `"COMPLETE"`, string(res["status"]))
+	require.Contains(t, string(res["trace"]), `"resourceSpans"`)
+}
+
+func countSpans(tr *tempopb.Trace) int {
+	count := 0
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
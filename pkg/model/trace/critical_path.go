@@ -0,0 +1,126 @@
+package trace
+
+import (
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// CriticalPath is the chain of spans that determined the end-to-end latency of a trace
+type CriticalPath struct {
+	DurationNanos uint64              `json:"durationNanos"`
+	Spans         []*CriticalPathSpan `json:"spans"`
+}
+
+// CriticalPathSpan is a span on the critical path. SelfTimeNanos is the time of the critical path spent in
+// the span itself and BlockingTimeNanos the time spent waiting on its children on the critical path.
+type CriticalPathSpan struct {
+	SpanID            string `json:"spanID"`
+	ParentSpanID      string `json:"parentSpanID,omitempty"`
+	ServiceName       string `json:"serviceName"`
+	Name              string `json:"name"`
+	StartTimeUnixNano uint64 `json:"startTimeUnixNano"`
+	DurationNanos     uint64 `json:"durationNanos"`
+	SelfTimeNanos     uint64 `json:"selfTimeNanos"`
+	BlockingTimeNanos uint64 `json:"blockingTimeNanos"`
+
+	criticalStart uint64
+	depth         int
+}
+
+// FindCriticalPath returns the critical path of a trace ordered by the time the spans are on it. The path is
+// walked backwards from the end of the trace: at every point the child that finished last is the one the
+// parent was waiting on. Children overlapping a child on the path are not on it, and children that finish
+// after their parent are only on it until the parent finishes. Spans without a parent in the trace are
+// treated as children of the trace.
+func FindCriticalPath(t *tempopb.Trace) *CriticalPath {
+	roots := buildSpanTree(t)
+	if len(roots) == 0 {
+		return &CriticalPath{}
+	}
+
+	start := roots[0].start()
+	end := start + spanTreeDuration(roots)
+
+	path := &CriticalPath{
+		DurationNanos: end - start,
+	}
+	walkCriticalPath(roots, start, end, -1, func(s *CriticalPathSpan) {
+		path.Spans = append(path.Spans, s)
+	})
+
+	sort.SliceStable(path.Spans, func(i, j int) bool {
+		a, b := path.Spans[i], path.Spans[j]
+		if a.criticalStart != b.criticalStart {
+			return a.criticalStart < b.criticalStart
+		}
+		return a.depth < b.depth
+	})
+
+	return path
+}
+
+type criticalChild struct {
+	node       *spanNode
+	start, end uint64
+}
+
+// walkCriticalPath walks the critical path through the children of a span on it in the window from start to
+// end. It returns the time of the window the children were on the path.
+func walkCriticalPath(children []*spanNode, start, end uint64, depth int, add func(*CriticalPathSpan)) uint64 {
+	// clamp the children to the window, children outside of it are async and not on the path
+	candidates := make([]criticalChild, 0, len(children))
+	for _, c := range children {
+		cStart, cEnd := c.start(), c.end()
+		if cStart < start {
+			cStart = start
+		}
+		if cEnd > end {
+			cEnd = end
+		}
+		if cStart >= cEnd {
+			continue
+		}
+		candidates = append(candidates, criticalChild{node: c, start: cStart, end: cEnd})
+	}
+
+	// last finished first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].end != candidates[j].end {
+			return candidates[i].end > candidates[j].end
+		}
+		return candidates[i].start < candidates[j].start
+	})
+
+	blocking := uint64(0)
+	t := end
+	for _, c := range candidates {
+		// overlaps the previous child on the path
+		if c.end > t {
+			continue
+		}
+
+		s := &CriticalPathSpan{
+			SpanID:            util.SpanIDToHexString(c.node.span.SpanId),
+			ServiceName:       c.node.serviceName,
+			Name:              c.node.span.Name,
+			StartTimeUnixNano: c.node.start(),
+			DurationNanos:     c.node.end() - c.node.start(),
+			criticalStart:     c.start,
+			depth:             depth + 1,
+		}
+		if !isZeroID(c.node.span.ParentSpanId) {
+			s.ParentSpanID = util.SpanIDToHexString(c.node.span.ParentSpanId)
+		}
+		add(s)
+
+		s.BlockingTimeNanos = walkCriticalPath(c.node.children, c.start, c.end, depth+1, add)
+		s.SelfTimeNanos = c.end - c.start - s.BlockingTimeNanos
+
+		blocking += c.end - c.start
+		t = c.start
+	}
+
+	return blocking
+}
@@ -0,0 +1,111 @@
+package trace
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestFindCriticalPath(t *testing.T) {
+	traceID := []byte{0x01, 0x02}
+
+	// a is the root, c overlaps b and e finishes after a. f starts after a finished
+	spans := []*v1.Span{
+		makeCriticalPathSpan(traceID, 0x0a, 0x00, 0, 100),
+		makeCriticalPathSpan(traceID, 0x0b, 0x0a, 10, 40),
+		makeCriticalPathSpan(traceID, 0x0c, 0x0a, 30, 60),
+		makeCriticalPathSpan(traceID, 0x0d, 0x0a, 70, 90),
+		makeCriticalPathSpan(traceID, 0x0e, 0x0a, 80, 150),
+		makeCriticalPathSpan(traceID, 0x0f, 0x0a, 120, 130),
+		makeCriticalPathSpan(traceID, 0x10, 0x0c, 35, 55),
+	}
+	batch := test.MakeBatch(1, traceID)
+	batch.ScopeSpans[0].Spans = spans
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{batch}}
+
+	path := FindCriticalPath(tr)
+	require.Equal(t, uint64(100), path.DurationNanos)
+
+	type expectedSpan struct {
+		id, parent     string
+		self, blocking uint64
+	}
+	expected := []expectedSpan{
+		{id: "000000000000000a", self: 50, blocking: 50},
+		{id: "000000000000000c", parent: "000000000000000a", self: 10, blocking: 20},
+		{id: "0000000000000010", parent: "000000000000000c", self: 20},
+		{id: "000000000000000e", parent: "000000000000000a", self: 20},
+	}
+	require.Len(t, path.Spans, len(expected))
+
+	selfTime := uint64(0)
+	for i, e := range expected {
+		s := path.Spans[i]
+		require.Equal(t, e.id, s.SpanID)
+		require.Equal(t, e.parent, s.ParentSpanID)
+		require.Equal(t, e.self, s.SelfTimeNanos, e.id)
+		require.Equal(t, e.blocking, s.BlockingTimeNanos, e.id)
+		require.Equal(t, "test-service", s.ServiceName)
+		selfTime += s.SelfTimeNanos
+	}
+	require.Equal(t, path.DurationNanos, selfTime)
+
+	// e reports its full duration even though only part of it is on the path
+	require.Equal(t, uint64(70), path.Spans[3].DurationNanos)
+}
+
+func TestFindCriticalPathWithoutParents(t *testing.T) {
+	// none of the spans have a parent, the one finishing last is on the path
+	tr := test.MakeTrace(5, []byte{0x01})
+
+	var last *v1.Span
+	var start uint64
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				if last == nil || s.EndTimeUnixNano > last.EndTimeUnixNano {
+					last = s
+				}
+				if start == 0 || s.StartTimeUnixNano < start {
+					start = s.StartTimeUnixNano
+				}
+			}
+		}
+	}
+
+	path := FindCriticalPath(tr)
+	require.Equal(t, last.EndTimeUnixNano-start, path.DurationNanos)
+	require.NotEmpty(t, path.Spans)
+	require.Equal(t, last.SpanId, mustSpanID(t, path.Spans[len(path.Spans)-1].SpanID))
+
+	for _, s := range path.Spans {
+		require.Empty(t, s.ParentSpanID)
+		require.Zero(t, s.BlockingTimeNanos)
+		require.LessOrEqual(t, s.SelfTimeNanos, s.DurationNanos)
+	}
+}
+
+func TestFindCriticalPathEmpty(t *testing.T) {
+	require.Equal(t, &CriticalPath{}, FindCriticalPath(nil))
+	require.Equal(t, &CriticalPath{}, FindCriticalPath(&tempopb.Trace{}))
+}
+
+func makeCriticalPathSpan(traceID []byte, id, parent byte, start, end uint64) *v1.Span {
+	s := test.MakeSpan(traceID)
+	s.SpanId = []byte{0, 0, 0, 0, 0, 0, 0, id}
+	s.ParentSpanId = []byte{0, 0, 0, 0, 0, 0, 0, parent}
+	s.StartTimeUnixNano = start
+	s.EndTimeUnixNano = end
+	return s
+}
+
+func mustSpanID(t *testing.T, id string) []byte {
+	b, err := hex.DecodeString(id)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,62 @@
+package trace
+
+import (
+	"hash"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// SpanDeduper removes spans that were already seen from the parts of a trace, deduping spans based on ID and
+// kind like the Combiner. Unlike the Combiner it doesn't keep the parts, so they can be passed on as they
+// are found.
+type SpanDeduper struct {
+	spans  map[token]struct{}
+	h      hash.Hash64
+	buffer []byte
+}
+
+func NewSpanDeduper() *SpanDeduper {
+	return &SpanDeduper{
+		spans:  map[token]struct{}{},
+		h:      newHash(),
+		buffer: make([]byte, 4),
+	}
+}
+
+// Dedupe destructively removes the spans of the trace that were already seen and returns the number of
+// spans left. Batches and scopes without spans left are removed.
+func (d *SpanDeduper) Dedupe(tr *tempopb.Trace) (spanCount int) {
+	if tr == nil {
+		return 0
+	}
+
+	batches := tr.Batches[:0]
+	for _, b := range tr.Batches {
+		scopeSpans := b.ScopeSpans[:0]
+		for _, ss := range b.ScopeSpans {
+			spans := ss.Spans[:0]
+			for _, s := range ss.Spans {
+				token := tokenForID(d.h, d.buffer, int32(s.Kind), s.SpanId)
+				if _, ok := d.spans[token]; ok {
+					continue
+				}
+				d.spans[token] = struct{}{}
+				spans = append(spans, s)
+			}
+
+			if len(spans) > 0 {
+				ss.Spans = spans
+				spanCount += len(spans)
+				scopeSpans = append(scopeSpans, ss)
+			}
+		}
+
+		if len(scopeSpans) > 0 {
+			b.ScopeSpans = scopeSpans
+			batches = append(batches, b)
+		}
+	}
+	tr.Batches = batches
+
+	return spanCount
+}
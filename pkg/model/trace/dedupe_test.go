@@ -0,0 +1,41 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestSpanDeduper(t *testing.T) {
+	traceA := test.MakeTraceWithSpanCount(2, 10, []byte{0x01})
+	traceB := test.MakeTraceWithSpanCount(2, 10, []byte{0x01})
+
+	// a part with all spans of a and half of the spans of b
+	overlapping := proto.Clone(traceA).(*tempopb.Trace)
+	overlapping.Batches = append(overlapping.Batches, proto.Clone(traceB.Batches[0]).(*v1.ResourceSpans))
+
+	d := NewSpanDeduper()
+	require.Equal(t, 20, d.Dedupe(proto.Clone(traceA).(*tempopb.Trace)))
+
+	deduped := proto.Clone(overlapping).(*tempopb.Trace)
+	require.Equal(t, 10, d.Dedupe(deduped))
+	require.Len(t, deduped.Batches, 1)
+	require.True(t, proto.Equal(traceB.Batches[0], deduped.Batches[0]))
+
+	// only the second batch of b is new
+	deduped = proto.Clone(traceB).(*tempopb.Trace)
+	require.Equal(t, 10, d.Dedupe(deduped))
+	require.True(t, proto.Equal(traceB.Batches[1], deduped.Batches[0]))
+
+	// everything was seen
+	deduped = proto.Clone(overlapping).(*tempopb.Trace)
+	require.Equal(t, 0, d.Dedupe(deduped))
+	require.Empty(t, deduped.Batches)
+
+	require.Equal(t, 0, d.Dedupe(nil))
+}
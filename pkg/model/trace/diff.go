@@ -0,0 +1,213 @@
+package trace
+
+import (
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// TraceDiff is the difference between a base trace and a trace compared to it
+type TraceDiff struct {
+	BaseDurationNanos    uint64 `json:"baseDurationNanos"`
+	CompareDurationNanos uint64 `json:"compareDurationNanos"`
+	DurationDeltaNanos   int64  `json:"durationDeltaNanos"`
+	// Added are the spans only in the compared trace, Missing the spans only in the base trace
+	Added   []*DiffSpan    `json:"added"`
+	Missing []*DiffSpan    `json:"missing"`
+	Changed []*ChangedSpan `json:"changed"`
+}
+
+// DiffSpan is a span that is only in one of the traces
+type DiffSpan struct {
+	SpanID            string   `json:"spanID"`
+	ServiceName       string   `json:"serviceName"`
+	Name              string   `json:"name"`
+	Path              []string `json:"path"`
+	StartTimeUnixNano uint64   `json:"startTimeUnixNano"`
+	DurationNanos     uint64   `json:"durationNanos"`
+}
+
+// ChangedSpan is a span of the base trace aligned with a span of the compared trace that differs in duration,
+// status or attributes
+type ChangedSpan struct {
+	ServiceName          string           `json:"serviceName"`
+	Name                 string           `json:"name"`
+	Path                 []string         `json:"path"`
+	BaseSpanID           string           `json:"baseSpanID"`
+	CompareSpanID        string           `json:"compareSpanID"`
+	BaseDurationNanos    uint64           `json:"baseDurationNanos"`
+	CompareDurationNanos uint64           `json:"compareDurationNanos"`
+	DurationDeltaNanos   int64            `json:"durationDeltaNanos"`
+	BaseStatus           string           `json:"baseStatus,omitempty"`
+	CompareStatus        string           `json:"compareStatus,omitempty"`
+	Attributes           []*AttributeDiff `json:"attributes,omitempty"`
+}
+
+// AttributeDiff is a span attribute that differs between aligned spans. The value is empty in the trace the
+// attribute is missing from.
+type AttributeDiff struct {
+	Key     string `json:"key"`
+	Base    string `json:"base,omitempty"`
+	Compare string `json:"compare,omitempty"`
+}
+
+// Diff compares two traces. Spans are aligned by their position in the trace: root spans and the children of
+// aligned spans are aligned if they have the same service and name, in order of their start time. Spans that
+// can't be aligned are added or missing, as are their children. Changed spans are sorted by the absolute
+// duration delta, largest first.
+func Diff(base, compare *tempopb.Trace) *TraceDiff {
+	baseRoots, compareRoots := buildSpanTree(base), buildSpanTree(compare)
+
+	d := &TraceDiff{
+		BaseDurationNanos:    spanTreeDuration(baseRoots),
+		CompareDurationNanos: spanTreeDuration(compareRoots),
+		Added:                []*DiffSpan{},
+		Missing:              []*DiffSpan{},
+		Changed:              []*ChangedSpan{},
+	}
+	d.DurationDeltaNanos = int64(d.CompareDurationNanos) - int64(d.BaseDurationNanos)
+
+	d.diffSpans(baseRoots, compareRoots, []string{})
+
+	sort.SliceStable(d.Changed, func(i, j int) bool {
+		return abs(d.Changed[i].DurationDeltaNanos) > abs(d.Changed[j].DurationDeltaNanos)
+	})
+
+	return d
+}
+
+// diffSpans aligns the spans of both traces below the same path
+func (d *TraceDiff) diffSpans(base, compare []*spanNode, path []string) {
+	// compare spans by service and name in order of their start time
+	compareByKey := map[string][]*spanNode{}
+	for _, n := range compare {
+		key := diffKey(n)
+		compareByKey[key] = append(compareByKey[key], n)
+	}
+
+	aligned := map[*spanNode]struct{}{}
+	for _, b := range base {
+		key := diffKey(b)
+		candidates := compareByKey[key]
+		if len(candidates) == 0 {
+			d.Missing = appendDiffSpans(d.Missing, b, path)
+			continue
+		}
+
+		c := candidates[0]
+		compareByKey[key] = candidates[1:]
+		aligned[c] = struct{}{}
+
+		if changed := diffSpan(b, c, path); changed != nil {
+			d.Changed = append(d.Changed, changed)
+		}
+		d.diffSpans(b.children, c.children, appendPath(path, key))
+	}
+
+	for _, c := range compare {
+		if _, ok := aligned[c]; !ok {
+			d.Added = appendDiffSpans(d.Added, c, path)
+		}
+	}
+}
+
+// diffSpan returns the differences of two aligned spans or nil if they don't differ
+func diffSpan(base, compare *spanNode, path []string) *ChangedSpan {
+	baseDuration, compareDuration := base.end()-base.start(), compare.end()-compare.start()
+	attributes := diffAttributes(base.span.Attributes, compare.span.Attributes)
+	baseStatus, compareStatus := StatusToString(base.span.GetStatus().GetCode()), StatusToString(compare.span.GetStatus().GetCode())
+
+	if baseDuration == compareDuration && baseStatus == compareStatus && len(attributes) == 0 {
+		return nil
+	}
+
+	changed := &ChangedSpan{
+		ServiceName:          base.serviceName,
+		Name:                 base.span.Name,
+		Path:                 path,
+		BaseSpanID:           util.SpanIDToHexString(base.span.SpanId),
+		CompareSpanID:        util.SpanIDToHexString(compare.span.SpanId),
+		BaseDurationNanos:    baseDuration,
+		CompareDurationNanos: compareDuration,
+		DurationDeltaNanos:   int64(compareDuration) - int64(baseDuration),
+		Attributes:           attributes,
+	}
+	if baseStatus != compareStatus {
+		changed.BaseStatus = baseStatus
+		changed.CompareStatus = compareStatus
+	}
+
+	return changed
+}
+
+// diffAttributes returns the attributes that are only set on one span or with different values, sorted by key
+func diffAttributes(base, compare []*v1_common.KeyValue) []*AttributeDiff {
+	values := make(map[string]string, len(base))
+	for _, kv := range base {
+		values[kv.Key] = stringifyValue(kv.Value)
+	}
+
+	var diffs []*AttributeDiff
+	for _, kv := range compare {
+		value := stringifyValue(kv.Value)
+		baseValue, ok := values[kv.Key]
+		delete(values, kv.Key)
+		if ok && baseValue == value {
+			continue
+		}
+		diffs = append(diffs, &AttributeDiff{Key: kv.Key, Base: baseValue, Compare: value})
+	}
+	for k, v := range values {
+		diffs = append(diffs, &AttributeDiff{Key: k, Base: v})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+// appendDiffSpans appends the span and all of its children
+func appendDiffSpans(spans []*DiffSpan, n *spanNode, path []string) []*DiffSpan {
+	spans = append(spans, &DiffSpan{
+		SpanID:            util.SpanIDToHexString(n.span.SpanId),
+		ServiceName:       n.serviceName,
+		Name:              n.span.Name,
+		Path:              path,
+		StartTimeUnixNano: n.start(),
+		DurationNanos:     n.end() - n.start(),
+	})
+
+	childPath := appendPath(path, diffKey(n))
+	for _, c := range n.children {
+		spans = appendDiffSpans(spans, c, childPath)
+	}
+	return spans
+}
+
+func diffKey(n *spanNode) string {
+	return n.serviceName + "/" + n.span.Name
+}
+
+// appendPath returns a copy of path with key appended, so paths can be shared by the spans below it
+func appendPath(path []string, key string) []string {
+	p := make([]string, 0, len(path)+1)
+	p = append(p, path...)
+	return append(p, key)
+}
+
+func stringifyValue(v *v1_common.AnyValue) string {
+	if v == nil {
+		return ""
+	}
+	return util.StringifyAnyValue(v)
+}
+
+func abs(i int64) int64 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
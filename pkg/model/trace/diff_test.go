@@ -0,0 +1,117 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestDiff(t *testing.T) {
+	traceID := []byte{0x01, 0x02}
+
+	// the compared trace is slower in db, doesn't call the cache and calls the queue instead
+	base := makeDiffTrace(traceID,
+		makeDiffSpan(traceID, 0x01, 0x00, "GET", 0, 100, "http.status_code", "200"),
+		makeDiffSpan(traceID, 0x02, 0x01, "db", 10, 30, "db.statement", "SELECT", "db.rows", "1"),
+		makeDiffSpan(traceID, 0x03, 0x01, "cache", 40, 50),
+		makeDiffSpan(traceID, 0x04, 0x03, "cache-lookup", 41, 49),
+	)
+	compare := makeDiffTrace(traceID,
+		makeDiffSpan(traceID, 0x11, 0x00, "GET", 0, 150, "http.status_code", "200"),
+		makeDiffSpan(traceID, 0x12, 0x11, "db", 10, 90, "db.statement", "SELECT *", "db.error", "timeout"),
+		makeDiffSpan(traceID, 0x13, 0x11, "queue", 100, 110),
+	)
+	compare.Batches[0].ScopeSpans[0].Spans[1].Status = &v1.Status{Code: v1.Status_STATUS_CODE_ERROR}
+
+	d := Diff(base, compare)
+	require.Equal(t, uint64(100), d.BaseDurationNanos)
+	require.Equal(t, uint64(150), d.CompareDurationNanos)
+	require.Equal(t, int64(50), d.DurationDeltaNanos)
+
+	require.Len(t, d.Added, 1)
+	require.Equal(t, "0000000000000013", d.Added[0].SpanID)
+	require.Equal(t, "queue", d.Added[0].Name)
+	require.Equal(t, []string{"test-service/GET"}, d.Added[0].Path)
+
+	require.Len(t, d.Missing, 2)
+	require.Equal(t, "0000000000000003", d.Missing[0].SpanID)
+	require.Equal(t, "0000000000000004", d.Missing[1].SpanID)
+	require.Equal(t, []string{"test-service/GET", "test-service/cache"}, d.Missing[1].Path)
+
+	require.Equal(t, []*ChangedSpan{
+		{
+			ServiceName:          "test-service",
+			Name:                 "db",
+			Path:                 []string{"test-service/GET"},
+			BaseSpanID:           "0000000000000002",
+			CompareSpanID:        "0000000000000012",
+			BaseDurationNanos:    20,
+			CompareDurationNanos: 80,
+			DurationDeltaNanos:   60,
+			BaseStatus:           "ok",
+			CompareStatus:        "error",
+			Attributes: []*AttributeDiff{
+				{Key: "db.error", Compare: "timeout"},
+				{Key: "db.rows", Base: "1"},
+				{Key: "db.statement", Base: "SELECT", Compare: "SELECT *"},
+			},
+		},
+		{
+			ServiceName:          "test-service",
+			Name:                 "GET",
+			Path:                 []string{},
+			BaseSpanID:           "0000000000000001",
+			CompareSpanID:        "0000000000000011",
+			BaseDurationNanos:    100,
+			CompareDurationNanos: 150,
+			DurationDeltaNanos:   50,
+		},
+	}, d.Changed)
+}
+
+func TestDiffSameTrace(t *testing.T) {
+	tr := test.MakeTrace(5, []byte{0x01})
+
+	d := Diff(tr, proto.Clone(tr).(*tempopb.Trace))
+	require.Zero(t, d.DurationDeltaNanos)
+	require.Empty(t, d.Added)
+	require.Empty(t, d.Missing)
+	require.Empty(t, d.Changed)
+
+	// all spans are added to an empty trace
+	d = Diff(&tempopb.Trace{}, tr)
+	count := 0
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	require.Len(t, d.Added, count)
+	require.Empty(t, d.Missing)
+}
+
+func makeDiffTrace(traceID []byte, spans ...*v1.Span) *tempopb.Trace {
+	batch := test.MakeBatch(1, traceID)
+	batch.ScopeSpans = batch.ScopeSpans[:1]
+	batch.ScopeSpans[0].Spans = spans
+	return &tempopb.Trace{Batches: []*v1.ResourceSpans{batch}}
+}
+
+func makeDiffSpan(traceID []byte, id, parent byte, name string, start, end uint64, attributes ...string) *v1.Span {
+	s := makeCriticalPathSpan(traceID, id, parent, start, end)
+	s.Name = name
+	s.Attributes = nil
+	for i := 0; i+1 < len(attributes); i += 2 {
+		s.Attributes = append(s.Attributes, &v1_common.KeyValue{
+			Key:   attributes[i],
+			Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: attributes[i+1]}},
+		})
+	}
+	return s
+}
@@ -0,0 +1,128 @@
+package trace
+
+import (
+	"bytes"
+	"sort"
+
+	semconv "go.opentelemetry.io/collector/semconv/v1.9.0"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// spanNode is a span of a trace linked to its parent and children
+type spanNode struct {
+	span        *v1.Span
+	serviceName string
+	parent      *spanNode
+	children    []*spanNode
+}
+
+func (n *spanNode) start() uint64 {
+	return n.span.StartTimeUnixNano
+}
+
+// end returns the end time of the span. spans that end before they start are treated as zero length.
+func (n *spanNode) end() uint64 {
+	if n.span.EndTimeUnixNano < n.span.StartTimeUnixNano {
+		return n.span.StartTimeUnixNano
+	}
+	return n.span.EndTimeUnixNano
+}
+
+// buildSpanTree links the spans of a trace to their parents and returns the spans without a parent in the
+// trace as roots. Roots and children are sorted by start time, spans with the same id are only added once.
+func buildSpanTree(t *tempopb.Trace) []*spanNode {
+	if t == nil {
+		return nil
+	}
+
+	nodes := map[string]*spanNode{}
+	var all []*spanNode
+	for _, b := range t.Batches {
+		serviceName := ""
+		if b.Resource != nil {
+			serviceName = stringAttribute(semconv.AttributeServiceName, b.Resource.Attributes)
+		}
+
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				id := string(s.SpanId)
+				if _, ok := nodes[id]; ok {
+					continue
+				}
+
+				n := &spanNode{span: s, serviceName: serviceName}
+				nodes[id] = n
+				all = append(all, n)
+			}
+		}
+	}
+
+	var roots []*spanNode
+	for _, n := range all {
+		parent, ok := nodes[string(n.span.ParentSpanId)]
+		if !ok || isZeroID(n.span.ParentSpanId) || parent == n {
+			roots = append(roots, n)
+			continue
+		}
+
+		n.parent = parent
+		parent.children = append(parent.children, n)
+	}
+
+	sortSpanNodes(roots)
+	for _, n := range all {
+		sortSpanNodes(n.children)
+	}
+
+	return roots
+}
+
+// spanTreeDuration returns the time from the first root span starting to the last one finishing
+func spanTreeDuration(roots []*spanNode) uint64 {
+	if len(roots) == 0 {
+		return 0
+	}
+
+	start, end := roots[0].start(), roots[0].end()
+	for _, r := range roots[1:] {
+		if r.end() > end {
+			end = r.end()
+		}
+	}
+	return end - start
+}
+
+// sortSpanNodes sorts spans by start time, longest first and span id
+func sortSpanNodes(nodes []*spanNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.start() != b.start() {
+			return a.start() < b.start()
+		}
+		if a.end() != b.end() {
+			return a.end() > b.end()
+		}
+		return bytes.Compare(a.span.SpanId, b.span.SpanId) < 0
+	})
+}
+
+func isZeroID(id []byte) bool {
+	for _, b := range id {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func stringAttribute(key string, attributes []*v1_common.KeyValue) string {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value.GetStringValue()
+		}
+	}
+	return ""
+}
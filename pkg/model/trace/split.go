@@ -0,0 +1,81 @@
+package trace
+
+import (
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// SplitBatches groups batches in messages of up to maxBytes. Batches larger than that are split by their
+// spans, so only a span larger than maxBytes ends up in a message that exceeds it.
+func SplitBatches(batches []*v1.ResourceSpans, maxBytes int) [][]*v1.ResourceSpans {
+	var (
+		split [][]*v1.ResourceSpans
+		msg   []*v1.ResourceSpans
+		size  int
+	)
+
+	for _, batch := range batches {
+		for _, b := range splitResourceSpans(batch, maxBytes) {
+			if len(msg) > 0 && size+b.Size() > maxBytes {
+				split = append(split, msg)
+				msg = nil
+				size = 0
+			}
+			msg = append(msg, b)
+			size += b.Size()
+		}
+	}
+	if len(msg) > 0 {
+		split = append(split, msg)
+	}
+
+	return split
+}
+
+// splitResourceSpans splits a batch larger than maxBytes into batches of the same resource and scopes with
+// up to maxBytes of spans. A span larger than maxBytes gets a batch of its own. The few bytes that encode
+// the length of each span are left out, maxBytes is expected to be well below any message limits.
+func splitResourceSpans(b *v1.ResourceSpans, maxBytes int) []*v1.ResourceSpans {
+	if b.Size() <= maxBytes {
+		return []*v1.ResourceSpans{b}
+	}
+
+	var (
+		split   []*v1.ResourceSpans
+		current *v1.ResourceSpans
+		size    int
+	)
+	newBatch := func() {
+		current = &v1.ResourceSpans{Resource: b.Resource, SchemaUrl: b.SchemaUrl}
+		split = append(split, current)
+		size = current.Size()
+	}
+	newBatch()
+
+	for _, ss := range b.ScopeSpans {
+		scopeSize := (&v1.ScopeSpans{Scope: ss.Scope, SchemaUrl: ss.SchemaUrl}).Size()
+
+		var scope *v1.ScopeSpans
+		for _, s := range ss.Spans {
+			spanSize := s.Size()
+
+			added := spanSize
+			if scope == nil {
+				added += scopeSize
+			}
+			if len(current.ScopeSpans) > 0 && size+added > maxBytes {
+				newBatch()
+				scope = nil
+			}
+
+			if scope == nil {
+				scope = &v1.ScopeSpans{Scope: ss.Scope, SchemaUrl: ss.SchemaUrl}
+				current.ScopeSpans = append(current.ScopeSpans, scope)
+				size += scopeSize
+			}
+			scope.Spans = append(scope.Spans, s)
+			size += spanSize
+		}
+	}
+
+	return split
+}
@@ -0,0 +1,63 @@
+package trace
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestSplitBatches(t *testing.T) {
+	tr := test.MakeTrace(3, []byte{0x0a})
+
+	// a single batch with all spans of the trace in two scopes
+	batch := proto.Clone(tr.Batches[0]).(*v1.ResourceSpans)
+	batch.ScopeSpans = nil
+	spanCount, maxSpanBytes := 0, 0
+	for i, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			scope := proto.Clone(ss).(*v1.ScopeSpans)
+			scope.Scope = &v1_common.InstrumentationScope{Name: strconv.Itoa(i % 2)}
+			batch.ScopeSpans = append(batch.ScopeSpans, scope)
+			for _, s := range ss.Spans {
+				spanCount++
+				if s.Size() > maxSpanBytes {
+					maxSpanBytes = s.Size()
+				}
+			}
+		}
+	}
+
+	maxBytes := batch.Size() / 3
+	require.Greater(t, maxBytes, maxSpanBytes)
+
+	split := SplitBatches([]*v1.ResourceSpans{batch}, maxBytes)
+	require.Greater(t, len(split), 2)
+
+	sent := 0
+	for _, msg := range split {
+		size := 0
+		for _, b := range msg {
+			size += b.Size()
+			require.Equal(t, batch.Resource, b.Resource)
+			for _, ss := range b.ScopeSpans {
+				require.NotNil(t, ss.Scope)
+				sent += len(ss.Spans)
+			}
+		}
+		require.LessOrEqual(t, size, maxBytes+64)
+	}
+	require.Equal(t, spanCount, sent)
+
+	// small batches are combined in one message
+	split = SplitBatches(tr.Batches, 1<<20)
+	require.Len(t, split, 1)
+	require.Len(t, split[0], len(tr.Batches))
+
+	require.Empty(t, SplitBatches(nil, 1<<20))
+}
@@ -0,0 +1,214 @@
+package trace
+
+import (
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// Summarize returns the structure of a trace: span and error counts per service and operation, the depth of
+// the span tree, the root span, the largest gaps and the largest spans. At most limit gaps and spans are
+// returned. A gap is a period of a span in which none of its children ran, root spans are treated as children
+// of the trace. Spans whose parents form a cycle can't be reached from a root and are only counted in
+// CycleSpanCount.
+func Summarize(t *tempopb.Trace, limit int) *tempopb.TraceSummary {
+	roots := buildSpanTree(t)
+	summary := &tempopb.TraceSummary{
+		RootSpanCount: uint32(len(roots)),
+	}
+	if len(roots) == 0 {
+		summary.CycleSpanCount = uint32(uniqueSpanCount(t))
+		return summary
+	}
+
+	s := &summarizer{
+		summary:    summary,
+		services:   map[string]*tempopb.TraceSummaryService{},
+		operations: map[string]map[string]*tempopb.TraceSummaryOperation{},
+		start:      roots[0].start(),
+		end:        roots[0].end(),
+	}
+	for _, r := range roots {
+		s.walk(r, 1)
+	}
+	summary.CycleSpanCount = uint32(uniqueSpanCount(t)) - summary.SpanCount
+
+	summary.RootSpan = summarySpan(roots[0])
+	summary.StartTimeUnixNano = s.start
+	summary.DurationNanos = s.end - s.start
+
+	rootsStart := roots[0].start()
+	s.gaps = appendGaps(s.gaps, nil, roots, rootsStart, rootsStart+spanTreeDuration(roots))
+
+	summary.Services = make([]*tempopb.TraceSummaryService, 0, len(s.services))
+	for name, svc := range s.services {
+		for _, op := range s.operations[name] {
+			svc.Operations = append(svc.Operations, op)
+		}
+		sort.Slice(svc.Operations, func(i, j int) bool {
+			a, b := svc.Operations[i], svc.Operations[j]
+			if a.SpanCount != b.SpanCount {
+				return a.SpanCount > b.SpanCount
+			}
+			return a.Name < b.Name
+		})
+		summary.Services = append(summary.Services, svc)
+	}
+	sort.Slice(summary.Services, func(i, j int) bool {
+		a, b := summary.Services[i], summary.Services[j]
+		if a.SpanCount != b.SpanCount {
+			return a.SpanCount > b.SpanCount
+		}
+		return a.ServiceName < b.ServiceName
+	})
+
+	sort.SliceStable(s.gaps, func(i, j int) bool {
+		return s.gaps[i].DurationNanos > s.gaps[j].DurationNanos
+	})
+	if len(s.gaps) > limit {
+		s.gaps = s.gaps[:limit]
+	}
+	summary.Gaps = s.gaps
+
+	sort.SliceStable(s.spans, func(i, j int) bool {
+		return s.spans[i].end()-s.spans[i].start() > s.spans[j].end()-s.spans[j].start()
+	})
+	if len(s.spans) > limit {
+		s.spans = s.spans[:limit]
+	}
+	for _, n := range s.spans {
+		summary.LargestSpans = append(summary.LargestSpans, summarySpan(n))
+	}
+
+	return summary
+}
+
+type summarizer struct {
+	summary *tempopb.TraceSummary
+	// operations by service and span name
+	services   map[string]*tempopb.TraceSummaryService
+	operations map[string]map[string]*tempopb.TraceSummaryOperation
+
+	start, end uint64
+	spans      []*spanNode
+	gaps       []*tempopb.TraceSummaryGap
+}
+
+func (s *summarizer) walk(n *spanNode, depth uint32) {
+	isError := n.span.GetStatus().GetCode() == v1.Status_STATUS_CODE_ERROR
+
+	s.summary.SpanCount++
+	if isError {
+		s.summary.ErrorCount++
+	}
+	if depth > s.summary.Depth {
+		s.summary.Depth = depth
+	}
+	if n.start() < s.start {
+		s.start = n.start()
+	}
+	if n.end() > s.end {
+		s.end = n.end()
+	}
+	s.spans = append(s.spans, n)
+
+	svc, ok := s.services[n.serviceName]
+	if !ok {
+		svc = &tempopb.TraceSummaryService{ServiceName: n.serviceName}
+		s.services[n.serviceName] = svc
+		s.operations[n.serviceName] = map[string]*tempopb.TraceSummaryOperation{}
+	}
+	op, ok := s.operations[n.serviceName][n.span.Name]
+	if !ok {
+		op = &tempopb.TraceSummaryOperation{Name: n.span.Name}
+		s.operations[n.serviceName][n.span.Name] = op
+	}
+	svc.SpanCount++
+	op.SpanCount++
+	op.TotalDurationNanos += n.end() - n.start()
+	if isError {
+		svc.ErrorCount++
+		op.ErrorCount++
+	}
+
+	if len(n.children) > 0 {
+		s.gaps = appendGaps(s.gaps, n, n.children, n.start(), n.end())
+	}
+	for _, c := range n.children {
+		s.walk(c, depth+1)
+	}
+}
+
+// appendGaps appends the periods from start to end in which none of the children ran. children must be sorted
+// by start time.
+func appendGaps(gaps []*tempopb.TraceSummaryGap, parent *spanNode, children []*spanNode, start, end uint64) []*tempopb.TraceSummaryGap {
+	appendGap := func(from, to uint64) {
+		gap := &tempopb.TraceSummaryGap{
+			StartTimeUnixNano: from,
+			DurationNanos:     to - from,
+		}
+		if parent != nil {
+			gap.SpanID = util.SpanIDToHexString(parent.span.SpanId)
+			gap.ServiceName = parent.serviceName
+			gap.Name = parent.span.Name
+		}
+		gaps = append(gaps, gap)
+	}
+
+	t := start
+	for _, c := range children {
+		cStart, cEnd := c.start(), c.end()
+		if cStart < start {
+			cStart = start
+		}
+		if cEnd > end {
+			cEnd = end
+		}
+		if cStart >= cEnd {
+			continue
+		}
+
+		if cStart > t {
+			appendGap(t, cStart)
+		}
+		if cEnd > t {
+			t = cEnd
+		}
+	}
+	if end > t {
+		appendGap(t, end)
+	}
+
+	return gaps
+}
+
+// uniqueSpanCount returns the number of spans in the trace, counting spans with the same id once like
+// buildSpanTree
+func uniqueSpanCount(t *tempopb.Trace) int {
+	if t == nil {
+		return 0
+	}
+
+	ids := map[string]struct{}{}
+	for _, b := range t.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				ids[string(s.SpanId)] = struct{}{}
+			}
+		}
+	}
+	return len(ids)
+}
+
+func summarySpan(n *spanNode) *tempopb.TraceSummarySpan {
+	return &tempopb.TraceSummarySpan{
+		SpanID:            util.SpanIDToHexString(n.span.SpanId),
+		ServiceName:       n.serviceName,
+		Name:              n.span.Name,
+		StartTimeUnixNano: n.start(),
+		DurationNanos:     n.end() - n.start(),
+		Status:            StatusToString(n.span.GetStatus().GetCode()),
+	}
+}
@@ -0,0 +1,122 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestSummarize(t *testing.T) {
+	traceID := []byte{0x01, 0x02}
+
+	// d finishes after its parent c, the parent of e is missing
+	tr := makeDiffTrace(traceID,
+		makeDiffSpan(traceID, 0x0a, 0x00, "GET", 0, 100),
+		makeDiffSpan(traceID, 0x0b, 0x0a, "db", 10, 30),
+		makeDiffSpan(traceID, 0x0c, 0x0a, "db", 40, 50),
+		makeDiffSpan(traceID, 0x0d, 0x0c, "cache", 45, 60),
+	)
+	tr.Batches[0].ScopeSpans[0].Spans[1].Status = &v1.Status{Code: v1.Status_STATUS_CODE_ERROR}
+
+	other := makeDiffTrace(traceID, makeDiffSpan(traceID, 0x0e, 0x99, "orphan", 200, 210))
+	other.Batches[0].Resource.Attributes[0].Value = &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "other-service"}}
+	tr.Batches = append(tr.Batches, other.Batches...)
+
+	summary := Summarize(tr, 3)
+
+	require.Equal(t, uint32(5), summary.SpanCount)
+	require.Equal(t, uint32(1), summary.ErrorCount)
+	require.Equal(t, uint64(0), summary.StartTimeUnixNano)
+	require.Equal(t, uint64(210), summary.DurationNanos)
+	require.Equal(t, uint32(3), summary.Depth)
+	require.Equal(t, uint32(2), summary.RootSpanCount)
+	require.Equal(t, &tempopb.TraceSummarySpan{
+		SpanID:        "000000000000000a",
+		ServiceName:   "test-service",
+		Name:          "GET",
+		DurationNanos: 100,
+		Status:        "ok",
+	}, summary.RootSpan)
+
+	require.Equal(t, []*tempopb.TraceSummaryService{
+		{
+			ServiceName: "test-service",
+			SpanCount:   4,
+			ErrorCount:  1,
+			Operations: []*tempopb.TraceSummaryOperation{
+				{Name: "db", SpanCount: 2, ErrorCount: 1, TotalDurationNanos: 30},
+				{Name: "GET", SpanCount: 1, TotalDurationNanos: 100},
+				{Name: "cache", SpanCount: 1, TotalDurationNanos: 15},
+			},
+		},
+		{
+			ServiceName: "other-service",
+			SpanCount:   1,
+			Operations: []*tempopb.TraceSummaryOperation{
+				{Name: "orphan", SpanCount: 1, TotalDurationNanos: 10},
+			},
+		},
+	}, summary.Services)
+
+	require.Equal(t, []*tempopb.TraceSummaryGap{
+		{StartTimeUnixNano: 100, DurationNanos: 100},
+		{SpanID: "000000000000000a", ServiceName: "test-service", Name: "GET", StartTimeUnixNano: 50, DurationNanos: 50},
+		{SpanID: "000000000000000a", ServiceName: "test-service", Name: "GET", StartTimeUnixNano: 0, DurationNanos: 10},
+	}, summary.Gaps)
+
+	largest := make([]string, 0, len(summary.LargestSpans))
+	for _, s := range summary.LargestSpans {
+		largest = append(largest, s.SpanID)
+	}
+	require.Equal(t, []string{"000000000000000a", "000000000000000b", "000000000000000d"}, largest)
+}
+
+func TestSummarizeFixture(t *testing.T) {
+	tr := test.MakeTrace(5, []byte{0x01})
+
+	count := 0
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+
+	// none of the spans have a parent
+	summary := Summarize(tr, 10)
+	require.Equal(t, uint32(count), summary.SpanCount)
+	require.Equal(t, uint32(count), summary.RootSpanCount)
+	require.Equal(t, uint32(1), summary.Depth)
+	require.Len(t, summary.Services, 1)
+	require.Equal(t, uint32(count), summary.Services[0].SpanCount)
+	require.LessOrEqual(t, len(summary.LargestSpans), 10)
+
+	require.Equal(t, &tempopb.TraceSummary{}, Summarize(nil, 10))
+}
+
+func TestSummarizeCycle(t *testing.T) {
+	traceID := []byte{0x01, 0x02}
+
+	// b and c are each other's parent so they can't be reached from the root
+	tr := makeDiffTrace(traceID,
+		makeDiffSpan(traceID, 0x0a, 0x00, "GET", 0, 100),
+		makeDiffSpan(traceID, 0x0b, 0x0c, "db", 10, 30),
+		makeDiffSpan(traceID, 0x0c, 0x0b, "db", 40, 50),
+	)
+
+	summary := Summarize(tr, 10)
+	require.Equal(t, uint32(1), summary.SpanCount)
+	require.Equal(t, uint32(2), summary.CycleSpanCount)
+	require.Equal(t, uint32(1), summary.RootSpanCount)
+
+	// without a root all spans are in the cycle
+	tr.Batches[0].ScopeSpans[0].Spans = tr.Batches[0].ScopeSpans[0].Spans[1:]
+	summary = Summarize(tr, 10)
+	require.Equal(t, uint32(0), summary.SpanCount)
+	require.Equal(t, uint32(2), summary.CycleSpanCount)
+	require.Nil(t, summary.RootSpan)
+}
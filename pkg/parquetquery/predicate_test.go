@@ -83,6 +83,112 @@ func TestSubstringPredicate(t *testing.T) {
 	}
 }
 
+func TestPrefixPredicate(t *testing.T) {
+	testCases := []predicateTestCase{
+		{
+			testName:   "all chunks/pages/values inspected",
+			predicate:  NewPrefixPredicate("b"),
+			keptChunks: 1,
+			keptPages:  1,
+			keptValues: 2,
+			writeData: func(w *parquet.Writer) { //nolint:all
+				require.NoError(t, w.Write(&testDictString{"abc"})) // skipped
+				require.NoError(t, w.Write(&testDictString{"bcd"})) // kept
+				require.NoError(t, w.Write(&testDictString{"b"}))   // kept
+				require.NoError(t, w.Write(&testDictString{"cde"})) // skipped
+			},
+		},
+		{
+			testName:   "column bounds allow for skipping a chunk",
+			predicate:  NewPrefixPredicate("x"), // Sorts after all values
+			keptChunks: 0,
+			keptPages:  0,
+			keptValues: 0,
+			writeData: func(w *parquet.Writer) { //nolint:all
+				require.NoError(t, w.Write(&testDictString{"abc"}))
+				require.NoError(t, w.Write(&testDictString{"bcd"}))
+				require.NoError(t, w.Write(&testDictString{"wxyz"}))
+			},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.testName, func(t *testing.T) {
+			testPredicate(t, tC)
+		})
+	}
+}
+
+func TestSuffixPredicate(t *testing.T) {
+	testCases := []predicateTestCase{
+		{
+			testName:   "all chunks/pages/values inspected",
+			predicate:  NewSuffixPredicate("c"),
+			keptChunks: 1,
+			keptPages:  1,
+			keptValues: 2,
+			writeData: func(w *parquet.Writer) { //nolint:all
+				require.NoError(t, w.Write(&testDictString{"abc"})) // kept
+				require.NoError(t, w.Write(&testDictString{"bcd"})) // skipped
+				require.NoError(t, w.Write(&testDictString{"c"}))   // kept
+			},
+		},
+		{
+			testName:   "dictionary in the page header allows for skipping a page",
+			predicate:  NewSuffixPredicate("a"), // Not present in any values
+			keptChunks: 1,
+			keptPages:  0,
+			keptValues: 0,
+			writeData: func(w *parquet.Writer) { //nolint:all
+				require.NoError(t, w.Write(&testDictString{"abc"}))
+				require.NoError(t, w.Write(&testDictString{"abc"}))
+			},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.testName, func(t *testing.T) {
+			testPredicate(t, tC)
+		})
+	}
+}
+
+func TestEqualFoldPredicate(t *testing.T) {
+	testCases := []predicateTestCase{
+		{
+			testName:   "all chunks/pages/values inspected",
+			predicate:  NewEqualFoldPredicate("get"),
+			keptChunks: 1,
+			keptPages:  1,
+			keptValues: 3,
+			writeData: func(w *parquet.Writer) { //nolint:all
+				require.NoError(t, w.Write(&testDictString{"GET"}))  // kept
+				require.NoError(t, w.Write(&testDictString{"get"}))  // kept
+				require.NoError(t, w.Write(&testDictString{"Get"}))  // kept
+				require.NoError(t, w.Write(&testDictString{"POST"})) // skipped
+				require.NoError(t, w.Write(&testDictString{"gets"})) // skipped
+			},
+		},
+		{
+			testName:   "dictionary in the page header allows for skipping a page",
+			predicate:  NewEqualFoldPredicate("put"), // Not present in any values
+			keptChunks: 1,
+			keptPages:  0,
+			keptValues: 0,
+			writeData: func(w *parquet.Writer) { //nolint:all
+				require.NoError(t, w.Write(&testDictString{"GET"}))
+				require.NoError(t, w.Write(&testDictString{"POST"}))
+			},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.testName, func(t *testing.T) {
+			testPredicate(t, tC)
+		})
+	}
+}
+
 func TestNewRegexInPredicate(t *testing.T) {
 	testCases := []predicateTestCase{
 		{
@@ -19,6 +19,22 @@ type Predicate interface {
 	KeepValue(pq.Value) bool
 }
 
+// KeepsAll returns true if the predicate doesn't filter any values. It's the case for a nil predicate
+// and an OrPredicate with a nil predicate.
+func KeepsAll(p Predicate) bool {
+	switch p := p.(type) {
+	case nil:
+		return true
+	case *OrPredicate:
+		for _, pred := range p.preds {
+			if KeepsAll(pred) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // StringInPredicate checks for any of the given strings.
 // Case sensitive exact byte matching
 type StringInPredicate struct {
@@ -230,6 +246,113 @@ func (p *SubstringPredicate) KeepPage(page pq.Page) bool {
 	return p.helper.keepPage(page, p.KeepValue)
 }
 
+// stringFuncPredicate checks for strings matching a simple string function like strings.HasPrefix.
+// Memoized and resets on each row group.
+type stringFuncPredicate struct {
+	name string
+	s    string
+	fn   func(v, s string) bool
+	// rangeFn optionally returns true if any value between min and max can match
+	rangeFn func(min, max, s []byte) bool
+	matches map[string]bool
+
+	helper DictionaryPredicateHelper
+}
+
+var _ Predicate = (*stringFuncPredicate)(nil)
+
+// NewPrefixPredicate checks for strings that start with the given prefix. Column chunks
+// and pages are skipped using their bounds.
+func NewPrefixPredicate(prefix string) Predicate {
+	return &stringFuncPredicate{
+		name: "PrefixPredicate",
+		s:    prefix,
+		fn:   strings.HasPrefix,
+		rangeFn: func(min, max, prefix []byte) bool {
+			// values with the prefix sort between the prefix itself and the prefix followed by 0xff..
+			if len(min) > len(prefix) {
+				min = min[:len(prefix)]
+			}
+			return bytes.Compare(min, prefix) <= 0 && bytes.Compare(max, prefix) >= 0
+		},
+		matches: map[string]bool{},
+	}
+}
+
+// NewSuffixPredicate checks for strings that end with the given suffix.
+func NewSuffixPredicate(suffix string) Predicate {
+	return &stringFuncPredicate{
+		name:    "SuffixPredicate",
+		s:       suffix,
+		fn:      strings.HasSuffix,
+		matches: map[string]bool{},
+	}
+}
+
+// NewEqualFoldPredicate checks for strings that are equal to the given string ignoring case.
+func NewEqualFoldPredicate(s string) Predicate {
+	return &stringFuncPredicate{
+		name:    "EqualFoldPredicate",
+		s:       s,
+		fn:      strings.EqualFold,
+		matches: map[string]bool{},
+	}
+}
+
+func (p *stringFuncPredicate) String() string {
+	return fmt.Sprintf("%s{%s}", p.name, p.s)
+}
+
+func (p *stringFuncPredicate) KeepColumnChunk(cc pq.ColumnChunk) bool {
+	p.helper.setNewRowGroup()
+
+	// Reset match cache on each row group change
+	p.matches = make(map[string]bool, len(p.matches))
+
+	if p.rangeFn == nil {
+		return true
+	}
+
+	if ci := cc.ColumnIndex(); ci != nil {
+		s := []byte(p.s)
+		for i := 0; i < ci.NumPages(); i++ {
+			if p.rangeFn(ci.MinValue(i).ByteArray(), ci.MaxValue(i).ByteArray(), s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func (p *stringFuncPredicate) KeepValue(v pq.Value) bool {
+	if v.IsNull() {
+		return false
+	}
+
+	b := v.ByteArray()
+
+	// Check uses zero alloc optimization of map[string([]byte)]
+	if m, ok := p.matches[string(b)]; ok {
+		return m
+	}
+
+	m := p.fn(string(b), p.s)
+	p.matches[string(b)] = m
+	return m
+}
+
+func (p *stringFuncPredicate) KeepPage(page pq.Page) bool {
+	if p.rangeFn != nil {
+		if min, max, ok := page.Bounds(); ok && !p.rangeFn(min.ByteArray(), max.ByteArray(), []byte(p.s)) {
+			return false
+		}
+	}
+
+	return p.helper.keepPage(page, p.KeepValue)
+}
+
 // IntBetweenPredicate checks for int between the bounds [min,max] inclusive
 type IntBetweenPredicate struct {
 	min, max int64
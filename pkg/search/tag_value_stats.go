@@ -0,0 +1,112 @@
+package search
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// TagValueStatsCombiner counts the values of a tag and estimates their cardinality. It builds the stats
+// of a single ingester or block and combines the stats of several of them.
+type TagValueStatsCombiner struct {
+	values *util.DistinctValueCollector[tempopb.TagValue]
+
+	mtx       sync.Mutex
+	sketch    *util.HyperLogLog
+	estimated bool
+}
+
+// NewTagValueStatsCombiner returns a combiner that counts values until their size exceeds maxBytes.
+// All values are part of the cardinality estimate.
+func NewTagValueStatsCombiner(maxBytes int) *TagValueStatsCombiner {
+	return &TagValueStatsCombiner{
+		values: util.NewDistinctValueCollector(maxBytes, func(v tempopb.TagValue) int { return len(v.Type) + len(v.Value) }),
+		sketch: util.NewHyperLogLog(),
+	}
+}
+
+// Collect adds count occurrences of the value. It is safe for concurrent use.
+func (c *TagValueStatsCombiner) Collect(v tempopb.TagValue, count uint64) {
+	c.mtx.Lock()
+	c.sketch.InsertString(v.Type + ":" + v.Value)
+	c.mtx.Unlock()
+
+	c.values.CollectWithCount(v, count)
+}
+
+// Combine adds the counts and the sketch of a stats response
+func (c *TagValueStatsCombiner) Combine(resp *tempopb.SearchTagValueStatsResponse) error {
+	if len(resp.Sketch) > 0 {
+		sketch, err := util.HyperLogLogFromBytes(resp.Sketch)
+		if err != nil {
+			return err
+		}
+
+		c.mtx.Lock()
+		c.sketch.Merge(sketch)
+		c.mtx.Unlock()
+	}
+
+	// the response didn't contain all of its values. the combined cardinality can't be exact
+	if resp.Cardinality > uint64(len(resp.Values)) {
+		c.mtx.Lock()
+		c.estimated = true
+		c.mtx.Unlock()
+	}
+
+	for _, v := range resp.Values {
+		c.values.CollectWithCount(tempopb.TagValue{Type: v.Type, Value: v.Value}, v.Count)
+	}
+
+	return nil
+}
+
+// Exceeded indicates if some values were not counted b/c the maximum size was reached
+func (c *TagValueStatsCombiner) Exceeded() bool {
+	return c.values.Exceeded()
+}
+
+// Response returns the limit most frequent values and the cardinality of the tag. A limit of 0 returns
+// all values. The cardinality is exact as long as all values were counted and estimated otherwise.
+func (c *TagValueStatsCombiner) Response(limit int) *tempopb.SearchTagValueStatsResponse {
+	counts := c.values.Counts()
+
+	values := make([]*tempopb.TagValueCount, 0, len(counts))
+	for v, n := range counts {
+		values = append(values, &tempopb.TagValueCount{
+			Type:  v.Type,
+			Value: v.Value,
+			Count: n,
+		})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		if values[i].Value != values[j].Value {
+			return values[i].Value < values[j].Value
+		}
+		return values[i].Type < values[j].Type
+	})
+
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	cardinality := uint64(len(counts))
+	if c.estimated || c.values.Exceeded() {
+		cardinality = c.sketch.Estimate()
+	}
+
+	return &tempopb.SearchTagValueStatsResponse{
+		Values:      values,
+		Cardinality: cardinality,
+		Sketch:      c.sketch.Bytes(),
+	}
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestTagValueStatsCombiner(t *testing.T) {
+	a := NewTagValueStatsCombiner(0)
+	a.Collect(tempopb.TagValue{Type: "string", Value: "foo"}, 3)
+	a.Collect(tempopb.TagValue{Type: "string", Value: "bar"}, 1)
+	a.Collect(tempopb.TagValue{Type: "int", Value: "1"}, 1)
+
+	b := NewTagValueStatsCombiner(0)
+	b.Collect(tempopb.TagValue{Type: "string", Value: "bar"}, 4)
+	b.Collect(tempopb.TagValue{Type: "string", Value: "foo"}, 1)
+	b.Collect(tempopb.TagValue{Type: "string", Value: "baz"}, 2)
+
+	c := NewTagValueStatsCombiner(0)
+	require.NoError(t, c.Combine(a.Response(0)))
+	require.NoError(t, c.Combine(b.Response(0)))
+
+	resp := c.Response(3)
+	require.Equal(t, []*tempopb.TagValueCount{
+		{Type: "string", Value: "bar", Count: 5},
+		{Type: "string", Value: "foo", Count: 4},
+		{Type: "string", Value: "baz", Count: 2},
+	}, resp.Values)
+	require.Equal(t, uint64(4), resp.Cardinality)
+
+	require.Error(t, c.Combine(&tempopb.SearchTagValueStatsResponse{Sketch: []byte{1}}))
+}
+
+func TestTagValueStatsCombinerExceeded(t *testing.T) {
+	c := NewTagValueStatsCombiner(100)
+	for i := 0; i < 1000; i++ {
+		c.Collect(tempopb.TagValue{Type: "int", Value: strconv.Itoa(i)}, 1)
+	}
+
+	// the values that don't fit are not counted but still part of the cardinality
+	resp := c.Response(0)
+	require.True(t, c.Exceeded())
+	require.Less(t, len(resp.Values), 1000)
+	require.InDelta(t, 1000, resp.Cardinality, 50)
+}
+
+func TestTagValueStatsCombinerEstimatedResponse(t *testing.T) {
+	a := NewTagValueStatsCombiner(100)
+	for i := 0; i < 1000; i++ {
+		a.Collect(tempopb.TagValue{Type: "int", Value: strconv.Itoa(i)}, 1)
+	}
+
+	// the combined values are within the limit but the response of a was truncated
+	c := NewTagValueStatsCombiner(0)
+	require.NoError(t, c.Combine(a.Response(0)))
+
+	resp := c.Response(0)
+	require.False(t, c.Exceeded())
+	require.InDelta(t, 1000, resp.Cardinality, 50)
+}
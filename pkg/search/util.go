@@ -57,9 +57,45 @@ func GetVirtualIntrinsicValues() []string {
 		traceql.IntrinsicKind.String(),
 		traceql.IntrinsicName.String(),
 		traceql.IntrinsicStatus.String(),
+		traceql.IntrinsicTraceDuration.String(),
+		traceql.IntrinsicTraceRootService.String(),
+		traceql.IntrinsicTraceRootSpan.String(),
 	}
 }
 
+// TagValueFromStatic converts a value found by a v2 tag values search to its api representation
+func TagValueFromStatic(v traceql.Static) tempopb.TagValue {
+	tv := tempopb.TagValue{}
+
+	switch v.Type {
+	case traceql.TypeString:
+		tv.Type = "string"
+		tv.Value = v.S // avoid formatting
+
+	case traceql.TypeBoolean:
+		tv.Type = "bool"
+		tv.Value = v.String()
+
+	case traceql.TypeInt:
+		tv.Type = "int"
+		tv.Value = v.String()
+
+	case traceql.TypeFloat:
+		tv.Type = "float"
+		tv.Value = v.String()
+
+	case traceql.TypeDuration:
+		tv.Type = "duration"
+		tv.Value = v.String()
+
+	case traceql.TypeStatus:
+		tv.Type = "keyword"
+		tv.Value = v.String()
+	}
+
+	return tv
+}
+
 // CombineSearchResults overlays the incoming search result with the existing result. This is required
 // for the following reason:  a trace may be present in multiple blocks, or in partial segments
 // in live traces.  The results should reflect elements of all segments.
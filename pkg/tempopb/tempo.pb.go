@@ -30,6 +30,32 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// PartialStatus tells if a trace by id response contains all parts of the trace that were found
+type PartialStatus int32
+
+const (
+	PartialStatus_COMPLETE PartialStatus = 0
+	PartialStatus_PARTIAL  PartialStatus = 1
+)
+
+var PartialStatus_name = map[int32]string{
+	0: "COMPLETE",
+	1: "PARTIAL",
+}
+
+var PartialStatus_value = map[string]int32{
+	"COMPLETE": 0,
+	"PARTIAL":  1,
+}
+
+func (x PartialStatus) String() string {
+	return proto.EnumName(PartialStatus_name, int32(x))
+}
+
+func (PartialStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{0}
+}
+
 // Read
 type TraceByIDRequest struct {
 	TraceID    []byte `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
@@ -99,16 +125,107 @@ func (m *TraceByIDRequest) GetQueryMode() string {
 	return ""
 }
 
+// TraceByIDStreamRequest streams the batches of a trace as they are found
+type TraceByIDStreamRequest struct {
+	TraceID []byte `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
+	// optional range of the trace in unix epoch seconds
+	Start uint32 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End   uint32 `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	// optional range of block ids and query mode, set by the query frontend for the shards of the trace
+	BlockStart string `protobuf:"bytes,4,opt,name=blockStart,proto3" json:"blockStart,omitempty"`
+	BlockEnd   string `protobuf:"bytes,5,opt,name=blockEnd,proto3" json:"blockEnd,omitempty"`
+	QueryMode  string `protobuf:"bytes,6,opt,name=queryMode,proto3" json:"queryMode,omitempty"`
+}
+
+func (m *TraceByIDStreamRequest) Reset()         { *m = TraceByIDStreamRequest{} }
+func (m *TraceByIDStreamRequest) String() string { return proto.CompactTextString(m) }
+func (*TraceByIDStreamRequest) ProtoMessage()    {}
+func (*TraceByIDStreamRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{1}
+}
+func (m *TraceByIDStreamRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceByIDStreamRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceByIDStreamRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TraceByIDStreamRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceByIDStreamRequest.Merge(m, src)
+}
+func (m *TraceByIDStreamRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceByIDStreamRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceByIDStreamRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceByIDStreamRequest proto.InternalMessageInfo
+
+func (m *TraceByIDStreamRequest) GetTraceID() []byte {
+	if m != nil {
+		return m.TraceID
+	}
+	return nil
+}
+
+func (m *TraceByIDStreamRequest) GetStart() uint32 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *TraceByIDStreamRequest) GetEnd() uint32 {
+	if m != nil {
+		return m.End
+	}
+	return 0
+}
+
+func (m *TraceByIDStreamRequest) GetBlockStart() string {
+	if m != nil {
+		return m.BlockStart
+	}
+	return ""
+}
+
+func (m *TraceByIDStreamRequest) GetBlockEnd() string {
+	if m != nil {
+		return m.BlockEnd
+	}
+	return ""
+}
+
+func (m *TraceByIDStreamRequest) GetQueryMode() string {
+	if m != nil {
+		return m.QueryMode
+	}
+	return ""
+}
+
 type TraceByIDResponse struct {
 	Trace   *Trace            `protobuf:"bytes,1,opt,name=trace,proto3" json:"trace,omitempty"`
 	Metrics *TraceByIDMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// PARTIAL if some parts of the trace couldn't be searched
+	Status PartialStatus `protobuf:"varint,3,opt,name=status,proto3,enum=tempopb.PartialStatus" json:"status,omitempty"`
+	// reason the trace is partial
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
 }
 
 func (m *TraceByIDResponse) Reset()         { *m = TraceByIDResponse{} }
 func (m *TraceByIDResponse) String() string { return proto.CompactTextString(m) }
 func (*TraceByIDResponse) ProtoMessage()    {}
 func (*TraceByIDResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{1}
+	return fileDescriptor_f22805646f4f62b6, []int{2}
 }
 func (m *TraceByIDResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -151,14 +268,30 @@ func (m *TraceByIDResponse) GetMetrics() *TraceByIDMetrics {
 	return nil
 }
 
+func (m *TraceByIDResponse) GetStatus() PartialStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PartialStatus_COMPLETE
+}
+
+func (m *TraceByIDResponse) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
 type TraceByIDMetrics struct {
+	TotalJobs  uint32 `protobuf:"varint,1,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
+	FailedJobs uint32 `protobuf:"varint,2,opt,name=failedJobs,proto3" json:"failedJobs,omitempty"`
 }
 
 func (m *TraceByIDMetrics) Reset()         { *m = TraceByIDMetrics{} }
 func (m *TraceByIDMetrics) String() string { return proto.CompactTextString(m) }
 func (*TraceByIDMetrics) ProtoMessage()    {}
 func (*TraceByIDMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{2}
+	return fileDescriptor_f22805646f4f62b6, []int{3}
 }
 func (m *TraceByIDMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -187,32 +320,38 @@ func (m *TraceByIDMetrics) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_TraceByIDMetrics proto.InternalMessageInfo
 
-// SearchRequest takes no block parameters and implies a "recent traces" search
-type SearchRequest struct {
-	// case insensitive partial match
-	Tags          map[string]string `protobuf:"bytes,1,rep,name=Tags,proto3" json:"Tags" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	MinDurationMs uint32            `protobuf:"varint,2,opt,name=MinDurationMs,proto3" json:"MinDurationMs,omitempty"`
-	MaxDurationMs uint32            `protobuf:"varint,3,opt,name=MaxDurationMs,proto3" json:"MaxDurationMs,omitempty"`
-	Limit         uint32            `protobuf:"varint,4,opt,name=Limit,proto3" json:"Limit,omitempty"`
-	Start         uint32            `protobuf:"varint,5,opt,name=start,proto3" json:"start,omitempty"`
-	End           uint32            `protobuf:"varint,6,opt,name=end,proto3" json:"end,omitempty"`
-	// TraceQL query
-	Query           string `protobuf:"bytes,8,opt,name=Query,proto3" json:"Query,omitempty"`
-	SpansPerSpanSet uint32 `protobuf:"varint,9,opt,name=SpansPerSpanSet,proto3" json:"SpansPerSpanSet,omitempty"`
+func (m *TraceByIDMetrics) GetTotalJobs() uint32 {
+	if m != nil {
+		return m.TotalJobs
+	}
+	return 0
 }
 
-func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
-func (m *SearchRequest) String() string { return proto.CompactTextString(m) }
-func (*SearchRequest) ProtoMessage()    {}
-func (*SearchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{3}
+func (m *TraceByIDMetrics) GetFailedJobs() uint32 {
+	if m != nil {
+		return m.FailedJobs
+	}
+	return 0
 }
-func (m *SearchRequest) XXX_Unmarshal(b []byte) error {
+
+// TracesByIDRequest looks up several traces at once
+type TracesByIDRequest struct {
+	// hex encoded trace ids
+	TraceIDs []string `protobuf:"bytes,1,rep,name=traceIDs,proto3" json:"traceIDs,omitempty"`
+}
+
+func (m *TracesByIDRequest) Reset()         { *m = TracesByIDRequest{} }
+func (m *TracesByIDRequest) String() string { return proto.CompactTextString(m) }
+func (*TracesByIDRequest) ProtoMessage()    {}
+func (*TracesByIDRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{4}
+}
+func (m *TracesByIDRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TracesByIDRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TracesByIDRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -222,102 +361,110 @@ func (m *SearchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error
 		return b[:n], nil
 	}
 }
-func (m *SearchRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchRequest.Merge(m, src)
+func (m *TracesByIDRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TracesByIDRequest.Merge(m, src)
 }
-func (m *SearchRequest) XXX_Size() int {
+func (m *TracesByIDRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchRequest.DiscardUnknown(m)
+func (m *TracesByIDRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TracesByIDRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchRequest proto.InternalMessageInfo
+var xxx_messageInfo_TracesByIDRequest proto.InternalMessageInfo
 
-func (m *SearchRequest) GetTags() map[string]string {
+func (m *TracesByIDRequest) GetTraceIDs() []string {
 	if m != nil {
-		return m.Tags
+		return m.TraceIDs
 	}
 	return nil
 }
 
-func (m *SearchRequest) GetMinDurationMs() uint32 {
-	if m != nil {
-		return m.MinDurationMs
-	}
-	return 0
+type TracesByIDResponse struct {
+	// found traces keyed by the hex encoded trace id of the request
+	Traces map[string]*Trace `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// hex encoded ids of the traces that were not found
+	NotFound []string `protobuf:"bytes,2,rep,name=notFound,proto3" json:"notFound,omitempty"`
 }
 
-func (m *SearchRequest) GetMaxDurationMs() uint32 {
-	if m != nil {
-		return m.MaxDurationMs
-	}
-	return 0
+func (m *TracesByIDResponse) Reset()         { *m = TracesByIDResponse{} }
+func (m *TracesByIDResponse) String() string { return proto.CompactTextString(m) }
+func (*TracesByIDResponse) ProtoMessage()    {}
+func (*TracesByIDResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{5}
 }
-
-func (m *SearchRequest) GetLimit() uint32 {
-	if m != nil {
-		return m.Limit
-	}
-	return 0
+func (m *TracesByIDResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (m *SearchRequest) GetStart() uint32 {
-	if m != nil {
-		return m.Start
+func (m *TracesByIDResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TracesByIDResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return 0
 }
-
-func (m *SearchRequest) GetEnd() uint32 {
-	if m != nil {
-		return m.End
-	}
-	return 0
+func (m *TracesByIDResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TracesByIDResponse.Merge(m, src)
+}
+func (m *TracesByIDResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TracesByIDResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TracesByIDResponse.DiscardUnknown(m)
 }
 
-func (m *SearchRequest) GetQuery() string {
+var xxx_messageInfo_TracesByIDResponse proto.InternalMessageInfo
+
+func (m *TracesByIDResponse) GetTraces() map[string]*Trace {
 	if m != nil {
-		return m.Query
+		return m.Traces
 	}
-	return ""
+	return nil
 }
 
-func (m *SearchRequest) GetSpansPerSpanSet() uint32 {
+func (m *TracesByIDResponse) GetNotFound() []string {
 	if m != nil {
-		return m.SpansPerSpanSet
+		return m.NotFound
 	}
-	return 0
-}
-
-// SearchBlockRequest takes SearchRequest parameters as well as all information necessary
-// to search a block in the backend.
-type SearchBlockRequest struct {
-	SearchReq     *SearchRequest `protobuf:"bytes,1,opt,name=searchReq,proto3" json:"searchReq,omitempty"`
-	BlockID       string         `protobuf:"bytes,2,opt,name=blockID,proto3" json:"blockID,omitempty"`
-	StartPage     uint32         `protobuf:"varint,3,opt,name=startPage,proto3" json:"startPage,omitempty"`
-	PagesToSearch uint32         `protobuf:"varint,4,opt,name=pagesToSearch,proto3" json:"pagesToSearch,omitempty"`
-	Encoding      string         `protobuf:"bytes,5,opt,name=encoding,proto3" json:"encoding,omitempty"`
-	IndexPageSize uint32         `protobuf:"varint,6,opt,name=indexPageSize,proto3" json:"indexPageSize,omitempty"`
-	TotalRecords  uint32         `protobuf:"varint,7,opt,name=totalRecords,proto3" json:"totalRecords,omitempty"`
-	DataEncoding  string         `protobuf:"bytes,8,opt,name=dataEncoding,proto3" json:"dataEncoding,omitempty"`
-	Version       string         `protobuf:"bytes,9,opt,name=version,proto3" json:"version,omitempty"`
-	Size_         uint64         `protobuf:"varint,10,opt,name=size,proto3" json:"size,omitempty"`
-	FooterSize    uint32         `protobuf:"varint,11,opt,name=footerSize,proto3" json:"footerSize,omitempty"`
+	return nil
 }
 
-func (m *SearchBlockRequest) Reset()         { *m = SearchBlockRequest{} }
-func (m *SearchBlockRequest) String() string { return proto.CompactTextString(m) }
-func (*SearchBlockRequest) ProtoMessage()    {}
-func (*SearchBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{4}
+// TraceSummary describes the structure of a trace without its spans
+type TraceSummary struct {
+	SpanCount         uint32 `protobuf:"varint,1,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
+	ErrorCount        uint32 `protobuf:"varint,2,opt,name=errorCount,proto3" json:"errorCount,omitempty"`
+	StartTimeUnixNano uint64 `protobuf:"varint,3,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
+	DurationNanos     uint64 `protobuf:"varint,4,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
+	// number of levels of the span tree
+	Depth uint32 `protobuf:"varint,5,opt,name=depth,proto3" json:"depth,omitempty"`
+	// number of spans without a parent in the trace
+	RootSpanCount uint32                 `protobuf:"varint,6,opt,name=rootSpanCount,proto3" json:"rootSpanCount,omitempty"`
+	RootSpan      *TraceSummarySpan      `protobuf:"bytes,7,opt,name=rootSpan,proto3" json:"rootSpan,omitempty"`
+	Services      []*TraceSummaryService `protobuf:"bytes,8,rep,name=services,proto3" json:"services,omitempty"`
+	// largest periods in which a span didn't wait on any of its children
+	Gaps         []*TraceSummaryGap  `protobuf:"bytes,9,rep,name=gaps,proto3" json:"gaps,omitempty"`
+	LargestSpans []*TraceSummarySpan `protobuf:"bytes,10,rep,name=largestSpans,proto3" json:"largestSpans,omitempty"`
+	// number of spans that aren't part of the span tree because their parents form a cycle
+	CycleSpanCount uint32 `protobuf:"varint,11,opt,name=cycleSpanCount,proto3" json:"cycleSpanCount,omitempty"`
+}
+
+func (m *TraceSummary) Reset()         { *m = TraceSummary{} }
+func (m *TraceSummary) String() string { return proto.CompactTextString(m) }
+func (*TraceSummary) ProtoMessage()    {}
+func (*TraceSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{6}
 }
-func (m *SearchBlockRequest) XXX_Unmarshal(b []byte) error {
+func (m *TraceSummary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TraceSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchBlockRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TraceSummary.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -327,112 +474,116 @@ func (m *SearchBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *SearchBlockRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchBlockRequest.Merge(m, src)
+func (m *TraceSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSummary.Merge(m, src)
 }
-func (m *SearchBlockRequest) XXX_Size() int {
+func (m *TraceSummary) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchBlockRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchBlockRequest.DiscardUnknown(m)
+func (m *TraceSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSummary.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchBlockRequest proto.InternalMessageInfo
+var xxx_messageInfo_TraceSummary proto.InternalMessageInfo
 
-func (m *SearchBlockRequest) GetSearchReq() *SearchRequest {
+func (m *TraceSummary) GetSpanCount() uint32 {
 	if m != nil {
-		return m.SearchReq
+		return m.SpanCount
 	}
-	return nil
+	return 0
 }
 
-func (m *SearchBlockRequest) GetBlockID() string {
+func (m *TraceSummary) GetErrorCount() uint32 {
 	if m != nil {
-		return m.BlockID
+		return m.ErrorCount
 	}
-	return ""
+	return 0
 }
 
-func (m *SearchBlockRequest) GetStartPage() uint32 {
+func (m *TraceSummary) GetStartTimeUnixNano() uint64 {
 	if m != nil {
-		return m.StartPage
+		return m.StartTimeUnixNano
 	}
 	return 0
 }
 
-func (m *SearchBlockRequest) GetPagesToSearch() uint32 {
+func (m *TraceSummary) GetDurationNanos() uint64 {
 	if m != nil {
-		return m.PagesToSearch
+		return m.DurationNanos
 	}
 	return 0
 }
 
-func (m *SearchBlockRequest) GetEncoding() string {
+func (m *TraceSummary) GetDepth() uint32 {
 	if m != nil {
-		return m.Encoding
+		return m.Depth
 	}
-	return ""
+	return 0
 }
 
-func (m *SearchBlockRequest) GetIndexPageSize() uint32 {
+func (m *TraceSummary) GetRootSpanCount() uint32 {
 	if m != nil {
-		return m.IndexPageSize
+		return m.RootSpanCount
 	}
 	return 0
 }
 
-func (m *SearchBlockRequest) GetTotalRecords() uint32 {
+func (m *TraceSummary) GetRootSpan() *TraceSummarySpan {
 	if m != nil {
-		return m.TotalRecords
+		return m.RootSpan
 	}
-	return 0
+	return nil
 }
 
-func (m *SearchBlockRequest) GetDataEncoding() string {
+func (m *TraceSummary) GetServices() []*TraceSummaryService {
 	if m != nil {
-		return m.DataEncoding
+		return m.Services
 	}
-	return ""
+	return nil
 }
 
-func (m *SearchBlockRequest) GetVersion() string {
+func (m *TraceSummary) GetGaps() []*TraceSummaryGap {
 	if m != nil {
-		return m.Version
+		return m.Gaps
 	}
-	return ""
+	return nil
 }
 
-func (m *SearchBlockRequest) GetSize_() uint64 {
+func (m *TraceSummary) GetLargestSpans() []*TraceSummarySpan {
 	if m != nil {
-		return m.Size_
+		return m.LargestSpans
 	}
-	return 0
+	return nil
 }
 
-func (m *SearchBlockRequest) GetFooterSize() uint32 {
+func (m *TraceSummary) GetCycleSpanCount() uint32 {
 	if m != nil {
-		return m.FooterSize
+		return m.CycleSpanCount
 	}
 	return 0
 }
 
-type SearchResponse struct {
-	Traces  []*TraceSearchMetadata `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
-	Metrics *SearchMetrics         `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+type TraceSummarySpan struct {
+	SpanID            string `protobuf:"bytes,1,opt,name=spanID,proto3" json:"spanID,omitempty"`
+	ServiceName       string `protobuf:"bytes,2,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Name              string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	StartTimeUnixNano uint64 `protobuf:"varint,4,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
+	DurationNanos     uint64 `protobuf:"varint,5,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
+	Status            string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
 }
 
-func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
-func (m *SearchResponse) String() string { return proto.CompactTextString(m) }
-func (*SearchResponse) ProtoMessage()    {}
-func (*SearchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{5}
+func (m *TraceSummarySpan) Reset()         { *m = TraceSummarySpan{} }
+func (m *TraceSummarySpan) String() string { return proto.CompactTextString(m) }
+func (*TraceSummarySpan) ProtoMessage()    {}
+func (*TraceSummarySpan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{7}
 }
-func (m *SearchResponse) XXX_Unmarshal(b []byte) error {
+func (m *TraceSummarySpan) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TraceSummarySpan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TraceSummarySpan.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -442,53 +593,79 @@ func (m *SearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, erro
 		return b[:n], nil
 	}
 }
-func (m *SearchResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchResponse.Merge(m, src)
+func (m *TraceSummarySpan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSummarySpan.Merge(m, src)
 }
-func (m *SearchResponse) XXX_Size() int {
+func (m *TraceSummarySpan) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchResponse.DiscardUnknown(m)
+func (m *TraceSummarySpan) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSummarySpan.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchResponse proto.InternalMessageInfo
+var xxx_messageInfo_TraceSummarySpan proto.InternalMessageInfo
 
-func (m *SearchResponse) GetTraces() []*TraceSearchMetadata {
+func (m *TraceSummarySpan) GetSpanID() string {
 	if m != nil {
-		return m.Traces
+		return m.SpanID
 	}
-	return nil
+	return ""
 }
 
-func (m *SearchResponse) GetMetrics() *SearchMetrics {
+func (m *TraceSummarySpan) GetServiceName() string {
 	if m != nil {
-		return m.Metrics
+		return m.ServiceName
 	}
-	return nil
+	return ""
 }
 
-type TraceSearchMetadata struct {
-	TraceID           string   `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
-	RootServiceName   string   `protobuf:"bytes,2,opt,name=rootServiceName,proto3" json:"rootServiceName,omitempty"`
-	RootTraceName     string   `protobuf:"bytes,3,opt,name=rootTraceName,proto3" json:"rootTraceName,omitempty"`
-	StartTimeUnixNano uint64   `protobuf:"varint,4,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
-	DurationMs        uint32   `protobuf:"varint,5,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
-	SpanSet           *SpanSet `protobuf:"bytes,6,opt,name=spanSet,proto3" json:"spanSet,omitempty"`
+func (m *TraceSummarySpan) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
 }
 
-func (m *TraceSearchMetadata) Reset()         { *m = TraceSearchMetadata{} }
-func (m *TraceSearchMetadata) String() string { return proto.CompactTextString(m) }
-func (*TraceSearchMetadata) ProtoMessage()    {}
-func (*TraceSearchMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{6}
+func (m *TraceSummarySpan) GetStartTimeUnixNano() uint64 {
+	if m != nil {
+		return m.StartTimeUnixNano
+	}
+	return 0
 }
-func (m *TraceSearchMetadata) XXX_Unmarshal(b []byte) error {
+
+func (m *TraceSummarySpan) GetDurationNanos() uint64 {
+	if m != nil {
+		return m.DurationNanos
+	}
+	return 0
+}
+
+func (m *TraceSummarySpan) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+type TraceSummaryService struct {
+	ServiceName string                   `protobuf:"bytes,1,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	SpanCount   uint32                   `protobuf:"varint,2,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
+	ErrorCount  uint32                   `protobuf:"varint,3,opt,name=errorCount,proto3" json:"errorCount,omitempty"`
+	Operations  []*TraceSummaryOperation `protobuf:"bytes,4,rep,name=operations,proto3" json:"operations,omitempty"`
+}
+
+func (m *TraceSummaryService) Reset()         { *m = TraceSummaryService{} }
+func (m *TraceSummaryService) String() string { return proto.CompactTextString(m) }
+func (*TraceSummaryService) ProtoMessage()    {}
+func (*TraceSummaryService) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{8}
+}
+func (m *TraceSummaryService) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *TraceSearchMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TraceSummaryService) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_TraceSearchMetadata.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TraceSummaryService.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -498,78 +675,65 @@ func (m *TraceSearchMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *TraceSearchMetadata) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TraceSearchMetadata.Merge(m, src)
+func (m *TraceSummaryService) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSummaryService.Merge(m, src)
 }
-func (m *TraceSearchMetadata) XXX_Size() int {
+func (m *TraceSummaryService) XXX_Size() int {
 	return m.Size()
 }
-func (m *TraceSearchMetadata) XXX_DiscardUnknown() {
-	xxx_messageInfo_TraceSearchMetadata.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_TraceSearchMetadata proto.InternalMessageInfo
-
-func (m *TraceSearchMetadata) GetTraceID() string {
-	if m != nil {
-		return m.TraceID
-	}
-	return ""
+func (m *TraceSummaryService) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSummaryService.DiscardUnknown(m)
 }
 
-func (m *TraceSearchMetadata) GetRootServiceName() string {
-	if m != nil {
-		return m.RootServiceName
-	}
-	return ""
-}
+var xxx_messageInfo_TraceSummaryService proto.InternalMessageInfo
 
-func (m *TraceSearchMetadata) GetRootTraceName() string {
+func (m *TraceSummaryService) GetServiceName() string {
 	if m != nil {
-		return m.RootTraceName
+		return m.ServiceName
 	}
 	return ""
 }
 
-func (m *TraceSearchMetadata) GetStartTimeUnixNano() uint64 {
+func (m *TraceSummaryService) GetSpanCount() uint32 {
 	if m != nil {
-		return m.StartTimeUnixNano
+		return m.SpanCount
 	}
 	return 0
 }
 
-func (m *TraceSearchMetadata) GetDurationMs() uint32 {
+func (m *TraceSummaryService) GetErrorCount() uint32 {
 	if m != nil {
-		return m.DurationMs
+		return m.ErrorCount
 	}
 	return 0
 }
 
-func (m *TraceSearchMetadata) GetSpanSet() *SpanSet {
+func (m *TraceSummaryService) GetOperations() []*TraceSummaryOperation {
 	if m != nil {
-		return m.SpanSet
+		return m.Operations
 	}
 	return nil
 }
 
-type SpanSet struct {
-	Spans      []*Span        `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans,omitempty"`
-	Matched    uint32         `protobuf:"varint,2,opt,name=matched,proto3" json:"matched,omitempty"`
-	Attributes []*v1.KeyValue `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty"`
+type TraceSummaryOperation struct {
+	Name               string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SpanCount          uint32 `protobuf:"varint,2,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
+	ErrorCount         uint32 `protobuf:"varint,3,opt,name=errorCount,proto3" json:"errorCount,omitempty"`
+	TotalDurationNanos uint64 `protobuf:"varint,4,opt,name=totalDurationNanos,proto3" json:"totalDurationNanos,omitempty"`
 }
 
-func (m *SpanSet) Reset()         { *m = SpanSet{} }
-func (m *SpanSet) String() string { return proto.CompactTextString(m) }
-func (*SpanSet) ProtoMessage()    {}
-func (*SpanSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{7}
+func (m *TraceSummaryOperation) Reset()         { *m = TraceSummaryOperation{} }
+func (m *TraceSummaryOperation) String() string { return proto.CompactTextString(m) }
+func (*TraceSummaryOperation) ProtoMessage()    {}
+func (*TraceSummaryOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{9}
 }
-func (m *SpanSet) XXX_Unmarshal(b []byte) error {
+func (m *TraceSummaryOperation) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpanSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TraceSummaryOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpanSet.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TraceSummaryOperation.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -579,59 +743,67 @@ func (m *SpanSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *SpanSet) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpanSet.Merge(m, src)
+func (m *TraceSummaryOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSummaryOperation.Merge(m, src)
 }
-func (m *SpanSet) XXX_Size() int {
+func (m *TraceSummaryOperation) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpanSet) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpanSet.DiscardUnknown(m)
+func (m *TraceSummaryOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSummaryOperation.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SpanSet proto.InternalMessageInfo
+var xxx_messageInfo_TraceSummaryOperation proto.InternalMessageInfo
 
-func (m *SpanSet) GetSpans() []*Span {
+func (m *TraceSummaryOperation) GetName() string {
 	if m != nil {
-		return m.Spans
+		return m.Name
 	}
-	return nil
+	return ""
 }
 
-func (m *SpanSet) GetMatched() uint32 {
+func (m *TraceSummaryOperation) GetSpanCount() uint32 {
 	if m != nil {
-		return m.Matched
+		return m.SpanCount
 	}
 	return 0
 }
 
-func (m *SpanSet) GetAttributes() []*v1.KeyValue {
+func (m *TraceSummaryOperation) GetErrorCount() uint32 {
 	if m != nil {
-		return m.Attributes
+		return m.ErrorCount
 	}
-	return nil
+	return 0
 }
 
-type Span struct {
-	SpanID            string         `protobuf:"bytes,1,opt,name=spanID,proto3" json:"spanID,omitempty"`
-	Name              string         `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	StartTimeUnixNano uint64         `protobuf:"varint,3,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
-	DurationNanos     uint64         `protobuf:"varint,4,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
-	Attributes        []*v1.KeyValue `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty"`
+func (m *TraceSummaryOperation) GetTotalDurationNanos() uint64 {
+	if m != nil {
+		return m.TotalDurationNanos
+	}
+	return 0
 }
 
-func (m *Span) Reset()         { *m = Span{} }
-func (m *Span) String() string { return proto.CompactTextString(m) }
-func (*Span) ProtoMessage()    {}
-func (*Span) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{8}
+type TraceSummaryGap struct {
+	// the span the gap is in, empty for gaps between root spans
+	SpanID            string `protobuf:"bytes,1,opt,name=spanID,proto3" json:"spanID,omitempty"`
+	ServiceName       string `protobuf:"bytes,2,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Name              string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	StartTimeUnixNano uint64 `protobuf:"varint,4,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
+	DurationNanos     uint64 `protobuf:"varint,5,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
 }
-func (m *Span) XXX_Unmarshal(b []byte) error {
+
+func (m *TraceSummaryGap) Reset()         { *m = TraceSummaryGap{} }
+func (m *TraceSummaryGap) String() string { return proto.CompactTextString(m) }
+func (*TraceSummaryGap) ProtoMessage()    {}
+func (*TraceSummaryGap) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{10}
+}
+func (m *TraceSummaryGap) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Span) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TraceSummaryGap) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Span.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TraceSummaryGap.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -641,74 +813,84 @@ func (m *Span) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Span) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Span.Merge(m, src)
+func (m *TraceSummaryGap) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSummaryGap.Merge(m, src)
 }
-func (m *Span) XXX_Size() int {
+func (m *TraceSummaryGap) XXX_Size() int {
 	return m.Size()
 }
-func (m *Span) XXX_DiscardUnknown() {
-	xxx_messageInfo_Span.DiscardUnknown(m)
+func (m *TraceSummaryGap) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSummaryGap.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Span proto.InternalMessageInfo
+var xxx_messageInfo_TraceSummaryGap proto.InternalMessageInfo
 
-func (m *Span) GetSpanID() string {
+func (m *TraceSummaryGap) GetSpanID() string {
 	if m != nil {
 		return m.SpanID
 	}
 	return ""
 }
 
-func (m *Span) GetName() string {
+func (m *TraceSummaryGap) GetServiceName() string {
 	if m != nil {
-		return m.Name
+		return m.ServiceName
 	}
 	return ""
 }
 
-func (m *Span) GetStartTimeUnixNano() uint64 {
+func (m *TraceSummaryGap) GetName() string {
 	if m != nil {
-		return m.StartTimeUnixNano
+		return m.Name
 	}
-	return 0
+	return ""
 }
 
-func (m *Span) GetDurationNanos() uint64 {
+func (m *TraceSummaryGap) GetStartTimeUnixNano() uint64 {
 	if m != nil {
-		return m.DurationNanos
+		return m.StartTimeUnixNano
 	}
 	return 0
 }
 
-func (m *Span) GetAttributes() []*v1.KeyValue {
+func (m *TraceSummaryGap) GetDurationNanos() uint64 {
 	if m != nil {
-		return m.Attributes
+		return m.DurationNanos
 	}
-	return nil
+	return 0
 }
 
-type SearchMetrics struct {
-	InspectedTraces uint32 `protobuf:"varint,1,opt,name=inspectedTraces,proto3" json:"inspectedTraces,omitempty"`
-	InspectedBytes  uint64 `protobuf:"varint,2,opt,name=inspectedBytes,proto3" json:"inspectedBytes,omitempty"`
-	TotalBlocks     uint32 `protobuf:"varint,3,opt,name=totalBlocks,proto3" json:"totalBlocks,omitempty"`
-	CompletedJobs   uint32 `protobuf:"varint,4,opt,name=completedJobs,proto3" json:"completedJobs,omitempty"`
-	TotalJobs       uint32 `protobuf:"varint,5,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
-	TotalBlockBytes uint64 `protobuf:"varint,6,opt,name=totalBlockBytes,proto3" json:"totalBlockBytes,omitempty"`
+// SearchRequest takes no block parameters and implies a "recent traces" search
+type SearchRequest struct {
+	// case insensitive partial match
+	Tags          map[string]string `protobuf:"bytes,1,rep,name=Tags,proto3" json:"Tags" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	MinDurationMs uint32            `protobuf:"varint,2,opt,name=MinDurationMs,proto3" json:"MinDurationMs,omitempty"`
+	MaxDurationMs uint32            `protobuf:"varint,3,opt,name=MaxDurationMs,proto3" json:"MaxDurationMs,omitempty"`
+	Limit         uint32            `protobuf:"varint,4,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	Start         uint32            `protobuf:"varint,5,opt,name=start,proto3" json:"start,omitempty"`
+	End           uint32            `protobuf:"varint,6,opt,name=end,proto3" json:"end,omitempty"`
+	// TraceQL query
+	Query           string `protobuf:"bytes,8,opt,name=Query,proto3" json:"Query,omitempty"`
+	SpansPerSpanSet uint32 `protobuf:"varint,9,opt,name=SpansPerSpanSet,proto3" json:"SpansPerSpanSet,omitempty"`
+	// set by the query frontend for the jobs of a paginated search. the results are ordered by start time
+	// with the most recent first and only traces after the last trace of the previous page are returned
+	Paginated              bool   `protobuf:"varint,10,opt,name=paginated,proto3" json:"paginated,omitempty"`
+	AfterStartTimeUnixNano uint64 `protobuf:"varint,11,opt,name=afterStartTimeUnixNano,proto3" json:"afterStartTimeUnixNano,omitempty"`
+	AfterTraceID           string `protobuf:"bytes,12,opt,name=afterTraceID,proto3" json:"afterTraceID,omitempty"`
 }
 
-func (m *SearchMetrics) Reset()         { *m = SearchMetrics{} }
-func (m *SearchMetrics) String() string { return proto.CompactTextString(m) }
-func (*SearchMetrics) ProtoMessage()    {}
-func (*SearchMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{9}
+func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
+func (m *SearchRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchRequest) ProtoMessage()    {}
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{11}
 }
-func (m *SearchMetrics) XXX_Unmarshal(b []byte) error {
+func (m *SearchRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchMetrics.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -718,120 +900,124 @@ func (m *SearchMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error
 		return b[:n], nil
 	}
 }
-func (m *SearchMetrics) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchMetrics.Merge(m, src)
+func (m *SearchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchRequest.Merge(m, src)
 }
-func (m *SearchMetrics) XXX_Size() int {
+func (m *SearchRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchMetrics) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchMetrics.DiscardUnknown(m)
+func (m *SearchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchMetrics proto.InternalMessageInfo
+var xxx_messageInfo_SearchRequest proto.InternalMessageInfo
 
-func (m *SearchMetrics) GetInspectedTraces() uint32 {
+func (m *SearchRequest) GetTags() map[string]string {
 	if m != nil {
-		return m.InspectedTraces
+		return m.Tags
 	}
-	return 0
+	return nil
 }
 
-func (m *SearchMetrics) GetInspectedBytes() uint64 {
+func (m *SearchRequest) GetMinDurationMs() uint32 {
 	if m != nil {
-		return m.InspectedBytes
+		return m.MinDurationMs
 	}
 	return 0
 }
 
-func (m *SearchMetrics) GetTotalBlocks() uint32 {
+func (m *SearchRequest) GetMaxDurationMs() uint32 {
 	if m != nil {
-		return m.TotalBlocks
+		return m.MaxDurationMs
 	}
 	return 0
 }
 
-func (m *SearchMetrics) GetCompletedJobs() uint32 {
+func (m *SearchRequest) GetLimit() uint32 {
 	if m != nil {
-		return m.CompletedJobs
+		return m.Limit
 	}
 	return 0
 }
 
-func (m *SearchMetrics) GetTotalJobs() uint32 {
+func (m *SearchRequest) GetStart() uint32 {
 	if m != nil {
-		return m.TotalJobs
+		return m.Start
 	}
 	return 0
 }
 
-func (m *SearchMetrics) GetTotalBlockBytes() uint64 {
+func (m *SearchRequest) GetEnd() uint32 {
 	if m != nil {
-		return m.TotalBlockBytes
+		return m.End
 	}
 	return 0
 }
 
-type SearchTagsRequest struct {
-	Scope string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+func (m *SearchRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
 }
 
-func (m *SearchTagsRequest) Reset()         { *m = SearchTagsRequest{} }
-func (m *SearchTagsRequest) String() string { return proto.CompactTextString(m) }
-func (*SearchTagsRequest) ProtoMessage()    {}
-func (*SearchTagsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{10}
+func (m *SearchRequest) GetSpansPerSpanSet() uint32 {
+	if m != nil {
+		return m.SpansPerSpanSet
+	}
+	return 0
 }
-func (m *SearchTagsRequest) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
+
+func (m *SearchRequest) GetPaginated() bool {
+	if m != nil {
+		return m.Paginated
+	}
+	return false
 }
-func (m *SearchTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_SearchTagsRequest.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+
+func (m *SearchRequest) GetAfterStartTimeUnixNano() uint64 {
+	if m != nil {
+		return m.AfterStartTimeUnixNano
 	}
-}
-func (m *SearchTagsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagsRequest.Merge(m, src)
-}
-func (m *SearchTagsRequest) XXX_Size() int {
-	return m.Size()
-}
-func (m *SearchTagsRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagsRequest.DiscardUnknown(m)
+	return 0
 }
 
-var xxx_messageInfo_SearchTagsRequest proto.InternalMessageInfo
-
-func (m *SearchTagsRequest) GetScope() string {
+func (m *SearchRequest) GetAfterTraceID() string {
 	if m != nil {
-		return m.Scope
+		return m.AfterTraceID
 	}
 	return ""
 }
 
-type SearchTagsResponse struct {
-	TagNames []string `protobuf:"bytes,1,rep,name=tagNames,proto3" json:"tagNames,omitempty"`
+// SearchBlockRequest takes SearchRequest parameters as well as all information necessary
+// to search a block in the backend.
+type SearchBlockRequest struct {
+	SearchReq       *SearchRequest `protobuf:"bytes,1,opt,name=searchReq,proto3" json:"searchReq,omitempty"`
+	BlockID         string         `protobuf:"bytes,2,opt,name=blockID,proto3" json:"blockID,omitempty"`
+	StartPage       uint32         `protobuf:"varint,3,opt,name=startPage,proto3" json:"startPage,omitempty"`
+	PagesToSearch   uint32         `protobuf:"varint,4,opt,name=pagesToSearch,proto3" json:"pagesToSearch,omitempty"`
+	Encoding        string         `protobuf:"bytes,5,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	IndexPageSize   uint32         `protobuf:"varint,6,opt,name=indexPageSize,proto3" json:"indexPageSize,omitempty"`
+	TotalRecords    uint32         `protobuf:"varint,7,opt,name=totalRecords,proto3" json:"totalRecords,omitempty"`
+	DataEncoding    string         `protobuf:"bytes,8,opt,name=dataEncoding,proto3" json:"dataEncoding,omitempty"`
+	Version         string         `protobuf:"bytes,9,opt,name=version,proto3" json:"version,omitempty"`
+	Size_           uint64         `protobuf:"varint,10,opt,name=size,proto3" json:"size,omitempty"`
+	FooterSize      uint32         `protobuf:"varint,11,opt,name=footerSize,proto3" json:"footerSize,omitempty"`
+	CompactionLevel uint32         `protobuf:"varint,12,opt,name=compactionLevel,proto3" json:"compactionLevel,omitempty"`
 }
 
-func (m *SearchTagsResponse) Reset()         { *m = SearchTagsResponse{} }
-func (m *SearchTagsResponse) String() string { return proto.CompactTextString(m) }
-func (*SearchTagsResponse) ProtoMessage()    {}
-func (*SearchTagsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{11}
+func (m *SearchBlockRequest) Reset()         { *m = SearchBlockRequest{} }
+func (m *SearchBlockRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchBlockRequest) ProtoMessage()    {}
+func (*SearchBlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{12}
 }
-func (m *SearchTagsResponse) XXX_Unmarshal(b []byte) error {
+func (m *SearchBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchTagsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchTagsResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchBlockRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -841,86 +1027,121 @@ func (m *SearchTagsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *SearchTagsResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagsResponse.Merge(m, src)
+func (m *SearchBlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchBlockRequest.Merge(m, src)
 }
-func (m *SearchTagsResponse) XXX_Size() int {
+func (m *SearchBlockRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchTagsResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagsResponse.DiscardUnknown(m)
+func (m *SearchBlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchBlockRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchTagsResponse proto.InternalMessageInfo
+var xxx_messageInfo_SearchBlockRequest proto.InternalMessageInfo
 
-func (m *SearchTagsResponse) GetTagNames() []string {
+func (m *SearchBlockRequest) GetSearchReq() *SearchRequest {
 	if m != nil {
-		return m.TagNames
+		return m.SearchReq
 	}
 	return nil
 }
 
-type SearchTagsV2Response struct {
-	Scopes []*SearchTagsV2Scope `protobuf:"bytes,1,rep,name=scopes,proto3" json:"scopes,omitempty"`
+func (m *SearchBlockRequest) GetBlockID() string {
+	if m != nil {
+		return m.BlockID
+	}
+	return ""
 }
 
-func (m *SearchTagsV2Response) Reset()         { *m = SearchTagsV2Response{} }
-func (m *SearchTagsV2Response) String() string { return proto.CompactTextString(m) }
-func (*SearchTagsV2Response) ProtoMessage()    {}
-func (*SearchTagsV2Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{12}
+func (m *SearchBlockRequest) GetStartPage() uint32 {
+	if m != nil {
+		return m.StartPage
+	}
+	return 0
 }
-func (m *SearchTagsV2Response) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
+
+func (m *SearchBlockRequest) GetPagesToSearch() uint32 {
+	if m != nil {
+		return m.PagesToSearch
+	}
+	return 0
 }
-func (m *SearchTagsV2Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_SearchTagsV2Response.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+
+func (m *SearchBlockRequest) GetEncoding() string {
+	if m != nil {
+		return m.Encoding
 	}
+	return ""
 }
-func (m *SearchTagsV2Response) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagsV2Response.Merge(m, src)
+
+func (m *SearchBlockRequest) GetIndexPageSize() uint32 {
+	if m != nil {
+		return m.IndexPageSize
+	}
+	return 0
 }
-func (m *SearchTagsV2Response) XXX_Size() int {
-	return m.Size()
+
+func (m *SearchBlockRequest) GetTotalRecords() uint32 {
+	if m != nil {
+		return m.TotalRecords
+	}
+	return 0
 }
-func (m *SearchTagsV2Response) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagsV2Response.DiscardUnknown(m)
+
+func (m *SearchBlockRequest) GetDataEncoding() string {
+	if m != nil {
+		return m.DataEncoding
+	}
+	return ""
 }
 
-var xxx_messageInfo_SearchTagsV2Response proto.InternalMessageInfo
+func (m *SearchBlockRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
 
-func (m *SearchTagsV2Response) GetScopes() []*SearchTagsV2Scope {
+func (m *SearchBlockRequest) GetSize_() uint64 {
 	if m != nil {
-		return m.Scopes
+		return m.Size_
 	}
-	return nil
+	return 0
 }
 
-type SearchTagsV2Scope struct {
-	Name string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+func (m *SearchBlockRequest) GetFooterSize() uint32 {
+	if m != nil {
+		return m.FooterSize
+	}
+	return 0
 }
 
-func (m *SearchTagsV2Scope) Reset()         { *m = SearchTagsV2Scope{} }
-func (m *SearchTagsV2Scope) String() string { return proto.CompactTextString(m) }
-func (*SearchTagsV2Scope) ProtoMessage()    {}
-func (*SearchTagsV2Scope) Descriptor() ([]byte, []int) {
+func (m *SearchBlockRequest) GetCompactionLevel() uint32 {
+	if m != nil {
+		return m.CompactionLevel
+	}
+	return 0
+}
+
+type SearchResponse struct {
+	Traces  []*TraceSearchMetadata `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
+	Metrics *SearchMetrics         `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// opaque token of the next page of a paginated search. empty on the last page
+	NextPageToken string `protobuf:"bytes,3,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+}
+
+func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
+func (m *SearchResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchResponse) ProtoMessage()    {}
+func (*SearchResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{13}
 }
-func (m *SearchTagsV2Scope) XXX_Unmarshal(b []byte) error {
+func (m *SearchResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchTagsV2Scope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchTagsV2Scope.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -930,49 +1151,64 @@ func (m *SearchTagsV2Scope) XXX_Marshal(b []byte, deterministic bool) ([]byte, e
 		return b[:n], nil
 	}
 }
-func (m *SearchTagsV2Scope) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagsV2Scope.Merge(m, src)
+func (m *SearchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResponse.Merge(m, src)
 }
-func (m *SearchTagsV2Scope) XXX_Size() int {
+func (m *SearchResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchTagsV2Scope) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagsV2Scope.DiscardUnknown(m)
+func (m *SearchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchTagsV2Scope proto.InternalMessageInfo
+var xxx_messageInfo_SearchResponse proto.InternalMessageInfo
 
-func (m *SearchTagsV2Scope) GetName() string {
+func (m *SearchResponse) GetTraces() []*TraceSearchMetadata {
 	if m != nil {
-		return m.Name
+		return m.Traces
 	}
-	return ""
+	return nil
 }
 
-func (m *SearchTagsV2Scope) GetTags() []string {
+func (m *SearchResponse) GetMetrics() *SearchMetrics {
 	if m != nil {
-		return m.Tags
+		return m.Metrics
 	}
 	return nil
 }
 
-type SearchTagValuesRequest struct {
-	TagName string `protobuf:"bytes,1,opt,name=tagName,proto3" json:"tagName,omitempty"`
-	Query   string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+func (m *SearchResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
 }
 
-func (m *SearchTagValuesRequest) Reset()         { *m = SearchTagValuesRequest{} }
-func (m *SearchTagValuesRequest) String() string { return proto.CompactTextString(m) }
-func (*SearchTagValuesRequest) ProtoMessage()    {}
-func (*SearchTagValuesRequest) Descriptor() ([]byte, []int) {
+// SearchPageToken is the position of a paginated search. It is handed to clients base64 encoded.
+type SearchPageToken struct {
+	// unix epoch seconds of the first page. all pages split the search into ingester and backend jobs
+	// at this time
+	Now int64 `protobuf:"varint,1,opt,name=now,proto3" json:"now,omitempty"`
+	// hash of the search parameters the token was created for
+	SearchHash uint64 `protobuf:"varint,2,opt,name=searchHash,proto3" json:"searchHash,omitempty"`
+	// last trace of the previous page. results are ordered by start time desc and trace id, the next
+	// page returns the traces after it
+	LastStartTimeUnixNano uint64 `protobuf:"varint,3,opt,name=lastStartTimeUnixNano,proto3" json:"lastStartTimeUnixNano,omitempty"`
+	LastTraceID           string `protobuf:"bytes,4,opt,name=lastTraceID,proto3" json:"lastTraceID,omitempty"`
+}
+
+func (m *SearchPageToken) Reset()         { *m = SearchPageToken{} }
+func (m *SearchPageToken) String() string { return proto.CompactTextString(m) }
+func (*SearchPageToken) ProtoMessage()    {}
+func (*SearchPageToken) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{14}
 }
-func (m *SearchTagValuesRequest) XXX_Unmarshal(b []byte) error {
+func (m *SearchPageToken) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchTagValuesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchPageToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchTagValuesRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchPageToken.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -982,48 +1218,67 @@ func (m *SearchTagValuesRequest) XXX_Marshal(b []byte, deterministic bool) ([]by
 		return b[:n], nil
 	}
 }
-func (m *SearchTagValuesRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagValuesRequest.Merge(m, src)
+func (m *SearchPageToken) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchPageToken.Merge(m, src)
 }
-func (m *SearchTagValuesRequest) XXX_Size() int {
+func (m *SearchPageToken) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchTagValuesRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagValuesRequest.DiscardUnknown(m)
+func (m *SearchPageToken) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchPageToken.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchTagValuesRequest proto.InternalMessageInfo
+var xxx_messageInfo_SearchPageToken proto.InternalMessageInfo
 
-func (m *SearchTagValuesRequest) GetTagName() string {
+func (m *SearchPageToken) GetNow() int64 {
 	if m != nil {
-		return m.TagName
+		return m.Now
 	}
-	return ""
+	return 0
 }
 
-func (m *SearchTagValuesRequest) GetQuery() string {
+func (m *SearchPageToken) GetSearchHash() uint64 {
 	if m != nil {
-		return m.Query
+		return m.SearchHash
+	}
+	return 0
+}
+
+func (m *SearchPageToken) GetLastStartTimeUnixNano() uint64 {
+	if m != nil {
+		return m.LastStartTimeUnixNano
+	}
+	return 0
+}
+
+func (m *SearchPageToken) GetLastTraceID() string {
+	if m != nil {
+		return m.LastTraceID
 	}
 	return ""
 }
 
-type SearchTagValuesResponse struct {
-	TagValues []string `protobuf:"bytes,1,rep,name=tagValues,proto3" json:"tagValues,omitempty"`
+type TraceSearchMetadata struct {
+	TraceID           string   `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
+	RootServiceName   string   `protobuf:"bytes,2,opt,name=rootServiceName,proto3" json:"rootServiceName,omitempty"`
+	RootTraceName     string   `protobuf:"bytes,3,opt,name=rootTraceName,proto3" json:"rootTraceName,omitempty"`
+	StartTimeUnixNano uint64   `protobuf:"varint,4,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
+	DurationMs        uint32   `protobuf:"varint,5,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	SpanSet           *SpanSet `protobuf:"bytes,6,opt,name=spanSet,proto3" json:"spanSet,omitempty"`
 }
 
-func (m *SearchTagValuesResponse) Reset()         { *m = SearchTagValuesResponse{} }
-func (m *SearchTagValuesResponse) String() string { return proto.CompactTextString(m) }
-func (*SearchTagValuesResponse) ProtoMessage()    {}
-func (*SearchTagValuesResponse) Descriptor() ([]byte, []int) {
+func (m *TraceSearchMetadata) Reset()         { *m = TraceSearchMetadata{} }
+func (m *TraceSearchMetadata) String() string { return proto.CompactTextString(m) }
+func (*TraceSearchMetadata) ProtoMessage()    {}
+func (*TraceSearchMetadata) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{15}
 }
-func (m *SearchTagValuesResponse) XXX_Unmarshal(b []byte) error {
+func (m *TraceSearchMetadata) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchTagValuesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TraceSearchMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchTagValuesResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TraceSearchMetadata.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1033,42 +1288,78 @@ func (m *SearchTagValuesResponse) XXX_Marshal(b []byte, deterministic bool) ([]b
 		return b[:n], nil
 	}
 }
-func (m *SearchTagValuesResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagValuesResponse.Merge(m, src)
+func (m *TraceSearchMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSearchMetadata.Merge(m, src)
 }
-func (m *SearchTagValuesResponse) XXX_Size() int {
+func (m *TraceSearchMetadata) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchTagValuesResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagValuesResponse.DiscardUnknown(m)
+func (m *TraceSearchMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSearchMetadata.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchTagValuesResponse proto.InternalMessageInfo
+var xxx_messageInfo_TraceSearchMetadata proto.InternalMessageInfo
 
-func (m *SearchTagValuesResponse) GetTagValues() []string {
+func (m *TraceSearchMetadata) GetTraceID() string {
 	if m != nil {
-		return m.TagValues
+		return m.TraceID
+	}
+	return ""
+}
+
+func (m *TraceSearchMetadata) GetRootServiceName() string {
+	if m != nil {
+		return m.RootServiceName
+	}
+	return ""
+}
+
+func (m *TraceSearchMetadata) GetRootTraceName() string {
+	if m != nil {
+		return m.RootTraceName
+	}
+	return ""
+}
+
+func (m *TraceSearchMetadata) GetStartTimeUnixNano() uint64 {
+	if m != nil {
+		return m.StartTimeUnixNano
+	}
+	return 0
+}
+
+func (m *TraceSearchMetadata) GetDurationMs() uint32 {
+	if m != nil {
+		return m.DurationMs
+	}
+	return 0
+}
+
+func (m *TraceSearchMetadata) GetSpanSet() *SpanSet {
+	if m != nil {
+		return m.SpanSet
 	}
 	return nil
 }
 
-type TagValue struct {
-	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+type SpanSet struct {
+	Spans      []*Span        `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans,omitempty"`
+	Matched    uint32         `protobuf:"varint,2,opt,name=matched,proto3" json:"matched,omitempty"`
+	Attributes []*v1.KeyValue `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty"`
 }
 
-func (m *TagValue) Reset()         { *m = TagValue{} }
-func (m *TagValue) String() string { return proto.CompactTextString(m) }
-func (*TagValue) ProtoMessage()    {}
-func (*TagValue) Descriptor() ([]byte, []int) {
+func (m *SpanSet) Reset()         { *m = SpanSet{} }
+func (m *SpanSet) String() string { return proto.CompactTextString(m) }
+func (*SpanSet) ProtoMessage()    {}
+func (*SpanSet) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{16}
 }
-func (m *TagValue) XXX_Unmarshal(b []byte) error {
+func (m *SpanSet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *TagValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SpanSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_TagValue.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SpanSet.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1078,48 +1369,59 @@ func (m *TagValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *TagValue) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TagValue.Merge(m, src)
+func (m *SpanSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanSet.Merge(m, src)
 }
-func (m *TagValue) XXX_Size() int {
+func (m *SpanSet) XXX_Size() int {
 	return m.Size()
 }
-func (m *TagValue) XXX_DiscardUnknown() {
-	xxx_messageInfo_TagValue.DiscardUnknown(m)
+func (m *SpanSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanSet.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TagValue proto.InternalMessageInfo
+var xxx_messageInfo_SpanSet proto.InternalMessageInfo
 
-func (m *TagValue) GetType() string {
+func (m *SpanSet) GetSpans() []*Span {
 	if m != nil {
-		return m.Type
+		return m.Spans
 	}
-	return ""
+	return nil
 }
 
-func (m *TagValue) GetValue() string {
+func (m *SpanSet) GetMatched() uint32 {
 	if m != nil {
-		return m.Value
+		return m.Matched
 	}
-	return ""
+	return 0
 }
 
-type SearchTagValuesV2Response struct {
-	TagValues []*TagValue `protobuf:"bytes,1,rep,name=tagValues,proto3" json:"tagValues,omitempty"`
+func (m *SpanSet) GetAttributes() []*v1.KeyValue {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
 }
 
-func (m *SearchTagValuesV2Response) Reset()         { *m = SearchTagValuesV2Response{} }
-func (m *SearchTagValuesV2Response) String() string { return proto.CompactTextString(m) }
-func (*SearchTagValuesV2Response) ProtoMessage()    {}
-func (*SearchTagValuesV2Response) Descriptor() ([]byte, []int) {
+type Span struct {
+	SpanID            string         `protobuf:"bytes,1,opt,name=spanID,proto3" json:"spanID,omitempty"`
+	Name              string         `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StartTimeUnixNano uint64         `protobuf:"varint,3,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
+	DurationNanos     uint64         `protobuf:"varint,4,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
+	Attributes        []*v1.KeyValue `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty"`
+}
+
+func (m *Span) Reset()         { *m = Span{} }
+func (m *Span) String() string { return proto.CompactTextString(m) }
+func (*Span) ProtoMessage()    {}
+func (*Span) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{17}
 }
-func (m *SearchTagValuesV2Response) XXX_Unmarshal(b []byte) error {
+func (m *Span) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchTagValuesV2Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Span) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchTagValuesV2Response.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Span.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1129,41 +1431,74 @@ func (m *SearchTagValuesV2Response) XXX_Marshal(b []byte, deterministic bool) ([
 		return b[:n], nil
 	}
 }
-func (m *SearchTagValuesV2Response) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagValuesV2Response.Merge(m, src)
+func (m *Span) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Span.Merge(m, src)
 }
-func (m *SearchTagValuesV2Response) XXX_Size() int {
+func (m *Span) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchTagValuesV2Response) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagValuesV2Response.DiscardUnknown(m)
+func (m *Span) XXX_DiscardUnknown() {
+	xxx_messageInfo_Span.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchTagValuesV2Response proto.InternalMessageInfo
+var xxx_messageInfo_Span proto.InternalMessageInfo
 
-func (m *SearchTagValuesV2Response) GetTagValues() []*TagValue {
+func (m *Span) GetSpanID() string {
 	if m != nil {
-		return m.TagValues
+		return m.SpanID
+	}
+	return ""
+}
+
+func (m *Span) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Span) GetStartTimeUnixNano() uint64 {
+	if m != nil {
+		return m.StartTimeUnixNano
+	}
+	return 0
+}
+
+func (m *Span) GetDurationNanos() uint64 {
+	if m != nil {
+		return m.DurationNanos
+	}
+	return 0
+}
+
+func (m *Span) GetAttributes() []*v1.KeyValue {
+	if m != nil {
+		return m.Attributes
 	}
 	return nil
 }
 
-type Trace struct {
-	Batches []*v11.ResourceSpans `protobuf:"bytes,1,rep,name=batches,proto3" json:"batches,omitempty"`
+type SearchMetrics struct {
+	InspectedTraces uint32 `protobuf:"varint,1,opt,name=inspectedTraces,proto3" json:"inspectedTraces,omitempty"`
+	InspectedBytes  uint64 `protobuf:"varint,2,opt,name=inspectedBytes,proto3" json:"inspectedBytes,omitempty"`
+	TotalBlocks     uint32 `protobuf:"varint,3,opt,name=totalBlocks,proto3" json:"totalBlocks,omitempty"`
+	CompletedJobs   uint32 `protobuf:"varint,4,opt,name=completedJobs,proto3" json:"completedJobs,omitempty"`
+	TotalJobs       uint32 `protobuf:"varint,5,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
+	TotalBlockBytes uint64 `protobuf:"varint,6,opt,name=totalBlockBytes,proto3" json:"totalBlockBytes,omitempty"`
 }
 
-func (m *Trace) Reset()         { *m = Trace{} }
-func (m *Trace) String() string { return proto.CompactTextString(m) }
-func (*Trace) ProtoMessage()    {}
-func (*Trace) Descriptor() ([]byte, []int) {
+func (m *SearchMetrics) Reset()         { *m = SearchMetrics{} }
+func (m *SearchMetrics) String() string { return proto.CompactTextString(m) }
+func (*SearchMetrics) ProtoMessage()    {}
+func (*SearchMetrics) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{18}
 }
-func (m *Trace) XXX_Unmarshal(b []byte) error {
+func (m *SearchMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Trace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Trace.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchMetrics.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1173,41 +1508,76 @@ func (m *Trace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Trace) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Trace.Merge(m, src)
+func (m *SearchMetrics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchMetrics.Merge(m, src)
 }
-func (m *Trace) XXX_Size() int {
+func (m *SearchMetrics) XXX_Size() int {
 	return m.Size()
 }
-func (m *Trace) XXX_DiscardUnknown() {
-	xxx_messageInfo_Trace.DiscardUnknown(m)
+func (m *SearchMetrics) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchMetrics.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Trace proto.InternalMessageInfo
+var xxx_messageInfo_SearchMetrics proto.InternalMessageInfo
 
-func (m *Trace) GetBatches() []*v11.ResourceSpans {
+func (m *SearchMetrics) GetInspectedTraces() uint32 {
 	if m != nil {
-		return m.Batches
+		return m.InspectedTraces
 	}
-	return nil
+	return 0
 }
 
-// Write
-type PushResponse struct {
+func (m *SearchMetrics) GetInspectedBytes() uint64 {
+	if m != nil {
+		return m.InspectedBytes
+	}
+	return 0
 }
 
-func (m *PushResponse) Reset()         { *m = PushResponse{} }
-func (m *PushResponse) String() string { return proto.CompactTextString(m) }
-func (*PushResponse) ProtoMessage()    {}
-func (*PushResponse) Descriptor() ([]byte, []int) {
+func (m *SearchMetrics) GetTotalBlocks() uint32 {
+	if m != nil {
+		return m.TotalBlocks
+	}
+	return 0
+}
+
+func (m *SearchMetrics) GetCompletedJobs() uint32 {
+	if m != nil {
+		return m.CompletedJobs
+	}
+	return 0
+}
+
+func (m *SearchMetrics) GetTotalJobs() uint32 {
+	if m != nil {
+		return m.TotalJobs
+	}
+	return 0
+}
+
+func (m *SearchMetrics) GetTotalBlockBytes() uint64 {
+	if m != nil {
+		return m.TotalBlockBytes
+	}
+	return 0
+}
+
+type SearchTagsRequest struct {
+	Scope string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+}
+
+func (m *SearchTagsRequest) Reset()         { *m = SearchTagsRequest{} }
+func (m *SearchTagsRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchTagsRequest) ProtoMessage()    {}
+func (*SearchTagsRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{19}
 }
-func (m *PushResponse) XXX_Unmarshal(b []byte) error {
+func (m *SearchTagsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *PushResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_PushResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchTagsRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1217,41 +1587,41 @@ func (m *PushResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *PushResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PushResponse.Merge(m, src)
+func (m *SearchTagsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagsRequest.Merge(m, src)
 }
-func (m *PushResponse) XXX_Size() int {
+func (m *SearchTagsRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *PushResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_PushResponse.DiscardUnknown(m)
-}
+func (m *SearchTagsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagsRequest.DiscardUnknown(m)
+}
 
-var xxx_messageInfo_PushResponse proto.InternalMessageInfo
+var xxx_messageInfo_SearchTagsRequest proto.InternalMessageInfo
 
-// PushBytesRequest pushes slices of traces, ids and searchdata. Traces are encoded using the
-//  current BatchDecoder in ./pkg/model
-type PushBytesRequest struct {
-	// pre-marshalled Traces. length must match ids
-	Traces []PreallocBytes `protobuf:"bytes,2,rep,name=traces,proto3,customtype=PreallocBytes" json:"traces"`
-	// trace ids. length must match traces
-	Ids []PreallocBytes `protobuf:"bytes,3,rep,name=ids,proto3,customtype=PreallocBytes" json:"ids"`
-	// search data, length must match traces
-	SearchData []PreallocBytes `protobuf:"bytes,4,rep,name=searchData,proto3,customtype=PreallocBytes" json:"searchData"`
+func (m *SearchTagsRequest) GetScope() string {
+	if m != nil {
+		return m.Scope
+	}
+	return ""
 }
 
-func (m *PushBytesRequest) Reset()         { *m = PushBytesRequest{} }
-func (m *PushBytesRequest) String() string { return proto.CompactTextString(m) }
-func (*PushBytesRequest) ProtoMessage()    {}
-func (*PushBytesRequest) Descriptor() ([]byte, []int) {
+type SearchTagsResponse struct {
+	TagNames []string `protobuf:"bytes,1,rep,name=tagNames,proto3" json:"tagNames,omitempty"`
+}
+
+func (m *SearchTagsResponse) Reset()         { *m = SearchTagsResponse{} }
+func (m *SearchTagsResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchTagsResponse) ProtoMessage()    {}
+func (*SearchTagsResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{20}
 }
-func (m *PushBytesRequest) XXX_Unmarshal(b []byte) error {
+func (m *SearchTagsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *PushBytesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchTagsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_PushBytesRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchTagsResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1261,35 +1631,41 @@ func (m *PushBytesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return b[:n], nil
 	}
 }
-func (m *PushBytesRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PushBytesRequest.Merge(m, src)
+func (m *SearchTagsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagsResponse.Merge(m, src)
 }
-func (m *PushBytesRequest) XXX_Size() int {
+func (m *SearchTagsResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *PushBytesRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_PushBytesRequest.DiscardUnknown(m)
+func (m *SearchTagsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagsResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PushBytesRequest proto.InternalMessageInfo
+var xxx_messageInfo_SearchTagsResponse proto.InternalMessageInfo
 
-type PushSpansRequest struct {
-	// just send entire OTel spans for now
-	Batches []*v11.ResourceSpans `protobuf:"bytes,1,rep,name=batches,proto3" json:"batches,omitempty"`
+func (m *SearchTagsResponse) GetTagNames() []string {
+	if m != nil {
+		return m.TagNames
+	}
+	return nil
 }
 
-func (m *PushSpansRequest) Reset()         { *m = PushSpansRequest{} }
-func (m *PushSpansRequest) String() string { return proto.CompactTextString(m) }
-func (*PushSpansRequest) ProtoMessage()    {}
-func (*PushSpansRequest) Descriptor() ([]byte, []int) {
+type SearchTagsV2Response struct {
+	Scopes []*SearchTagsV2Scope `protobuf:"bytes,1,rep,name=scopes,proto3" json:"scopes,omitempty"`
+}
+
+func (m *SearchTagsV2Response) Reset()         { *m = SearchTagsV2Response{} }
+func (m *SearchTagsV2Response) String() string { return proto.CompactTextString(m) }
+func (*SearchTagsV2Response) ProtoMessage()    {}
+func (*SearchTagsV2Response) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{21}
 }
-func (m *PushSpansRequest) XXX_Unmarshal(b []byte) error {
+func (m *SearchTagsV2Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *PushSpansRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchTagsV2Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_PushSpansRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchTagsV2Response.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1299,42 +1675,42 @@ func (m *PushSpansRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return b[:n], nil
 	}
 }
-func (m *PushSpansRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PushSpansRequest.Merge(m, src)
+func (m *SearchTagsV2Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagsV2Response.Merge(m, src)
 }
-func (m *PushSpansRequest) XXX_Size() int {
+func (m *SearchTagsV2Response) XXX_Size() int {
 	return m.Size()
 }
-func (m *PushSpansRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_PushSpansRequest.DiscardUnknown(m)
+func (m *SearchTagsV2Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagsV2Response.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PushSpansRequest proto.InternalMessageInfo
+var xxx_messageInfo_SearchTagsV2Response proto.InternalMessageInfo
 
-func (m *PushSpansRequest) GetBatches() []*v11.ResourceSpans {
+func (m *SearchTagsV2Response) GetScopes() []*SearchTagsV2Scope {
 	if m != nil {
-		return m.Batches
+		return m.Scopes
 	}
 	return nil
 }
 
-type TraceBytes struct {
-	// pre-marshalled Traces
-	Traces [][]byte `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
+type SearchTagsV2Scope struct {
+	Name string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
 }
 
-func (m *TraceBytes) Reset()         { *m = TraceBytes{} }
-func (m *TraceBytes) String() string { return proto.CompactTextString(m) }
-func (*TraceBytes) ProtoMessage()    {}
-func (*TraceBytes) Descriptor() ([]byte, []int) {
+func (m *SearchTagsV2Scope) Reset()         { *m = SearchTagsV2Scope{} }
+func (m *SearchTagsV2Scope) String() string { return proto.CompactTextString(m) }
+func (*SearchTagsV2Scope) ProtoMessage()    {}
+func (*SearchTagsV2Scope) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{22}
 }
-func (m *TraceBytes) XXX_Unmarshal(b []byte) error {
+func (m *SearchTagsV2Scope) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *TraceBytes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchTagsV2Scope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_TraceBytes.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchTagsV2Scope.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1344,44 +1720,49 @@ func (m *TraceBytes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *TraceBytes) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TraceBytes.Merge(m, src)
+func (m *SearchTagsV2Scope) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagsV2Scope.Merge(m, src)
 }
-func (m *TraceBytes) XXX_Size() int {
+func (m *SearchTagsV2Scope) XXX_Size() int {
 	return m.Size()
 }
-func (m *TraceBytes) XXX_DiscardUnknown() {
-	xxx_messageInfo_TraceBytes.DiscardUnknown(m)
+func (m *SearchTagsV2Scope) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagsV2Scope.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TraceBytes proto.InternalMessageInfo
+var xxx_messageInfo_SearchTagsV2Scope proto.InternalMessageInfo
 
-func (m *TraceBytes) GetTraces() [][]byte {
+func (m *SearchTagsV2Scope) GetName() string {
 	if m != nil {
-		return m.Traces
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SearchTagsV2Scope) GetTags() []string {
+	if m != nil {
+		return m.Tags
 	}
 	return nil
 }
 
-// this message exists for marshalling/unmarshalling convenience to/from parquet. in parquet we proto encode
-// links to a column. unfortunately you can't encode a slice directly so we use this wrapper to generate
-// the required marshalling/unmarshalling functions.
-type LinkSlice struct {
-	Links []*v11.Span_Link `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+type SearchTagValuesRequest struct {
+	TagName string `protobuf:"bytes,1,opt,name=tagName,proto3" json:"tagName,omitempty"`
+	Query   string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
 }
 
-func (m *LinkSlice) Reset()         { *m = LinkSlice{} }
-func (m *LinkSlice) String() string { return proto.CompactTextString(m) }
-func (*LinkSlice) ProtoMessage()    {}
-func (*LinkSlice) Descriptor() ([]byte, []int) {
+func (m *SearchTagValuesRequest) Reset()         { *m = SearchTagValuesRequest{} }
+func (m *SearchTagValuesRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchTagValuesRequest) ProtoMessage()    {}
+func (*SearchTagValuesRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{23}
 }
-func (m *LinkSlice) XXX_Unmarshal(b []byte) error {
+func (m *SearchTagValuesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *LinkSlice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchTagValuesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_LinkSlice.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchTagValuesRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1391,43 +1772,48 @@ func (m *LinkSlice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *LinkSlice) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LinkSlice.Merge(m, src)
+func (m *SearchTagValuesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagValuesRequest.Merge(m, src)
 }
-func (m *LinkSlice) XXX_Size() int {
+func (m *SearchTagValuesRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *LinkSlice) XXX_DiscardUnknown() {
-	xxx_messageInfo_LinkSlice.DiscardUnknown(m)
+func (m *SearchTagValuesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagValuesRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LinkSlice proto.InternalMessageInfo
+var xxx_messageInfo_SearchTagValuesRequest proto.InternalMessageInfo
 
-func (m *LinkSlice) GetLinks() []*v11.Span_Link {
+func (m *SearchTagValuesRequest) GetTagName() string {
 	if m != nil {
-		return m.Links
+		return m.TagName
 	}
-	return nil
+	return ""
 }
 
-type SpanMetricsRequest struct {
-	Query   string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	GroupBy string `protobuf:"bytes,2,opt,name=groupBy,proto3" json:"groupBy,omitempty"`
-	Limit   uint64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+func (m *SearchTagValuesRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
 }
 
-func (m *SpanMetricsRequest) Reset()         { *m = SpanMetricsRequest{} }
-func (m *SpanMetricsRequest) String() string { return proto.CompactTextString(m) }
-func (*SpanMetricsRequest) ProtoMessage()    {}
-func (*SpanMetricsRequest) Descriptor() ([]byte, []int) {
+type SearchTagValuesResponse struct {
+	TagValues []string `protobuf:"bytes,1,rep,name=tagValues,proto3" json:"tagValues,omitempty"`
+}
+
+func (m *SearchTagValuesResponse) Reset()         { *m = SearchTagValuesResponse{} }
+func (m *SearchTagValuesResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchTagValuesResponse) ProtoMessage()    {}
+func (*SearchTagValuesResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{24}
 }
-func (m *SpanMetricsRequest) XXX_Unmarshal(b []byte) error {
+func (m *SearchTagValuesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpanMetricsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchTagValuesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpanMetricsRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchTagValuesResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1437,57 +1823,42 @@ func (m *SpanMetricsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *SpanMetricsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpanMetricsRequest.Merge(m, src)
+func (m *SearchTagValuesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagValuesResponse.Merge(m, src)
 }
-func (m *SpanMetricsRequest) XXX_Size() int {
+func (m *SearchTagValuesResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpanMetricsRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpanMetricsRequest.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_SpanMetricsRequest proto.InternalMessageInfo
-
-func (m *SpanMetricsRequest) GetQuery() string {
-	if m != nil {
-		return m.Query
-	}
-	return ""
+func (m *SearchTagValuesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagValuesResponse.DiscardUnknown(m)
 }
 
-func (m *SpanMetricsRequest) GetGroupBy() string {
-	if m != nil {
-		return m.GroupBy
-	}
-	return ""
-}
+var xxx_messageInfo_SearchTagValuesResponse proto.InternalMessageInfo
 
-func (m *SpanMetricsRequest) GetLimit() uint64 {
+func (m *SearchTagValuesResponse) GetTagValues() []string {
 	if m != nil {
-		return m.Limit
+		return m.TagValues
 	}
-	return 0
+	return nil
 }
 
-type SpanMetricsSummaryRequest struct {
-	Query   string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	GroupBy string `protobuf:"bytes,2,opt,name=groupBy,proto3" json:"groupBy,omitempty"`
-	Limit   uint64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+type TagValue struct {
+	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (m *SpanMetricsSummaryRequest) Reset()         { *m = SpanMetricsSummaryRequest{} }
-func (m *SpanMetricsSummaryRequest) String() string { return proto.CompactTextString(m) }
-func (*SpanMetricsSummaryRequest) ProtoMessage()    {}
-func (*SpanMetricsSummaryRequest) Descriptor() ([]byte, []int) {
+func (m *TagValue) Reset()         { *m = TagValue{} }
+func (m *TagValue) String() string { return proto.CompactTextString(m) }
+func (*TagValue) ProtoMessage()    {}
+func (*TagValue) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{25}
 }
-func (m *SpanMetricsSummaryRequest) XXX_Unmarshal(b []byte) error {
+func (m *TagValue) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpanMetricsSummaryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TagValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpanMetricsSummaryRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TagValue.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1497,58 +1868,48 @@ func (m *SpanMetricsSummaryRequest) XXX_Marshal(b []byte, deterministic bool) ([
 		return b[:n], nil
 	}
 }
-func (m *SpanMetricsSummaryRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpanMetricsSummaryRequest.Merge(m, src)
+func (m *TagValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagValue.Merge(m, src)
 }
-func (m *SpanMetricsSummaryRequest) XXX_Size() int {
+func (m *TagValue) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpanMetricsSummaryRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpanMetricsSummaryRequest.DiscardUnknown(m)
+func (m *TagValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagValue.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SpanMetricsSummaryRequest proto.InternalMessageInfo
+var xxx_messageInfo_TagValue proto.InternalMessageInfo
 
-func (m *SpanMetricsSummaryRequest) GetQuery() string {
+func (m *TagValue) GetType() string {
 	if m != nil {
-		return m.Query
+		return m.Type
 	}
 	return ""
 }
 
-func (m *SpanMetricsSummaryRequest) GetGroupBy() string {
+func (m *TagValue) GetValue() string {
 	if m != nil {
-		return m.GroupBy
+		return m.Value
 	}
 	return ""
 }
 
-func (m *SpanMetricsSummaryRequest) GetLimit() uint64 {
-	if m != nil {
-		return m.Limit
-	}
-	return 0
-}
-
-type SpanMetricsResponse struct {
-	Estimated      bool           `protobuf:"varint,1,opt,name=estimated,proto3" json:"estimated,omitempty"`
-	SpanCount      uint64         `protobuf:"varint,2,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
-	ErrorSpanCount uint64         `protobuf:"varint,3,opt,name=errorSpanCount,proto3" json:"errorSpanCount,omitempty"`
-	Metrics        []*SpanMetrics `protobuf:"bytes,4,rep,name=metrics,proto3" json:"metrics,omitempty"`
+type SearchTagValuesV2Response struct {
+	TagValues []*TagValue `protobuf:"bytes,1,rep,name=tagValues,proto3" json:"tagValues,omitempty"`
 }
 
-func (m *SpanMetricsResponse) Reset()         { *m = SpanMetricsResponse{} }
-func (m *SpanMetricsResponse) String() string { return proto.CompactTextString(m) }
-func (*SpanMetricsResponse) ProtoMessage()    {}
-func (*SpanMetricsResponse) Descriptor() ([]byte, []int) {
+func (m *SearchTagValuesV2Response) Reset()         { *m = SearchTagValuesV2Response{} }
+func (m *SearchTagValuesV2Response) String() string { return proto.CompactTextString(m) }
+func (*SearchTagValuesV2Response) ProtoMessage()    {}
+func (*SearchTagValuesV2Response) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{26}
 }
-func (m *SpanMetricsResponse) XXX_Unmarshal(b []byte) error {
+func (m *SearchTagValuesV2Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpanMetricsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchTagValuesV2Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpanMetricsResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchTagValuesV2Response.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1558,63 +1919,43 @@ func (m *SpanMetricsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *SpanMetricsResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpanMetricsResponse.Merge(m, src)
+func (m *SearchTagValuesV2Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagValuesV2Response.Merge(m, src)
 }
-func (m *SpanMetricsResponse) XXX_Size() int {
+func (m *SearchTagValuesV2Response) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpanMetricsResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpanMetricsResponse.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_SpanMetricsResponse proto.InternalMessageInfo
-
-func (m *SpanMetricsResponse) GetEstimated() bool {
-	if m != nil {
-		return m.Estimated
-	}
-	return false
-}
-
-func (m *SpanMetricsResponse) GetSpanCount() uint64 {
-	if m != nil {
-		return m.SpanCount
-	}
-	return 0
+func (m *SearchTagValuesV2Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagValuesV2Response.DiscardUnknown(m)
 }
 
-func (m *SpanMetricsResponse) GetErrorSpanCount() uint64 {
-	if m != nil {
-		return m.ErrorSpanCount
-	}
-	return 0
-}
+var xxx_messageInfo_SearchTagValuesV2Response proto.InternalMessageInfo
 
-func (m *SpanMetricsResponse) GetMetrics() []*SpanMetrics {
+func (m *SearchTagValuesV2Response) GetTagValues() []*TagValue {
 	if m != nil {
-		return m.Metrics
+		return m.TagValues
 	}
 	return nil
 }
 
-type RawHistogram struct {
-	Bucket uint64 `protobuf:"varint,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
-	Count  uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+type TagValueCount struct {
+	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Count uint64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
 }
 
-func (m *RawHistogram) Reset()         { *m = RawHistogram{} }
-func (m *RawHistogram) String() string { return proto.CompactTextString(m) }
-func (*RawHistogram) ProtoMessage()    {}
-func (*RawHistogram) Descriptor() ([]byte, []int) {
+func (m *TagValueCount) Reset()         { *m = TagValueCount{} }
+func (m *TagValueCount) String() string { return proto.CompactTextString(m) }
+func (*TagValueCount) ProtoMessage()    {}
+func (*TagValueCount) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{27}
 }
-func (m *RawHistogram) XXX_Unmarshal(b []byte) error {
+func (m *TagValueCount) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *RawHistogram) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TagValueCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_RawHistogram.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TagValueCount.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1624,50 +1965,60 @@ func (m *RawHistogram) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *RawHistogram) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RawHistogram.Merge(m, src)
+func (m *TagValueCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagValueCount.Merge(m, src)
 }
-func (m *RawHistogram) XXX_Size() int {
+func (m *TagValueCount) XXX_Size() int {
 	return m.Size()
 }
-func (m *RawHistogram) XXX_DiscardUnknown() {
-	xxx_messageInfo_RawHistogram.DiscardUnknown(m)
+func (m *TagValueCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagValueCount.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RawHistogram proto.InternalMessageInfo
+var xxx_messageInfo_TagValueCount proto.InternalMessageInfo
 
-func (m *RawHistogram) GetBucket() uint64 {
+func (m *TagValueCount) GetType() string {
 	if m != nil {
-		return m.Bucket
+		return m.Type
 	}
-	return 0
+	return ""
 }
 
-func (m *RawHistogram) GetCount() uint64 {
+func (m *TagValueCount) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *TagValueCount) GetCount() uint64 {
 	if m != nil {
 		return m.Count
 	}
 	return 0
 }
 
-type SpanMetrics struct {
-	LatencyHistogram []*RawHistogram `protobuf:"bytes,1,rep,name=latency_histogram,json=latencyHistogram,proto3" json:"latency_histogram,omitempty"`
-	Static           *TraceQLStatic  `protobuf:"bytes,2,opt,name=static,proto3" json:"static,omitempty"`
-	Errors           uint64          `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+type SearchTagValueStatsResponse struct {
+	// values ordered by count, highest first
+	Values []*TagValueCount `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	// estimated number of distinct values
+	Cardinality uint64 `protobuf:"varint,2,opt,name=cardinality,proto3" json:"cardinality,omitempty"`
+	// HyperLogLog sketch of the distinct values. used to combine the cardinality of responses
+	Sketch []byte `protobuf:"bytes,3,opt,name=sketch,proto3" json:"sketch,omitempty"`
 }
 
-func (m *SpanMetrics) Reset()         { *m = SpanMetrics{} }
-func (m *SpanMetrics) String() string { return proto.CompactTextString(m) }
-func (*SpanMetrics) ProtoMessage()    {}
-func (*SpanMetrics) Descriptor() ([]byte, []int) {
+func (m *SearchTagValueStatsResponse) Reset()         { *m = SearchTagValueStatsResponse{} }
+func (m *SearchTagValueStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchTagValueStatsResponse) ProtoMessage()    {}
+func (*SearchTagValueStatsResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{28}
 }
-func (m *SpanMetrics) XXX_Unmarshal(b []byte) error {
+func (m *SearchTagValueStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpanMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchTagValueStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpanMetrics.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchTagValueStatsResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1677,61 +2028,55 @@ func (m *SpanMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *SpanMetrics) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpanMetrics.Merge(m, src)
+func (m *SearchTagValueStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagValueStatsResponse.Merge(m, src)
 }
-func (m *SpanMetrics) XXX_Size() int {
+func (m *SearchTagValueStatsResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpanMetrics) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpanMetrics.DiscardUnknown(m)
+func (m *SearchTagValueStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagValueStatsResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SpanMetrics proto.InternalMessageInfo
+var xxx_messageInfo_SearchTagValueStatsResponse proto.InternalMessageInfo
 
-func (m *SpanMetrics) GetLatencyHistogram() []*RawHistogram {
+func (m *SearchTagValueStatsResponse) GetValues() []*TagValueCount {
 	if m != nil {
-		return m.LatencyHistogram
+		return m.Values
 	}
 	return nil
 }
 
-func (m *SpanMetrics) GetStatic() *TraceQLStatic {
+func (m *SearchTagValueStatsResponse) GetCardinality() uint64 {
 	if m != nil {
-		return m.Static
+		return m.Cardinality
 	}
-	return nil
+	return 0
 }
 
-func (m *SpanMetrics) GetErrors() uint64 {
+func (m *SearchTagValueStatsResponse) GetSketch() []byte {
 	if m != nil {
-		return m.Errors
+		return m.Sketch
 	}
-	return 0
+	return nil
 }
 
-type SpanMetricsSummary struct {
-	SpanCount      uint64         `protobuf:"varint,1,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
-	ErrorSpanCount uint64         `protobuf:"varint,2,opt,name=errorSpanCount,proto3" json:"errorSpanCount,omitempty"`
-	Static         *TraceQLStatic `protobuf:"bytes,3,opt,name=static,proto3" json:"static,omitempty"`
-	P99            uint64         `protobuf:"varint,4,opt,name=p99,proto3" json:"p99,omitempty"`
-	P95            uint64         `protobuf:"varint,5,opt,name=p95,proto3" json:"p95,omitempty"`
-	P90            uint64         `protobuf:"varint,6,opt,name=p90,proto3" json:"p90,omitempty"`
-	P50            uint64         `protobuf:"varint,7,opt,name=p50,proto3" json:"p50,omitempty"`
+type Trace struct {
+	Batches []*v11.ResourceSpans `protobuf:"bytes,1,rep,name=batches,proto3" json:"batches,omitempty"`
 }
 
-func (m *SpanMetricsSummary) Reset()         { *m = SpanMetricsSummary{} }
-func (m *SpanMetricsSummary) String() string { return proto.CompactTextString(m) }
-func (*SpanMetricsSummary) ProtoMessage()    {}
-func (*SpanMetricsSummary) Descriptor() ([]byte, []int) {
+func (m *Trace) Reset()         { *m = Trace{} }
+func (m *Trace) String() string { return proto.CompactTextString(m) }
+func (*Trace) ProtoMessage()    {}
+func (*Trace) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{29}
 }
-func (m *SpanMetricsSummary) XXX_Unmarshal(b []byte) error {
+func (m *Trace) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpanMetricsSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Trace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpanMetricsSummary.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Trace.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1741,83 +2086,41 @@ func (m *SpanMetricsSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *SpanMetricsSummary) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpanMetricsSummary.Merge(m, src)
+func (m *Trace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Trace.Merge(m, src)
 }
-func (m *SpanMetricsSummary) XXX_Size() int {
+func (m *Trace) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpanMetricsSummary) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpanMetricsSummary.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_SpanMetricsSummary proto.InternalMessageInfo
-
-func (m *SpanMetricsSummary) GetSpanCount() uint64 {
-	if m != nil {
-		return m.SpanCount
-	}
-	return 0
+func (m *Trace) XXX_DiscardUnknown() {
+	xxx_messageInfo_Trace.DiscardUnknown(m)
 }
 
-func (m *SpanMetricsSummary) GetErrorSpanCount() uint64 {
-	if m != nil {
-		return m.ErrorSpanCount
-	}
-	return 0
-}
+var xxx_messageInfo_Trace proto.InternalMessageInfo
 
-func (m *SpanMetricsSummary) GetStatic() *TraceQLStatic {
+func (m *Trace) GetBatches() []*v11.ResourceSpans {
 	if m != nil {
-		return m.Static
+		return m.Batches
 	}
 	return nil
 }
 
-func (m *SpanMetricsSummary) GetP99() uint64 {
-	if m != nil {
-		return m.P99
-	}
-	return 0
-}
-
-func (m *SpanMetricsSummary) GetP95() uint64 {
-	if m != nil {
-		return m.P95
-	}
-	return 0
-}
-
-func (m *SpanMetricsSummary) GetP90() uint64 {
-	if m != nil {
-		return m.P90
-	}
-	return 0
-}
-
-func (m *SpanMetricsSummary) GetP50() uint64 {
-	if m != nil {
-		return m.P50
-	}
-	return 0
-}
-
-type SpanMetricsSummaryResponse struct {
-	Summaries []*SpanMetricsSummary `protobuf:"bytes,1,rep,name=summaries,proto3" json:"summaries,omitempty"`
+// Write
+type PushResponse struct {
 }
 
-func (m *SpanMetricsSummaryResponse) Reset()         { *m = SpanMetricsSummaryResponse{} }
-func (m *SpanMetricsSummaryResponse) String() string { return proto.CompactTextString(m) }
-func (*SpanMetricsSummaryResponse) ProtoMessage()    {}
-func (*SpanMetricsSummaryResponse) Descriptor() ([]byte, []int) {
+func (m *PushResponse) Reset()         { *m = PushResponse{} }
+func (m *PushResponse) String() string { return proto.CompactTextString(m) }
+func (*PushResponse) ProtoMessage()    {}
+func (*PushResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f22805646f4f62b6, []int{30}
 }
-func (m *SpanMetricsSummaryResponse) XXX_Unmarshal(b []byte) error {
+func (m *PushResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpanMetricsSummaryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *PushResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpanMetricsSummaryResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_PushResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1827,48 +2130,79 @@ func (m *SpanMetricsSummaryResponse) XXX_Marshal(b []byte, deterministic bool) (
 		return b[:n], nil
 	}
 }
-func (m *SpanMetricsSummaryResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpanMetricsSummaryResponse.Merge(m, src)
+func (m *PushResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushResponse.Merge(m, src)
 }
-func (m *SpanMetricsSummaryResponse) XXX_Size() int {
+func (m *PushResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpanMetricsSummaryResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpanMetricsSummaryResponse.DiscardUnknown(m)
+func (m *PushResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SpanMetricsSummaryResponse proto.InternalMessageInfo
+var xxx_messageInfo_PushResponse proto.InternalMessageInfo
 
-func (m *SpanMetricsSummaryResponse) GetSummaries() []*SpanMetricsSummary {
-	if m != nil {
-		return m.Summaries
-	}
-	return nil
+// PushBytesRequest pushes slices of traces, ids and searchdata. Traces are encoded using the
+//  current BatchDecoder in ./pkg/model
+type PushBytesRequest struct {
+	// pre-marshalled Traces. length must match ids
+	Traces []PreallocBytes `protobuf:"bytes,2,rep,name=traces,proto3,customtype=PreallocBytes" json:"traces"`
+	// trace ids. length must match traces
+	Ids []PreallocBytes `protobuf:"bytes,3,rep,name=ids,proto3,customtype=PreallocBytes" json:"ids"`
+	// search data, length must match traces
+	SearchData []PreallocBytes `protobuf:"bytes,4,rep,name=searchData,proto3,customtype=PreallocBytes" json:"searchData"`
 }
 
-type TraceQLStatic struct {
-	Type   int32   `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
-	N      int64   `protobuf:"varint,2,opt,name=n,proto3" json:"n,omitempty"`
-	F      float64 `protobuf:"fixed64,3,opt,name=f,proto3" json:"f,omitempty"`
-	S      string  `protobuf:"bytes,4,opt,name=s,proto3" json:"s,omitempty"`
-	B      bool    `protobuf:"varint,5,opt,name=b,proto3" json:"b,omitempty"`
-	D      uint64  `protobuf:"varint,6,opt,name=d,proto3" json:"d,omitempty"`
-	Status int32   `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
-	Kind   int32   `protobuf:"varint,8,opt,name=kind,proto3" json:"kind,omitempty"`
+func (m *PushBytesRequest) Reset()         { *m = PushBytesRequest{} }
+func (m *PushBytesRequest) String() string { return proto.CompactTextString(m) }
+func (*PushBytesRequest) ProtoMessage()    {}
+func (*PushBytesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{31}
+}
+func (m *PushBytesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PushBytesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PushBytesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PushBytesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushBytesRequest.Merge(m, src)
+}
+func (m *PushBytesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PushBytesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushBytesRequest.DiscardUnknown(m)
 }
 
-func (m *TraceQLStatic) Reset()         { *m = TraceQLStatic{} }
-func (m *TraceQLStatic) String() string { return proto.CompactTextString(m) }
-func (*TraceQLStatic) ProtoMessage()    {}
-func (*TraceQLStatic) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{31}
+var xxx_messageInfo_PushBytesRequest proto.InternalMessageInfo
+
+type PushSpansRequest struct {
+	// just send entire OTel spans for now
+	Batches []*v11.ResourceSpans `protobuf:"bytes,1,rep,name=batches,proto3" json:"batches,omitempty"`
 }
-func (m *TraceQLStatic) XXX_Unmarshal(b []byte) error {
+
+func (m *PushSpansRequest) Reset()         { *m = PushSpansRequest{} }
+func (m *PushSpansRequest) String() string { return proto.CompactTextString(m) }
+func (*PushSpansRequest) ProtoMessage()    {}
+func (*PushSpansRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{32}
+}
+func (m *PushSpansRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *TraceQLStatic) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *PushSpansRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_TraceQLStatic.Marshal(b, m, deterministic)
+		return xxx_messageInfo_PushSpansRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1878,1938 +2212,1970 @@ func (m *TraceQLStatic) XXX_Marshal(b []byte, deterministic bool) ([]byte, error
 		return b[:n], nil
 	}
 }
-func (m *TraceQLStatic) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TraceQLStatic.Merge(m, src)
+func (m *PushSpansRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushSpansRequest.Merge(m, src)
 }
-func (m *TraceQLStatic) XXX_Size() int {
+func (m *PushSpansRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *TraceQLStatic) XXX_DiscardUnknown() {
-	xxx_messageInfo_TraceQLStatic.DiscardUnknown(m)
+func (m *PushSpansRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushSpansRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TraceQLStatic proto.InternalMessageInfo
+var xxx_messageInfo_PushSpansRequest proto.InternalMessageInfo
 
-func (m *TraceQLStatic) GetType() int32 {
+func (m *PushSpansRequest) GetBatches() []*v11.ResourceSpans {
 	if m != nil {
-		return m.Type
+		return m.Batches
 	}
-	return 0
+	return nil
 }
 
-func (m *TraceQLStatic) GetN() int64 {
-	if m != nil {
-		return m.N
+type TraceBytes struct {
+	// pre-marshalled Traces
+	Traces [][]byte `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
+}
+
+func (m *TraceBytes) Reset()         { *m = TraceBytes{} }
+func (m *TraceBytes) String() string { return proto.CompactTextString(m) }
+func (*TraceBytes) ProtoMessage()    {}
+func (*TraceBytes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{33}
+}
+func (m *TraceBytes) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceBytes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceBytes.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return 0
+}
+func (m *TraceBytes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceBytes.Merge(m, src)
+}
+func (m *TraceBytes) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceBytes) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceBytes.DiscardUnknown(m)
 }
 
-func (m *TraceQLStatic) GetF() float64 {
+var xxx_messageInfo_TraceBytes proto.InternalMessageInfo
+
+func (m *TraceBytes) GetTraces() [][]byte {
 	if m != nil {
-		return m.F
+		return m.Traces
 	}
-	return 0
+	return nil
 }
 
-func (m *TraceQLStatic) GetS() string {
-	if m != nil {
-		return m.S
+// this message exists for marshalling/unmarshalling convenience to/from parquet. in parquet we proto encode
+// links to a column. unfortunately you can't encode a slice directly so we use this wrapper to generate
+// the required marshalling/unmarshalling functions.
+type LinkSlice struct {
+	Links []*v11.Span_Link `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+}
+
+func (m *LinkSlice) Reset()         { *m = LinkSlice{} }
+func (m *LinkSlice) String() string { return proto.CompactTextString(m) }
+func (*LinkSlice) ProtoMessage()    {}
+func (*LinkSlice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{34}
+}
+func (m *LinkSlice) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LinkSlice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LinkSlice.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return ""
+}
+func (m *LinkSlice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinkSlice.Merge(m, src)
+}
+func (m *LinkSlice) XXX_Size() int {
+	return m.Size()
+}
+func (m *LinkSlice) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinkSlice.DiscardUnknown(m)
 }
 
-func (m *TraceQLStatic) GetB() bool {
+var xxx_messageInfo_LinkSlice proto.InternalMessageInfo
+
+func (m *LinkSlice) GetLinks() []*v11.Span_Link {
 	if m != nil {
-		return m.B
+		return m.Links
 	}
-	return false
+	return nil
 }
 
-func (m *TraceQLStatic) GetD() uint64 {
+type SpanMetricsRequest struct {
+	Query   string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	GroupBy string `protobuf:"bytes,2,opt,name=groupBy,proto3" json:"groupBy,omitempty"`
+	Limit   uint64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (m *SpanMetricsRequest) Reset()         { *m = SpanMetricsRequest{} }
+func (m *SpanMetricsRequest) String() string { return proto.CompactTextString(m) }
+func (*SpanMetricsRequest) ProtoMessage()    {}
+func (*SpanMetricsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{35}
+}
+func (m *SpanMetricsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SpanMetricsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpanMetricsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SpanMetricsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanMetricsRequest.Merge(m, src)
+}
+func (m *SpanMetricsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpanMetricsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanMetricsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpanMetricsRequest proto.InternalMessageInfo
+
+func (m *SpanMetricsRequest) GetQuery() string {
 	if m != nil {
-		return m.D
+		return m.Query
 	}
-	return 0
+	return ""
 }
 
-func (m *TraceQLStatic) GetStatus() int32 {
+func (m *SpanMetricsRequest) GetGroupBy() string {
 	if m != nil {
-		return m.Status
+		return m.GroupBy
 	}
-	return 0
+	return ""
 }
 
-func (m *TraceQLStatic) GetKind() int32 {
+func (m *SpanMetricsRequest) GetLimit() uint64 {
 	if m != nil {
-		return m.Kind
+		return m.Limit
 	}
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*TraceByIDRequest)(nil), "tempopb.TraceByIDRequest")
-	proto.RegisterType((*TraceByIDResponse)(nil), "tempopb.TraceByIDResponse")
-	proto.RegisterType((*TraceByIDMetrics)(nil), "tempopb.TraceByIDMetrics")
-	proto.RegisterType((*SearchRequest)(nil), "tempopb.SearchRequest")
-	proto.RegisterMapType((map[string]string)(nil), "tempopb.SearchRequest.TagsEntry")
-	proto.RegisterType((*SearchBlockRequest)(nil), "tempopb.SearchBlockRequest")
-	proto.RegisterType((*SearchResponse)(nil), "tempopb.SearchResponse")
-	proto.RegisterType((*TraceSearchMetadata)(nil), "tempopb.TraceSearchMetadata")
-	proto.RegisterType((*SpanSet)(nil), "tempopb.SpanSet")
-	proto.RegisterType((*Span)(nil), "tempopb.Span")
-	proto.RegisterType((*SearchMetrics)(nil), "tempopb.SearchMetrics")
-	proto.RegisterType((*SearchTagsRequest)(nil), "tempopb.SearchTagsRequest")
-	proto.RegisterType((*SearchTagsResponse)(nil), "tempopb.SearchTagsResponse")
-	proto.RegisterType((*SearchTagsV2Response)(nil), "tempopb.SearchTagsV2Response")
-	proto.RegisterType((*SearchTagsV2Scope)(nil), "tempopb.SearchTagsV2Scope")
-	proto.RegisterType((*SearchTagValuesRequest)(nil), "tempopb.SearchTagValuesRequest")
-	proto.RegisterType((*SearchTagValuesResponse)(nil), "tempopb.SearchTagValuesResponse")
-	proto.RegisterType((*TagValue)(nil), "tempopb.TagValue")
-	proto.RegisterType((*SearchTagValuesV2Response)(nil), "tempopb.SearchTagValuesV2Response")
-	proto.RegisterType((*Trace)(nil), "tempopb.Trace")
-	proto.RegisterType((*PushResponse)(nil), "tempopb.PushResponse")
-	proto.RegisterType((*PushBytesRequest)(nil), "tempopb.PushBytesRequest")
-	proto.RegisterType((*PushSpansRequest)(nil), "tempopb.PushSpansRequest")
-	proto.RegisterType((*TraceBytes)(nil), "tempopb.TraceBytes")
-	proto.RegisterType((*LinkSlice)(nil), "tempopb.LinkSlice")
-	proto.RegisterType((*SpanMetricsRequest)(nil), "tempopb.SpanMetricsRequest")
-	proto.RegisterType((*SpanMetricsSummaryRequest)(nil), "tempopb.SpanMetricsSummaryRequest")
-	proto.RegisterType((*SpanMetricsResponse)(nil), "tempopb.SpanMetricsResponse")
-	proto.RegisterType((*RawHistogram)(nil), "tempopb.RawHistogram")
-	proto.RegisterType((*SpanMetrics)(nil), "tempopb.SpanMetrics")
-	proto.RegisterType((*SpanMetricsSummary)(nil), "tempopb.SpanMetricsSummary")
-	proto.RegisterType((*SpanMetricsSummaryResponse)(nil), "tempopb.SpanMetricsSummaryResponse")
-	proto.RegisterType((*TraceQLStatic)(nil), "tempopb.TraceQLStatic")
+type SpanMetricsSummaryRequest struct {
+	Query   string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	GroupBy string `protobuf:"bytes,2,opt,name=groupBy,proto3" json:"groupBy,omitempty"`
+	Limit   uint64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 }
 
-func init() { proto.RegisterFile("pkg/tempopb/tempo.proto", fileDescriptor_f22805646f4f62b6) }
-
-var fileDescriptor_f22805646f4f62b6 = []byte{
-	// 1834 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x4f, 0x73, 0x1b, 0x49,
-	0x15, 0xf7, 0x58, 0xff, 0xac, 0x27, 0x3b, 0xb1, 0x7b, 0x13, 0x47, 0x51, 0x82, 0xe3, 0x9a, 0x4d,
-	0x81, 0xa1, 0x58, 0xd9, 0xd1, 0xc6, 0xb5, 0x64, 0x43, 0x41, 0x21, 0x1c, 0x92, 0xec, 0xc6, 0x4b,
-	0xb6, 0x65, 0x42, 0xd5, 0x5e, 0xb6, 0x5a, 0x33, 0x1d, 0x65, 0xb0, 0x34, 0xa3, 0x9d, 0x69, 0x99,
-	0x88, 0x13, 0x17, 0x38, 0x71, 0xe0, 0xc2, 0x81, 0x0b, 0x55, 0x9c, 0x28, 0x3e, 0x07, 0x97, 0x3d,
-	0x51, 0x5b, 0x9c, 0x28, 0x0e, 0x5b, 0x54, 0xf2, 0x09, 0x38, 0x72, 0xa3, 0xde, 0xeb, 0xee, 0xf9,
-	0x27, 0xd9, 0xcb, 0xc2, 0x9e, 0xd4, 0xef, 0xd7, 0xbf, 0x7e, 0xfd, 0xfa, 0xf5, 0x7b, 0xaf, 0xdf,
-	0x08, 0xae, 0x4d, 0x4f, 0x47, 0xfb, 0x4a, 0x4e, 0xa6, 0xd1, 0x74, 0xa8, 0x7f, 0xbb, 0xd3, 0x38,
-	0x52, 0x11, 0x6b, 0x18, 0xb0, 0x73, 0x45, 0xc5, 0xc2, 0x93, 0xfb, 0x67, 0x77, 0xf6, 0x69, 0xa0,
-	0xa7, 0x3b, 0xdb, 0x5e, 0x34, 0x99, 0x44, 0x21, 0xc2, 0x7a, 0x64, 0xf0, 0xb7, 0x46, 0x81, 0x7a,
-	0x31, 0x1b, 0x76, 0xbd, 0x68, 0xb2, 0x3f, 0x8a, 0x46, 0xd1, 0x3e, 0xc1, 0xc3, 0xd9, 0x73, 0x92,
-	0x48, 0xa0, 0x91, 0xa6, 0xbb, 0xbf, 0x76, 0x60, 0xf3, 0x04, 0xd5, 0xf6, 0xe7, 0x8f, 0x8f, 0xb8,
-	0xfc, 0x64, 0x26, 0x13, 0xc5, 0xda, 0xd0, 0xa0, 0xad, 0x1e, 0x1f, 0xb5, 0x9d, 0x5d, 0x67, 0x6f,
-	0x9d, 0x5b, 0x91, 0xed, 0x00, 0x0c, 0xc7, 0x91, 0x77, 0x3a, 0x50, 0x22, 0x56, 0xed, 0xd5, 0x5d,
-	0x67, 0xaf, 0xc9, 0x73, 0x08, 0xeb, 0xc0, 0x1a, 0x49, 0x0f, 0x42, 0xbf, 0x5d, 0xa1, 0xd9, 0x54,
-	0x66, 0x37, 0xa1, 0xf9, 0xc9, 0x4c, 0xc6, 0xf3, 0xe3, 0xc8, 0x97, 0xed, 0x1a, 0x4d, 0x66, 0x80,
-	0x1b, 0xc2, 0x56, 0xce, 0x8e, 0x64, 0x1a, 0x85, 0x89, 0x64, 0xb7, 0xa1, 0x46, 0x3b, 0x93, 0x19,
-	0xad, 0xde, 0xa5, 0xae, 0xf1, 0x49, 0x97, 0xa8, 0x5c, 0x4f, 0xb2, 0xb7, 0xa1, 0x31, 0x91, 0x2a,
-	0x0e, 0xbc, 0x84, 0x2c, 0x6a, 0xf5, 0xae, 0x17, 0x79, 0xa8, 0xf2, 0x58, 0x13, 0xb8, 0x65, 0xba,
-	0x2c, 0x77, 0x6e, 0x33, 0xe9, 0xfe, 0x75, 0x15, 0x36, 0x06, 0x52, 0xc4, 0xde, 0x0b, 0xeb, 0x89,
-	0x77, 0xa1, 0x7a, 0x22, 0x46, 0x49, 0xdb, 0xd9, 0xad, 0xec, 0xb5, 0x7a, 0xbb, 0xa9, 0xde, 0x02,
-	0xab, 0x8b, 0x94, 0x07, 0xa1, 0x8a, 0xe7, 0xfd, 0xea, 0xa7, 0x9f, 0xdf, 0x5a, 0xe1, 0xb4, 0x86,
-	0xdd, 0x86, 0x8d, 0xe3, 0x20, 0x3c, 0x9a, 0xc5, 0x42, 0x05, 0x51, 0x78, 0xac, 0x8d, 0xdb, 0xe0,
-	0x45, 0x90, 0x58, 0xe2, 0x65, 0x8e, 0x55, 0x31, 0xac, 0x3c, 0xc8, 0xae, 0x40, 0xed, 0x49, 0x30,
-	0x09, 0x54, 0xbb, 0x4a, 0xb3, 0x5a, 0x40, 0x34, 0xa1, 0x8b, 0xa8, 0x69, 0x94, 0x04, 0xb6, 0x09,
-	0x15, 0x19, 0xfa, 0xed, 0x3a, 0x61, 0x38, 0x44, 0xde, 0x87, 0xe8, 0xe8, 0xf6, 0x1a, 0x79, 0x5d,
-	0x0b, 0x6c, 0x0f, 0x2e, 0x0f, 0xa6, 0x22, 0x4c, 0x9e, 0xca, 0x18, 0x7f, 0x07, 0x52, 0xb5, 0x9b,
-	0xb4, 0xa6, 0x0c, 0x77, 0xde, 0x81, 0x66, 0x7a, 0x44, 0x54, 0x7f, 0x2a, 0xe7, 0x74, 0x23, 0x4d,
-	0x8e, 0x43, 0x54, 0x7f, 0x26, 0xc6, 0x33, 0x69, 0xe2, 0x41, 0x0b, 0xef, 0xae, 0x7e, 0xc7, 0x71,
-	0x7f, 0x59, 0x01, 0xa6, 0x5d, 0xd5, 0xc7, 0x28, 0xb0, 0x5e, 0xbd, 0x0b, 0xcd, 0xc4, 0x3a, 0xd0,
-	0x5c, 0xed, 0xf6, 0x72, 0xd7, 0xf2, 0x8c, 0x88, 0x51, 0x49, 0xb1, 0xf4, 0xf8, 0xc8, 0x6c, 0x64,
-	0x45, 0x8c, 0x2c, 0x3a, 0xfa, 0x53, 0x31, 0x92, 0xc6, 0x7f, 0x19, 0x80, 0x1e, 0x9e, 0x8a, 0x91,
-	0x4c, 0x4e, 0x22, 0xad, 0xda, 0xf8, 0xb0, 0x08, 0x62, 0xe4, 0xca, 0xd0, 0x8b, 0xfc, 0x20, 0x1c,
-	0x99, 0xe0, 0x4c, 0x65, 0xd4, 0x10, 0x84, 0xbe, 0x7c, 0x89, 0xea, 0x06, 0xc1, 0x2f, 0xa4, 0xf1,
-	0x6d, 0x11, 0x64, 0x2e, 0xac, 0xab, 0x48, 0x89, 0x31, 0x97, 0x5e, 0x14, 0xfb, 0x49, 0xbb, 0x41,
-	0xa4, 0x02, 0x86, 0x1c, 0x5f, 0x28, 0xf1, 0xc0, 0xee, 0xa4, 0x2f, 0xa4, 0x80, 0xe1, 0x39, 0xcf,
-	0x64, 0x9c, 0x04, 0x51, 0x48, 0xf7, 0xd1, 0xe4, 0x56, 0x64, 0x0c, 0xaa, 0x09, 0x6e, 0x0f, 0xbb,
-	0xce, 0x5e, 0x95, 0xd3, 0x18, 0x33, 0xf2, 0x79, 0x14, 0x29, 0x19, 0x93, 0x61, 0x2d, 0xda, 0x33,
-	0x87, 0xb8, 0x2f, 0xe1, 0x92, 0xf5, 0xa8, 0x49, 0xaa, 0xbb, 0x50, 0xa7, 0xbc, 0xb1, 0x51, 0x7d,
-	0xb3, 0x98, 0x2d, 0x9a, 0x7d, 0x2c, 0x95, 0x40, 0xab, 0xb8, 0xe1, 0xb2, 0x83, 0x72, 0x92, 0x95,
-	0x6f, 0x6c, 0x21, 0xc3, 0xfe, 0xed, 0xc0, 0x1b, 0x4b, 0x34, 0x96, 0xab, 0x4b, 0x33, 0xab, 0x2e,
-	0x7b, 0x70, 0x39, 0x8e, 0x22, 0x35, 0x90, 0xf1, 0x59, 0xe0, 0xc9, 0x0f, 0xc4, 0xc4, 0x86, 0x54,
-	0x19, 0xc6, 0x1b, 0x41, 0x88, 0xd4, 0x13, 0x4f, 0x17, 0x9b, 0x22, 0xc8, 0xbe, 0x0d, 0x5b, 0x14,
-	0x06, 0x27, 0xc1, 0x44, 0xfe, 0x24, 0x0c, 0x5e, 0x7e, 0x20, 0xc2, 0x88, 0x6e, 0xbf, 0xca, 0x17,
-	0x27, 0xd0, 0x93, 0x7e, 0x96, 0x86, 0x3a, 0xa5, 0x72, 0x08, 0xfb, 0x16, 0x34, 0x12, 0x93, 0x27,
-	0x75, 0xf2, 0xc0, 0x66, 0xe6, 0x01, 0x8d, 0x73, 0x4b, 0x70, 0x7f, 0xe5, 0x40, 0xc3, 0x80, 0xec,
-	0x4d, 0xa8, 0x21, 0x6c, 0xdd, 0xbd, 0x51, 0x58, 0xc5, 0xf5, 0x1c, 0x3a, 0x65, 0x22, 0x94, 0xf7,
-	0x42, 0xfa, 0xa6, 0x4c, 0x58, 0x91, 0xdd, 0x07, 0x10, 0x4a, 0xc5, 0xc1, 0x70, 0xa6, 0x24, 0x56,
-	0x07, 0xd4, 0x71, 0x23, 0xd5, 0x61, 0x6a, 0xff, 0xd9, 0x9d, 0xee, 0xfb, 0x72, 0xfe, 0x0c, 0x13,
-	0x8f, 0xe7, 0xe8, 0xee, 0x5f, 0x1c, 0xa8, 0xe2, 0x36, 0x6c, 0x1b, 0xea, 0xb8, 0x51, 0xea, 0x73,
-	0x23, 0x61, 0x48, 0x85, 0x99, 0x9f, 0x69, 0xbc, 0xdc, 0x6d, 0x95, 0xf3, 0xdc, 0x76, 0x1b, 0x36,
-	0xac, 0x93, 0x50, 0x4e, 0x8c, 0x83, 0x8b, 0x60, 0xe9, 0x14, 0xb5, 0x2f, 0x77, 0x8a, 0x7f, 0x39,
-	0xb6, 0x2e, 0x9b, 0x20, 0xc3, 0x48, 0x09, 0xc2, 0x64, 0x2a, 0x3d, 0x25, 0xfd, 0x13, 0x1b, 0xcc,
-	0x54, 0xbb, 0x4a, 0x30, 0xfb, 0x3a, 0x5c, 0x4a, 0xa1, 0xfe, 0x1c, 0x37, 0x5f, 0x25, 0xfb, 0x4a,
-	0x28, 0xdb, 0x85, 0x16, 0x65, 0x2a, 0x15, 0x2a, 0x5b, 0x85, 0xf3, 0x10, 0x1e, 0xd4, 0x8b, 0x26,
-	0xd3, 0xb1, 0x54, 0xd2, 0x7f, 0x2f, 0x1a, 0x26, 0xb6, 0x8e, 0x14, 0x40, 0xac, 0x45, 0xb4, 0x88,
-	0x18, 0x3a, 0x88, 0x32, 0x00, 0xed, 0xce, 0x54, 0x6a, 0x73, 0xea, 0x64, 0x4e, 0x19, 0x76, 0xbf,
-	0x09, 0x5b, 0xfa, 0xc8, 0x58, 0x79, 0x6d, 0xe1, 0xc4, 0x82, 0xef, 0x45, 0x53, 0x69, 0x2e, 0x51,
-	0x0b, 0xee, 0x81, 0x2d, 0xb2, 0x9a, 0x6a, 0xd2, 0xbc, 0x03, 0x6b, 0x4a, 0x8c, 0x30, 0x0f, 0x74,
-	0xe4, 0x35, 0x79, 0x2a, 0xbb, 0xef, 0xc1, 0x95, 0x6c, 0xc5, 0xb3, 0x5e, 0xba, 0xa6, 0x07, 0x75,
-	0x52, 0x69, 0x63, 0xb5, 0x53, 0xca, 0x71, 0x4d, 0x1f, 0x20, 0x85, 0x1b, 0xa6, 0x7b, 0x3f, 0x6f,
-	0xa8, 0x99, 0x4c, 0xc3, 0xca, 0xc9, 0x85, 0x15, 0x83, 0xaa, 0xc2, 0xb7, 0x74, 0x95, 0x8c, 0xa1,
-	0xb1, 0xfb, 0x08, 0xb6, 0xd3, 0xc5, 0x74, 0xef, 0x49, 0xbe, 0x07, 0xd1, 0xe6, 0xa6, 0x55, 0x42,
-	0x8b, 0xe8, 0x04, 0x6a, 0x1b, 0xec, 0x73, 0x43, 0x82, 0xfb, 0x0e, 0x5c, 0x5b, 0xd0, 0x64, 0x4e,
-	0x85, 0x57, 0x62, 0x41, 0xe3, 0x8a, 0x0c, 0x70, 0xef, 0xc2, 0x9a, 0x5d, 0x42, 0x26, 0xce, 0x53,
-	0xf7, 0xd2, 0x78, 0xf9, 0xeb, 0xe6, 0x3e, 0x81, 0xeb, 0xa5, 0xed, 0x72, 0x6e, 0xdc, 0x2f, 0x6f,
-	0xd8, 0xea, 0x6d, 0x65, 0x45, 0xd6, 0xcc, 0xe4, 0x6d, 0xe8, 0x43, 0x8d, 0xc2, 0x95, 0xdd, 0x83,
-	0xc6, 0x90, 0xf2, 0xde, 0xae, 0xbb, 0x95, 0xae, 0xd3, 0xcd, 0xdf, 0xd9, 0x9d, 0x2e, 0x97, 0x49,
-	0x34, 0x8b, 0x3d, 0x49, 0xaf, 0x34, 0xb7, 0x7c, 0xf7, 0x12, 0xac, 0x3f, 0x9d, 0x25, 0x69, 0x99,
-	0x77, 0xff, 0xe8, 0xc0, 0x26, 0x02, 0x14, 0x4e, 0xd6, 0xab, 0x6f, 0xa5, 0xb5, 0x1f, 0x6f, 0x61,
-	0xbd, 0x7f, 0x15, 0xfb, 0x95, 0x7f, 0x7c, 0x7e, 0x6b, 0xe3, 0x69, 0x2c, 0xc5, 0x78, 0x1c, 0x79,
-	0x9a, 0x6d, 0x8b, 0xfe, 0x37, 0xa0, 0x12, 0xf8, 0xba, 0xe8, 0x9c, 0xcb, 0x45, 0x06, 0x3b, 0x04,
-	0xd0, 0x0f, 0xf5, 0x91, 0x50, 0xa2, 0x5d, 0xbd, 0x88, 0x9f, 0x23, 0xba, 0xc7, 0xda, 0x44, 0x7d,
-	0x12, 0x63, 0xe2, 0xff, 0xe1, 0x82, 0xdb, 0x00, 0xa6, 0xa7, 0xc3, 0x8c, 0xde, 0x2e, 0xbc, 0x73,
-	0xeb, 0xf6, 0x50, 0xee, 0xf7, 0xa0, 0xf9, 0x24, 0x08, 0x4f, 0x07, 0xe3, 0xc0, 0x93, 0xec, 0x0e,
-	0xd4, 0xc6, 0x41, 0x78, 0x6a, 0xf7, 0xba, 0xb1, 0xb8, 0x17, 0xee, 0xd1, 0xc5, 0x05, 0x5c, 0x33,
-	0xdd, 0x8f, 0x80, 0x21, 0x66, 0xdf, 0xbb, 0x2c, 0x35, 0x75, 0x54, 0x3a, 0xb9, 0xa8, 0xc4, 0x28,
-	0x1e, 0xc5, 0xd1, 0x6c, 0xda, 0xb7, 0xd1, 0x6a, 0x45, 0xe4, 0x8f, 0xa9, 0xa3, 0xd3, 0x85, 0x55,
-	0x0b, 0xae, 0x80, 0xeb, 0x39, 0xdd, 0x83, 0xd9, 0x64, 0x22, 0xe2, 0xf9, 0x57, 0xbb, 0xc5, 0x9f,
-	0x1d, 0x78, 0xa3, 0x60, 0x7f, 0x96, 0x25, 0x32, 0x51, 0xc1, 0x44, 0x28, 0xe9, 0xd3, 0x0e, 0x6b,
-	0x3c, 0x03, 0xa8, 0xc5, 0x9a, 0x8a, 0xf0, 0x87, 0xd1, 0x2c, 0x54, 0xa6, 0x82, 0x66, 0x00, 0x16,
-	0x59, 0x19, 0xc7, 0x11, 0x35, 0x8c, 0x9a, 0xa2, 0xb7, 0x2c, 0xa1, 0xac, 0x9b, 0x35, 0x11, 0x55,
-	0xf2, 0xf7, 0x95, 0xc2, 0x63, 0xb8, 0xd0, 0x42, 0x7c, 0x17, 0xd6, 0xb9, 0xf8, 0xf9, 0xa3, 0x20,
-	0x51, 0xd1, 0x28, 0x16, 0x13, 0xbc, 0xd2, 0xe1, 0xcc, 0x3b, 0x95, 0x8a, 0x0c, 0xac, 0x72, 0x23,
-	0xe1, 0x49, 0xbd, 0x9c, 0x65, 0x5a, 0x70, 0x7f, 0xef, 0x40, 0x2b, 0xa7, 0x96, 0xf5, 0x61, 0x6b,
-	0x2c, 0x94, 0x0c, 0xbd, 0xf9, 0xc7, 0x2f, 0xac, 0x4a, 0x73, 0xef, 0x57, 0x53, 0x3b, 0xf2, 0xfb,
-	0xf1, 0x4d, 0xc3, 0xcf, 0x2c, 0xe8, 0x42, 0x3d, 0x51, 0x42, 0x05, 0xde, 0x42, 0x17, 0x44, 0x91,
-	0xf7, 0xe1, 0x93, 0x01, 0xcd, 0x72, 0xc3, 0x42, 0x8b, 0xc9, 0x07, 0x89, 0xf1, 0x88, 0x91, 0xdc,
-	0xbf, 0x39, 0x85, 0x28, 0x32, 0x37, 0x5d, 0x74, 0xb3, 0xf3, 0xc5, 0x6e, 0x5e, 0x3d, 0xc7, 0xcd,
-	0xd6, 0xc8, 0xca, 0x7f, 0x65, 0xe4, 0x26, 0x54, 0xa6, 0xf7, 0xee, 0x99, 0x87, 0x1b, 0x87, 0x1a,
-	0x39, 0xa4, 0xf7, 0x8b, 0x90, 0x43, 0x8d, 0x1c, 0x98, 0xd7, 0x0a, 0x87, 0x84, 0x1c, 0x1e, 0x50,
-	0x9b, 0x8b, 0xc8, 0xe1, 0x81, 0xfb, 0x53, 0xe8, 0x2c, 0x8b, 0x5e, 0x13, 0x60, 0xf7, 0xa0, 0x99,
-	0x10, 0x14, 0xc8, 0xc5, 0x74, 0x5b, 0xb2, 0x2e, 0x63, 0xbb, 0xbf, 0x73, 0x60, 0xa3, 0x60, 0x7a,
-	0xa1, 0x52, 0xd7, 0x4c, 0xa5, 0x5e, 0x07, 0x27, 0x24, 0x8f, 0x54, 0xb8, 0x13, 0xa2, 0xf4, 0x9c,
-	0xce, 0xef, 0x70, 0xe7, 0x39, 0x4a, 0xfa, 0xc1, 0x6e, 0x72, 0x27, 0x41, 0x69, 0x48, 0x87, 0x5b,
-	0xe3, 0xce, 0x10, 0x25, 0xdf, 0x1c, 0xcc, 0xf1, 0xa9, 0x53, 0x52, 0x42, 0xcd, 0x74, 0x03, 0x5f,
-	0xe3, 0x46, 0xc2, 0x1d, 0x4f, 0x83, 0xd0, 0xa7, 0x96, 0xbd, 0xc6, 0x69, 0xdc, 0xfb, 0x8d, 0x03,
-	0x75, 0x2c, 0x60, 0x32, 0x66, 0xdf, 0x87, 0x66, 0x5a, 0x6d, 0x59, 0xf6, 0x01, 0x5a, 0xae, 0xc0,
-	0x9d, 0xab, 0x85, 0xa9, 0xb4, 0x5a, 0xaf, 0xb0, 0x1f, 0x40, 0x2b, 0x25, 0x3f, 0xeb, 0xfd, 0x2f,
-	0x2a, 0x7a, 0x7f, 0x70, 0x60, 0xd3, 0x38, 0xf1, 0xa1, 0x0c, 0x65, 0x2c, 0x54, 0x94, 0x1a, 0x46,
-	0xa5, 0xb2, 0xa4, 0x35, 0x5f, 0x77, 0xcf, 0x37, 0xec, 0x31, 0xc0, 0x43, 0xa9, 0x6c, 0x12, 0x2d,
-	0xbd, 0x32, 0xab, 0xe3, 0xe6, 0xf2, 0xc9, 0xd4, 0xc0, 0x3f, 0x55, 0xa1, 0x81, 0x1f, 0x9f, 0x81,
-	0x8c, 0xd9, 0x23, 0xd8, 0xf8, 0x51, 0x10, 0xfa, 0xe9, 0x47, 0x38, 0x5b, 0xf2, 0xd5, 0x6e, 0xf5,
-	0x76, 0x96, 0x4d, 0xe5, 0x3c, 0xb7, 0x6e, 0x3f, 0x71, 0x3c, 0x19, 0x2a, 0x76, 0xce, 0xb7, 0x64,
-	0xe7, 0xda, 0x02, 0x9e, 0xaa, 0x78, 0x00, 0xad, 0xdc, 0x77, 0x6a, 0xfe, 0x90, 0x0b, 0x5f, 0xaf,
-	0x17, 0xa9, 0x79, 0x08, 0x90, 0xf5, 0x42, 0x6c, 0x59, 0xf7, 0x64, 0x95, 0xdc, 0x58, 0x3a, 0x97,
-	0x2a, 0x7a, 0xdf, 0x1e, 0x49, 0x37, 0x55, 0x17, 0xaa, 0xfa, 0xda, 0xd2, 0x26, 0x2d, 0xa7, 0xec,
-	0x19, 0x5c, 0x2e, 0xf5, 0x2a, 0xec, 0xd6, 0xe2, 0x9a, 0x42, 0xfb, 0xd5, 0xd9, 0x3d, 0x9f, 0x90,
-	0xea, 0xfd, 0x28, 0xd7, 0xf9, 0xd9, 0x1e, 0xe8, 0x8b, 0x35, 0xbb, 0xe7, 0x11, 0xf2, 0x36, 0xf7,
-	0x7e, 0x0c, 0x9b, 0x03, 0x15, 0x4b, 0x31, 0x09, 0xc2, 0x91, 0x8d, 0x98, 0xfb, 0x50, 0x37, 0x1f,
-	0xeb, 0x5f, 0xf6, 0x86, 0x0f, 0x9c, 0xde, 0xcf, 0xa0, 0x61, 0x43, 0xf8, 0xe3, 0xa5, 0xa5, 0xd7,
-	0xbd, 0xa8, 0x16, 0x19, 0xfd, 0x6f, 0x5e, 0xc8, 0xb1, 0xc6, 0xf7, 0xdb, 0x9f, 0xbe, 0xda, 0x71,
-	0x3e, 0x7b, 0xb5, 0xe3, 0xfc, 0xf3, 0xd5, 0x8e, 0xf3, 0xdb, 0xd7, 0x3b, 0x2b, 0x9f, 0xbd, 0xde,
-	0x59, 0xf9, 0xfb, 0xeb, 0x9d, 0x95, 0x61, 0x9d, 0xfe, 0x75, 0x7b, 0xfb, 0x3f, 0x01, 0x00, 0x00,
-	0xff, 0xff, 0x63, 0xf7, 0xc0, 0x44, 0xf6, 0x13, 0x00, 0x00,
+func (m *SpanMetricsSummaryRequest) Reset()         { *m = SpanMetricsSummaryRequest{} }
+func (m *SpanMetricsSummaryRequest) String() string { return proto.CompactTextString(m) }
+func (*SpanMetricsSummaryRequest) ProtoMessage()    {}
+func (*SpanMetricsSummaryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{36}
 }
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// PusherClient is the client API for Pusher service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type PusherClient interface {
-	// different versions of PushBytes expect the trace data to be pushed in different formats
-	PushBytes(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error)
-	PushBytesV2(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error)
+func (m *SpanMetricsSummaryRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-type pusherClient struct {
-	cc *grpc.ClientConn
+func (m *SpanMetricsSummaryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpanMetricsSummaryRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-
-func NewPusherClient(cc *grpc.ClientConn) PusherClient {
-	return &pusherClient{cc}
+func (m *SpanMetricsSummaryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanMetricsSummaryRequest.Merge(m, src)
+}
+func (m *SpanMetricsSummaryRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpanMetricsSummaryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanMetricsSummaryRequest.DiscardUnknown(m)
 }
 
-func (c *pusherClient) PushBytes(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error) {
-	out := new(PushResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Pusher/PushBytes", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SpanMetricsSummaryRequest proto.InternalMessageInfo
+
+func (m *SpanMetricsSummaryRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
 	}
-	return out, nil
+	return ""
 }
 
-func (c *pusherClient) PushBytesV2(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error) {
-	out := new(PushResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Pusher/PushBytesV2", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanMetricsSummaryRequest) GetGroupBy() string {
+	if m != nil {
+		return m.GroupBy
 	}
-	return out, nil
+	return ""
 }
 
-// PusherServer is the server API for Pusher service.
-type PusherServer interface {
-	// different versions of PushBytes expect the trace data to be pushed in different formats
-	PushBytes(context.Context, *PushBytesRequest) (*PushResponse, error)
-	PushBytesV2(context.Context, *PushBytesRequest) (*PushResponse, error)
+func (m *SpanMetricsSummaryRequest) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
 }
 
-// UnimplementedPusherServer can be embedded to have forward compatible implementations.
-type UnimplementedPusherServer struct {
+type SpanMetricsResponse struct {
+	Estimated      bool           `protobuf:"varint,1,opt,name=estimated,proto3" json:"estimated,omitempty"`
+	SpanCount      uint64         `protobuf:"varint,2,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
+	ErrorSpanCount uint64         `protobuf:"varint,3,opt,name=errorSpanCount,proto3" json:"errorSpanCount,omitempty"`
+	Metrics        []*SpanMetrics `protobuf:"bytes,4,rep,name=metrics,proto3" json:"metrics,omitempty"`
 }
 
-func (*UnimplementedPusherServer) PushBytes(ctx context.Context, req *PushBytesRequest) (*PushResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PushBytes not implemented")
-}
-func (*UnimplementedPusherServer) PushBytesV2(ctx context.Context, req *PushBytesRequest) (*PushResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PushBytesV2 not implemented")
+func (m *SpanMetricsResponse) Reset()         { *m = SpanMetricsResponse{} }
+func (m *SpanMetricsResponse) String() string { return proto.CompactTextString(m) }
+func (*SpanMetricsResponse) ProtoMessage()    {}
+func (*SpanMetricsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{37}
 }
-
-func RegisterPusherServer(s *grpc.Server, srv PusherServer) {
-	s.RegisterService(&_Pusher_serviceDesc, srv)
+func (m *SpanMetricsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func _Pusher_PushBytes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PushBytesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(PusherServer).PushBytes(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Pusher/PushBytes",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PusherServer).PushBytes(ctx, req.(*PushBytesRequest))
+func (m *SpanMetricsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpanMetricsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
 }
-
-func _Pusher_PushBytesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PushBytesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(PusherServer).PushBytesV2(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Pusher/PushBytesV2",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PusherServer).PushBytesV2(ctx, req.(*PushBytesRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+func (m *SpanMetricsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanMetricsResponse.Merge(m, src)
 }
-
-var _Pusher_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.Pusher",
-	HandlerType: (*PusherServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "PushBytes",
-			Handler:    _Pusher_PushBytes_Handler,
-		},
-		{
-			MethodName: "PushBytesV2",
-			Handler:    _Pusher_PushBytesV2_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "pkg/tempopb/tempo.proto",
+func (m *SpanMetricsResponse) XXX_Size() int {
+	return m.Size()
 }
-
-// MetricsGeneratorClient is the client API for MetricsGenerator service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MetricsGeneratorClient interface {
-	PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error)
-	GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error)
+func (m *SpanMetricsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanMetricsResponse.DiscardUnknown(m)
 }
 
-type metricsGeneratorClient struct {
-	cc *grpc.ClientConn
-}
+var xxx_messageInfo_SpanMetricsResponse proto.InternalMessageInfo
 
-func NewMetricsGeneratorClient(cc *grpc.ClientConn) MetricsGeneratorClient {
-	return &metricsGeneratorClient{cc}
+func (m *SpanMetricsResponse) GetEstimated() bool {
+	if m != nil {
+		return m.Estimated
+	}
+	return false
 }
 
-func (c *metricsGeneratorClient) PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error) {
-	out := new(PushResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/PushSpans", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanMetricsResponse) GetSpanCount() uint64 {
+	if m != nil {
+		return m.SpanCount
 	}
-	return out, nil
+	return 0
 }
 
-func (c *metricsGeneratorClient) GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error) {
-	out := new(SpanMetricsResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/GetMetrics", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanMetricsResponse) GetErrorSpanCount() uint64 {
+	if m != nil {
+		return m.ErrorSpanCount
 	}
-	return out, nil
+	return 0
 }
 
-// MetricsGeneratorServer is the server API for MetricsGenerator service.
-type MetricsGeneratorServer interface {
-	PushSpans(context.Context, *PushSpansRequest) (*PushResponse, error)
-	GetMetrics(context.Context, *SpanMetricsRequest) (*SpanMetricsResponse, error)
+func (m *SpanMetricsResponse) GetMetrics() []*SpanMetrics {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
 }
 
-// UnimplementedMetricsGeneratorServer can be embedded to have forward compatible implementations.
-type UnimplementedMetricsGeneratorServer struct {
+type RawHistogram struct {
+	Bucket uint64 `protobuf:"varint,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Count  uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
 }
 
-func (*UnimplementedMetricsGeneratorServer) PushSpans(ctx context.Context, req *PushSpansRequest) (*PushResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PushSpans not implemented")
-}
-func (*UnimplementedMetricsGeneratorServer) GetMetrics(ctx context.Context, req *SpanMetricsRequest) (*SpanMetricsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetMetrics not implemented")
+func (m *RawHistogram) Reset()         { *m = RawHistogram{} }
+func (m *RawHistogram) String() string { return proto.CompactTextString(m) }
+func (*RawHistogram) ProtoMessage()    {}
+func (*RawHistogram) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{38}
 }
-
-func RegisterMetricsGeneratorServer(s *grpc.Server, srv MetricsGeneratorServer) {
-	s.RegisterService(&_MetricsGenerator_serviceDesc, srv)
+func (m *RawHistogram) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func _MetricsGenerator_PushSpans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PushSpansRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MetricsGeneratorServer).PushSpans(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.MetricsGenerator/PushSpans",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MetricsGeneratorServer).PushSpans(ctx, req.(*PushSpansRequest))
+func (m *RawHistogram) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RawHistogram.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
+}
+func (m *RawHistogram) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RawHistogram.Merge(m, src)
+}
+func (m *RawHistogram) XXX_Size() int {
+	return m.Size()
+}
+func (m *RawHistogram) XXX_DiscardUnknown() {
+	xxx_messageInfo_RawHistogram.DiscardUnknown(m)
 }
 
-func _MetricsGenerator_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SpanMetricsRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MetricsGeneratorServer).GetMetrics(ctx, in)
+var xxx_messageInfo_RawHistogram proto.InternalMessageInfo
+
+func (m *RawHistogram) GetBucket() uint64 {
+	if m != nil {
+		return m.Bucket
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.MetricsGenerator/GetMetrics",
+	return 0
+}
+
+func (m *RawHistogram) GetCount() uint64 {
+	if m != nil {
+		return m.Count
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MetricsGeneratorServer).GetMetrics(ctx, req.(*SpanMetricsRequest))
+	return 0
+}
+
+type SpanMetrics struct {
+	LatencyHistogram []*RawHistogram `protobuf:"bytes,1,rep,name=latency_histogram,json=latencyHistogram,proto3" json:"latency_histogram,omitempty"`
+	Static           *TraceQLStatic  `protobuf:"bytes,2,opt,name=static,proto3" json:"static,omitempty"`
+	Errors           uint64          `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (m *SpanMetrics) Reset()         { *m = SpanMetrics{} }
+func (m *SpanMetrics) String() string { return proto.CompactTextString(m) }
+func (*SpanMetrics) ProtoMessage()    {}
+func (*SpanMetrics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{39}
+}
+func (m *SpanMetrics) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SpanMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpanMetrics.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
+}
+func (m *SpanMetrics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanMetrics.Merge(m, src)
+}
+func (m *SpanMetrics) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpanMetrics) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanMetrics.DiscardUnknown(m)
 }
 
-var _MetricsGenerator_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.MetricsGenerator",
-	HandlerType: (*MetricsGeneratorServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "PushSpans",
-			Handler:    _MetricsGenerator_PushSpans_Handler,
-		},
-		{
-			MethodName: "GetMetrics",
-			Handler:    _MetricsGenerator_GetMetrics_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "pkg/tempopb/tempo.proto",
+var xxx_messageInfo_SpanMetrics proto.InternalMessageInfo
+
+func (m *SpanMetrics) GetLatencyHistogram() []*RawHistogram {
+	if m != nil {
+		return m.LatencyHistogram
+	}
+	return nil
 }
 
-// QuerierClient is the client API for Querier service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type QuerierClient interface {
-	FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error)
-	SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
-	SearchBlock(ctx context.Context, in *SearchBlockRequest, opts ...grpc.CallOption) (*SearchResponse, error)
-	SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error)
-	SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error)
-	SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error)
-	SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error)
+func (m *SpanMetrics) GetStatic() *TraceQLStatic {
+	if m != nil {
+		return m.Static
+	}
+	return nil
 }
 
-type querierClient struct {
-	cc *grpc.ClientConn
+func (m *SpanMetrics) GetErrors() uint64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
 }
 
-func NewQuerierClient(cc *grpc.ClientConn) QuerierClient {
-	return &querierClient{cc}
+type SpanMetricsSummary struct {
+	SpanCount      uint64         `protobuf:"varint,1,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
+	ErrorSpanCount uint64         `protobuf:"varint,2,opt,name=errorSpanCount,proto3" json:"errorSpanCount,omitempty"`
+	Static         *TraceQLStatic `protobuf:"bytes,3,opt,name=static,proto3" json:"static,omitempty"`
+	P99            uint64         `protobuf:"varint,4,opt,name=p99,proto3" json:"p99,omitempty"`
+	P95            uint64         `protobuf:"varint,5,opt,name=p95,proto3" json:"p95,omitempty"`
+	P90            uint64         `protobuf:"varint,6,opt,name=p90,proto3" json:"p90,omitempty"`
+	P50            uint64         `protobuf:"varint,7,opt,name=p50,proto3" json:"p50,omitempty"`
 }
 
-func (c *querierClient) FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error) {
-	out := new(TraceByIDResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/FindTraceByID", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanMetricsSummary) Reset()         { *m = SpanMetricsSummary{} }
+func (m *SpanMetricsSummary) String() string { return proto.CompactTextString(m) }
+func (*SpanMetricsSummary) ProtoMessage()    {}
+func (*SpanMetricsSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{40}
+}
+func (m *SpanMetricsSummary) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SpanMetricsSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpanMetricsSummary.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return out, nil
+}
+func (m *SpanMetricsSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanMetricsSummary.Merge(m, src)
+}
+func (m *SpanMetricsSummary) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpanMetricsSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanMetricsSummary.DiscardUnknown(m)
 }
 
-func (c *querierClient) SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
-	out := new(SearchResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchRecent", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SpanMetricsSummary proto.InternalMessageInfo
+
+func (m *SpanMetricsSummary) GetSpanCount() uint64 {
+	if m != nil {
+		return m.SpanCount
 	}
-	return out, nil
+	return 0
 }
 
-func (c *querierClient) SearchBlock(ctx context.Context, in *SearchBlockRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
-	out := new(SearchResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchBlock", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanMetricsSummary) GetErrorSpanCount() uint64 {
+	if m != nil {
+		return m.ErrorSpanCount
 	}
-	return out, nil
+	return 0
 }
 
-func (c *querierClient) SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error) {
-	out := new(SearchTagsResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTags", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanMetricsSummary) GetStatic() *TraceQLStatic {
+	if m != nil {
+		return m.Static
 	}
-	return out, nil
+	return nil
 }
 
-func (c *querierClient) SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error) {
-	out := new(SearchTagsV2Response)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagsV2", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanMetricsSummary) GetP99() uint64 {
+	if m != nil {
+		return m.P99
 	}
-	return out, nil
+	return 0
 }
 
-func (c *querierClient) SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error) {
-	out := new(SearchTagValuesResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagValues", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanMetricsSummary) GetP95() uint64 {
+	if m != nil {
+		return m.P95
 	}
-	return out, nil
+	return 0
 }
 
-func (c *querierClient) SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error) {
-	out := new(SearchTagValuesV2Response)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagValuesV2", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanMetricsSummary) GetP90() uint64 {
+	if m != nil {
+		return m.P90
 	}
-	return out, nil
+	return 0
 }
 
-// QuerierServer is the server API for Querier service.
-type QuerierServer interface {
-	FindTraceByID(context.Context, *TraceByIDRequest) (*TraceByIDResponse, error)
-	SearchRecent(context.Context, *SearchRequest) (*SearchResponse, error)
-	SearchBlock(context.Context, *SearchBlockRequest) (*SearchResponse, error)
-	SearchTags(context.Context, *SearchTagsRequest) (*SearchTagsResponse, error)
-	SearchTagsV2(context.Context, *SearchTagsRequest) (*SearchTagsV2Response, error)
-	SearchTagValues(context.Context, *SearchTagValuesRequest) (*SearchTagValuesResponse, error)
-	SearchTagValuesV2(context.Context, *SearchTagValuesRequest) (*SearchTagValuesV2Response, error)
+func (m *SpanMetricsSummary) GetP50() uint64 {
+	if m != nil {
+		return m.P50
+	}
+	return 0
 }
 
-// UnimplementedQuerierServer can be embedded to have forward compatible implementations.
-type UnimplementedQuerierServer struct {
+type SpanMetricsSummaryResponse struct {
+	Summaries []*SpanMetricsSummary `protobuf:"bytes,1,rep,name=summaries,proto3" json:"summaries,omitempty"`
 }
 
-func (*UnimplementedQuerierServer) FindTraceByID(ctx context.Context, req *TraceByIDRequest) (*TraceByIDResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method FindTraceByID not implemented")
+func (m *SpanMetricsSummaryResponse) Reset()         { *m = SpanMetricsSummaryResponse{} }
+func (m *SpanMetricsSummaryResponse) String() string { return proto.CompactTextString(m) }
+func (*SpanMetricsSummaryResponse) ProtoMessage()    {}
+func (*SpanMetricsSummaryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{41}
 }
-func (*UnimplementedQuerierServer) SearchRecent(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchRecent not implemented")
+func (m *SpanMetricsSummaryResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (*UnimplementedQuerierServer) SearchBlock(ctx context.Context, req *SearchBlockRequest) (*SearchResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchBlock not implemented")
+func (m *SpanMetricsSummaryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpanMetricsSummaryResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-func (*UnimplementedQuerierServer) SearchTags(ctx context.Context, req *SearchTagsRequest) (*SearchTagsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchTags not implemented")
+func (m *SpanMetricsSummaryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanMetricsSummaryResponse.Merge(m, src)
 }
-func (*UnimplementedQuerierServer) SearchTagsV2(ctx context.Context, req *SearchTagsRequest) (*SearchTagsV2Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchTagsV2 not implemented")
+func (m *SpanMetricsSummaryResponse) XXX_Size() int {
+	return m.Size()
 }
-func (*UnimplementedQuerierServer) SearchTagValues(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValues not implemented")
+func (m *SpanMetricsSummaryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanMetricsSummaryResponse.DiscardUnknown(m)
 }
-func (*UnimplementedQuerierServer) SearchTagValuesV2(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesV2Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValuesV2 not implemented")
+
+var xxx_messageInfo_SpanMetricsSummaryResponse proto.InternalMessageInfo
+
+func (m *SpanMetricsSummaryResponse) GetSummaries() []*SpanMetricsSummary {
+	if m != nil {
+		return m.Summaries
+	}
+	return nil
 }
 
-func RegisterQuerierServer(s *grpc.Server, srv QuerierServer) {
-	s.RegisterService(&_Querier_serviceDesc, srv)
+type TraceQLStatic struct {
+	Type   int32   `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	N      int64   `protobuf:"varint,2,opt,name=n,proto3" json:"n,omitempty"`
+	F      float64 `protobuf:"fixed64,3,opt,name=f,proto3" json:"f,omitempty"`
+	S      string  `protobuf:"bytes,4,opt,name=s,proto3" json:"s,omitempty"`
+	B      bool    `protobuf:"varint,5,opt,name=b,proto3" json:"b,omitempty"`
+	D      uint64  `protobuf:"varint,6,opt,name=d,proto3" json:"d,omitempty"`
+	Status int32   `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
+	Kind   int32   `protobuf:"varint,8,opt,name=kind,proto3" json:"kind,omitempty"`
 }
 
-func _Querier_FindTraceByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TraceByIDRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).FindTraceByID(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/FindTraceByID",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).FindTraceByID(ctx, req.(*TraceByIDRequest))
+func (m *TraceQLStatic) Reset()         { *m = TraceQLStatic{} }
+func (m *TraceQLStatic) String() string { return proto.CompactTextString(m) }
+func (*TraceQLStatic) ProtoMessage()    {}
+func (*TraceQLStatic) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{42}
+}
+func (m *TraceQLStatic) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceQLStatic) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceQLStatic.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
+}
+func (m *TraceQLStatic) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceQLStatic.Merge(m, src)
+}
+func (m *TraceQLStatic) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceQLStatic) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceQLStatic.DiscardUnknown(m)
 }
 
-func _Querier_SearchRecent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchRecent(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchRecent",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchRecent(ctx, req.(*SearchRequest))
+var xxx_messageInfo_TraceQLStatic proto.InternalMessageInfo
+
+func (m *TraceQLStatic) GetType() int32 {
+	if m != nil {
+		return m.Type
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _Querier_SearchBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchBlockRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchBlock(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchBlock",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchBlock(ctx, req.(*SearchBlockRequest))
+func (m *TraceQLStatic) GetN() int64 {
+	if m != nil {
+		return m.N
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _Querier_SearchTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchTagsRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchTags(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchTags",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchTags(ctx, req.(*SearchTagsRequest))
+func (m *TraceQLStatic) GetF() float64 {
+	if m != nil {
+		return m.F
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _Querier_SearchTagsV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchTagsRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchTagsV2(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchTagsV2",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchTagsV2(ctx, req.(*SearchTagsRequest))
+func (m *TraceQLStatic) GetS() string {
+	if m != nil {
+		return m.S
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Querier_SearchTagValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchTagValuesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *TraceQLStatic) GetB() bool {
+	if m != nil {
+		return m.B
 	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchTagValues(ctx, in)
+	return false
+}
+
+func (m *TraceQLStatic) GetD() uint64 {
+	if m != nil {
+		return m.D
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchTagValues",
+	return 0
+}
+
+func (m *TraceQLStatic) GetStatus() int32 {
+	if m != nil {
+		return m.Status
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchTagValues(ctx, req.(*SearchTagValuesRequest))
+	return 0
+}
+
+func (m *TraceQLStatic) GetKind() int32 {
+	if m != nil {
+		return m.Kind
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _Querier_SearchTagValuesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchTagValuesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+// QueryRangeRequest is a TraceQL metrics query evaluated over [start, end) in intervals of step.
+// All values are in nanoseconds.
+type QueryRangeRequest struct {
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Start uint64 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End   uint64 `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	Step  uint64 `protobuf:"varint,4,opt,name=step,proto3" json:"step,omitempty"`
+}
+
+func (m *QueryRangeRequest) Reset()         { *m = QueryRangeRequest{} }
+func (m *QueryRangeRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryRangeRequest) ProtoMessage()    {}
+func (*QueryRangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{43}
+}
+func (m *QueryRangeRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryRangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRangeRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchTagValuesV2(ctx, in)
+}
+func (m *QueryRangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRangeRequest.Merge(m, src)
+}
+func (m *QueryRangeRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryRangeRequest proto.InternalMessageInfo
+
+func (m *QueryRangeRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchTagValuesV2",
+	return ""
+}
+
+func (m *QueryRangeRequest) GetStart() uint64 {
+	if m != nil {
+		return m.Start
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchTagValuesV2(ctx, req.(*SearchTagValuesRequest))
+	return 0
+}
+
+func (m *QueryRangeRequest) GetEnd() uint64 {
+	if m != nil {
+		return m.End
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-var _Querier_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.Querier",
-	HandlerType: (*QuerierServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "FindTraceByID",
-			Handler:    _Querier_FindTraceByID_Handler,
-		},
-		{
-			MethodName: "SearchRecent",
-			Handler:    _Querier_SearchRecent_Handler,
-		},
-		{
-			MethodName: "SearchBlock",
-			Handler:    _Querier_SearchBlock_Handler,
-		},
-		{
-			MethodName: "SearchTags",
-			Handler:    _Querier_SearchTags_Handler,
-		},
-		{
-			MethodName: "SearchTagsV2",
-			Handler:    _Querier_SearchTagsV2_Handler,
-		},
-		{
-			MethodName: "SearchTagValues",
-			Handler:    _Querier_SearchTagValues_Handler,
-		},
-		{
-			MethodName: "SearchTagValuesV2",
-			Handler:    _Querier_SearchTagValuesV2_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "pkg/tempopb/tempo.proto",
+func (m *QueryRangeRequest) GetStep() uint64 {
+	if m != nil {
+		return m.Step
+	}
+	return 0
 }
 
-// StreamingQuerierClient is the client API for StreamingQuerier service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type StreamingQuerierClient interface {
-	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchClient, error)
+type QueryRangeResponse struct {
+	// raw counts per interval, combined in the query frontend
+	Series []*QueryRangeSeries `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
 }
 
-type streamingQuerierClient struct {
-	cc *grpc.ClientConn
+func (m *QueryRangeResponse) Reset()         { *m = QueryRangeResponse{} }
+func (m *QueryRangeResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryRangeResponse) ProtoMessage()    {}
+func (*QueryRangeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{44}
 }
-
-func NewStreamingQuerierClient(cc *grpc.ClientConn) StreamingQuerierClient {
-	return &streamingQuerierClient{cc}
+func (m *QueryRangeResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (c *streamingQuerierClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_StreamingQuerier_serviceDesc.Streams[0], "/tempopb.StreamingQuerier/Search", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &streamingQuerierSearchClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *QueryRangeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRangeResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return x, nil
 }
-
-type StreamingQuerier_SearchClient interface {
-	Recv() (*SearchResponse, error)
-	grpc.ClientStream
+func (m *QueryRangeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRangeResponse.Merge(m, src)
 }
-
-type streamingQuerierSearchClient struct {
-	grpc.ClientStream
+func (m *QueryRangeResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRangeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRangeResponse.DiscardUnknown(m)
 }
 
-func (x *streamingQuerierSearchClient) Recv() (*SearchResponse, error) {
-	m := new(SearchResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+var xxx_messageInfo_QueryRangeResponse proto.InternalMessageInfo
+
+func (m *QueryRangeResponse) GetSeries() []*QueryRangeSeries {
+	if m != nil {
+		return m.Series
 	}
-	return m, nil
+	return nil
 }
 
-// StreamingQuerierServer is the server API for StreamingQuerier service.
-type StreamingQuerierServer interface {
-	Search(*SearchRequest, StreamingQuerier_SearchServer) error
+type QueryRangeSeries struct {
+	Labels  []*QueryRangeLabel  `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
+	Samples []*QueryRangeSample `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples,omitempty"`
 }
 
-// UnimplementedStreamingQuerierServer can be embedded to have forward compatible implementations.
-type UnimplementedStreamingQuerierServer struct {
+func (m *QueryRangeSeries) Reset()         { *m = QueryRangeSeries{} }
+func (m *QueryRangeSeries) String() string { return proto.CompactTextString(m) }
+func (*QueryRangeSeries) ProtoMessage()    {}
+func (*QueryRangeSeries) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{45}
 }
-
-func (*UnimplementedStreamingQuerierServer) Search(req *SearchRequest, srv StreamingQuerier_SearchServer) error {
-	return status.Errorf(codes.Unimplemented, "method Search not implemented")
+func (m *QueryRangeSeries) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func RegisterStreamingQuerierServer(s *grpc.Server, srv StreamingQuerierServer) {
-	s.RegisterService(&_StreamingQuerier_serviceDesc, srv)
+func (m *QueryRangeSeries) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRangeSeries.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryRangeSeries) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRangeSeries.Merge(m, src)
+}
+func (m *QueryRangeSeries) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRangeSeries) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRangeSeries.DiscardUnknown(m)
 }
 
-func _StreamingQuerier_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SearchRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+var xxx_messageInfo_QueryRangeSeries proto.InternalMessageInfo
+
+func (m *QueryRangeSeries) GetLabels() []*QueryRangeLabel {
+	if m != nil {
+		return m.Labels
 	}
-	return srv.(StreamingQuerierServer).Search(m, &streamingQuerierSearchServer{stream})
+	return nil
 }
 
-type StreamingQuerier_SearchServer interface {
-	Send(*SearchResponse) error
-	grpc.ServerStream
+func (m *QueryRangeSeries) GetSamples() []*QueryRangeSample {
+	if m != nil {
+		return m.Samples
+	}
+	return nil
 }
 
-type streamingQuerierSearchServer struct {
-	grpc.ServerStream
+type QueryRangeLabel struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (x *streamingQuerierSearchServer) Send(m *SearchResponse) error {
-	return x.ServerStream.SendMsg(m)
+func (m *QueryRangeLabel) Reset()         { *m = QueryRangeLabel{} }
+func (m *QueryRangeLabel) String() string { return proto.CompactTextString(m) }
+func (*QueryRangeLabel) ProtoMessage()    {}
+func (*QueryRangeLabel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{46}
 }
-
-var _StreamingQuerier_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.StreamingQuerier",
-	HandlerType: (*StreamingQuerierServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "Search",
-			Handler:       _StreamingQuerier_Search_Handler,
-			ServerStreams: true,
-		},
-	},
-	Metadata: "pkg/tempopb/tempo.proto",
+func (m *QueryRangeLabel) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-// MetricsClient is the client API for Metrics service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MetricsClient interface {
-	SpanMetricsSummary(ctx context.Context, in *SpanMetricsSummaryRequest, opts ...grpc.CallOption) (*SpanMetricsSummaryResponse, error)
+func (m *QueryRangeLabel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRangeLabel.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-
-type metricsClient struct {
-	cc *grpc.ClientConn
+func (m *QueryRangeLabel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRangeLabel.Merge(m, src)
 }
-
-func NewMetricsClient(cc *grpc.ClientConn) MetricsClient {
-	return &metricsClient{cc}
+func (m *QueryRangeLabel) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRangeLabel) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRangeLabel.DiscardUnknown(m)
 }
 
-func (c *metricsClient) SpanMetricsSummary(ctx context.Context, in *SpanMetricsSummaryRequest, opts ...grpc.CallOption) (*SpanMetricsSummaryResponse, error) {
-	out := new(SpanMetricsSummaryResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Metrics/SpanMetricsSummary", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_QueryRangeLabel proto.InternalMessageInfo
+
+func (m *QueryRangeLabel) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return out, nil
+	return ""
 }
 
-// MetricsServer is the server API for Metrics service.
-type MetricsServer interface {
-	SpanMetricsSummary(context.Context, *SpanMetricsSummaryRequest) (*SpanMetricsSummaryResponse, error)
+func (m *QueryRangeLabel) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
 }
 
-// UnimplementedMetricsServer can be embedded to have forward compatible implementations.
-type UnimplementedMetricsServer struct {
+type QueryRangeSample struct {
+	TimestampMs int64   `protobuf:"varint,1,opt,name=timestampMs,proto3" json:"timestampMs,omitempty"`
+	Value       float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (*UnimplementedMetricsServer) SpanMetricsSummary(ctx context.Context, req *SpanMetricsSummaryRequest) (*SpanMetricsSummaryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SpanMetricsSummary not implemented")
+func (m *QueryRangeSample) Reset()         { *m = QueryRangeSample{} }
+func (m *QueryRangeSample) String() string { return proto.CompactTextString(m) }
+func (*QueryRangeSample) ProtoMessage()    {}
+func (*QueryRangeSample) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{47}
 }
-
-func RegisterMetricsServer(s *grpc.Server, srv MetricsServer) {
-	s.RegisterService(&_Metrics_serviceDesc, srv)
+func (m *QueryRangeSample) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func _Metrics_SpanMetricsSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SpanMetricsSummaryRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MetricsServer).SpanMetricsSummary(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Metrics/SpanMetricsSummary",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MetricsServer).SpanMetricsSummary(ctx, req.(*SpanMetricsSummaryRequest))
+func (m *QueryRangeSample) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRangeSample.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
 }
-
-var _Metrics_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.Metrics",
-	HandlerType: (*MetricsServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "SpanMetricsSummary",
-			Handler:    _Metrics_SpanMetricsSummary_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "pkg/tempopb/tempo.proto",
+func (m *QueryRangeSample) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRangeSample.Merge(m, src)
+}
+func (m *QueryRangeSample) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRangeSample) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRangeSample.DiscardUnknown(m)
 }
 
-func (m *TraceByIDRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_QueryRangeSample proto.InternalMessageInfo
+
+func (m *QueryRangeSample) GetTimestampMs() int64 {
+	if m != nil {
+		return m.TimestampMs
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *TraceByIDRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *QueryRangeSample) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
 }
 
-func (m *TraceByIDRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.QueryMode) > 0 {
-		i -= len(m.QueryMode)
-		copy(dAtA[i:], m.QueryMode)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.QueryMode)))
-		i--
-		dAtA[i] = 0x2a
-	}
-	if len(m.BlockEnd) > 0 {
-		i -= len(m.BlockEnd)
-		copy(dAtA[i:], m.BlockEnd)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockEnd)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.BlockStart) > 0 {
-		i -= len(m.BlockStart)
-		copy(dAtA[i:], m.BlockStart)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockStart)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.TraceID) > 0 {
-		i -= len(m.TraceID)
-		copy(dAtA[i:], m.TraceID)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+func init() {
+	proto.RegisterEnum("tempopb.PartialStatus", PartialStatus_name, PartialStatus_value)
+	proto.RegisterType((*TraceByIDRequest)(nil), "tempopb.TraceByIDRequest")
+	proto.RegisterType((*TraceByIDStreamRequest)(nil), "tempopb.TraceByIDStreamRequest")
+	proto.RegisterType((*TraceByIDResponse)(nil), "tempopb.TraceByIDResponse")
+	proto.RegisterType((*TraceByIDMetrics)(nil), "tempopb.TraceByIDMetrics")
+	proto.RegisterType((*TracesByIDRequest)(nil), "tempopb.TracesByIDRequest")
+	proto.RegisterType((*TracesByIDResponse)(nil), "tempopb.TracesByIDResponse")
+	proto.RegisterMapType((map[string]*Trace)(nil), "tempopb.TracesByIDResponse.TracesEntry")
+	proto.RegisterType((*TraceSummary)(nil), "tempopb.TraceSummary")
+	proto.RegisterType((*TraceSummarySpan)(nil), "tempopb.TraceSummarySpan")
+	proto.RegisterType((*TraceSummaryService)(nil), "tempopb.TraceSummaryService")
+	proto.RegisterType((*TraceSummaryOperation)(nil), "tempopb.TraceSummaryOperation")
+	proto.RegisterType((*TraceSummaryGap)(nil), "tempopb.TraceSummaryGap")
+	proto.RegisterType((*SearchRequest)(nil), "tempopb.SearchRequest")
+	proto.RegisterMapType((map[string]string)(nil), "tempopb.SearchRequest.TagsEntry")
+	proto.RegisterType((*SearchBlockRequest)(nil), "tempopb.SearchBlockRequest")
+	proto.RegisterType((*SearchResponse)(nil), "tempopb.SearchResponse")
+	proto.RegisterType((*SearchPageToken)(nil), "tempopb.SearchPageToken")
+	proto.RegisterType((*TraceSearchMetadata)(nil), "tempopb.TraceSearchMetadata")
+	proto.RegisterType((*SpanSet)(nil), "tempopb.SpanSet")
+	proto.RegisterType((*Span)(nil), "tempopb.Span")
+	proto.RegisterType((*SearchMetrics)(nil), "tempopb.SearchMetrics")
+	proto.RegisterType((*SearchTagsRequest)(nil), "tempopb.SearchTagsRequest")
+	proto.RegisterType((*SearchTagsResponse)(nil), "tempopb.SearchTagsResponse")
+	proto.RegisterType((*SearchTagsV2Response)(nil), "tempopb.SearchTagsV2Response")
+	proto.RegisterType((*SearchTagsV2Scope)(nil), "tempopb.SearchTagsV2Scope")
+	proto.RegisterType((*SearchTagValuesRequest)(nil), "tempopb.SearchTagValuesRequest")
+	proto.RegisterType((*SearchTagValuesResponse)(nil), "tempopb.SearchTagValuesResponse")
+	proto.RegisterType((*TagValue)(nil), "tempopb.TagValue")
+	proto.RegisterType((*SearchTagValuesV2Response)(nil), "tempopb.SearchTagValuesV2Response")
+	proto.RegisterType((*TagValueCount)(nil), "tempopb.TagValueCount")
+	proto.RegisterType((*SearchTagValueStatsResponse)(nil), "tempopb.SearchTagValueStatsResponse")
+	proto.RegisterType((*Trace)(nil), "tempopb.Trace")
+	proto.RegisterType((*PushResponse)(nil), "tempopb.PushResponse")
+	proto.RegisterType((*PushBytesRequest)(nil), "tempopb.PushBytesRequest")
+	proto.RegisterType((*PushSpansRequest)(nil), "tempopb.PushSpansRequest")
+	proto.RegisterType((*TraceBytes)(nil), "tempopb.TraceBytes")
+	proto.RegisterType((*LinkSlice)(nil), "tempopb.LinkSlice")
+	proto.RegisterType((*SpanMetricsRequest)(nil), "tempopb.SpanMetricsRequest")
+	proto.RegisterType((*SpanMetricsSummaryRequest)(nil), "tempopb.SpanMetricsSummaryRequest")
+	proto.RegisterType((*SpanMetricsResponse)(nil), "tempopb.SpanMetricsResponse")
+	proto.RegisterType((*RawHistogram)(nil), "tempopb.RawHistogram")
+	proto.RegisterType((*SpanMetrics)(nil), "tempopb.SpanMetrics")
+	proto.RegisterType((*SpanMetricsSummary)(nil), "tempopb.SpanMetricsSummary")
+	proto.RegisterType((*SpanMetricsSummaryResponse)(nil), "tempopb.SpanMetricsSummaryResponse")
+	proto.RegisterType((*TraceQLStatic)(nil), "tempopb.TraceQLStatic")
+	proto.RegisterType((*QueryRangeRequest)(nil), "tempopb.QueryRangeRequest")
+	proto.RegisterType((*QueryRangeResponse)(nil), "tempopb.QueryRangeResponse")
+	proto.RegisterType((*QueryRangeSeries)(nil), "tempopb.QueryRangeSeries")
+	proto.RegisterType((*QueryRangeLabel)(nil), "tempopb.QueryRangeLabel")
+	proto.RegisterType((*QueryRangeSample)(nil), "tempopb.QueryRangeSample")
 }
 
-func (m *TraceByIDResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func init() { proto.RegisterFile("pkg/tempopb/tempo.proto", fileDescriptor_f22805646f4f62b6) }
+
+var fileDescriptor_f22805646f4f62b6 = []byte{
+	// 2625 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0xbd, 0x73, 0x5b, 0xc7,
+	0x11, 0xd7, 0x23, 0x3e, 0x48, 0x2c, 0x00, 0x89, 0x3c, 0x4b, 0x34, 0x0c, 0x39, 0x14, 0xe7, 0x59,
+	0x63, 0x2b, 0x1e, 0x1b, 0xa4, 0x60, 0x29, 0xb6, 0xac, 0xc4, 0x1e, 0xd3, 0x92, 0xf5, 0x61, 0xd2,
+	0xa6, 0x0f, 0x0c, 0x33, 0xe3, 0x22, 0x9e, 0xc3, 0xc3, 0x09, 0x7c, 0x21, 0xf0, 0x1e, 0xfc, 0xde,
+	0x03, 0x2d, 0xa4, 0x4f, 0xd2, 0xa4, 0x48, 0x93, 0xc2, 0xe3, 0x19, 0xcf, 0xa4, 0x4c, 0xda, 0x24,
+	0x33, 0x29, 0xd2, 0xa5, 0x71, 0x91, 0xc2, 0x93, 0x2a, 0x93, 0xc2, 0x93, 0x91, 0xff, 0x82, 0x94,
+	0xe9, 0x32, 0xbb, 0x77, 0xf7, 0xbe, 0xf0, 0x08, 0xd9, 0x51, 0x8a, 0x54, 0x78, 0xfb, 0xbb, 0xbd,
+	0xbd, 0xbd, 0xdd, 0xbd, 0xdd, 0xbd, 0x03, 0x3c, 0x3d, 0x39, 0x1e, 0x6e, 0x45, 0x72, 0x3c, 0xf1,
+	0x27, 0x7d, 0xf5, 0xdb, 0x99, 0x04, 0x7e, 0xe4, 0xb3, 0x65, 0x0d, 0xb6, 0xcf, 0x47, 0x81, 0x70,
+	0xe4, 0xd6, 0xc9, 0xd5, 0x2d, 0xfa, 0x50, 0xc3, 0xed, 0x75, 0xc7, 0x1f, 0x8f, 0x7d, 0x0f, 0x61,
+	0xf5, 0xa5, 0xf1, 0x97, 0x87, 0x6e, 0x74, 0x34, 0xed, 0x77, 0x1c, 0x7f, 0xbc, 0x35, 0xf4, 0x87,
+	0xfe, 0x16, 0xc1, 0xfd, 0xe9, 0x03, 0xa2, 0x88, 0xa0, 0x2f, 0xc5, 0x6e, 0xff, 0xdc, 0x82, 0xd5,
+	0x03, 0x14, 0xbb, 0x33, 0xbb, 0x77, 0x8b, 0xcb, 0x8f, 0xa7, 0x32, 0x8c, 0x58, 0x0b, 0x96, 0x69,
+	0xa9, 0x7b, 0xb7, 0x5a, 0xd6, 0xa6, 0x75, 0xa5, 0xc1, 0x0d, 0xc9, 0x36, 0x00, 0xfa, 0x23, 0xdf,
+	0x39, 0xee, 0x45, 0x22, 0x88, 0x5a, 0x4b, 0x9b, 0xd6, 0x95, 0x1a, 0x4f, 0x21, 0xac, 0x0d, 0x2b,
+	0x44, 0xdd, 0xf6, 0x06, 0xad, 0x12, 0x8d, 0xc6, 0x34, 0x7b, 0x16, 0x6a, 0x1f, 0x4f, 0x65, 0x30,
+	0xdb, 0xf3, 0x07, 0xb2, 0x55, 0xa1, 0xc1, 0x04, 0xb0, 0xff, 0x60, 0xc1, 0x7a, 0xac, 0x48, 0x2f,
+	0x0a, 0xa4, 0x18, 0x3f, 0x5e, 0x9d, 0xf3, 0x50, 0x09, 0x63, 0x4d, 0x9a, 0x5c, 0x11, 0x6c, 0x15,
+	0x4a, 0x52, 0xaf, 0xdf, 0xe4, 0xf8, 0x99, 0x53, 0xbb, 0xbc, 0x50, 0xed, 0xca, 0x22, 0xb5, 0xab,
+	0x79, 0xb5, 0xff, 0x64, 0xc1, 0x5a, 0xca, 0x7e, 0xe1, 0xc4, 0xf7, 0x42, 0xc9, 0x2e, 0x43, 0x85,
+	0x54, 0x24, 0x7d, 0xeb, 0xdd, 0xb3, 0x1d, 0xed, 0xcb, 0x0e, 0xb1, 0x72, 0x35, 0xc8, 0x5e, 0x81,
+	0xe5, 0xb1, 0x8c, 0x02, 0xd7, 0x09, 0x49, 0xff, 0x7a, 0xf7, 0x99, 0x2c, 0x1f, 0x8a, 0xdc, 0x53,
+	0x0c, 0xdc, 0x70, 0xb2, 0x0e, 0x54, 0xc3, 0x48, 0x44, 0xd3, 0x90, 0xf6, 0x77, 0xb6, 0xbb, 0x1e,
+	0xcf, 0xd9, 0x17, 0x41, 0xe4, 0x8a, 0x51, 0x8f, 0x46, 0xb9, 0xe6, 0x42, 0xe3, 0x8d, 0x65, 0x18,
+	0x8a, 0xa1, 0xd4, 0xfb, 0x36, 0xa4, 0xbd, 0x0f, 0xab, 0xf9, 0x65, 0x70, 0xb3, 0x91, 0x1f, 0x89,
+	0xd1, 0x7d, 0xbf, 0x1f, 0x92, 0xf2, 0x4d, 0x9e, 0x00, 0x68, 0xc6, 0x07, 0xc2, 0x1d, 0xc9, 0x01,
+	0x0d, 0x2b, 0x9b, 0xa7, 0x10, 0x7b, 0x4b, 0xdb, 0x22, 0x4c, 0x07, 0x53, 0x1b, 0x56, 0xb4, 0xbb,
+	0x50, 0x62, 0x09, 0x6d, 0x6b, 0x68, 0xfb, 0xcf, 0x16, 0xb0, 0xf4, 0x0c, 0x6d, 0xbe, 0x37, 0xa1,
+	0x4a, 0x2c, 0x6a, 0x42, 0xbd, 0xfb, 0x42, 0xd6, 0x2e, 0x19, 0x66, 0x0d, 0xdd, 0xf6, 0xa2, 0x60,
+	0xc6, 0xf5, 0x34, 0x5c, 0xd3, 0xf3, 0xa3, 0x77, 0xfc, 0xa9, 0x37, 0x68, 0x2d, 0xa9, 0x35, 0x0d,
+	0xdd, 0xbe, 0x07, 0xf5, 0xd4, 0x14, 0x0c, 0x96, 0x63, 0x39, 0xa3, 0xbd, 0xd6, 0x38, 0x7e, 0xa2,
+	0xf3, 0x4e, 0xc4, 0x68, 0x2a, 0x5b, 0x4b, 0xc5, 0xce, 0xa3, 0xc1, 0xd7, 0x97, 0x5e, 0xb3, 0xec,
+	0x47, 0x25, 0x68, 0x10, 0xd8, 0x9b, 0x8e, 0xc7, 0x22, 0x98, 0xa1, 0xf9, 0xc2, 0x89, 0xf0, 0xde,
+	0xf6, 0xa7, 0x5e, 0x64, 0xcc, 0x17, 0x03, 0x68, 0x3e, 0x19, 0x04, 0x7e, 0xa0, 0x86, 0xb5, 0xf9,
+	0x12, 0x84, 0xbd, 0x04, 0x6b, 0x14, 0xc0, 0x07, 0xee, 0x58, 0xfe, 0xd0, 0x73, 0x1f, 0xbe, 0x27,
+	0x3c, 0x9f, 0xbc, 0x5c, 0xe6, 0xf3, 0x03, 0xec, 0x32, 0x34, 0x07, 0xd3, 0x40, 0x44, 0xae, 0xef,
+	0x21, 0x1d, 0x92, 0x7b, 0xcb, 0x3c, 0x0b, 0xe2, 0x09, 0x19, 0xc8, 0x49, 0x74, 0x44, 0x61, 0xdd,
+	0xe4, 0x8a, 0xc0, 0xb9, 0x81, 0xef, 0x47, 0xbd, 0x58, 0xd7, 0x2a, 0x8d, 0x66, 0x41, 0x76, 0x1d,
+	0x56, 0x0c, 0xd0, 0x5a, 0x2e, 0x0a, 0x50, 0xbd, 0x6d, 0x64, 0xe0, 0x31, 0x2b, 0x7b, 0x0d, 0x56,
+	0x42, 0x19, 0x9c, 0xb8, 0xe8, 0xbf, 0x15, 0xf2, 0xdf, 0xb3, 0xc5, 0xd3, 0x14, 0x13, 0x8f, 0xb9,
+	0xd9, 0x4b, 0x50, 0x1e, 0x8a, 0x49, 0xd8, 0xaa, 0xd1, 0xac, 0x56, 0xe1, 0xac, 0x3b, 0x62, 0xc2,
+	0x89, 0x8b, 0xfd, 0x00, 0x1a, 0x23, 0x11, 0x0c, 0x65, 0x48, 0xcb, 0x86, 0x2d, 0xd8, 0x2c, 0x2d,
+	0x56, 0x31, 0xc3, 0xce, 0x9e, 0x87, 0xb3, 0xce, 0xcc, 0x19, 0xc9, 0xc4, 0x08, 0x75, 0x32, 0x42,
+	0x0e, 0xb5, 0xff, 0x6a, 0x32, 0x64, 0x4a, 0x14, 0x5b, 0x87, 0x2a, 0xfa, 0x55, 0x67, 0xa4, 0x1a,
+	0xd7, 0x14, 0xdb, 0x84, 0xba, 0xde, 0xcd, 0x7b, 0x62, 0x2c, 0x75, 0x82, 0x4c, 0x43, 0x8c, 0x41,
+	0xd9, 0xc3, 0x21, 0x95, 0x1d, 0xe9, 0xbb, 0xd8, 0xf1, 0xe5, 0x6f, 0xec, 0xf8, 0x4a, 0x91, 0xe3,
+	0xd7, 0xe3, 0x3c, 0x51, 0xd5, 0x1a, 0x12, 0x65, 0xff, 0xd1, 0x82, 0xa7, 0x0a, 0xbc, 0x90, 0xd7,
+	0xdc, 0x9a, 0xd7, 0x3c, 0x13, 0xdc, 0x4b, 0x8b, 0x83, 0xbb, 0x34, 0x17, 0xdc, 0x6f, 0x00, 0xf8,
+	0x13, 0xa9, 0x34, 0xc4, 0x58, 0x45, 0x5f, 0x6d, 0x14, 0xfa, 0xea, 0x7d, 0xc3, 0xc6, 0x53, 0x33,
+	0xec, 0x4f, 0x2d, 0xb8, 0x50, 0xc8, 0x15, 0x5b, 0xd4, 0x4a, 0x59, 0xf4, 0xc9, 0x74, 0xed, 0x00,
+	0xa3, 0xa4, 0x77, 0xab, 0xe0, 0x7c, 0x15, 0x8c, 0xd8, 0xbf, 0xb7, 0xe0, 0x5c, 0x2e, 0x46, 0xff,
+	0xff, 0x23, 0xc4, 0xfe, 0xa2, 0x04, 0xcd, 0x9e, 0x14, 0x81, 0x73, 0x64, 0x52, 0xf5, 0xeb, 0x50,
+	0x3e, 0x10, 0x43, 0x93, 0x75, 0x37, 0x63, 0xef, 0x64, 0xb8, 0x3a, 0xc8, 0x42, 0xb9, 0x73, 0xa7,
+	0xfc, 0xc5, 0x57, 0x97, 0xce, 0x70, 0x9a, 0x83, 0x6b, 0xee, 0xb9, 0x9e, 0xb1, 0xcb, 0x9e, 0x29,
+	0x0f, 0x59, 0x90, 0xb8, 0xc4, 0xc3, 0x14, 0x57, 0x49, 0x73, 0xa5, 0x41, 0x4c, 0x5a, 0xbb, 0xee,
+	0xd8, 0x55, 0x95, 0xba, 0xc9, 0x15, 0x91, 0x14, 0xfb, 0x4a, 0x41, 0xb1, 0xaf, 0x26, 0xc5, 0xfe,
+	0x3c, 0x54, 0x3e, 0xc0, 0xfa, 0xdc, 0x5a, 0x21, 0x03, 0x2a, 0x82, 0x5d, 0x81, 0x73, 0x74, 0xee,
+	0xf7, 0x65, 0x80, 0xbf, 0x3d, 0x19, 0xb5, 0x6a, 0x34, 0x27, 0x0f, 0x63, 0xec, 0x4c, 0xc4, 0xd0,
+	0xf5, 0x44, 0x24, 0x07, 0x2d, 0xd8, 0xb4, 0xae, 0xac, 0xf0, 0x04, 0x60, 0xdf, 0x83, 0x75, 0xf1,
+	0x20, 0x92, 0x41, 0x6f, 0xce, 0x1d, 0x75, 0x32, 0xf2, 0x29, 0xa3, 0xcc, 0x86, 0x06, 0x8d, 0x1c,
+	0xe8, 0x4e, 0xa6, 0x41, 0xca, 0x65, 0xb0, 0xf6, 0xab, 0x50, 0x8b, 0x8d, 0x5b, 0x50, 0x98, 0xce,
+	0xa7, 0x0b, 0x53, 0x2d, 0x5d, 0x88, 0x7e, 0x57, 0x02, 0xa6, 0x9c, 0xb4, 0x83, 0x6d, 0x8b, 0xf1,
+	0xe7, 0x35, 0xa8, 0x85, 0xc6, 0x75, 0xba, 0x15, 0x59, 0x2f, 0x76, 0x2a, 0x4f, 0x18, 0xb1, 0x63,
+	0xa0, 0xe6, 0xe7, 0xde, 0x2d, 0xbd, 0x90, 0x21, 0xe9, 0x54, 0xe1, 0xc6, 0xf6, 0xb1, 0x9b, 0x28,
+	0xe9, 0x53, 0x65, 0x00, 0xf4, 0xed, 0x44, 0x0c, 0x65, 0x78, 0xe0, 0x2b, 0xd1, 0xda, 0x7b, 0x59,
+	0x10, 0x4b, 0xb3, 0xf4, 0x1c, 0x7f, 0xe0, 0x7a, 0x43, 0xd3, 0x6a, 0x19, 0x1a, 0x25, 0xb8, 0xde,
+	0x40, 0x3e, 0x44, 0x71, 0x3d, 0xf7, 0xa7, 0xd2, 0x94, 0xa5, 0x0c, 0x88, 0x96, 0xa4, 0x33, 0xc8,
+	0xa5, 0xe3, 0x07, 0x83, 0x90, 0x4a, 0x53, 0x93, 0x67, 0x30, 0xe4, 0x19, 0x88, 0x48, 0xdc, 0x36,
+	0x2b, 0xa9, 0x50, 0xc8, 0x60, 0xb8, 0xcf, 0x13, 0x19, 0x84, 0xae, 0xef, 0x51, 0x24, 0xd4, 0xb8,
+	0x21, 0xf1, 0x04, 0x86, 0xb8, 0x3c, 0x90, 0x47, 0xe9, 0x9b, 0x7a, 0x1f, 0xdf, 0x47, 0xd7, 0xe2,
+	0x48, 0x5d, 0xf7, 0x3e, 0x31, 0x82, 0xf1, 0xe5, 0xf8, 0xe3, 0x89, 0x70, 0x30, 0x86, 0x77, 0xe5,
+	0x89, 0x1c, 0x91, 0x8b, 0x9b, 0x3c, 0x0f, 0xdb, 0x9f, 0x5b, 0x70, 0xd6, 0x18, 0x5f, 0x37, 0x3c,
+	0xd7, 0x72, 0x0d, 0x4f, 0xbe, 0x60, 0x12, 0xf7, 0x9e, 0x8c, 0x04, 0x6e, 0x20, 0xee, 0x72, 0xb6,
+	0xf3, 0xfd, 0x63, 0xde, 0xb9, 0x73, 0xcd, 0xe3, 0x65, 0x68, 0x7a, 0xf2, 0x21, 0xb9, 0xeb, 0xc0,
+	0x3f, 0x96, 0x9e, 0xce, 0x31, 0x59, 0xd0, 0xfe, 0xcc, 0x82, 0x73, 0x4a, 0x40, 0x8c, 0x61, 0x34,
+	0x7a, 0xfe, 0x27, 0x14, 0x44, 0x25, 0x8e, 0x9f, 0x68, 0x10, 0x15, 0x33, 0x77, 0x45, 0x78, 0x44,
+	0x0a, 0x94, 0x79, 0x0a, 0x61, 0xd7, 0xe0, 0xc2, 0x48, 0x84, 0x51, 0xef, 0x94, 0x8e, 0xa6, 0x78,
+	0x10, 0xd3, 0x23, 0x0e, 0x98, 0x53, 0xa2, 0x5a, 0xd6, 0x34, 0x64, 0xff, 0x3b, 0x2e, 0x60, 0x19,
+	0xab, 0xe4, 0x6f, 0x09, 0xb5, 0xe4, 0x96, 0x70, 0x05, 0xce, 0x51, 0x73, 0x32, 0x97, 0x76, 0xf3,
+	0xb0, 0xe9, 0x8b, 0x48, 0xfc, 0x7b, 0x49, 0x0e, 0xce, 0x82, 0xdf, 0x32, 0x19, 0x6f, 0x00, 0x0c,
+	0x92, 0x7c, 0xa7, 0x72, 0x57, 0x0a, 0x61, 0x2f, 0xc2, 0x72, 0xa8, 0x13, 0x52, 0x95, 0xbc, 0xb8,
+	0x9a, 0x78, 0x51, 0xe1, 0xdc, 0x30, 0xd8, 0x3f, 0xb3, 0x60, 0x59, 0x83, 0xec, 0x39, 0xa8, 0x84,
+	0xd4, 0xf7, 0xa8, 0x90, 0x69, 0x66, 0x66, 0x71, 0x35, 0x46, 0xdd, 0xbf, 0x88, 0x9c, 0x23, 0x39,
+	0xd0, 0xf9, 0xd8, 0x90, 0xec, 0x26, 0x80, 0x88, 0xa2, 0xc0, 0xed, 0x4f, 0x23, 0x89, 0x69, 0x18,
+	0x65, 0x5c, 0x8c, 0x65, 0xe8, 0x2b, 0xe5, 0xc9, 0xd5, 0xce, 0xbb, 0x72, 0x76, 0x88, 0x79, 0x86,
+	0xa7, 0xd8, 0xed, 0xbf, 0x58, 0x50, 0x5e, 0xd8, 0x07, 0x99, 0x1a, 0xb6, 0xf4, 0xb8, 0x1a, 0xf6,
+	0x84, 0xed, 0x6d, 0x76, 0x17, 0x95, 0x6f, 0xb7, 0x8b, 0x7f, 0x59, 0xd0, 0xcc, 0x1c, 0x14, 0x8c,
+	0x14, 0xd7, 0x0b, 0x27, 0xd2, 0x89, 0xe4, 0xe0, 0xc0, 0x1c, 0x48, 0x3a, 0xc4, 0x39, 0x18, 0xbb,
+	0xc7, 0x18, 0xda, 0x99, 0xe1, 0xe2, 0xea, 0x04, 0xe4, 0x50, 0x8c, 0x67, 0x4a, 0x4c, 0x94, 0x97,
+	0x4d, 0xb9, 0x4b, 0x43, 0xb8, 0x51, 0xcc, 0x10, 0x23, 0x19, 0xe9, 0x7b, 0x95, 0x4e, 0x9b, 0x19,
+	0x30, 0x7b, 0x31, 0xab, 0xe4, 0x2f, 0x66, 0x57, 0xe0, 0x5c, 0x22, 0x52, 0xa9, 0x53, 0x25, 0x75,
+	0xf2, 0xb0, 0xfd, 0x5d, 0x58, 0x53, 0x5b, 0xc6, 0x42, 0x63, 0xea, 0x04, 0x56, 0x56, 0xc7, 0x9f,
+	0x98, 0x16, 0x4a, 0x11, 0xf6, 0x36, 0xb0, 0x34, 0xab, 0x4e, 0x55, 0x78, 0x9d, 0x13, 0x43, 0x3c,
+	0x07, 0xc9, 0x75, 0x4e, 0xd3, 0xf6, 0x7d, 0x38, 0x9f, 0xcc, 0x38, 0xec, 0xc6, 0x73, 0xba, 0x50,
+	0x25, 0x91, 0x26, 0x56, 0xdb, 0xb9, 0x3c, 0xa5, 0xd8, 0x7b, 0xc8, 0xc2, 0x35, 0xa7, 0x7d, 0x13,
+	0xd6, 0xe6, 0x06, 0x0b, 0x5b, 0x3d, 0x06, 0xe5, 0x08, 0x9b, 0x16, 0x75, 0xcf, 0xa3, 0x6f, 0xfb,
+	0x2e, 0xac, 0xc7, 0x93, 0xc9, 0xef, 0x61, 0xfa, 0x2d, 0x41, 0xa9, 0x1b, 0x67, 0x09, 0x45, 0xa2,
+	0x11, 0xe8, 0x5a, 0x6f, 0xaa, 0x2b, 0x11, 0xf6, 0xab, 0xf0, 0xf4, 0x9c, 0x24, 0xbd, 0x2b, 0x74,
+	0x89, 0x01, 0xb5, 0x29, 0x12, 0xc0, 0xbe, 0x06, 0x2b, 0x66, 0x0a, 0xa9, 0x38, 0x8b, 0xcd, 0x4b,
+	0xdf, 0xc5, 0xc5, 0xdc, 0xde, 0x85, 0x67, 0x72, 0xcb, 0xa5, 0xcc, 0xb8, 0x95, 0x5f, 0xb0, 0xde,
+	0x5d, 0x4b, 0x0a, 0x85, 0x1e, 0x49, 0xeb, 0xf0, 0x3e, 0x34, 0x0d, 0xac, 0x1a, 0xdb, 0x6f, 0xac,
+	0x08, 0xa2, 0x4e, 0xdc, 0x1d, 0x97, 0xb9, 0x22, 0xec, 0x5f, 0x58, 0x70, 0x31, 0xab, 0x5f, 0x2f,
+	0x12, 0x51, 0x62, 0x92, 0x0e, 0x54, 0x4f, 0xd2, 0xea, 0xad, 0xcf, 0xa9, 0x47, 0x7a, 0x70, 0xcd,
+	0x85, 0xa7, 0xc3, 0x11, 0xc1, 0xc0, 0xf5, 0xc4, 0xc8, 0x8d, 0x66, 0xfa, 0x08, 0xa5, 0x21, 0x4a,
+	0x30, 0xc7, 0x32, 0x72, 0x8e, 0x48, 0x91, 0x06, 0xd7, 0x94, 0xbd, 0x03, 0x15, 0x3a, 0x89, 0xec,
+	0x06, 0x2c, 0xf7, 0x29, 0xa5, 0x99, 0x35, 0x2f, 0xc5, 0x6b, 0xaa, 0xe7, 0xb2, 0x93, 0xab, 0x1d,
+	0x2e, 0x43, 0x7f, 0x1a, 0x38, 0x74, 0xa9, 0x0b, 0xb9, 0xe1, 0xb7, 0xcf, 0x42, 0x63, 0x7f, 0x1a,
+	0xc6, 0x55, 0xd8, 0xfe, 0x8d, 0x05, 0xab, 0x08, 0xd0, 0x49, 0x31, 0x01, 0xf3, 0x72, 0x5c, 0x9a,
+	0x31, 0xc0, 0x1a, 0x3b, 0x17, 0xb0, 0xe7, 0xfd, 0xc7, 0x57, 0x97, 0x9a, 0xfb, 0x81, 0x14, 0xa3,
+	0x91, 0xef, 0x28, 0x6e, 0xcd, 0xc4, 0x5e, 0x80, 0x92, 0x3b, 0x50, 0xf9, 0xf4, 0x54, 0x5e, 0xe4,
+	0x60, 0xd7, 0x4d, 0xf9, 0xbc, 0x25, 0x22, 0xd1, 0x2a, 0x2f, 0xe2, 0x4f, 0x31, 0xda, 0x7b, 0x4a,
+	0x45, 0xb5, 0x13, 0xad, 0xe2, 0x13, 0x98, 0xe0, 0x32, 0x80, 0x7e, 0x03, 0xc2, 0x64, 0xb5, 0x9e,
+	0x69, 0x43, 0x1a, 0x66, 0x53, 0xf6, 0x1b, 0x50, 0xdb, 0x75, 0xbd, 0xe3, 0xde, 0x08, 0x2f, 0x8a,
+	0x57, 0xa1, 0x32, 0x72, 0xbd, 0x63, 0xb3, 0xd6, 0xc5, 0xf9, 0xb5, 0x70, 0x8d, 0x0e, 0x4e, 0xe0,
+	0x8a, 0xd3, 0xfe, 0x10, 0x18, 0x62, 0xa6, 0x1d, 0x49, 0xb2, 0x8e, 0x3a, 0x70, 0x56, 0xea, 0xc0,
+	0xe1, 0x01, 0x1d, 0x06, 0xfe, 0x74, 0xb2, 0x63, 0x0e, 0xa2, 0x21, 0x91, 0x7f, 0x44, 0xb7, 0x02,
+	0x1d, 0x92, 0x44, 0xd8, 0x02, 0x9e, 0x49, 0xc9, 0xd6, 0x17, 0xb0, 0xff, 0xed, 0x12, 0xbf, 0xb5,
+	0xe0, 0xa9, 0x8c, 0xfe, 0x49, 0x02, 0x90, 0x61, 0xe4, 0x8e, 0xe9, 0xa2, 0x60, 0xa9, 0x8b, 0x42,
+	0x0c, 0xcc, 0x5f, 0x41, 0xcb, 0xe9, 0x2b, 0xe8, 0xf3, 0x70, 0x96, 0x2e, 0x9c, 0xc9, 0xeb, 0x83,
+	0x5a, 0x32, 0x87, 0xb2, 0x4e, 0xd2, 0xe3, 0xa9, 0x3b, 0xf3, 0xf9, 0x4c, 0x9d, 0xcf, 0x77, 0x78,
+	0xf6, 0xf7, 0xa1, 0xc1, 0xc5, 0x27, 0x77, 0xdd, 0x30, 0xf2, 0x87, 0x81, 0x18, 0xa3, 0x4b, 0xfb,
+	0x53, 0xe7, 0x58, 0xaa, 0xe7, 0xa8, 0x32, 0xd7, 0x54, 0x72, 0xbe, 0x97, 0xd2, 0xe7, 0xfb, 0x53,
+	0x0b, 0xea, 0x29, 0xb1, 0x6c, 0x07, 0xd6, 0x46, 0x22, 0x92, 0x9e, 0x33, 0xfb, 0xe8, 0xc8, 0x88,
+	0xd4, 0x7e, 0xbf, 0x10, 0xeb, 0x91, 0x5e, 0x8f, 0xaf, 0x6a, 0xfe, 0x44, 0x03, 0xfd, 0x60, 0xe9,
+	0x3a, 0x73, 0x4d, 0x2a, 0x45, 0xde, 0x07, 0xbb, 0x3d, 0x1a, 0xe5, 0x9a, 0x0b, 0x35, 0x26, 0x1b,
+	0x84, 0xda, 0x22, 0x9a, 0xb2, 0xff, 0x66, 0x01, 0x9b, 0xf7, 0xf4, 0xfc, 0x93, 0xdb, 0x63, 0xcc,
+	0xbc, 0x74, 0x8a, 0x99, 0x8d, 0x92, 0xa5, 0x6f, 0xa4, 0xe4, 0x2a, 0x94, 0x26, 0x37, 0x6e, 0xe8,
+	0x9e, 0x04, 0x3f, 0x15, 0x72, 0x5d, 0xdf, 0xb4, 0xf1, 0x53, 0x21, 0xdb, 0xba, 0x10, 0xe3, 0x27,
+	0x21, 0xd7, 0xb7, 0x5b, 0xcb, 0x1a, 0xb9, 0xbe, 0x6d, 0xff, 0x08, 0xda, 0x45, 0xd1, 0xab, 0x03,
+	0xec, 0x06, 0xd4, 0x42, 0x82, 0x5c, 0x39, 0x7f, 0xdc, 0x0a, 0xe6, 0x25, 0xdc, 0xf6, 0xaf, 0x2d,
+	0x68, 0x66, 0x54, 0xcf, 0xe4, 0xfe, 0x8a, 0xce, 0xfd, 0x0d, 0xb0, 0x3c, 0xb2, 0x48, 0x89, 0x5b,
+	0x1e, 0x52, 0x0f, 0x68, 0xff, 0x16, 0xb7, 0x1e, 0x20, 0x15, 0xea, 0xfe, 0xdb, 0x0a, 0x91, 0xea,
+	0xd3, 0xe6, 0x56, 0xb8, 0xd5, 0x47, 0x6a, 0xa0, 0x37, 0x66, 0x0d, 0x52, 0x4f, 0x4d, 0xcb, 0x24,
+	0x5b, 0x53, 0xb8, 0xe2, 0xb1, 0xeb, 0x0d, 0xe8, 0xf2, 0x55, 0xe1, 0xf4, 0x6d, 0x4b, 0x58, 0xa3,
+	0xfb, 0x38, 0x17, 0xde, 0x50, 0x2e, 0x3e, 0xa6, 0x99, 0xc7, 0xfd, 0x72, 0xc1, 0xe3, 0x7e, 0x59,
+	0xdd, 0xf7, 0xf1, 0xb6, 0x16, 0xc9, 0x89, 0x76, 0x06, 0x7d, 0xdb, 0x77, 0x80, 0xa5, 0x97, 0xd1,
+	0xf6, 0xbc, 0x0a, 0xd5, 0x50, 0xa6, 0x8c, 0x99, 0xbc, 0x15, 0x26, 0xcc, 0x3d, 0x62, 0xe0, 0x9a,
+	0xd1, 0x9e, 0xc1, 0x6a, 0x7e, 0x8c, 0x6d, 0x43, 0x75, 0x24, 0xfa, 0x72, 0x64, 0xc4, 0xb4, 0x0a,
+	0xc4, 0xec, 0x22, 0x03, 0xd7, 0x7c, 0xf8, 0xd2, 0x1f, 0x0a, 0x6c, 0xe7, 0x54, 0x15, 0x39, 0x65,
+	0x65, 0xe2, 0xe0, 0x86, 0xd3, 0xbe, 0x09, 0xe7, 0x72, 0xf2, 0x0a, 0xfb, 0x9f, 0xe2, 0x46, 0xe2,
+	0x7e, 0x46, 0x6f, 0x92, 0x48, 0xbd, 0xa8, 0x3b, 0x96, 0x61, 0x24, 0xc6, 0x93, 0xbd, 0x50, 0xdf,
+	0xe5, 0xd2, 0x50, 0x56, 0x96, 0xa5, 0x65, 0xbd, 0xf8, 0x22, 0x34, 0x33, 0xff, 0x2d, 0xb0, 0x06,
+	0xac, 0xbc, 0xfd, 0xfe, 0xde, 0xfe, 0xee, 0xed, 0x83, 0xdb, 0xab, 0x67, 0x58, 0x1d, 0x96, 0xf7,
+	0xdf, 0xe2, 0x07, 0xf7, 0xde, 0xda, 0x5d, 0xb5, 0xba, 0xbf, 0xb4, 0xa0, 0x8a, 0x05, 0x4a, 0x06,
+	0xec, 0x4d, 0xa8, 0xc5, 0xd5, 0x94, 0x25, 0x1b, 0xce, 0x57, 0xd8, 0xf6, 0x85, 0xcc, 0x50, 0x5c,
+	0x8d, 0xcf, 0xb0, 0xb7, 0xa0, 0x1e, 0x33, 0x1f, 0x76, 0xff, 0x1b, 0x11, 0xdd, 0xcf, 0x2d, 0x58,
+	0xd5, 0x87, 0xe4, 0x8e, 0xf4, 0x64, 0x20, 0x22, 0x3f, 0x56, 0x4c, 0x3d, 0x03, 0x67, 0xa5, 0xa6,
+	0xeb, 0xea, 0xe9, 0x8a, 0xdd, 0x03, 0xb8, 0x23, 0x23, 0x93, 0x24, 0x0b, 0x8f, 0xa4, 0x91, 0xf1,
+	0x6c, 0xf1, 0x60, 0xac, 0xe0, 0x97, 0x15, 0x58, 0x46, 0x47, 0xb9, 0x32, 0x60, 0x77, 0xa1, 0xf9,
+	0x8e, 0xeb, 0x0d, 0xe2, 0x3f, 0x65, 0x58, 0xc1, 0xff, 0x41, 0x46, 0x6e, 0xbb, 0x68, 0x28, 0x65,
+	0xb9, 0x86, 0x79, 0x61, 0x70, 0xa4, 0x17, 0xb1, 0x53, 0x5e, 0x7d, 0xda, 0x4f, 0xcf, 0xe1, 0xb1,
+	0x88, 0xdb, 0x50, 0x4f, 0xbd, 0x28, 0xa5, 0x37, 0x39, 0xf7, 0xce, 0xb4, 0x48, 0xcc, 0x1d, 0x80,
+	0xa4, 0x8d, 0x67, 0x45, 0x8d, 0xbf, 0x11, 0x72, 0xb1, 0x70, 0x2c, 0x16, 0xf4, 0x2e, 0x34, 0x12,
+	0xfc, 0xb0, 0xbb, 0x50, 0xd4, 0x77, 0x0a, 0xef, 0x17, 0x29, 0x61, 0x87, 0xe6, 0x81, 0x23, 0x6e,
+	0xb3, 0xd9, 0xa5, 0xf9, 0x39, 0x99, 0x9b, 0x43, 0x7b, 0xf3, 0x74, 0x86, 0x58, 0xee, 0x87, 0xb0,
+	0x96, 0x1b, 0x3c, 0xec, 0x3e, 0x5e, 0xb2, 0x7d, 0x1a, 0x43, 0x46, 0xe7, 0x1f, 0xc3, 0x53, 0x05,
+	0xad, 0xf7, 0xe3, 0xa5, 0x5f, 0x3e, 0x85, 0x21, 0xd3, 0xb9, 0x2b, 0x4f, 0x25, 0x19, 0x23, 0x65,
+	0xde, 0xb9, 0x74, 0xdd, 0xbe, 0x58, 0x38, 0x16, 0x87, 0xf4, 0x67, 0x16, 0xac, 0xaa, 0x3f, 0x70,
+	0x5d, 0x6f, 0x68, 0x62, 0xfb, 0x26, 0x54, 0xd5, 0xf2, 0xdf, 0x3a, 0x16, 0xb7, 0x2d, 0x76, 0x1f,
+	0x6a, 0xc9, 0xa1, 0xb8, 0x34, 0x1f, 0xf9, 0x99, 0xbf, 0x8b, 0x17, 0x1d, 0x8d, 0x6d, 0xab, 0xfb,
+	0x13, 0x58, 0x36, 0x07, 0xf7, 0xa3, 0xc2, 0x86, 0xc2, 0x5e, 0x54, 0x61, 0xf5, 0x12, 0xcf, 0x2d,
+	0xe4, 0x31, 0x96, 0xd8, 0x69, 0x7d, 0xf1, 0x68, 0xc3, 0xfa, 0xf2, 0xd1, 0x86, 0xf5, 0xcf, 0x47,
+	0x1b, 0xd6, 0xaf, 0xbe, 0xde, 0x38, 0xf3, 0xe5, 0xd7, 0x1b, 0x67, 0xfe, 0xfe, 0xf5, 0xc6, 0x99,
+	0x7e, 0x95, 0xfe, 0x7d, 0x7f, 0xe5, 0x3f, 0x03, 0x00, 0x22, 0x62, 0xd2, 0x6e, 0xfe, 0x1f, 0x00,
+	0x00,
 }
 
-func (m *TraceByIDResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PusherClient is the client API for Pusher service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PusherClient interface {
+	// different versions of PushBytes expect the trace data to be pushed in different formats
+	PushBytes(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error)
+	PushBytesV2(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error)
 }
 
-func (m *TraceByIDResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Metrics != nil {
-		{
-			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.Trace != nil {
-		{
-			size, err := m.Trace.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
+type pusherClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPusherClient(cc *grpc.ClientConn) PusherClient {
+	return &pusherClient{cc}
+}
+
+func (c *pusherClient) PushBytes(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Pusher/PushBytes", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func (m *TraceByIDMetrics) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *pusherClient) PushBytesV2(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Pusher/PushBytesV2", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *TraceByIDMetrics) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// PusherServer is the server API for Pusher service.
+type PusherServer interface {
+	// different versions of PushBytes expect the trace data to be pushed in different formats
+	PushBytes(context.Context, *PushBytesRequest) (*PushResponse, error)
+	PushBytesV2(context.Context, *PushBytesRequest) (*PushResponse, error)
 }
 
-func (m *TraceByIDMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+// UnimplementedPusherServer can be embedded to have forward compatible implementations.
+type UnimplementedPusherServer struct {
 }
 
-func (m *SearchRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (*UnimplementedPusherServer) PushBytes(ctx context.Context, req *PushBytesRequest) (*PushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushBytes not implemented")
+}
+func (*UnimplementedPusherServer) PushBytesV2(ctx context.Context, req *PushBytesRequest) (*PushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushBytesV2 not implemented")
 }
 
-func (m *SearchRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func RegisterPusherServer(s *grpc.Server, srv PusherServer) {
+	s.RegisterService(&_Pusher_serviceDesc, srv)
 }
 
-func (m *SearchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.SpansPerSpanSet != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.SpansPerSpanSet))
-		i--
-		dAtA[i] = 0x48
+func _Pusher_PushBytes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushBytesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
-		i--
-		dAtA[i] = 0x42
+	if interceptor == nil {
+		return srv.(PusherServer).PushBytes(ctx, in)
 	}
-	if m.End != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.End))
-		i--
-		dAtA[i] = 0x30
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Pusher/PushBytes",
 	}
-	if m.Start != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
-		i--
-		dAtA[i] = 0x28
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PusherServer).PushBytes(ctx, req.(*PushBytesRequest))
 	}
-	if m.Limit != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
-		i--
-		dAtA[i] = 0x20
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Pusher_PushBytesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushBytesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.MaxDurationMs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.MaxDurationMs))
-		i--
-		dAtA[i] = 0x18
+	if interceptor == nil {
+		return srv.(PusherServer).PushBytesV2(ctx, in)
 	}
-	if m.MinDurationMs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.MinDurationMs))
-		i--
-		dAtA[i] = 0x10
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Pusher/PushBytesV2",
 	}
-	if len(m.Tags) > 0 {
-		for k := range m.Tags {
-			v := m.Tags[k]
-			baseI := i
-			i -= len(v)
-			copy(dAtA[i:], v)
-			i = encodeVarintTempo(dAtA, i, uint64(len(v)))
-			i--
-			dAtA[i] = 0x12
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintTempo(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintTempo(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0xa
-		}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PusherServer).PushBytesV2(ctx, req.(*PushBytesRequest))
 	}
-	return len(dAtA) - i, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchBlockRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+var _Pusher_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.Pusher",
+	HandlerType: (*PusherServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PushBytes",
+			Handler:    _Pusher_PushBytes_Handler,
+		},
+		{
+			MethodName: "PushBytesV2",
+			Handler:    _Pusher_PushBytesV2_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/tempopb/tempo.proto",
+}
+
+// MetricsGeneratorClient is the client API for MetricsGenerator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MetricsGeneratorClient interface {
+	PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error)
+	GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error)
+}
+
+type metricsGeneratorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMetricsGeneratorClient(cc *grpc.ClientConn) MetricsGeneratorClient {
+	return &metricsGeneratorClient{cc}
+}
+
+func (c *metricsGeneratorClient) PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/PushSpans", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *SearchBlockRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (c *metricsGeneratorClient) GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error) {
+	out := new(SpanMetricsResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/GetMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (m *SearchBlockRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.FooterSize != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.FooterSize))
-		i--
-		dAtA[i] = 0x58
-	}
-	if m.Size_ != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Size_))
-		i--
-		dAtA[i] = 0x50
-	}
-	if len(m.Version) > 0 {
-		i -= len(m.Version)
-		copy(dAtA[i:], m.Version)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Version)))
-		i--
-		dAtA[i] = 0x4a
+// MetricsGeneratorServer is the server API for MetricsGenerator service.
+type MetricsGeneratorServer interface {
+	PushSpans(context.Context, *PushSpansRequest) (*PushResponse, error)
+	GetMetrics(context.Context, *SpanMetricsRequest) (*SpanMetricsResponse, error)
+}
+
+// UnimplementedMetricsGeneratorServer can be embedded to have forward compatible implementations.
+type UnimplementedMetricsGeneratorServer struct {
+}
+
+func (*UnimplementedMetricsGeneratorServer) PushSpans(ctx context.Context, req *PushSpansRequest) (*PushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushSpans not implemented")
+}
+func (*UnimplementedMetricsGeneratorServer) GetMetrics(ctx context.Context, req *SpanMetricsRequest) (*SpanMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetrics not implemented")
+}
+
+func RegisterMetricsGeneratorServer(s *grpc.Server, srv MetricsGeneratorServer) {
+	s.RegisterService(&_MetricsGenerator_serviceDesc, srv)
+}
+
+func _MetricsGenerator_PushSpans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushSpansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if len(m.DataEncoding) > 0 {
-		i -= len(m.DataEncoding)
-		copy(dAtA[i:], m.DataEncoding)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.DataEncoding)))
-		i--
-		dAtA[i] = 0x42
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).PushSpans(ctx, in)
 	}
-	if m.TotalRecords != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.TotalRecords))
-		i--
-		dAtA[i] = 0x38
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/PushSpans",
 	}
-	if m.IndexPageSize != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.IndexPageSize))
-		i--
-		dAtA[i] = 0x30
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).PushSpans(ctx, req.(*PushSpansRequest))
 	}
-	if len(m.Encoding) > 0 {
-		i -= len(m.Encoding)
-		copy(dAtA[i:], m.Encoding)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Encoding)))
-		i--
-		dAtA[i] = 0x2a
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsGenerator_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpanMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.PagesToSearch != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.PagesToSearch))
-		i--
-		dAtA[i] = 0x20
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).GetMetrics(ctx, in)
 	}
-	if m.StartPage != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.StartPage))
-		i--
-		dAtA[i] = 0x18
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/GetMetrics",
 	}
-	if len(m.BlockID) > 0 {
-		i -= len(m.BlockID)
-		copy(dAtA[i:], m.BlockID)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockID)))
-		i--
-		dAtA[i] = 0x12
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).GetMetrics(ctx, req.(*SpanMetricsRequest))
 	}
-	if m.SearchReq != nil {
+	return interceptor(ctx, in, info, handler)
+}
+
+var _MetricsGenerator_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.MetricsGenerator",
+	HandlerType: (*MetricsGeneratorServer)(nil),
+	Methods: []grpc.MethodDesc{
 		{
-			size, err := m.SearchReq.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+			MethodName: "PushSpans",
+			Handler:    _MetricsGenerator_PushSpans_Handler,
+		},
+		{
+			MethodName: "GetMetrics",
+			Handler:    _MetricsGenerator_GetMetrics_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/tempopb/tempo.proto",
 }
 
-func (m *SearchResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+// QuerierClient is the client API for Querier service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type QuerierClient interface {
+	FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error)
+	SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	SearchBlock(ctx context.Context, in *SearchBlockRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error)
+	SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error)
+	SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error)
+	SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error)
+	SearchTagValueStats(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValueStatsResponse, error)
+	QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error)
 }
 
-func (m *SearchResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type querierClient struct {
+	cc *grpc.ClientConn
 }
 
-func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Metrics != nil {
-		{
-			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.Traces) > 0 {
-		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Traces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+func NewQuerierClient(cc *grpc.ClientConn) QuerierClient {
+	return &querierClient{cc}
+}
+
+func (c *querierClient) FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error) {
+	out := new(TraceByIDResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/FindTraceByID", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func (m *TraceSearchMetadata) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchRecent", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *TraceSearchMetadata) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) SearchBlock(ctx context.Context, in *SearchBlockRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (m *TraceSearchMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.SpanSet != nil {
-		{
-			size, err := m.SpanSet.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x32
-	}
-	if m.DurationMs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.DurationMs))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.StartTimeUnixNano != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
-		i--
-		dAtA[i] = 0x20
-	}
-	if len(m.RootTraceName) > 0 {
-		i -= len(m.RootTraceName)
-		copy(dAtA[i:], m.RootTraceName)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.RootTraceName)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.RootServiceName) > 0 {
-		i -= len(m.RootServiceName)
-		copy(dAtA[i:], m.RootServiceName)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.RootServiceName)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.TraceID) > 0 {
-		i -= len(m.TraceID)
-		copy(dAtA[i:], m.TraceID)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
-		i--
-		dAtA[i] = 0xa
+func (c *querierClient) SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error) {
+	out := new(SearchTagsResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTags", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func (m *SpanSet) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error) {
+	out := new(SearchTagsV2Response)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagsV2", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *SpanSet) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error) {
+	out := new(SearchTagValuesResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagValues", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (m *SpanSet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Attributes) > 0 {
-		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if m.Matched != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Matched))
-		i--
-		dAtA[i] = 0x10
+func (c *querierClient) SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error) {
+	out := new(SearchTagValuesV2Response)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagValuesV2", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	if len(m.Spans) > 0 {
-		for iNdEx := len(m.Spans) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Spans[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+	return out, nil
+}
+
+func (c *querierClient) SearchTagValueStats(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValueStatsResponse, error) {
+	out := new(SearchTagValueStatsResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagValueStats", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func (m *Span) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error) {
+	out := new(QueryRangeResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/QueryRange", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *Span) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// QuerierServer is the server API for Querier service.
+type QuerierServer interface {
+	FindTraceByID(context.Context, *TraceByIDRequest) (*TraceByIDResponse, error)
+	SearchRecent(context.Context, *SearchRequest) (*SearchResponse, error)
+	SearchBlock(context.Context, *SearchBlockRequest) (*SearchResponse, error)
+	SearchTags(context.Context, *SearchTagsRequest) (*SearchTagsResponse, error)
+	SearchTagsV2(context.Context, *SearchTagsRequest) (*SearchTagsV2Response, error)
+	SearchTagValues(context.Context, *SearchTagValuesRequest) (*SearchTagValuesResponse, error)
+	SearchTagValuesV2(context.Context, *SearchTagValuesRequest) (*SearchTagValuesV2Response, error)
+	SearchTagValueStats(context.Context, *SearchTagValuesRequest) (*SearchTagValueStatsResponse, error)
+	QueryRange(context.Context, *QueryRangeRequest) (*QueryRangeResponse, error)
 }
 
-func (m *Span) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Attributes) > 0 {
-		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x2a
-		}
-	}
-	if m.DurationNanos != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
-		i--
-		dAtA[i] = 0x20
+// UnimplementedQuerierServer can be embedded to have forward compatible implementations.
+type UnimplementedQuerierServer struct {
+}
+
+func (*UnimplementedQuerierServer) FindTraceByID(ctx context.Context, req *TraceByIDRequest) (*TraceByIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindTraceByID not implemented")
+}
+func (*UnimplementedQuerierServer) SearchRecent(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchRecent not implemented")
+}
+func (*UnimplementedQuerierServer) SearchBlock(ctx context.Context, req *SearchBlockRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchBlock not implemented")
+}
+func (*UnimplementedQuerierServer) SearchTags(ctx context.Context, req *SearchTagsRequest) (*SearchTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTags not implemented")
+}
+func (*UnimplementedQuerierServer) SearchTagsV2(ctx context.Context, req *SearchTagsRequest) (*SearchTagsV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagsV2 not implemented")
+}
+func (*UnimplementedQuerierServer) SearchTagValues(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValues not implemented")
+}
+func (*UnimplementedQuerierServer) SearchTagValuesV2(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValuesV2 not implemented")
+}
+func (*UnimplementedQuerierServer) SearchTagValueStats(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValueStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValueStats not implemented")
+}
+func (*UnimplementedQuerierServer) QueryRange(ctx context.Context, req *QueryRangeRequest) (*QueryRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRange not implemented")
+}
+
+func RegisterQuerierServer(s *grpc.Server, srv QuerierServer) {
+	s.RegisterService(&_Querier_serviceDesc, srv)
+}
+
+func _Querier_FindTraceByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.StartTimeUnixNano != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
-		i--
-		dAtA[i] = 0x18
+	if interceptor == nil {
+		return srv.(QuerierServer).FindTraceByID(ctx, in)
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
-		i--
-		dAtA[i] = 0x12
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/FindTraceByID",
 	}
-	if len(m.SpanID) > 0 {
-		i -= len(m.SpanID)
-		copy(dAtA[i:], m.SpanID)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanID)))
-		i--
-		dAtA[i] = 0xa
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).FindTraceByID(ctx, req.(*TraceByIDRequest))
 	}
-	return len(dAtA) - i, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchMetrics) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func _Querier_SearchRecent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
-}
-
-func (m *SearchMetrics) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchRecent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchRecent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchRecent(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.TotalBlockBytes != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.TotalBlockBytes))
-		i--
-		dAtA[i] = 0x30
+func _Querier_SearchBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.TotalJobs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.TotalJobs))
-		i--
-		dAtA[i] = 0x28
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchBlock(ctx, in)
 	}
-	if m.CompletedJobs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.CompletedJobs))
-		i--
-		dAtA[i] = 0x20
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchBlock",
 	}
-	if m.TotalBlocks != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.TotalBlocks))
-		i--
-		dAtA[i] = 0x18
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchBlock(ctx, req.(*SearchBlockRequest))
 	}
-	if m.InspectedBytes != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedBytes))
-		i--
-		dAtA[i] = 0x10
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Querier_SearchTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.InspectedTraces != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedTraces))
-		i--
-		dAtA[i] = 0x8
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchTags(ctx, in)
 	}
-	return len(dAtA) - i, nil
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchTags(ctx, req.(*SearchTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchTagsRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func _Querier_SearchTagsV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchTagsV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchTagsV2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchTagsV2(ctx, req.(*SearchTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchTagsRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func _Querier_SearchTagValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchTagValues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchTagValues",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchTagValues(ctx, req.(*SearchTagValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchTagsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Scope) > 0 {
-		i -= len(m.Scope)
-		copy(dAtA[i:], m.Scope)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Scope)))
-		i--
-		dAtA[i] = 0xa
+func _Querier_SearchTagValuesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchTagValuesV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchTagValuesV2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchTagValuesV2(ctx, req.(*SearchTagValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchTagsResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func _Querier_SearchTagValueStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagValuesRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchTagValueStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchTagValueStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchTagValueStats(ctx, req.(*SearchTagValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchTagsResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func _Querier_QueryRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuerierServer).QueryRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/QueryRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).QueryRange(ctx, req.(*QueryRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchTagsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.TagNames) > 0 {
-		for iNdEx := len(m.TagNames) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.TagNames[iNdEx])
-			copy(dAtA[i:], m.TagNames[iNdEx])
-			i = encodeVarintTempo(dAtA, i, uint64(len(m.TagNames[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+var _Querier_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.Querier",
+	HandlerType: (*QuerierServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindTraceByID",
+			Handler:    _Querier_FindTraceByID_Handler,
+		},
+		{
+			MethodName: "SearchRecent",
+			Handler:    _Querier_SearchRecent_Handler,
+		},
+		{
+			MethodName: "SearchBlock",
+			Handler:    _Querier_SearchBlock_Handler,
+		},
+		{
+			MethodName: "SearchTags",
+			Handler:    _Querier_SearchTags_Handler,
+		},
+		{
+			MethodName: "SearchTagsV2",
+			Handler:    _Querier_SearchTagsV2_Handler,
+		},
+		{
+			MethodName: "SearchTagValues",
+			Handler:    _Querier_SearchTagValues_Handler,
+		},
+		{
+			MethodName: "SearchTagValuesV2",
+			Handler:    _Querier_SearchTagValuesV2_Handler,
+		},
+		{
+			MethodName: "SearchTagValueStats",
+			Handler:    _Querier_SearchTagValueStats_Handler,
+		},
+		{
+			MethodName: "QueryRange",
+			Handler:    _Querier_QueryRange_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/tempopb/tempo.proto",
 }
 
-func (m *SearchTagsV2Response) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+// StreamingQuerierClient is the client API for StreamingQuerier service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StreamingQuerierClient interface {
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchClient, error)
+	TraceByID(ctx context.Context, in *TraceByIDStreamRequest, opts ...grpc.CallOption) (StreamingQuerier_TraceByIDClient, error)
 }
 
-func (m *SearchTagsV2Response) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type streamingQuerierClient struct {
+	cc *grpc.ClientConn
 }
 
-func (m *SearchTagsV2Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Scopes) > 0 {
-		for iNdEx := len(m.Scopes) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Scopes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+func NewStreamingQuerierClient(cc *grpc.ClientConn) StreamingQuerierClient {
+	return &streamingQuerierClient{cc}
 }
 
-func (m *SearchTagsV2Scope) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *streamingQuerierClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingQuerier_serviceDesc.Streams[0], "/tempopb.StreamingQuerier/Search", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
-}
-
-func (m *SearchTagsV2Scope) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *SearchTagsV2Scope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Tags) > 0 {
-		for iNdEx := len(m.Tags) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Tags[iNdEx])
-			copy(dAtA[i:], m.Tags[iNdEx])
-			i = encodeVarintTempo(dAtA, i, uint64(len(m.Tags[iNdEx])))
-			i--
-			dAtA[i] = 0x12
-		}
+	x := &streamingQuerierSearchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
-		i--
-		dAtA[i] = 0xa
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return x, nil
 }
 
-func (m *SearchTagValuesRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type StreamingQuerier_SearchClient interface {
+	Recv() (*SearchResponse, error)
+	grpc.ClientStream
 }
 
-func (m *SearchTagValuesRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type streamingQuerierSearchClient struct {
+	grpc.ClientStream
 }
 
-func (m *SearchTagValuesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.TagName) > 0 {
-		i -= len(m.TagName)
-		copy(dAtA[i:], m.TagName)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.TagName)))
-		i--
-		dAtA[i] = 0xa
+func (x *streamingQuerierSearchClient) Recv() (*SearchResponse, error) {
+	m := new(SearchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return m, nil
 }
 
-func (m *SearchTagValuesResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *streamingQuerierClient) TraceByID(ctx context.Context, in *TraceByIDStreamRequest, opts ...grpc.CallOption) (StreamingQuerier_TraceByIDClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingQuerier_serviceDesc.Streams[1], "/tempopb.StreamingQuerier/TraceByID", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	x := &streamingQuerierTraceByIDClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (m *SearchTagValuesResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type StreamingQuerier_TraceByIDClient interface {
+	Recv() (*TraceByIDResponse, error)
+	grpc.ClientStream
 }
 
-func (m *SearchTagValuesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.TagValues) > 0 {
-		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.TagValues[iNdEx])
-			copy(dAtA[i:], m.TagValues[iNdEx])
-			i = encodeVarintTempo(dAtA, i, uint64(len(m.TagValues[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+type streamingQuerierTraceByIDClient struct {
+	grpc.ClientStream
 }
 
-func (m *TagValue) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func (x *streamingQuerierTraceByIDClient) Recv() (*TraceByIDResponse, error) {
+	m := new(TraceByIDResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return m, nil
 }
 
-func (m *TagValue) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// StreamingQuerierServer is the server API for StreamingQuerier service.
+type StreamingQuerierServer interface {
+	Search(*SearchRequest, StreamingQuerier_SearchServer) error
+	TraceByID(*TraceByIDStreamRequest, StreamingQuerier_TraceByIDServer) error
 }
 
-func (m *TagValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Value) > 0 {
-		i -= len(m.Value)
-		copy(dAtA[i:], m.Value)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Value)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.Type) > 0 {
-		i -= len(m.Type)
-		copy(dAtA[i:], m.Type)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Type)))
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+// UnimplementedStreamingQuerierServer can be embedded to have forward compatible implementations.
+type UnimplementedStreamingQuerierServer struct {
 }
 
-func (m *SearchTagValuesV2Response) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (*UnimplementedStreamingQuerierServer) Search(req *SearchRequest, srv StreamingQuerier_SearchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
-
-func (m *SearchTagValuesV2Response) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (*UnimplementedStreamingQuerierServer) TraceByID(req *TraceByIDStreamRequest, srv StreamingQuerier_TraceByIDServer) error {
+	return status.Errorf(codes.Unimplemented, "method TraceByID not implemented")
 }
 
-func (m *SearchTagValuesV2Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.TagValues) > 0 {
-		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.TagValues[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+func RegisterStreamingQuerierServer(s *grpc.Server, srv StreamingQuerierServer) {
+	s.RegisterService(&_StreamingQuerier_serviceDesc, srv)
 }
 
-func (m *Trace) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func _StreamingQuerier_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return dAtA[:n], nil
+	return srv.(StreamingQuerierServer).Search(m, &streamingQuerierSearchServer{stream})
 }
 
-func (m *Trace) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type StreamingQuerier_SearchServer interface {
+	Send(*SearchResponse) error
+	grpc.ServerStream
 }
 
-func (m *Trace) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Batches) > 0 {
-		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+type streamingQuerierSearchServer struct {
+	grpc.ServerStream
 }
 
-func (m *PushResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (x *streamingQuerierSearchServer) Send(m *SearchResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (m *PushResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func _StreamingQuerier_TraceByID_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TraceByIDStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamingQuerierServer).TraceByID(m, &streamingQuerierTraceByIDServer{stream})
 }
 
-func (m *PushResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+type StreamingQuerier_TraceByIDServer interface {
+	Send(*TraceByIDResponse) error
+	grpc.ServerStream
 }
 
-func (m *PushBytesRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type streamingQuerierTraceByIDServer struct {
+	grpc.ServerStream
 }
 
-func (m *PushBytesRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (x *streamingQuerierTraceByIDServer) Send(m *TraceByIDResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (m *PushBytesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.SearchData) > 0 {
-		for iNdEx := len(m.SearchData) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.SearchData[iNdEx].Size()
-				i -= size
-				if _, err := m.SearchData[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x22
-		}
-	}
-	if len(m.Ids) > 0 {
-		for iNdEx := len(m.Ids) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.Ids[iNdEx].Size()
-				i -= size
-				if _, err := m.Ids[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if len(m.Traces) > 0 {
-		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.Traces[iNdEx].Size()
-				i -= size
-				if _, err := m.Traces[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-		}
-	}
-	return len(dAtA) - i, nil
+var _StreamingQuerier_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.StreamingQuerier",
+	HandlerType: (*StreamingQuerierServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Search",
+			Handler:       _StreamingQuerier_Search_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TraceByID",
+			Handler:       _StreamingQuerier_TraceByID_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/tempopb/tempo.proto",
 }
 
-func (m *PushSpansRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+// MetricsClient is the client API for Metrics service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MetricsClient interface {
+	SpanMetricsSummary(ctx context.Context, in *SpanMetricsSummaryRequest, opts ...grpc.CallOption) (*SpanMetricsSummaryResponse, error)
+}
+
+type metricsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMetricsClient(cc *grpc.ClientConn) MetricsClient {
+	return &metricsClient{cc}
+}
+
+func (c *metricsClient) SpanMetricsSummary(ctx context.Context, in *SpanMetricsSummaryRequest, opts ...grpc.CallOption) (*SpanMetricsSummaryResponse, error) {
+	out := new(SpanMetricsSummaryResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Metrics/SpanMetricsSummary", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *PushSpansRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// MetricsServer is the server API for Metrics service.
+type MetricsServer interface {
+	SpanMetricsSummary(context.Context, *SpanMetricsSummaryRequest) (*SpanMetricsSummaryResponse, error)
 }
 
-func (m *PushSpansRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Batches) > 0 {
-		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+// UnimplementedMetricsServer can be embedded to have forward compatible implementations.
+type UnimplementedMetricsServer struct {
+}
+
+func (*UnimplementedMetricsServer) SpanMetricsSummary(ctx context.Context, req *SpanMetricsSummaryRequest) (*SpanMetricsSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpanMetricsSummary not implemented")
+}
+
+func RegisterMetricsServer(s *grpc.Server, srv MetricsServer) {
+	s.RegisterService(&_Metrics_serviceDesc, srv)
+}
+
+func _Metrics_SpanMetricsSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpanMetricsSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	if interceptor == nil {
+		return srv.(MetricsServer).SpanMetricsSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Metrics/SpanMetricsSummary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServer).SpanMetricsSummary(ctx, req.(*SpanMetricsSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *TraceBytes) Marshal() (dAtA []byte, err error) {
+var _Metrics_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.Metrics",
+	HandlerType: (*MetricsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SpanMetricsSummary",
+			Handler:    _Metrics_SpanMetricsSummary_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/tempopb/tempo.proto",
+}
+
+func (m *TraceByIDRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3819,29 +4185,48 @@ func (m *TraceBytes) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TraceBytes) MarshalTo(dAtA []byte) (int, error) {
+func (m *TraceByIDRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TraceBytes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TraceByIDRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Traces) > 0 {
-		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Traces[iNdEx])
-			copy(dAtA[i:], m.Traces[iNdEx])
-			i = encodeVarintTempo(dAtA, i, uint64(len(m.Traces[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
+	if len(m.QueryMode) > 0 {
+		i -= len(m.QueryMode)
+		copy(dAtA[i:], m.QueryMode)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.QueryMode)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.BlockEnd) > 0 {
+		i -= len(m.BlockEnd)
+		copy(dAtA[i:], m.BlockEnd)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockEnd)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.BlockStart) > 0 {
+		i -= len(m.BlockStart)
+		copy(dAtA[i:], m.BlockStart)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockStart)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.TraceID) > 0 {
+		i -= len(m.TraceID)
+		copy(dAtA[i:], m.TraceID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *LinkSlice) Marshal() (dAtA []byte, err error) {
+func (m *TraceByIDStreamRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3851,34 +4236,58 @@ func (m *LinkSlice) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *LinkSlice) MarshalTo(dAtA []byte) (int, error) {
+func (m *TraceByIDStreamRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *LinkSlice) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TraceByIDStreamRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Links) > 0 {
-		for iNdEx := len(m.Links) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Links[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+	if len(m.QueryMode) > 0 {
+		i -= len(m.QueryMode)
+		copy(dAtA[i:], m.QueryMode)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.QueryMode)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.BlockEnd) > 0 {
+		i -= len(m.BlockEnd)
+		copy(dAtA[i:], m.BlockEnd)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockEnd)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.BlockStart) > 0 {
+		i -= len(m.BlockStart)
+		copy(dAtA[i:], m.BlockStart)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockStart)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.End != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Start != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.TraceID) > 0 {
+		i -= len(m.TraceID)
+		copy(dAtA[i:], m.TraceID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsRequest) Marshal() (dAtA []byte, err error) {
+func (m *TraceByIDResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3888,39 +4297,56 @@ func (m *SpanMetricsRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SpanMetricsRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *TraceByIDResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SpanMetricsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TraceByIDResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Limit != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Status != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Status))
 		i--
 		dAtA[i] = 0x18
 	}
-	if len(m.GroupBy) > 0 {
-		i -= len(m.GroupBy)
-		copy(dAtA[i:], m.GroupBy)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.GroupBy)))
+	if m.Metrics != nil {
+		{
+			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
 		i--
 		dAtA[i] = 0x12
 	}
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+	if m.Trace != nil {
+		{
+			size, err := m.Trace.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsSummaryRequest) Marshal() (dAtA []byte, err error) {
+func (m *TraceByIDMetrics) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3930,39 +4356,30 @@ func (m *SpanMetricsSummaryRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SpanMetricsSummaryRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *TraceByIDMetrics) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SpanMetricsSummaryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TraceByIDMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Limit != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.GroupBy) > 0 {
-		i -= len(m.GroupBy)
-		copy(dAtA[i:], m.GroupBy)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.GroupBy)))
+	if m.FailedJobs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.FailedJobs))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x10
 	}
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+	if m.TotalJobs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalJobs))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsResponse) Marshal() (dAtA []byte, err error) {
+func (m *TracesByIDRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3972,54 +4389,29 @@ func (m *SpanMetricsResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SpanMetricsResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *TracesByIDRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SpanMetricsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TracesByIDRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Metrics) > 0 {
-		for iNdEx := len(m.Metrics) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Metrics[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
+	if len(m.TraceIDs) > 0 {
+		for iNdEx := len(m.TraceIDs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TraceIDs[iNdEx])
+			copy(dAtA[i:], m.TraceIDs[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceIDs[iNdEx])))
 			i--
-			dAtA[i] = 0x22
-		}
-	}
-	if m.ErrorSpanCount != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorSpanCount))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.SpanCount != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
-		i--
-		dAtA[i] = 0x10
-	}
-	if m.Estimated {
-		i--
-		if m.Estimated {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+			dAtA[i] = 0xa
 		}
-		i--
-		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *RawHistogram) Marshal() (dAtA []byte, err error) {
+func (m *TracesByIDResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4029,30 +4421,55 @@ func (m *RawHistogram) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *RawHistogram) MarshalTo(dAtA []byte) (int, error) {
+func (m *TracesByIDResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *RawHistogram) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TracesByIDResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Count != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Count))
-		i--
-		dAtA[i] = 0x10
+	if len(m.NotFound) > 0 {
+		for iNdEx := len(m.NotFound) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.NotFound[iNdEx])
+			copy(dAtA[i:], m.NotFound[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.NotFound[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	if m.Bucket != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Bucket))
-		i--
-		dAtA[i] = 0x8
+	if len(m.Traces) > 0 {
+		for k := range m.Traces {
+			v := m.Traces[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintTempo(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintTempo(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintTempo(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetrics) Marshal() (dAtA []byte, err error) {
+func (m *TraceSummary) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4062,37 +4479,39 @@ func (m *SpanMetrics) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SpanMetrics) MarshalTo(dAtA []byte) (int, error) {
+func (m *TraceSummary) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SpanMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TraceSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Errors != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Errors))
+	if m.CycleSpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.CycleSpanCount))
 		i--
-		dAtA[i] = 0x18
+		dAtA[i] = 0x58
 	}
-	if m.Static != nil {
-		{
-			size, err := m.Static.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
+	if len(m.LargestSpans) > 0 {
+		for iNdEx := len(m.LargestSpans) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.LargestSpans[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
 			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
+			i--
+			dAtA[i] = 0x52
 		}
-		i--
-		dAtA[i] = 0x12
 	}
-	if len(m.LatencyHistogram) > 0 {
-		for iNdEx := len(m.LatencyHistogram) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.Gaps) > 0 {
+		for iNdEx := len(m.Gaps) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.LatencyHistogram[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Gaps[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -4100,13 +4519,69 @@ func (m *SpanMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 				i = encodeVarintTempo(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0xa
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.Services) > 0 {
+		for iNdEx := len(m.Services) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Services[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.RootSpan != nil {
+		{
+			size, err := m.RootSpan.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.RootSpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.RootSpanCount))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Depth != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Depth))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.DurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.StartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.ErrorCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.SpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
+		i--
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsSummary) Marshal() (dAtA []byte, err error) {
+func (m *TraceSummarySpan) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4116,62 +4591,58 @@ func (m *SpanMetricsSummary) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SpanMetricsSummary) MarshalTo(dAtA []byte) (int, error) {
+func (m *TraceSummarySpan) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SpanMetricsSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TraceSummarySpan) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.P50 != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.P50))
-		i--
-		dAtA[i] = 0x38
-	}
-	if m.P90 != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.P90))
+	if len(m.Status) > 0 {
+		i -= len(m.Status)
+		copy(dAtA[i:], m.Status)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Status)))
 		i--
-		dAtA[i] = 0x30
+		dAtA[i] = 0x32
 	}
-	if m.P95 != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.P95))
+	if m.DurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
 		i--
 		dAtA[i] = 0x28
 	}
-	if m.P99 != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.P99))
+	if m.StartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
 		i--
 		dAtA[i] = 0x20
 	}
-	if m.Static != nil {
-		{
-			size, err := m.Static.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
-		}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
 		i--
 		dAtA[i] = 0x1a
 	}
-	if m.ErrorSpanCount != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorSpanCount))
+	if len(m.ServiceName) > 0 {
+		i -= len(m.ServiceName)
+		copy(dAtA[i:], m.ServiceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ServiceName)))
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x12
 	}
-	if m.SpanCount != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
+	if len(m.SpanID) > 0 {
+		i -= len(m.SpanID)
+		copy(dAtA[i:], m.SpanID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanID)))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsSummaryResponse) Marshal() (dAtA []byte, err error) {
+func (m *TraceSummaryService) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4181,20 +4652,20 @@ func (m *SpanMetricsSummaryResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SpanMetricsSummaryResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *TraceSummaryService) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SpanMetricsSummaryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TraceSummaryService) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Summaries) > 0 {
-		for iNdEx := len(m.Summaries) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.Operations) > 0 {
+		for iNdEx := len(m.Operations) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.Summaries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Operations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -4202,13 +4673,30 @@ func (m *SpanMetricsSummaryResponse) MarshalToSizedBuffer(dAtA []byte) (int, err
 				i = encodeVarintTempo(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0xa
+			dAtA[i] = 0x22
 		}
 	}
+	if m.ErrorCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorCount))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.SpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ServiceName) > 0 {
+		i -= len(m.ServiceName)
+		copy(dAtA[i:], m.ServiceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ServiceName)))
+		i--
+		dAtA[i] = 0xa
+	}
 	return len(dAtA) - i, nil
 }
 
-func (m *TraceQLStatic) Marshal() (dAtA []byte, err error) {
+func (m *TraceSummaryOperation) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4218,755 +4706,5649 @@ func (m *TraceQLStatic) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TraceQLStatic) MarshalTo(dAtA []byte) (int, error) {
+func (m *TraceSummaryOperation) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TraceQLStatic) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TraceSummaryOperation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Kind != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Kind))
+	if m.TotalDurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalDurationNanos))
 		i--
-		dAtA[i] = 0x40
+		dAtA[i] = 0x20
 	}
-	if m.Status != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Status))
-		i--
-		dAtA[i] = 0x38
-	}
-	if m.D != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.D))
-		i--
-		dAtA[i] = 0x30
-	}
-	if m.B {
-		i--
-		if m.B {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
+	if m.ErrorCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorCount))
 		i--
-		dAtA[i] = 0x28
-	}
-	if len(m.S) > 0 {
-		i -= len(m.S)
-		copy(dAtA[i:], m.S)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.S)))
-		i--
-		dAtA[i] = 0x22
-	}
-	if m.F != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.F))))
-		i--
-		dAtA[i] = 0x19
+		dAtA[i] = 0x18
 	}
-	if m.N != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.N))
+	if m.SpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
 		i--
 		dAtA[i] = 0x10
 	}
-	if m.Type != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Type))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintTempo(dAtA []byte, offset int, v uint64) int {
-	offset -= sovTempo(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *TraceSummaryGap) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *TraceByIDRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *TraceSummaryGap) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceSummaryGap) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.TraceID)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.DurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
+		i--
+		dAtA[i] = 0x28
 	}
-	l = len(m.BlockStart)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.StartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
+		i--
+		dAtA[i] = 0x20
 	}
-	l = len(m.BlockEnd)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	l = len(m.QueryMode)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.ServiceName) > 0 {
+		i -= len(m.ServiceName)
+		copy(dAtA[i:], m.ServiceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ServiceName)))
+		i--
+		dAtA[i] = 0x12
 	}
-	return n
+	if len(m.SpanID) > 0 {
+		i -= len(m.SpanID)
+		copy(dAtA[i:], m.SpanID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *TraceByIDResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Trace != nil {
-		l = m.Trace.Size()
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.Metrics != nil {
-		l = m.Metrics.Size()
-		n += 1 + l + sovTempo(uint64(l))
+func (m *SearchRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *TraceByIDMetrics) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	return n
+func (m *SearchRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SearchRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SearchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Tags) > 0 {
-		for k, v := range m.Tags {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovTempo(uint64(len(k))) + 1 + len(v) + sovTempo(uint64(len(v)))
-			n += mapEntrySize + 1 + sovTempo(uint64(mapEntrySize))
+	if len(m.AfterTraceID) > 0 {
+		i -= len(m.AfterTraceID)
+		copy(dAtA[i:], m.AfterTraceID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.AfterTraceID)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.AfterStartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.AfterStartTimeUnixNano))
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.Paginated {
+		i--
+		if m.Paginated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
+		i--
+		dAtA[i] = 0x50
 	}
-	if m.MinDurationMs != 0 {
-		n += 1 + sovTempo(uint64(m.MinDurationMs))
+	if m.SpansPerSpanSet != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpansPerSpanSet))
+		i--
+		dAtA[i] = 0x48
 	}
-	if m.MaxDurationMs != 0 {
-		n += 1 + sovTempo(uint64(m.MaxDurationMs))
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0x42
 	}
-	if m.Limit != 0 {
-		n += 1 + sovTempo(uint64(m.Limit))
+	if m.End != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x30
 	}
 	if m.Start != 0 {
-		n += 1 + sovTempo(uint64(m.Start))
+		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x28
 	}
-	if m.End != 0 {
-		n += 1 + sovTempo(uint64(m.End))
+	if m.Limit != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x20
 	}
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.MaxDurationMs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.MaxDurationMs))
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.SpansPerSpanSet != 0 {
-		n += 1 + sovTempo(uint64(m.SpansPerSpanSet))
+	if m.MinDurationMs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.MinDurationMs))
+		i--
+		dAtA[i] = 0x10
 	}
-	return n
+	if len(m.Tags) > 0 {
+		for k := range m.Tags {
+			v := m.Tags[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintTempo(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintTempo(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintTempo(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *SearchBlockRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchBlockRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchBlockRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchBlockRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.SearchReq != nil {
-		l = m.SearchReq.Size()
-		n += 1 + l + sovTempo(uint64(l))
+	if m.CompactionLevel != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.CompactionLevel))
+		i--
+		dAtA[i] = 0x60
 	}
-	l = len(m.BlockID)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.FooterSize != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.FooterSize))
+		i--
+		dAtA[i] = 0x58
 	}
-	if m.StartPage != 0 {
-		n += 1 + sovTempo(uint64(m.StartPage))
+	if m.Size_ != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Size_))
+		i--
+		dAtA[i] = 0x50
 	}
-	if m.PagesToSearch != 0 {
-		n += 1 + sovTempo(uint64(m.PagesToSearch))
+	if len(m.Version) > 0 {
+		i -= len(m.Version)
+		copy(dAtA[i:], m.Version)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Version)))
+		i--
+		dAtA[i] = 0x4a
 	}
-	l = len(m.Encoding)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.DataEncoding) > 0 {
+		i -= len(m.DataEncoding)
+		copy(dAtA[i:], m.DataEncoding)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.DataEncoding)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.TotalRecords != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalRecords))
+		i--
+		dAtA[i] = 0x38
 	}
 	if m.IndexPageSize != 0 {
-		n += 1 + sovTempo(uint64(m.IndexPageSize))
+		i = encodeVarintTempo(dAtA, i, uint64(m.IndexPageSize))
+		i--
+		dAtA[i] = 0x30
 	}
-	if m.TotalRecords != 0 {
-		n += 1 + sovTempo(uint64(m.TotalRecords))
+	if len(m.Encoding) > 0 {
+		i -= len(m.Encoding)
+		copy(dAtA[i:], m.Encoding)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Encoding)))
+		i--
+		dAtA[i] = 0x2a
 	}
-	l = len(m.DataEncoding)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.PagesToSearch != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.PagesToSearch))
+		i--
+		dAtA[i] = 0x20
 	}
-	l = len(m.Version)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.StartPage != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartPage))
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.Size_ != 0 {
-		n += 1 + sovTempo(uint64(m.Size_))
+	if len(m.BlockID) > 0 {
+		i -= len(m.BlockID)
+		copy(dAtA[i:], m.BlockID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockID)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.FooterSize != 0 {
-		n += 1 + sovTempo(uint64(m.FooterSize))
+	if m.SearchReq != nil {
+		{
+			size, err := m.SearchReq.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SearchResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Traces) > 0 {
-		for _, e := range m.Traces {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if len(m.NextPageToken) > 0 {
+		i -= len(m.NextPageToken)
+		copy(dAtA[i:], m.NextPageToken)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.NextPageToken)))
+		i--
+		dAtA[i] = 0x1a
 	}
 	if m.Metrics != nil {
-		l = m.Metrics.Size()
-		n += 1 + l + sovTempo(uint64(l))
+		{
+			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
 	}
-	return n
+	if len(m.Traces) > 0 {
+		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Traces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *TraceSearchMetadata) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchPageToken) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchPageToken) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchPageToken) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.TraceID)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.RootServiceName)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.RootTraceName)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.LastTraceID) > 0 {
+		i -= len(m.LastTraceID)
+		copy(dAtA[i:], m.LastTraceID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.LastTraceID)))
+		i--
+		dAtA[i] = 0x22
 	}
-	if m.StartTimeUnixNano != 0 {
-		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	if m.LastStartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.LastStartTimeUnixNano))
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.DurationMs != 0 {
-		n += 1 + sovTempo(uint64(m.DurationMs))
+	if m.SearchHash != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SearchHash))
+		i--
+		dAtA[i] = 0x10
 	}
-	if m.SpanSet != nil {
-		l = m.SpanSet.Size()
-		n += 1 + l + sovTempo(uint64(l))
+	if m.Now != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Now))
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanSet) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Spans) > 0 {
-		for _, e := range m.Spans {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
-	}
-	if m.Matched != 0 {
-		n += 1 + sovTempo(uint64(m.Matched))
-	}
-	if len(m.Attributes) > 0 {
-		for _, e := range m.Attributes {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+func (m *TraceSearchMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *Span) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *TraceSearchMetadata) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceSearchMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.SpanID)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.SpanSet != nil {
+		{
+			size, err := m.SpanSet.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
 	}
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.DurationMs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationMs))
+		i--
+		dAtA[i] = 0x28
 	}
 	if m.StartTimeUnixNano != 0 {
-		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
-	}
-	if m.DurationNanos != 0 {
-		n += 1 + sovTempo(uint64(m.DurationNanos))
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
+		i--
+		dAtA[i] = 0x20
 	}
-	if len(m.Attributes) > 0 {
-		for _, e := range m.Attributes {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if len(m.RootTraceName) > 0 {
+		i -= len(m.RootTraceName)
+		copy(dAtA[i:], m.RootTraceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.RootTraceName)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	return n
-}
-
-func (m *SearchMetrics) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.InspectedTraces != 0 {
-		n += 1 + sovTempo(uint64(m.InspectedTraces))
-	}
-	if m.InspectedBytes != 0 {
-		n += 1 + sovTempo(uint64(m.InspectedBytes))
-	}
-	if m.TotalBlocks != 0 {
-		n += 1 + sovTempo(uint64(m.TotalBlocks))
-	}
-	if m.CompletedJobs != 0 {
-		n += 1 + sovTempo(uint64(m.CompletedJobs))
-	}
-	if m.TotalJobs != 0 {
-		n += 1 + sovTempo(uint64(m.TotalJobs))
+	if len(m.RootServiceName) > 0 {
+		i -= len(m.RootServiceName)
+		copy(dAtA[i:], m.RootServiceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.RootServiceName)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.TotalBlockBytes != 0 {
-		n += 1 + sovTempo(uint64(m.TotalBlockBytes))
+	if len(m.TraceID) > 0 {
+		i -= len(m.TraceID)
+		copy(dAtA[i:], m.TraceID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SearchTagsRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Scope)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+func (m *SpanSet) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SearchTagsResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.TagNames) > 0 {
-		for _, s := range m.TagNames {
-			l = len(s)
-			n += 1 + l + sovTempo(uint64(l))
-		}
-	}
-	return n
+func (m *SpanSet) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SearchTagsV2Response) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SpanSet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Scopes) > 0 {
-		for _, e := range m.Scopes {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.Attributes) > 0 {
+		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
 		}
 	}
-	return n
-}
-
-func (m *SearchTagsV2Scope) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.Matched != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Matched))
+		i--
+		dAtA[i] = 0x10
 	}
-	if len(m.Tags) > 0 {
-		for _, s := range m.Tags {
-			l = len(s)
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.Spans) > 0 {
+		for iNdEx := len(m.Spans) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Spans[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SearchTagValuesRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.TagName)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+func (m *Span) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SearchTagValuesResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *Span) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Span) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.TagValues) > 0 {
-		for _, s := range m.TagValues {
-			l = len(s)
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.Attributes) > 0 {
+		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
 		}
 	}
-	return n
-}
-
-func (m *TagValue) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.DurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
+		i--
+		dAtA[i] = 0x20
 	}
-	var l int
-	_ = l
-	l = len(m.Type)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.StartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
+		i--
+		dAtA[i] = 0x18
 	}
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
 	}
-	return n
+	if len(m.SpanID) > 0 {
+		i -= len(m.SpanID)
+		copy(dAtA[i:], m.SpanID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *SearchTagValuesV2Response) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.TagValues) > 0 {
-		for _, e := range m.TagValues {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+func (m *SearchMetrics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *Trace) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SearchMetrics) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Batches) > 0 {
-		for _, e := range m.Batches {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if m.TotalBlockBytes != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalBlockBytes))
+		i--
+		dAtA[i] = 0x30
 	}
-	return n
-}
-
-func (m *PushResponse) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.TotalJobs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalJobs))
+		i--
+		dAtA[i] = 0x28
 	}
-	var l int
-	_ = l
-	return n
-}
-
-func (m *PushBytesRequest) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.CompletedJobs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.CompletedJobs))
+		i--
+		dAtA[i] = 0x20
 	}
-	var l int
-	_ = l
-	if len(m.Traces) > 0 {
-		for _, e := range m.Traces {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if m.TotalBlocks != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalBlocks))
+		i--
+		dAtA[i] = 0x18
 	}
-	if len(m.Ids) > 0 {
-		for _, e := range m.Ids {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if m.InspectedBytes != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedBytes))
+		i--
+		dAtA[i] = 0x10
 	}
-	if len(m.SearchData) > 0 {
-		for _, e := range m.SearchData {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if m.InspectedTraces != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedTraces))
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *PushSpansRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchTagsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Batches) > 0 {
-		for _, e := range m.Batches {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if len(m.Scope) > 0 {
+		i -= len(m.Scope)
+		copy(dAtA[i:], m.Scope)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Scope)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *TraceBytes) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchTagsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Traces) > 0 {
-		for _, b := range m.Traces {
-			l = len(b)
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.TagNames) > 0 {
+		for iNdEx := len(m.TagNames) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TagNames[iNdEx])
+			copy(dAtA[i:], m.TagNames[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.TagNames[iNdEx])))
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *LinkSlice) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchTagsV2Response) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagsV2Response) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagsV2Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Links) > 0 {
-		for _, e := range m.Links {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.Scopes) > 0 {
+		for iNdEx := len(m.Scopes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Scopes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchTagsV2Scope) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagsV2Scope) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagsV2Scope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.GroupBy)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.Tags) > 0 {
+		for iNdEx := len(m.Tags) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Tags[iNdEx])
+			copy(dAtA[i:], m.Tags[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Tags[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	if m.Limit != 0 {
-		n += 1 + sovTempo(uint64(m.Limit))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsSummaryRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchTagValuesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagValuesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagValuesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.GroupBy)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.Limit != 0 {
-		n += 1 + sovTempo(uint64(m.Limit))
+	if len(m.TagName) > 0 {
+		i -= len(m.TagName)
+		copy(dAtA[i:], m.TagName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.TagName)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchTagValuesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	if m.Estimated {
-		n += 2
-	}
-	if m.SpanCount != 0 {
-		n += 1 + sovTempo(uint64(m.SpanCount))
-	}
-	if m.ErrorSpanCount != 0 {
-		n += 1 + sovTempo(uint64(m.ErrorSpanCount))
-	}
-	if len(m.Metrics) > 0 {
-		for _, e := range m.Metrics {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagValuesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagValuesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TagValues) > 0 {
+		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TagValues[iNdEx])
+			copy(dAtA[i:], m.TagValues[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.TagValues[iNdEx])))
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *RawHistogram) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *TagValue) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TagValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TagValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Bucket != 0 {
-		n += 1 + sovTempo(uint64(m.Bucket))
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.Count != 0 {
-		n += 1 + sovTempo(uint64(m.Count))
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetrics) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchTagValuesV2Response) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagValuesV2Response) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagValuesV2Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.LatencyHistogram) > 0 {
-		for _, e := range m.LatencyHistogram {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.TagValues) > 0 {
+		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.TagValues[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	if m.Static != nil {
-		l = m.Static.Size()
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.Errors != 0 {
-		n += 1 + sovTempo(uint64(m.Errors))
-	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsSummary) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *TagValueCount) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TagValueCount) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TagValueCount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.SpanCount != 0 {
-		n += 1 + sovTempo(uint64(m.SpanCount))
-	}
-	if m.ErrorSpanCount != 0 {
-		n += 1 + sovTempo(uint64(m.ErrorSpanCount))
-	}
-	if m.Static != nil {
-		l = m.Static.Size()
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.P99 != 0 {
-		n += 1 + sovTempo(uint64(m.P99))
-	}
-	if m.P95 != 0 {
-		n += 1 + sovTempo(uint64(m.P95))
+	if m.Count != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.P90 != 0 {
-		n += 1 + sovTempo(uint64(m.P90))
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.P50 != 0 {
-		n += 1 + sovTempo(uint64(m.P50))
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsSummaryResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchTagValueStatsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagValueStatsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagValueStatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Summaries) > 0 {
-		for _, e := range m.Summaries {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.Sketch) > 0 {
+		i -= len(m.Sketch)
+		copy(dAtA[i:], m.Sketch)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Sketch)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Cardinality != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Cardinality))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Values[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *TraceQLStatic) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Trace) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Trace) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Trace) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Type != 0 {
-		n += 1 + sovTempo(uint64(m.Type))
+	if len(m.Batches) > 0 {
+		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	if m.N != 0 {
-		n += 1 + sovTempo(uint64(m.N))
+	return len(dAtA) - i, nil
+}
+
+func (m *PushResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	if m.F != 0 {
-		n += 9
+	return dAtA[:n], nil
+}
+
+func (m *PushResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PushResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *PushBytesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	l = len(m.S)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	return dAtA[:n], nil
+}
+
+func (m *PushBytesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PushBytesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.SearchData) > 0 {
+		for iNdEx := len(m.SearchData) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.SearchData[iNdEx].Size()
+				i -= size
+				if _, err := m.SearchData[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
 	}
-	if m.B {
-		n += 2
+	if len(m.Ids) > 0 {
+		for iNdEx := len(m.Ids) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Ids[iNdEx].Size()
+				i -= size
+				if _, err := m.Ids[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
 	}
-	if m.D != 0 {
-		n += 1 + sovTempo(uint64(m.D))
+	if len(m.Traces) > 0 {
+		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Traces[iNdEx].Size()
+				i -= size
+				if _, err := m.Traces[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	if m.Status != 0 {
-		n += 1 + sovTempo(uint64(m.Status))
+	return len(dAtA) - i, nil
+}
+
+func (m *PushSpansRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	if m.Kind != 0 {
-		n += 1 + sovTempo(uint64(m.Kind))
+	return dAtA[:n], nil
+}
+
+func (m *PushSpansRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PushSpansRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Batches) > 0 {
+		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceBytes) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceBytes) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceBytes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Traces[iNdEx])
+			copy(dAtA[i:], m.Traces[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Traces[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LinkSlice) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LinkSlice) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LinkSlice) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Links) > 0 {
+		for iNdEx := len(m.Links) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Links[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Limit != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.GroupBy) > 0 {
+		i -= len(m.GroupBy)
+		copy(dAtA[i:], m.GroupBy)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.GroupBy)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsSummaryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsSummaryRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsSummaryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Limit != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.GroupBy) > 0 {
+		i -= len(m.GroupBy)
+		copy(dAtA[i:], m.GroupBy)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.GroupBy)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Metrics) > 0 {
+		for iNdEx := len(m.Metrics) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Metrics[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.ErrorSpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorSpanCount))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.SpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Estimated {
+		i--
+		if m.Estimated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RawHistogram) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RawHistogram) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RawHistogram) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Bucket != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Bucket))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetrics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetrics) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Errors != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Errors))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Static != nil {
+		{
+			size, err := m.Static.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.LatencyHistogram) > 0 {
+		for iNdEx := len(m.LatencyHistogram) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.LatencyHistogram[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsSummary) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsSummary) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.P50 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P50))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.P90 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P90))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.P95 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P95))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.P99 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P99))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Static != nil {
+		{
+			size, err := m.Static.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.ErrorSpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorSpanCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.SpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsSummaryResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsSummaryResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsSummaryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Summaries) > 0 {
+		for iNdEx := len(m.Summaries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Summaries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceQLStatic) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceQLStatic) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceQLStatic) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Kind != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Kind))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Status != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.D != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.D))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.B {
+		i--
+		if m.B {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.S) > 0 {
+		i -= len(m.S)
+		copy(dAtA[i:], m.S)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.S)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.F != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.F))))
+		i--
+		dAtA[i] = 0x19
+	}
+	if m.N != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.N))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Type != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRangeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRangeRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRangeRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Step != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Step))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.End != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Start != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRangeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRangeResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRangeResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Series) > 0 {
+		for iNdEx := len(m.Series) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Series[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRangeSeries) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRangeSeries) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRangeSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Samples) > 0 {
+		for iNdEx := len(m.Samples) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Samples[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Labels[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRangeLabel) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRangeLabel) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRangeLabel) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRangeSample) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRangeSample) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRangeSample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Value != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i--
+		dAtA[i] = 0x11
+	}
+	if m.TimestampMs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TimestampMs))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintTempo(dAtA []byte, offset int, v uint64) int {
+	offset -= sovTempo(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *TraceByIDRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TraceID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.BlockStart)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.BlockEnd)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.QueryMode)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceByIDStreamRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TraceID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Start != 0 {
+		n += 1 + sovTempo(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovTempo(uint64(m.End))
+	}
+	l = len(m.BlockStart)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.BlockEnd)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.QueryMode)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceByIDResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Trace != nil {
+		l = m.Trace.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Metrics != nil {
+		l = m.Metrics.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Status != 0 {
+		n += 1 + sovTempo(uint64(m.Status))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceByIDMetrics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TotalJobs != 0 {
+		n += 1 + sovTempo(uint64(m.TotalJobs))
+	}
+	if m.FailedJobs != 0 {
+		n += 1 + sovTempo(uint64(m.FailedJobs))
+	}
+	return n
+}
+
+func (m *TracesByIDRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.TraceIDs) > 0 {
+		for _, s := range m.TraceIDs {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TracesByIDResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for k, v := range m.Traces {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovTempo(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovTempo(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovTempo(uint64(mapEntrySize))
+		}
+	}
+	if len(m.NotFound) > 0 {
+		for _, s := range m.NotFound {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TraceSummary) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.SpanCount))
+	}
+	if m.ErrorCount != 0 {
+		n += 1 + sovTempo(uint64(m.ErrorCount))
+	}
+	if m.StartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	}
+	if m.DurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanos))
+	}
+	if m.Depth != 0 {
+		n += 1 + sovTempo(uint64(m.Depth))
+	}
+	if m.RootSpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.RootSpanCount))
+	}
+	if m.RootSpan != nil {
+		l = m.RootSpan.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if len(m.Services) > 0 {
+		for _, e := range m.Services {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Gaps) > 0 {
+		for _, e := range m.Gaps {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.LargestSpans) > 0 {
+		for _, e := range m.LargestSpans {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.CycleSpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.CycleSpanCount))
+	}
+	return n
+}
+
+func (m *TraceSummarySpan) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SpanID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	}
+	if m.DurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanos))
+	}
+	l = len(m.Status)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceSummaryService) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.SpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.SpanCount))
+	}
+	if m.ErrorCount != 0 {
+		n += 1 + sovTempo(uint64(m.ErrorCount))
+	}
+	if len(m.Operations) > 0 {
+		for _, e := range m.Operations {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TraceSummaryOperation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.SpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.SpanCount))
+	}
+	if m.ErrorCount != 0 {
+		n += 1 + sovTempo(uint64(m.ErrorCount))
+	}
+	if m.TotalDurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.TotalDurationNanos))
+	}
+	return n
+}
+
+func (m *TraceSummaryGap) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SpanID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	}
+	if m.DurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanos))
+	}
+	return n
+}
+
+func (m *SearchRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Tags) > 0 {
+		for k, v := range m.Tags {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovTempo(uint64(len(k))) + 1 + len(v) + sovTempo(uint64(len(v)))
+			n += mapEntrySize + 1 + sovTempo(uint64(mapEntrySize))
+		}
+	}
+	if m.MinDurationMs != 0 {
+		n += 1 + sovTempo(uint64(m.MinDurationMs))
+	}
+	if m.MaxDurationMs != 0 {
+		n += 1 + sovTempo(uint64(m.MaxDurationMs))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovTempo(uint64(m.Limit))
+	}
+	if m.Start != 0 {
+		n += 1 + sovTempo(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovTempo(uint64(m.End))
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.SpansPerSpanSet != 0 {
+		n += 1 + sovTempo(uint64(m.SpansPerSpanSet))
+	}
+	if m.Paginated {
+		n += 2
+	}
+	if m.AfterStartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.AfterStartTimeUnixNano))
+	}
+	l = len(m.AfterTraceID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchBlockRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SearchReq != nil {
+		l = m.SearchReq.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.BlockID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartPage != 0 {
+		n += 1 + sovTempo(uint64(m.StartPage))
+	}
+	if m.PagesToSearch != 0 {
+		n += 1 + sovTempo(uint64(m.PagesToSearch))
+	}
+	l = len(m.Encoding)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.IndexPageSize != 0 {
+		n += 1 + sovTempo(uint64(m.IndexPageSize))
+	}
+	if m.TotalRecords != 0 {
+		n += 1 + sovTempo(uint64(m.TotalRecords))
+	}
+	l = len(m.DataEncoding)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Size_ != 0 {
+		n += 1 + sovTempo(uint64(m.Size_))
+	}
+	if m.FooterSize != 0 {
+		n += 1 + sovTempo(uint64(m.FooterSize))
+	}
+	if m.CompactionLevel != 0 {
+		n += 1 + sovTempo(uint64(m.CompactionLevel))
+	}
+	return n
+}
+
+func (m *SearchResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for _, e := range m.Traces {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Metrics != nil {
+		l = m.Metrics.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.NextPageToken)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchPageToken) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Now != 0 {
+		n += 1 + sovTempo(uint64(m.Now))
+	}
+	if m.SearchHash != 0 {
+		n += 1 + sovTempo(uint64(m.SearchHash))
+	}
+	if m.LastStartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.LastStartTimeUnixNano))
+	}
+	l = len(m.LastTraceID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceSearchMetadata) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TraceID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.RootServiceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.RootTraceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	}
+	if m.DurationMs != 0 {
+		n += 1 + sovTempo(uint64(m.DurationMs))
+	}
+	if m.SpanSet != nil {
+		l = m.SpanSet.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SpanSet) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Spans) > 0 {
+		for _, e := range m.Spans {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Matched != 0 {
+		n += 1 + sovTempo(uint64(m.Matched))
+	}
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Span) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SpanID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	}
+	if m.DurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanos))
+	}
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchMetrics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.InspectedTraces != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedTraces))
+	}
+	if m.InspectedBytes != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedBytes))
+	}
+	if m.TotalBlocks != 0 {
+		n += 1 + sovTempo(uint64(m.TotalBlocks))
+	}
+	if m.CompletedJobs != 0 {
+		n += 1 + sovTempo(uint64(m.CompletedJobs))
+	}
+	if m.TotalJobs != 0 {
+		n += 1 + sovTempo(uint64(m.TotalJobs))
+	}
+	if m.TotalBlockBytes != 0 {
+		n += 1 + sovTempo(uint64(m.TotalBlockBytes))
+	}
+	return n
+}
+
+func (m *SearchTagsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Scope)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchTagsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.TagNames) > 0 {
+		for _, s := range m.TagNames {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchTagsV2Response) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Scopes) > 0 {
+		for _, e := range m.Scopes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchTagsV2Scope) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if len(m.Tags) > 0 {
+		for _, s := range m.Tags {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchTagValuesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TagName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchTagValuesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.TagValues) > 0 {
+		for _, s := range m.TagValues {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TagValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchTagValuesV2Response) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.TagValues) > 0 {
+		for _, e := range m.TagValues {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TagValueCount) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Count != 0 {
+		n += 1 + sovTempo(uint64(m.Count))
+	}
+	return n
+}
+
+func (m *SearchTagValueStatsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, e := range m.Values {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Cardinality != 0 {
+		n += 1 + sovTempo(uint64(m.Cardinality))
+	}
+	l = len(m.Sketch)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *Trace) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Batches) > 0 {
+		for _, e := range m.Batches {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PushResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *PushBytesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for _, e := range m.Traces {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Ids) > 0 {
+		for _, e := range m.Ids {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.SearchData) > 0 {
+		for _, e := range m.SearchData {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PushSpansRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Batches) > 0 {
+		for _, e := range m.Batches {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TraceBytes) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for _, b := range m.Traces {
+			l = len(b)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LinkSlice) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Links) > 0 {
+		for _, e := range m.Links {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SpanMetricsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.GroupBy)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovTempo(uint64(m.Limit))
+	}
+	return n
+}
+
+func (m *SpanMetricsSummaryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.GroupBy)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovTempo(uint64(m.Limit))
+	}
+	return n
+}
+
+func (m *SpanMetricsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Estimated {
+		n += 2
+	}
+	if m.SpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.SpanCount))
+	}
+	if m.ErrorSpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.ErrorSpanCount))
+	}
+	if len(m.Metrics) > 0 {
+		for _, e := range m.Metrics {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *RawHistogram) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Bucket != 0 {
+		n += 1 + sovTempo(uint64(m.Bucket))
+	}
+	if m.Count != 0 {
+		n += 1 + sovTempo(uint64(m.Count))
+	}
+	return n
+}
+
+func (m *SpanMetrics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.LatencyHistogram) > 0 {
+		for _, e := range m.LatencyHistogram {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Static != nil {
+		l = m.Static.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Errors != 0 {
+		n += 1 + sovTempo(uint64(m.Errors))
+	}
+	return n
+}
+
+func (m *SpanMetricsSummary) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.SpanCount))
+	}
+	if m.ErrorSpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.ErrorSpanCount))
+	}
+	if m.Static != nil {
+		l = m.Static.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.P99 != 0 {
+		n += 1 + sovTempo(uint64(m.P99))
+	}
+	if m.P95 != 0 {
+		n += 1 + sovTempo(uint64(m.P95))
+	}
+	if m.P90 != 0 {
+		n += 1 + sovTempo(uint64(m.P90))
+	}
+	if m.P50 != 0 {
+		n += 1 + sovTempo(uint64(m.P50))
+	}
+	return n
+}
+
+func (m *SpanMetricsSummaryResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Summaries) > 0 {
+		for _, e := range m.Summaries {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TraceQLStatic) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovTempo(uint64(m.Type))
+	}
+	if m.N != 0 {
+		n += 1 + sovTempo(uint64(m.N))
+	}
+	if m.F != 0 {
+		n += 9
+	}
+	l = len(m.S)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.B {
+		n += 2
+	}
+	if m.D != 0 {
+		n += 1 + sovTempo(uint64(m.D))
+	}
+	if m.Status != 0 {
+		n += 1 + sovTempo(uint64(m.Status))
+	}
+	if m.Kind != 0 {
+		n += 1 + sovTempo(uint64(m.Kind))
+	}
+	return n
+}
+
+func (m *QueryRangeRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Start != 0 {
+		n += 1 + sovTempo(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovTempo(uint64(m.End))
+	}
+	if m.Step != 0 {
+		n += 1 + sovTempo(uint64(m.Step))
+	}
+	return n
+}
+
+func (m *QueryRangeResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Series) > 0 {
+		for _, e := range m.Series {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *QueryRangeSeries) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Samples) > 0 {
+		for _, e := range m.Samples {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *QueryRangeLabel) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryRangeSample) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TimestampMs != 0 {
+		n += 1 + sovTempo(uint64(m.TimestampMs))
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	return n
+}
+
+func sovTempo(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozTempo(x uint64) (n int) {
+	return sovTempo(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceByIDRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceByIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceID = append(m.TraceID[:0], dAtA[iNdEx:postIndex]...)
+			if m.TraceID == nil {
+				m.TraceID = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockStart", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockStart = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockEnd", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockEnd = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryMode", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QueryMode = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceByIDStreamRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceByIDStreamRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceByIDStreamRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceID = append(m.TraceID[:0], dAtA[iNdEx:postIndex]...)
+			if m.TraceID == nil {
+				m.TraceID = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockStart", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockStart = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockEnd", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockEnd = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryMode", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QueryMode = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceByIDResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceByIDResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Trace == nil {
+				m.Trace = &Trace{}
+			}
+			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metrics == nil {
+				m.Metrics = &TraceByIDMetrics{}
+			}
+			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= PartialStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceByIDMetrics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceByIDMetrics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceByIDMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalJobs", wireType)
+			}
+			m.TotalJobs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalJobs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailedJobs", wireType)
+			}
+			m.FailedJobs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FailedJobs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TracesByIDRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TracesByIDRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TracesByIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceIDs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceIDs = append(m.TraceIDs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TracesByIDResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TracesByIDResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TracesByIDResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Traces == nil {
+				m.Traces = make(map[string]*Trace)
+			}
+			var mapkey string
+			var mapvalue *Trace
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTempo
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTempo
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthTempo
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTempo
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthTempo
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &Trace{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipTempo(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Traces[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NotFound", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NotFound = append(m.NotFound, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceSummary) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceSummary: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceSummary: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+			}
+			m.SpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpanCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCount", wireType)
+			}
+			m.ErrorCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+			}
+			m.StartTimeUnixNano = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
+			}
+			m.DurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Depth", wireType)
+			}
+			m.Depth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Depth |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootSpanCount", wireType)
+			}
+			m.RootSpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RootSpanCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootSpan", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RootSpan == nil {
+				m.RootSpan = &TraceSummarySpan{}
+			}
+			if err := m.RootSpan.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Services", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Services = append(m.Services, &TraceSummaryService{})
+			if err := m.Services[len(m.Services)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Gaps", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Gaps = append(m.Gaps, &TraceSummaryGap{})
+			if err := m.Gaps[len(m.Gaps)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LargestSpans", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LargestSpans = append(m.LargestSpans, &TraceSummarySpan{})
+			if err := m.LargestSpans[len(m.LargestSpans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CycleSpanCount", wireType)
+			}
+			m.CycleSpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CycleSpanCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceSummarySpan) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceSummarySpan: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceSummarySpan: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+			}
+			m.StartTimeUnixNano = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
+			}
+			m.DurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Status = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceSummaryService) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceSummaryService: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceSummaryService: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+			}
+			m.SpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpanCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCount", wireType)
+			}
+			m.ErrorCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operations = append(m.Operations, &TraceSummaryOperation{})
+			if err := m.Operations[len(m.Operations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceSummaryOperation) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceSummaryOperation: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceSummaryOperation: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+			}
+			m.SpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpanCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCount", wireType)
+			}
+			m.ErrorCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalDurationNanos", wireType)
+			}
+			m.TotalDurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalDurationNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceSummaryGap) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceSummaryGap: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceSummaryGap: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+			}
+			m.StartTimeUnixNano = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
+			}
+			m.DurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Tags == nil {
+				m.Tags = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTempo
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTempo
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthTempo
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTempo
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthTempo
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipTempo(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Tags[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinDurationMs", wireType)
+			}
+			m.MinDurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinDurationMs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxDurationMs", wireType)
+			}
+			m.MaxDurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxDurationMs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpansPerSpanSet", wireType)
+			}
+			m.SpansPerSpanSet = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpansPerSpanSet |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Paginated", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Paginated = bool(v != 0)
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AfterStartTimeUnixNano", wireType)
+			}
+			m.AfterStartTimeUnixNano = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AfterStartTimeUnixNano |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AfterTraceID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AfterTraceID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchBlockRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchReq", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SearchReq == nil {
+				m.SearchReq = &SearchRequest{}
+			}
+			if err := m.SearchReq.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartPage", wireType)
+			}
+			m.StartPage = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartPage |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PagesToSearch", wireType)
+			}
+			m.PagesToSearch = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PagesToSearch |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Encoding = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexPageSize", wireType)
+			}
+			m.IndexPageSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IndexPageSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalRecords", wireType)
+			}
+			m.TotalRecords = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalRecords |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataEncoding", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DataEncoding = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+			}
+			m.Size_ = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Size_ |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FooterSize", wireType)
+			}
+			m.FooterSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FooterSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompactionLevel", wireType)
+			}
+			m.CompactionLevel = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CompactionLevel |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	return n
-}
 
-func sovTempo(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozTempo(x uint64) (n int) {
-	return sovTempo(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
+func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4989,17 +10371,17 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceByIDRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceByIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5009,31 +10391,31 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TraceID = append(m.TraceID[:0], dAtA[iNdEx:postIndex]...)
-			if m.TraceID == nil {
-				m.TraceID = []byte{}
+			m.Traces = append(m.Traces, &TraceSearchMetadata{})
+			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlockStart", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5043,27 +10425,31 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.BlockStart = string(dAtA[iNdEx:postIndex])
+			if m.Metrics == nil {
+				m.Metrics = &SearchMetrics{}
+			}
+			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlockEnd", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NextPageToken", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5091,11 +10477,118 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.BlockEnd = string(dAtA[iNdEx:postIndex])
+			m.NextPageToken = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchPageToken) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchPageToken: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchPageToken: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Now", wireType)
+			}
+			m.Now = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Now |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchHash", wireType)
+			}
+			m.SearchHash = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SearchHash |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastStartTimeUnixNano", wireType)
+			}
+			m.LastStartTimeUnixNano = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastStartTimeUnixNano |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QueryMode", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LastTraceID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5123,7 +10616,7 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.QueryMode = string(dAtA[iNdEx:postIndex])
+			m.LastTraceID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5146,7 +10639,7 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
+func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5169,17 +10662,113 @@ func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceByIDResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceSearchMetadata: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceByIDResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceSearchMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RootServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootTraceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-			var msglen int
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RootTraceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+			}
+			m.StartTimeUnixNano = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5189,31 +10778,33 @@ func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Trace == nil {
-				m.Trace = &Trace{}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
 			}
-			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.DurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationMs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 2:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanSet", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5240,10 +10831,10 @@ func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Metrics == nil {
-				m.Metrics = &TraceByIDMetrics{}
+			if m.SpanSet == nil {
+				m.SpanSet = &SpanSet{}
 			}
-			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.SpanSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -5268,57 +10859,7 @@ func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceByIDMetrics) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: TraceByIDMetrics: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceByIDMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SearchRequest) Unmarshal(dAtA []byte) error {
+func (m *SpanSet) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5331,173 +10872,27 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SearchRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Tags == nil {
-				m.Tags = make(map[string]string)
-			}
-			var mapkey string
-			var mapvalue string
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowTempo
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowTempo
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthTempo
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthTempo
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var stringLenmapvalue uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowTempo
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapvalue |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapvalue := int(stringLenmapvalue)
-					if intStringLenmapvalue < 0 {
-						return ErrInvalidLengthTempo
-					}
-					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
-					if postStringIndexmapvalue < 0 {
-						return ErrInvalidLengthTempo
-					}
-					if postStringIndexmapvalue > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
-					iNdEx = postStringIndexmapvalue
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipTempo(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if (skippy < 0) || (iNdEx+skippy) < 0 {
-						return ErrInvalidLengthTempo
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
-				}
-			}
-			m.Tags[mapkey] = mapvalue
-			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MinDurationMs", wireType)
-			}
-			m.MinDurationMs = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.MinDurationMs |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MaxDurationMs", wireType)
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanSet: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanSet: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
 			}
-			m.MaxDurationMs = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5507,54 +10902,31 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.MaxDurationMs |= uint32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.Limit = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Limit |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.Start = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Start |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			m.Spans = append(m.Spans, &Span{})
+			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		case 6:
+			iNdEx = postIndex
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Matched", wireType)
 			}
-			m.End = 0
+			m.Matched = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5564,16 +10936,16 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.End |= uint32(b&0x7F) << shift
+				m.Matched |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5583,43 +10955,26 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Query = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 9:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpansPerSpanSet", wireType)
-			}
-			m.SpansPerSpanSet = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.SpansPerSpanSet |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			m.Attributes = append(m.Attributes, &v1.KeyValue{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -5641,7 +10996,7 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
+func (m *Span) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5664,17 +11019,17 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchBlockRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: Span: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Span: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SearchReq", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5684,31 +11039,27 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.SearchReq == nil {
-				m.SearchReq = &SearchRequest{}
-			}
-			if err := m.SearchReq.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.SpanID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5736,13 +11087,13 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.BlockID = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartPage", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
 			}
-			m.StartPage = 0
+			m.StartTimeUnixNano = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5752,16 +11103,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StartPage |= uint32(b&0x7F) << shift
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PagesToSearch", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
 			}
-			m.PagesToSearch = 0
+			m.DurationNanos = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5771,16 +11122,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.PagesToSearch |= uint32(b&0x7F) << shift
+				m.DurationNanos |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5790,29 +11141,81 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Encoding = string(dAtA[iNdEx:postIndex])
+			m.Attributes = append(m.Attributes, &v1.KeyValue{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 6:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchMetrics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IndexPageSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedTraces", wireType)
 			}
-			m.IndexPageSize = 0
+			m.InspectedTraces = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5822,16 +11225,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.IndexPageSize |= uint32(b&0x7F) << shift
+				m.InspectedTraces |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalRecords", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedBytes", wireType)
 			}
-			m.TotalRecords = 0
+			m.InspectedBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5841,16 +11244,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TotalRecords |= uint32(b&0x7F) << shift
+				m.InspectedBytes |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DataEncoding", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlocks", wireType)
 			}
-			var stringLen uint64
+			m.TotalBlocks = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5860,29 +11263,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.TotalBlocks |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.DataEncoding = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompletedJobs", wireType)
 			}
-			var stringLen uint64
+			m.CompletedJobs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5892,29 +11282,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.CompletedJobs |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Version = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 10:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalJobs", wireType)
 			}
-			m.Size_ = 0
+			m.TotalJobs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5924,16 +11301,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Size_ |= uint64(b&0x7F) << shift
+				m.TotalJobs |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 11:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FooterSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlockBytes", wireType)
 			}
-			m.FooterSize = 0
+			m.TotalBlockBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5943,7 +11320,7 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.FooterSize |= uint32(b&0x7F) << shift
+				m.TotalBlockBytes |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -5969,7 +11346,7 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchResponse) Unmarshal(dAtA []byte) error {
+func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5992,51 +11369,17 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Traces = append(m.Traces, &TraceSearchMetadata{})
-			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6046,27 +11389,23 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Metrics == nil {
-				m.Metrics = &SearchMetrics{}
-			}
-			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Scope = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -6089,7 +11428,7 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
+func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6112,151 +11451,17 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceSearchMetadata: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceSearchMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.TraceID = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RootServiceName", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.RootServiceName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RootTraceName", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.RootTraceName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
-			}
-			m.StartTimeUnixNano = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
-			}
-			m.DurationMs = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.DurationMs |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 6:
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanSet", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TagNames", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6266,27 +11471,23 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.SpanSet == nil {
-				m.SpanSet = &SpanSet{}
-			}
-			if err := m.SpanSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.TagNames = append(m.TagNames, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -6309,7 +11510,7 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanSet) Unmarshal(dAtA []byte) error {
+func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6332,68 +11533,15 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanSet: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagsV2Response: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanSet: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagsV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Spans = append(m.Spans, &Span{})
-			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Matched", wireType)
-			}
-			m.Matched = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Matched |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Scopes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6420,8 +11568,8 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Attributes = append(m.Attributes, &v1.KeyValue{})
-			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Scopes = append(m.Scopes, &SearchTagsV2Scope{})
+			if err := m.Scopes[len(m.Scopes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6446,7 +11594,7 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Span) Unmarshal(dAtA []byte) error {
+func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6469,15 +11617,15 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Span: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagsV2Scope: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Span: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagsV2Scope: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6505,11 +11653,11 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.SpanID = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6537,79 +11685,7 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
-			}
-			m.StartTimeUnixNano = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
-			}
-			m.DurationNanos = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.DurationNanos |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Attributes = append(m.Attributes, &v1.KeyValue{})
-			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Tags = append(m.Tags, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -6632,7 +11708,7 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
+func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6655,93 +11731,17 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchMetrics: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagValuesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagValuesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InspectedTraces", wireType)
-			}
-			m.InspectedTraces = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.InspectedTraces |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InspectedBytes", wireType)
-			}
-			m.InspectedBytes = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.InspectedBytes |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlocks", wireType)
-			}
-			m.TotalBlocks = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TotalBlocks |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CompletedJobs", wireType)
-			}
-			m.CompletedJobs = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.CompletedJobs |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalJobs", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagName", wireType)
 			}
-			m.TotalJobs = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6751,16 +11751,29 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TotalJobs |= uint32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlockBytes", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.TotalBlockBytes = 0
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6770,11 +11783,24 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TotalBlockBytes |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -6796,7 +11822,7 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
+func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6819,15 +11845,15 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagValuesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagValuesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6855,7 +11881,7 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Scope = string(dAtA[iNdEx:postIndex])
+			m.TagValues = append(m.TagValues, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -6878,7 +11904,7 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
+func (m *TagValue) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6901,15 +11927,15 @@ func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TagValue: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TagValue: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagNames", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6937,7 +11963,39 @@ func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagNames = append(m.TagNames, string(dAtA[iNdEx:postIndex]))
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -6960,7 +12018,7 @@ func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
+func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6983,15 +12041,15 @@ func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsV2Response: wiretype end group for non-group")
+			return fmt.Errorf("proto: SearchTagValuesV2Response: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SearchTagValuesV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Scopes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7018,8 +12076,8 @@ func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Scopes = append(m.Scopes, &SearchTagsV2Scope{})
-			if err := m.Scopes[len(m.Scopes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.TagValues = append(m.TagValues, &TagValue{})
+			if err := m.TagValues[len(m.TagValues)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -7044,7 +12102,7 @@ func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
+func (m *TagValueCount) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7067,15 +12125,15 @@ func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsV2Scope: wiretype end group for non-group")
+			return fmt.Errorf("proto: TagValueCount: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsV2Scope: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TagValueCount: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -7103,11 +12161,11 @@ func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.Type = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -7124,18 +12182,174 @@ func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchTagValueStatsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTagValueStatsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTagValueStatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, &TagValueCount{})
+			if err := m.Values[len(m.Values)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cardinality", wireType)
+			}
+			m.Cardinality = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Cardinality |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sketch", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Tags = append(m.Tags, string(dAtA[iNdEx:postIndex]))
+			m.Sketch = append(m.Sketch[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sketch == nil {
+				m.Sketch = []byte{}
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -7158,7 +12372,7 @@ func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
+func (m *Trace) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7181,17 +12395,17 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: Trace: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Trace: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7201,55 +12415,25 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.Batches = append(m.Batches, &v11.ResourceSpans{})
+			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			m.Query = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -7272,7 +12456,7 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
+func (m *PushResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7295,44 +12479,12 @@ func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: PushResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PushResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.TagValues = append(m.TagValues, string(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -7354,7 +12506,7 @@ func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TagValue) Unmarshal(dAtA []byte) error {
+func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7377,17 +12529,17 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TagValue: wiretype end group for non-group")
+			return fmt.Errorf("proto: PushBytesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TagValue: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PushBytesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7397,29 +12549,32 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Type = string(dAtA[iNdEx:postIndex])
+			var v PreallocBytes
+			m.Traces = append(m.Traces, v)
+			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Ids", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7429,23 +12584,61 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Value = string(dAtA[iNdEx:postIndex])
+			var v PreallocBytes
+			m.Ids = append(m.Ids, v)
+			if err := m.Ids[len(m.Ids)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchData", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v PreallocBytes
+			m.SearchData = append(m.SearchData, v)
+			if err := m.SearchData[len(m.SearchData)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -7468,7 +12661,7 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
+func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7491,15 +12684,15 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesV2Response: wiretype end group for non-group")
+			return fmt.Errorf("proto: PushSpansRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PushSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7526,8 +12719,8 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagValues = append(m.TagValues, &TagValue{})
-			if err := m.TagValues[len(m.TagValues)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Batches = append(m.Batches, &v11.ResourceSpans{})
+			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -7552,7 +12745,7 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Trace) Unmarshal(dAtA []byte) error {
+func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7575,17 +12768,17 @@ func (m *Trace) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Trace: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceBytes: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Trace: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceBytes: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7595,25 +12788,23 @@ func (m *Trace) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Batches = append(m.Batches, &v11.ResourceSpans{})
-			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Traces = append(m.Traces, make([]byte, postIndex-iNdEx))
+			copy(m.Traces[len(m.Traces)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -7636,7 +12827,7 @@ func (m *Trace) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PushResponse) Unmarshal(dAtA []byte) error {
+func (m *LinkSlice) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7655,16 +12846,50 @@ func (m *PushResponse) Unmarshal(dAtA []byte) error {
 			if b < 0x80 {
 				break
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: PushResponse: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PushResponse: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LinkSlice: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LinkSlice: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Links", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Links = append(m.Links, &v11.Span_Link{})
+			if err := m.Links[len(m.Links)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -7686,7 +12911,7 @@ func (m *PushResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
+func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7709,17 +12934,17 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PushBytesRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanMetricsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PushBytesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanMetricsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 2:
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7729,32 +12954,29 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			var v PreallocBytes
-			m.Traces = append(m.Traces, v)
-			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Query = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Ids", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7764,32 +12986,29 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			var v PreallocBytes
-			m.Ids = append(m.Ids, v)
-			if err := m.Ids[len(m.Ids)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.GroupBy = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SearchData", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
 			}
-			var byteLen int
+			m.Limit = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7799,27 +13018,11 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				m.Limit |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			var v PreallocBytes
-			m.SearchData = append(m.SearchData, v)
-			if err := m.SearchData[len(m.SearchData)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -7841,7 +13044,7 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
+func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7864,17 +13067,17 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PushSpansRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanMetricsSummaryRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PushSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanMetricsSummaryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7884,26 +13087,75 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Batches = append(m.Batches, &v11.ResourceSpans{})
-			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.GroupBy = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -7925,7 +13177,7 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceBytes) Unmarshal(dAtA []byte) error {
+func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7948,17 +13200,75 @@ func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceBytes: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanMetricsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceBytes: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanMetricsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Estimated", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Estimated = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+			}
+			m.SpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpanCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorSpanCount", wireType)
+			}
+			m.ErrorSpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorSpanCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7968,23 +13278,25 @@ func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Traces = append(m.Traces, make([]byte, postIndex-iNdEx))
-			copy(m.Traces[len(m.Traces)-1], dAtA[iNdEx:postIndex])
+			m.Metrics = append(m.Metrics, &SpanMetrics{})
+			if err := m.Metrics[len(m.Metrics)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -8007,7 +13319,7 @@ func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LinkSlice) Unmarshal(dAtA []byte) error {
+func (m *RawHistogram) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8030,17 +13342,36 @@ func (m *LinkSlice) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LinkSlice: wiretype end group for non-group")
+			return fmt.Errorf("proto: RawHistogram: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LinkSlice: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RawHistogram: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Links", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+			}
+			m.Bucket = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bucket |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			var msglen int
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8050,26 +13381,11 @@ func (m *LinkSlice) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Count |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Links = append(m.Links, &v11.Span_Link{})
-			if err := m.Links[len(m.Links)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -8091,7 +13407,7 @@ func (m *LinkSlice) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
+func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8114,17 +13430,17 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanMetrics: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencyHistogram", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8134,29 +13450,31 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Query = string(dAtA[iNdEx:postIndex])
+			m.LatencyHistogram = append(m.LatencyHistogram, &RawHistogram{})
+			if err := m.LatencyHistogram[len(m.LatencyHistogram)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Static", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8166,29 +13484,33 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GroupBy = string(dAtA[iNdEx:postIndex])
+			if m.Static == nil {
+				m.Static = &TraceQLStatic{}
+			}
+			if err := m.Static.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
 			}
-			m.Limit = 0
+			m.Errors = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8198,7 +13520,7 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Limit |= uint64(b&0x7F) << shift
+				m.Errors |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -8224,7 +13546,7 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
+func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8247,17 +13569,17 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsSummaryRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanMetricsSummary: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsSummaryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanMetricsSummary: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
 			}
-			var stringLen uint64
+			m.SpanCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8267,29 +13589,35 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.SpanCount |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorSpanCount", wireType)
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.ErrorSpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorSpanCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.Query = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Static", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8299,29 +13627,33 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GroupBy = string(dAtA[iNdEx:postIndex])
+			if m.Static == nil {
+				m.Static = &TraceQLStatic{}
+			}
+			if err := m.Static.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 3:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field P99", wireType)
 			}
-			m.Limit = 0
+			m.P99 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8331,66 +13663,16 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Limit |= uint64(b&0x7F) << shift
+				m.P99 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsResponse: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Estimated", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field P95", wireType)
 			}
-			var v int
+			m.P95 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8400,17 +13682,16 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				m.P95 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Estimated = bool(v != 0)
-		case 2:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field P90", wireType)
 			}
-			m.SpanCount = 0
+			m.P90 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8420,16 +13701,16 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SpanCount |= uint64(b&0x7F) << shift
+				m.P90 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+		case 7:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ErrorSpanCount", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field P50", wireType)
 			}
-			m.ErrorSpanCount = 0
+			m.P50 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8439,14 +13720,64 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ErrorSpanCount |= uint64(b&0x7F) << shift
+				m.P50 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanMetricsSummaryResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanMetricsSummaryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Summaries", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8473,8 +13804,8 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Metrics = append(m.Metrics, &SpanMetrics{})
-			if err := m.Metrics[len(m.Metrics)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Summaries = append(m.Summaries, &SpanMetricsSummary{})
+			if err := m.Summaries[len(m.Summaries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -8499,7 +13830,7 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RawHistogram) Unmarshal(dAtA []byte) error {
+func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8522,17 +13853,17 @@ func (m *RawHistogram) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RawHistogram: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceQLStatic: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RawHistogram: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceQLStatic: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			m.Bucket = 0
+			m.Type = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8542,16 +13873,16 @@ func (m *RawHistogram) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Bucket |= uint64(b&0x7F) << shift
+				m.Type |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field N", wireType)
 			}
-			m.Count = 0
+			m.N = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8561,66 +13892,27 @@ func (m *RawHistogram) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Count |= uint64(b&0x7F) << shift
+				m.N |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field F", wireType)
 			}
-			if iNdEx >= l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetrics: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.F = float64(math.Float64frombits(v))
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LatencyHistogram", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field S", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8630,31 +13922,29 @@ func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.LatencyHistogram = append(m.LatencyHistogram, &RawHistogram{})
-			if err := m.LatencyHistogram[len(m.LatencyHistogram)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.S = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Static", wireType)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field B", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8664,33 +13954,55 @@ func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
+			m.B = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field D", wireType)
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.D = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.D |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if m.Static == nil {
-				m.Static = &TraceQLStatic{}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
 			}
-			if err := m.Static.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 3:
+		case 8:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
 			}
-			m.Errors = 0
+			m.Kind = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8700,7 +14012,7 @@ func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Errors |= uint64(b&0x7F) << shift
+				m.Kind |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -8726,7 +14038,7 @@ func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
+func (m *QueryRangeRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8749,91 +14061,17 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsSummary: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryRangeRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsSummary: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryRangeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
-			}
-			m.SpanCount = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.SpanCount |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ErrorSpanCount", wireType)
-			}
-			m.ErrorSpanCount = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.ErrorSpanCount |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Static", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Static == nil {
-				m.Static = &TraceQLStatic{}
-			}
-			if err := m.Static.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field P99", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
-			m.P99 = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8843,16 +14081,29 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.P99 |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field P95", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
 			}
-			m.P95 = 0
+			m.Start = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8862,16 +14113,16 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.P95 |= uint64(b&0x7F) << shift
+				m.Start |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field P90", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
 			}
-			m.P90 = 0
+			m.End = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8881,16 +14132,16 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.P90 |= uint64(b&0x7F) << shift
+				m.End |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field P50", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Step", wireType)
 			}
-			m.P50 = 0
+			m.Step = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8900,7 +14151,7 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.P50 |= uint64(b&0x7F) << shift
+				m.Step |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -8926,7 +14177,7 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
+func (m *QueryRangeResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8949,15 +14200,15 @@ func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsSummaryResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryRangeResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsSummaryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryRangeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Summaries", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Series", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8984,8 +14235,8 @@ func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Summaries = append(m.Summaries, &SpanMetricsSummary{})
-			if err := m.Summaries[len(m.Summaries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Series = append(m.Series, &QueryRangeSeries{})
+			if err := m.Series[len(m.Series)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -9010,7 +14261,7 @@ func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
+func (m *QueryRangeSeries) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -9033,17 +14284,17 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceQLStatic: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryRangeSeries: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceQLStatic: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryRangeSeries: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
 			}
-			m.Type = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9053,16 +14304,31 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= int32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, &QueryRangeLabel{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field N", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
 			}
-			m.N = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9072,25 +14338,79 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.N |= int64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field F", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			var v uint64
-			if (iNdEx + 8) > l {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.F = float64(math.Float64frombits(v))
-		case 4:
+			m.Samples = append(m.Samples, &QueryRangeSample{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryRangeLabel) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryRangeLabel: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryRangeLabel: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field S", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -9118,13 +14438,13 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.S = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field B", wireType)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9134,55 +14454,79 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.B = bool(v != 0)
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field D", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.D = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.D |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.Status = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Status |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-		case 8:
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryRangeSample) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryRangeSample: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryRangeSample: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
 			}
-			m.Kind = 0
+			m.TimestampMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9192,11 +14536,22 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Kind |= int32(b&0x7F) << shift
+				m.TimestampMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
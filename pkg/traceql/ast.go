@@ -3,6 +3,8 @@ package traceql
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -23,6 +25,16 @@ type typedExpression interface {
 
 type RootExpr struct {
 	Pipeline Pipeline
+	// MetricsPipeline is set for metrics queries that turn the spans matched by
+	// the pipeline into time series, e.g. { } | rate() by (resource.service.name)
+	MetricsPipeline *MetricsAggregate
+	// OrderBy and Limit are set by the trailing stages of a search, e.g.
+	// { } | order by duration desc | limit 20. They apply to the traces found by
+	// the whole search, not to the spans of a single trace.
+	OrderBy *OrderBy
+	Limit   int
+	// Hints tune how the query is executed, e.g. { } with (most_recent=true)
+	Hints *Hints
 }
 
 func newRootExpr(e pipelineElement) *RootExpr {
@@ -36,6 +48,194 @@ func newRootExpr(e pipelineElement) *RootExpr {
 	}
 }
 
+func newRootExprWithMetrics(e pipelineElement, m MetricsAggregate) *RootExpr {
+	r := newRootExpr(e)
+	r.MetricsPipeline = &m
+	return r
+}
+
+// SearchOrder returns the order of the results of a search or nil if the first results found can be
+// returned. Most recent and exhaustive searches have to look at every trace, they keep the most recent
+// traces which is the order of unordered results.
+func (r *RootExpr) SearchOrder() *OrderBy {
+	if r == nil {
+		return nil
+	}
+	if r.OrderBy != nil {
+		return r.OrderBy
+	}
+
+	mostRecent, _ := r.Hints.GetBool(HintMostRecent)
+	exhaustive, _ := r.Hints.GetBool(HintExhaustive)
+	if mostRecent || exhaustive {
+		return &OrderBy{Key: OrderKeyStartTime, Desc: true}
+	}
+
+	return nil
+}
+
+// SearchHints returns the hints of a search or nil if it has none.
+func (r *RootExpr) SearchHints() *Hints {
+	if r == nil {
+		return nil
+	}
+	return r.Hints
+}
+
+func newRootExprWithOrder(e pipelineElement, o *OrderBy, limit int) *RootExpr {
+	r := newRootExpr(e)
+	r.OrderBy = o
+	r.Limit = limit
+	return r
+}
+
+// **********************
+// Ordering
+// **********************
+
+// OrderBy orders the traces returned by a search. Without it the most recent traces come first.
+type OrderBy struct {
+	Key  OrderKey
+	Desc bool
+}
+
+func newOrderBy(key OrderKey, desc bool) OrderBy {
+	return OrderBy{
+		Key:  key,
+		Desc: desc,
+	}
+}
+
+// **********************
+// Hints
+// **********************
+
+const (
+	// HintMostRecent searches all data and returns the most recent traces
+	HintMostRecent = "most_recent"
+	// HintExhaustive searches all data instead of stopping at the first results
+	HintExhaustive = "exhaustive"
+	// HintSample only searches the given fraction of traces
+	HintSample = "sample"
+	// HintSpss sets the number of spans returned per spanset
+	HintSpss = "spss"
+	// HintJobSize sets the target number of bytes of the backend jobs of a search
+	HintJobSize = "job_size"
+	// HintIngesters controls if recent data in the ingesters is searched
+	HintIngesters = "ingesters"
+)
+
+type Hint struct {
+	Name  string
+	Value Static
+}
+
+func newHint(name string, value Static) *Hint {
+	return &Hint{
+		Name:  name,
+		Value: value,
+	}
+}
+
+// Hints are set with a trailing with(...) clause. They tune how much data is searched and
+// returned without changing the query itself.
+type Hints struct {
+	Hints []*Hint
+}
+
+func newHints(h []*Hint) *Hints {
+	return &Hints{
+		Hints: h,
+	}
+}
+
+// GetBool returns the value of a boolean hint. The last value wins if a hint is set more than once.
+func (h *Hints) GetBool(name string) (bool, bool) {
+	v, ok := h.get(name, TypeBoolean)
+	return v.B, ok
+}
+
+// GetInt returns the value of an integer hint.
+func (h *Hints) GetInt(name string) (int, bool) {
+	v, ok := h.get(name, TypeInt)
+	return v.N, ok
+}
+
+// GetFloat returns the value of a numeric hint as a float.
+func (h *Hints) GetFloat(name string) (float64, bool) {
+	// integers like sample=1 are valid floats
+	v, ok := h.get(name, TypeFloat, TypeInt)
+	if !ok {
+		return 0, false
+	}
+	return v.asFloat(), true
+}
+
+func (h *Hints) get(name string, types ...StaticType) (Static, bool) {
+	if h == nil {
+		return Static{}, false
+	}
+
+	for i := len(h.Hints) - 1; i >= 0; i-- {
+		if h.Hints[i].Name != name {
+			continue
+		}
+		for _, t := range types {
+			if h.Hints[i].Value.Type == t {
+				return h.Hints[i].Value, true
+			}
+		}
+	}
+	return Static{}, false
+}
+
+// **********************
+// Metrics
+// **********************
+
+// MetricsAggregate is the last stage of a metrics query. It is not evaluated by the
+// engine, instead the metrics are computed from the spans returned by the pipeline.
+type MetricsAggregate struct {
+	Op       MetricsAggregateOp
+	By       []Attribute
+	Attr     Attribute // only used by quantile_over_time
+	Quantile float64   // only used by quantile_over_time
+}
+
+func newMetricsAggregate(op MetricsAggregateOp, by []Attribute) MetricsAggregate {
+	return MetricsAggregate{
+		Op: op,
+		By: by,
+	}
+}
+
+func newQuantileOverTime(attr Attribute, q float64, by []Attribute) MetricsAggregate {
+	return MetricsAggregate{
+		Op:       MetricsAggregateQuantileOverTime,
+		By:       by,
+		Attr:     attr,
+		Quantile: q,
+	}
+}
+
+// extractConditions adds the fields needed to compute the metrics to the second pass. They
+// don't change which spans match.
+func (m MetricsAggregate) extractConditions(request *FetchSpansRequest) {
+	for _, a := range m.By {
+		request.SecondPassConditions = append(request.SecondPassConditions, Condition{
+			Attribute: a,
+			Op:        OpNone,
+		})
+	}
+
+	if m.Op == MetricsAggregateQuantileOverTime {
+		request.SecondPassConditions = append(request.SecondPassConditions, Condition{
+			Attribute: m.Attr,
+			Op:        OpNone,
+		})
+	}
+}
+
 // **********************
 // Pipeline
 // **********************
@@ -76,11 +276,17 @@ func (p Pipeline) impliedType() StaticType {
 }
 
 func (p Pipeline) extractConditions(req *FetchSpansRequest) {
+	filtering := 0
 	for _, element := range p.Elements {
 		element.extractConditions(req)
+
+		// select only fetches additional data and doesn't change which spans match
+		if _, ok := element.(SelectOperation); !ok {
+			filtering++
+		}
 	}
 	// TODO this needs to be fine-tuned a bit, e.g. { .foo = "bar" } | by(.namespace), AllConditions can still be true
-	if len(p.Elements) > 1 {
+	if filtering > 1 {
 		req.AllConditions = false
 	}
 }
@@ -134,6 +340,31 @@ func (CoalesceOperation) evaluate(ss []*Spanset) ([]*Spanset, error) {
 	return ss, nil
 }
 
+// SelectOperation requests additional attributes to be returned with the matching spans.
+// They are fetched in the second pass so they don't affect which spans match.
+type SelectOperation struct {
+	attrs []Attribute
+}
+
+func newSelectOperation(attrs []Attribute) SelectOperation {
+	return SelectOperation{
+		attrs: attrs,
+	}
+}
+
+func (o SelectOperation) extractConditions(request *FetchSpansRequest) {
+	for _, a := range o.attrs {
+		request.SecondPassConditions = append(request.SecondPassConditions, Condition{
+			Attribute: a,
+			Op:        OpNone,
+		})
+	}
+}
+
+func (SelectOperation) evaluate(ss []*Spanset) ([]*Spanset, error) {
+	return ss, nil
+}
+
 // **********************
 // Scalars
 // **********************
@@ -187,6 +418,7 @@ func (o ScalarOperation) extractConditions(request *FetchSpansRequest) {
 type Aggregate struct {
 	op AggregateOp
 	e  FieldExpression
+	q  float64 // only used by quantile
 }
 
 func newAggregate(agg AggregateOp, e FieldExpression) Aggregate {
@@ -196,11 +428,19 @@ func newAggregate(agg AggregateOp, e FieldExpression) Aggregate {
 	}
 }
 
+func newQuantileAggregate(e FieldExpression, q float64) Aggregate {
+	return Aggregate{
+		op: aggregateQuantile,
+		e:  e,
+		q:  q,
+	}
+}
+
 // nolint: revive
 func (Aggregate) __scalarExpression() {}
 
 func (a Aggregate) impliedType() StaticType {
-	if a.op == aggregateCount || a.e == nil {
+	if a.op == aggregateCount || a.op == aggregateCountDistinct || a.e == nil {
 		return TypeInt
 	}
 
@@ -228,8 +468,39 @@ type SpansetOperation struct {
 }
 
 func (o SpansetOperation) extractConditions(request *FetchSpansRequest) {
+	before := len(request.Conditions)
 	o.LHS.extractConditions(request)
+	lhs := len(request.Conditions) - before
 	o.RHS.extractConditions(request)
+	rhs := len(request.Conditions) - before - lhs
+
+	// structural operators need the storage layer to return the relationships between spans
+	structural := IntrinsicNone
+	switch o.Op {
+	case OpSpansetDescendant, OpSpansetAncestor, OpSpansetNotDescendant:
+		structural = IntrinsicStructuralDescendant
+	case OpSpansetChild, OpSpansetParent, OpSpansetNotChild:
+		structural = IntrinsicStructuralChild
+	case OpSpansetSibling, OpSpansetNotSibling:
+		structural = IntrinsicStructuralSibling
+	}
+
+	if structural != IntrinsicNone {
+		request.appendCondition(Condition{
+			Attribute: NewIntrinsic(structural),
+			Op:        OpNone,
+		})
+
+		// a side without conditions, i.e. { }, can be any span of the trace. the storage layer only
+		// returns spans that match one of the conditions so ask for a field every span has
+		if lhs == 0 || rhs == 0 {
+			request.appendCondition(Condition{
+				Attribute: NewIntrinsic(IntrinsicDuration),
+				Op:        OpNone,
+			})
+		}
+	}
+
 	request.AllConditions = false
 }
 
@@ -369,6 +640,47 @@ func (o BinaryOperation) referencesSpan() bool {
 	return o.LHS.referencesSpan() || o.RHS.referencesSpan()
 }
 
+// existenceCheck returns the attribute compared to nil in operations like { .foo = nil } or
+// { nil != .foo }. The second return value is false if the operation isn't an existence check.
+func (o BinaryOperation) existenceCheck() (FieldExpression, bool) {
+	if o.Op != OpEqual && o.Op != OpNotEqual {
+		return nil, false
+	}
+
+	if s, ok := o.RHS.(Static); ok && s.Type == TypeNil {
+		return o.LHS, true
+	}
+	if s, ok := o.LHS.(Static); ok && s.Type == TypeNil {
+		return o.RHS, true
+	}
+
+	return nil, false
+}
+
+type IndexOperation struct {
+	Expression FieldExpression
+	Index      int
+}
+
+func newIndexOperation(e FieldExpression, index int) IndexOperation {
+	return IndexOperation{
+		Expression: e,
+		Index:      index,
+	}
+}
+
+// nolint: revive
+func (IndexOperation) __fieldExpression() {}
+
+func (o IndexOperation) impliedType() StaticType {
+	// the type of the element isn't known until the array is fetched
+	return TypeAttribute
+}
+
+func (o IndexOperation) referencesSpan() bool {
+	return o.Expression.referencesSpan()
+}
+
 type UnaryOperation struct {
 	Op         Operator
 	Expression FieldExpression
@@ -385,6 +697,11 @@ func newUnaryOperation(op Operator, e FieldExpression) UnaryOperation {
 func (UnaryOperation) __fieldExpression() {}
 
 func (o UnaryOperation) impliedType() StaticType {
+	// lower() and upper() always return strings
+	if o.Op == OpLower || o.Op == OpUpper {
+		return TypeString
+	}
+
 	// both operators (opPower and opNot) will just be based on the operand type
 	return o.Expression.impliedType()
 }
@@ -405,6 +722,7 @@ type Static struct {
 	D      time.Duration
 	Status Status // todo: can we just use the N member for status and kind?
 	Kind   Kind
+	Arr    *[]Static // elements of TypeArray. held by pointer so Static remains comparable, use MapKey() to key maps by value
 }
 
 // nolint: revive
@@ -439,10 +757,97 @@ func (s Static) Equals(other Static) bool {
 		return Status(s.N) == other.Status
 	}
 
+	if s.Type == TypeArray || other.Type == TypeArray {
+		return s.arrayEquals(other)
+	}
+
 	// no special cases, just compare directly
 	return s == other
 }
 
+func (s Static) arrayEquals(other Static) bool {
+	if s.Type != other.Type {
+		return false
+	}
+
+	lhs, rhs := s.Elements(), other.Elements()
+	if len(lhs) != len(rhs) {
+		return false
+	}
+	for i := range lhs {
+		if !lhs[i].Equals(rhs[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// StaticMapKey is a comparable form of a Static for use as a map key. A Static can't be used as a key
+// directly because arrays would be keyed by the address of their elements instead of their contents.
+type StaticMapKey struct {
+	static Static // the value itself for all types except arrays. Arr is always nil
+	arr    string // the typed encoding of the elements of an array
+}
+
+// MapKey returns a key that is equal for all statics that are equal by type and value. Arrays are
+// keyed by the type and value of each element and never collide with a non-array value.
+func (s Static) MapKey() StaticMapKey {
+	if s.Type != TypeArray {
+		return StaticMapKey{static: s}
+	}
+
+	sb := strings.Builder{}
+	s.writeArrayKey(&sb)
+	return StaticMapKey{static: Static{Type: TypeArray}, arr: sb.String()}
+}
+
+func (s Static) writeArrayKey(sb *strings.Builder) {
+	for _, e := range s.Elements() {
+		sb.WriteString(strconv.Itoa(int(e.Type)))
+		if e.Type == TypeArray {
+			sb.WriteByte('[')
+			e.writeArrayKey(sb)
+			sb.WriteByte(']')
+			continue
+		}
+		sb.WriteByte(':')
+		if e.Type == TypeFloat {
+			sb.WriteString(strconv.FormatFloat(e.F, 'g', -1, 64)) // EncodeToString rounds floats
+		} else {
+			sb.WriteString(strconv.Quote(e.EncodeToString(false)))
+		}
+		sb.WriteByte(',')
+	}
+}
+
+// Elements returns the elements of an array. It returns nil for all other types.
+func (s Static) Elements() []Static {
+	if s.Type != TypeArray || s.Arr == nil {
+		return nil
+	}
+	return *s.Arr
+}
+
+// contains returns true if s is an array with at least one element equal to other.
+func (s Static) contains(other Static) bool {
+	for _, e := range s.Elements() {
+		if e.Equals(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// index returns the element at position i of an array, or nil if s is not an array or i is out of range
+func (s Static) index(i int) Static {
+	elems := s.Elements()
+	if i < 0 || i >= len(elems) {
+		return NewStaticNil()
+	}
+	return elems[i]
+}
+
 func (s Static) compare(other *Static) int {
 	if s.Type != other.Type {
 		if s.asFloat() > other.asFloat() {
@@ -567,6 +972,13 @@ func NewStaticBool(b bool) Static {
 	}
 }
 
+func NewStaticArray(elems []Static) Static {
+	return Static{
+		Type: TypeArray,
+		Arr:  &elems,
+	}
+}
+
 func NewStaticNil() Static {
 	return Static{
 		Type: TypeNil,
@@ -630,6 +1042,12 @@ func (a Attribute) impliedType() StaticType {
 		return TypeStatus
 	case IntrinsicKind:
 		return TypeKind
+	case IntrinsicTraceDuration:
+		return TypeDuration
+	case IntrinsicTraceRootService:
+		return TypeString
+	case IntrinsicTraceRootSpan:
+		return TypeString
 	case IntrinsicParent:
 		return TypeNil
 	}
@@ -645,8 +1063,8 @@ func (Attribute) referencesSpan() bool {
 // this handles parent, span, and resource scopes.
 func NewScopedAttribute(scope AttributeScope, parent bool, att string) Attribute {
 	intrinsic := IntrinsicNone
-	// if we are explicitly passed a resource or span scopes then we shouldn't parse for intrinsic
-	if scope != AttributeScopeResource && scope != AttributeScopeSpan {
+	// if we are explicitly passed a scope then we shouldn't parse for intrinsic
+	if scope == AttributeScopeNone {
 		intrinsic = intrinsicFromString(att)
 	}
 
@@ -674,3 +1092,4 @@ var _ pipelineElement = (*SpansetFilter)(nil)
 var _ pipelineElement = (*CoalesceOperation)(nil)
 var _ pipelineElement = (*ScalarFilter)(nil)
 var _ pipelineElement = (*GroupOperation)(nil)
+var _ pipelineElement = (*SelectOperation)(nil)
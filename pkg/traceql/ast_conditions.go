@@ -5,6 +5,11 @@ func (f SpansetFilter) extractConditions(request *FetchSpansRequest) {
 }
 
 func (o BinaryOperation) extractConditions(request *FetchSpansRequest) {
+	if cond, ok := o.caseInsensitiveCondition(); ok {
+		request.appendCondition(cond)
+		return
+	}
+
 	// TODO we can further optimise this by attempting to execute every FieldExpression, if they only contain statics it should resolve
 	switch o.LHS.(type) {
 	case Attribute:
@@ -42,6 +47,11 @@ func (o BinaryOperation) extractConditions(request *FetchSpansRequest) {
 			// 2 statics, don't need to send any conditions
 			return
 		case Attribute:
+			// functions aren't symmetric. startsWith("foo", .bar) can't be turned into a condition on .bar
+			if o.Op.isFunction() {
+				o.RHS.extractConditions(request)
+				return
+			}
 			request.appendCondition(Condition{
 				Attribute: o.RHS.(Attribute),
 				Op:        o.Op,
@@ -57,11 +67,48 @@ func (o BinaryOperation) extractConditions(request *FetchSpansRequest) {
 	}
 }
 
+// caseInsensitiveCondition returns the condition { .foo =* "bar" } for comparisons like { lower(.foo) = "bar" }
+// so they can be pushed down. It matches more values than the comparison when "bar" isn't lowercase, but
+// the engine evaluates the function again on the fetched spans.
+func (o BinaryOperation) caseInsensitiveCondition() (Condition, bool) {
+	if o.Op != OpEqual {
+		return Condition{}, false
+	}
+
+	fn, s := o.LHS, o.RHS
+	if _, ok := fn.(Static); ok {
+		fn, s = s, fn
+	}
+
+	u, ok := fn.(UnaryOperation)
+	if !ok || (u.Op != OpLower && u.Op != OpUpper) {
+		return Condition{}, false
+	}
+	a, ok := u.Expression.(Attribute)
+	if !ok {
+		return Condition{}, false
+	}
+	static, ok := s.(Static)
+	if !ok || static.Type != TypeString {
+		return Condition{}, false
+	}
+
+	return Condition{
+		Attribute: a,
+		Op:        OpEqualFold,
+		Operands:  []Static{static},
+	}, true
+}
+
 func (o UnaryOperation) extractConditions(request *FetchSpansRequest) {
 	// TODO when Op is Not we should just either negate all inner Operands or just fetch the columns with OpNone
 	o.Expression.extractConditions(request)
 }
 
+func (o IndexOperation) extractConditions(request *FetchSpansRequest) {
+	o.Expression.extractConditions(request)
+}
+
 func (s Static) extractConditions(request *FetchSpansRequest) {
 }
 
@@ -34,6 +34,47 @@ func TestSpansetFilter_extractConditions(t *testing.T) {
 			conditions:    []Condition{},
 			allConditions: true,
 		},
+		{
+			query: `{ .foo contains "bar" && .baz[1] = 3 }`,
+			conditions: []Condition{
+				newCondition(NewAttribute("foo"), OpContains, NewStaticString("bar")),
+				newCondition(NewAttribute("baz"), OpNone),
+			},
+			allConditions: true,
+		},
+		{
+			query: `{ lower(.foo) = "get" && "GET" = upper(span.bar) && startsWith(resource.baz, "api") }`,
+			conditions: []Condition{
+				newCondition(NewAttribute("foo"), OpEqualFold, NewStaticString("get")),
+				newCondition(NewScopedAttribute(AttributeScopeSpan, false, "bar"), OpEqualFold, NewStaticString("GET")),
+				newCondition(NewScopedAttribute(AttributeScopeResource, false, "baz"), OpStartsWith, NewStaticString("api")),
+			},
+			allConditions: true,
+		},
+		{
+			query: `{ .foo =* "bar" || endsWith(.baz, "z") || contains(.fzz, "y") }`,
+			conditions: []Condition{
+				newCondition(NewAttribute("foo"), OpEqualFold, NewStaticString("bar")),
+				newCondition(NewAttribute("baz"), OpEndsWith, NewStaticString("z")),
+				newCondition(NewAttribute("fzz"), OpSubstring, NewStaticString("y")),
+			},
+			allConditions: false,
+		},
+		{
+			query: `{ lower(.foo) != "bar" && startsWith("bar", .baz) }`,
+			conditions: []Condition{
+				newCondition(NewAttribute("foo"), OpNone),
+				newCondition(NewAttribute("baz"), OpNone),
+			},
+			allConditions: true,
+		},
+		{
+			query: `{ .foo[0] != "bar" }`,
+			conditions: []Condition{
+				newCondition(NewAttribute("foo"), OpNone),
+			},
+			allConditions: true,
+		},
 		{
 			query: `{ .foo = .bar }`,
 			conditions: []Condition{
@@ -147,3 +188,49 @@ func TestScalarFilter_extractConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestSpansetOperation_extractConditions(t *testing.T) {
+	tests := []struct {
+		query      string
+		conditions []Condition
+	}{
+		{
+			query: `{ .foo = "a" } > { .bar = "b" }`,
+			conditions: []Condition{
+				newCondition(NewAttribute("foo"), OpEqual, NewStaticString("a")),
+				newCondition(NewAttribute("bar"), OpEqual, NewStaticString("b")),
+				newCondition(NewIntrinsic(IntrinsicStructuralChild), OpNone),
+			},
+		},
+		{
+			// an empty side matches any span
+			query: `{ .foo = "a" } !>> { }`,
+			conditions: []Condition{
+				newCondition(NewAttribute("foo"), OpEqual, NewStaticString("a")),
+				newCondition(NewIntrinsic(IntrinsicStructuralDescendant), OpNone),
+				newCondition(NewIntrinsic(IntrinsicDuration), OpNone),
+			},
+		},
+		{
+			query: `{ .foo = "a" } && { }`,
+			conditions: []Condition{
+				newCondition(NewAttribute("foo"), OpEqual, NewStaticString("a")),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := Parse(tt.query)
+			require.NoError(t, err)
+
+			req := &FetchSpansRequest{
+				Conditions:    []Condition{},
+				AllConditions: true,
+			}
+			expr.Pipeline.extractConditions(req)
+
+			assert.Equal(t, tt.conditions, req.Conditions)
+			assert.False(t, req.AllConditions, "FetchSpansRequest.AllConditions")
+		})
+	}
+}
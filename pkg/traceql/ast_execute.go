@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -38,6 +39,16 @@ func (o SpansetOperation) evaluate(input []*Spanset) (output []*Spanset, err err
 				output = append(output, matchingSpanset)
 			}
 
+		case OpSpansetChild, OpSpansetDescendant, OpSpansetSibling,
+			OpSpansetParent, OpSpansetAncestor,
+			OpSpansetNotChild, OpSpansetNotDescendant, OpSpansetNotSibling:
+			spans := structuralMatches(o.Op, lhs, rhs)
+			if len(spans) > 0 {
+				matchingSpanset := input[i].clone()
+				matchingSpanset.Spans = spans
+				output = append(output, matchingSpanset)
+			}
+
 		default:
 			return nil, fmt.Errorf("spanset operation (%v) not supported", o.Op)
 		}
@@ -162,6 +173,42 @@ func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 			copy.AddAttribute(a.String(), copy.Scalar)
 			output = append(output, copy)
 
+		case aggregateQuantile:
+			// nearest-rank quantile so the result is one of the values and keeps its type. spans
+			// without a numeric value are skipped
+			vals := make([]Static, 0, len(ss.Spans))
+			for _, s := range ss.Spans {
+				val, err := a.e.execute(s)
+				if err != nil {
+					return nil, err
+				}
+				if val.Type.isNumeric() {
+					vals = append(vals, val)
+				}
+			}
+			copy := ss.clone()
+			copy.Scalar = quantile(vals, a.q)
+			copy.AddAttribute(a.String(), copy.Scalar)
+			output = append(output, copy)
+
+		case aggregateCountDistinct:
+			distinct := map[StaticMapKey]struct{}{}
+			for _, s := range ss.Spans {
+				val, err := a.e.execute(s)
+				if err != nil {
+					return nil, err
+				}
+				// spans without the value don't add a distinct value
+				if val.Type == TypeNil {
+					continue
+				}
+				distinct[val.MapKey()] = struct{}{}
+			}
+			copy := ss.clone()
+			copy.Scalar = NewStaticInt(len(distinct))
+			copy.AddAttribute(a.String(), copy.Scalar)
+			output = append(output, copy)
+
 		default:
 			return nil, fmt.Errorf("aggregate operation (%v) not supported", a.op)
 		}
@@ -170,6 +217,24 @@ func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 	return output, nil
 }
 
+// quantile returns the value at quantile q of vals using the nearest-rank method. vals is sorted in place
+func quantile(vals []Static, q float64) Static {
+	if len(vals) == 0 {
+		return NewStaticNil()
+	}
+
+	sort.Slice(vals, func(i, j int) bool {
+		return vals[i].compare(&vals[j]) == -1
+	})
+
+	rank := int(math.Ceil(q*float64(len(vals)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return vals[rank]
+}
+
 func (o BinaryOperation) execute(span Span) (Static, error) {
 	lhs, err := o.LHS.execute(span)
 	if err != nil {
@@ -181,6 +246,17 @@ func (o BinaryOperation) execute(span Span) (Static, error) {
 		return NewStaticNil(), err
 	}
 
+	// contains compares the rhs to the elements of the lhs instead of to the lhs itself
+	if o.Op == OpContains {
+		return NewStaticBool(lhs.contains(rhs)), nil
+	}
+
+	// comparing to nil tests if the attribute is present on the span, regardless of its type
+	if _, ok := o.existenceCheck(); ok {
+		missing := lhs.Type == TypeNil && rhs.Type == TypeNil
+		return NewStaticBool(missing == (o.Op == OpEqual)), nil
+	}
+
 	// Ensure the resolved types are still valid
 	lhsT := lhs.impliedType()
 	rhsT := rhs.impliedType()
@@ -202,6 +278,14 @@ func (o BinaryOperation) execute(span Span) (Static, error) {
 			return NewStaticBool(strings.Compare(lhs.String(), rhs.String()) < 0), nil
 		case OpLessEqual:
 			return NewStaticBool(strings.Compare(lhs.String(), rhs.String()) <= 0), nil
+		case OpEqualFold:
+			return NewStaticBool(strings.EqualFold(lhs.S, rhs.S)), nil
+		case OpStartsWith:
+			return NewStaticBool(strings.HasPrefix(lhs.S, rhs.S)), nil
+		case OpEndsWith:
+			return NewStaticBool(strings.HasSuffix(lhs.S, rhs.S)), nil
+		case OpSubstring:
+			return NewStaticBool(strings.Contains(lhs.S, rhs.S)), nil
 		default:
 		}
 	}
@@ -305,14 +389,44 @@ func (o UnaryOperation) execute(span Span) (Static, error) {
 			return NewStaticDuration(-1 * static.D), nil
 		}
 	}
+	if o.Op == OpLower || o.Op == OpUpper {
+		// the attribute is missing or isn't a string. return nil so any comparison fails
+		if static.Type != TypeString {
+			return NewStaticNil(), nil
+		}
+		if o.Op == OpLower {
+			return NewStaticString(strings.ToLower(static.S)), nil
+		}
+		return NewStaticString(strings.ToUpper(static.S)), nil
+	}
+
+	return NewStaticNil(), errors.New("UnaryOperation has Op different from Not, Sub, Lower and Upper")
+}
+
+func (o IndexOperation) execute(span Span) (Static, error) {
+	static, err := o.Expression.execute(span)
+	if err != nil {
+		return NewStaticNil(), err
+	}
 
-	return NewStaticNil(), errors.New("UnaryOperation has Op different from Not and Sub")
+	return static.index(o.Index), nil
 }
 
 func (s Static) execute(span Span) (Static, error) {
 	return s, nil
 }
 
+// GroupBy returns the values of the by() attributes for the span in the same order. Unscoped
+// attributes are resolved the same way as in a spanset filter.
+func (m MetricsAggregate) GroupBy(span Span) []Static {
+	values := make([]Static, 0, len(m.By))
+	for _, a := range m.By {
+		v, _ := a.execute(span) // attributes never return an error
+		values = append(values, v)
+	}
+	return values
+}
+
 func (a Attribute) execute(span Span) (Static, error) {
 	atts := span.Attributes()
 	static, ok := atts[a]
@@ -322,12 +436,12 @@ func (a Attribute) execute(span Span) (Static, error) {
 
 	if a.Scope == AttributeScopeNone {
 		for attribute, static := range atts {
-			if a.Name == attribute.Name && attribute.Scope == AttributeScopeSpan {
+			if a.Name == attribute.Name && a.Parent == attribute.Parent && attribute.Scope == AttributeScopeSpan {
 				return static, nil
 			}
 		}
 		for attribute, static := range atts {
-			if a.Name == attribute.Name {
+			if a.Name == attribute.Name && a.Parent == attribute.Parent {
 				return static, nil
 			}
 		}
@@ -336,6 +450,60 @@ func (a Attribute) execute(span Span) (Static, error) {
 	return NewStaticNil(), nil
 }
 
+// structuralMatches returns the spans on the rhs that have the relationship described by op to at
+// least one span on the lhs. i.e. for { a } > { b } it returns all b that are children of an a. The
+// negated operators return the rhs spans that don't have the relationship to any lhs span.
+func structuralMatches(op Operator, lhs []*Spanset, rhs []*Spanset) []Span {
+	var related func(l, r Span) bool
+	negate := false
+
+	switch op {
+	case OpSpansetChild:
+		related = func(l, r Span) bool { return r.ChildOf(l) }
+	case OpSpansetDescendant:
+		related = func(l, r Span) bool { return r.DescendantOf(l) }
+	case OpSpansetSibling:
+		related = func(l, r Span) bool { return r.SiblingOf(l) }
+	case OpSpansetParent:
+		related = func(l, r Span) bool { return l.ChildOf(r) }
+	case OpSpansetAncestor:
+		related = func(l, r Span) bool { return l.DescendantOf(r) }
+	case OpSpansetNotChild:
+		related = func(l, r Span) bool { return r.ChildOf(l) }
+		negate = true
+	case OpSpansetNotDescendant:
+		related = func(l, r Span) bool { return r.DescendantOf(l) }
+		negate = true
+	case OpSpansetNotSibling:
+		related = func(l, r Span) bool { return r.SiblingOf(l) }
+		negate = true
+	default:
+		return nil
+	}
+
+	var output []Span
+	for _, rss := range rhs {
+		for _, r := range rss.Spans {
+			found := false
+		lhsLoop:
+			for _, lss := range lhs {
+				for _, l := range lss.Spans {
+					if related(l, r) {
+						found = true
+						break lhsLoop
+					}
+				}
+			}
+
+			if found != negate {
+				output = append(output, r)
+			}
+		}
+	}
+
+	return output
+}
+
 func uniqueSpans(ss1 []*Spanset, ss2 []*Spanset) []Span {
 	ss1Count := 0
 	ss2Count := 0
@@ -155,6 +155,186 @@ func TestSpansetFilter_matches(t *testing.T) {
 			},
 			matches: true,
 		},
+		{
+			query: `{ traceDuration > 5s && rootServiceName = "checkout" && rootName = "GET /cart" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewIntrinsic(IntrinsicTraceDuration):    NewStaticDuration(10 * time.Second),
+					NewIntrinsic(IntrinsicTraceRootService): NewStaticString("checkout"),
+					NewIntrinsic(IntrinsicTraceRootSpan):    NewStaticString("GET /cart"),
+				},
+			},
+			matches: true,
+		},
+		{
+			query: `{ traceDuration > 5s }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewIntrinsic(IntrinsicTraceDuration): NewStaticDuration(time.Second),
+				},
+			},
+			matches: false,
+		},
+		{
+			query: `{ parent.name = "HTTP GET" && parent.span.foo = "parent" && span.foo = "child" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeNone, true, "name"): NewStaticString("HTTP GET"),
+					NewScopedAttribute(AttributeScopeSpan, true, "foo"):  NewStaticString("parent"),
+					NewScopedAttribute(AttributeScopeSpan, false, "foo"): NewStaticString("child"),
+				},
+			},
+			matches: true,
+		},
+		{
+			// unscoped attributes only match the parent if asked for
+			query: `{ parent.foo = "child" || .foo = "parent" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, true, "foo"):  NewStaticString("parent"),
+					NewScopedAttribute(AttributeScopeSpan, false, "foo"): NewStaticString("child"),
+				},
+			},
+			matches: false,
+		},
+		{
+			query: `{ span.tags contains "beta" && span.partitions contains 3 }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, false, "tags"):       NewStaticArray([]Static{NewStaticString("alpha"), NewStaticString("beta")}),
+					NewScopedAttribute(AttributeScopeSpan, false, "partitions"): NewStaticArray([]Static{NewStaticInt(1), NewStaticInt(3)}),
+				},
+			},
+			matches: true,
+		},
+		{
+			query: `{ .tags contains "gamma" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, false, "tags"): NewStaticArray([]Static{NewStaticString("alpha"), NewStaticString("beta")}),
+				},
+			},
+			matches: false,
+		},
+		{
+			// scalars are not arrays
+			query: `{ .tags contains "alpha" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, false, "tags"): NewStaticString("alpha"),
+				},
+			},
+			matches: false,
+		},
+		{
+			query: `{ span.tags[1] = "beta" && span.tags[0] != "beta" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, false, "tags"): NewStaticArray([]Static{NewStaticString("alpha"), NewStaticString("beta")}),
+				},
+			},
+			matches: true,
+		},
+		{
+			// out of range indexes are nil
+			query: `{ span.tags[2] = "beta" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, false, "tags"): NewStaticArray([]Static{NewStaticString("alpha"), NewStaticString("beta")}),
+				},
+			},
+			matches: false,
+		},
+		{
+			query: `{ span.a = span.b }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, false, "a"): NewStaticArray([]Static{NewStaticInt(1), NewStaticFloat(2)}),
+					NewScopedAttribute(AttributeScopeSpan, false, "b"): NewStaticArray([]Static{NewStaticFloat(1), NewStaticInt(2)}),
+				},
+			},
+			matches: true,
+		},
+		{
+			query: `{ span.foo != nil && span.bar = nil }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, false, "foo"): NewStaticInt(1),
+				},
+			},
+			matches: true,
+		},
+		{
+			query: `{ span.foo = nil }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, false, "foo"): NewStaticString("bar"),
+				},
+			},
+			matches: false,
+		},
+		{
+			// unscoped attributes exist if either the span or resource has them
+			query: `{ nil != .foo }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeResource, false, "foo"): NewStaticBool(false),
+				},
+			},
+			matches: true,
+		},
+		{
+			query: `{ lower(span.method) = "get" && upper(name) = "GET /API" && span.method =* "gEt" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewIntrinsic(IntrinsicName):                             NewStaticString("Get /api"),
+					NewScopedAttribute(AttributeScopeSpan, false, "method"): NewStaticString("GET"),
+				},
+			},
+			matches: true,
+		},
+		{
+			query: `{ startsWith(.url, "/api") && endsWith(.url, "users") && contains(.url, "v1") }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewAttribute("url"): NewStaticString("/api/v1/users"),
+				},
+			},
+			matches: true,
+		},
+		{
+			query: `{ startsWith(.url, "/api") || endsWith(.url, "/api") || contains(.url, "api") }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewAttribute("url"): NewStaticString("/API"),
+				},
+			},
+			matches: false,
+		},
+		{
+			// string functions don't match attributes of other types
+			query: `{ lower(.foo) = "1" || startsWith(.foo, "1") || .foo =* "1" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewAttribute("foo"): NewStaticInt(1),
+				},
+			},
+			matches: false,
+		},
+		{
+			query:   `{ lower(.missing) != "foo" }`,
+			span:    &mockSpan{},
+			matches: false,
+		},
+		{
+			query: `{ resource.foo != nil }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeSpan, false, "foo"): NewStaticBool(false),
+				},
+			},
+			matches: false,
+		},
 	}
 	for _, tt := range tests {
 		// create a evalTC and use testEvaluator
@@ -250,6 +430,50 @@ func TestSpansetOperationEvaluate(t *testing.T) {
 	}
 }
 
+func TestSpansetOperationEvaluateStructural(t *testing.T) {
+	// root (a)
+	// ├── child1 (b)
+	// │   └── grandchild1 (c)
+	// └── child2 (b)
+	//     └── grandchild2 (d)
+	root := &mockSpan{id: []byte{1}, left: 1, right: 10, parent: 0, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}}
+	child1 := &mockSpan{id: []byte{2}, left: 2, right: 5, parent: 1, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("b")}}
+	grandchild1 := &mockSpan{id: []byte{3}, left: 3, right: 4, parent: 2, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("c")}}
+	child2 := &mockSpan{id: []byte{4}, left: 6, right: 9, parent: 1, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("b")}}
+	grandchild2 := &mockSpan{id: []byte{5}, left: 7, right: 8, parent: 6, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("d")}}
+
+	testCases := []struct {
+		query    string
+		expected []Span
+	}{
+		{"{ .foo = `a` } > { .foo = `b` }", []Span{child1, child2}},
+		{"{ .foo = `a` } > { .foo = `c` }", nil},
+		{"{ .foo = `a` } >> { .foo = `c` }", []Span{grandchild1}},
+		{"{ .foo = `b` } ~ { .foo = `b` }", []Span{child1, child2}},
+		{"{ .foo = `c` } ~ { }", nil},
+		{"{ .foo = `c` } < { .foo = `b` }", []Span{child1}},
+		{"{ .foo = `c` } << { }", []Span{root, child1}},
+		{"{ .foo = `b` } !> { }", []Span{root, child1, child2}},
+		{"{ .foo = `a` } !>> { }", []Span{root}},
+		{"{ .foo = `x` } !>> { .foo = `c` }", []Span{grandchild1}},
+		{"{ .foo = `c` } !~ { .foo = `b` }", []Span{child1, child2}},
+		{"{ .foo = `b` } !~ { .foo = `b` }", nil},
+	}
+
+	for _, tc := range testCases {
+		input := []*Spanset{
+			{Spans: []Span{root, child1, grandchild1, child2, grandchild2}},
+		}
+
+		output := []*Spanset{}
+		if len(tc.expected) > 0 {
+			output = []*Spanset{{Spans: tc.expected}}
+		}
+
+		testEvaluator(t, evalTC{tc.query, input, output})
+	}
+}
+
 func TestScalarFilterEvaluate(t *testing.T) {
 	testCases := []evalTC{
 		{
@@ -452,6 +676,108 @@ func TestScalarFilterEvaluate(t *testing.T) {
 				},
 			},
 		},
+		// quantile
+		{
+			"{ .foo = `a` } | quantile(duration, 0.5) >= 10ms",
+			[]*Spanset{
+				{Spans: []Span{
+					// p50 duration = 2ms
+					&mockSpan{attributes: map[Attribute]Static{
+						NewAttribute("foo"):             NewStaticString("a"),
+						NewIntrinsic(IntrinsicDuration): NewStaticDuration(8 * time.Millisecond)},
+					},
+					&mockSpan{attributes: map[Attribute]Static{
+						NewAttribute("foo"):             NewStaticString("a"),
+						NewIntrinsic(IntrinsicDuration): NewStaticDuration(2 * time.Millisecond)},
+					},
+				}},
+				{Spans: []Span{
+					// p50 duration = 12ms
+					&mockSpan{attributes: map[Attribute]Static{
+						NewAttribute("foo"):             NewStaticString("a"),
+						NewIntrinsic(IntrinsicDuration): NewStaticDuration(15 * time.Millisecond)},
+					},
+					&mockSpan{attributes: map[Attribute]Static{
+						NewAttribute("foo"):             NewStaticString("a"),
+						NewIntrinsic(IntrinsicDuration): NewStaticDuration(12 * time.Millisecond)},
+					},
+					&mockSpan{attributes: map[Attribute]Static{
+						NewAttribute("foo"):             NewStaticString("a"),
+						NewIntrinsic(IntrinsicDuration): NewStaticDuration(3 * time.Millisecond)},
+					},
+				}},
+			},
+			[]*Spanset{
+				{
+					Scalar: NewStaticDuration(12 * time.Millisecond),
+					Spans: []Span{
+						&mockSpan{attributes: map[Attribute]Static{
+							NewAttribute("foo"):             NewStaticString("a"),
+							NewIntrinsic(IntrinsicDuration): NewStaticDuration(15 * time.Millisecond)},
+						},
+						&mockSpan{attributes: map[Attribute]Static{
+							NewAttribute("foo"):             NewStaticString("a"),
+							NewIntrinsic(IntrinsicDuration): NewStaticDuration(12 * time.Millisecond)},
+						},
+						&mockSpan{attributes: map[Attribute]Static{
+							NewAttribute("foo"):             NewStaticString("a"),
+							NewIntrinsic(IntrinsicDuration): NewStaticDuration(3 * time.Millisecond)},
+						},
+					},
+					Attributes: map[string]Static{"quantile(duration, 0.5)": NewStaticDuration(12 * time.Millisecond)},
+				},
+			},
+		},
+		// count_distinct
+		{
+			"{ .foo = `a` } | count_distinct(.bar) > 1",
+			[]*Spanset{
+				{Spans: []Span{
+					// 1 distinct value
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticString("x")}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticString("x")}},
+				}},
+				{Spans: []Span{
+					// 2 distinct values, spans without the attribute are ignored
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticString("x")}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(1)}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+				}},
+			},
+			[]*Spanset{
+				{
+					Scalar: NewStaticInt(2),
+					Spans: []Span{
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticString("x")}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(1)}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+					},
+					Attributes: map[string]Static{"count_distinct(.bar)": NewStaticInt(2)},
+				},
+			},
+		},
+		// count_distinct keys arrays by their elements and never matches them to strings
+		{
+			"{ .foo = `a` } | count_distinct(.bar) > 1",
+			[]*Spanset{
+				{Spans: []Span{
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticArray([]Static{NewStaticString("x")})}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticArray([]Static{NewStaticString("x")})}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticString("[`x`]")}},
+				}},
+			},
+			[]*Spanset{
+				{
+					Scalar: NewStaticInt(2),
+					Spans: []Span{
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticArray([]Static{NewStaticString("x")})}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticArray([]Static{NewStaticString("x")})}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticString("[`x`]")}},
+					},
+					Attributes: map[string]Static{"count_distinct(.bar)": NewStaticInt(2)},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -7,7 +7,53 @@ import (
 )
 
 func (r RootExpr) String() string {
-	return r.Pipeline.String()
+	s := r.Pipeline.String()
+	if r.MetricsPipeline != nil {
+		s += "|" + r.MetricsPipeline.String()
+	}
+	if r.OrderBy != nil {
+		s += "|" + r.OrderBy.String()
+	}
+	if r.Limit > 0 {
+		s += "|limit " + strconv.Itoa(r.Limit)
+	}
+	if r.Hints != nil {
+		s += " " + r.Hints.String()
+	}
+	return s
+}
+
+func (h Hints) String() string {
+	hints := make([]string, 0, len(h.Hints))
+	for _, hint := range h.Hints {
+		hints = append(hints, hint.Name+"="+hint.Value.String())
+	}
+	return "with(" + strings.Join(hints, ", ") + ")"
+}
+
+func (o OrderBy) String() string {
+	if o.Desc {
+		return "order by " + o.Key.String() + " desc"
+	}
+	return "order by " + o.Key.String()
+}
+
+func (m MetricsAggregate) String() string {
+	s := m.Op.String() + "("
+	if m.Op == MetricsAggregateQuantileOverTime {
+		s += m.Attr.String() + ", " + strconv.FormatFloat(m.Quantile, 'f', -1, 64)
+	}
+	s += ")"
+
+	if len(m.By) > 0 {
+		by := make([]string, 0, len(m.By))
+		for _, a := range m.By {
+			by = append(by, a.String())
+		}
+		s += " by(" + strings.Join(by, ", ") + ")"
+	}
+
+	return s
 }
 
 func (p Pipeline) String() string {
@@ -26,6 +72,14 @@ func (o CoalesceOperation) String() string {
 	return "coalesce()"
 }
 
+func (o SelectOperation) String() string {
+	s := make([]string, 0, len(o.attrs))
+	for _, a := range o.attrs {
+		s = append(s, a.String())
+	}
+	return "select(" + strings.Join(s, ", ") + ")"
+}
+
 func (o ScalarOperation) String() string {
 	return binaryOp(o.Op, o.LHS, o.RHS)
 }
@@ -35,6 +89,10 @@ func (a Aggregate) String() string {
 		return a.op.String() + "()"
 	}
 
+	if a.op == aggregateQuantile {
+		return a.op.String() + "(" + a.e.String() + ", " + strconv.FormatFloat(a.q, 'f', -1, 64) + ")"
+	}
+
 	return a.op.String() + "(" + a.e.String() + ")"
 }
 
@@ -58,6 +116,10 @@ func (o UnaryOperation) String() string {
 	return unaryOp(o.Op, o.Expression)
 }
 
+func (o IndexOperation) String() string {
+	return wrapElement(o.Expression) + "[" + strconv.Itoa(o.Index) + "]"
+}
+
 func (n Static) String() string {
 	return n.EncodeToString(true)
 }
@@ -83,6 +145,13 @@ func (n Static) EncodeToString(quotes bool) string {
 		return n.Status.String()
 	case TypeKind:
 		return n.Kind.String()
+	case TypeArray:
+		elems := n.Elements()
+		strs := make([]string, 0, len(elems))
+		for _, e := range elems {
+			strs = append(strs, e.EncodeToString(quotes))
+		}
+		return "[" + strings.Join(strs, ", ") + "]"
 	}
 
 	return fmt.Sprintf("static(%d)", n.Type)
@@ -117,11 +186,32 @@ func (a Attribute) String() string {
 	return scope + att
 }
 
+func (c Condition) String() string {
+	if c.Op == OpNone {
+		return c.Attribute.String()
+	}
+
+	operands := make([]string, 0, len(c.Operands))
+	for _, o := range c.Operands {
+		operands = append(operands, o.String())
+	}
+	if c.Op.isFunction() {
+		return c.Op.funcName() + "(" + c.Attribute.String() + ", " + strings.Join(operands, ", ") + ")"
+	}
+	return c.Attribute.String() + " " + c.Op.String() + " " + strings.Join(operands, ", ")
+}
+
 func binaryOp(op Operator, lhs Element, rhs Element) string {
+	if op.isFunction() {
+		return op.funcName() + "(" + lhs.String() + ", " + rhs.String() + ")"
+	}
 	return wrapElement(lhs) + " " + op.String() + " " + wrapElement(rhs)
 }
 
 func unaryOp(op Operator, e Element) string {
+	if op.isFunction() {
+		return op.funcName() + "(" + e.String() + ")"
+	}
 	return op.String() + wrapElement(e)
 }
 
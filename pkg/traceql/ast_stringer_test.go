@@ -46,6 +46,8 @@ func TestStringerRoundtrip(t *testing.T) {
 		"{ parent.duration = 1s }",
 		"{ span.duration = 1s }",
 		"{ resource.duration = 1s }",
+		"{ .foo contains `bar` }", // contains of arrays and the substring function share their name
+		"{ contains(.foo, `bar`) }",
 	}
 
 	for _, q := range roundtrippable {
@@ -56,3 +58,10 @@ func TestStringerRoundtrip(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionStringer(t *testing.T) {
+	require.Equal(t, ".foo contains `bar`", newCondition(NewAttribute("foo"), OpContains, NewStaticString("bar")).String())
+	require.Equal(t, "contains(.foo, `bar`)", newCondition(NewAttribute("foo"), OpSubstring, NewStaticString("bar")).String())
+	require.Equal(t, "startsWith(.foo, `bar`)", newCondition(NewAttribute("foo"), OpStartsWith, NewStaticString("bar")).String())
+	require.NotEqual(t, OpContains.String(), OpSubstring.String())
+}
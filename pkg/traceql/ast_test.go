@@ -52,6 +52,41 @@ func TestStatic_Equals(t *testing.T) {
 	}
 }
 
+func TestStatic_MapKey(t *testing.T) {
+	arr := func(elems ...Static) Static { return NewStaticArray(elems) }
+
+	areEqual := []struct {
+		lhs, rhs Static
+	}{
+		{NewStaticInt(1), NewStaticInt(1)},
+		{NewStaticString("foo"), NewStaticString("foo")},
+		{arr(NewStaticString("a"), NewStaticInt(1)), arr(NewStaticString("a"), NewStaticInt(1))},
+		{arr(), arr()},
+		{arr(arr(NewStaticBool(true))), arr(arr(NewStaticBool(true)))},
+	}
+	areNotEqual := []struct {
+		lhs, rhs Static
+	}{
+		{NewStaticInt(1), NewStaticInt(2)},
+		{arr(NewStaticString("a")), NewStaticString("[a]")},
+		{arr(NewStaticString("a")), NewStaticString("a")},
+		{arr(NewStaticString("1")), arr(NewStaticInt(1))},
+		{arr(NewStaticString("a, b")), arr(NewStaticString("a"), NewStaticString("b"))},
+		{arr(NewStaticFloat(1.000001)), arr(NewStaticFloat(1.000002))},
+		{arr(arr(NewStaticInt(1)), NewStaticInt(2)), arr(arr(NewStaticInt(1), NewStaticInt(2)))},
+	}
+	for _, tt := range areEqual {
+		t.Run(fmt.Sprintf("%v == %v", tt.lhs, tt.rhs), func(t *testing.T) {
+			assert.Equal(t, tt.lhs.MapKey(), tt.rhs.MapKey())
+		})
+	}
+	for _, tt := range areNotEqual {
+		t.Run(fmt.Sprintf("%v != %v", tt.lhs, tt.rhs), func(t *testing.T) {
+			assert.NotEqual(t, tt.lhs.MapKey(), tt.rhs.MapKey())
+		})
+	}
+}
+
 func TestPipelineExtractConditions(t *testing.T) {
 	testCases := []struct {
 		query   string
@@ -345,6 +380,9 @@ type mockSpan struct {
 	startTimeUnixNanos uint64
 	durationNanos      uint64
 	attributes         map[Attribute]Static
+
+	// nested set values used to evaluate structural operators
+	left, right, parent int
 }
 
 func (m *mockSpan) Attributes() map[Attribute]Static {
@@ -359,3 +397,14 @@ func (m *mockSpan) StartTimeUnixNanos() uint64 {
 func (m *mockSpan) DurationNanos() uint64 {
 	return m.durationNanos
 }
+func (m *mockSpan) DescendantOf(s Span) bool {
+	other := s.(*mockSpan)
+	return m.left > other.left && m.right < other.right
+}
+func (m *mockSpan) ChildOf(s Span) bool {
+	return m.parent != 0 && m.parent == s.(*mockSpan).left
+}
+func (m *mockSpan) SiblingOf(s Span) bool {
+	other := s.(*mockSpan)
+	return m != other && m.parent != 0 && m.parent == other.parent
+}
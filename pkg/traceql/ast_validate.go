@@ -1,6 +1,9 @@
 package traceql
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // unsupportedError is returned for traceql features that are not yet supported.
 type unsupportedError struct {
@@ -16,7 +19,91 @@ func (e unsupportedError) Error() string {
 }
 
 func (r RootExpr) validate() error {
-	return r.Pipeline.validate()
+	if err := r.Pipeline.validate(); err != nil {
+		return err
+	}
+
+	if r.MetricsPipeline != nil {
+		if err := r.MetricsPipeline.validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Hints != nil {
+		if err := r.Hints.validate(); err != nil {
+			return err
+		}
+	}
+
+	// metrics are computed from all spans in the range, sampling would require scaling every function
+	if _, ok := r.Hints.GetFloat(HintSample); ok && r.MetricsPipeline != nil {
+		return newUnsupportedError("hint " + HintSample + " in metrics queries")
+	}
+
+	return nil
+}
+
+// validateSearch checks that the query can be run by the search api. Metrics queries are only run by
+// the query range api.
+func (r RootExpr) validateSearch() error {
+	if r.MetricsPipeline != nil {
+		return errors.New("metrics queries are not supported by search, use the query range api")
+	}
+	return nil
+}
+
+func (h Hints) validate() error {
+	for _, hint := range h.Hints {
+		if err := hint.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h Hint) validate() error {
+	v := h.Value
+	switch h.Name {
+	case HintMostRecent, HintExhaustive, HintIngesters:
+		if v.Type != TypeBoolean {
+			return fmt.Errorf("hint %s must be true or false: %s=%s", h.Name, h.Name, v.String())
+		}
+	case HintSample:
+		if (v.Type != TypeFloat && v.Type != TypeInt) || v.asFloat() <= 0 || v.asFloat() > 1 {
+			return fmt.Errorf("hint %s must be greater than 0 and at most 1: %s=%s", h.Name, h.Name, v.String())
+		}
+	case HintSpss, HintJobSize:
+		if v.Type != TypeInt || v.N <= 0 {
+			return fmt.Errorf("hint %s must be a positive integer: %s=%s", h.Name, h.Name, v.String())
+		}
+	default:
+		return fmt.Errorf("unknown hint: %s", h.Name)
+	}
+	return nil
+}
+
+func (m MetricsAggregate) validate() error {
+	for _, a := range m.By {
+		if err := a.validate(); err != nil {
+			return err
+		}
+	}
+
+	switch m.Op {
+	case MetricsAggregateRate, MetricsAggregateCountOverTime:
+	case MetricsAggregateQuantileOverTime:
+		// values are recorded in exponential duration buckets
+		if m.Attr.Intrinsic != IntrinsicDuration {
+			return newUnsupportedError(m.Op.String() + " of " + m.Attr.String())
+		}
+		if m.Quantile < 0 || m.Quantile > 1 {
+			return fmt.Errorf("quantile must be between 0 and 1: %s", m.String())
+		}
+	default:
+		return newUnsupportedError(m.Op.String())
+	}
+
+	return nil
 }
 
 func (p Pipeline) validate() error {
@@ -44,6 +131,16 @@ func (o CoalesceOperation) validate() error {
 	return newUnsupportedError("coalesce()")
 }
 
+func (o SelectOperation) validate() error {
+	for _, a := range o.attrs {
+		if err := a.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (o ScalarOperation) validate() error {
 	if err := o.LHS.validate(); err != nil {
 		return err
@@ -74,9 +171,10 @@ func (a Aggregate) validate() error {
 		return err
 	}
 
-	// aggregate field expressions require a type of a number or attribute
+	// aggregate field expressions require a type of a number or attribute. distinct values
+	// can be counted for any type
 	t := a.e.impliedType()
-	if t != TypeAttribute && !t.isNumeric() {
+	if a.op != aggregateCountDistinct && t != TypeAttribute && !t.isNumeric() {
 		return fmt.Errorf("aggregate field expressions must resolve to a number type: %s", a.String())
 	}
 
@@ -84,8 +182,12 @@ func (a Aggregate) validate() error {
 		return fmt.Errorf("aggregate field expressions must reference the span: %s", a.String())
 	}
 
+	if a.op == aggregateQuantile && (a.q < 0 || a.q > 1) {
+		return fmt.Errorf("quantile must be between 0 and 1: %s", a.String())
+	}
+
 	switch a.op {
-	case aggregateCount, aggregateAvg, aggregateMin, aggregateMax, aggregateSum:
+	case aggregateCount, aggregateAvg, aggregateMin, aggregateMax, aggregateSum, aggregateQuantile, aggregateCountDistinct:
 	default:
 		return newUnsupportedError(fmt.Sprintf("aggregate operation (%v)", a.op))
 	}
@@ -102,12 +204,6 @@ func (o SpansetOperation) validate() error {
 		return err
 	}
 
-	// supported spanset operations
-	switch o.Op {
-	case OpSpansetChild, OpSpansetDescendant, OpSpansetSibling:
-		return newUnsupportedError(fmt.Sprintf("spanset operation (%v)", o.Op))
-	}
-
 	return nil
 }
 
@@ -159,6 +255,17 @@ func (f ScalarFilter) validate() error {
 }
 
 func (o BinaryOperation) validate() error {
+	// nil is only valid to test attributes for existence. intrinsics are always present
+	if e, ok := o.existenceCheck(); ok {
+		if err := e.validate(); err != nil {
+			return err
+		}
+		if a, ok := e.(Attribute); !ok || a.Intrinsic != IntrinsicNone {
+			return fmt.Errorf("nil can only be compared to attributes: %s", o.String())
+		}
+		return nil
+	}
+
 	if err := o.LHS.validate(); err != nil {
 		return err
 	}
@@ -177,6 +284,11 @@ func (o BinaryOperation) validate() error {
 		return fmt.Errorf("illegal operation for the given types: %s", o.String())
 	}
 
+	// only attributes can hold arrays
+	if o.Op == OpContains && lhsT != TypeAttribute {
+		return fmt.Errorf("contains must operate on an attribute: %s", o.String())
+	}
+
 	switch o.Op {
 	case OpSpansetChild,
 		OpSpansetDescendant,
@@ -204,18 +316,19 @@ func (o UnaryOperation) validate() error {
 	return nil
 }
 
+func (o IndexOperation) validate() error {
+	return o.Expression.validate()
+}
+
 func (n Static) validate() error {
 	if n.Type == TypeNil {
-		return newUnsupportedError("nil")
+		return fmt.Errorf("nil can only be compared to attributes")
 	}
 
 	return nil
 }
 
 func (a Attribute) validate() error {
-	if a.Parent {
-		return newUnsupportedError("parent")
-	}
 	switch a.Intrinsic {
 	case IntrinsicParent,
 		IntrinsicChildCount:
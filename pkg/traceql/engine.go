@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -39,27 +40,50 @@ func (e *Engine) Compile(query string) (func(input []*Spanset) (result []*Spanse
 	return expr.Pipeline.evaluate, req, nil
 }
 
-func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchRequest, spanSetFetcher SpansetFetcher) (*tempopb.SearchResponse, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "traceql.Engine.ExecuteSearch")
-	defer span.Finish()
+// CompileMetricsQueryRange parses and validates a metrics query. It returns the metrics stage, the
+// evaluation of the spanset pipeline and the fetch request which includes the fields needed to
+// compute the metrics in the second pass.
+func (e *Engine) CompileMetricsQueryRange(query string) (*MetricsAggregate, func(input []*Spanset) (result []*Spanset, err error), *FetchSpansRequest, error) {
+	expr, err := Parse(query)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := expr.validate(); err != nil {
+		return nil, nil, nil, err
+	}
+	if expr.MetricsPipeline == nil {
+		return nil, nil, nil, fmt.Errorf("not a metrics query: %s", query)
+	}
+
+	req := &FetchSpansRequest{
+		AllConditions: true,
+	}
+	expr.Pipeline.extractConditions(req)
+	expr.MetricsPipeline.extractConditions(req)
+
+	return expr.MetricsPipeline, expr.Pipeline.evaluate, req, nil
+}
 
+// CompileSearch parses and validates a search query. It returns the expression and the fetch request
+// ExecuteSearch passes to the storage layer, without the second pass callback.
+func (e *Engine) CompileSearch(searchReq *tempopb.SearchRequest) (*RootExpr, FetchSpansRequest, error) {
 	rootExpr, err := e.parseQuery(searchReq)
 	if err != nil {
-		return nil, err
+		return nil, FetchSpansRequest{}, err
 	}
 
 	fetchSpansRequest := e.createFetchSpansRequest(searchReq, rootExpr.Pipeline)
-
-	span.SetTag("pipeline", rootExpr.Pipeline)
-	span.SetTag("fetchSpansRequest", fetchSpansRequest)
+	fetchSpansRequest.Sample, _ = rootExpr.Hints.GetFloat(HintSample)
 
 	// calculate search meta conditions. the only choice is whether or not to include duration
-	// if we request duration as part of the normal span fetch then we can ignore it
+	// if we request duration as part of the normal span fetch or select it then we can ignore it
 	durationRequested := false
-	for _, c := range fetchSpansRequest.Conditions {
-		if c.Attribute.Intrinsic == IntrinsicDuration {
-			durationRequested = true
-			break
+	for _, conds := range [][]Condition{fetchSpansRequest.Conditions, fetchSpansRequest.SecondPassConditions} {
+		for _, c := range conds {
+			if c.Attribute.Intrinsic == IntrinsicDuration {
+				durationRequested = true
+				break
+			}
 		}
 	}
 
@@ -68,9 +92,26 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		metaConditions = SearchMetaConditionsWithoutDuration()
 	}
 
+	// selected attributes have already been added to the second pass
+	fetchSpansRequest.SecondPassConditions = append(fetchSpansRequest.SecondPassConditions, metaConditions...)
+
+	return rootExpr, fetchSpansRequest, nil
+}
+
+func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchRequest, spanSetFetcher SpansetFetcher) (*tempopb.SearchResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "traceql.Engine.ExecuteSearch")
+	defer span.Finish()
+
+	rootExpr, fetchSpansRequest, err := e.CompileSearch(searchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetTag("pipeline", rootExpr.Pipeline)
+	span.SetTag("fetchSpansRequest", fetchSpansRequest)
+
 	spansetsEvaluated := 0
 	// set up the expression evaluation as a filter to reduce data pulled
-	fetchSpansRequest.SecondPassConditions = metaConditions
 	fetchSpansRequest.SecondPass = func(inSS *Spanset) ([]*Spanset, error) {
 		if len(inSS.Spans) == 0 {
 			return nil, nil
@@ -111,6 +152,14 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 	iterator := fetchSpansResponse.Results
 	defer iterator.Close()
 
+	// the limit of the request has already been reconciled with the one of the query by the api
+	limit := int(searchReq.Limit)
+	if limit == 0 {
+		limit = rootExpr.Limit
+	}
+
+	order := SearchRequestOrder(searchReq, rootExpr)
+
 	res := &tempopb.SearchResponse{
 		Traces:  nil,
 		Metrics: &tempopb.SearchMetrics{},
@@ -124,13 +173,31 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		if spanset == nil {
 			break
 		}
-		res.Traces = append(res.Traces, e.asTraceSearchMetadata(spanset))
+		metadata := e.asTraceSearchMetadata(spanset)
+		if !AfterSearchCursor(searchReq, metadata) {
+			continue
+		}
+		res.Traces = append(res.Traces, metadata)
 
-		if len(res.Traces) >= int(searchReq.Limit) && searchReq.Limit > 0 {
-			break
+		if limit <= 0 {
+			continue
+		}
+		if order == nil {
+			if len(res.Traces) >= limit {
+				break
+			}
+			continue
+		}
+		// ordered searches have to look at every trace. only keep the first limit traces to bound memory
+		if len(res.Traces) >= 2*limit {
+			res.Traces = TopTraces(res.Traces, order, limit)
 		}
 	}
 
+	if order != nil {
+		res.Traces = TopTraces(res.Traces, order, limit)
+	}
+
 	span.SetTag("spansets_evaluated", spansetsEvaluated)
 	span.SetTag("spansets_found", len(res.Traces))
 
@@ -144,6 +211,80 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 	return res, nil
 }
 
+// ParseSearch returns the parsed TraceQL query of a search or nil if it has none or it's invalid. The
+// order and hints of the search can be read from the result even if it's nil.
+func ParseSearch(searchReq *tempopb.SearchRequest) *RootExpr {
+	if searchReq.Query == "" {
+		return nil
+	}
+
+	rootExpr, err := Parse(searchReq.Query)
+	if err != nil {
+		return nil
+	}
+	return rootExpr
+}
+
+// SearchRequestOrder returns the order of the results of a search. Pages of a paginated search return
+// the most recent traces after the cursor, they are ordered like unordered results but have to look at
+// every trace.
+func SearchRequestOrder(searchReq *tempopb.SearchRequest, rootExpr *RootExpr) *OrderBy {
+	if searchReq.Paginated {
+		return &OrderBy{Key: OrderKeyStartTime, Desc: true}
+	}
+	return rootExpr.SearchOrder()
+}
+
+// AfterSearchCursor returns true if the trace is sorted after the last trace of the previous page of a
+// paginated search. It is always true for the first page and searches that aren't paginated.
+func AfterSearchCursor(searchReq *tempopb.SearchRequest, t *tempopb.TraceSearchMetadata) bool {
+	if !searchReq.Paginated || searchReq.AfterTraceID == "" {
+		return true
+	}
+	if t.StartTimeUnixNano != searchReq.AfterStartTimeUnixNano {
+		return t.StartTimeUnixNano < searchReq.AfterStartTimeUnixNano
+	}
+	return t.TraceID > searchReq.AfterTraceID
+}
+
+// SortTraces sorts search results by the order. Unordered results are sorted by start time with the most
+// recent traces first. Traces with equal values are sorted by ID so results are the same across shards.
+func SortTraces(traces []*tempopb.TraceSearchMetadata, order *OrderBy) {
+	sort.Slice(traces, func(i, j int) bool {
+		a, b := traces[i], traces[j]
+
+		if order == nil {
+			if a.StartTimeUnixNano != b.StartTimeUnixNano {
+				return a.StartTimeUnixNano > b.StartTimeUnixNano
+			}
+			return a.TraceID < b.TraceID
+		}
+
+		var av, bv uint64
+		switch order.Key {
+		case OrderKeyDuration:
+			av, bv = uint64(a.DurationMs), uint64(b.DurationMs)
+		case OrderKeyStartTime:
+			av, bv = a.StartTimeUnixNano, b.StartTimeUnixNano
+		}
+
+		if av != bv {
+			return (av < bv) != order.Desc
+		}
+		return a.TraceID < b.TraceID
+	})
+}
+
+// TopTraces sorts search results by the order and returns the first limit of them. If limit is 0 all
+// results are returned.
+func TopTraces(traces []*tempopb.TraceSearchMetadata, order *OrderBy, limit int) []*tempopb.TraceSearchMetadata {
+	SortTraces(traces, order)
+	if limit > 0 && len(traces) > limit {
+		traces = traces[:limit]
+	}
+	return traces
+}
+
 func (e *Engine) ExecuteTagValues(
 	ctx context.Context,
 	tag Attribute,
@@ -269,6 +410,9 @@ func (e *Engine) parseQuery(searchReq *tempopb.SearchRequest) (*RootExpr, error)
 	if err != nil {
 		return nil, err
 	}
+	if err := r.validateSearch(); err != nil {
+		return nil, err
+	}
 	return r, r.validate()
 }
 
@@ -315,14 +459,30 @@ func (e *Engine) asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMet
 		}
 
 		for attribute, static := range atts {
-			if attribute.Intrinsic == IntrinsicName || attribute.Intrinsic == IntrinsicDuration {
+			// parent attributes belong to another span
+			if attribute.Parent {
 				continue
 			}
 
+			if attribute.Intrinsic == IntrinsicName ||
+				attribute.Intrinsic == IntrinsicDuration ||
+				attribute.Intrinsic == IntrinsicTraceDuration ||
+				attribute.Intrinsic == IntrinsicTraceRootService ||
+				attribute.Intrinsic == IntrinsicTraceRootSpan {
+				continue
+			}
+
+			// event, link and instrumentation attributes are kept apart from the span's own attributes
+			key := attribute.Name
+			switch attribute.Scope {
+			case AttributeScopeEvent, AttributeScopeLink, AttributeScopeInstrumentation:
+				key = attribute.Scope.String() + "." + attribute.Name
+			}
+
 			staticAnyValue := static.asAnyValue()
 
 			keyValue := &common_v1.KeyValue{
-				Key:   attribute.Name,
+				Key:   key,
 				Value: staticAnyValue,
 			}
 
@@ -403,6 +563,19 @@ func (s Static) asAnyValue() *common_v1.AnyValue {
 				StringValue: s.Kind.String(),
 			},
 		}
+	case TypeArray:
+		elems := s.Elements()
+		values := make([]*common_v1.AnyValue, 0, len(elems))
+		for _, e := range elems {
+			values = append(values, e.asAnyValue())
+		}
+		return &common_v1.AnyValue{
+			Value: &common_v1.AnyValue_ArrayValue{
+				ArrayValue: &common_v1.ArrayValue{
+					Values: values,
+				},
+			},
+		}
 	}
 
 	return &common_v1.AnyValue{
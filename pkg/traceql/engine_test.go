@@ -388,6 +388,178 @@ func newCondition(attr Attribute, op Operator, operands ...Static) Condition {
 	}
 }
 
+func TestEngine_ExecuteSelect(t *testing.T) {
+	e := NewEngine()
+
+	req := &tempopb.SearchRequest{
+		Query: "{ .foo = `a` } | select(span.http.status_code, resource.k8s.pod.name, duration)",
+	}
+	spanSetFetcher := MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{},
+	}
+	_, err := e.ExecuteSearch(context.Background(), req, &spanSetFetcher)
+	require.NoError(t, err)
+
+	// selected attributes are fetched in the second pass and don't change the first pass
+	expectedFetchSpansRequest := FetchSpansRequest{
+		Conditions: []Condition{
+			newCondition(NewAttribute("foo"), OpEqual, NewStaticString("a")),
+		},
+		AllConditions: true,
+		SecondPassConditions: append([]Condition{
+			newCondition(NewScopedAttribute(AttributeScopeSpan, false, "http.status_code"), OpNone),
+			newCondition(NewScopedAttribute(AttributeScopeResource, false, "k8s.pod.name"), OpNone),
+			newCondition(NewIntrinsic(IntrinsicDuration), OpNone),
+		}, SearchMetaConditionsWithoutDuration()...),
+	}
+	spanSetFetcher.capturedRequest.SecondPass = nil // have to set this to nil b/c assert.Equal does not handle function pointers
+	assert.Equal(t, expectedFetchSpansRequest, spanSetFetcher.capturedRequest)
+}
+
+func TestEngine_ExecuteOrderByLimit(t *testing.T) {
+	e := NewEngine()
+
+	newSpanset := func(id byte, start uint64, duration time.Duration) *Spanset {
+		return &Spanset{
+			TraceID:            []byte{id},
+			StartTimeUnixNanos: start,
+			DurationNanos:      uint64(duration.Nanoseconds()),
+			Spans:              []Span{&mockSpan{id: []byte{id}}},
+		}
+	}
+
+	tcs := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "{ } | order by duration desc | limit 2", expected: []string{"3", "1"}},
+		{query: "{ } | order by duration | limit 2", expected: []string{"4", "2"}},
+		{query: "{ } | order by startTime desc", expected: []string{"4", "3", "2", "1"}},
+		{query: "{ } | limit 3", expected: []string{"1", "2", "3"}}, // unordered searches stop at the limit
+		{query: "{ } | limit 2 with (most_recent=true)", expected: []string{"4", "3"}},
+		{query: "{ } | limit 2 with (exhaustive=true)", expected: []string{"4", "3"}},
+		{query: "{ } | order by duration | limit 2 with (most_recent=true)", expected: []string{"4", "2"}}, // order by takes precedence
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			spanSetFetcher := MockSpanSetFetcher{
+				iterator: &MockSpanSetIterator{
+					results: []*Spanset{
+						newSpanset(1, 1, 3*time.Second),
+						newSpanset(2, 2, 2*time.Second),
+						newSpanset(3, 3, 5*time.Second),
+						newSpanset(4, 4, time.Second),
+					},
+				},
+			}
+
+			response, err := e.ExecuteSearch(context.Background(), &tempopb.SearchRequest{Query: tc.query}, &spanSetFetcher)
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(response.Traces))
+			for _, tr := range response.Traces {
+				actual = append(actual, tr.TraceID)
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestEngine_ExecutePaginated(t *testing.T) {
+	e := NewEngine()
+
+	newSpanset := func(id byte, start uint64) *Spanset {
+		return &Spanset{
+			TraceID:            []byte{id},
+			StartTimeUnixNanos: start,
+			Spans:              []Span{&mockSpan{id: []byte{id}}},
+		}
+	}
+
+	tcs := []struct {
+		name     string
+		req      *tempopb.SearchRequest
+		expected []string
+	}{
+		{name: "first page", req: &tempopb.SearchRequest{Paginated: true}, expected: []string{"4", "3", "5"}},
+		{name: "after cursor", req: &tempopb.SearchRequest{Paginated: true, AfterStartTimeUnixNano: 3, AfterTraceID: "3"}, expected: []string{"5", "2", "1"}},
+		{name: "last page", req: &tempopb.SearchRequest{Paginated: true, AfterStartTimeUnixNano: 1, AfterTraceID: "1"}, expected: []string{}},
+		{name: "not paginated", req: &tempopb.SearchRequest{AfterStartTimeUnixNano: 3, AfterTraceID: "3"}, expected: []string{"1", "2", "3"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			spanSetFetcher := MockSpanSetFetcher{
+				iterator: &MockSpanSetIterator{
+					results: []*Spanset{
+						newSpanset(1, 1),
+						newSpanset(2, 2),
+						newSpanset(3, 3),
+						newSpanset(4, 4),
+						newSpanset(5, 3),
+					},
+				},
+			}
+
+			tc.req.Query = "{ }"
+			tc.req.Limit = 3
+			response, err := e.ExecuteSearch(context.Background(), tc.req, &spanSetFetcher)
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(response.Traces))
+			for _, tr := range response.Traces {
+				actual = append(actual, tr.TraceID)
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestEngine_ExecuteSample(t *testing.T) {
+	e := NewEngine()
+
+	req := &tempopb.SearchRequest{
+		Query: "{ .foo = `a` } with (sample=0.25)",
+	}
+	spanSetFetcher := MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{},
+	}
+	_, err := e.ExecuteSearch(context.Background(), req, &spanSetFetcher)
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, spanSetFetcher.capturedRequest.Sample)
+}
+
+func TestSortTraces(t *testing.T) {
+	traces := func() []*tempopb.TraceSearchMetadata {
+		return []*tempopb.TraceSearchMetadata{
+			{TraceID: "1", StartTimeUnixNano: 1, DurationMs: 30},
+			{TraceID: "2", StartTimeUnixNano: 3, DurationMs: 10},
+			{TraceID: "3", StartTimeUnixNano: 2, DurationMs: 30},
+		}
+	}
+	ids := func(traces []*tempopb.TraceSearchMetadata) []string {
+		ids := make([]string, 0, len(traces))
+		for _, tr := range traces {
+			ids = append(ids, tr.TraceID)
+		}
+		return ids
+	}
+
+	// unordered results are most recent first
+	actual := traces()
+	SortTraces(actual, nil)
+	assert.Equal(t, []string{"2", "3", "1"}, ids(actual))
+
+	// equal values are sorted by trace id
+	actual = traces()
+	SortTraces(actual, &OrderBy{Key: OrderKeyDuration, Desc: true})
+	assert.Equal(t, []string{"1", "3", "2"}, ids(actual))
+
+	assert.Equal(t, []string{"2", "1"}, ids(TopTraces(traces(), &OrderBy{Key: OrderKeyDuration}, 2)))
+	assert.Equal(t, []string{"1", "3", "2"}, ids(TopTraces(traces(), &OrderBy{Key: OrderKeyStartTime}, 0)))
+}
+
 func TestUnixSecToNano(t *testing.T) {
 	now := time.Now()
 	// tolerate delta's up to 1 second
@@ -10,6 +10,8 @@ const (
 	aggregateMin
 	aggregateSum
 	aggregateAvg
+	aggregateQuantile
+	aggregateCountDistinct
 )
 
 func (a AggregateOp) String() string {
@@ -25,7 +27,52 @@ func (a AggregateOp) String() string {
 		return "sum"
 	case aggregateAvg:
 		return "avg"
+	case aggregateQuantile:
+		return "quantile"
+	case aggregateCountDistinct:
+		return "count_distinct"
 	}
 
 	return fmt.Sprintf("aggregate(%d)", a)
 }
+
+type MetricsAggregateOp int
+
+const (
+	MetricsAggregateRate MetricsAggregateOp = iota
+	MetricsAggregateCountOverTime
+	MetricsAggregateQuantileOverTime
+)
+
+func (a MetricsAggregateOp) String() string {
+
+	switch a {
+	case MetricsAggregateRate:
+		return "rate"
+	case MetricsAggregateCountOverTime:
+		return "count_over_time"
+	case MetricsAggregateQuantileOverTime:
+		return "quantile_over_time"
+	}
+
+	return fmt.Sprintf("metricsAggregate(%d)", a)
+}
+
+// OrderKey is the trace level value search results are ordered by
+type OrderKey int
+
+const (
+	OrderKeyDuration OrderKey = iota
+	OrderKeyStartTime
+)
+
+func (k OrderKey) String() string {
+	switch k {
+	case OrderKeyDuration:
+		return "duration"
+	case OrderKeyStartTime:
+		return "startTime"
+	}
+
+	return fmt.Sprintf("orderKey(%d)", k)
+}
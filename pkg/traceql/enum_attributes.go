@@ -8,11 +8,16 @@ const (
 	AttributeScopeNone AttributeScope = iota
 	AttributeScopeResource
 	AttributeScopeSpan
+	AttributeScopeEvent
+	AttributeScopeLink
+	AttributeScopeInstrumentation
 	AttributeScopeUnknown
 
 	none = "none"
 )
 
+// AllAttributeScopes returns the scopes searched by the tags endpoints when no scope is given. The
+// instrumentation scope is left out to keep the default output stable and must be requested explicitly.
 func AllAttributeScopes() []AttributeScope {
 	return []AttributeScope{AttributeScopeResource, AttributeScopeSpan}
 }
@@ -25,6 +30,12 @@ func (s AttributeScope) String() string {
 		return "span"
 	case AttributeScopeResource:
 		return "resource"
+	case AttributeScopeEvent:
+		return "event"
+	case AttributeScopeLink:
+		return "link"
+	case AttributeScopeInstrumentation:
+		return "instrumentation"
 	}
 
 	return fmt.Sprintf("att(%d).", s)
@@ -36,6 +47,12 @@ func AttributeScopeFromString(s string) AttributeScope {
 		return AttributeScopeSpan
 	case "resource":
 		return AttributeScopeResource
+	case "event":
+		return AttributeScopeEvent
+	case "link":
+		return AttributeScopeLink
+	case "instrumentation":
+		return AttributeScopeInstrumentation
 	case "":
 		fallthrough
 	case none:
@@ -54,19 +71,22 @@ const (
 	IntrinsicStatus
 	IntrinsicKind
 	IntrinsicChildCount
-
-	// not yet implemented in traceql but will be
-	IntrinsicParent
 	IntrinsicTraceRootService
 	IntrinsicTraceRootSpan
 	IntrinsicTraceDuration
 
+	// not yet implemented in traceql but will be
+	IntrinsicParent
+
 	// not yet implemented in traceql and may never be. these exist so that we can retrieve
 	// these fields from the fetch layer
 	IntrinsicTraceID
 	IntrinsicTraceStartTime
 	IntrinsicSpanID
 	IntrinsicSpanStartTime
+	IntrinsicStructuralDescendant
+	IntrinsicStructuralChild
+	IntrinsicStructuralSibling
 )
 
 func (i Intrinsic) String() string {
@@ -83,15 +103,15 @@ func (i Intrinsic) String() string {
 		return "kind"
 	case IntrinsicChildCount:
 		return "childCount"
-	// below is unimplemented
-	case IntrinsicParent:
-		return "parent"
 	case IntrinsicTraceRootService:
-		return "traceRootService"
+		return "rootServiceName"
 	case IntrinsicTraceRootSpan:
-		return "traceRootSpan"
+		return "rootName"
 	case IntrinsicTraceDuration:
 		return "traceDuration"
+	// below is unimplemented
+	case IntrinsicParent:
+		return "parent"
 	case IntrinsicTraceID:
 		return "traceID"
 	case IntrinsicTraceStartTime:
@@ -100,6 +120,12 @@ func (i Intrinsic) String() string {
 		return "spanID"
 	case IntrinsicSpanStartTime:
 		return "spanStartTime"
+	case IntrinsicStructuralDescendant:
+		return "structuralDescendant"
+	case IntrinsicStructuralChild:
+		return "structuralChild"
+	case IntrinsicStructuralSibling:
+		return "structuralSibling"
 	}
 
 	return fmt.Sprintf("intrinsic(%d)", i)
@@ -118,15 +144,15 @@ func intrinsicFromString(s string) Intrinsic {
 		return IntrinsicKind
 	case "childCount":
 		return IntrinsicChildCount
-	// unimplemented
-	case "parent":
-		return IntrinsicParent
-	case "traceRootService":
+	case "rootServiceName":
 		return IntrinsicTraceRootService
-	case "traceRootSpan":
+	case "rootName":
 		return IntrinsicTraceRootSpan
 	case "traceDuration":
 		return IntrinsicTraceDuration
+	// unimplemented
+	case "parent":
+		return IntrinsicParent
 	case "traceID":
 		return IntrinsicTraceID
 	case "traceStartTime":
@@ -7,7 +7,7 @@ import (
 )
 
 func TestAllAttributesString(t *testing.T) {
-	for _, s := range AllAttributeScopes() {
+	for _, s := range append(AllAttributeScopes(), AttributeScopeEvent, AttributeScopeLink, AttributeScopeInstrumentation) {
 		actual := AttributeScopeFromString(s.String())
 		require.Equal(t, s, actual)
 	}
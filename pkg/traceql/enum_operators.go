@@ -28,6 +28,18 @@ const (
 	OpSpansetAnd
 	OpSpansetUnion
 	OpSpansetSibling
+	OpSpansetParent
+	OpSpansetAncestor
+	OpSpansetNotChild
+	OpSpansetNotDescendant
+	OpSpansetNotSibling
+	OpContains
+	OpEqualFold
+	OpStartsWith
+	OpEndsWith
+	OpSubstring
+	OpLower
+	OpUpper
 )
 
 func (op Operator) isBoolean() bool {
@@ -41,7 +53,30 @@ func (op Operator) isBoolean() bool {
 		op == OpGreaterEqual ||
 		op == OpLess ||
 		op == OpLessEqual ||
-		op == OpNot
+		op == OpNot ||
+		op == OpContains ||
+		op == OpEqualFold ||
+		op == OpStartsWith ||
+		op == OpEndsWith ||
+		op == OpSubstring
+}
+
+// isFunction returns true if the operator is written as a function call, i.e. startsWith(.foo, "bar")
+func (op Operator) isFunction() bool {
+	return op == OpStartsWith ||
+		op == OpEndsWith ||
+		op == OpSubstring ||
+		op == OpLower ||
+		op == OpUpper
+}
+
+// funcName returns the name the function is called by in a query. substring shares its name with the
+// contains operator of arrays and is told apart by the function call syntax
+func (op Operator) funcName() string {
+	if op == OpSubstring {
+		return "contains"
+	}
+	return op.String()
 }
 
 func (op Operator) binaryTypesValid(lhsT StaticType, rhsT StaticType) bool {
@@ -53,7 +88,18 @@ func binaryTypeValid(op Operator, t StaticType) bool {
 		return true
 	}
 
+	// contains tests an array for a scalar element. the array side is always an attribute
+	// which is checked above, so here we only need to accept the element types
+	if op == OpContains {
+		return t == TypeInt ||
+			t == TypeFloat ||
+			t == TypeString ||
+			t == TypeBoolean
+	}
+
 	switch t {
+	case TypeArray:
+		return op == OpEqual || op == OpNotEqual
 	case TypeBoolean:
 		return op == OpAnd ||
 			op == OpOr ||
@@ -81,6 +127,10 @@ func binaryTypeValid(op Operator, t StaticType) bool {
 			op == OpNotEqual ||
 			op == OpRegex ||
 			op == OpNotRegex ||
+			op == OpEqualFold ||
+			op == OpStartsWith ||
+			op == OpEndsWith ||
+			op == OpSubstring ||
 			op == OpGreater ||
 			op == OpGreaterEqual ||
 			op == OpLess ||
@@ -106,6 +156,8 @@ func (op Operator) unaryTypesValid(t StaticType) bool {
 		return t.isNumeric()
 	case OpNot:
 		return t == TypeBoolean
+	case OpLower, OpUpper:
+		return t == TypeString
 	}
 
 	return false
@@ -158,6 +210,30 @@ func (op Operator) String() string {
 		return "~"
 	case OpSpansetUnion:
 		return "||"
+	case OpSpansetParent:
+		return "<"
+	case OpSpansetAncestor:
+		return "<<"
+	case OpSpansetNotChild:
+		return "!>"
+	case OpSpansetNotDescendant:
+		return "!>>"
+	case OpSpansetNotSibling:
+		return "!~"
+	case OpContains:
+		return "contains"
+	case OpEqualFold:
+		return "=*"
+	case OpStartsWith:
+		return "startsWith"
+	case OpEndsWith:
+		return "endsWith"
+	case OpSubstring:
+		return "substring"
+	case OpLower:
+		return "lower"
+	case OpUpper:
+		return "upper"
 	}
 
 	return fmt.Sprintf("operator(%d)", op)
@@ -33,6 +33,12 @@ func TestOperatorIsBoolean(t *testing.T) {
 		{OpSpansetAnd, false},
 		{OpSpansetUnion, false},
 		{OpSpansetSibling, false},
+		{OpEqualFold, true},
+		{OpStartsWith, true},
+		{OpEndsWith, true},
+		{OpSubstring, true},
+		{OpLower, false},
+		{OpUpper, false},
 	}
 
 	for _, tc := range tt {
@@ -95,6 +101,14 @@ func TestOperatorBinaryTypesValid(t *testing.T) {
 		{OpRegex, TypeKind, false},
 		{OpRegex, TypeInt, false},
 		{OpNotRegex, TypeInt, false},
+		{OpEqualFold, TypeString, true},
+		{OpStartsWith, TypeString, true},
+		{OpEndsWith, TypeAttribute, true},
+		{OpSubstring, TypeString, true},
+
+		{OpEqualFold, TypeInt, false},
+		{OpStartsWith, TypeStatus, false},
+		{OpSubstring, TypeBoolean, false},
 		// boolean
 		{OpAnd, TypeBoolean, true},
 		{OpOr, TypeAttribute, true},
@@ -157,6 +171,11 @@ func TestOperatorUnaryTypesValid(t *testing.T) {
 		{OpSub, TypeStatus, false},
 		{OpSub, TypeNil, false},
 		{OpSub, TypeSpanset, false},
+		// string functions
+		{OpLower, TypeString, true},
+		{OpUpper, TypeString, true},
+		{OpLower, TypeInt, false},
+		{OpUpper, TypeNil, false},
 	}
 
 	for _, tc := range tt {
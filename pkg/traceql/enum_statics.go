@@ -15,6 +15,7 @@ const (
 	TypeDuration
 	TypeStatus
 	TypeKind
+	TypeArray // a list of statics. only produced by attributes, there are no array literals
 )
 
 // isMatchingOperand returns whether two types can be combined with a binary operator. the kind of operator is
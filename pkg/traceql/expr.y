@@ -12,6 +12,13 @@ import (
     root RootExpr
     groupOperation GroupOperation
     coalesceOperation CoalesceOperation
+    selectOperation SelectOperation
+    attributeList []Attribute
+    metricsAggregation MetricsAggregate
+    orderBy OrderBy
+    orderKey OrderKey
+    hint *Hint
+    hintList []*Hint
 
     spansetExpression SpansetExpression
     spansetPipelineExpression SpansetExpression
@@ -41,8 +48,17 @@ import (
 }
 
 %type <RootExpr> root
+%type <RootExpr> query
+%type <hint> hint
+%type <hintList> hintList
 %type <groupOperation> groupOperation
 %type <coalesceOperation> coalesceOperation
+%type <selectOperation> selectOperation
+%type <attributeList> attributeList
+%type <metricsAggregation> metricsAggregation
+%type <orderBy> orderBy
+%type <orderKey> orderKey
+%type <staticInt> limit
 
 %type <spansetExpression> spansetExpression
 %type <spansetPipelineExpression> spansetPipelineExpression
@@ -60,6 +76,7 @@ import (
 %type <aggregate> aggregate 
 
 %type <fieldExpression> fieldExpression
+%type <fieldExpression> stringFunction
 %type <static> static
 %type <intrinsicField> intrinsicField
 %type <attributeField> attributeField
@@ -68,19 +85,24 @@ import (
 %token <staticInt>      INTEGER
 %token <staticFloat>    FLOAT
 %token <staticDuration> DURATION
-%token <val>            DOT OPEN_BRACE CLOSE_BRACE OPEN_PARENS CLOSE_PARENS
+%token <val>            DOT OPEN_BRACE CLOSE_BRACE OPEN_PARENS CLOSE_PARENS OPEN_BRACKET CLOSE_BRACKET COMMA
                         NIL TRUE FALSE STATUS_ERROR STATUS_OK STATUS_UNSET
                         KIND_UNSPECIFIED KIND_INTERNAL KIND_SERVER KIND_CLIENT KIND_PRODUCER KIND_CONSUMER
                         IDURATION CHILDCOUNT NAME STATUS PARENT KIND
-                        PARENT_DOT RESOURCE_DOT SPAN_DOT
-                        COUNT AVG MAX MIN SUM
-                        BY COALESCE
+                        TRACE_DURATION ROOT_NAME ROOT_SERVICE_NAME
+                        PARENT_DOT RESOURCE_DOT SPAN_DOT EVENT_DOT LINK_DOT INSTRUMENTATION_DOT
+                        COUNT AVG MAX MIN SUM QUANTILE COUNT_DISTINCT
+                        BY COALESCE SELECT
+                        RATE COUNT_OVER_TIME QUANTILE_OVER_TIME
+                        ORDER ASC DESCENDING LIMIT START_TIME
+                        STARTS_WITH ENDS_WITH LOWER UPPER
+                        WITH
                         END_ATTRIBUTE
 
 // Operators are listed with increasing precedence.
 %left <binOp> PIPE
 %left <binOp> AND OR
-%left <binOp> EQ NEQ LT LTE GT GTE NRE RE DESC TILDE
+%left <binOp> EQ NEQ LT LTE GT GTE NRE RE DESC ANCE TILDE NOT_CHILD NOT_DESC CONTAINS EQ_FOLD
 %left <binOp> ADD SUB
 %left <binOp> NOT
 %left <binOp> MUL DIV MOD
@@ -91,9 +113,49 @@ import (
 // Pipeline
 // **********************
 root:
+    query
+  | query WITH OPEN_PARENS hintList CLOSE_PARENS { yylex.(*lexer).expr.Hints = newHints($4) }
+  ;
+
+query:
     spansetPipeline                             { yylex.(*lexer).expr = newRootExpr($1) }
   | spansetPipelineExpression                   { yylex.(*lexer).expr = newRootExpr($1) }
   | scalarPipelineExpressionFilter              { yylex.(*lexer).expr = newRootExpr($1) }
+  | spansetPipeline PIPE metricsAggregation     { yylex.(*lexer).expr = newRootExprWithMetrics($1, $3) }
+  | spansetPipeline PIPE orderBy                { yylex.(*lexer).expr = newRootExprWithOrder($1, &$3, 0) }
+  | spansetPipeline PIPE limit                  { yylex.(*lexer).expr = newRootExprWithOrder($1, nil, $3) }
+  | spansetPipeline PIPE orderBy PIPE limit     { yylex.(*lexer).expr = newRootExprWithOrder($1, &$3, $5) }
+  ;
+
+// **********************
+// Hints
+// **********************
+hintList:
+    hint                                        { $$ = []*Hint{$1} }
+  | hintList COMMA hint                         { $$ = append($1, $3) }
+  ;
+
+hint:
+    IDENTIFIER EQ static                        { $$ = newHint($1, $3) }
+  ;
+
+// **********************
+// Ordering
+// **********************
+orderBy:
+    ORDER BY orderKey                           { $$ = newOrderBy($3, false) }
+  | ORDER BY orderKey ASC                       { $$ = newOrderBy($3, false) }
+  | ORDER BY orderKey DESCENDING                { $$ = newOrderBy($3, true) }
+  ;
+
+limit:
+    LIMIT INTEGER                               { if $2 == 0 { yylex.Error("limit must be greater than 0") }; $$ = $2 }
+  ;
+
+orderKey:
+    IDURATION                                   { $$ = OrderKeyDuration }
+  | TRACE_DURATION                              { $$ = OrderKeyDuration }
+  | START_TIME                                  { $$ = OrderKeyStartTime }
   ;
 
 // **********************
@@ -106,6 +168,11 @@ spansetPipelineExpression: // shares the same operators as spansetExpression. sp
   | spansetPipelineExpression DESC  spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetDescendant, $1, $3) }
   | spansetPipelineExpression OR    spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetUnion, $1, $3) }
   | spansetPipelineExpression TILDE spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetSibling, $1, $3) }
+  | spansetPipelineExpression LT    spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetParent, $1, $3) }
+  | spansetPipelineExpression ANCE  spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetAncestor, $1, $3) }
+  | spansetPipelineExpression NOT_CHILD spansetPipelineExpression { $$ = newSpansetOperation(OpSpansetNotChild, $1, $3) }
+  | spansetPipelineExpression NOT_DESC  spansetPipelineExpression { $$ = newSpansetOperation(OpSpansetNotDescendant, $1, $3) }
+  | spansetPipelineExpression NRE   spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetNotSibling, $1, $3) }
   | wrappedSpansetPipeline                                       { $$ = $1 }
   ;
 
@@ -120,6 +187,7 @@ spansetPipeline:
   | spansetPipeline PIPE scalarFilter          { $$ = $1.addItem($3)  }
   | spansetPipeline PIPE groupOperation        { $$ = $1.addItem($3)  }
   | spansetPipeline PIPE coalesceOperation     { $$ = $1.addItem($3)  }
+  | spansetPipeline PIPE selectOperation       { $$ = $1.addItem($3)  }
   ;
 
 groupOperation:
@@ -130,6 +198,31 @@ coalesceOperation:
     COALESCE OPEN_PARENS CLOSE_PARENS           { $$ = newCoalesceOperation() }
   ;
 
+selectOperation:
+    SELECT OPEN_PARENS attributeList CLOSE_PARENS { $$ = newSelectOperation($3) }
+  ;
+
+// **********************
+// Metrics
+// **********************
+metricsAggregation:
+    RATE OPEN_PARENS CLOSE_PARENS                                                            { $$ = newMetricsAggregate(MetricsAggregateRate, nil) }
+  | RATE OPEN_PARENS CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                  { $$ = newMetricsAggregate(MetricsAggregateRate, $6) }
+  | COUNT_OVER_TIME OPEN_PARENS CLOSE_PARENS                                                 { $$ = newMetricsAggregate(MetricsAggregateCountOverTime, nil) }
+  | COUNT_OVER_TIME OPEN_PARENS CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS       { $$ = newMetricsAggregate(MetricsAggregateCountOverTime, $6) }
+  | QUANTILE_OVER_TIME OPEN_PARENS intrinsicField COMMA FLOAT CLOSE_PARENS                   { $$ = newQuantileOverTime($3, $5, nil) }
+  | QUANTILE_OVER_TIME OPEN_PARENS intrinsicField COMMA FLOAT CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS { $$ = newQuantileOverTime($3, $5, $9) }
+  | QUANTILE_OVER_TIME OPEN_PARENS intrinsicField COMMA INTEGER CLOSE_PARENS                 { $$ = newQuantileOverTime($3, float64($5), nil) }
+  | QUANTILE_OVER_TIME OPEN_PARENS intrinsicField COMMA INTEGER CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS { $$ = newQuantileOverTime($3, float64($5), $9) }
+  ;
+
+attributeList:
+    attributeField                              { $$ = []Attribute{$1} }
+  | intrinsicField                              { $$ = []Attribute{$1} }
+  | attributeList COMMA attributeField          { $$ = append($1, $3) }
+  | attributeList COMMA intrinsicField          { $$ = append($1, $3) }
+  ;
+
 spansetExpression: // shares the same operators as scalarPipelineExpression. split out for readability
     OPEN_PARENS spansetExpression CLOSE_PARENS   { $$ = $2 }
   | spansetExpression AND   spansetExpression    { $$ = newSpansetOperation(OpSpansetAnd, $1, $3) }
@@ -137,6 +230,11 @@ spansetExpression: // shares the same operators as scalarPipelineExpression. spl
   | spansetExpression DESC  spansetExpression    { $$ = newSpansetOperation(OpSpansetDescendant, $1, $3) }
   | spansetExpression OR    spansetExpression    { $$ = newSpansetOperation(OpSpansetUnion, $1, $3) }
   | spansetExpression TILDE spansetExpression    { $$ = newSpansetOperation(OpSpansetSibling, $1, $3) }
+  | spansetExpression LT    spansetExpression    { $$ = newSpansetOperation(OpSpansetParent, $1, $3) }
+  | spansetExpression ANCE  spansetExpression    { $$ = newSpansetOperation(OpSpansetAncestor, $1, $3) }
+  | spansetExpression NOT_CHILD spansetExpression { $$ = newSpansetOperation(OpSpansetNotChild, $1, $3) }
+  | spansetExpression NOT_DESC  spansetExpression { $$ = newSpansetOperation(OpSpansetNotDescendant, $1, $3) }
+  | spansetExpression NRE   spansetExpression    { $$ = newSpansetOperation(OpSpansetNotSibling, $1, $3) }
   | spansetFilter                                { $$ = $1 } 
   ;
 
@@ -208,6 +306,9 @@ aggregate:
   | MIN OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateMin, $3) }
   | AVG OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateAvg, $3) }
   | SUM OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateSum, $3) }
+  | QUANTILE OPEN_PARENS fieldExpression COMMA FLOAT CLOSE_PARENS    { $$ = newQuantileAggregate($3, $5) }
+  | QUANTILE OPEN_PARENS fieldExpression COMMA INTEGER CLOSE_PARENS  { $$ = newQuantileAggregate($3, float64($5)) }
+  | COUNT_DISTINCT OPEN_PARENS fieldExpression CLOSE_PARENS          { $$ = newAggregate(aggregateCountDistinct, $3) }
   ;
 
 // **********************
@@ -228,6 +329,8 @@ fieldExpression:
   | fieldExpression GTE fieldExpression      { $$ = newBinaryOperation(OpGreaterEqual, $1, $3) }
   | fieldExpression RE fieldExpression       { $$ = newBinaryOperation(OpRegex, $1, $3) }
   | fieldExpression NRE fieldExpression      { $$ = newBinaryOperation(OpNotRegex, $1, $3) }
+  | fieldExpression CONTAINS fieldExpression { $$ = newBinaryOperation(OpContains, $1, $3) }
+  | fieldExpression EQ_FOLD fieldExpression  { $$ = newBinaryOperation(OpEqualFold, $1, $3) }
   | fieldExpression POW fieldExpression      { $$ = newBinaryOperation(OpPower, $1, $3) }
   | fieldExpression AND fieldExpression      { $$ = newBinaryOperation(OpAnd, $1, $3) }
   | fieldExpression OR fieldExpression       { $$ = newBinaryOperation(OpOr, $1, $3) }
@@ -236,6 +339,16 @@ fieldExpression:
   | static                                   { $$ = $1 }
   | intrinsicField                           { $$ = $1 }
   | attributeField                           { $$ = $1 }
+  | attributeField OPEN_BRACKET INTEGER CLOSE_BRACKET { $$ = newIndexOperation($1, $3) }
+  | stringFunction                           { $$ = $1 }
+  ;
+
+stringFunction:
+    LOWER OPEN_PARENS fieldExpression CLOSE_PARENS                             { $$ = newUnaryOperation(OpLower, $3) }
+  | UPPER OPEN_PARENS fieldExpression CLOSE_PARENS                             { $$ = newUnaryOperation(OpUpper, $3) }
+  | STARTS_WITH OPEN_PARENS fieldExpression COMMA fieldExpression CLOSE_PARENS { $$ = newBinaryOperation(OpStartsWith, $3, $5) }
+  | ENDS_WITH OPEN_PARENS fieldExpression COMMA fieldExpression CLOSE_PARENS   { $$ = newBinaryOperation(OpEndsWith, $3, $5) }
+  | CONTAINS OPEN_PARENS fieldExpression COMMA fieldExpression CLOSE_PARENS    { $$ = newBinaryOperation(OpSubstring, $3, $5) }
   ;
 
 // **********************
@@ -261,18 +374,24 @@ static:
   ;
 
 intrinsicField:
-    IDURATION      { $$ = NewIntrinsic(IntrinsicDuration)   }
-  | CHILDCOUNT     { $$ = NewIntrinsic(IntrinsicChildCount) }
-  | NAME           { $$ = NewIntrinsic(IntrinsicName)       }
-  | STATUS         { $$ = NewIntrinsic(IntrinsicStatus)     }
-  | KIND           { $$ = NewIntrinsic(IntrinsicKind)       }
-  | PARENT         { $$ = NewIntrinsic(IntrinsicParent)     }
+    IDURATION          { $$ = NewIntrinsic(IntrinsicDuration)         }
+  | CHILDCOUNT         { $$ = NewIntrinsic(IntrinsicChildCount)       }
+  | NAME               { $$ = NewIntrinsic(IntrinsicName)             }
+  | STATUS             { $$ = NewIntrinsic(IntrinsicStatus)           }
+  | KIND               { $$ = NewIntrinsic(IntrinsicKind)             }
+  | PARENT             { $$ = NewIntrinsic(IntrinsicParent)           }
+  | TRACE_DURATION     { $$ = NewIntrinsic(IntrinsicTraceDuration)    }
+  | ROOT_NAME          { $$ = NewIntrinsic(IntrinsicTraceRootSpan)    }
+  | ROOT_SERVICE_NAME  { $$ = NewIntrinsic(IntrinsicTraceRootService) }
   ;
 
 attributeField:
     DOT IDENTIFIER END_ATTRIBUTE                      { $$ = NewAttribute($2)                                      }
   | RESOURCE_DOT IDENTIFIER END_ATTRIBUTE             { $$ = NewScopedAttribute(AttributeScopeResource, false, $2) }
   | SPAN_DOT IDENTIFIER END_ATTRIBUTE                 { $$ = NewScopedAttribute(AttributeScopeSpan, false, $2)     }
+  | EVENT_DOT IDENTIFIER END_ATTRIBUTE                { $$ = NewScopedAttribute(AttributeScopeEvent, false, $2)    }
+  | LINK_DOT IDENTIFIER END_ATTRIBUTE                 { $$ = NewScopedAttribute(AttributeScopeLink, false, $2)     }
+  | INSTRUMENTATION_DOT IDENTIFIER END_ATTRIBUTE      { $$ = NewScopedAttribute(AttributeScopeInstrumentation, false, $2) }
   | PARENT_DOT IDENTIFIER END_ATTRIBUTE               { $$ = NewScopedAttribute(AttributeScopeNone, true, $2)      }
   | PARENT_DOT RESOURCE_DOT IDENTIFIER END_ATTRIBUTE  { $$ = NewScopedAttribute(AttributeScopeResource, true, $3)  }
   | PARENT_DOT SPAN_DOT IDENTIFIER END_ATTRIBUTE      { $$ = NewScopedAttribute(AttributeScopeSpan, true, $3)      }
@@ -1,22 +1,29 @@
-// Code generated by goyacc -o pkg/traceql/expr.y.go pkg/traceql/expr.y. DO NOT EDIT.
+// Code generated by goyacc -o expr.y.go expr.y. DO NOT EDIT.
 
-//line pkg/traceql/expr.y:2
+//line expr.y:2
 package traceql
 
 import __yyfmt__ "fmt"
 
-//line pkg/traceql/expr.y:2
+//line expr.y:2
 
 import (
 	"time"
 )
 
-//line pkg/traceql/expr.y:11
+//line expr.y:11
 type yySymType struct {
-	yys               int
-	root              RootExpr
-	groupOperation    GroupOperation
-	coalesceOperation CoalesceOperation
+	yys                int
+	root               RootExpr
+	groupOperation     GroupOperation
+	coalesceOperation  CoalesceOperation
+	selectOperation    SelectOperation
+	attributeList      []Attribute
+	metricsAggregation MetricsAggregate
+	orderBy            OrderBy
+	orderKey           OrderKey
+	hint               *Hint
+	hintList           []*Hint
 
 	spansetExpression         SpansetExpression
 	spansetPipelineExpression SpansetExpression
@@ -55,55 +62,85 @@ const OPEN_BRACE = 57352
 const CLOSE_BRACE = 57353
 const OPEN_PARENS = 57354
 const CLOSE_PARENS = 57355
-const NIL = 57356
-const TRUE = 57357
-const FALSE = 57358
-const STATUS_ERROR = 57359
-const STATUS_OK = 57360
-const STATUS_UNSET = 57361
-const KIND_UNSPECIFIED = 57362
-const KIND_INTERNAL = 57363
-const KIND_SERVER = 57364
-const KIND_CLIENT = 57365
-const KIND_PRODUCER = 57366
-const KIND_CONSUMER = 57367
-const IDURATION = 57368
-const CHILDCOUNT = 57369
-const NAME = 57370
-const STATUS = 57371
-const PARENT = 57372
-const KIND = 57373
-const PARENT_DOT = 57374
-const RESOURCE_DOT = 57375
-const SPAN_DOT = 57376
-const COUNT = 57377
-const AVG = 57378
-const MAX = 57379
-const MIN = 57380
-const SUM = 57381
-const BY = 57382
-const COALESCE = 57383
-const END_ATTRIBUTE = 57384
-const PIPE = 57385
-const AND = 57386
-const OR = 57387
-const EQ = 57388
-const NEQ = 57389
-const LT = 57390
-const LTE = 57391
-const GT = 57392
-const GTE = 57393
-const NRE = 57394
-const RE = 57395
-const DESC = 57396
-const TILDE = 57397
-const ADD = 57398
-const SUB = 57399
-const NOT = 57400
-const MUL = 57401
-const DIV = 57402
-const MOD = 57403
-const POW = 57404
+const OPEN_BRACKET = 57356
+const CLOSE_BRACKET = 57357
+const COMMA = 57358
+const NIL = 57359
+const TRUE = 57360
+const FALSE = 57361
+const STATUS_ERROR = 57362
+const STATUS_OK = 57363
+const STATUS_UNSET = 57364
+const KIND_UNSPECIFIED = 57365
+const KIND_INTERNAL = 57366
+const KIND_SERVER = 57367
+const KIND_CLIENT = 57368
+const KIND_PRODUCER = 57369
+const KIND_CONSUMER = 57370
+const IDURATION = 57371
+const CHILDCOUNT = 57372
+const NAME = 57373
+const STATUS = 57374
+const PARENT = 57375
+const KIND = 57376
+const TRACE_DURATION = 57377
+const ROOT_NAME = 57378
+const ROOT_SERVICE_NAME = 57379
+const PARENT_DOT = 57380
+const RESOURCE_DOT = 57381
+const SPAN_DOT = 57382
+const EVENT_DOT = 57383
+const LINK_DOT = 57384
+const INSTRUMENTATION_DOT = 57385
+const COUNT = 57386
+const AVG = 57387
+const MAX = 57388
+const MIN = 57389
+const SUM = 57390
+const QUANTILE = 57391
+const COUNT_DISTINCT = 57392
+const BY = 57393
+const COALESCE = 57394
+const SELECT = 57395
+const RATE = 57396
+const COUNT_OVER_TIME = 57397
+const QUANTILE_OVER_TIME = 57398
+const ORDER = 57399
+const ASC = 57400
+const DESCENDING = 57401
+const LIMIT = 57402
+const START_TIME = 57403
+const STARTS_WITH = 57404
+const ENDS_WITH = 57405
+const LOWER = 57406
+const UPPER = 57407
+const WITH = 57408
+const END_ATTRIBUTE = 57409
+const PIPE = 57410
+const AND = 57411
+const OR = 57412
+const EQ = 57413
+const NEQ = 57414
+const LT = 57415
+const LTE = 57416
+const GT = 57417
+const GTE = 57418
+const NRE = 57419
+const RE = 57420
+const DESC = 57421
+const ANCE = 57422
+const TILDE = 57423
+const NOT_CHILD = 57424
+const NOT_DESC = 57425
+const CONTAINS = 57426
+const EQ_FOLD = 57427
+const ADD = 57428
+const SUB = 57429
+const NOT = 57430
+const MUL = 57431
+const DIV = 57432
+const MOD = 57433
+const POW = 57434
 
 var yyToknames = [...]string{
 	"$end",
@@ -119,6 +156,9 @@ var yyToknames = [...]string{
 	"CLOSE_BRACE",
 	"OPEN_PARENS",
 	"CLOSE_PARENS",
+	"OPEN_BRACKET",
+	"CLOSE_BRACKET",
+	"COMMA",
 	"NIL",
 	"TRUE",
 	"FALSE",
@@ -137,16 +177,38 @@ var yyToknames = [...]string{
 	"STATUS",
 	"PARENT",
 	"KIND",
+	"TRACE_DURATION",
+	"ROOT_NAME",
+	"ROOT_SERVICE_NAME",
 	"PARENT_DOT",
 	"RESOURCE_DOT",
 	"SPAN_DOT",
+	"EVENT_DOT",
+	"LINK_DOT",
+	"INSTRUMENTATION_DOT",
 	"COUNT",
 	"AVG",
 	"MAX",
 	"MIN",
 	"SUM",
+	"QUANTILE",
+	"COUNT_DISTINCT",
 	"BY",
 	"COALESCE",
+	"SELECT",
+	"RATE",
+	"COUNT_OVER_TIME",
+	"QUANTILE_OVER_TIME",
+	"ORDER",
+	"ASC",
+	"DESCENDING",
+	"LIMIT",
+	"START_TIME",
+	"STARTS_WITH",
+	"ENDS_WITH",
+	"LOWER",
+	"UPPER",
+	"WITH",
 	"END_ATTRIBUTE",
 	"PIPE",
 	"AND",
@@ -160,7 +222,12 @@ var yyToknames = [...]string{
 	"NRE",
 	"RE",
 	"DESC",
+	"ANCE",
 	"TILDE",
+	"NOT_CHILD",
+	"NOT_DESC",
+	"CONTAINS",
+	"EQ_FOLD",
 	"ADD",
 	"SUB",
 	"NOT",
@@ -169,6 +236,7 @@ var yyToknames = [...]string{
 	"MOD",
 	"POW",
 }
+
 var yyStatenames = [...]string{}
 
 const yyEofCode = 1
@@ -176,208 +244,322 @@ const yyErrCode = 2
 const yyInitialStackSize = 16
 
 //line yacctab:1
-var yyExca = [...]int{
+var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 185,
-	13, 48,
-	-2, 56,
+	-1, 257,
+	13, 88,
+	-2, 96,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 600
-
-var yyAct = [...]int{
-
-	12, 5, 183, 2, 65, 6, 7, 16, 163, 42,
-	39, 38, 150, 151, 62, 152, 153, 154, 163, 57,
-	58, 49, 59, 60, 61, 62, 33, 126, 106, 26,
-	34, 36, 107, 108, 118, 120, 121, 122, 123, 155,
-	156, 157, 158, 159, 160, 162, 161, 216, 218, 150,
-	151, 217, 152, 153, 154, 163, 209, 140, 142, 143,
-	144, 145, 146, 147, 152, 153, 154, 163, 148, 208,
-	130, 166, 167, 168, 72, 73, 74, 78, 94, 207,
-	64, 66, 206, 77, 75, 76, 80, 79, 81, 82,
-	83, 84, 85, 86, 87, 88, 89, 90, 91, 93,
-	92, 97, 95, 96, 15, 125, 119, 176, 177, 178,
-	179, 181, 180, 57, 58, 175, 59, 60, 61, 62,
-	129, 180, 59, 60, 61, 62, 67, 68, 106, 182,
-	133, 113, 107, 108, 185, 26, 187, 46, 47, 48,
-	49, 125, 181, 105, 17, 18, 19, 28, 15, 104,
-	110, 29, 31, 172, 69, 189, 190, 191, 192, 193,
-	194, 195, 196, 197, 198, 199, 200, 201, 202, 203,
-	204, 126, 103, 21, 24, 22, 23, 25, 13, 111,
-	102, 101, 173, 174, 63, 42, 39, 42, 39, 187,
-	72, 73, 74, 78, 94, 20, 211, 66, 132, 77,
-	75, 76, 80, 79, 81, 82, 83, 84, 85, 86,
-	87, 88, 89, 90, 91, 93, 92, 97, 95, 96,
-	215, 50, 51, 52, 53, 54, 55, 56, 98, 99,
-	100, 57, 58, 210, 59, 60, 61, 62, 43, 214,
-	171, 170, 67, 68, 44, 45, 169, 46, 47, 48,
-	49, 164, 165, 155, 156, 157, 158, 159, 160, 162,
-	161, 213, 71, 150, 151, 70, 152, 153, 154, 163,
-	164, 165, 155, 156, 157, 158, 159, 160, 162, 161,
-	212, 41, 150, 151, 14, 152, 153, 154, 163, 4,
-	11, 9, 164, 165, 155, 156, 157, 158, 159, 160,
-	162, 161, 205, 109, 150, 151, 1, 152, 153, 154,
-	163, 164, 165, 155, 156, 157, 158, 159, 160, 162,
-	161, 188, 0, 150, 151, 0, 152, 153, 154, 163,
-	0, 0, 0, 164, 165, 155, 156, 157, 158, 159,
-	160, 162, 161, 149, 0, 150, 151, 0, 152, 153,
-	154, 163, 164, 165, 155, 156, 157, 158, 159, 160,
-	162, 161, 130, 0, 150, 151, 0, 152, 153, 154,
-	163, 0, 0, 0, 0, 0, 164, 165, 155, 156,
-	157, 158, 159, 160, 162, 161, 0, 0, 150, 151,
-	0, 152, 153, 154, 163, 50, 51, 52, 53, 54,
-	55, 128, 0, 0, 0, 57, 58, 0, 59, 60,
-	61, 62, 50, 51, 52, 53, 54, 55, 0, 0,
-	0, 0, 44, 45, 0, 46, 47, 48, 49, 17,
-	18, 19, 0, 15, 0, 186, 17, 18, 19, 0,
-	15, 0, 184, 0, 44, 45, 0, 46, 47, 48,
-	49, 0, 0, 0, 0, 0, 0, 0, 21, 24,
-	22, 23, 25, 13, 0, 21, 24, 22, 23, 25,
-	13, 17, 18, 19, 0, 15, 0, 8, 37, 3,
-	20, 0, 17, 18, 19, 0, 15, 20, 110, 0,
-	0, 0, 0, 127, 0, 124, 0, 0, 0, 0,
-	21, 24, 22, 23, 25, 13, 112, 114, 115, 116,
-	117, 21, 24, 22, 23, 25, 0, 17, 18, 19,
-	0, 0, 20, 141, 32, 35, 27, 30, 40, 10,
-	33, 0, 28, 20, 34, 36, 29, 31, 0, 0,
-	0, 0, 0, 0, 0, 0, 21, 24, 22, 23,
-	25, 32, 35, 27, 30, 0, 0, 33, 0, 28,
-	0, 34, 36, 29, 31, 0, 0, 0, 20, 0,
-	0, 0, 131, 134, 135, 136, 137, 138, 139, 72,
-	73, 74, 78, 0, 0, 0, 133, 0, 77, 75,
-	76, 80, 79, 81, 82, 83, 84, 85, 86, 87,
+const yyLast = 1129
+
+var yyAct = [...]int16{
+	84, 79, 85, 6, 313, 7, 8, 83, 137, 243,
+	255, 3, 17, 53, 201, 202, 203, 214, 199, 200,
+	52, 201, 202, 203, 214, 214, 71, 72, 13, 73,
+	74, 75, 76, 179, 138, 76, 139, 140, 56, 63,
+	179, 73, 74, 75, 76, 162, 164, 165, 166, 167,
+	168, 169, 170, 171, 172, 204, 205, 206, 207, 208,
+	209, 211, 210, 303, 175, 181, 245, 30, 212, 213,
+	199, 200, 319, 201, 202, 203, 214, 318, 287, 197,
+	286, 174, 217, 218, 219, 349, 60, 61, 62, 63,
+	285, 64, 65, 66, 67, 68, 69, 284, 174, 189,
+	191, 192, 193, 194, 195, 196, 71, 72, 283, 73,
+	74, 75, 76, 71, 72, 282, 73, 74, 75, 76,
+	281, 177, 58, 59, 29, 60, 61, 62, 63, 147,
+	236, 237, 238, 239, 240, 241, 316, 332, 333, 357,
+	356, 215, 216, 204, 205, 206, 207, 208, 209, 211,
+	210, 331, 251, 175, 330, 329, 212, 213, 199, 200,
+	249, 201, 202, 203, 214, 363, 348, 251, 335, 317,
+	220, 64, 65, 66, 67, 68, 69, 252, 353, 138,
+	362, 139, 140, 335, 352, 340, 71, 72, 257, 73,
+	74, 75, 76, 259, 58, 59, 339, 60, 61, 62,
+	63, 261, 262, 263, 264, 265, 266, 267, 268, 269,
+	270, 271, 272, 273, 274, 275, 276, 277, 278, 252,
+	324, 312, 215, 216, 204, 205, 206, 207, 208, 209,
+	211, 210, 290, 291, 292, 293, 294, 212, 213, 199,
+	200, 306, 201, 202, 203, 214, 54, 11, 355, 307,
+	354, 335, 309, 335, 304, 315, 334, 314, 310, 335,
+	53, 301, 53, 305, 302, 16, 235, 163, 359, 259,
+	178, 358, 342, 215, 216, 204, 205, 206, 207, 208,
+	209, 211, 210, 341, 311, 56, 254, 56, 212, 213,
+	199, 200, 253, 201, 202, 203, 214, 248, 64, 65,
+	66, 67, 68, 69, 180, 183, 184, 185, 186, 187,
+	188, 328, 327, 58, 59, 227, 60, 61, 62, 63,
+	138, 247, 139, 140, 336, 337, 338, 103, 104, 105,
+	106, 108, 107, 109, 110, 111, 346, 246, 345, 234,
+	233, 232, 315, 315, 314, 314, 350, 351, 231, 230,
+	228, 229, 182, 152, 280, 347, 134, 133, 132, 315,
+	315, 314, 314, 360, 361, 87, 88, 89, 93, 112,
+	131, 78, 80, 130, 129, 128, 127, 92, 90, 91,
+	95, 94, 96, 97, 98, 99, 100, 101, 102, 103,
+	104, 105, 106, 108, 107, 109, 110, 111, 118, 113,
+	114, 115, 116, 117, 77, 124, 125, 126, 250, 70,
+	244, 215, 216, 204, 205, 206, 207, 208, 209, 211,
+	210, 57, 121, 122, 119, 120, 212, 213, 199, 200,
+	289, 201, 202, 203, 214, 344, 343, 86, 87, 88,
+	89, 93, 112, 176, 123, 80, 288, 81, 82, 226,
+	92, 90, 91, 95, 94, 96, 97, 98, 99, 100,
+	101, 102, 103, 104, 105, 106, 108, 107, 109, 110,
+	111, 118, 113, 114, 115, 116, 117, 323, 326, 325,
+	225, 224, 223, 55, 222, 18, 19, 20, 221, 16,
+	15, 148, 5, 12, 10, 121, 122, 119, 120, 41,
+	44, 322, 308, 46, 136, 42, 135, 50, 142, 43,
+	47, 45, 48, 49, 141, 242, 2, 123, 1, 0,
+	81, 82, 321, 22, 25, 23, 24, 26, 27, 28,
+	215, 216, 204, 205, 206, 207, 208, 209, 211, 210,
+	0, 0, 0, 0, 0, 212, 213, 199, 200, 320,
+	201, 202, 203, 214, 215, 216, 204, 205, 206, 207,
+	208, 209, 211, 210, 0, 0, 21, 0, 0, 212,
+	213, 199, 200, 300, 201, 202, 203, 214, 215, 216,
+	204, 205, 206, 207, 208, 209, 211, 210, 0, 0,
+	0, 0, 0, 212, 213, 199, 200, 0, 201, 202,
+	203, 214, 299, 0, 0, 215, 216, 204, 205, 206,
+	207, 208, 209, 211, 210, 0, 0, 0, 0, 0,
+	212, 213, 199, 200, 298, 201, 202, 203, 214, 215,
+	216, 204, 205, 206, 207, 208, 209, 211, 210, 0,
+	0, 0, 0, 0, 212, 213, 199, 200, 297, 201,
+	202, 203, 214, 0, 0, 215, 216, 204, 205, 206,
+	207, 208, 209, 211, 210, 0, 0, 0, 0, 0,
+	212, 213, 199, 200, 296, 201, 202, 203, 214, 0,
+	215, 216, 204, 205, 206, 207, 208, 209, 211, 210,
+	0, 0, 0, 0, 0, 212, 213, 199, 200, 295,
+	201, 202, 203, 214, 215, 216, 204, 205, 206, 207,
+	208, 209, 211, 210, 0, 0, 0, 0, 0, 212,
+	213, 199, 200, 279, 201, 202, 203, 214, 0, 0,
+	215, 216, 204, 205, 206, 207, 208, 209, 211, 210,
+	0, 0, 0, 0, 0, 212, 213, 199, 200, 260,
+	201, 202, 203, 214, 0, 215, 216, 204, 205, 206,
+	207, 208, 209, 211, 210, 0, 0, 0, 0, 0,
+	212, 213, 199, 200, 198, 201, 202, 203, 214, 215,
+	216, 204, 205, 206, 207, 208, 209, 211, 210, 0,
+	0, 0, 0, 0, 212, 213, 199, 200, 0, 201,
+	202, 203, 214, 0, 0, 215, 216, 204, 205, 206,
+	207, 208, 209, 211, 210, 0, 0, 0, 0, 0,
+	212, 213, 199, 200, 0, 201, 202, 203, 214, 0,
+	0, 0, 215, 216, 204, 205, 206, 207, 208, 209,
+	211, 210, 0, 0, 0, 0, 0, 212, 213, 199,
+	200, 0, 201, 202, 203, 214, 18, 19, 20, 46,
+	16, 42, 148, 50, 0, 43, 47, 45, 48, 49,
+	0, 0, 0, 18, 19, 20, 0, 16, 0, 148,
+	0, 0, 0, 18, 19, 20, 0, 16, 0, 258,
+	0, 0, 0, 173, 22, 25, 23, 24, 26, 27,
+	28, 14, 149, 150, 143, 144, 145, 146, 0, 0,
+	147, 22, 25, 23, 24, 26, 27, 28, 14, 149,
+	150, 22, 25, 23, 24, 26, 27, 28, 14, 18,
+	19, 20, 0, 16, 0, 256, 0, 21, 18, 19,
+	20, 0, 16, 0, 9, 0, 18, 19, 20, 31,
+	34, 0, 190, 36, 21, 32, 0, 40, 0, 33,
+	37, 35, 38, 39, 21, 0, 0, 22, 25, 23,
+	24, 26, 27, 28, 14, 0, 22, 25, 23, 24,
+	26, 27, 28, 14, 22, 25, 23, 24, 26, 27,
+	28, 41, 44, 0, 0, 46, 0, 42, 0, 50,
+	0, 43, 47, 45, 48, 49, 0, 0, 0, 0,
+	21, 0, 31, 34, 0, 0, 36, 0, 32, 21,
+	40, 112, 33, 37, 35, 38, 39, 21, 36, 0,
+	32, 0, 40, 0, 33, 37, 35, 38, 39, 51,
+	4, 103, 104, 105, 106, 108, 107, 109, 110, 111,
+	118, 113, 114, 115, 116, 117, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 151, 153, 154, 155, 156, 157, 158, 159, 160,
+	161, 87, 88, 89, 93, 0, 0, 0, 182, 0,
+	0, 0, 0, 92, 90, 91, 95, 94, 96, 97,
+	98, 99, 100, 101, 102, 87, 88, 89, 93, 0,
+	0, 0, 0, 0, 0, 0, 0, 92, 90, 91,
+	95, 94, 96, 97, 98, 99, 100, 101, 102,
 }
-var yyPact = [...]int{
-
-	465, -1000, -14, 509, -1000, 507, -1000, -1000, 465, -1000,
-	366, -1000, 175, 172, -1000, 69, -1000, -1000, -1000, -1000,
-	222, 169, 168, 160, 137, 131, 138, 119, 119, 119,
-	119, 119, 94, 94, 94, 94, 94, 482, 128, 480,
-	388, 107, 349, 574, 118, 118, 118, 118, 118, 118,
-	-1000, -1000, -1000, -1000, -1000, -1000, 511, 511, 511, 511,
-	511, 511, 511, 185, -1000, 332, 185, 185, 185, -1000,
+
+var yyPact = [...]int16{
+	932, -1000, 58, -1, 943, -1000, 922, -1000, -1000, 932,
+	-1000, 227, -1000, 100, 392, -1000, 360, -1000, -1000, -1000,
+	-1000, 399, 364, 363, 362, 361, 358, 346, 345, 344,
+	850, 341, 341, 341, 341, 341, 341, 341, 341, 341,
+	341, 255, 255, 255, 255, 255, 255, 255, 255, 255,
+	255, 880, 85, 430, 108, 257, 20, 1076, 340, 340,
+	340, 340, 340, 340, -1000, -1000, -1000, -1000, -1000, -1000,
+	940, 940, 940, 940, 940, 940, 940, 433, -1000, 763,
+	433, 433, 433, -1000, -1000, 156, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, 242, 237, 236, 149, -1000, -1000,
-	-1000, 102, 185, 185, 185, 185, 507, -1000, -1000, -1000,
-	476, 117, 97, 430, -1000, -1000, 97, -1000, -24, 94,
-	-1000, -1000, -24, -1000, -1000, -1000, 138, -1000, -1000, -1000,
-	-1000, 188, -1000, 423, 78, 78, -41, -41, -41, -41,
-	-37, 511, 63, 63, -48, -48, -48, -48, 308, -1000,
-	185, 185, 185, 185, 185, 185, 185, 185, 185, 185,
-	185, 185, 185, 185, 185, 185, 289, 5, 5, 40,
-	37, 27, 14, 229, 192, -1000, 267, 248, 226, 207,
-	480, 57, 34, 92, 430, -1000, 423, -16, -1000, 5,
-	5, -54, -54, -54, -44, -44, -44, -44, -44, -44,
-	-44, -44, -54, -7, -7, -1000, -1000, -1000, -1000, -1000,
-	9, 6, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, 484, 480, 478, 477, 476, 445, 311, 337,
+	336, 329, 328, 327, -1000, -1000, -1000, 253, 433, 433,
+	433, 433, 433, 433, 406, -1000, -2, -1000, 922, -1000,
+	-1000, -1000, -1000, 325, 309, 285, 109, 402, 479, 280,
+	274, 955, 923, -1000, -1000, 955, -1000, -1000, -1000, -1000,
+	-1000, -1000, 786, 255, -1000, -1000, 786, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, 867, -1000, -1000, -1000, -1000,
+	36, -1000, 877, -3, -3, -53, -53, -53, -53, -60,
+	940, -48, -48, -57, -57, -57, -57, 736, -1000, 433,
+	433, 433, 433, 433, 433, 433, 433, 433, 433, 433,
+	433, 433, 433, 433, 433, 433, 433, 710, -75, -75,
+	348, 53, 48, 41, 30, 23, 13, 11, 442, 426,
+	433, 433, 433, 433, 433, -1000, 686, 661, 635, 611,
+	586, 560, 248, -1000, -8, 69, 250, 228, 298, 223,
+	-1000, 430, 27, 208, 1012, 68, 923, -1000, 877, -4,
+	-1000, -75, -75, -67, -67, -67, -68, -68, -68, -68,
+	-68, -68, -68, -68, -68, -68, -67, -16, -16, -1000,
+	154, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 10, 5,
+	536, 509, 485, 461, 204, -1000, -1000, -1000, -1000, 472,
+	-1000, -1000, 406, 1100, -1000, 104, 103, 135, 79, -1000,
+	-1000, -1000, -1000, 243, -1000, -1000, 867, -1000, -1000, -1000,
+	-1000, -1000, 433, 433, 433, 183, 172, -1000, -1000, 271,
+	260, 429, -1000, -1000, -1000, 1012, 342, 153, 72, -1000,
+	-1000, 1012, 1012, 171, 165, -1000, -1000, -1000, -1000, -1000,
+	237, 235, 89, 88, -1000, -1000, 259, 256, 1012, 1012,
+	167, 152, -1000, -1000,
 }
-var yyPgo = [...]int{
 
-	0, 306, 6, 303, 1, 478, 291, 2, 290, 5,
-	227, 289, 528, 0, 284, 281, 7, 4, 154, 265,
-	262,
+var yyPgo = [...]int16{
+	0, 518, 516, 9, 515, 6, 514, 508, 4, 506,
+	504, 502, 8, 3, 1039, 494, 10, 493, 5, 409,
+	492, 246, 28, 490, 483, 12, 1, 437, 7, 0,
+	2,
 }
-var yyR1 = [...]int{
-
-	0, 1, 1, 1, 5, 5, 5, 5, 5, 5,
-	5, 6, 7, 7, 7, 7, 7, 7, 7, 2,
-	3, 4, 4, 4, 4, 4, 4, 4, 8, 8,
-	9, 10, 10, 10, 10, 10, 10, 11, 11, 12,
-	12, 12, 12, 12, 12, 12, 12, 14, 15, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 16, 16, 16, 16, 16, 17, 17,
-	17, 17, 17, 17, 17, 17, 17, 17, 17, 17,
-	17, 17, 17, 17, 17, 17, 17, 17, 17, 17,
-	18, 18, 18, 18, 18, 18, 18, 18, 18, 18,
-	18, 18, 18, 18, 18, 18, 19, 19, 19, 19,
-	19, 19, 20, 20, 20, 20, 20, 20,
+
+var yyR1 = [...]int8{
+	0, 1, 1, 2, 2, 2, 2, 2, 2, 2,
+	4, 4, 3, 10, 10, 10, 12, 11, 11, 11,
+	14, 14, 14, 14, 14, 14, 14, 14, 14, 14,
+	14, 14, 15, 16, 16, 16, 16, 16, 16, 16,
+	16, 5, 6, 7, 9, 9, 9, 9, 9, 9,
+	9, 9, 8, 8, 8, 8, 13, 13, 13, 13,
+	13, 13, 13, 13, 13, 13, 13, 13, 17, 17,
+	18, 19, 19, 19, 19, 19, 19, 20, 20, 21,
+	21, 21, 21, 21, 21, 21, 21, 23, 24, 22,
+	22, 22, 22, 22, 22, 22, 22, 22, 22, 22,
+	22, 22, 22, 25, 25, 25, 25, 25, 25, 25,
+	25, 26, 26, 26, 26, 26, 26, 26, 26, 26,
+	26, 26, 26, 26, 26, 26, 26, 26, 26, 26,
+	26, 26, 26, 26, 26, 26, 26, 27, 27, 27,
+	27, 27, 28, 28, 28, 28, 28, 28, 28, 28,
+	28, 28, 28, 28, 28, 28, 28, 28, 29, 29,
+	29, 29, 29, 29, 29, 29, 29, 30, 30, 30,
+	30, 30, 30, 30, 30, 30,
 }
-var yyR2 = [...]int{
 
-	0, 1, 1, 1, 3, 3, 3, 3, 3, 3,
-	1, 3, 1, 1, 1, 3, 3, 3, 3, 4,
+var yyR2 = [...]int8{
+	0, 1, 5, 1, 1, 1, 3, 3, 3, 5,
+	1, 3, 3, 3, 4, 4, 2, 1, 1, 1,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	3, 1, 3, 1, 1, 1, 3, 3, 3, 3,
+	3, 4, 3, 4, 3, 7, 3, 7, 6, 10,
+	6, 10, 1, 1, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 1, 2, 3,
 	3, 1, 1, 1, 1, 1, 1, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 1, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 1, 1, 1, 1,
-	2, 2, 2, 3, 4, 4, 4, 4, 3, 3,
+	2, 2, 2, 3, 4, 4, 4, 4, 6, 6,
+	4, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 2, 2, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	2, 2, 1, 1, 1, 4, 1, 4, 4, 6,
+	6, 6, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 3, 3, 3, 3, 4, 4,
+	1, 1, 1, 1, 1, 1, 1, 3, 3, 3,
+	3, 3, 3, 3, 4, 4,
 }
-var yyChk = [...]int{
-
-	-1000, -1, -7, -5, -11, -4, -9, -2, 12, -6,
-	-12, -8, -13, 40, -14, 10, -16, 6, 7, 8,
-	57, 35, 37, 38, 36, 39, 43, 44, 50, 54,
-	45, 55, 44, 50, 54, 45, 55, -5, -7, -4,
-	-12, -15, -13, -10, 56, 57, 59, 60, 61, 62,
-	46, 47, 48, 49, 50, 51, -10, 56, 57, 59,
-	60, 61, 62, 12, 11, -17, 12, 57, 58, -18,
-	-19, -20, 5, 6, 7, 15, 16, 14, 8, 18,
-	17, 19, 20, 21, 22, 23, 24, 25, 26, 27,
-	28, 29, 31, 30, 9, 33, 34, 32, 6, 7,
-	8, 12, 12, 12, 12, 12, -4, -9, -2, -3,
-	12, 41, -5, 12, -5, -5, -5, -5, -4, 12,
-	-4, -4, -4, -4, 13, 13, 43, 13, 13, 13,
-	13, -12, -18, 12, -12, -12, -12, -12, -12, -12,
-	-13, 12, -13, -13, -13, -13, -13, -13, -17, 11,
-	56, 57, 59, 60, 61, 46, 47, 48, 49, 50,
-	51, 53, 52, 62, 44, 45, -17, -17, -17, 4,
-	4, 4, 4, 33, 34, 13, -17, -17, -17, -17,
-	-4, -13, 12, -7, 12, -16, 12, -7, 13, -17,
-	-17, -17, -17, -17, -17, -17, -17, -17, -17, -17,
-	-17, -17, -17, -17, -17, 13, 42, 42, 42, 42,
-	4, 4, 13, 13, 13, 13, 13, 42, 42,
+
+var yyChk = [...]int16{
+	-1000, -1, -2, -16, -14, -20, -13, -18, -5, 12,
+	-15, -21, -17, -22, 51, -23, 10, -25, 6, 7,
+	8, 87, 44, 46, 47, 45, 48, 49, 50, 66,
+	68, 69, 75, 79, 70, 81, 73, 80, 82, 83,
+	77, 69, 75, 79, 70, 81, 73, 80, 82, 83,
+	77, -14, -16, -13, -21, -24, -22, -19, 86, 87,
+	89, 90, 91, 92, 71, 72, 73, 74, 75, 76,
+	-19, 86, 87, 89, 90, 91, 92, 12, 11, -26,
+	12, 87, 88, -28, -29, -30, -27, 5, 6, 7,
+	18, 19, 17, 8, 21, 20, 22, 23, 24, 25,
+	26, 27, 28, 29, 30, 31, 32, 34, 33, 35,
+	36, 37, 9, 39, 40, 41, 42, 43, 38, 64,
+	65, 62, 63, 84, 6, 7, 8, 12, 12, 12,
+	12, 12, 12, 12, 12, -9, -10, -12, -13, -18,
+	-5, -6, -7, 54, 55, 56, 57, 60, 12, 52,
+	53, -14, 12, -14, -14, -14, -14, -14, -14, -14,
+	-14, -14, -13, 12, -13, -13, -13, -13, -13, -13,
+	-13, -13, -13, 13, 13, 68, 13, 13, 13, 13,
+	-21, -28, 12, -21, -21, -21, -21, -21, -21, -22,
+	12, -22, -22, -22, -22, -22, -22, -26, 11, 86,
+	87, 89, 90, 91, 71, 72, 73, 74, 75, 76,
+	78, 77, 84, 85, 92, 69, 70, -26, -26, -26,
+	14, 4, 4, 4, 4, 4, 4, 4, 39, 40,
+	12, 12, 12, 12, 12, 13, -26, -26, -26, -26,
+	-26, -26, -4, -3, 4, 68, 12, 12, 12, 51,
+	6, -13, -22, 12, 12, -16, 12, -25, 12, -16,
+	13, -26, -26, -26, -26, -26, -26, -26, -26, -26,
+	-26, -26, -26, -26, -26, -26, -26, -26, -26, 13,
+	6, 67, 67, 67, 67, 67, 67, 67, 4, 4,
+	-26, -26, -26, -26, -26, 13, 13, 13, 13, 16,
+	13, 13, 16, 71, -12, 13, 13, -29, -11, 29,
+	35, 61, 13, -8, -30, -29, 68, 15, 67, 67,
+	13, 13, 16, 16, 16, 7, 6, -3, -28, 51,
+	51, 16, 58, 59, 13, 16, -26, -26, -26, 13,
+	13, 12, 12, 7, 6, -30, -29, 13, 13, 13,
+	-8, -8, 13, 13, 13, 13, 51, 51, 12, 12,
+	-8, -8, 13, 13,
 }
-var yyDef = [...]int{
 
-	0, -2, 1, 2, 3, 12, 13, 14, 0, 10,
-	0, 27, 0, 0, 46, 0, 56, 57, 58, 59,
+var yyDef = [...]int16{
+	0, -2, 1, 3, 4, 5, 33, 34, 35, 0,
+	31, 0, 67, 0, 0, 86, 0, 96, 97, 98,
+	99, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 12,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	31, 32, 33, 34, 35, 36, 0, 0, 0, 0,
-	0, 0, 0, 0, 28, 0, 0, 0, 0, 87,
-	88, 89, 90, 91, 92, 93, 94, 95, 96, 97,
-	98, 99, 100, 101, 102, 103, 104, 105, 106, 107,
-	108, 109, 110, 111, 0, 0, 0, 0, 60, 61,
-	62, 0, 0, 0, 0, 0, 15, 16, 17, 18,
-	0, 0, 5, 0, 6, 7, 8, 9, 22, 0,
-	23, 24, 25, 26, 4, 11, 0, 21, 39, 47,
-	49, 37, 38, 0, 40, 41, 42, 43, 44, 45,
-	30, 0, 50, 51, 52, 53, 54, 55, 0, 29,
+	0, 0, 0, 33, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 71, 72, 73, 74, 75, 76,
+	0, 0, 0, 0, 0, 0, 0, 0, 68, 0,
+	0, 0, 0, 132, 133, 134, 136, 142, 143, 144,
+	145, 146, 147, 148, 149, 150, 151, 152, 153, 154,
+	155, 156, 157, 158, 159, 160, 161, 162, 163, 164,
+	165, 166, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 100, 101, 102, 0, 0, 0,
+	0, 0, 0, 0, 0, 6, 7, 8, 36, 37,
+	38, 39, 40, 0, 0, 0, 0, 0, 0, 0,
+	0, 21, 0, 22, 23, 24, 25, 26, 27, 28,
+	29, 30, 57, 0, 58, 59, 60, 61, 62, 63,
+	64, 65, 66, 20, 32, 0, 56, 79, 87, 89,
+	77, 78, 0, 80, 81, 82, 83, 84, 85, 70,
+	0, 90, 91, 92, 93, 94, 95, 0, 69, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 85, 86, 0,
-	0, 0, 0, 0, 0, 63, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, -2, 0, 0, 19, 69,
-	70, 71, 72, 73, 74, 75, 76, 77, 78, 79,
-	80, 81, 82, 83, 84, 68, 112, 113, 114, 115,
-	0, 0, 64, 65, 66, 67, 20, 116, 117,
+	0, 0, 0, 0, 0, 0, 0, 0, 130, 131,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 103, 0, 0, 0, 0,
+	0, 0, 0, 10, 0, 0, 0, 0, 0, 0,
+	16, 0, 0, 0, 0, 0, 0, -2, 0, 0,
+	41, 112, 113, 114, 115, 116, 117, 118, 119, 120,
+	121, 122, 123, 124, 125, 126, 127, 128, 129, 111,
+	0, 167, 168, 169, 170, 171, 172, 173, 0, 0,
+	0, 0, 0, 0, 0, 104, 105, 106, 107, 0,
+	110, 2, 0, 0, 9, 44, 46, 0, 13, 17,
+	18, 19, 42, 0, 52, 53, 0, 135, 174, 175,
+	137, 138, 0, 0, 0, 0, 0, 11, 12, 0,
+	0, 0, 14, 15, 43, 0, 0, 0, 0, 108,
+	109, 0, 0, 0, 0, 54, 55, 139, 140, 141,
+	0, 0, 48, 50, 45, 47, 0, 0, 0, 0,
+	0, 0, 49, 51,
 }
-var yyTok1 = [...]int{
 
+var yyTok1 = [...]int8{
 	1,
 }
-var yyTok2 = [...]int{
 
+var yyTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
 	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
 	52, 53, 54, 55, 56, 57, 58, 59, 60, 61,
-	62,
+	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
+	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
+	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
+	92,
 }
-var yyTok3 = [...]int{
+
+var yyTok3 = [...]int8{
 	0,
 }
 
@@ -459,9 +641,9 @@ func yyErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := yyPact[state]
+	base := int(yyPact[state])
 	for tok := TOKSTART; tok-1 < len(yyToknames); tok++ {
-		if n := base + tok; n >= 0 && n < yyLast && yyChk[yyAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < yyLast && int(yyChk[int(yyAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -471,13 +653,13 @@ func yyErrorMessage(state, lookAhead int) string {
 
 	if yyDef[state] == -2 {
 		i := 0
-		for yyExca[i] != -1 || yyExca[i+1] != state {
+		for yyExca[i] != -1 || int(yyExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; yyExca[i] >= 0; i += 2 {
-			tok := yyExca[i]
+			tok := int(yyExca[i])
 			if tok < TOKSTART || yyExca[i+1] == 0 {
 				continue
 			}
@@ -508,30 +690,30 @@ func yylex1(lex yyLexer, lval *yySymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = yyTok1[0]
+		token = int(yyTok1[0])
 		goto out
 	}
 	if char < len(yyTok1) {
-		token = yyTok1[char]
+		token = int(yyTok1[char])
 		goto out
 	}
 	if char >= yyPrivate {
 		if char < yyPrivate+len(yyTok2) {
-			token = yyTok2[char-yyPrivate]
+			token = int(yyTok2[char-yyPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(yyTok3); i += 2 {
-		token = yyTok3[i+0]
+		token = int(yyTok3[i+0])
 		if token == char {
-			token = yyTok3[i+1]
+			token = int(yyTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = yyTok2[1] /* unknown char */
+		token = int(yyTok2[1]) /* unknown char */
 	}
 	if yyDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", yyTokname(token), uint(char))
@@ -586,7 +768,7 @@ yystack:
 	yyS[yyp].yys = yystate
 
 yynewstate:
-	yyn = yyPact[yystate]
+	yyn = int(yyPact[yystate])
 	if yyn <= yyFlag {
 		goto yydefault /* simple state */
 	}
@@ -597,8 +779,8 @@ yynewstate:
 	if yyn < 0 || yyn >= yyLast {
 		goto yydefault
 	}
-	yyn = yyAct[yyn]
-	if yyChk[yyn] == yytoken { /* valid shift */
+	yyn = int(yyAct[yyn])
+	if int(yyChk[yyn]) == yytoken { /* valid shift */
 		yyrcvr.char = -1
 		yytoken = -1
 		yyVAL = yyrcvr.lval
@@ -611,7 +793,7 @@ yynewstate:
 
 yydefault:
 	/* default state action */
-	yyn = yyDef[yystate]
+	yyn = int(yyDef[yystate])
 	if yyn == -2 {
 		if yyrcvr.char < 0 {
 			yyrcvr.char, yytoken = yylex1(yylex, &yyrcvr.lval)
@@ -620,18 +802,18 @@ yydefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if yyExca[xi+0] == -1 && yyExca[xi+1] == yystate {
+			if yyExca[xi+0] == -1 && int(yyExca[xi+1]) == yystate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			yyn = yyExca[xi+0]
+			yyn = int(yyExca[xi+0])
 			if yyn < 0 || yyn == yytoken {
 				break
 			}
 		}
-		yyn = yyExca[xi+1]
+		yyn = int(yyExca[xi+1])
 		if yyn < 0 {
 			goto ret0
 		}
@@ -653,10 +835,10 @@ yydefault:
 
 			/* find a state where "error" is a legal shift action */
 			for yyp >= 0 {
-				yyn = yyPact[yyS[yyp].yys] + yyErrCode
+				yyn = int(yyPact[yyS[yyp].yys]) + yyErrCode
 				if yyn >= 0 && yyn < yyLast {
-					yystate = yyAct[yyn] /* simulate a shift of "error" */
-					if yyChk[yystate] == yyErrCode {
+					yystate = int(yyAct[yyn]) /* simulate a shift of "error" */
+					if int(yyChk[yystate]) == yyErrCode {
 						goto yystack
 					}
 				}
@@ -692,7 +874,7 @@ yydefault:
 	yypt := yyp
 	_ = yypt // guard against "declared and not used"
 
-	yyp -= yyR2[yyn]
+	yyp -= int(yyR2[yyn])
 	// yyp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if yyp+1 >= len(yyS) {
@@ -703,720 +885,1065 @@ yydefault:
 	yyVAL = yyS[yyp+1]
 
 	/* consult goto table to find next state */
-	yyn = yyR1[yyn]
-	yyg := yyPgo[yyn]
+	yyn = int(yyR1[yyn])
+	yyg := int(yyPgo[yyn])
 	yyj := yyg + yyS[yyp].yys + 1
 
 	if yyj >= yyLast {
-		yystate = yyAct[yyg]
+		yystate = int(yyAct[yyg])
 	} else {
-		yystate = yyAct[yyj]
-		if yyChk[yystate] != -yyn {
-			yystate = yyAct[yyg]
+		yystate = int(yyAct[yyj])
+		if int(yyChk[yystate]) != -yyn {
+			yystate = int(yyAct[yyg])
 		}
 	}
 	// dummy call; replaced with literal code
 	switch yynt {
 
-	case 1:
+	case 2:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line expr.y:117
+		{
+			yylex.(*lexer).expr.Hints = newHints(yyDollar[4].hintList)
+		}
+	case 3:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:94
+//line expr.y:121
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].spansetPipeline)
 		}
-	case 2:
+	case 4:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:95
+//line expr.y:122
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].spansetPipelineExpression)
 		}
-	case 3:
+	case 5:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:96
+//line expr.y:123
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].scalarPipelineExpressionFilter)
 		}
-	case 4:
+	case 6:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:124
+		{
+			yylex.(*lexer).expr = newRootExprWithMetrics(yyDollar[1].spansetPipeline, yyDollar[3].metricsAggregation)
+		}
+	case 7:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:125
+		{
+			yylex.(*lexer).expr = newRootExprWithOrder(yyDollar[1].spansetPipeline, &yyDollar[3].orderBy, 0)
+		}
+	case 8:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:126
+		{
+			yylex.(*lexer).expr = newRootExprWithOrder(yyDollar[1].spansetPipeline, nil, yyDollar[3].staticInt)
+		}
+	case 9:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line expr.y:127
+		{
+			yylex.(*lexer).expr = newRootExprWithOrder(yyDollar[1].spansetPipeline, &yyDollar[3].orderBy, yyDollar[5].staticInt)
+		}
+	case 10:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:134
+		{
+			yyVAL.hintList = []*Hint{yyDollar[1].hint}
+		}
+	case 11:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:135
+		{
+			yyVAL.hintList = append(yyDollar[1].hintList, yyDollar[3].hint)
+		}
+	case 12:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:139
+		{
+			yyVAL.hint = newHint(yyDollar[1].staticStr, yyDollar[3].static)
+		}
+	case 13:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:146
+		{
+			yyVAL.orderBy = newOrderBy(yyDollar[3].orderKey, false)
+		}
+	case 14:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:147
+		{
+			yyVAL.orderBy = newOrderBy(yyDollar[3].orderKey, false)
+		}
+	case 15:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:148
+		{
+			yyVAL.orderBy = newOrderBy(yyDollar[3].orderKey, true)
+		}
+	case 16:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line expr.y:152
+		{
+			if yyDollar[2].staticInt == 0 {
+				yylex.Error("limit must be greater than 0")
+			}
+			yyVAL.staticInt = yyDollar[2].staticInt
+		}
+	case 17:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:156
+		{
+			yyVAL.orderKey = OrderKeyDuration
+		}
+	case 18:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:157
+		{
+			yyVAL.orderKey = OrderKeyDuration
+		}
+	case 19:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:158
+		{
+			yyVAL.orderKey = OrderKeyStartTime
+		}
+	case 20:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:103
+//line expr.y:165
 		{
 			yyVAL.spansetPipelineExpression = yyDollar[2].spansetPipelineExpression
 		}
-	case 5:
+	case 21:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:104
+//line expr.y:166
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 6:
+	case 22:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:105
+//line expr.y:167
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 7:
+	case 23:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:106
+//line expr.y:168
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 8:
+	case 24:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:107
+//line expr.y:169
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 9:
+	case 25:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:108
+//line expr.y:170
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 10:
+	case 26:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:171
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 27:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:172
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 28:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:173
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 29:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:174
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 30:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:175
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 31:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:109
+//line expr.y:176
 		{
 			yyVAL.spansetPipelineExpression = yyDollar[1].wrappedSpansetPipeline
 		}
-	case 11:
+	case 32:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:113
+//line expr.y:180
 		{
 			yyVAL.wrappedSpansetPipeline = yyDollar[2].spansetPipeline
 		}
-	case 12:
+	case 33:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:116
+//line expr.y:183
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].spansetExpression)
 		}
-	case 13:
+	case 34:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:117
+//line expr.y:184
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].scalarFilter)
 		}
-	case 14:
+	case 35:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:118
+//line expr.y:185
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].groupOperation)
 		}
-	case 15:
+	case 36:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:119
+//line expr.y:186
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].spansetExpression)
 		}
-	case 16:
+	case 37:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:120
+//line expr.y:187
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].scalarFilter)
 		}
-	case 17:
+	case 38:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:121
+//line expr.y:188
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].groupOperation)
 		}
-	case 18:
+	case 39:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:122
+//line expr.y:189
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].coalesceOperation)
 		}
-	case 19:
+	case 40:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:190
+		{
+			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].selectOperation)
+		}
+	case 41:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:126
+//line expr.y:194
 		{
 			yyVAL.groupOperation = newGroupOperation(yyDollar[3].fieldExpression)
 		}
-	case 20:
+	case 42:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:130
+//line expr.y:198
 		{
 			yyVAL.coalesceOperation = newCoalesceOperation()
 		}
-	case 21:
+	case 43:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:202
+		{
+			yyVAL.selectOperation = newSelectOperation(yyDollar[3].attributeList)
+		}
+	case 44:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:209
+		{
+			yyVAL.metricsAggregation = newMetricsAggregate(MetricsAggregateRate, nil)
+		}
+	case 45:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line expr.y:210
+		{
+			yyVAL.metricsAggregation = newMetricsAggregate(MetricsAggregateRate, yyDollar[6].attributeList)
+		}
+	case 46:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:211
+		{
+			yyVAL.metricsAggregation = newMetricsAggregate(MetricsAggregateCountOverTime, nil)
+		}
+	case 47:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line expr.y:212
+		{
+			yyVAL.metricsAggregation = newMetricsAggregate(MetricsAggregateCountOverTime, yyDollar[6].attributeList)
+		}
+	case 48:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:213
+		{
+			yyVAL.metricsAggregation = newQuantileOverTime(yyDollar[3].intrinsicField, yyDollar[5].staticFloat, nil)
+		}
+	case 49:
+		yyDollar = yyS[yypt-10 : yypt+1]
+//line expr.y:214
+		{
+			yyVAL.metricsAggregation = newQuantileOverTime(yyDollar[3].intrinsicField, yyDollar[5].staticFloat, yyDollar[9].attributeList)
+		}
+	case 50:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:215
+		{
+			yyVAL.metricsAggregation = newQuantileOverTime(yyDollar[3].intrinsicField, float64(yyDollar[5].staticInt), nil)
+		}
+	case 51:
+		yyDollar = yyS[yypt-10 : yypt+1]
+//line expr.y:216
+		{
+			yyVAL.metricsAggregation = newQuantileOverTime(yyDollar[3].intrinsicField, float64(yyDollar[5].staticInt), yyDollar[9].attributeList)
+		}
+	case 52:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:220
+		{
+			yyVAL.attributeList = []Attribute{yyDollar[1].attributeField}
+		}
+	case 53:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:221
+		{
+			yyVAL.attributeList = []Attribute{yyDollar[1].intrinsicField}
+		}
+	case 54:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:222
+		{
+			yyVAL.attributeList = append(yyDollar[1].attributeList, yyDollar[3].attributeField)
+		}
+	case 55:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:134
+//line expr.y:223
+		{
+			yyVAL.attributeList = append(yyDollar[1].attributeList, yyDollar[3].intrinsicField)
+		}
+	case 56:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:227
 		{
 			yyVAL.spansetExpression = yyDollar[2].spansetExpression
 		}
-	case 22:
+	case 57:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:135
+//line expr.y:228
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 23:
+	case 58:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:136
+//line expr.y:229
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 24:
+	case 59:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:137
+//line expr.y:230
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 25:
+	case 60:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:138
+//line expr.y:231
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 26:
+	case 61:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:139
+//line expr.y:232
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 27:
+	case 62:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:233
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 63:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:234
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 64:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:235
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 65:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:236
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 66:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:237
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 67:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:140
+//line expr.y:238
 		{
 			yyVAL.spansetExpression = yyDollar[1].spansetFilter
 		}
-	case 28:
+	case 68:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:144
+//line expr.y:242
 		{
 			yyVAL.spansetFilter = newSpansetFilter(NewStaticBool(true))
 		}
-	case 29:
+	case 69:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:145
+//line expr.y:243
 		{
 			yyVAL.spansetFilter = newSpansetFilter(yyDollar[2].fieldExpression)
 		}
-	case 30:
+	case 70:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:149
+//line expr.y:247
 		{
 			yyVAL.scalarFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 31:
+	case 71:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:153
+//line expr.y:251
 		{
 			yyVAL.scalarFilterOperation = OpEqual
 		}
-	case 32:
+	case 72:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:154
+//line expr.y:252
 		{
 			yyVAL.scalarFilterOperation = OpNotEqual
 		}
-	case 33:
+	case 73:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:155
+//line expr.y:253
 		{
 			yyVAL.scalarFilterOperation = OpLess
 		}
-	case 34:
+	case 74:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:156
+//line expr.y:254
 		{
 			yyVAL.scalarFilterOperation = OpLessEqual
 		}
-	case 35:
+	case 75:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:157
+//line expr.y:255
 		{
 			yyVAL.scalarFilterOperation = OpGreater
 		}
-	case 36:
+	case 76:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:158
+//line expr.y:256
 		{
 			yyVAL.scalarFilterOperation = OpGreaterEqual
 		}
-	case 37:
+	case 77:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:165
+//line expr.y:263
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 38:
+	case 78:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:166
+//line expr.y:264
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].static)
 		}
-	case 39:
+	case 79:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:170
+//line expr.y:268
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[2].scalarPipelineExpression
 		}
-	case 40:
+	case 80:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:171
+//line expr.y:269
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpAdd, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 41:
+	case 81:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:172
+//line expr.y:270
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpSub, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 42:
+	case 82:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:173
+//line expr.y:271
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMult, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 43:
+	case 83:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:174
+//line expr.y:272
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpDiv, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 44:
+	case 84:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:175
+//line expr.y:273
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMod, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 45:
+	case 85:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:176
+//line expr.y:274
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpPower, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 46:
+	case 86:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:177
+//line expr.y:275
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[1].wrappedScalarPipeline
 		}
-	case 47:
+	case 87:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:181
+//line expr.y:279
 		{
 			yyVAL.wrappedScalarPipeline = yyDollar[2].scalarPipeline
 		}
-	case 48:
+	case 88:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:185
+//line expr.y:283
 		{
 			yyVAL.scalarPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].aggregate)
 		}
-	case 49:
+	case 89:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:189
+//line expr.y:287
 		{
 			yyVAL.scalarExpression = yyDollar[2].scalarExpression
 		}
-	case 50:
+	case 90:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:190
+//line expr.y:288
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpAdd, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 51:
+	case 91:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:191
+//line expr.y:289
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpSub, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 52:
+	case 92:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:192
+//line expr.y:290
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMult, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 53:
+	case 93:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:193
+//line expr.y:291
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpDiv, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 54:
+	case 94:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:194
+//line expr.y:292
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMod, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 55:
+	case 95:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:195
+//line expr.y:293
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpPower, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 56:
+	case 96:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:196
+//line expr.y:294
 		{
 			yyVAL.scalarExpression = yyDollar[1].aggregate
 		}
-	case 57:
+	case 97:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:197
+//line expr.y:295
 		{
 			yyVAL.scalarExpression = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 58:
+	case 98:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:198
+//line expr.y:296
 		{
 			yyVAL.scalarExpression = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 59:
+	case 99:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:199
+//line expr.y:297
 		{
 			yyVAL.scalarExpression = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 60:
+	case 100:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:200
+//line expr.y:298
 		{
 			yyVAL.scalarExpression = NewStaticInt(-yyDollar[2].staticInt)
 		}
-	case 61:
+	case 101:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:201
+//line expr.y:299
 		{
 			yyVAL.scalarExpression = NewStaticFloat(-yyDollar[2].staticFloat)
 		}
-	case 62:
+	case 102:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:202
+//line expr.y:300
 		{
 			yyVAL.scalarExpression = NewStaticDuration(-yyDollar[2].staticDuration)
 		}
-	case 63:
+	case 103:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:206
+//line expr.y:304
 		{
 			yyVAL.aggregate = newAggregate(aggregateCount, nil)
 		}
-	case 64:
+	case 104:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:207
+//line expr.y:305
 		{
 			yyVAL.aggregate = newAggregate(aggregateMax, yyDollar[3].fieldExpression)
 		}
-	case 65:
+	case 105:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:208
+//line expr.y:306
 		{
 			yyVAL.aggregate = newAggregate(aggregateMin, yyDollar[3].fieldExpression)
 		}
-	case 66:
+	case 106:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:209
+//line expr.y:307
 		{
 			yyVAL.aggregate = newAggregate(aggregateAvg, yyDollar[3].fieldExpression)
 		}
-	case 67:
+	case 107:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:210
+//line expr.y:308
 		{
 			yyVAL.aggregate = newAggregate(aggregateSum, yyDollar[3].fieldExpression)
 		}
-	case 68:
+	case 108:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:309
+		{
+			yyVAL.aggregate = newQuantileAggregate(yyDollar[3].fieldExpression, yyDollar[5].staticFloat)
+		}
+	case 109:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:310
+		{
+			yyVAL.aggregate = newQuantileAggregate(yyDollar[3].fieldExpression, float64(yyDollar[5].staticInt))
+		}
+	case 110:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:311
+		{
+			yyVAL.aggregate = newAggregate(aggregateCountDistinct, yyDollar[3].fieldExpression)
+		}
+	case 111:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:217
+//line expr.y:318
 		{
 			yyVAL.fieldExpression = yyDollar[2].fieldExpression
 		}
-	case 69:
+	case 112:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:218
+//line expr.y:319
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAdd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 70:
+	case 113:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:219
+//line expr.y:320
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpSub, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 71:
+	case 114:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:220
+//line expr.y:321
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMult, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 72:
+	case 115:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:221
+//line expr.y:322
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpDiv, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 73:
+	case 116:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:222
+//line expr.y:323
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMod, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 74:
+	case 117:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:223
+//line expr.y:324
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 75:
+	case 118:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:224
+//line expr.y:325
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 76:
+	case 119:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:225
+//line expr.y:326
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLess, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 77:
+	case 120:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:226
+//line expr.y:327
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLessEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 78:
+	case 121:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:227
+//line expr.y:328
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreater, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 79:
+	case 122:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:228
+//line expr.y:329
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreaterEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 80:
+	case 123:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:229
+//line expr.y:330
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 81:
+	case 124:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:230
+//line expr.y:331
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 82:
+	case 125:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:332
+		{
+			yyVAL.fieldExpression = newBinaryOperation(OpContains, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
+		}
+	case 126:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:333
+		{
+			yyVAL.fieldExpression = newBinaryOperation(OpEqualFold, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
+		}
+	case 127:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:231
+//line expr.y:334
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpPower, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 83:
+	case 128:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:232
+//line expr.y:335
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAnd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 84:
+	case 129:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:233
+//line expr.y:336
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpOr, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 85:
+	case 130:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:234
+//line expr.y:337
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpSub, yyDollar[2].fieldExpression)
 		}
-	case 86:
+	case 131:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:235
+//line expr.y:338
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpNot, yyDollar[2].fieldExpression)
 		}
-	case 87:
+	case 132:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:236
+//line expr.y:339
 		{
 			yyVAL.fieldExpression = yyDollar[1].static
 		}
-	case 88:
+	case 133:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:237
+//line expr.y:340
 		{
 			yyVAL.fieldExpression = yyDollar[1].intrinsicField
 		}
-	case 89:
+	case 134:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:238
+//line expr.y:341
 		{
 			yyVAL.fieldExpression = yyDollar[1].attributeField
 		}
-	case 90:
+	case 135:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:342
+		{
+			yyVAL.fieldExpression = newIndexOperation(yyDollar[1].attributeField, yyDollar[3].staticInt)
+		}
+	case 136:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:343
+		{
+			yyVAL.fieldExpression = yyDollar[1].fieldExpression
+		}
+	case 137:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:347
+		{
+			yyVAL.fieldExpression = newUnaryOperation(OpLower, yyDollar[3].fieldExpression)
+		}
+	case 138:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:348
+		{
+			yyVAL.fieldExpression = newUnaryOperation(OpUpper, yyDollar[3].fieldExpression)
+		}
+	case 139:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:349
+		{
+			yyVAL.fieldExpression = newBinaryOperation(OpStartsWith, yyDollar[3].fieldExpression, yyDollar[5].fieldExpression)
+		}
+	case 140:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:350
+		{
+			yyVAL.fieldExpression = newBinaryOperation(OpEndsWith, yyDollar[3].fieldExpression, yyDollar[5].fieldExpression)
+		}
+	case 141:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:351
+		{
+			yyVAL.fieldExpression = newBinaryOperation(OpSubstring, yyDollar[3].fieldExpression, yyDollar[5].fieldExpression)
+		}
+	case 142:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:245
+//line expr.y:358
 		{
 			yyVAL.static = NewStaticString(yyDollar[1].staticStr)
 		}
-	case 91:
+	case 143:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:246
+//line expr.y:359
 		{
 			yyVAL.static = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 92:
+	case 144:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:247
+//line expr.y:360
 		{
 			yyVAL.static = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 93:
+	case 145:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:248
+//line expr.y:361
 		{
 			yyVAL.static = NewStaticBool(true)
 		}
-	case 94:
+	case 146:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:249
+//line expr.y:362
 		{
 			yyVAL.static = NewStaticBool(false)
 		}
-	case 95:
+	case 147:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:250
+//line expr.y:363
 		{
 			yyVAL.static = NewStaticNil()
 		}
-	case 96:
+	case 148:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:251
+//line expr.y:364
 		{
 			yyVAL.static = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 97:
+	case 149:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:252
+//line expr.y:365
 		{
 			yyVAL.static = NewStaticStatus(StatusOk)
 		}
-	case 98:
+	case 150:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:253
+//line expr.y:366
 		{
 			yyVAL.static = NewStaticStatus(StatusError)
 		}
-	case 99:
+	case 151:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:254
+//line expr.y:367
 		{
 			yyVAL.static = NewStaticStatus(StatusUnset)
 		}
-	case 100:
+	case 152:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:255
+//line expr.y:368
 		{
 			yyVAL.static = NewStaticKind(KindUnspecified)
 		}
-	case 101:
+	case 153:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:256
+//line expr.y:369
 		{
 			yyVAL.static = NewStaticKind(KindInternal)
 		}
-	case 102:
+	case 154:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:257
+//line expr.y:370
 		{
 			yyVAL.static = NewStaticKind(KindServer)
 		}
-	case 103:
+	case 155:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:258
+//line expr.y:371
 		{
 			yyVAL.static = NewStaticKind(KindClient)
 		}
-	case 104:
+	case 156:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:259
+//line expr.y:372
 		{
 			yyVAL.static = NewStaticKind(KindProducer)
 		}
-	case 105:
+	case 157:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:260
+//line expr.y:373
 		{
 			yyVAL.static = NewStaticKind(KindConsumer)
 		}
-	case 106:
+	case 158:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:264
+//line expr.y:377
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicDuration)
 		}
-	case 107:
+	case 159:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:265
+//line expr.y:378
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicChildCount)
 		}
-	case 108:
+	case 160:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:266
+//line expr.y:379
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicName)
 		}
-	case 109:
+	case 161:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:267
+//line expr.y:380
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicStatus)
 		}
-	case 110:
+	case 162:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:268
+//line expr.y:381
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicKind)
 		}
-	case 111:
+	case 163:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:269
+//line expr.y:382
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicParent)
 		}
-	case 112:
+	case 164:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:383
+		{
+			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceDuration)
+		}
+	case 165:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:384
+		{
+			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootSpan)
+		}
+	case 166:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:385
+		{
+			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootService)
+		}
+	case 167:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:273
+//line expr.y:389
 		{
 			yyVAL.attributeField = NewAttribute(yyDollar[2].staticStr)
 		}
-	case 113:
+	case 168:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:274
+//line expr.y:390
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, false, yyDollar[2].staticStr)
 		}
-	case 114:
+	case 169:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:275
+//line expr.y:391
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, false, yyDollar[2].staticStr)
 		}
-	case 115:
+	case 170:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:392
+		{
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeEvent, false, yyDollar[2].staticStr)
+		}
+	case 171:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:393
+		{
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeLink, false, yyDollar[2].staticStr)
+		}
+	case 172:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:394
+		{
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeInstrumentation, false, yyDollar[2].staticStr)
+		}
+	case 173:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:276
+//line expr.y:395
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeNone, true, yyDollar[2].staticStr)
 		}
-	case 116:
+	case 174:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:277
+//line expr.y:396
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, true, yyDollar[3].staticStr)
 		}
-	case 117:
+	case 175:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:278
+//line expr.y:397
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, true, yyDollar[3].staticStr)
 		}
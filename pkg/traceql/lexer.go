@@ -6,80 +6,136 @@ import (
 	"text/scanner"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/prometheus/common/model"
 )
 
 var tokens = map[string]int{
-	".":           DOT,
-	"{":           OPEN_BRACE,
-	"}":           CLOSE_BRACE,
-	"(":           OPEN_PARENS,
-	")":           CLOSE_PARENS,
-	"=":           EQ,
-	"!=":          NEQ,
-	"=~":          RE,
-	"!~":          NRE,
-	">":           GT,
-	">=":          GTE,
-	"<":           LT,
-	"<=":          LTE,
-	"+":           ADD,
-	"-":           SUB,
-	"/":           DIV,
-	"%":           MOD,
-	"*":           MUL,
-	"^":           POW,
-	"true":        TRUE,
-	"false":       FALSE,
-	"nil":         NIL,
-	"ok":          STATUS_OK,
-	"error":       STATUS_ERROR,
-	"unset":       STATUS_UNSET,
-	"unspecified": KIND_UNSPECIFIED,
-	"internal":    KIND_INTERNAL,
-	"server":      KIND_SERVER,
-	"client":      KIND_CLIENT,
-	"producer":    KIND_PRODUCER,
-	"consumer":    KIND_CONSUMER,
-	"&&":          AND,
-	"||":          OR,
-	"!":           NOT,
-	"|":           PIPE,
-	">>":          DESC,
-	"~":           TILDE,
-	"duration":    IDURATION,
-	"childCount":  CHILDCOUNT,
-	"name":        NAME,
-	"status":      STATUS,
-	"kind":        KIND,
-	"parent":      PARENT,
-	"parent.":     PARENT_DOT,
-	"resource.":   RESOURCE_DOT,
-	"span.":       SPAN_DOT,
-	"count":       COUNT,
-	"avg":         AVG,
-	"max":         MAX,
-	"min":         MIN,
-	"sum":         SUM,
-	"by":          BY,
-	"coalesce":    COALESCE,
+	".":                  DOT,
+	"{":                  OPEN_BRACE,
+	"}":                  CLOSE_BRACE,
+	"(":                  OPEN_PARENS,
+	")":                  CLOSE_PARENS,
+	",":                  COMMA,
+	"[":                  OPEN_BRACKET,
+	"]":                  CLOSE_BRACKET,
+	"=":                  EQ,
+	"!=":                 NEQ,
+	"=~":                 RE,
+	"!~":                 NRE,
+	"=*":                 EQ_FOLD,
+	">":                  GT,
+	">=":                 GTE,
+	"<":                  LT,
+	"<=":                 LTE,
+	"+":                  ADD,
+	"-":                  SUB,
+	"/":                  DIV,
+	"%":                  MOD,
+	"*":                  MUL,
+	"^":                  POW,
+	"true":               TRUE,
+	"false":              FALSE,
+	"nil":                NIL,
+	"ok":                 STATUS_OK,
+	"error":              STATUS_ERROR,
+	"unset":              STATUS_UNSET,
+	"unspecified":        KIND_UNSPECIFIED,
+	"internal":           KIND_INTERNAL,
+	"server":             KIND_SERVER,
+	"client":             KIND_CLIENT,
+	"producer":           KIND_PRODUCER,
+	"consumer":           KIND_CONSUMER,
+	"&&":                 AND,
+	"||":                 OR,
+	"!":                  NOT,
+	"|":                  PIPE,
+	">>":                 DESC,
+	"<<":                 ANCE,
+	"!>":                 NOT_CHILD,
+	"!>>":                NOT_DESC,
+	"~":                  TILDE,
+	"duration":           IDURATION,
+	"childCount":         CHILDCOUNT,
+	"name":               NAME,
+	"status":             STATUS,
+	"kind":               KIND,
+	"parent":             PARENT,
+	"traceDuration":      TRACE_DURATION,
+	"rootName":           ROOT_NAME,
+	"rootServiceName":    ROOT_SERVICE_NAME,
+	"parent.":            PARENT_DOT,
+	"resource.":          RESOURCE_DOT,
+	"span.":              SPAN_DOT,
+	"event.":             EVENT_DOT,
+	"link.":              LINK_DOT,
+	"instrumentation.":   INSTRUMENTATION_DOT,
+	"count":              COUNT,
+	"avg":                AVG,
+	"max":                MAX,
+	"min":                MIN,
+	"sum":                SUM,
+	"quantile":           QUANTILE,
+	"count_distinct":     COUNT_DISTINCT,
+	"rate":               RATE,
+	"count_over_time":    COUNT_OVER_TIME,
+	"quantile_over_time": QUANTILE_OVER_TIME,
+	"by":                 BY,
+	"coalesce":           COALESCE,
+	"select":             SELECT,
+	"contains":           CONTAINS,
+	"startsWith":         STARTS_WITH,
+	"endsWith":           ENDS_WITH,
+	"lower":              LOWER,
+	"upper":              UPPER,
+	"order":              ORDER,
+	"asc":                ASC,
+	"desc":               DESCENDING,
+	"limit":              LIMIT,
+	"startTime":          START_TIME,
+	"with":               WITH,
 }
 
 type lexer struct {
 	scanner.Scanner
+	query  string
 	expr   *RootExpr
 	parser *yyParserImpl
 	errs   []ParseError
 
 	parsingAttribute bool
+
+	// argLists holds an entry for every open parenthesis that is true if the parenthesis opens the
+	// argument list of a function with several arguments. commas only end attributes inside these.
+	argLists []bool
+	lastTok  int
+	metricBy bool // the last BY follows a metrics aggregate and takes a list of attributes
 }
 
 func (l *lexer) Lex(lval *yySymType) int {
+	tok := l.lex(lval)
+
+	switch tok {
+	case OPEN_PARENS:
+		l.argLists = append(l.argLists, opensArgumentList(l.lastTok, l.metricBy))
+	case CLOSE_PARENS:
+		if len(l.argLists) > 0 {
+			l.argLists = l.argLists[:len(l.argLists)-1]
+		}
+	case BY:
+		l.metricBy = l.lastTok == CLOSE_PARENS
+	}
+
+	l.lastTok = tok
+	return tok
+}
+
+func (l *lexer) lex(lval *yySymType) int {
 	// if we are currently parsing an attribute and the next rune suggests that
 	//  this attribute will end, then return a special token indicating that the attribute is
 	//  done parsing
-	if l.parsingAttribute && !isAttributeRune(l.Peek()) {
+	if l.parsingAttribute && !l.isAttributeRune(l.Peek()) {
 		l.parsingAttribute = false
 		return END_ATTRIBUTE
 	}
@@ -99,7 +155,7 @@ func (l *lexer) Lex(lval *yySymType) int {
 
 		// go forward until we find the end of the attribute
 		r := l.Peek()
-		for isAttributeRune(r) {
+		for l.isAttributeRune(r) {
 			str += string(l.Next())
 			r = l.Peek()
 		}
@@ -163,6 +219,13 @@ func (l *lexer) Lex(lval *yySymType) int {
 	tokStrNext := l.TokenText() + string(l.Peek())
 	if tok, ok := tokens[tokStrNext]; ok {
 		l.Next()
+
+		// look one rune further for three rune operators like !>>
+		if tok3, ok := tokens[tokStrNext+string(l.Peek())]; ok {
+			l.Next()
+			tok = tok3
+		}
+
 		l.parsingAttribute = startsAttribute(tok)
 		return tok
 	}
@@ -177,7 +240,22 @@ func (l *lexer) Lex(lval *yySymType) int {
 }
 
 func (l *lexer) Error(msg string) {
-	l.errs = append(l.errs, newParseError(msg, l.Line, l.Column))
+	err := newParseError(msg, l.Line, l.Column)
+
+	// the parser only knows the type of identifiers and literals, replace it with the text of the offending
+	// token. the end of the query and of an attribute don't have any text.
+	switch err.token {
+	case "", "$end", "END_ATTRIBUTE":
+	default:
+		text := l.query[l.Position.Offset:l.Pos().Offset]
+		if err.token == "IDENTIFIER" {
+			err.suggestions = suggest(text)
+		}
+		err.token = text
+		err.endCol = err.col + utf8.RuneCountInString(text)
+	}
+
+	l.errs = append(l.errs, err)
 }
 
 func tryScanDuration(number string, l *scanner.Scanner) (time.Duration, bool) {
@@ -245,16 +323,40 @@ func isAttributeRune(r rune) bool {
 	}
 
 	switch r {
-	case scanner.EOF, '{', '}', '(', ')', '=', '~', '!', '<', '>', '&', '|', '^':
+	case scanner.EOF, '{', '}', '(', ')', '[', ']', '=', '~', '!', '<', '>', '&', '|', '^':
 		return false
 	default:
 		return true
 	}
 }
 
+// isAttributeRune is isAttributeRune with commas ending the attribute inside a function argument list
+func (l *lexer) isAttributeRune(r rune) bool {
+	if r == ',' && len(l.argLists) > 0 && l.argLists[len(l.argLists)-1] {
+		return false
+	}
+	return isAttributeRune(r)
+}
+
+// opensArgumentList returns true if a parenthesis following tok opens the argument list of a function
+// whose arguments may be attributes separated by commas. a BY opens a list of attributes only after a
+// metrics aggregate, when grouping spansets it takes a single field.
+func opensArgumentList(tok int, metricBy bool) bool {
+	switch tok {
+	case SELECT, QUANTILE, STARTS_WITH, ENDS_WITH, CONTAINS:
+		return true
+	case BY:
+		return metricBy
+	}
+	return false
+}
+
 func startsAttribute(tok int) bool {
 	return tok == DOT ||
 		tok == RESOURCE_DOT ||
 		tok == SPAN_DOT ||
+		tok == EVENT_DOT ||
+		tok == LINK_DOT ||
+		tok == INSTRUMENTATION_DOT ||
 		tok == PARENT_DOT
 }
@@ -40,6 +40,15 @@ func TestLexerAttributes(t *testing.T) {
 		{`resource.foo3`, []int{RESOURCE_DOT, IDENTIFIER, END_ATTRIBUTE}},
 		{`resource.foo+bar`, []int{RESOURCE_DOT, IDENTIFIER, END_ATTRIBUTE}},
 		{`resource.foo-bar`, []int{RESOURCE_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		// event attributes
+		{`event.name`, []int{EVENT_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`event.exception.type`, []int{EVENT_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		// link attributes
+		{`link.traceID`, []int{LINK_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`link.foo-bar`, []int{LINK_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		// instrumentation attributes
+		{`instrumentation.name`, []int{INSTRUMENTATION_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`instrumentation.version`, []int{INSTRUMENTATION_DOT, IDENTIFIER, END_ATTRIBUTE}},
 		// parent span attributes
 		{`parent.span.foo`, []int{PARENT_DOT, SPAN_DOT, IDENTIFIER, END_ATTRIBUTE}},
 		{`parent.span.count`, []int{PARENT_DOT, SPAN_DOT, IDENTIFIER, END_ATTRIBUTE}},
@@ -58,7 +67,39 @@ func TestLexerAttributes(t *testing.T) {
 		{`.foo{.bar`, []int{DOT, IDENTIFIER, END_ATTRIBUTE, OPEN_BRACE, DOT, IDENTIFIER, END_ATTRIBUTE}},
 		{`.foo).bar`, []int{DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE}},
 		{`.foo(.bar`, []int{DOT, IDENTIFIER, END_ATTRIBUTE, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`.foo[0]`, []int{DOT, IDENTIFIER, END_ATTRIBUTE, OPEN_BRACKET, INTEGER, CLOSE_BRACKET}},
+		{`.foo contains "bar"`, []int{DOT, IDENTIFIER, END_ATTRIBUTE, CONTAINS, STRING}},
+		{`.foo=*"bar"`, []int{DOT, IDENTIFIER, END_ATTRIBUTE, EQ_FOLD, STRING}},
+		{`lower(.foo)`, []int{LOWER, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_PARENS}},
+		{`startsWith(.foo, "bar")`, []int{STARTS_WITH, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, COMMA, STRING, CLOSE_PARENS}},
+		// commas only end attributes in the argument lists of functions with several arguments
+		{`.foo,bar`, []int{DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`(.foo,bar)`, []int{OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_PARENS}},
+		{`max(.foo,bar)`, []int{MAX, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_PARENS}},
+		{`by(.foo,bar)`, []int{BY, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_PARENS}},
+		{`startsWith(.foo,"bar")`, []int{STARTS_WITH, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, COMMA, STRING, CLOSE_PARENS}},
+		{`quantile(.foo,0.9)`, []int{QUANTILE, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, COMMA, FLOAT, CLOSE_PARENS}},
+		{`select(.foo,.bar)`, []int{SELECT, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, COMMA, DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_PARENS}},
+		{`rate() by(.foo,.bar)`, []int{RATE, OPEN_PARENS, CLOSE_PARENS, BY, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, COMMA, DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_PARENS}},
+		{`startsWith(lower(.foo,bar), "x")`, []int{STARTS_WITH, OPEN_PARENS, LOWER, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_PARENS, COMMA, STRING, CLOSE_PARENS}},
+		{`.lower`, []int{DOT, IDENTIFIER, END_ATTRIBUTE}},
 		{`. foo`, []int{DOT, END_ATTRIBUTE, IDENTIFIER}},
+		// reserved words and scopes are part of attribute names
+		{`.contains`, []int{DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`.order`, []int{DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`span.limit`, []int{SPAN_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`resource.select`, []int{RESOURCE_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`parent.with`, []int{PARENT_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`.foo.contains.bar`, []int{DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`.order.asc`, []int{DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`.rootName`, []int{DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`.event.name`, []int{DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`span.link.foo`, []int{SPAN_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`.foo=*bar`, []int{DOT, IDENTIFIER, END_ATTRIBUTE, EQ_FOLD, IDENTIFIER}},
+		// brackets end attribute names so names containing them can't be queried
+		{`.foo[bar]`, []int{DOT, IDENTIFIER, END_ATTRIBUTE, OPEN_BRACKET, IDENTIFIER, CLOSE_BRACKET}},
+		{`.foo]bar`, []int{DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_BRACKET, IDENTIFIER}},
+		{`span.labels[app]`, []int{SPAN_DOT, IDENTIFIER, END_ATTRIBUTE, OPEN_BRACKET, IDENTIFIER, CLOSE_BRACKET}},
 		// not attributes
 		{`.3`, []int{FLOAT}},
 		{`.24h`, []int{DURATION}},
@@ -84,6 +125,20 @@ func TestLexerDuration(t *testing.T) {
 	}))
 }
 
+func TestLexerStructuralOperators(t *testing.T) {
+	testLexer(t, ([]lexerTestCase{
+		{`{ } > { }`, []int{OPEN_BRACE, CLOSE_BRACE, GT, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } >> { }`, []int{OPEN_BRACE, CLOSE_BRACE, DESC, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } ~ { }`, []int{OPEN_BRACE, CLOSE_BRACE, TILDE, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } < { }`, []int{OPEN_BRACE, CLOSE_BRACE, LT, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } << { }`, []int{OPEN_BRACE, CLOSE_BRACE, ANCE, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } !> { }`, []int{OPEN_BRACE, CLOSE_BRACE, NOT_CHILD, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } !>> { }`, []int{OPEN_BRACE, CLOSE_BRACE, NOT_DESC, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } !~ { }`, []int{OPEN_BRACE, CLOSE_BRACE, NRE, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ }!>>{ }`, []int{OPEN_BRACE, CLOSE_BRACE, NOT_DESC, OPEN_BRACE, CLOSE_BRACE}},
+	}))
+}
+
 func TestLexerParseDuration(t *testing.T) {
 	const MICROSECOND = 1000 * time.Nanosecond
 	const DAY = 24 * time.Hour
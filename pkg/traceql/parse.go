@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"text/scanner"
+	"unicode/utf8"
 )
 
 func init() {
@@ -22,7 +23,8 @@ func Parse(s string) (expr *RootExpr, err error) {
 		if r := recover(); r != nil {
 			var ok bool
 			if err, ok = r.(error); ok {
-				if errors.Is(err, ParseError{}) {
+				var parseErr ParseError
+				if errors.As(err, &parseErr) {
 					return
 				}
 				err = newParseError(err.Error(), 0, 0)
@@ -30,6 +32,7 @@ func Parse(s string) (expr *RootExpr, err error) {
 		}
 	}()
 	l := lexer{
+		query:  s,
 		parser: yyNewParser().(*yyParserImpl),
 	}
 	l.Init(strings.NewReader(s))
@@ -47,6 +50,31 @@ func Parse(s string) (expr *RootExpr, err error) {
 	return l.expr, nil
 }
 
+// ParseAndValidate parses the query and validates the expression. Validation errors are returned as
+// ParseError too.
+func ParseAndValidate(s string) (*RootExpr, error) {
+	expr, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := expr.validate(); err != nil {
+		return nil, newValidationError(err, s)
+	}
+	return expr, nil
+}
+
+// ParseAndValidateSearch is ParseAndValidate for queries of the search api, which rejects metrics queries.
+func ParseAndValidateSearch(s string) (*RootExpr, error) {
+	expr, err := ParseAndValidate(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := expr.validateSearch(); err != nil {
+		return nil, newValidationError(err, s)
+	}
+	return expr, nil
+}
+
 func ParseIdentifier(s string) (Attribute, error) {
 	if i := intrinsicFromString(s); i != IntrinsicNone {
 		return NewIntrinsic(i), nil
@@ -59,6 +87,8 @@ func ParseIdentifier(s string) (Attribute, error) {
 		return NewScopedAttribute(AttributeScopeResource, false, strings.TrimPrefix(s, "resource.")), nil
 	case strings.HasPrefix(s, "span."):
 		return NewScopedAttribute(AttributeScopeSpan, false, strings.TrimPrefix(s, "span.")), nil
+	case strings.HasPrefix(s, "instrumentation."):
+		return NewScopedAttribute(AttributeScopeInstrumentation, false, strings.TrimPrefix(s, "instrumentation.")), nil
 	default:
 		return Attribute{}, fmt.Errorf("tag name is not valid intrinsic or scoped attribute: %s", s)
 	}
@@ -72,10 +102,16 @@ func MustParseIdentifier(s string) Attribute {
 	return a
 }
 
-// ParseError is what is returned when we failed to parse.
+// ParseError is what is returned when we failed to parse. Besides the position it contains the offending
+// token, the tokens the parser expected instead and suggestions for misspelled intrinsics and scopes.
 type ParseError struct {
-	msg       string
-	line, col int
+	err         error // validation error
+	msg         string
+	line, col   int
+	endCol      int
+	token       string
+	expected    []string
+	suggestions []string
 }
 
 func (p ParseError) Error() string {
@@ -85,10 +121,144 @@ func (p ParseError) Error() string {
 	return fmt.Sprintf("parse error at line %d, col %d: %s", p.line, p.col, p.msg)
 }
 
+// Unwrap returns the validation error, nil for syntax errors.
+func (p ParseError) Unwrap() error {
+	return p.err
+}
+
+// Line returns the line of the offending token. It's 0 if the position is unknown.
+func (p ParseError) Line() int {
+	return p.line
+}
+
+// Column returns the first column of the offending token. It's 0 if the position is unknown.
+func (p ParseError) Column() int {
+	return p.col
+}
+
+// EndColumn returns the column after the offending token.
+func (p ParseError) EndColumn() int {
+	return p.endCol
+}
+
+// Token returns the offending token, $end if the query ended unexpectedly.
+func (p ParseError) Token() string {
+	return p.token
+}
+
+// Expected returns the tokens that are valid at the position of the offending token.
+func (p ParseError) Expected() []string {
+	return p.expected
+}
+
+// Suggestions returns intrinsics or scopes the offending token might be a misspelling of.
+func (p ParseError) Suggestions() []string {
+	return p.suggestions
+}
+
+// newParseError creates a ParseError. Syntax errors from the parser have the form
+// "syntax error: unexpected <token>, expecting <token> or <token>" and are split into the offending
+// and the expected tokens.
 func newParseError(msg string, line, col int) ParseError {
+	err := ParseError{
+		msg:    msg,
+		line:   line,
+		col:    col,
+		endCol: col,
+	}
+
+	if !strings.HasPrefix(msg, syntaxErrorPrefix) {
+		return err
+	}
+
+	unexpected, expecting, _ := strings.Cut(strings.TrimPrefix(msg, syntaxErrorPrefix), ", expecting ")
+	err.token = unexpected
+	if expecting != "" {
+		err.expected = strings.Split(expecting, " or ")
+	}
+
+	return err
+}
+
+// newValidationError creates a ParseError for an expression that parsed but isn't valid. The AST doesn't
+// keep the position of expressions, so the position is the first line of the query.
+func newValidationError(err error, query string) ParseError {
+	firstLine, _, _ := strings.Cut(query, "\n")
 	return ParseError{
-		msg:  msg,
-		line: line,
-		col:  col,
+		err:    err,
+		msg:    err.Error(),
+		line:   1,
+		col:    1,
+		endCol: 1 + utf8.RuneCountInString(firstLine),
+	}
+}
+
+const syntaxErrorPrefix = "syntax error: unexpected "
+
+// suggestionCandidates are the intrinsics and scopes that are suggested for misspelled identifiers
+var suggestionCandidates = []string{
+	"duration",
+	"childCount",
+	"name",
+	"status",
+	"kind",
+	"traceDuration",
+	"rootName",
+	"rootServiceName",
+	"parent.",
+	"resource.",
+	"span.",
+	"event.",
+	"link.",
+	"instrumentation.",
+}
+
+// maxSuggestionDistance is the maximum edit distance between an identifier and a suggestion
+const maxSuggestionDistance = 2
+
+// suggest returns the intrinsics and scopes within maxSuggestionDistance of the identifier
+func suggest(identifier string) []string {
+	// very short identifiers are close to too many candidates
+	if len(identifier) <= maxSuggestionDistance {
+		return nil
+	}
+
+	var suggestions []string
+	for _, c := range suggestionCandidates {
+		if editDistance(strings.ToLower(identifier), strings.ToLower(strings.TrimSuffix(c, "."))) <= maxSuggestionDistance {
+			suggestions = append(suggestions, c)
+		}
 	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
 }
@@ -23,7 +23,7 @@ func TestPipelineErrors(t *testing.T) {
 		t.Run(tc.in, func(t *testing.T) {
 			_, err := Parse(tc.in)
 
-			require.Equal(t, tc.err, err)
+			require.EqualError(t, err, tc.err.Error())
 		})
 	}
 }
@@ -78,7 +78,7 @@ func TestPipelineOperatorPrecedence(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(tc.expected)}, actual)
 		})
 	}
 }
@@ -134,7 +134,7 @@ func TestPipelineSpansetOperators(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(tc.expected)}, actual)
 		})
 	}
 }
@@ -216,7 +216,7 @@ func TestPipelineScalarOperators(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(tc.expected)}, actual)
 		})
 	}
 }
@@ -256,7 +256,7 @@ func TestPipelines(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{tc.expected}, actual)
+			require.Equal(t, &RootExpr{Pipeline: tc.expected}, actual)
 		})
 	}
 }
@@ -275,11 +275,277 @@ func TestGroupCoalesceErrors(t *testing.T) {
 		t.Run(tc.in, func(t *testing.T) {
 			_, err := Parse(tc.in)
 
-			require.Equal(t, tc.err, err)
+			require.EqualError(t, err, tc.err.Error())
 		})
 	}
 }
 
+func TestSelectOperation(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Pipeline
+	}{
+		{in: "{ true } | select(.a)", expected: newPipeline(newSpansetFilter(NewStaticBool(true)), newSelectOperation([]Attribute{NewAttribute("a")}))},
+		{in: "{ true } | select(span.a, resource.b, name)", expected: newPipeline(
+			newSpansetFilter(NewStaticBool(true)),
+			newSelectOperation([]Attribute{
+				NewScopedAttribute(AttributeScopeSpan, false, "a"),
+				NewScopedAttribute(AttributeScopeResource, false, "b"),
+				NewIntrinsic(IntrinsicName),
+			}),
+		)},
+		{in: "{ true } | select(.a) | count() > 1", expected: newPipeline(
+			newSpansetFilter(NewStaticBool(true)),
+			newSelectOperation([]Attribute{NewAttribute("a")}),
+			newScalarFilter(OpGreater, newAggregate(aggregateCount, nil), NewStaticInt(1)),
+		)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse(tc.in)
+
+			require.NoError(t, err)
+			require.Equal(t, &RootExpr{Pipeline: tc.expected}, actual)
+		})
+	}
+}
+
+func TestMetricsAggregate(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected *RootExpr
+	}{
+		{
+			in: "{ } | rate()",
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(MetricsAggregateRate, nil),
+			),
+		},
+		{
+			in: "{ status = error } | rate() by (resource.service.name, name)",
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(newBinaryOperation(OpEqual, NewIntrinsic(IntrinsicStatus), NewStaticStatus(StatusError)))),
+				newMetricsAggregate(MetricsAggregateRate, []Attribute{
+					NewScopedAttribute(AttributeScopeResource, false, "service.name"),
+					NewIntrinsic(IntrinsicName),
+				}),
+			),
+		},
+		{
+			in: "{ } | count_over_time() by(.a)",
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(MetricsAggregateCountOverTime, []Attribute{NewAttribute("a")}),
+			),
+		},
+		{
+			in: "{ .a = 1 } | select(.b) | quantile_over_time(duration, .99)",
+			expected: newRootExprWithMetrics(
+				newPipeline(
+					newSpansetFilter(newBinaryOperation(OpEqual, NewAttribute("a"), NewStaticInt(1))),
+					newSelectOperation([]Attribute{NewAttribute("b")}),
+				),
+				newQuantileOverTime(NewIntrinsic(IntrinsicDuration), 0.99, nil),
+			),
+		},
+		{
+			in: "{ } | quantile_over_time(duration, 0.5) by (span.a)",
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newQuantileOverTime(NewIntrinsic(IntrinsicDuration), 0.5, []Attribute{NewScopedAttribute(AttributeScopeSpan, false, "a")}),
+			),
+		},
+		{
+			in: "{ } | quantile_over_time(duration, 1)",
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newQuantileOverTime(NewIntrinsic(IntrinsicDuration), 1, nil),
+			),
+		},
+		{
+			in: "{ } | quantile_over_time(duration, 0) by (span.a)",
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newQuantileOverTime(NewIntrinsic(IntrinsicDuration), 0, []Attribute{NewScopedAttribute(AttributeScopeSpan, false, "a")}),
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse(tc.in)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+			require.NoError(t, actual.validate())
+		})
+	}
+}
+
+func TestMetricsAggregateErrors(t *testing.T) {
+	parseFails := []string{
+		"rate()",             // metrics need a spanset pipeline
+		"{ } | rate() | { }", // metrics must be the last stage
+		"{ } | rate(duration)",
+		"{ } | quantile_over_time(duration)",
+		"{ } | rate() by ()",
+		"({ } | rate()) && ({ } | rate())",
+	}
+	for _, q := range parseFails {
+		t.Run(q, func(t *testing.T) {
+			_, err := Parse(q)
+			require.Error(t, err)
+		})
+	}
+
+	validateFails := []string{
+		"{ } | quantile_over_time(duration, 1.5)",
+		"{ } | quantile_over_time(duration, 2)",
+		"{ } | quantile_over_time(name, 0.5)",
+		"{ } | rate() with (sample=0.5)",
+	}
+	for _, q := range validateFails {
+		t.Run(q, func(t *testing.T) {
+			expr, err := Parse(q)
+			require.NoError(t, err)
+			require.Error(t, expr.validate())
+		})
+	}
+}
+
+func TestOrderByLimit(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected *RootExpr
+	}{
+		{
+			in: "{ } | order by duration desc | limit 20",
+			expected: newRootExprWithOrder(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				&OrderBy{Key: OrderKeyDuration, Desc: true},
+				20,
+			),
+		},
+		{
+			in: "{ status = error } | order by startTime",
+			expected: newRootExprWithOrder(
+				newPipeline(newSpansetFilter(newBinaryOperation(OpEqual, NewIntrinsic(IntrinsicStatus), NewStaticStatus(StatusError)))),
+				&OrderBy{Key: OrderKeyStartTime},
+				0,
+			),
+		},
+		{
+			in: "{ } | select(.a) | order by traceDuration asc",
+			expected: newRootExprWithOrder(
+				newPipeline(newSpansetFilter(NewStaticBool(true)), newSelectOperation([]Attribute{NewAttribute("a")})),
+				&OrderBy{Key: OrderKeyDuration},
+				0,
+			),
+		},
+		{
+			in: "{ } | limit 5",
+			expected: newRootExprWithOrder(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				nil,
+				5,
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse(tc.in)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+			require.NoError(t, actual.validate())
+		})
+	}
+}
+
+func TestOrderByLimitErrors(t *testing.T) {
+	parseFails := []string{
+		"{ } | limit 0",
+		"{ } | limit 5 | order by duration",  // limit must be the last stage
+		"{ } | order by duration | { }",      // order by must be the last stage
+		"({ } | order by duration) && ({ })", // order by only applies to the whole search
+		"{ } | order by name",
+		"{ } | order by duration desc | rate()",
+		"{ } | limit",
+	}
+	for _, q := range parseFails {
+		t.Run(q, func(t *testing.T) {
+			_, err := Parse(q)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestHints(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected *RootExpr
+	}{
+		{
+			in: "{ } with (most_recent=true)",
+			expected: &RootExpr{
+				Pipeline: newPipeline(newSpansetFilter(NewStaticBool(true))),
+				Hints:    newHints([]*Hint{newHint(HintMostRecent, NewStaticBool(true))}),
+			},
+		},
+		{
+			in: "{ .a = 1 } | order by duration with (sample=0.1, exhaustive=true, spss=10)",
+			expected: &RootExpr{
+				Pipeline: newPipeline(newSpansetFilter(newBinaryOperation(OpEqual, NewAttribute("a"), NewStaticInt(1)))),
+				OrderBy:  &OrderBy{Key: OrderKeyDuration},
+				Hints: newHints([]*Hint{
+					newHint(HintSample, NewStaticFloat(0.1)),
+					newHint(HintExhaustive, NewStaticBool(true)),
+					newHint(HintSpss, NewStaticInt(10)),
+				}),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse(tc.in)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+			require.NoError(t, actual.validate())
+		})
+	}
+}
+
+func TestHintsGetters(t *testing.T) {
+	expr, err := Parse("{ } with (sample=1, job_size=1000, ingesters=false, job_size=2000)")
+	require.NoError(t, err)
+
+	sample, ok := expr.Hints.GetFloat(HintSample)
+	require.True(t, ok)
+	require.Equal(t, 1.0, sample)
+
+	jobSize, ok := expr.Hints.GetInt(HintJobSize)
+	require.True(t, ok)
+	require.Equal(t, 2000, jobSize) // last value wins
+
+	ingesters, ok := expr.Hints.GetBool(HintIngesters)
+	require.True(t, ok)
+	require.False(t, ingesters)
+
+	_, ok = expr.Hints.GetBool(HintMostRecent)
+	require.False(t, ok)
+
+	// getters are safe on queries without hints
+	expr, err = Parse("{ }")
+	require.NoError(t, err)
+	_, ok = expr.Hints.GetInt(HintSpss)
+	require.False(t, ok)
+}
+
 func TestGroupCoalesceOperation(t *testing.T) {
 	tests := []struct {
 		in       string
@@ -294,7 +560,7 @@ func TestGroupCoalesceOperation(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{tc.expected}, actual)
+			require.Equal(t, &RootExpr{Pipeline: tc.expected}, actual)
 		})
 	}
 }
@@ -311,7 +577,7 @@ func TestSpansetExpressionErrors(t *testing.T) {
 		t.Run(tc.in, func(t *testing.T) {
 			_, err := Parse(tc.in)
 
-			require.Equal(t, tc.err, err)
+			require.EqualError(t, err, tc.err.Error())
 		})
 	}
 }
@@ -363,7 +629,7 @@ func TestSpansetExpressionPrecedence(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(tc.expected)}, actual)
 		})
 	}
 }
@@ -378,6 +644,11 @@ func TestSpansetExpressionOperators(t *testing.T) {
 		{in: "{ true } >> { false }", expected: newSpansetOperation(OpSpansetDescendant, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } || { false }", expected: newSpansetOperation(OpSpansetUnion, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } ~ { false }", expected: newSpansetOperation(OpSpansetSibling, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } < { false }", expected: newSpansetOperation(OpSpansetParent, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } << { false }", expected: newSpansetOperation(OpSpansetAncestor, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } !> { false }", expected: newSpansetOperation(OpSpansetNotChild, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } !>> { false }", expected: newSpansetOperation(OpSpansetNotDescendant, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } !~ { false }", expected: newSpansetOperation(OpSpansetNotSibling, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		// this test was added to highlight the one shift/reduce conflict in the grammar. this could also be parsed as two spanset pipelines &&ed together.
 		{in: "({ true }) && ({ false })", expected: newSpansetOperation(OpSpansetAnd, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 	}
@@ -387,7 +658,7 @@ func TestSpansetExpressionOperators(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(tc.expected)}, actual)
 		})
 	}
 }
@@ -407,7 +678,7 @@ func TestScalarExpressionErrors(t *testing.T) {
 		t.Run(tc.in, func(t *testing.T) {
 			_, err := Parse(tc.in)
 
-			require.Equal(t, tc.err, err)
+			require.EqualError(t, err, tc.err.Error())
 		})
 	}
 }
@@ -444,7 +715,7 @@ func TestScalarExpressionPrecedence(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(tc.expected)}, actual)
 		})
 	}
 }
@@ -459,6 +730,9 @@ func TestScalarExpressionOperators(t *testing.T) {
 		{in: "min(1) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateMin, NewStaticInt(1)), NewStaticInt(1))},
 		{in: "sum(true) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateSum, NewStaticBool(true)), NewStaticInt(1))},
 		{in: "avg(`c`) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateAvg, NewStaticString("c")), NewStaticInt(1))},
+		{in: "quantile(duration, 0.95) > 1s", expected: newScalarFilter(OpGreater, newQuantileAggregate(NewIntrinsic(IntrinsicDuration), 0.95), NewStaticDuration(time.Second))},
+		{in: "quantile(.a, 1) > 1", expected: newScalarFilter(OpGreater, newQuantileAggregate(NewAttribute("a"), 1), NewStaticInt(1))},
+		{in: "count_distinct(resource.a) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateCountDistinct, NewScopedAttribute(AttributeScopeResource, false, "a")), NewStaticInt(1))},
 	}
 
 	for _, tc := range tests {
@@ -466,7 +740,7 @@ func TestScalarExpressionOperators(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(tc.expected)}, actual)
 		})
 	}
 }
@@ -486,7 +760,7 @@ func TestSpansetFilterErrors(t *testing.T) {
 		t.Run(tc.in, func(t *testing.T) {
 			_, err := Parse(tc.in)
 
-			require.Equal(t, tc.err, err)
+			require.EqualError(t, err, tc.err.Error())
 		})
 	}
 }
@@ -605,7 +879,7 @@ func TestSpansetFilterOperatorPrecedence(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(newSpansetFilter(tc.expected))}, actual)
 		})
 	}
 }
@@ -647,7 +921,7 @@ func TestSpansetFilterStatics(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(newSpansetFilter(tc.expected))}, actual)
 		})
 	}
 }
@@ -682,12 +956,26 @@ func TestSpansetFilterOperators(t *testing.T) {
 		{in: "{ .a = 3 }", expected: newBinaryOperation(OpEqual, NewAttribute("a"), NewStaticInt(3)), alsoTestWithoutSpace: true},
 		{in: "{ .a = 3.0 }", expected: newBinaryOperation(OpEqual, NewAttribute("a"), NewStaticFloat(3)), alsoTestWithoutSpace: true},
 		{in: "{ .a = true }", expected: newBinaryOperation(OpEqual, NewAttribute("a"), NewStaticBool(true)), alsoTestWithoutSpace: true},
+
+		// Arrays
+		{in: "{ .a contains `foo` }", expected: newBinaryOperation(OpContains, NewAttribute("a"), NewStaticString("foo"))},
+		{in: "{ span.a contains 3 }", expected: newBinaryOperation(OpContains, NewScopedAttribute(AttributeScopeSpan, false, "a"), NewStaticInt(3))},
+		{in: "{ .a[0] = `foo` }", expected: newBinaryOperation(OpEqual, newIndexOperation(NewAttribute("a"), 0), NewStaticString("foo")), alsoTestWithoutSpace: true},
+		{in: "{ resource.a[12] > 3 }", expected: newBinaryOperation(OpGreater, newIndexOperation(NewScopedAttribute(AttributeScopeResource, false, "a"), 12), NewStaticInt(3)), alsoTestWithoutSpace: true},
+
+		// String functions
+		{in: "{ .a =* `foo` }", expected: newBinaryOperation(OpEqualFold, NewAttribute("a"), NewStaticString("foo")), alsoTestWithoutSpace: true},
+		{in: "{ lower(.a) = `foo` }", expected: newBinaryOperation(OpEqual, newUnaryOperation(OpLower, NewAttribute("a")), NewStaticString("foo")), alsoTestWithoutSpace: true},
+		{in: "{ upper(name) = `FOO` }", expected: newBinaryOperation(OpEqual, newUnaryOperation(OpUpper, NewIntrinsic(IntrinsicName)), NewStaticString("FOO")), alsoTestWithoutSpace: true},
+		{in: "{ startsWith(.a, `foo`) }", expected: newBinaryOperation(OpStartsWith, NewAttribute("a"), NewStaticString("foo"))},
+		{in: "{ endsWith(span.a, `foo`) }", expected: newBinaryOperation(OpEndsWith, NewScopedAttribute(AttributeScopeSpan, false, "a"), NewStaticString("foo"))},
+		{in: "{ contains(lower(.a), `foo`) }", expected: newBinaryOperation(OpSubstring, newUnaryOperation(OpLower, NewAttribute("a")), NewStaticString("foo"))},
 	}
 
 	test := func(q string, expected FieldExpression) {
 		actual, err := Parse(q)
 		require.NoError(t, err, q)
-		require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(expected))}, actual, q)
+		require.Equal(t, &RootExpr{Pipeline: newPipeline(newSpansetFilter(expected))}, actual, q)
 	}
 
 	for _, tc := range tests {
@@ -715,9 +1003,95 @@ func TestAttributeNameErrors(t *testing.T) {
 		t.Run(tc.in, func(t *testing.T) {
 			_, err := Parse(tc.in)
 
-			require.Equal(t, tc.err, err)
+			require.EqualError(t, err, tc.err.Error())
+		})
+	}
+}
+
+func TestParseErrorDetails(t *testing.T) {
+	tests := []struct {
+		in          string
+		line        int
+		col, endCol int
+		token       string
+		expected    []string
+		suggestions []string
+	}{
+		{in: "{ .a | .b }", line: 1, col: 6, endCol: 7, token: "|"},
+		{in: "{ .a } | { .b", line: 1, col: 14, endCol: 14, token: "$end"},
+		{in: "count(avg)", line: 1, col: 7, endCol: 10, token: "avg", expected: []string{")"}},
+		{in: "{ .a = 1 } !>> { .b = 2 } >>> { }", line: 1, col: 29, endCol: 30, token: ">", expected: []string{"{", "("}},
+		{in: "{ durration > 1s }", line: 1, col: 3, endCol: 12, token: "durration", suggestions: []string{"duration"}},
+		{in: "{ spn.foo = 1 }", line: 1, col: 3, endCol: 6, token: "spn", suggestions: []string{"span."}},
+		{in: "{ rootname = `foo` }", line: 1, col: 3, endCol: 11, token: "rootname", suggestions: []string{"rootName"}},
+		{in: "wharblgarbl", line: 1, col: 1, endCol: 12, token: "wharblgarbl"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			_, err := Parse(tc.in)
+
+			var parseErr ParseError
+			require.ErrorAs(t, err, &parseErr)
+			require.Equal(t, tc.line, parseErr.Line())
+			require.Equal(t, tc.col, parseErr.Column())
+			require.Equal(t, tc.endCol, parseErr.EndColumn())
+			require.Equal(t, tc.token, parseErr.Token())
+			require.Equal(t, tc.expected, parseErr.Expected())
+			require.Equal(t, tc.suggestions, parseErr.Suggestions())
+		})
+	}
+}
+
+func TestParseAndValidateErrors(t *testing.T) {
+	tests := []struct {
+		in     string
+		endCol int
+	}{
+		{in: "{ .a = 1 } | quantile_over_time(duration, 2)", endCol: 45},
+		{in: "{ 1 = `foo` }", endCol: 14},
+		{in: "{ 1 = `foo` \n && .b }", endCol: 13},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			expr, err := Parse(tc.in)
+			require.NoError(t, err)
+			validateErr := expr.validate()
+			require.Error(t, validateErr)
+
+			_, err = ParseAndValidate(tc.in)
+
+			var parseErr ParseError
+			require.ErrorAs(t, err, &parseErr)
+			require.EqualError(t, parseErr.Unwrap(), validateErr.Error())
+			require.EqualError(t, err, "parse error at line 1, col 1: "+validateErr.Error())
+			require.Equal(t, 1, parseErr.Line())
+			require.Equal(t, 1, parseErr.Column())
+			require.Equal(t, tc.endCol, parseErr.EndColumn())
 		})
 	}
+
+	// syntax errors are returned as is
+	_, err := ParseAndValidate("{ .a | .b }")
+	var parseErr ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, 6, parseErr.Column())
+	require.NoError(t, parseErr.Unwrap())
+}
+
+func TestParseAndValidateSearch(t *testing.T) {
+	_, err := ParseAndValidateSearch("{ .a = 1 } | count() > 1")
+	require.NoError(t, err)
+
+	// metrics queries are valid, but not for the search api
+	_, err = ParseAndValidate("{ } | rate()")
+	require.NoError(t, err)
+	_, err = ParseAndValidateSearch("{ } | rate()")
+	var parseErr ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.EqualError(t, err, "parse error at line 1, col 1: metrics queries are not supported by search, use the query range api")
+	require.Equal(t, 13, parseErr.EndColumn())
 }
 
 func TestAttributes(t *testing.T) {
@@ -748,6 +1122,18 @@ func TestAttributes(t *testing.T) {
 		{in: "parent.span.foo", expected: NewScopedAttribute(AttributeScopeSpan, true, "foo")},
 		{in: "parent.resource.foo.bar.baz", expected: NewScopedAttribute(AttributeScopeResource, true, "foo.bar.baz")},
 		{in: "parent.span.foo.bar", expected: NewScopedAttribute(AttributeScopeSpan, true, "foo.bar")},
+		{in: "event.name", expected: NewScopedAttribute(AttributeScopeEvent, false, "name")},
+		{in: "event.exception.type", expected: NewScopedAttribute(AttributeScopeEvent, false, "exception.type")},
+		{in: "link.traceID", expected: NewScopedAttribute(AttributeScopeLink, false, "traceID")},
+		{in: "link.foo.bar", expected: NewScopedAttribute(AttributeScopeLink, false, "foo.bar")},
+		{in: "instrumentation.name", expected: NewScopedAttribute(AttributeScopeInstrumentation, false, "name")},
+		{in: "instrumentation.version", expected: NewScopedAttribute(AttributeScopeInstrumentation, false, "version")},
+		// reserved words are part of the attribute name
+		{in: ".contains", expected: NewAttribute("contains")},
+		{in: ".order.limit", expected: NewAttribute("order.limit")},
+		{in: "span.select", expected: NewScopedAttribute(AttributeScopeSpan, false, "select")},
+		{in: "resource.with", expected: NewScopedAttribute(AttributeScopeResource, false, "with")},
+		{in: "parent.span.startTime", expected: NewScopedAttribute(AttributeScopeSpan, true, "startTime")},
 	}
 
 	for _, tc := range tests {
@@ -756,25 +1142,25 @@ func TestAttributes(t *testing.T) {
 			actual, err := Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(newSpansetFilter(tc.expected))}, actual)
 
 			s = "{" + tc.in + "}"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(newSpansetFilter(tc.expected))}, actual)
 
 			s = "{ (" + tc.in + ") }"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(newSpansetFilter(tc.expected))}, actual)
 
 			s = "{ " + tc.in + " + " + tc.in + " }"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(newBinaryOperation(OpAdd, tc.expected, tc.expected)))}, actual)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(newSpansetFilter(newBinaryOperation(OpAdd, tc.expected, tc.expected)))}, actual)
 		})
 	}
 }
@@ -790,6 +1176,9 @@ func TestIntrinsics(t *testing.T) {
 		{in: "status", expected: IntrinsicStatus},
 		{in: "kind", expected: IntrinsicKind},
 		{in: "parent", expected: IntrinsicParent},
+		{in: "traceDuration", expected: IntrinsicTraceDuration},
+		{in: "rootName", expected: IntrinsicTraceRootSpan},
+		{in: "rootServiceName", expected: IntrinsicTraceRootService},
 	}
 
 	for _, tc := range tests {
@@ -799,7 +1188,7 @@ func TestIntrinsics(t *testing.T) {
 			actual, err := Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeNone,
 					Parent:    false,
@@ -812,7 +1201,7 @@ func TestIntrinsics(t *testing.T) {
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeNone,
 					Parent:    false,
@@ -825,7 +1214,7 @@ func TestIntrinsics(t *testing.T) {
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeSpan,
 					Parent:    false,
@@ -838,7 +1227,7 @@ func TestIntrinsics(t *testing.T) {
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeResource,
 					Parent:    false,
@@ -851,7 +1240,7 @@ func TestIntrinsics(t *testing.T) {
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeNone,
 					Parent:    true,
@@ -865,7 +1254,7 @@ func TestIntrinsics(t *testing.T) {
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeNone,
 					Parent:    true,
@@ -878,7 +1267,7 @@ func TestIntrinsics(t *testing.T) {
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeResource,
 					Parent:    true,
@@ -891,7 +1280,7 @@ func TestIntrinsics(t *testing.T) {
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeSpan,
 					Parent:    true,
@@ -904,14 +1293,15 @@ func TestIntrinsics(t *testing.T) {
 
 func TestParseIdentifier(t *testing.T) {
 	testCases := map[string]Attribute{
-		"name":             NewIntrinsic(IntrinsicName),
-		"status":           NewIntrinsic(IntrinsicStatus),
-		"kind":             NewIntrinsic(IntrinsicKind),
-		".name":            NewAttribute("name"),
-		".status":          NewAttribute("status"),
-		".foo.bar":         NewAttribute("foo.bar"),
-		"resource.foo.bar": NewScopedAttribute(AttributeScopeResource, false, "foo.bar"),
-		"span.foo.bar":     NewScopedAttribute(AttributeScopeSpan, false, "foo.bar"),
+		"name":                 NewIntrinsic(IntrinsicName),
+		"status":               NewIntrinsic(IntrinsicStatus),
+		"kind":                 NewIntrinsic(IntrinsicKind),
+		".name":                NewAttribute("name"),
+		".status":              NewAttribute("status"),
+		".foo.bar":             NewAttribute("foo.bar"),
+		"resource.foo.bar":     NewScopedAttribute(AttributeScopeResource, false, "foo.bar"),
+		"span.foo.bar":         NewScopedAttribute(AttributeScopeSpan, false, "foo.bar"),
+		"instrumentation.name": NewScopedAttribute(AttributeScopeInstrumentation, false, "name"),
 	}
 	for i, expected := range testCases {
 		actual, err := ParseIdentifier(i)
@@ -934,7 +1324,7 @@ func TestEmptyQuery(t *testing.T) {
 		t.Run(tc.in, func(t *testing.T) {
 			actual, err := Parse(tc.in)
 			require.NoError(t, err, tc.in)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(NewStaticBool(true)))}, actual, tc.in)
+			require.Equal(t, &RootExpr{Pipeline: newPipeline(newSpansetFilter(NewStaticBool(true)))}, actual, tc.in)
 		})
 	}
 }
@@ -2,6 +2,8 @@ package traceql
 
 import (
 	"context"
+	"hash/fnv"
+	"math"
 )
 
 type Operands []Static
@@ -12,6 +14,12 @@ type Condition struct {
 	Operands  Operands
 }
 
+// ExistenceCheck returns true if the condition compares the attribute to nil. { .foo != nil } requires
+// the attribute to be present and { .foo = nil } requires it to be missing.
+func (c Condition) ExistenceCheck() bool {
+	return (c.Op == OpEqual || c.Op == OpNotEqual) && len(c.Operands) == 1 && c.Operands[0].Type == TypeNil
+}
+
 func SearchMetaConditions() []Condition {
 	return []Condition{
 		{NewIntrinsic(IntrinsicTraceRootService), OpNone, nil},
@@ -62,6 +70,22 @@ type FetchSpansRequest struct {
 	// TODO: extend this to an arbitrary number of passes
 	SecondPass           SecondPassFn
 	SecondPassConditions []Condition
+
+	// Sample is the fraction of traces to fetch, set by the with(sample=...) hint. 0 fetches
+	// all traces. See SampleTrace.
+	Sample float64
+}
+
+// SampleTrace returns true if the trace is part of a sample of the given fraction of traces. Traces are
+// selected by a hash of their ID so the same traces are sampled in every block and ingester.
+func SampleTrace(traceID []byte, sample float64) bool {
+	if sample <= 0 || sample >= 1 {
+		return true
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write(traceID)
+	return float64(h.Sum64()) < sample*math.MaxUint64
 }
 
 func (f *FetchSpansRequest) appendCondition(c ...Condition) {
@@ -76,6 +100,12 @@ type Span interface {
 	ID() []byte
 	StartTimeUnixNanos() uint64
 	DurationNanos() uint64
+
+	// these are used by the engine to evaluate structural operators. they are only
+	// guaranteed to work if the structural intrinsics were requested from the storage layer
+	DescendantOf(Span) bool
+	ChildOf(Span) bool
+	SiblingOf(Span) bool
 }
 
 const attributeMatched = "__matched"
@@ -19,12 +19,15 @@ func New(buckets [64]int) *LatencyHistogram {
 }
 
 func (m *LatencyHistogram) Record(durationNanos uint64) {
-	// Increment bucket that matches log2(duration)
-	var bucket int
-	if durationNanos >= 2 {
-		bucket = int(math.Ceil(math.Log2(float64(durationNanos))))
+	m.buckets[bucketFor(durationNanos)]++
+}
+
+// bucketFor returns the bucket that matches log2(duration)
+func bucketFor(durationNanos uint64) int {
+	if durationNanos < 2 {
+		return 0
 	}
-	m.buckets[bucket]++
+	return int(math.Ceil(math.Log2(float64(durationNanos))))
 }
 
 func (m *LatencyHistogram) Count() int {
@@ -84,16 +87,41 @@ type MetricsResults struct {
 	SpanCount int
 	Series    map[traceql.Static]*LatencyHistogram
 	Errors    map[traceql.Static]int
+
+	// keys holds the first static recorded for each value so that equal arrays share one series
+	keys map[traceql.StaticMapKey]traceql.Static
 }
 
 func NewMetricsResults() *MetricsResults {
 	return &MetricsResults{
 		Series: map[traceql.Static]*LatencyHistogram{},
 		Errors: map[traceql.Static]int{},
+		keys:   map[traceql.StaticMapKey]traceql.Static{},
 	}
 }
 
+// seriesKey returns the static used as the key in Series and Errors for the given value. Statics
+// compare arrays by reference so equal arrays are mapped to the first one seen.
+func (m *MetricsResults) seriesKey(series traceql.Static) traceql.Static {
+	if series.Type != traceql.TypeArray {
+		return series
+	}
+
+	if m.keys == nil {
+		m.keys = map[traceql.StaticMapKey]traceql.Static{}
+	}
+
+	k := series.MapKey()
+	if existing, ok := m.keys[k]; ok {
+		return existing
+	}
+	m.keys[k] = series
+	return series
+}
+
 func (m *MetricsResults) Record(series traceql.Static, durationNanos uint64, err bool) {
+	series = m.seriesKey(series)
+
 	s := m.Series[series]
 	if s == nil {
 		s = &LatencyHistogram{}
@@ -114,6 +142,7 @@ func (m *MetricsResults) Combine(other *MetricsResults) {
 	}
 
 	for k, v := range other.Series {
+		k = m.seriesKey(k)
 		s := m.Series[k]
 		if s == nil {
 			s = &LatencyHistogram{}
@@ -123,7 +152,7 @@ func (m *MetricsResults) Combine(other *MetricsResults) {
 	}
 
 	for k, v := range other.Errors {
-		m.Errors[k] += v
+		m.Errors[m.seriesKey(k)] += v
 	}
 }
 
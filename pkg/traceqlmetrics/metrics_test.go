@@ -77,6 +77,27 @@ func TestMetricsResultsCombine(t *testing.T) {
 	require.Equal(t, 1, m.Errors[c])
 }
 
+func TestMetricsResultsCombineArrays(t *testing.T) {
+	a := traceql.NewStaticArray([]traceql.Static{traceql.NewStaticString("1")})
+	a2 := traceql.NewStaticArray([]traceql.Static{traceql.NewStaticString("1")})
+	b := traceql.NewStaticArray([]traceql.Static{traceql.NewStaticInt(1)})
+
+	m := NewMetricsResults()
+	m.Record(a, 1, true)
+	m.Record(a2, 1, false)
+
+	m2 := NewMetricsResults()
+	m2.Record(a2, 1, true)
+	m2.Record(b, 1, false)
+
+	m.Combine(m2)
+
+	require.Equal(t, 2, len(m.Series))
+	require.Equal(t, 3, m.Series[a].Count())
+	require.Equal(t, 2, m.Errors[a])
+	require.Equal(t, 1, m.Series[b].Count())
+}
+
 func TestGetMetrics(t *testing.T) {
 
 	ctx := context.TODO()
@@ -7,6 +7,7 @@ import (
 )
 
 type mockSpan struct {
+	start    uint64
 	duration uint64
 	attrs    map[traceql.Attribute]traceql.Static
 }
@@ -33,10 +34,18 @@ func (m *mockSpan) WithErr() *mockSpan {
 	return m
 }
 
+func (m *mockSpan) WithStart(start uint64) *mockSpan {
+	m.start = start
+	return m
+}
+
 func (m *mockSpan) Attributes() map[traceql.Attribute]traceql.Static { return m.attrs }
 func (m *mockSpan) ID() []byte                                       { return nil }
-func (m *mockSpan) StartTimeUnixNanos() uint64                       { return 0 }
+func (m *mockSpan) StartTimeUnixNanos() uint64                       { return m.start }
 func (m *mockSpan) DurationNanos() uint64                            { return m.duration }
+func (m *mockSpan) DescendantOf(traceql.Span) bool                   { return false }
+func (m *mockSpan) ChildOf(traceql.Span) bool                        { return false }
+func (m *mockSpan) SiblingOf(traceql.Span) bool                      { return false }
 
 type mockFetcher struct {
 	filter   traceql.SecondPassFn
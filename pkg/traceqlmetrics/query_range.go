@@ -0,0 +1,418 @@
+package traceqlmetrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	// MaxQueryRangeIntervals is the maximum number of intervals in a range query. It is the
+	// same limit as Prometheus has for the number of points per series.
+	MaxQueryRangeIntervals = 11_000
+
+	// labelBucket is added to the series returned by QueryRange for quantiles. It holds the
+	// exponential duration bucket of the counts and is removed by the combiner.
+	labelBucket = "__bucket"
+)
+
+// QueryRangeRequest is a TraceQL metrics query evaluated over [Start, End) in intervals of Step.
+// All values are in nanoseconds.
+type QueryRangeRequest struct {
+	Query string
+	Start uint64
+	End   uint64
+	Step  uint64
+}
+
+// QueryRangeRequestFromProto converts the request sent to the ingesters
+func QueryRangeRequestFromProto(req *tempopb.QueryRangeRequest) *QueryRangeRequest {
+	return &QueryRangeRequest{
+		Query: req.Query,
+		Start: req.Start,
+		End:   req.End,
+		Step:  req.Step,
+	}
+}
+
+// Proto converts the request to the request sent to the ingesters
+func (r *QueryRangeRequest) Proto() *tempopb.QueryRangeRequest {
+	return &tempopb.QueryRangeRequest{
+		Query: r.Query,
+		Start: r.Start,
+		End:   r.End,
+		Step:  r.Step,
+	}
+}
+
+func (r *QueryRangeRequest) Validate() error {
+	if r.Step == 0 {
+		return errors.New("step must be greater than 0")
+	}
+	if r.End <= r.Start {
+		return fmt.Errorf("start must be before end. received start=%d end=%d", r.Start, r.End)
+	}
+	if r.intervals() > MaxQueryRangeIntervals {
+		return fmt.Errorf("exceeded maximum resolution of %d points per series. try decreasing the range or increasing the step", MaxQueryRangeIntervals)
+	}
+	return nil
+}
+
+func (r *QueryRangeRequest) intervals() int {
+	return int((r.End - r.Start + r.Step - 1) / r.Step)
+}
+
+// interval returns the interval that contains ts or -1 if it is outside of the range
+func (r *QueryRangeRequest) interval(ts uint64) int {
+	if ts < r.Start || ts >= r.End {
+		return -1
+	}
+	return int((ts - r.Start) / r.Step)
+}
+
+// timestampMs returns the timestamp of the interval. It is the start of the interval.
+func (r *QueryRangeRequest) timestampMs(interval int) int64 {
+	return int64(r.Start+uint64(interval)*r.Step) / int64(time.Millisecond)
+}
+
+// QueryRange evaluates a metrics query on the spans returned by the fetcher. The returned series
+// hold the raw counts per interval. They can be summed across fetchers, i.e. blocks, and have to
+// be passed through a QueryRangeCombiner to get the final values.
+func QueryRange(ctx context.Context, req *QueryRangeRequest, fetcher traceql.SpansetFetcher) ([]*prompb.TimeSeries, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	metrics, eval, fetchReq, err := traceql.NewEngine().CompileMetricsQueryRange(req.Query)
+	if err != nil {
+		return nil, errors.Wrap(err, "compiling query")
+	}
+
+	// Span start time is needed to find the interval of each span. It is fetched without
+	// filtering, with the fields needed by the metrics, for the spans matched by the pipeline.
+	fetchReq.StartTimeUnixNanos = req.Start
+	fetchReq.EndTimeUnixNanos = req.End
+	fetchReq.SecondPassConditions = append(fetchReq.SecondPassConditions, traceql.Condition{
+		Attribute: traceql.NewIntrinsic(traceql.IntrinsicSpanStartTime),
+	})
+	fetchReq.SecondPass = func(ss *traceql.Spanset) ([]*traceql.Spanset, error) {
+		if len(ss.Spans) == 0 {
+			return nil, nil
+		}
+		return eval([]*traceql.Spanset{ss})
+	}
+
+	res, err := fetcher.Fetch(ctx, *fetchReq)
+	if err == util.ErrUnsupported {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Results.Close()
+
+	series := map[string]*rawSeries{}
+	for {
+		ss, err := res.Results.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ss == nil {
+			break
+		}
+
+		for _, s := range ss.Spans {
+			i := req.interval(s.StartTimeUnixNanos())
+			if i < 0 {
+				continue
+			}
+
+			lbls := groupByLabels(metrics, s)
+			if metrics.Op == traceql.MetricsAggregateQuantileOverTime {
+				lbls = append(lbls, prompb.Label{Name: labelBucket, Value: strconv.Itoa(bucketFor(s.DurationNanos()))})
+			}
+
+			getOrCreate(series, lbls).samples[req.timestampMs(i)]++
+		}
+	}
+
+	return sortedTimeSeries(series, func(s *rawSeries) []prompb.Sample {
+		return s.sortedSamples()
+	}), nil
+}
+
+// QueryRangeCombiner sums the raw series returned by QueryRange and computes the final
+// values of the metrics query.
+type QueryRangeCombiner struct {
+	req     *QueryRangeRequest
+	metrics *traceql.MetricsAggregate
+	series  map[string]*rawSeries
+}
+
+func NewQueryRangeCombiner(req *QueryRangeRequest) (*QueryRangeCombiner, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	metrics, _, _, err := traceql.NewEngine().CompileMetricsQueryRange(req.Query)
+	if err != nil {
+		return nil, errors.Wrap(err, "compiling query")
+	}
+
+	return &QueryRangeCombiner{
+		req:     req,
+		metrics: metrics,
+		series:  map[string]*rawSeries{},
+	}, nil
+}
+
+func (c *QueryRangeCombiner) Combine(series []*prompb.TimeSeries) {
+	sumInto(c.series, series)
+}
+
+// Results returns the final series sorted by their labels
+func (c *QueryRangeCombiner) Results() []*prompb.TimeSeries {
+	if c.metrics.Op == traceql.MetricsAggregateQuantileOverTime {
+		return c.quantiles()
+	}
+
+	step := time.Duration(c.req.Step).Seconds()
+	return sortedTimeSeries(c.series, func(s *rawSeries) []prompb.Sample {
+		samples := make([]prompb.Sample, 0, c.req.intervals())
+		for i := 0; i < c.req.intervals(); i++ {
+			ts := c.req.timestampMs(i)
+
+			v := s.samples[ts]
+			if c.metrics.Op == traceql.MetricsAggregateRate {
+				v /= step
+			}
+			samples = append(samples, prompb.Sample{Timestamp: ts, Value: v})
+		}
+		return samples
+	})
+}
+
+// quantiles merges the duration buckets of each series into histograms and returns the quantile in seconds
+// for each interval with spans
+func (c *QueryRangeCombiner) quantiles() []*prompb.TimeSeries {
+	histograms := map[string]*rawHistograms{}
+	for _, s := range c.series {
+		lbls := make([]prompb.Label, 0, len(s.labels))
+		bucket := -1
+		for _, l := range s.labels {
+			if l.Name == labelBucket {
+				bucket, _ = strconv.Atoi(l.Value)
+				continue
+			}
+			lbls = append(lbls, l)
+		}
+		if bucket < 0 || bucket >= len(LatencyHistogram{}.buckets) {
+			continue
+		}
+
+		key := labelsKey(lbls)
+		h := histograms[key]
+		if h == nil {
+			h = &rawHistograms{labels: lbls, histograms: map[int64]*LatencyHistogram{}}
+			histograms[key] = h
+		}
+		for ts, v := range s.samples {
+			hist := h.histograms[ts]
+			if hist == nil {
+				hist = &LatencyHistogram{}
+				h.histograms[ts] = hist
+			}
+			hist.buckets[bucket] += int(math.Round(v)) // counts of replicated data are fractional
+		}
+	}
+
+	results := make([]*prompb.TimeSeries, 0, len(histograms))
+	for _, h := range histograms {
+		ts := &prompb.TimeSeries{Labels: h.labels}
+		for i := 0; i < c.req.intervals(); i++ {
+			hist := h.histograms[c.req.timestampMs(i)]
+			if hist == nil || hist.Count() == 0 {
+				continue
+			}
+			ts.Samples = append(ts.Samples, prompb.Sample{
+				Timestamp: c.req.timestampMs(i),
+				Value:     time.Duration(hist.Percentile(float32(c.metrics.Quantile))).Seconds(),
+			})
+		}
+		results = append(results, ts)
+	}
+	sortTimeSeries(results)
+
+	return results
+}
+
+// RawSeriesCombiner sums the raw series returned by QueryRange for several fetchers, i.e. blocks,
+// without computing the final values of the query.
+type RawSeriesCombiner struct {
+	series map[string]*rawSeries
+}
+
+func NewRawSeriesCombiner() *RawSeriesCombiner {
+	return &RawSeriesCombiner{
+		series: map[string]*rawSeries{},
+	}
+}
+
+func (c *RawSeriesCombiner) Combine(series []*prompb.TimeSeries) {
+	sumInto(c.series, series)
+}
+
+// Results returns the summed series sorted by their labels
+func (c *RawSeriesCombiner) Results() []*prompb.TimeSeries {
+	return sortedTimeSeries(c.series, func(s *rawSeries) []prompb.Sample {
+		return s.sortedSamples()
+	})
+}
+
+// ScaleSeries multiplies the samples of raw series by f. It's used to correct the counts of data that is
+// stored more than once, like traces in the ingesters and in level 0 blocks with a replication factor above 1.
+func ScaleSeries(series []*prompb.TimeSeries, f float64) {
+	for _, ts := range series {
+		for i := range ts.Samples {
+			ts.Samples[i].Value *= f
+		}
+	}
+}
+
+// SeriesToProto converts raw series to the series returned by the ingesters
+func SeriesToProto(series []*prompb.TimeSeries) []*tempopb.QueryRangeSeries {
+	out := make([]*tempopb.QueryRangeSeries, 0, len(series))
+	for _, ts := range series {
+		s := &tempopb.QueryRangeSeries{
+			Labels:  make([]*tempopb.QueryRangeLabel, 0, len(ts.Labels)),
+			Samples: make([]*tempopb.QueryRangeSample, 0, len(ts.Samples)),
+		}
+		for _, l := range ts.Labels {
+			s.Labels = append(s.Labels, &tempopb.QueryRangeLabel{Name: l.Name, Value: l.Value})
+		}
+		for _, sample := range ts.Samples {
+			s.Samples = append(s.Samples, &tempopb.QueryRangeSample{TimestampMs: sample.Timestamp, Value: sample.Value})
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// SeriesFromProto converts the series returned by the ingesters to raw series
+func SeriesFromProto(series []*tempopb.QueryRangeSeries) []*prompb.TimeSeries {
+	out := make([]*prompb.TimeSeries, 0, len(series))
+	for _, s := range series {
+		ts := &prompb.TimeSeries{
+			Labels:  make([]prompb.Label, 0, len(s.Labels)),
+			Samples: make([]prompb.Sample, 0, len(s.Samples)),
+		}
+		for _, l := range s.Labels {
+			ts.Labels = append(ts.Labels, prompb.Label{Name: l.Name, Value: l.Value})
+		}
+		for _, sample := range s.Samples {
+			ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: sample.TimestampMs, Value: sample.Value})
+		}
+		out = append(out, ts)
+	}
+	return out
+}
+
+func sumInto(dst map[string]*rawSeries, series []*prompb.TimeSeries) {
+	for _, ts := range series {
+		s := getOrCreate(dst, ts.Labels)
+		for _, sample := range ts.Samples {
+			s.samples[sample.Timestamp] += sample.Value
+		}
+	}
+}
+
+type rawSeries struct {
+	labels  []prompb.Label
+	samples map[int64]float64 // timestamp ms -> value
+}
+
+func (s *rawSeries) sortedSamples() []prompb.Sample {
+	samples := make([]prompb.Sample, 0, len(s.samples))
+	for ts, v := range s.samples {
+		samples = append(samples, prompb.Sample{Timestamp: ts, Value: v})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Timestamp < samples[j].Timestamp
+	})
+	return samples
+}
+
+type rawHistograms struct {
+	labels     []prompb.Label
+	histograms map[int64]*LatencyHistogram // timestamp ms -> histogram
+}
+
+// groupByLabels returns the by() values of the span as labels. Spans that don't have an attribute
+// don't get the label.
+func groupByLabels(metrics *traceql.MetricsAggregate, s traceql.Span) []prompb.Label {
+	values := metrics.GroupBy(s)
+
+	lbls := make([]prompb.Label, 0, len(values)+1)
+	for i, v := range values {
+		if v.Type == traceql.TypeNil {
+			continue
+		}
+		lbls = append(lbls, prompb.Label{Name: metrics.By[i].String(), Value: v.EncodeToString(false)})
+	}
+	return lbls
+}
+
+func getOrCreate(series map[string]*rawSeries, lbls []prompb.Label) *rawSeries {
+	sort.Slice(lbls, func(i, j int) bool {
+		return lbls[i].Name < lbls[j].Name
+	})
+
+	key := labelsKey(lbls)
+	s := series[key]
+	if s == nil {
+		s = &rawSeries{labels: lbls, samples: map[int64]float64{}}
+		series[key] = s
+	}
+	return s
+}
+
+func labelsKey(lbls []prompb.Label) string {
+	var sb strings.Builder
+	for _, l := range lbls {
+		sb.WriteString(l.Name)
+		sb.WriteByte(0xff)
+		sb.WriteString(l.Value)
+		sb.WriteByte(0xff)
+	}
+	return sb.String()
+}
+
+func sortedTimeSeries(series map[string]*rawSeries, samples func(*rawSeries) []prompb.Sample) []*prompb.TimeSeries {
+	results := make([]*prompb.TimeSeries, 0, len(series))
+	for _, s := range series {
+		results = append(results, &prompb.TimeSeries{
+			Labels:  s.labels,
+			Samples: samples(s),
+		})
+	}
+	sortTimeSeries(results)
+
+	return results
+}
+
+func sortTimeSeries(series []*prompb.TimeSeries) {
+	sort.Slice(series, func(i, j int) bool {
+		return labelsKey(series[i].Labels) < labelsKey(series[j].Labels)
+	})
+}
@@ -0,0 +1,153 @@
+package traceqlmetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/traceql"
+)
+
+func TestQueryRangeRequestValidate(t *testing.T) {
+	s := uint64(time.Second)
+
+	require.NoError(t, (&QueryRangeRequest{Start: 10 * s, End: 20 * s, Step: s}).Validate())
+	require.Error(t, (&QueryRangeRequest{Start: 10 * s, End: 20 * s}).Validate())
+	require.Error(t, (&QueryRangeRequest{Start: 20 * s, End: 10 * s, Step: s}).Validate())
+	require.Error(t, (&QueryRangeRequest{Start: 0, End: 20_000 * s, Step: s}).Validate())
+}
+
+func TestQueryRange(t *testing.T) {
+	s := uint64(time.Second)
+	req := &QueryRangeRequest{
+		Query: "{ } | rate() by (span.foo)",
+		Start: 10 * s,
+		End:   40 * s,
+		Step:  10 * s,
+	}
+
+	newFetcher := func() *mockFetcher {
+		return &mockFetcher{
+			Spansets: []*traceql.Spanset{
+				{
+					Spans: []traceql.Span{
+						newMockSpan(128, "span.foo", "1").WithStart(10 * s),
+						newMockSpan(128, "span.foo", "1").WithStart(15 * s),
+						newMockSpan(128).WithStart(12 * s),
+						newMockSpan(128, "span.foo", "2").WithStart(25 * s),
+						newMockSpan(128, "span.foo", "1").WithStart(35 * s),
+						newMockSpan(128, "span.foo", "1").WithStart(45 * s), // outside of the range
+					},
+				},
+			},
+		}
+	}
+
+	raw, err := QueryRange(context.Background(), req, newFetcher())
+	require.NoError(t, err)
+	require.Equal(t, []*prompb.TimeSeries{
+		{
+			Labels:  []prompb.Label{},
+			Samples: []prompb.Sample{{Timestamp: 10_000, Value: 1}},
+		},
+		{
+			Labels:  []prompb.Label{{Name: "span.foo", Value: "1"}},
+			Samples: []prompb.Sample{{Timestamp: 10_000, Value: 2}, {Timestamp: 30_000, Value: 1}},
+		},
+		{
+			Labels:  []prompb.Label{{Name: "span.foo", Value: "2"}},
+			Samples: []prompb.Sample{{Timestamp: 20_000, Value: 1}},
+		},
+	}, raw)
+
+	// two shards with the same data
+	c, err := NewQueryRangeCombiner(req)
+	require.NoError(t, err)
+	c.Combine(raw)
+	raw, err = QueryRange(context.Background(), req, newFetcher())
+	require.NoError(t, err)
+	c.Combine(raw)
+
+	require.Equal(t, []*prompb.TimeSeries{
+		{
+			Labels:  []prompb.Label{},
+			Samples: []prompb.Sample{{Timestamp: 10_000, Value: 0.2}, {Timestamp: 20_000, Value: 0}, {Timestamp: 30_000, Value: 0}},
+		},
+		{
+			Labels:  []prompb.Label{{Name: "span.foo", Value: "1"}},
+			Samples: []prompb.Sample{{Timestamp: 10_000, Value: 0.4}, {Timestamp: 20_000, Value: 0}, {Timestamp: 30_000, Value: 0.2}},
+		},
+		{
+			Labels:  []prompb.Label{{Name: "span.foo", Value: "2"}},
+			Samples: []prompb.Sample{{Timestamp: 10_000, Value: 0}, {Timestamp: 20_000, Value: 0.2}, {Timestamp: 30_000, Value: 0}},
+		},
+	}, c.Results())
+}
+
+func TestQueryRangeQuantile(t *testing.T) {
+	s := uint64(time.Second)
+	req := &QueryRangeRequest{
+		Query: "{ } | quantile_over_time(duration, 0.5)",
+		Start: 10 * s,
+		End:   30 * s,
+		Step:  10 * s,
+	}
+
+	m := &mockFetcher{
+		Spansets: []*traceql.Spanset{
+			{
+				Spans: []traceql.Span{
+					newMockSpan(128, "span.foo", "1").WithStart(10 * s),
+					newMockSpan(128, "span.foo", "1").WithStart(11 * s), // p50
+					newMockSpan(256, "span.foo", "1").WithStart(12 * s),
+					newMockSpan(256, "span.foo", "1").WithStart(13 * s),
+				},
+			},
+		},
+	}
+
+	raw, err := QueryRange(context.Background(), req, m)
+	require.NoError(t, err)
+
+	c, err := NewQueryRangeCombiner(req)
+	require.NoError(t, err)
+	c.Combine(raw)
+
+	// intervals without spans have no quantile
+	require.Equal(t, []*prompb.TimeSeries{
+		{
+			Labels:  []prompb.Label{},
+			Samples: []prompb.Sample{{Timestamp: 10_000, Value: time.Duration(128).Seconds()}},
+		},
+	}, c.Results())
+}
+
+func TestQueryRangeNotAMetricsQuery(t *testing.T) {
+	_, err := QueryRange(context.Background(), &QueryRangeRequest{Query: "{ }", Start: 1, End: 2, Step: 1}, &mockFetcher{})
+	require.Error(t, err)
+}
+
+func TestRawSeriesCombiner(t *testing.T) {
+	c := NewRawSeriesCombiner()
+	c.Combine([]*prompb.TimeSeries{
+		{Labels: []prompb.Label{{Name: "span.foo", Value: "b"}}, Samples: []prompb.Sample{{Timestamp: 10_000, Value: 1}}},
+		{Labels: []prompb.Label{{Name: "span.foo", Value: "a"}}, Samples: []prompb.Sample{{Timestamp: 20_000, Value: 2}}},
+	})
+	c.Combine([]*prompb.TimeSeries{
+		{Labels: []prompb.Label{{Name: "span.foo", Value: "a"}}, Samples: []prompb.Sample{{Timestamp: 10_000, Value: 3}, {Timestamp: 20_000, Value: 4}}},
+	})
+
+	results := c.Results()
+	ScaleSeries(results, 0.5)
+
+	require.Equal(t, []*prompb.TimeSeries{
+		{Labels: []prompb.Label{{Name: "span.foo", Value: "a"}}, Samples: []prompb.Sample{{Timestamp: 10_000, Value: 1.5}, {Timestamp: 20_000, Value: 3}}},
+		{Labels: []prompb.Label{{Name: "span.foo", Value: "b"}}, Samples: []prompb.Sample{{Timestamp: 10_000, Value: 0.5}}},
+	}, results)
+
+	// round trip through the ingester response
+	require.Equal(t, results, SeriesFromProto(SeriesToProto(results)))
+}
@@ -162,6 +162,20 @@ func (c *Client) QueryTrace(id string) (*tempopb.Trace, error) {
 	return m, nil
 }
 
+// QueryTraceSummary returns the summary of a trace instead of its spans
+func (c *Client) QueryTraceSummary(id string) (*tempopb.TraceSummary, error) {
+	m := &tempopb.TraceSummary{}
+	resp, err := c.getFor(c.BaseURL+QueryTraceEndpoint+"/"+id+"/summary", m)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, ErrTraceNotFound
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
 func (c *Client) SearchTraceQL(query string) (*tempopb.SearchResponse, error) {
 	m := &tempopb.SearchResponse{}
 	_, err := c.getFor(c.buildQueryURL("q", query, 0, 0), m)
@@ -3,7 +3,7 @@ package util
 import "sync"
 
 type DistinctValueCollector[T comparable] struct {
-	values   map[T]struct{}
+	values   map[T]uint64
 	len      func(T) int
 	maxLen   int
 	currLen  int
@@ -16,7 +16,7 @@ type DistinctValueCollector[T comparable] struct {
 // is interpreted as unlimited.
 func NewDistinctValueCollector[T comparable](maxDataSize int, len func(T) int) *DistinctValueCollector[T] {
 	return &DistinctValueCollector[T]{
-		values: make(map[T]struct{}),
+		values: make(map[T]uint64),
 		maxLen: maxDataSize,
 		len:    len,
 	}
@@ -49,7 +49,30 @@ func (d *DistinctValueCollector[T]) Collect(v T) (exceeded bool) {
 		return true
 	}
 
-	d.values[v] = struct{}{}
+	d.values[v] = 1
+	d.currLen += len
+	return false
+}
+
+// CollectWithCount collects the value and adds count to the number of times it was seen. Counts
+// are only accurate if all values are collected with this method.
+func (d *DistinctValueCollector[T]) CollectWithCount(v T, count uint64) (exceeded bool) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if _, ok := d.values[v]; ok {
+		d.values[v] += count
+		return
+	}
+
+	len := d.len(v)
+	d.totalLen += len
+
+	if d.maxLen > 0 && d.currLen+len > d.maxLen {
+		return true
+	}
+
+	d.values[v] = count
 	d.currLen += len
 	return false
 }
@@ -68,6 +91,19 @@ func (d *DistinctValueCollector[T]) Values() []T {
 	return ss
 }
 
+// Counts returns the values collected and the number of times they were seen
+func (d *DistinctValueCollector[T]) Counts() map[T]uint64 {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+
+	counts := make(map[T]uint64, len(d.values))
+	for k, c := range d.values {
+		counts[k] = c
+	}
+
+	return counts
+}
+
 // Exceeded indicates if some values were lost because the maximum size limit was met.
 func (d *DistinctValueCollector[T]) Exceeded() bool {
 	d.mtx.RLock()
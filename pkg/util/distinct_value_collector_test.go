@@ -0,0 +1,24 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistinctValueCollectorCounts(t *testing.T) {
+	d := NewDistinctValueCollector(10, func(s string) int { return len(s) })
+
+	d.CollectWithCount("123", 1)
+	d.CollectWithCount("4567", 2)
+	d.CollectWithCount("123", 3)
+	d.CollectWithCount("890", 1)
+	d.CollectWithCount("11", 5)
+
+	require.True(t, d.Exceeded())
+	require.Equal(t, map[string]uint64{
+		"123":  4,
+		"4567": 2,
+		"890":  1,
+	}, d.Counts())
+}
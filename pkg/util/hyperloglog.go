@@ -0,0 +1,99 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// hyperLogLogPrecision is the number of hash bits used to pick a register. 2^12 registers
+	// have a standard error of 1.6% and marshal to 4KiB.
+	hyperLogLogPrecision = 12
+	hyperLogLogRegisters = 1 << hyperLogLogPrecision
+)
+
+// HyperLogLog estimates the number of distinct values inserted into it in constant memory.
+// Sketches can be merged to estimate the number of distinct values across all of them.
+// It is not safe for concurrent use.
+type HyperLogLog struct {
+	registers []uint8
+}
+
+func NewHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{
+		registers: make([]uint8, hyperLogLogRegisters),
+	}
+}
+
+// HyperLogLogFromBytes restores a sketch marshalled with Bytes
+func HyperLogLogFromBytes(b []byte) (*HyperLogLog, error) {
+	if len(b) != hyperLogLogRegisters {
+		return nil, fmt.Errorf("invalid hyperloglog sketch of %d bytes, expected %d", len(b), hyperLogLogRegisters)
+	}
+
+	h := NewHyperLogLog()
+	copy(h.registers, b)
+	return h, nil
+}
+
+func (h *HyperLogLog) InsertString(s string) {
+	h.insertHash(xxhash.Sum64String(s))
+}
+
+func (h *HyperLogLog) Insert(b []byte) {
+	h.insertHash(xxhash.Sum64(b))
+}
+
+func (h *HyperLogLog) insertHash(x uint64) {
+	// the first bits select the register, the position of the first set bit of the remaining ones is
+	// recorded. the added bit bounds the position if all remaining bits are 0.
+	i := x >> (64 - hyperLogLogPrecision)
+	rank := uint8(bits.LeadingZeros64(x<<hyperLogLogPrecision|1<<(hyperLogLogPrecision-1))) + 1
+
+	if rank > h.registers[i] {
+		h.registers[i] = rank
+	}
+}
+
+// Merge adds the values of other to the sketch
+func (h *HyperLogLog) Merge(other *HyperLogLog) {
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+}
+
+// Estimate returns the estimated number of distinct values
+func (h *HyperLogLog) Estimate() uint64 {
+	const m = float64(hyperLogLogRegisters)
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+
+	// small cardinalities are estimated more accurately by counting the empty registers
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+// Bytes returns the registers of the sketch
+func (h *HyperLogLog) Bytes() []byte {
+	b := make([]byte, len(h.registers))
+	copy(b, h.registers)
+	return b
+}
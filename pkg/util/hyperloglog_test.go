@@ -0,0 +1,43 @@
+package util
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000, 100_000} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			h := NewHyperLogLog()
+			for i := 0; i < n; i++ {
+				h.InsertString(strconv.Itoa(i))
+				// duplicates are not counted
+				h.InsertString(strconv.Itoa(i))
+			}
+
+			require.InDelta(t, n, h.Estimate(), float64(n)*0.05)
+		})
+	}
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	a := NewHyperLogLog()
+	b := NewHyperLogLog()
+
+	// half of the values are in both sketches
+	for i := 0; i < 10_000; i++ {
+		a.InsertString(strconv.Itoa(i))
+		b.InsertString(strconv.Itoa(i + 5_000))
+	}
+
+	restored, err := HyperLogLogFromBytes(b.Bytes())
+	require.NoError(t, err)
+
+	a.Merge(restored)
+	require.InDelta(t, 15_000, a.Estimate(), 15_000*0.05)
+
+	_, err = HyperLogLogFromBytes([]byte{1, 2, 3})
+	require.Error(t, err)
+}
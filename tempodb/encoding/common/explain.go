@@ -0,0 +1,80 @@
+package common
+
+import (
+	"sort"
+
+	"github.com/grafana/tempo/pkg/parquetquery"
+)
+
+// ExplainedColumn is a column that is read to fetch a TraceQL condition
+type ExplainedColumn struct {
+	Column string
+	// PushedDown is true if the condition is evaluated as a predicate while the column is read
+	PushedDown bool
+}
+
+type recordedColumn struct {
+	column   string
+	selectAs string
+	keepsAll bool
+}
+
+// ColumnRecorder records the columns that iterators are created for without reading them. Fetches are
+// explained by building their iterators with MakeIter.
+type ColumnRecorder struct {
+	columns []recordedColumn
+}
+
+// MakeIter records the column and returns an iterator without results
+func (r *ColumnRecorder) MakeIter(column string, predicate parquetquery.Predicate, selectAs string) parquetquery.Iterator {
+	r.columns = append(r.columns, recordedColumn{
+		column:   column,
+		selectAs: selectAs,
+		keepsAll: parquetquery.KeepsAll(predicate),
+	})
+	return &recordedIterator{column: column}
+}
+
+// Columns returns the recorded columns that are not recorded by base. base records a fetch without
+// conditions, so the result are the columns that are read because of the conditions. They are sorted by
+// column.
+func (r *ColumnRecorder) Columns(base *ColumnRecorder) []ExplainedColumn {
+	baseCounts := map[recordedColumn]int{}
+	for _, c := range base.columns {
+		baseCounts[c]++
+	}
+
+	columns := []ExplainedColumn{}
+	for _, c := range r.columns {
+		if baseCounts[c] > 0 {
+			baseCounts[c]--
+			continue
+		}
+		columns = append(columns, ExplainedColumn{Column: c.column, PushedDown: !c.keepsAll})
+	}
+
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].Column < columns[j].Column
+	})
+	return columns
+}
+
+type recordedIterator struct {
+	column string
+}
+
+var _ parquetquery.Iterator = (*recordedIterator)(nil)
+
+func (i *recordedIterator) String() string {
+	return "recordedIterator(" + i.column + ")"
+}
+
+func (i *recordedIterator) Next() (*parquetquery.IteratorResult, error) {
+	return nil, nil
+}
+
+func (i *recordedIterator) SeekTo(parquetquery.RowNumber, int) (*parquetquery.IteratorResult, error) {
+	return nil, nil
+}
+
+func (i *recordedIterator) Close() {}
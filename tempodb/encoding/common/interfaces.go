@@ -20,11 +20,16 @@ type TagCallback func(t string)
 
 type TagCallbackV2 func(traceql.Static) (stop bool)
 
+// TagValueCountCallback is called with the number of times a value was seen. The same value can be
+// reported more than once.
+type TagValueCountCallback func(v traceql.Static, count uint64) (stop bool)
+
 type Searcher interface {
 	Search(ctx context.Context, req *tempopb.SearchRequest, opts SearchOptions) (*tempopb.SearchResponse, error)
 	SearchTags(ctx context.Context, scope traceql.AttributeScope, cb TagCallback, opts SearchOptions) error
 	SearchTagValues(ctx context.Context, tag string, cb TagCallback, opts SearchOptions) error
 	SearchTagValuesV2(ctx context.Context, tag traceql.Attribute, cb TagCallbackV2, opts SearchOptions) error
+	SearchTagValueCounts(ctx context.Context, tag traceql.Attribute, cb TagValueCountCallback, opts SearchOptions) error
 
 	Fetch(context.Context, traceql.FetchSpansRequest, SearchOptions) (traceql.FetchSpansResponse, error)
 }
@@ -157,6 +157,10 @@ func (b *BackendBlock) SearchTagValuesV2(context.Context, traceql.Attribute, com
 	return common.ErrUnsupported
 }
 
+func (b *BackendBlock) SearchTagValueCounts(context.Context, traceql.Attribute, common.TagValueCountCallback, common.SearchOptions) error {
+	return common.ErrUnsupported
+}
+
 func (b *BackendBlock) Fetch(context.Context, traceql.FetchSpansRequest, common.SearchOptions) (traceql.FetchSpansResponse, error) {
 	return traceql.FetchSpansResponse{}, common.ErrUnsupported
 }
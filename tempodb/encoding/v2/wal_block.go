@@ -301,6 +301,10 @@ func (a *walBlock) SearchTagValuesV2(context.Context, traceql.Attribute, common.
 	return common.ErrUnsupported
 }
 
+func (a *walBlock) SearchTagValueCounts(context.Context, traceql.Attribute, common.TagValueCountCallback, common.SearchOptions) error {
+	return common.ErrUnsupported
+}
+
 // Fetch implements traceql.SpansetFetcher
 func (a *walBlock) Fetch(context.Context, traceql.FetchSpansRequest, common.SearchOptions) (traceql.FetchSpansResponse, error) {
 	return traceql.FetchSpansResponse{}, common.ErrUnsupported
@@ -8,11 +8,13 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
 	"github.com/grafana/tempo/tempodb/encoding/vparquet"
 	"github.com/grafana/tempo/tempodb/encoding/vparquet2"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
 )
 
 // VersionedEncoding represents a backend block version, and the methods to
@@ -63,11 +65,28 @@ func FromVersion(v string) (VersionedEncoding, error) {
 		return vparquet.Encoding{}, nil
 	case vparquet2.VersionString:
 		return vparquet2.Encoding{}, nil
+	case vparquet3.VersionString:
+		return vparquet3.Encoding{}, nil
 	default:
 		return nil, fmt.Errorf("%s is not a valid block version", v)
 	}
 }
 
+// ConditionColumns returns the columns that are read for the TraceQL condition in blocks of the given
+// version and whether the condition is pushed down to them.
+func ConditionColumns(v string, cond traceql.Condition, allConditions bool) ([]common.ExplainedColumn, error) {
+	switch v {
+	case vparquet.VersionString:
+		return vparquet.ConditionColumns(cond, allConditions)
+	case vparquet2.VersionString:
+		return vparquet2.ConditionColumns(cond, allConditions)
+	case vparquet3.VersionString:
+		return vparquet3.ConditionColumns(cond, allConditions)
+	default:
+		return nil, fmt.Errorf("%s does not support TraceQL", v)
+	}
+}
+
 // DefaultEncoding for newly written blocks.
 func DefaultEncoding() VersionedEncoding {
 	return vparquet.Encoding{}
@@ -79,6 +98,7 @@ func AllEncodings() []VersionedEncoding {
 		v2.Encoding{},
 		vparquet.Encoding{},
 		vparquet2.Encoding{},
+		vparquet3.Encoding{},
 	}
 }
 
@@ -95,6 +95,18 @@ func searchTags(_ context.Context, scope traceql.AttributeScope, cb common.TagCa
 		}
 	}
 
+	// instrumentation scope fields have dedicated columns only
+	if scope == traceql.AttributeScopeInstrumentation {
+		for lbl, col := range instrumentationColumnLookups {
+			idx, _ := pq.GetColumnIndexByPath(pf, col)
+			if idx == -1 {
+				continue
+			}
+
+			specialAttrIdxs[idx] = lbl
+		}
+	}
+
 	// now search all row groups
 	var err error
 	rgs := pf.RowGroups()
@@ -226,6 +238,27 @@ func searchTagValues(ctx context.Context, tag traceql.Attribute, cb common.TagCa
 		return nil
 	}
 
+	// The instrumentation scope has dedicated columns only. Spans without
+	// an instrumentation scope have empty values which are skipped.
+	if tag.Scope == traceql.AttributeScopeInstrumentation {
+		columnPath := instrumentationColumnLookups[tag.Name]
+		if columnPath == "" {
+			return nil
+		}
+
+		skipEmpty := func(v traceql.Static) bool {
+			if v.Type == traceql.TypeString && v.S == "" {
+				return false
+			}
+			return cb(v)
+		}
+		err := searchSpecialTagValues(ctx, columnPath, pf, skipEmpty)
+		if err != nil {
+			return fmt.Errorf("unexpected error searching instrumentation tags: %w", err)
+		}
+		return nil
+	}
+
 	// Special handling for weird non-traceql things
 	if columnPath := nonTraceQLAttributes[tag.Name]; columnPath != "" {
 		err := searchSpecialTagValues(ctx, columnPath, pf, cb)
@@ -253,6 +286,101 @@ func searchTagValues(ctx context.Context, tag traceql.Attribute, cb common.TagCa
 	return nil
 }
 
+func (b *backendBlock) SearchTagValueCounts(ctx context.Context, tag traceql.Attribute, cb common.TagValueCountCallback, opts common.SearchOptions) error {
+	span, derivedCtx := opentracing.StartSpanFromContext(ctx, "parquet.backendBlock.SearchTagValueCounts",
+		opentracing.Tags{
+			"blockID":   b.meta.BlockID,
+			"tenantID":  b.meta.TenantID,
+			"blockSize": b.meta.Size,
+		})
+	defer span.Finish()
+
+	pf, rr, err := b.openForSearch(derivedCtx, opts)
+	if err != nil {
+		return fmt.Errorf("unexpected error opening parquet file: %w", err)
+	}
+	defer func() { span.SetTag("inspectedBytes", rr.BytesRead()) }()
+
+	return searchTagValueCounts(derivedCtx, tag, cb, pf)
+}
+
+// searchTagValueCounts counts how often each value of the tag occurs. Dedicated columns are counted from
+// their dictionary indexes. Generic attributes are counted by reading the values of all matching keys,
+// see below why their dictionaries can't be used.
+func searchTagValueCounts(ctx context.Context, tag traceql.Attribute, cb common.TagValueCountCallback, pf *parquet.File) error {
+	// Only intrinsics holding names are counted. The others are numbers or ids and counting them
+	// doesn't tell anything.
+	if tag.Intrinsic != traceql.IntrinsicNone {
+		switch tag.Intrinsic {
+		case traceql.IntrinsicName, traceql.IntrinsicTraceRootSpan, traceql.IntrinsicTraceRootService:
+			err := countSpecialTagValues(ctx, intrinsicColumnLookups[tag.Intrinsic].columnPath, pf, cb)
+			if err != nil {
+				return fmt.Errorf("unexpected error counting special tags: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if tag.Scope == traceql.AttributeScopeInstrumentation {
+		columnPath := instrumentationColumnLookups[tag.Name]
+		if columnPath == "" {
+			return nil
+		}
+
+		err := countSpecialTagValues(ctx, columnPath, pf, cb)
+		if err != nil {
+			return fmt.Errorf("unexpected error counting instrumentation tags: %w", err)
+		}
+		return nil
+	}
+
+	if columnPath := nonTraceQLAttributes[tag.Name]; columnPath != "" {
+		err := countSpecialTagValues(ctx, columnPath, pf, cb)
+		if err != nil {
+			return fmt.Errorf("unexpected error counting special tags: %s %w", columnPath, err)
+		}
+		return nil
+	}
+
+	column := wellKnownColumnLookups[tag.Name]
+	if column.columnPath != "" && (tag.Scope == column.level || tag.Scope == traceql.AttributeScopeNone) {
+		err := countSpecialTagValues(ctx, column.columnPath, pf, cb)
+		if err != nil {
+			return fmt.Errorf("unexpected error counting special tags: %w", err)
+		}
+	}
+
+	// Generic key/values are a full scan of the matching keys. Their value columns are shared by all
+	// keys, so the dictionary of a value column holds the values of every key and its indexes can't
+	// be counted for this tag alone. Row groups and pages of the key column without the tag are still
+	// skipped by the column index and the dictionary of the key column, but every value of a matching
+	// key is read. The values are summed up and reported once per file like the dedicated columns.
+	type valueCount struct {
+		v     traceql.Static
+		count uint64
+	}
+	counts := map[traceql.StaticMapKey]valueCount{}
+	err := searchStandardTagValues(ctx, tag, pf, func(v traceql.Static) bool {
+		k := v.MapKey()
+		c := counts[k]
+		c.v = v
+		c.count++
+		counts[k] = c
+		return false
+	})
+	if err != nil {
+		return fmt.Errorf("unexpected error counting standard tags: %w", err)
+	}
+
+	for _, c := range counts {
+		if cb(c.v, c.count) {
+			break
+		}
+	}
+
+	return nil
+}
+
 // searchStandardTagValues searches a parquet file for "standard" tags. i.e. tags that don't have unique
 // columns and are contained in labelMappings
 func searchStandardTagValues(ctx context.Context, tag traceql.Attribute, pf *parquet.File, cb common.TagCallbackV2) error {
@@ -344,3 +472,31 @@ func searchSpecialTagValues(ctx context.Context, column string, pf *parquet.File
 
 	return nil
 }
+
+// countSpecialTagValues counts the values of the provided column. Dictionary encoded pages are counted
+// without reading the values.
+func countSpecialTagValues(ctx context.Context, column string, pf *parquet.File, cb common.TagValueCountCallback) error {
+	// Dedicated string columns store an empty string when the value is missing while generic
+	// attributes have no entry at all. Empty strings are skipped so both count the same things.
+	pred := newCountValuesPredicate(func(v traceql.Static, count uint64) bool {
+		if v.Type == traceql.TypeString && v.S == "" {
+			return false
+		}
+		return cb(v, count)
+	})
+	rgs := pf.RowGroups()
+
+	iter := makeIterFunc(ctx, rgs, pf)(column, pred, "")
+	defer iter.Close()
+	for {
+		match, err := iter.Next()
+		if err != nil {
+			return errors.Wrap(err, "iter.Next failed")
+		}
+		if match == nil {
+			break
+		}
+	}
+
+	return nil
+}
@@ -23,6 +23,14 @@ func TestBackendBlockSearchFetchMetaData(t *testing.T) {
 	}
 
 	makeSpanset := func(traceID []byte, rootSpanName, rootServiceName string, startTimeUnixNano, durationNanos uint64, spans ...traceql.Span) *traceql.Spanset {
+		// trace-level intrinsics requested in the second pass are copied onto every span
+		for _, s := range spans {
+			atts := s.(*span).attributes
+			atts[traceql.NewIntrinsic(traceql.IntrinsicTraceRootSpan)] = traceql.NewStaticString(rootSpanName)
+			atts[traceql.NewIntrinsic(traceql.IntrinsicTraceRootService)] = traceql.NewStaticString(rootServiceName)
+			atts[traceql.NewIntrinsic(traceql.IntrinsicTraceDuration)] = traceql.NewStaticDuration(time.Duration(durationNanos))
+		}
+
 		return &traceql.Spanset{
 			TraceID:            traceID,
 			RootSpanName:       rootSpanName,
@@ -90,6 +90,23 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = error}`),
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = 2}`),
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = client }`),
+		// Trace-level intrinsics
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration = 100ms}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration > 99ms}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootName = "RootSpan"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName = "RootService"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName =~ "Root.*"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration < 1s && ` + LabelName + ` = "hello"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName = "notRootService" || ` + LabelName + ` = "hello"}`),
+		// Events and links
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.name = "e2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.name =~ "e.*" && ` + LabelName + ` = "hello"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.traceID = "1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.spanID = "0000000000000002" && link.key = "value"}`),
+		// Instrumentation scope
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-1" && instrumentation.version < "version-2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.version = "version-1" && ` + LabelName + ` = "hello"}`),
 		// Resource well-known attributes
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "spanservicename"}`), // Overridden at span
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelCluster + ` = "cluster"}`),
@@ -227,7 +244,11 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 	searchesThatDontMatch := []traceql.FetchSpansRequest{
 		// TODO - Should the below query return data or not?  It does match the resource
 		// makeReq(parse(t, `{.foo = "abc"}`)), // This should not return results because the span has overridden this attribute to "def".
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "xyz.*"}`),  // Regex IN
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "xyz.*"}`), // Regex IN
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.name = "e3"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.key = "other"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-1" && instrumentation.version > "version-1"}`),
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo !~ ".*"}`),     // Regex IN
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.bool = true}`), // Bool not match
 		// this will actually return from the fetch layer (and be rejected by the engine) due to the way we pull start/end time
@@ -240,6 +261,17 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = 200}`),                  // Well-known attribute: http.status_code not match
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` > 600}`),                  // Well-known attribute: http.status_code not match
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "xyz" || .` + LabelHTTPStatusCode + " = 1000}"), // Matches neither condition
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration > 100ms}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootName = "NotRootSpan"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName = "NotRootService"}`),
+		{
+			// Trace-level intrinsic matches but span-level doesn't
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{rootServiceName = "RootService"}`), // match
+				parse(t, `{`+LabelName+` = "nothello"}`),      // no match
+			},
+		},
 		{
 			// Time range after trace
 			StartTimeUnixNanos: uint64(3000 * time.Second),
@@ -401,6 +433,7 @@ func fullyPopulatedTestTrace(id common.ID) *Trace {
 				},
 				ScopeSpans: []ScopeSpan{
 					{
+						Scope: Scope{Name: "scope-1", Version: "version-1"},
 						Spans: []Span{
 							{
 								ID:             []byte("spanid"),
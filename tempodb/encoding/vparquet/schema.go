@@ -35,15 +35,19 @@ const (
 	LabelStatusCode     = "status.code"
 	LabelStatus         = "status"
 	LabelKind           = "kind"
+	LabelVersion        = "version"
 )
 
 // These definition levels match the schema below
 const (
-	DefinitionLevelTrace                     = 0
-	DefinitionLevelResourceSpans             = 1
-	DefinitionLevelResourceAttrs             = 2
-	DefinitionLevelResourceSpansILSSpan      = 3
-	DefinitionLevelResourceSpansILSSpanAttrs = 4
+	DefinitionLevelTrace                          = 0
+	DefinitionLevelResourceSpans                  = 1
+	DefinitionLevelResourceAttrs                  = 2
+	DefinitionLevelResourceSpansILS               = 2
+	DefinitionLevelResourceSpansILSSpan           = 3
+	DefinitionLevelResourceSpansILSSpanAttrs      = 4
+	DefinitionLevelResourceSpansILSSpanEvent      = 4
+	DefinitionLevelResourceSpansILSSpanEventAttrs = 5
 
 	FieldResourceAttrKey       = "rs.Resource.Attrs.Key"
 	FieldResourceAttrVal       = "rs.Resource.Attrs.Value"
@@ -458,6 +458,79 @@ func (r *reportValuesPredicate) KeepValue(v parquet.Value) bool {
 	return false
 }
 
+// countValuesPredicate is a "fake" predicate like reportValuesPredicate that counts all values in a
+// given column
+type countValuesPredicate struct {
+	cb      common.TagValueCountCallback
+	counts  []uint64
+	stopped bool
+}
+
+func newCountValuesPredicate(cb common.TagValueCountCallback) *countValuesPredicate {
+	return &countValuesPredicate{cb: cb}
+}
+
+func (c *countValuesPredicate) String() string {
+	return "countValuesPredicate{}"
+}
+
+func (c *countValuesPredicate) KeepColumnChunk(parquet.ColumnChunk) bool {
+	return !c.stopped
+}
+
+// KeepPage counts the dictionary indexes of dictionary encoded pages and reports the count of each
+// value once per page. It returns false b/c the values don't have to be read. Other pages are
+// counted value by value in KeepValue.
+func (c *countValuesPredicate) KeepPage(pg parquet.Page) bool {
+	if c.stopped {
+		return false
+	}
+
+	dict := pg.Dictionary()
+	if dict == nil {
+		return true
+	}
+
+	if cap(c.counts) < dict.Len() {
+		c.counts = make([]uint64, dict.Len())
+	}
+	counts := c.counts[:dict.Len()]
+
+	indexes := pg.Data()
+	for _, i := range indexes.Int32() {
+		counts[i]++
+	}
+
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		counts[i] = 0
+
+		if !c.stopped {
+			c.stopped = countCallback(c.cb, dict.Index(int32(i)), n)
+		}
+	}
+
+	return false
+}
+
+// KeepValue is only called for pages without a dictionary. It counts the value and returns false so
+// the iterator doesn't do any extra work.
+func (c *countValuesPredicate) KeepValue(v parquet.Value) bool {
+	if !c.stopped {
+		c.stopped = countCallback(c.cb, v, 1)
+	}
+
+	return false
+}
+
+func countCallback(cb common.TagValueCountCallback, v parquet.Value, count uint64) (stop bool) {
+	return callback(func(s traceql.Static) bool {
+		return cb(s, count)
+	}, v)
+}
+
 func callback(cb common.TagCallbackV2, v parquet.Value) (stop bool) {
 	switch v.Kind() {
 
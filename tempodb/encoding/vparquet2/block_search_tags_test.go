@@ -45,6 +45,7 @@ func TestBackendBlockSearchTags(t *testing.T) {
 	testVals(traceql.AttributeScopeResource, resourceAttrVals)
 	testVals(traceql.AttributeScopeNone, spanAttrVals)
 	testVals(traceql.AttributeScopeSpan, spanAttrVals)
+	testVals(traceql.AttributeScopeInstrumentation, map[string]string{LabelName: "", LabelVersion: ""})
 }
 
 func TestBackendBlockSearchTagValues(t *testing.T) {
@@ -128,6 +129,14 @@ func TestBackendBlockSearchTagValuesV2(t *testing.T) {
 			traceql.NewStaticString("abc"),
 			traceql.NewStaticString("def"),
 		}},
+
+		// Instrumentation scope, empty values are skipped
+		{traceql.MustParseIdentifier("instrumentation.name"), []traceql.Static{
+			traceql.NewStaticString("scope-1"),
+		}},
+		{traceql.MustParseIdentifier("instrumentation.version"), []traceql.Static{
+			traceql.NewStaticString("version-1"),
+		}},
 	}
 
 	ctx := context.Background()
@@ -145,6 +154,85 @@ func TestBackendBlockSearchTagValuesV2(t *testing.T) {
 	}
 }
 
+func TestBackendBlockSearchTagValueCounts(t *testing.T) {
+	// every value occurs in both populated traces. The dedicated columns of the last trace hold
+	// empty strings which aren't counted
+	block := makeBackendBlockWithTraces(t, []*Trace{
+		fullyPopulatedTestTrace(common.ID{0}),
+		fullyPopulatedTestTrace(common.ID{1}),
+		{
+			TraceID: common.ID{2},
+			ResourceSpans: []ResourceSpans{{
+				ScopeSpans: []ScopeSpans{{
+					Spans: []Span{{SpanID: []byte("spanid")}},
+				}},
+			}},
+		},
+	})
+
+	testCases := []struct {
+		tag    traceql.Attribute
+		counts map[traceql.Static]uint64
+	}{
+		// Intrinsic
+		{traceql.MustParseIdentifier("name"), map[traceql.Static]uint64{
+			traceql.NewStaticString("hello"): 2,
+			traceql.NewStaticString("world"): 2,
+		}},
+
+		// Only intrinsics holding names are counted
+		{traceql.MustParseIdentifier("duration"), map[traceql.Static]uint64{}},
+
+		// Trace-level special
+		{traceql.NewAttribute("root.name"), map[traceql.Static]uint64{
+			traceql.NewStaticString("RootSpan"): 2,
+		}},
+
+		// Resource only, mixed well-known column and generic key/value
+		{traceql.MustParseIdentifier("resource.service.name"), map[traceql.Static]uint64{
+			traceql.NewStaticString("myservice"): 2,
+			traceql.NewStaticString("service2"):  2,
+			traceql.NewStaticInt(123):            2,
+		}},
+
+		// Attr present at both resource and span level
+		{traceql.MustParseIdentifier(".foo"), map[traceql.Static]uint64{
+			traceql.NewStaticString("abc"): 2,
+			traceql.NewStaticString("def"): 2,
+		}},
+
+		// Instrumentation scope, empty values are skipped
+		{traceql.MustParseIdentifier("instrumentation.name"), map[traceql.Static]uint64{
+			traceql.NewStaticString("scope-1"): 2,
+		}},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		got := map[traceql.Static]uint64{}
+		cb := func(v traceql.Static, count uint64) bool {
+			got[v] += count
+			return false
+		}
+
+		err := block.SearchTagValueCounts(ctx, tc.tag, cb, common.DefaultSearchOptions())
+		require.NoError(t, err, tc.tag)
+		require.Equal(t, tc.counts, got, "tag=%v", tc.tag)
+	}
+
+	// Generic key/values are summed up and every value is reported once
+	reported := map[traceql.Static]int{}
+	err := block.SearchTagValueCounts(ctx, traceql.MustParseIdentifier(".foo"), func(v traceql.Static, _ uint64) bool {
+		reported[v]++
+		return false
+	}, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Equal(t, map[traceql.Static]int{
+		traceql.NewStaticString("abc"): 1,
+		traceql.NewStaticString("def"): 1,
+	}, reported)
+}
+
 func BenchmarkBackendBlockSearchTags(b *testing.B) {
 	ctx := context.TODO()
 	tenantID := "1"
@@ -1,6 +1,7 @@
 package vparquet2
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -10,11 +11,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/golang/protobuf/jsonpb" //nolint:all //deprecated
 	"github.com/pkg/errors"
 	"github.com/segmentio/parquet-go"
 
 	"github.com/grafana/tempo/pkg/parquetquery"
 	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util"
@@ -25,9 +29,16 @@ import (
 type span struct {
 	attributes         map[traceql.Attribute]traceql.Static
 	id                 []byte
+	parentID           []byte
 	startTimeUnixNanos uint64
 	durationNanos      uint64
 	rowNum             parquetquery.RowNumber
+
+	// nested set values computed by the trace collector when structural
+	// operators are requested. zero means unknown
+	nestedSetLeft   int32
+	nestedSetRight  int32
+	nestedSetParent int32
 }
 
 func (s *span) Attributes() map[traceql.Attribute]traceql.Static {
@@ -43,6 +54,22 @@ func (s *span) DurationNanos() uint64 {
 	return s.durationNanos
 }
 
+func (s *span) DescendantOf(other traceql.Span) bool {
+	o := other.(*span)
+	return s.nestedSetLeft != 0 && o.nestedSetLeft != 0 &&
+		s.nestedSetLeft > o.nestedSetLeft && s.nestedSetRight < o.nestedSetRight
+}
+
+func (s *span) ChildOf(other traceql.Span) bool {
+	o := other.(*span)
+	return s.nestedSetParent != 0 && s.nestedSetParent == o.nestedSetLeft
+}
+
+func (s *span) SiblingOf(other traceql.Span) bool {
+	o := other.(*span)
+	return s != o && s.nestedSetParent != 0 && s.nestedSetParent == o.nestedSetParent
+}
+
 // attributesMatched counts all attributes in the map as well as metadata fields like start/end/id
 func (s *span) attributesMatched() int {
 	count := 0
@@ -82,9 +109,13 @@ var spanPool = sync.Pool{
 
 func putSpan(s *span) {
 	s.id = nil
+	s.parentID = nil
 	s.startTimeUnixNanos = 0
 	s.durationNanos = 0
 	s.rowNum = parquetquery.EmptyRowNumber()
+	s.nestedSetLeft = 0
+	s.nestedSetRight = 0
+	s.nestedSetParent = 0
 
 	// clear attributes
 	for k := range s.attributes {
@@ -125,6 +156,7 @@ const (
 	columnPathResourceK8sContainerName = "rs.list.element.Resource.K8sContainerName"
 
 	columnPathSpanID             = "rs.list.element.ss.list.element.Spans.list.element.SpanID"
+	columnPathSpanParentSpanID   = "rs.list.element.ss.list.element.Spans.list.element.ParentSpanID"
 	columnPathSpanName           = "rs.list.element.ss.list.element.Spans.list.element.Name"
 	columnPathSpanStartTime      = "rs.list.element.ss.list.element.Spans.list.element.StartTimeUnixNano"
 	columnPathSpanDuration       = "rs.list.element.ss.list.element.Spans.list.element.DurationNano"
@@ -138,15 +170,32 @@ const (
 	columnPathSpanHTTPStatusCode = "rs.list.element.ss.list.element.Spans.list.element.HttpStatusCode"
 	columnPathSpanHTTPMethod     = "rs.list.element.ss.list.element.Spans.list.element.HttpMethod"
 	columnPathSpanHTTPURL        = "rs.list.element.ss.list.element.Spans.list.element.HttpUrl"
+	columnPathSpanEventName      = "rs.list.element.ss.list.element.Spans.list.element.Events.list.element.Name"
+	columnPathSpanEventAttrKey   = "rs.list.element.ss.list.element.Spans.list.element.Events.list.element.Attrs.list.element.Key"
+	columnPathSpanEventAttrValue = "rs.list.element.ss.list.element.Spans.list.element.Events.list.element.Attrs.list.element.Value"
+	columnPathSpanLinks          = "rs.list.element.ss.list.element.Spans.list.element.Links"
+	columnPathScopeName          = "rs.list.element.ss.list.element.Scope.Name"
+	columnPathScopeVersion       = "rs.list.element.ss.list.element.Scope.Version"
 
 	otherEntrySpansetKey = "spanset"
 	otherEntrySpanKey    = "span"
+	otherEntryEventKey   = "event"
+
+	// link fields that are queryable next to the link attributes, i.e. link.traceID
+	linkTraceID = "traceID"
+	linkSpanID  = "spanID"
 
 	// a fake intrinsic scope at the trace lvl
 	intrinsicScopeTrace = -1
 	intrinsicScopeSpan  = -2
 )
 
+// instrumentationColumnLookups maps the fields of the instrumentation scope to their columns
+var instrumentationColumnLookups = map[string]string{
+	LabelName:    columnPathScopeName,
+	LabelVersion: columnPathScopeVersion,
+}
+
 // todo: scope is the only field used here. either remove the other fields or use them.
 var intrinsicColumnLookups = map[traceql.Intrinsic]struct {
 	scope      traceql.AttributeScope
@@ -162,7 +211,7 @@ var intrinsicColumnLookups = map[traceql.Intrinsic]struct {
 
 	traceql.IntrinsicTraceRootService: {intrinsicScopeTrace, traceql.TypeString, columnPathRootServiceName},
 	traceql.IntrinsicTraceRootSpan:    {intrinsicScopeTrace, traceql.TypeString, columnPathRootSpanName},
-	traceql.IntrinsicTraceDuration:    {intrinsicScopeTrace, traceql.TypeString, columnPathDurationNanos},
+	traceql.IntrinsicTraceDuration:    {intrinsicScopeTrace, traceql.TypeDuration, columnPathDurationNanos},
 	traceql.IntrinsicTraceID:          {intrinsicScopeTrace, traceql.TypeDuration, columnPathTraceID},
 	traceql.IntrinsicTraceStartTime:   {intrinsicScopeTrace, traceql.TypeDuration, columnPathStartTimeUnixNano},
 }
@@ -190,6 +239,26 @@ var wellKnownColumnLookups = map[string]struct {
 	LabelHTTPUrl:        {columnPathSpanHTTPURL, traceql.AttributeScopeSpan, traceql.TypeString},
 }
 
+// ConditionColumns explains how the condition is fetched. It builds the iterators of a fetch with only
+// this condition and returns the columns that are read for it.
+func ConditionColumns(cond traceql.Condition, allConditions bool) ([]common.ExplainedColumn, error) {
+	if err := checkConditions([]traceql.Condition{cond}); err != nil {
+		return nil, errors.Wrap(err, "conditions invalid")
+	}
+
+	base := &common.ColumnRecorder{}
+	if _, err := createAllIterator(base.MakeIter, nil, nil, allConditions, 0, 0, 0); err != nil {
+		return nil, err
+	}
+
+	rec := &common.ColumnRecorder{}
+	if _, err := createAllIterator(rec.MakeIter, nil, []traceql.Condition{cond}, allConditions, 0, 0, 0); err != nil {
+		return nil, err
+	}
+
+	return rec.Columns(base), nil
+}
+
 // Fetch spansets from the block for the given TraceQL FetchSpansRequest. The request is checked for
 // internal consistencies:  operand count matches the operation, all operands in each condition are identical
 // types, and the operand type is compatible with the operation.
@@ -230,7 +299,9 @@ func checkConditions(conditions []traceql.Condition) error {
 		case traceql.OpEqual, traceql.OpNotEqual,
 			traceql.OpGreater, traceql.OpGreaterEqual,
 			traceql.OpLess, traceql.OpLessEqual,
-			traceql.OpRegex, traceql.OpNotRegex:
+			traceql.OpRegex, traceql.OpNotRegex,
+			traceql.OpContains,
+			traceql.OpEqualFold, traceql.OpStartsWith, traceql.OpEndsWith, traceql.OpSubstring:
 			if opCount != 1 {
 				return fmt.Errorf("operation %v must have exactly 1 argument. condition: %+v", cond.Op, cond)
 			}
@@ -511,7 +582,9 @@ func (i *mergeSpansetIterator) Close() {
 //                                                            V
 
 func fetch(ctx context.Context, req traceql.FetchSpansRequest, pf *parquet.File, opts common.SearchOptions) (*spansetIterator, error) {
-	iter, err := createAllIterator(ctx, nil, req.Conditions, req.AllConditions, req.StartTimeUnixNanos, req.EndTimeUnixNanos, pf, opts)
+	makeIter := makeIterFunc(ctx, rowGroupsFromFile(pf, opts), pf)
+
+	iter, err := createAllIterator(makeIter, nil, req.Conditions, req.AllConditions, req.StartTimeUnixNanos, req.EndTimeUnixNanos, req.Sample)
 	if err != nil {
 		return nil, fmt.Errorf("error creating iterator: %w", err)
 	}
@@ -519,7 +592,7 @@ func fetch(ctx context.Context, req traceql.FetchSpansRequest, pf *parquet.File,
 	if req.SecondPass != nil {
 		iter = newBridgeIterator(iter, req.SecondPass)
 
-		iter, err = createAllIterator(ctx, iter, req.SecondPassConditions, false, 0, 0, pf, opts)
+		iter, err = createAllIterator(makeIter, iter, req.SecondPassConditions, false, 0, 0, 0)
 		if err != nil {
 			return nil, fmt.Errorf("error creating second pass iterator: %w", err)
 		}
@@ -528,15 +601,51 @@ func fetch(ctx context.Context, req traceql.FetchSpansRequest, pf *parquet.File,
 	return newSpansetIterator(iter), nil
 }
 
-func createAllIterator(ctx context.Context, primaryIter parquetquery.Iterator, conds []traceql.Condition, allConditions bool, start uint64, end uint64, pf *parquet.File, opts common.SearchOptions) (parquetquery.Iterator, error) {
+func createAllIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator, conds []traceql.Condition, allConditions bool, start uint64, end uint64, sample float64) (parquetquery.Iterator, error) {
 	// Categorize conditions into span-level or resource-level
 	var (
 		mingledConditions  bool
 		spanConditions     []traceql.Condition
+		scopeConditions    []traceql.Condition
 		resourceConditions []traceql.Condition
 		traceConditions    []traceql.Condition
+		parentConditions   []traceql.Condition
+		structural         bool
+
+		parentSpanConditions     []traceql.Condition
+		parentResourceConditions []traceql.Condition
+		missing                  bool
+		missingConditions        []traceql.Condition
 	)
 	for _, cond := range conds {
+		switch cond.Attribute.Intrinsic {
+		case traceql.IntrinsicStructuralDescendant, traceql.IntrinsicStructuralChild, traceql.IntrinsicStructuralSibling:
+			structural = true
+			continue
+		}
+
+		// Parent conditions are evaluated on the parent span, which usually doesn't match the
+		// query itself. They are fetched for every span by the relations iterator and copied to
+		// the children in the trace collector.
+		parent := cond.Attribute.Parent
+		cond.Attribute.Parent = false
+
+		// Existence checks only need the attribute column. Spans with the attribute satisfy
+		// { .foo != nil } and are filtered as usual, { .foo = nil } is looking for spans
+		// without it and is resolved below.
+		if cond.ExistenceCheck() {
+			if cond.Op == traceql.OpEqual && !parent {
+				missingConditions = append(missingConditions, cond)
+			}
+			cond = traceql.Condition{Attribute: cond.Attribute, Op: traceql.OpNone}
+		}
+
+		// Arrays are stored as encoded strings in this block format so there are no
+		// elements to filter on. Fetch the attribute and leave it to the engine.
+		if cond.Op == traceql.OpContains {
+			cond = traceql.Condition{Attribute: cond.Attribute, Op: traceql.OpNone}
+		}
+
 		// If no-scoped intrinsic then assign default scope
 		scope := cond.Attribute.Scope
 		if cond.Attribute.Scope == traceql.AttributeScopeNone {
@@ -545,6 +654,22 @@ func createAllIterator(ctx context.Context, primaryIter parquetquery.Iterator, c
 			}
 		}
 
+		if parent {
+			parentConditions = append(parentConditions, cond)
+			switch scope {
+			case traceql.AttributeScopeNone:
+				parentSpanConditions = append(parentSpanConditions, cond)
+				parentResourceConditions = append(parentResourceConditions, cond)
+			case traceql.AttributeScopeSpan, intrinsicScopeSpan:
+				parentSpanConditions = append(parentSpanConditions, cond)
+			case traceql.AttributeScopeResource:
+				parentResourceConditions = append(parentResourceConditions, cond)
+			default:
+				return nil, fmt.Errorf("unsupported traceql parent scope: %s", cond.Attribute)
+			}
+			continue
+		}
+
 		switch scope {
 
 		case traceql.AttributeScopeNone:
@@ -553,10 +678,14 @@ func createAllIterator(ctx context.Context, primaryIter parquetquery.Iterator, c
 			resourceConditions = append(resourceConditions, cond)
 			continue
 
-		case traceql.AttributeScopeSpan, intrinsicScopeSpan:
+		case traceql.AttributeScopeSpan, intrinsicScopeSpan, traceql.AttributeScopeEvent, traceql.AttributeScopeLink:
 			spanConditions = append(spanConditions, cond)
 			continue
 
+		case traceql.AttributeScopeInstrumentation:
+			scopeConditions = append(scopeConditions, cond)
+			continue
+
 		case traceql.AttributeScopeResource:
 			resourceConditions = append(resourceConditions, cond)
 			continue
@@ -570,8 +699,38 @@ func createAllIterator(ctx context.Context, primaryIter parquetquery.Iterator, c
 		}
 	}
 
-	rgs := rowGroupsFromFile(pf, opts)
-	makeIter := makeIterFunc(ctx, rgs, pf)
+	// { span.foo = nil } and { resource.foo = nil } are pushed down to the span and batch collectors
+	// which count the attribute as matched when it isn't found. Other checks AND-ed with the rest of
+	// the query only fetch the attribute for the engine, the other conditions still filter the spans.
+	// Only OR-ed checks require all spans.
+	var (
+		missingAttrs   []traceql.Attribute
+		projectedAttrs []traceql.Attribute
+		seenMissing    = map[traceql.Attribute]struct{}{}
+	)
+	for _, cond := range missingConditions {
+		if _, ok := seenMissing[cond.Attribute]; ok {
+			continue
+		}
+		seenMissing[cond.Attribute] = struct{}{}
+
+		if pushdownMissing(cond, conds) {
+			missingAttrs = append(missingAttrs, cond.Attribute)
+			continue
+		}
+		if allConditions {
+			projectedAttrs = append(projectedAttrs, cond.Attribute)
+			continue
+		}
+		missing = true
+	}
+
+	// Projected attributes don't filter so they aren't counted
+	var (
+		spanFilters     = countFilters(spanConditions, projectedAttrs)
+		scopeFilters    = countFilters(scopeConditions, projectedAttrs)
+		resourceFilters = countFilters(resourceConditions, projectedAttrs)
+	)
 
 	// Global state
 	// Span-filtering behavior changes depending on the resource-filtering in effect,
@@ -583,46 +742,128 @@ func createAllIterator(ctx context.Context, primaryIter parquetquery.Iterator, c
 	// TODO - After introducing AllConditions it seems like some of this logic overlaps.
 	//        Determine if it can be generalized or simplified.
 	var (
+		// Parent attributes and span relationships are resolved in the trace collector from
+		// the relations iterator. Spans can still be filtered on their own conditions unless
+		// a parent condition is OR-ed with them, then any span could match through its parent.
+		// Structural operators only relate the spans matching either side so they filter as usual.
+		relations = len(parentConditions) > 0 || structural
+		allSpans  = (len(parentConditions) > 0 && !allConditions) || missing
+
+		// The number of conditions evaluated on the spans and batches themselves.
+		filterConditions = spanFilters + scopeFilters + resourceFilters
+
 		// If there are only span conditions, then don't return a span upstream
 		// unless it matches at least 1 span-level condition.
-		spanRequireAtLeastOneMatch = len(spanConditions) > 0 && len(resourceConditions) == 0
+		spanRequireAtLeastOneMatch = spanFilters > 0 && scopeFilters == 0 && resourceFilters == 0 && len(traceConditions) == 0 && !allSpans
+
+		// If there are only instrumentation scope conditions, then don't return a scope upstream
+		// unless it matches at least 1 scope-level condition.
+		scopeRequireAtLeastOneMatch = spanFilters == 0 && scopeFilters > 0 && resourceFilters == 0 && len(traceConditions) == 0 && !allSpans
 
 		// If there are only resource conditions, then don't return a resource upstream
 		// unless it matches at least 1 resource-level condition.
-		batchRequireAtLeastOneMatch = len(spanConditions) == 0 && len(resourceConditions) > 0
+		batchRequireAtLeastOneMatch = spanFilters == 0 && scopeFilters == 0 && resourceFilters > 0 && len(traceConditions) == 0 && !allSpans
 
 		// Don't return the final spanset upstream unless it matched at least 1 condition
-		// anywhere, except in the case of the empty query: {}
-		batchRequireAtLeastOneMatchOverall = len(conds) > 0
+		// anywhere, except in the case of the empty query: {}. Trace-level conditions are
+		// only known once the trace collector runs so they also disable this check.
+		batchRequireAtLeastOneMatchOverall = filterConditions > 0 && len(traceConditions) == 0 && !allSpans
 	)
 
 	// Optimization for queries like {resource.x... && span.y ...}
 	// Requires no mingled scopes like .foo=x, which could be satisfied
 	// one either resource or span.
-	allConditions = allConditions && !mingledConditions
+	// Attributes that are checked for being missing can't be required either.
+	allConditions = allConditions && !mingledConditions && !allSpans && len(missingAttrs) == 0 && len(projectedAttrs) == 0
 
-	spanIter, err := createSpanIterator(makeIter, primaryIter, spanConditions, spanRequireAtLeastOneMatch, allConditions)
+	spanIter, err := createSpanIterator(makeIter, primaryIter, spanConditions, missingAttrs, projectedAttrs, spanRequireAtLeastOneMatch, allConditions, false)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating span iterator")
 	}
 
-	resourceIter, err := createResourceIterator(makeIter, spanIter, resourceConditions, batchRequireAtLeastOneMatch, batchRequireAtLeastOneMatchOverall, allConditions)
+	// the instrumentation scope sits between the spans and the resource. the extra
+	// level is only read when it's queried
+	if len(scopeConditions) > 0 {
+		spanIter, err = createScopeIterator(makeIter, spanIter, scopeConditions, scopeRequireAtLeastOneMatch, allConditions)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating scope iterator")
+		}
+	}
+
+	resourceIter, err := createResourceIterator(makeIter, spanIter, resourceConditions, missingAttrs, projectedAttrs, batchRequireAtLeastOneMatch, batchRequireAtLeastOneMatchOverall, allConditions)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating resource iterator")
 	}
 
-	return createTraceIterator(makeIter, resourceIter, traceConditions, start, end), nil
+	var relationsIter parquetquery.Iterator
+	if relations {
+		relationsIter, err = createRelationsIterator(makeIter, parentSpanConditions, parentResourceConditions)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating relations iterator")
+		}
+	}
+
+	return createTraceIterator(makeIter, resourceIter, relationsIter, traceConditions, parentConditions, structural, start, end, sample, allConditions)
+}
+
+// pushdownMissing returns true if { .foo = nil } can be resolved by the span or batch collector. Unscoped
+// attributes could be found on either level, and other conditions on the attribute would be missed
+// when it's counted as not found.
+func pushdownMissing(cond traceql.Condition, conds []traceql.Condition) bool {
+	if cond.Attribute.Intrinsic != traceql.IntrinsicNone {
+		return false
+	}
+	if cond.Attribute.Scope != traceql.AttributeScopeSpan && cond.Attribute.Scope != traceql.AttributeScopeResource {
+		return false
+	}
+
+	for _, c := range conds {
+		if c.Attribute.Name != cond.Attribute.Name || c.Attribute.Intrinsic != traceql.IntrinsicNone {
+			continue
+		}
+		if c.Attribute.Scope != cond.Attribute.Scope && c.Attribute.Scope != traceql.AttributeScopeNone {
+			continue
+		}
+		if c.Attribute != cond.Attribute || c.Op != traceql.OpEqual || !c.ExistenceCheck() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// countFilters returns the number of conditions that filter, i.e. that aren't only fetching one of the
+// projected attributes
+func countFilters(conds []traceql.Condition, projected []traceql.Attribute) int {
+	count := 0
+conds:
+	for _, cond := range conds {
+		if cond.Op == traceql.OpNone {
+			for _, a := range projected {
+				if cond.Attribute == a {
+					continue conds
+				}
+			}
+		}
+		count++
+	}
+	return count
 }
 
 // createSpanIterator iterates through all span-level columns, groups them into rows representing
-// one span each.  Spans are returned that match any of the given conditions.
-func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator, conditions []traceql.Condition, requireAtLeastOneMatch, allConditions bool) (parquetquery.Iterator, error) {
+// one span each.  Spans are returned that match any of the given conditions. If fetchParentIDs is set the
+// parent span IDs are always fetched so the trace collector can link spans to their parents.
+// Spans without one of the missing span attributes match as well, spans with one of the projected
+// attributes don't match because of it.
+func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator, conditions []traceql.Condition, missing, projected []traceql.Attribute, requireAtLeastOneMatch, allConditions, fetchParentIDs bool) (parquetquery.Iterator, error) {
 
 	var (
 		columnSelectAs    = map[string]string{}
 		columnPredicates  = map[string][]parquetquery.Predicate{}
 		iters             []parquetquery.Iterator
 		genericConditions []traceql.Condition
+		eventConditions   []traceql.Condition
+		linkConditions    []traceql.Condition
 	)
 
 	addPredicate := func(columnPath string, p parquetquery.Predicate) {
@@ -630,6 +871,16 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 	}
 
 	for _, cond := range conditions {
+		// Event or link?
+		switch cond.Attribute.Scope {
+		case traceql.AttributeScopeEvent:
+			eventConditions = append(eventConditions, cond)
+			continue
+		case traceql.AttributeScopeLink:
+			linkConditions = append(linkConditions, cond)
+			continue
+		}
+
 		// Intrinsic?
 		switch cond.Attribute.Intrinsic {
 		case traceql.IntrinsicSpanID:
@@ -711,6 +962,11 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 		genericConditions = append(genericConditions, cond)
 	}
 
+	if fetchParentIDs {
+		addPredicate(columnPathSpanParentSpanID, nil)
+		columnSelectAs[columnPathSpanParentSpanID] = columnPathSpanParentSpanID
+	}
+
 	attrIter, err := createAttributeIterator(makeIter, genericConditions, DefinitionLevelResourceSpansILSSpanAttrs,
 		columnPathSpanAttrKey, columnPathSpanAttrString, columnPathSpanAttrInt, columnPathSpanAttrDouble, columnPathSpanAttrBool, allConditions)
 	if err != nil {
@@ -720,10 +976,34 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 		iters = append(iters, attrIter)
 	}
 
+	if len(eventConditions) > 0 {
+		eventIter, err := createEventIterator(makeIter, eventConditions, allConditions)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating span event iterator")
+		}
+		iters = append(iters, eventIter)
+	}
+
+	// links are stored as a single proto encoded column per span. they are decoded
+	// and matched in the span collector
+	if len(linkConditions) > 0 {
+		addPredicate(columnPathSpanLinks, nil)
+		columnSelectAs[columnPathSpanLinks] = columnPathSpanLinks
+	}
+
 	for columnPath, predicates := range columnPredicates {
 		iters = append(iters, makeIter(columnPath, parquetquery.NewOrPredicate(predicates...), columnSelectAs[columnPath]))
 	}
 
+	eventConds, err := newNestedConditions(eventConditions)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating event conditions")
+	}
+	linkConds, err := newNestedConditions(linkConditions)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating link conditions")
+	}
+
 	var required []parquetquery.Iterator
 	if primaryIter != nil {
 		required = []parquetquery.Iterator{primaryIter}
@@ -742,7 +1022,15 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 		minCount = len(distinct)
 	}
 	spanCol := &spanCollector{
-		minAttributes: minCount,
+		minAttributes:   minCount,
+		projected:       projected,
+		eventConditions: eventConds,
+		linkConditions:  linkConds,
+	}
+	for _, a := range missing {
+		if a.Scope == traceql.AttributeScopeSpan {
+			spanCol.missing = append(spanCol.missing, a)
+		}
 	}
 
 	// This is an optimization for when all of the span conditions must be met.
@@ -756,8 +1044,9 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 	// only span conditions are present, and we require at least one of them to match.
 	// Wrap up the individual conditions with a union and move it into the required list.
 	// This skips over static columns like ID that are omnipresent. This is also only
-	// possible when there isn't a duration filter because it's computed from start/end.
-	if requireAtLeastOneMatch && len(iters) > 0 {
+	// possible when there isn't a duration filter because it's computed from start/end,
+	// or a missing attribute because those spans have no entries at all.
+	if requireAtLeastOneMatch && len(iters) > 0 && len(spanCol.missing) == 0 {
 		required = append(required, parquetquery.NewUnionIterator(DefinitionLevelResourceSpansILSSpan, iters, nil))
 		iters = nil
 	}
@@ -779,58 +1068,90 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 	return parquetquery.NewLeftJoinIterator(DefinitionLevelResourceSpansILSSpan, required, iters, spanCol), nil
 }
 
-// createResourceIterator iterates through all resourcespans-level (batch-level) columns, groups them into rows representing
-// one batch each. It builds on top of the span iterator, and turns the groups of spans and resource-level values into
-// spansets.  Spansets are returned that match any of the given conditions.
-func createResourceIterator(makeIter makeIterFn, spanIterator parquetquery.Iterator, conditions []traceql.Condition, requireAtLeastOneMatch, requireAtLeastOneMatchOverall, allConditions bool) (parquetquery.Iterator, error) {
-
+// createScopeIterator iterates through the instrumentation scope of every scopespans and copies the
+// requested fields to its spans. It builds on top of the span iterator. Scopes are returned that
+// match any of the given conditions.
+func createScopeIterator(makeIter makeIterFn, spanIterator parquetquery.Iterator, conditions []traceql.Condition, requireAtLeastOneMatch, allConditions bool) (parquetquery.Iterator, error) {
 	var (
-		columnSelectAs    = map[string]string{}
-		columnPredicates  = map[string][]parquetquery.Predicate{}
-		iters             = []parquetquery.Iterator{}
-		genericConditions []traceql.Condition
+		columnSelectAs   = map[string]string{}
+		columnPredicates = map[string][]parquetquery.Predicate{}
+		iters            []parquetquery.Iterator
 	)
 
-	addPredicate := func(columnPath string, p parquetquery.Predicate) {
-		columnPredicates[columnPath] = append(columnPredicates[columnPath], p)
-	}
-
 	for _, cond := range conditions {
+		// the instrumentation scope only has a name and a version. other
+		// fields never exist
+		columnPath, ok := instrumentationColumnLookups[cond.Attribute.Name]
+		if !ok {
+			continue
+		}
 
-		// Well-known selector?
-		if entry, ok := wellKnownColumnLookups[cond.Attribute.Name]; ok && entry.level != traceql.AttributeScopeSpan {
-			if cond.Op == traceql.OpNone {
-				addPredicate(entry.columnPath, nil) // No filtering
-				columnSelectAs[entry.columnPath] = cond.Attribute.Name
-				continue
-			}
-
-			// Compatible type?
-			if entry.typ == operandType(cond.Operands) {
-				pred, err := createPredicate(cond.Op, cond.Operands)
-				if err != nil {
-					return nil, errors.Wrap(err, "creating predicate")
-				}
-				iters = append(iters, makeIter(entry.columnPath, pred, cond.Attribute.Name))
-				continue
+		var pred parquetquery.Predicate
+		if operandType(cond.Operands) == traceql.TypeString {
+			var err error
+			pred, err = createStringPredicate(cond.Op, cond.Operands)
+			if err != nil {
+				return nil, errors.Wrap(err, "creating predicate")
 			}
 		}
-
-		// Else: generic attribute lookup
-		genericConditions = append(genericConditions, cond)
+		columnPredicates[columnPath] = append(columnPredicates[columnPath], pred)
+		columnSelectAs[columnPath] = cond.Attribute.Name
 	}
 
 	for columnPath, predicates := range columnPredicates {
 		iters = append(iters, makeIter(columnPath, parquetquery.NewOrPredicate(predicates...), columnSelectAs[columnPath]))
 	}
 
-	attrIter, err := createAttributeIterator(makeIter, genericConditions, DefinitionLevelResourceAttrs,
-		columnPathResourceAttrKey, columnPathResourceAttrString, columnPathResourceAttrInt, columnPathResourceAttrDouble, columnPathResourceAttrBool, allConditions)
-	if err != nil {
-		return nil, errors.Wrap(err, "creating span attribute iterator")
+	minCount := 0
+	if requireAtLeastOneMatch {
+		minCount = 1
 	}
-	if attrIter != nil {
-		iters = append(iters, attrIter)
+	if allConditions {
+		// The final number of expected attributes
+		distinct := map[string]struct{}{}
+		for _, cond := range conditions {
+			distinct[cond.Attribute.Name] = struct{}{}
+		}
+		minCount = len(distinct)
+	}
+	scopeCol := &scopeCollector{
+		minAttributes: minCount,
+	}
+
+	var required []parquetquery.Iterator
+
+	// This is an optimization for when all of the scope conditions must be met.
+	// We simply move all iterators into the required list.
+	if allConditions {
+		required = append(required, iters...)
+		iters = nil
+	}
+
+	// This is an optimization for cases when only scope conditions are
+	// present and we require at least one of them to match.  Wrap
+	// up the individual conditions with a union and move it into the
+	// required list.
+	if requireAtLeastOneMatch && len(iters) > 0 {
+		required = append(required, parquetquery.NewUnionIterator(DefinitionLevelResourceSpansILS, iters, nil))
+		iters = nil
+	}
+
+	// Put span iterator last so it is only read when
+	// the scope conditions are met.
+	required = append(required, spanIterator)
+
+	return parquetquery.NewLeftJoinIterator(DefinitionLevelResourceSpansILS,
+		required, iters, scopeCol), nil
+}
+
+// createResourceIterator iterates through all resourcespans-level (batch-level) columns, groups them into rows representing
+// one batch each. It builds on top of the span iterator, and turns the groups of spans and resource-level values into
+// spansets.  Spansets are returned that match any of the given conditions. Batches and spans without one of
+// the missing attributes match as well, the projected attributes never count as a match.
+func createResourceIterator(makeIter makeIterFn, spanIterator parquetquery.Iterator, conditions []traceql.Condition, missing, projected []traceql.Attribute, requireAtLeastOneMatch, requireAtLeastOneMatchOverall, allConditions bool) (parquetquery.Iterator, error) {
+	iters, err := createResourceColumnIterators(makeIter, conditions, allConditions)
+	if err != nil {
+		return nil, err
 	}
 
 	minCount := 0
@@ -848,6 +1169,13 @@ func createResourceIterator(makeIter makeIterFn, spanIterator parquetquery.Itera
 	batchCol := &batchCollector{
 		requireAtLeastOneMatchOverall: requireAtLeastOneMatchOverall,
 		minAttributes:                 minCount,
+		missingOverall:                missing,
+		projected:                     projected,
+	}
+	for _, a := range missing {
+		if a.Scope == traceql.AttributeScopeResource {
+			batchCol.missing = append(batchCol.missing, a)
+		}
 	}
 
 	var required []parquetquery.Iterator
@@ -862,8 +1190,9 @@ func createResourceIterator(makeIter makeIterFn, spanIterator parquetquery.Itera
 	// This is an optimization for cases when only resource conditions are
 	// present and we require at least one of them to match.  Wrap
 	// up the individual conditions with a union and move it into the
-	// required list.
-	if requireAtLeastOneMatch && len(iters) > 0 {
+	// required list. Batches without a missing attribute have no entries
+	// so this isn't possible then.
+	if requireAtLeastOneMatch && len(iters) > 0 && len(batchCol.missing) == 0 {
 		required = append(required, parquetquery.NewUnionIterator(DefinitionLevelResourceSpans, iters, nil))
 		iters = nil
 	}
@@ -879,56 +1208,181 @@ func createResourceIterator(makeIter makeIterFn, spanIterator parquetquery.Itera
 		required, iters, batchCol), nil
 }
 
-func createTraceIterator(makeIter makeIterFn, resourceIter parquetquery.Iterator, conds []traceql.Condition, start, end uint64) parquetquery.Iterator {
-	traceIters := make([]parquetquery.Iterator, 0, 3)
+// createResourceColumnIterators returns the iterators of the resource-level columns and generic
+// attributes that are read for the given conditions
+func createResourceColumnIterators(makeIter makeIterFn, conditions []traceql.Condition, allConditions bool) ([]parquetquery.Iterator, error) {
+	var (
+		columnSelectAs    = map[string]string{}
+		columnPredicates  = map[string][]parquetquery.Predicate{}
+		iters             = []parquetquery.Iterator{}
+		genericConditions []traceql.Condition
+	)
 
-	// order is interesting here. would it be more efficient to grab the span/resource conditions first
-	// or the time range filtering first?
-	traceIters = append(traceIters, resourceIter)
+	addPredicate := func(columnPath string, p parquetquery.Predicate) {
+		columnPredicates[columnPath] = append(columnPredicates[columnPath], p)
+	}
 
-	// evaluate time range
-	// Time range filtering?
-	if start > 0 && end > 0 {
-		// Here's how we detect the span overlaps the time window:
-		// Span start <= req.End
-		// Span end >= req.Start
-		var startFilter, endFilter parquetquery.Predicate
-		startFilter = parquetquery.NewIntBetweenPredicate(0, int64(end))
-		endFilter = parquetquery.NewIntBetweenPredicate(int64(start), math.MaxInt64)
+	for _, cond := range conditions {
 
-		traceIters = append(traceIters, makeIter(columnPathStartTimeUnixNano, startFilter, columnPathStartTimeUnixNano))
-		traceIters = append(traceIters, makeIter(columnPathEndTimeUnixNano, endFilter, columnPathEndTimeUnixNano))
-	}
+		// Well-known selector?
+		if entry, ok := wellKnownColumnLookups[cond.Attribute.Name]; ok && entry.level != traceql.AttributeScopeSpan {
+			if cond.Op == traceql.OpNone {
+				addPredicate(entry.columnPath, nil) // No filtering
+				columnSelectAs[entry.columnPath] = cond.Attribute.Name
+				continue
+			}
 
-	for _, cond := range conds {
-		switch cond.Attribute.Intrinsic {
-		case traceql.IntrinsicTraceID:
-			traceIters = append(traceIters, makeIter(columnPathTraceID, nil, columnPathTraceID))
-		case traceql.IntrinsicTraceDuration:
-			traceIters = append(traceIters, makeIter(columnPathDurationNanos, nil, columnPathDurationNanos))
-		case traceql.IntrinsicTraceStartTime:
-			if start == 0 && end == 0 {
-				traceIters = append(traceIters, makeIter(columnPathStartTimeUnixNano, nil, columnPathStartTimeUnixNano))
+			// Compatible type?
+			if entry.typ == operandType(cond.Operands) {
+				pred, err := createPredicate(cond.Op, cond.Operands)
+				if err != nil {
+					return nil, errors.Wrap(err, "creating predicate")
+				}
+				iters = append(iters, makeIter(entry.columnPath, pred, cond.Attribute.Name))
+				continue
 			}
-		case traceql.IntrinsicTraceRootSpan:
-			traceIters = append(traceIters, makeIter(columnPathRootSpanName, nil, columnPathRootSpanName))
-		case traceql.IntrinsicTraceRootService:
-			traceIters = append(traceIters, makeIter(columnPathRootServiceName, nil, columnPathRootServiceName))
 		}
-	}
 
-	// Final trace iterator
-	// Join iterator means it requires matching resources to have been found
-	// TraceCollor adds trace-level data to the spansets
-	return parquetquery.NewJoinIterator(DefinitionLevelTrace, traceIters, &traceCollector{})
-}
+		// Else: generic attribute lookup
+		genericConditions = append(genericConditions, cond)
+	}
 
-func createPredicate(op traceql.Operator, operands traceql.Operands) (parquetquery.Predicate, error) {
-	if op == traceql.OpNone {
-		return nil, nil
+	for columnPath, predicates := range columnPredicates {
+		iters = append(iters, makeIter(columnPath, parquetquery.NewOrPredicate(predicates...), columnSelectAs[columnPath]))
 	}
 
-	switch operands[0].Type {
+	attrIter, err := createAttributeIterator(makeIter, genericConditions, DefinitionLevelResourceAttrs,
+		columnPathResourceAttrKey, columnPathResourceAttrString, columnPathResourceAttrInt, columnPathResourceAttrDouble, columnPathResourceAttrBool, allConditions)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating span attribute iterator")
+	}
+	if attrIter != nil {
+		iters = append(iters, attrIter)
+	}
+
+	return iters, nil
+}
+
+// createRelationsIterator iterates the ID and parent ID of every span so the trace collector can find the
+// parents of the returned spans and compute how they are related. The attributes that are requested with the
+// parent scope are read for every span as well. Nothing else is read so this is a lot cheaper than returning
+// every span from the span iterator.
+func createRelationsIterator(makeIter makeIterFn, spanConditions, resourceConditions []traceql.Condition) (parquetquery.Iterator, error) {
+	spanIter, err := createSpanIterator(makeIter, makeIter(columnPathSpanID, nil, columnPathSpanID), spanConditions, nil, nil, false, false, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating span iterator")
+	}
+
+	if len(resourceConditions) == 0 {
+		return spanIter, nil
+	}
+
+	iters, err := createResourceColumnIterators(makeIter, resourceConditions, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating resource iterator")
+	}
+
+	return parquetquery.NewLeftJoinIterator(DefinitionLevelResourceSpans,
+		[]parquetquery.Iterator{spanIter}, iters, &relationsCollector{}), nil
+}
+
+func createTraceIterator(makeIter makeIterFn, resourceIter, relationsIter parquetquery.Iterator, conds, parentConds []traceql.Condition, structural bool, start, end uint64, sample float64, allConditions bool) (parquetquery.Iterator, error) {
+	traceIters := make([]parquetquery.Iterator, 0, 3)
+
+	// sampling only needs the trace ID so it goes first and filters out everything else as cheaply as possible
+	if sample > 0 && sample < 1 {
+		pred := parquetquery.NewGenericPredicate(func(traceID []byte) bool {
+			return traceql.SampleTrace(traceID, sample)
+		}, nil, func(v parquet.Value) []byte {
+			return v.ByteArray()
+		})
+		traceIters = append(traceIters, makeIter(columnPathTraceID, pred, ""))
+	}
+
+	// add conditional iterators first. this way if someone searches for { traceDuration > 1s && span.foo = "bar" } the query will
+	// be sped up by filtering on traceDuration first. predicates can only be pushed down if all conditions must be met,
+	// otherwise the column is just fetched and the engine makes the final decision
+	for _, cond := range conds {
+		switch cond.Attribute.Intrinsic {
+		case traceql.IntrinsicTraceID:
+			traceIters = append(traceIters, makeIter(columnPathTraceID, nil, columnPathTraceID))
+		case traceql.IntrinsicTraceDuration:
+			var pred parquetquery.Predicate
+			if allConditions {
+				var err error
+				pred, err = createIntPredicate(cond.Op, cond.Operands)
+				if err != nil {
+					return nil, err
+				}
+			}
+			traceIters = append(traceIters, makeIter(columnPathDurationNanos, pred, columnPathDurationNanos))
+		case traceql.IntrinsicTraceStartTime:
+			if start == 0 && end == 0 {
+				traceIters = append(traceIters, makeIter(columnPathStartTimeUnixNano, nil, columnPathStartTimeUnixNano))
+			}
+		case traceql.IntrinsicTraceRootSpan:
+			var pred parquetquery.Predicate
+			if allConditions {
+				var err error
+				pred, err = createStringPredicate(cond.Op, cond.Operands)
+				if err != nil {
+					return nil, err
+				}
+			}
+			traceIters = append(traceIters, makeIter(columnPathRootSpanName, pred, columnPathRootSpanName))
+		case traceql.IntrinsicTraceRootService:
+			var pred parquetquery.Predicate
+			if allConditions {
+				var err error
+				pred, err = createStringPredicate(cond.Op, cond.Operands)
+				if err != nil {
+					return nil, err
+				}
+			}
+			traceIters = append(traceIters, makeIter(columnPathRootServiceName, pred, columnPathRootServiceName))
+		}
+	}
+
+	// order is interesting here. would it be more efficient to grab the span/resource conditions first
+	// or the time range filtering first?
+	traceIters = append(traceIters, resourceIter)
+
+	// evaluate time range
+	// Time range filtering?
+	if start > 0 && end > 0 {
+		// Here's how we detect the span overlaps the time window:
+		// Span start <= req.End
+		// Span end >= req.Start
+		var startFilter, endFilter parquetquery.Predicate
+		startFilter = parquetquery.NewIntBetweenPredicate(0, int64(end))
+		endFilter = parquetquery.NewIntBetweenPredicate(int64(start), math.MaxInt64)
+
+		traceIters = append(traceIters, makeIter(columnPathStartTimeUnixNano, startFilter, columnPathStartTimeUnixNano))
+		traceIters = append(traceIters, makeIter(columnPathEndTimeUnixNano, endFilter, columnPathEndTimeUnixNano))
+	}
+
+	// the relations of the spans are only read for the traces that have matching spans in the time range
+	if relationsIter != nil {
+		traceIters = append(traceIters, relationsIter)
+	}
+
+	parentAttrs := make([]traceql.Attribute, 0, len(parentConds))
+	for _, cond := range parentConds {
+		parentAttrs = append(parentAttrs, cond.Attribute)
+	}
+
+	// Final trace iterator
+	// Join iterator means it requires matching resources to have been found
+	// TraceCollor adds trace-level data to the spansets
+	return parquetquery.NewJoinIterator(DefinitionLevelTrace, traceIters, newTraceCollector(parentAttrs, structural)), nil
+}
+
+func createPredicate(op traceql.Operator, operands traceql.Operands) (parquetquery.Predicate, error) {
+	if op == traceql.OpNone {
+		return nil, nil
+	}
+
+	switch operands[0].Type {
 	case traceql.TypeString:
 		return createStringPredicate(op, operands)
 	case traceql.TypeInt:
@@ -978,6 +1432,15 @@ func createStringPredicate(op traceql.Operator, operands traceql.Operands) (parq
 	case traceql.OpEqual:
 		return parquetquery.NewStringInPredicate([]string{s}), nil
 
+	case traceql.OpEqualFold:
+		return parquetquery.NewEqualFoldPredicate(s), nil
+	case traceql.OpStartsWith:
+		return parquetquery.NewPrefixPredicate(s), nil
+	case traceql.OpEndsWith:
+		return parquetquery.NewSuffixPredicate(s), nil
+	case traceql.OpSubstring:
+		return parquetquery.NewSubstringPredicate(s), nil
+
 	case traceql.OpGreater:
 		return parquetquery.NewGenericPredicate(
 			func(v string) bool {
@@ -1238,9 +1701,233 @@ func createAttributeIterator(makeIter makeIterFn, conditions []traceql.Condition
 	return nil, nil
 }
 
+// createEventIterator iterates the events of every span and returns the requested event attributes.
+// event attribute values are stored proto encoded so no predicates can be pushed down for them.
+// instead the values are decoded here and evaluated by the span collector. the event name can only
+// be filtered when all conditions must be met, otherwise another event could satisfy the query
+func createEventIterator(makeIter makeIterFn, conditions []traceql.Condition, allConditions bool) (parquetquery.Iterator, error) {
+	var (
+		keys      []string
+		namePreds []parquetquery.Predicate
+		fetchName bool
+	)
+	for _, cond := range conditions {
+		if cond.Attribute.Name != LabelName {
+			keys = append(keys, cond.Attribute.Name)
+			continue
+		}
+
+		fetchName = true
+		if !allConditions || operandType(cond.Operands) != traceql.TypeString {
+			namePreds = append(namePreds, nil)
+			continue
+		}
+
+		pred, err := createStringPredicate(cond.Op, cond.Operands)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating event name predicate")
+		}
+		namePreds = append(namePreds, pred)
+	}
+
+	var namePred parquetquery.Predicate
+	if len(namePreds) > 0 {
+		namePred = parquetquery.NewOrPredicate(namePreds...)
+	}
+
+	var attrIters []parquetquery.Iterator
+	if len(keys) > 0 {
+		attrIters = append(attrIters, parquetquery.NewJoinIterator(DefinitionLevelResourceSpansILSSpanEventAttrs,
+			[]parquetquery.Iterator{
+				makeIter(columnPathSpanEventAttrKey, parquetquery.NewStringInPredicate(keys), "key"),
+				makeIter(columnPathSpanEventAttrValue, nil, "value"),
+			},
+			&eventAttributeCollector{}))
+	}
+
+	// every event has a name so the column is always used to find all events
+	return parquetquery.NewLeftJoinIterator(DefinitionLevelResourceSpansILSSpanEvent,
+		[]parquetquery.Iterator{makeIter(columnPathSpanEventName, namePred, columnPathSpanEventName)},
+		attrIters,
+		&eventCollector{fetchName: fetchName}), nil
+}
+
+// nestedCondition is a condition on an event or link attribute. it is evaluated against every decoded
+// event or link of a span to find the one that is returned
+type nestedCondition struct {
+	attribute traceql.Attribute
+	typ       traceql.StaticType
+	pred      parquetquery.Predicate
+}
+
+func newNestedConditions(conditions []traceql.Condition) ([]nestedCondition, error) {
+	nested := make([]nestedCondition, 0, len(conditions))
+	for _, cond := range conditions {
+		nc := nestedCondition{attribute: cond.Attribute}
+
+		// other operand types are left to the engine
+		switch typ := operandType(cond.Operands); typ {
+		case traceql.TypeString, traceql.TypeInt, traceql.TypeFloat, traceql.TypeBoolean:
+			pred, err := createPredicate(cond.Op, cond.Operands)
+			if err != nil {
+				return nil, err
+			}
+			nc.typ = typ
+			nc.pred = pred
+		}
+
+		nested = append(nested, nc)
+	}
+
+	return nested, nil
+}
+
+// matches returns true if the given attributes hold a value that satisfies the condition
+func (c *nestedCondition) matches(attrs map[traceql.Attribute]traceql.Static) bool {
+	v, ok := attrs[c.attribute]
+	if !ok {
+		return false
+	}
+	if c.pred == nil {
+		return true
+	}
+	if v.Type != c.typ {
+		return false
+	}
+
+	switch v.Type {
+	case traceql.TypeString:
+		return c.pred.KeepValue(parquet.ValueOf(v.S))
+	case traceql.TypeInt:
+		return c.pred.KeepValue(parquet.ValueOf(int64(v.N)))
+	case traceql.TypeFloat:
+		return c.pred.KeepValue(parquet.ValueOf(v.F))
+	case traceql.TypeBoolean:
+		return c.pred.KeepValue(parquet.ValueOf(v.B))
+	}
+
+	return false
+}
+
+// copyNestedAttributes finds the event or link that satisfies the most conditions and copies its matching
+// attributes to the span. the first one wins a tie. a span holds a single value per attribute so all values
+// are taken from the same event or link, this keeps { event.a = 1 && event.b = 2 } from being satisfied by
+// two different events
+func copyNestedAttributes(sp *span, entries []map[traceql.Attribute]traceql.Static, conditions []nestedCondition) {
+	var (
+		best      map[traceql.Attribute]traceql.Static
+		bestCount = 0
+	)
+	for _, attrs := range entries {
+		count := 0
+		for i := range conditions {
+			if conditions[i].matches(attrs) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best = attrs
+			bestCount = count
+		}
+	}
+
+	for i := range conditions {
+		if conditions[i].matches(best) {
+			sp.attributes[conditions[i].attribute] = best[conditions[i].attribute]
+		}
+	}
+}
+
+// appendLinkAttributes decodes the proto encoded links of a span and appends the requested
+// attributes of each link
+func appendLinkAttributes(links []map[traceql.Attribute]traceql.Static, buf []byte, conditions []nestedCondition) []map[traceql.Attribute]traceql.Static {
+	if len(buf) == 0 {
+		return links
+	}
+
+	linkSlice := tempopb.LinkSlice{}
+	if err := linkSlice.Unmarshal(buf); err != nil {
+		return links
+	}
+
+	for _, l := range linkSlice.Links {
+		attrs := make(map[traceql.Attribute]traceql.Static, len(conditions))
+		for _, cond := range conditions {
+			switch cond.attribute.Name {
+			case linkTraceID:
+				attrs[cond.attribute] = traceql.NewStaticString(util.TraceIDToHexString(l.TraceId))
+				continue
+			case linkSpanID:
+				attrs[cond.attribute] = traceql.NewStaticString(util.SpanIDToHexString(l.SpanId))
+				continue
+			}
+
+			for _, kv := range l.Attributes {
+				if kv.Key != cond.attribute.Name {
+					continue
+				}
+				if v, ok := staticFromAnyValue(kv.Value); ok {
+					attrs[cond.attribute] = v
+				}
+				break
+			}
+		}
+		links = append(links, attrs)
+	}
+
+	return links
+}
+
+// staticFromAnyValue converts an OTLP value to a traceql static. kvlists and nested arrays are not supported
+func staticFromAnyValue(v *v1_common.AnyValue) (traceql.Static, bool) {
+	if v == nil {
+		return traceql.Static{}, false
+	}
+
+	switch v := v.Value.(type) {
+	case *v1_common.AnyValue_StringValue:
+		return traceql.NewStaticString(v.StringValue), true
+	case *v1_common.AnyValue_IntValue:
+		return traceql.NewStaticInt(int(v.IntValue)), true
+	case *v1_common.AnyValue_DoubleValue:
+		return traceql.NewStaticFloat(v.DoubleValue), true
+	case *v1_common.AnyValue_BoolValue:
+		return traceql.NewStaticBool(v.BoolValue), true
+	case *v1_common.AnyValue_ArrayValue:
+		if v.ArrayValue == nil {
+			return traceql.Static{}, false
+		}
+		elems := make([]traceql.Static, 0, len(v.ArrayValue.Values))
+		for _, e := range v.ArrayValue.Values {
+			s, ok := staticFromAnyValue(e)
+			if !ok || s.Type == traceql.TypeArray {
+				return traceql.Static{}, false
+			}
+			elems = append(elems, s)
+		}
+		return traceql.NewStaticArray(elems), true
+	}
+
+	return traceql.Static{}, false
+}
+
 // This turns groups of span values into Span objects
 type spanCollector struct {
 	minAttributes int
+
+	// missing attributes are counted as matched when they aren't found
+	missing []traceql.Attribute
+
+	// projected attributes are only fetched, finding them isn't counted as a match
+	projected []traceql.Attribute
+
+	// eventConditions and linkConditions are used to pick the event and link
+	// of the span whose attributes are returned. events and links are buffers
+	// reused by KeepGroup
+	eventConditions []nestedCondition
+	linkConditions  []nestedCondition
+	events          []map[traceql.Attribute]traceql.Static
+	links           []map[traceql.Attribute]traceql.Static
 }
 
 var _ parquetquery.GroupPredicate = (*spanCollector)(nil)
@@ -1265,8 +1952,15 @@ func (c *spanCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 		sp.rowNum = res.RowNumber
 	}
 
+	c.events = c.events[:0]
+	c.links = c.links[:0]
+
 	for _, e := range res.OtherEntries {
-		if e.Key == otherEntrySpanKey {
+		switch e.Key {
+		case otherEntrySpanKey:
+			continue
+		case otherEntryEventKey:
+			c.events = append(c.events, e.Value.(map[traceql.Attribute]traceql.Static))
 			continue
 		}
 		sp.attributes[newSpanAttr(e.Key)] = e.Value.(traceql.Static)
@@ -1279,6 +1973,10 @@ func (c *spanCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 		switch kv.Key {
 		case columnPathSpanID:
 			sp.id = kv.Value.ByteArray()
+		case columnPathSpanParentSpanID:
+			sp.parentID = kv.Value.ByteArray()
+		case columnPathSpanLinks:
+			c.links = appendLinkAttributes(c.links, kv.Value.ByteArray(), c.linkConditions)
 		case columnPathSpanStartTime:
 			sp.startTimeUnixNanos = kv.Value.Uint64()
 		case columnPathSpanDuration:
@@ -1337,8 +2035,12 @@ func (c *spanCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 		}
 	}
 
+	// copy over the matching attributes of the best matching event and link
+	copyNestedAttributes(sp, c.events, c.eventConditions)
+	copyNestedAttributes(sp, c.links, c.linkConditions)
+
 	if c.minAttributes > 0 {
-		count := sp.attributesMatched()
+		count := sp.attributesMatched() + matchMissing(sp.attributes, c.missing) + matchProjected(sp.attributes, c.projected)
 		if count < c.minAttributes {
 			putSpan(sp)
 			return false
@@ -1352,12 +2054,53 @@ func (c *spanCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 	return true
 }
 
+// scopeCollector receives rows of matching instrumentation scopes and
+// copies the scope fields to their spans
+type scopeCollector struct {
+	minAttributes int
+}
+
+var _ parquetquery.GroupPredicate = (*scopeCollector)(nil)
+
+func (c *scopeCollector) String() string {
+	return fmt.Sprintf("scopeCollector(%d)", c.minAttributes)
+}
+
+func (c *scopeCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
+	if len(res.Entries) < c.minAttributes {
+		return false
+	}
+
+	for _, e := range res.OtherEntries {
+		sp, ok := e.Value.(*span)
+		if !ok {
+			continue
+		}
+		for _, kv := range res.Entries {
+			sp.attributes[newScopeAttr(kv.Key)] = traceql.NewStaticString(kv.Value.String())
+		}
+	}
+
+	// the spans are passed on untouched to the batch collector
+	res.Entries = res.Entries[:0]
+
+	return true
+}
+
 // batchCollector receives rows of matching resource-level
 // This turns groups of batch values and Spans into SpanSets
 type batchCollector struct {
 	requireAtLeastOneMatchOverall bool
 	minAttributes                 int
 
+	// missing attributes are counted as matched when they aren't found. missing
+	// holds the resource attributes, missingOverall the span attributes as well
+	missing        []traceql.Attribute
+	missingOverall []traceql.Attribute
+
+	// projected attributes are only fetched, finding them isn't counted as a match
+	projected []traceql.Attribute
+
 	// shared static spans used in KeepGroup. done for memory savings, but won't
 	// work if the batchCollector is accessed concurrently
 	buffer []*span
@@ -1404,7 +2147,7 @@ func (c *batchCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 	}
 
 	if c.minAttributes > 0 {
-		if len(resAttrs) < c.minAttributes {
+		if len(resAttrs)+matchMissing(resAttrs, c.missing)+matchProjected(resAttrs, c.projected) < c.minAttributes {
 			return false
 		}
 	}
@@ -1432,7 +2175,7 @@ func (c *batchCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 	// Copy over only spans that met minimum criteria
 	if c.requireAtLeastOneMatchOverall {
 		for _, span := range c.buffer {
-			if span.attributesMatched() > 0 {
+			if span.attributesMatched()+matchMissing(span.attributes, c.missingOverall)+matchProjected(span.attributes, c.projected) > 0 {
 				filteredSpans = append(filteredSpans, span)
 				continue
 			}
@@ -1461,14 +2204,123 @@ func (c *batchCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 	return true
 }
 
+// matchMissing returns the number of missing attributes that aren't found minus the
+// ones that are. Found attributes were already counted but don't match { .foo = nil }.
+func matchMissing(attrs map[traceql.Attribute]traceql.Static, missing []traceql.Attribute) int {
+	count := 0
+	for _, a := range missing {
+		if v, ok := attrs[a]; ok && v.Type != traceql.TypeNil {
+			count--
+		} else {
+			count++
+		}
+	}
+	return count
+}
+
+// matchProjected returns minus the number of projected attributes that are found. They were already
+// counted but only fetched for the engine. Unscoped attributes are found at the span or resource level.
+func matchProjected(attrs map[traceql.Attribute]traceql.Static, projected []traceql.Attribute) int {
+	count := 0
+	for _, a := range projected {
+		keys := []traceql.Attribute{a}
+		if a.Scope == traceql.AttributeScopeNone && a.Intrinsic == traceql.IntrinsicNone {
+			keys = []traceql.Attribute{newSpanAttr(a.Name), newResAttr(a.Name)}
+		}
+		for _, k := range keys {
+			if v, ok := attrs[k]; ok && v.Type != traceql.TypeNil {
+				count--
+			}
+		}
+	}
+	return count
+}
+
+// relationsCollector copies the resource-level attributes of a batch to its spans for the relations
+// iterator. The spans are passed on one by one, they aren't returned as a spanset.
+type relationsCollector struct {
+	// shared static spans used in KeepGroup. done for memory savings, but won't
+	// work if the relationsCollector is accessed concurrently
+	buffer []*span
+}
+
+var _ parquetquery.GroupPredicate = (*relationsCollector)(nil)
+
+func (c *relationsCollector) String() string {
+	return "relationsCollector{}"
+}
+
+func (c *relationsCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
+	c.buffer = c.buffer[:0]
+
+	resAttrs := make(map[traceql.Attribute]traceql.Static)
+	for _, kv := range res.OtherEntries {
+		if span, ok := kv.Value.(*span); ok {
+			c.buffer = append(c.buffer, span)
+			continue
+		}
+		resAttrs[newResAttr(kv.Key)] = kv.Value.(traceql.Static)
+	}
+
+	for _, e := range res.Entries {
+		switch e.Value.Kind() {
+		case parquet.Int64:
+			resAttrs[newResAttr(e.Key)] = traceql.NewStaticInt(int(e.Value.Int64()))
+		case parquet.ByteArray:
+			resAttrs[newResAttr(e.Key)] = traceql.NewStaticString(e.Value.String())
+		}
+	}
+
+	res.Entries = res.Entries[:0]
+	res.OtherEntries = res.OtherEntries[:0]
+
+	for _, span := range c.buffer {
+		for k, v := range resAttrs {
+			if _, alreadyExists := span.attributes[k]; !alreadyExists && v.Type != traceql.TypeNil {
+				span.attributes[k] = v
+			}
+		}
+		res.AppendOtherValue(otherEntrySpanKey, span)
+	}
+
+	return len(c.buffer) > 0
+}
+
 // traceCollector receives rows from the resource-level matches.
 // It adds trace-level attributes into the spansets before
 // they are returned
 type traceCollector struct {
+	// traceAttrs is a map reused by KeepGroup to reduce allocations
+	traceAttrs map[traceql.Attribute]traceql.Static
+
+	// relations are the spans of the relations iterator. They are reused by KeepGroup to
+	// link the returned spans to their parents
+	relations      []*span
+	relationsByRow map[parquetquery.RowNumber]*span
+	spansByID      map[string]*span
+
+	// parentAttrs are the attributes requested with the parent scope
+	parentAttrs []traceql.Attribute
+
+	// structural is set if the nested set values of the spans are needed. The block doesn't
+	// store them so they are always computed
+	structural bool
+	children   map[string][]*span
 }
 
 var _ parquetquery.GroupPredicate = (*traceCollector)(nil)
 
+func newTraceCollector(parentAttrs []traceql.Attribute, structural bool) *traceCollector {
+	return &traceCollector{
+		traceAttrs:     make(map[traceql.Attribute]traceql.Static),
+		relationsByRow: make(map[parquetquery.RowNumber]*span),
+		spansByID:      make(map[string]*span),
+		parentAttrs:    parentAttrs,
+		structural:     structural,
+		children:       make(map[string][]*span),
+	}
+}
+
 func (c *traceCollector) String() string {
 	return "traceCollector{}"
 }
@@ -1476,6 +2328,10 @@ func (c *traceCollector) String() string {
 func (c *traceCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 	finalSpanset := &traceql.Spanset{}
 
+	for k := range c.traceAttrs {
+		delete(c.traceAttrs, k)
+	}
+
 	for _, e := range res.Entries {
 		switch e.Key {
 		case columnPathTraceID:
@@ -1484,16 +2340,47 @@ func (c *traceCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 			finalSpanset.StartTimeUnixNanos = e.Value.Uint64()
 		case columnPathDurationNanos:
 			finalSpanset.DurationNanos = e.Value.Uint64()
+			c.traceAttrs[traceql.NewIntrinsic(traceql.IntrinsicTraceDuration)] = traceql.NewStaticDuration(time.Duration(finalSpanset.DurationNanos))
 		case columnPathRootSpanName:
 			finalSpanset.RootSpanName = e.Value.String()
+			c.traceAttrs[traceql.NewIntrinsic(traceql.IntrinsicTraceRootSpan)] = traceql.NewStaticString(finalSpanset.RootSpanName)
 		case columnPathRootServiceName:
 			finalSpanset.RootServiceName = e.Value.String()
+			c.traceAttrs[traceql.NewIntrinsic(traceql.IntrinsicTraceRootService)] = traceql.NewStaticString(finalSpanset.RootServiceName)
 		}
 	}
 
+	c.relations = c.relations[:0]
 	for _, e := range res.OtherEntries {
-		if spanset, ok := e.Value.(*traceql.Spanset); ok {
-			finalSpanset.Spans = append(finalSpanset.Spans, spanset.Spans...)
+		switch v := e.Value.(type) {
+		case *traceql.Spanset:
+			finalSpanset.Spans = append(finalSpanset.Spans, v.Spans...)
+		case *span:
+			c.relations = append(c.relations, v)
+		}
+	}
+
+	if len(c.relations) > 0 {
+		if c.structural {
+			c.assignNestedSet()
+		}
+		c.linkRelations(finalSpanset.Spans)
+		if len(c.parentAttrs) > 0 {
+			c.resolveParents(finalSpanset.Spans)
+		}
+
+		for _, sp := range c.relations {
+			putSpan(sp)
+		}
+	}
+
+	// trace-level intrinsics are evaluated by the engine on the span so copy them down
+	if len(c.traceAttrs) > 0 {
+		for _, s := range finalSpanset.Spans {
+			sp := s.(*span)
+			for k, v := range c.traceAttrs {
+				sp.attributes[k] = v
+			}
 		}
 	}
 
@@ -1504,6 +2391,120 @@ func (c *traceCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 	return true
 }
 
+// linkRelations copies the ID, parent ID and nested set values of the relations to the returned
+// spans. Both are read from the same rows so they are matched by row number.
+func (c *traceCollector) linkRelations(spans []traceql.Span) {
+	for k := range c.relationsByRow {
+		delete(c.relationsByRow, k)
+	}
+	for k := range c.spansByID {
+		delete(c.spansByID, k)
+	}
+
+	for _, r := range c.relations {
+		c.relationsByRow[parquetquery.TruncateRowNumber(DefinitionLevelResourceSpansILSSpan, r.rowNum)] = r
+		if len(r.id) > 0 {
+			c.spansByID[string(r.id)] = r
+		}
+	}
+
+	for _, s := range spans {
+		sp := s.(*span)
+		r, ok := c.relationsByRow[parquetquery.TruncateRowNumber(DefinitionLevelResourceSpansILSSpan, sp.rowNum)]
+		if !ok {
+			continue
+		}
+		sp.id = r.id
+		sp.parentID = r.parentID
+		sp.nestedSetLeft = r.nestedSetLeft
+		sp.nestedSetRight = r.nestedSetRight
+		sp.nestedSetParent = r.nestedSetParent
+	}
+}
+
+// resolveParents copies the requested attributes of every span's parent onto the span itself
+// with the parent flag set. i.e. span.foo of the parent becomes parent.span.foo of the child
+func (c *traceCollector) resolveParents(spans []traceql.Span) {
+	for _, s := range spans {
+		sp := s.(*span)
+		if len(sp.parentID) == 0 {
+			continue
+		}
+
+		parent, ok := c.spansByID[string(sp.parentID)]
+		if !ok || bytes.Equal(parent.id, sp.id) {
+			continue
+		}
+
+		for k, v := range parent.attributes {
+			if v.Type == traceql.TypeNil || !c.isParentAttr(k) {
+				continue
+			}
+			k.Parent = true
+			sp.attributes[k] = v
+		}
+	}
+}
+
+// assignNestedSet numbers the spans of the relations using the nested set model so the engine can evaluate
+// structural operators: a descendant's left and right lie between its ancestor's and a span's parent
+// value is the left value of its parent. spans whose parent isn't found in the trace are treated as roots
+func (c *traceCollector) assignNestedSet() {
+	for k := range c.children {
+		delete(c.children, k)
+	}
+
+	ids := make(map[string]struct{}, len(c.relations))
+	for _, sp := range c.relations {
+		ids[string(sp.id)] = struct{}{}
+	}
+
+	var roots []*span
+	for _, sp := range c.relations {
+		if _, ok := ids[string(sp.parentID)]; len(sp.parentID) == 0 || !ok {
+			roots = append(roots, sp)
+			continue
+		}
+		c.children[string(sp.parentID)] = append(c.children[string(sp.parentID)], sp)
+	}
+
+	var (
+		next  int32 = 1
+		visit func(sp *span, parent int32)
+	)
+	visit = func(sp *span, parent int32) {
+		sp.nestedSetParent = parent
+		sp.nestedSetLeft = next
+		next++
+
+		// deleting the children protects against cycles in malformed traces
+		children := c.children[string(sp.id)]
+		delete(c.children, string(sp.id))
+		for _, child := range children {
+			visit(child, sp.nestedSetLeft)
+		}
+
+		sp.nestedSetRight = next
+		next++
+	}
+
+	for _, root := range roots {
+		visit(root, 0)
+	}
+}
+
+// isParentAttr returns true if the given span attribute is requested with the parent scope
+func (c *traceCollector) isParentAttr(a traceql.Attribute) bool {
+	for _, p := range c.parentAttrs {
+		if p.Name == a.Name && p.Intrinsic == a.Intrinsic &&
+			(p.Scope == traceql.AttributeScopeNone || p.Scope == a.Scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // attributeCollector receives rows from the individual key/string/int/etc
 // columns and joins them together into map[key]value entries with the
 // right type.
@@ -1549,6 +2550,81 @@ func (c *attributeCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 	return true
 }
 
+// eventAttributeCollector receives the key and proto encoded value of an event attribute
+// and decodes them into a key/value entry
+type eventAttributeCollector struct {
+}
+
+var _ parquetquery.GroupPredicate = (*eventAttributeCollector)(nil)
+
+func (c *eventAttributeCollector) String() string {
+	return "eventAttributeCollector{}"
+}
+
+func (c *eventAttributeCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
+	var (
+		key string
+		val traceql.Static
+		ok  bool
+	)
+
+	for _, e := range res.Entries {
+		switch e.Key {
+		case "key":
+			key = e.Value.String()
+		case "value":
+			// event attributes are currently encoded as proto, but were previously json
+			anyValue := &v1_common.AnyValue{}
+			if err := anyValue.Unmarshal(e.Value.ByteArray()); err != nil {
+				_ = jsonpb.Unmarshal(bytes.NewBuffer(e.Value.ByteArray()), anyValue)
+			}
+			val, ok = staticFromAnyValue(anyValue)
+		}
+	}
+
+	res.Entries = res.Entries[:0]
+	res.OtherEntries = res.OtherEntries[:0]
+	if !ok {
+		return false
+	}
+	res.AppendOtherValue(key, val)
+
+	return true
+}
+
+// eventCollector gathers the name and attributes of a single event
+type eventCollector struct {
+	fetchName bool
+}
+
+var _ parquetquery.GroupPredicate = (*eventCollector)(nil)
+
+func (c *eventCollector) String() string {
+	return fmt.Sprintf("eventCollector(%v)", c.fetchName)
+}
+
+func (c *eventCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
+	attrs := make(map[traceql.Attribute]traceql.Static, len(res.OtherEntries)+1)
+
+	if c.fetchName {
+		for _, e := range res.Entries {
+			if e.Key == columnPathSpanEventName {
+				attrs[newEventAttr(LabelName)] = traceql.NewStaticString(e.Value.String())
+			}
+		}
+	}
+
+	for _, e := range res.OtherEntries {
+		attrs[newEventAttr(e.Key)] = e.Value.(traceql.Static)
+	}
+
+	res.Entries = res.Entries[:0]
+	res.OtherEntries = res.OtherEntries[:0]
+	res.AppendOtherValue(otherEntryEventKey, attrs)
+
+	return true
+}
+
 func newSpanAttr(name string) traceql.Attribute {
 	return traceql.NewScopedAttribute(traceql.AttributeScopeSpan, false, name)
 }
@@ -1556,3 +2632,11 @@ func newSpanAttr(name string) traceql.Attribute {
 func newResAttr(name string) traceql.Attribute {
 	return traceql.NewScopedAttribute(traceql.AttributeScopeResource, false, name)
 }
+
+func newScopeAttr(name string) traceql.Attribute {
+	return traceql.NewScopedAttribute(traceql.AttributeScopeInstrumentation, false, name)
+}
+
+func newEventAttr(name string) traceql.Attribute {
+	return traceql.NewScopedAttribute(traceql.AttributeScopeEvent, false, name)
+}
@@ -14,8 +14,10 @@ import (
 
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
@@ -90,6 +92,23 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = error}`),
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = 2}`),
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = client }`),
+		// Trace-level intrinsics
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration = 100ms}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration > 99ms}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootName = "RootSpan"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName = "RootService"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName =~ "Root.*"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration < 1s && ` + LabelName + ` = "hello"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName = "notRootService" || ` + LabelName + ` = "hello"}`),
+		// Events and links
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.name = "e2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.name =~ "e.*" && ` + LabelName + ` = "hello"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.traceID = "1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.spanID = "0000000000000002" && link.key = "value"}`),
+		// Instrumentation scope
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-1" && instrumentation.version < "version-2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.version = "version-1" && ` + LabelName + ` = "hello"}`),
 		// Resource well-known attributes
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "spanservicename"}`), // Overridden at span
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelCluster + ` = "cluster"}`),
@@ -143,6 +162,20 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.foo = "abc"}`), // Resource-level only
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.foo = "def"}`),     // Span-level only
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo}`),                 // Projection only
+		// Existence checks
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.foo != nil}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.foo != nil && span.bar != nil}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.` + LabelHTTPStatusCode + ` != nil}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.missing = nil}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.missing = nil && span.foo = "def"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.missing = nil}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.missing = nil}`),
+		// String functions
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =* "DEF"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{lower(resource.foo) = "abc"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{upper(span.` + LabelHTTPMethod + `) = "GET"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{startsWith(span.` + LabelHTTPUrl + `, "url/")}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{endsWith(.foo, "ef") && contains(name, "ell")}`),
 		makeReq(
 			// Matches either condition
 			parse(t, `{.foo = "baz"}`),
@@ -228,7 +261,11 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 	searchesThatDontMatch := []traceql.FetchSpansRequest{
 		// TODO - Should the below query return data or not?  It does match the resource
 		// makeReq(parse(t, `{.foo = "abc"}`)),                           // This should not return results because the span has overridden this attribute to "def".
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "xyz.*"}`),                                     // Regex IN
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "xyz.*"}`), // Regex IN
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.name = "e3"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.key = "other"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-1" && instrumentation.version > "version-1"}`),
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo !~ ".*"}`),                                        // String Not Regex
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.bool = true}`),                                    // Bool not match
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` >  100s}`),                       // Intrinsic: duration
@@ -239,6 +276,25 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = 200}`),                  // Well-known attribute: http.status_code not match
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` > 600}`),                  // Well-known attribute: http.status_code not match
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "xyz" || .` + LabelHTTPStatusCode + " = 1000}"), // Matches neither condition
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration > 100ms}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootName = "NotRootSpan"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName = "NotRootService"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.missing != nil}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.missing != nil}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.foo != nil && span.missing != nil}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` = nil}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =* "xyz"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{lower(span.` + LabelHTTPMethod + `) = "post"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{startsWith(span.` + LabelHTTPUrl + `, "hello")}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{endsWith(.foo, "de") || contains(name, "xyz")}`),
+		{
+			// Trace-level intrinsic matches but span-level doesn't
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{rootServiceName = "RootService"}`), // match
+				parse(t, `{`+LabelName+` = "nothello"}`),      // no match
+			},
+		},
 		{
 			// Time range after trace
 			StartTimeUnixNanos: uint64(3000 * time.Second),
@@ -327,6 +383,246 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 	}
 }
 
+func TestBackendBlockSearchTraceQLMissing(t *testing.T) {
+	wantTraceID := test.ValidTraceID(nil)
+	b := makeBackendBlockWithTraces(t, []*Trace{fullyPopulatedTestTrace(wantTraceID)})
+	ctx := context.Background()
+
+	testCases := []struct {
+		query   string
+		spanIDs []string
+	}{
+		// spans and batches with the attribute are dropped by the collectors
+		{`{span.foo = nil}`, []string{"spanid2"}},
+		{`{resource.foo = nil}`, []string{"spanid2"}},
+		{`{span.foo = nil || span.bar = 123}`, []string{"spanid", "spanid2"}},
+		{`{resource.` + LabelServiceName + ` = nil}`, nil},
+		// unscoped or mixed with other conditions on the attribute only fetches the attribute.
+		// the other AND-ed conditions still filter, OR-ed they require all spans
+		{`{.foo = nil}`, []string{"spanid", "spanid2"}},
+		{`{.foo = nil && name = "world"}`, []string{"spanid2"}},
+		{`{event.foo = nil && name = "hello"}`, []string{"spanid"}},
+		{`{.foo = nil && resource.service.name = "service2"}`, []string{"spanid2"}},
+		{`{.foo = nil || name = "world"}`, []string{"spanid", "spanid2"}},
+		{`{span.foo = nil || span.foo = "def"}`, []string{"spanid", "spanid2"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			resp, err := b.Fetch(ctx, traceql.MustExtractFetchSpansRequestWithMetadata(tc.query), common.DefaultSearchOptions())
+			require.NoError(t, err)
+
+			var spanIDs []string
+			for {
+				spanSet, err := resp.Results.Next(ctx)
+				require.NoError(t, err)
+				if spanSet == nil {
+					break
+				}
+				for _, s := range spanSet.Spans {
+					spanIDs = append(spanIDs, string(s.ID()))
+				}
+			}
+			require.ElementsMatch(t, tc.spanIDs, spanIDs)
+		})
+	}
+}
+
+func TestBackendBlockSearchTraceQLSample(t *testing.T) {
+	numTraces := 250
+	traces := make([]*Trace, 0, numTraces)
+	wantTraceIDs := map[string]struct{}{}
+	for i := 0; i < numTraces; i++ {
+		id := test.ValidTraceID(nil)
+		traces = append(traces, traceToParquet(id, test.MakeTrace(1, id), nil))
+		if traceql.SampleTrace(id, 0.5) {
+			wantTraceIDs[string(id)] = struct{}{}
+		}
+	}
+
+	b := makeBackendBlockWithTraces(t, traces)
+	ctx := context.Background()
+
+	req := traceql.MustExtractFetchSpansRequestWithMetadata(`{}`)
+	req.Sample = 0.5
+
+	resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	actualTraceIDs := map[string]struct{}{}
+	for {
+		spanSet, err := resp.Results.Next(ctx)
+		require.NoError(t, err)
+		if spanSet == nil {
+			break
+		}
+		actualTraceIDs[string(spanSet.TraceID)] = struct{}{}
+	}
+
+	require.Less(t, len(wantTraceIDs), numTraces)
+	require.Equal(t, wantTraceIDs, actualTraceIDs)
+}
+
+func TestBackendBlockSearchTraceQLParent(t *testing.T) {
+	b := makeBackendBlockWithTraces(t, []*Trace{relationsTestTrace(test.ValidTraceID(nil), 0)})
+	ctx := context.Background()
+
+	// only the spans matching the conditions on the span itself are fetched, the parents are
+	// read from the relations
+	resp, err := b.Fetch(ctx, traceql.MustExtractFetchSpansRequestWithMetadata(`{ parent.name = "root" && span.foo = "bar" }`), common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	fetched := map[string]*span{}
+	for {
+		spanSet, err := resp.Results.Next(ctx)
+		require.NoError(t, err)
+		if spanSet == nil {
+			break
+		}
+		for _, s := range spanSet.Spans {
+			fetched[util.SpanIDToHexString(s.ID())] = s.(*span)
+		}
+	}
+	require.Len(t, fetched, 2)
+	parentName := traceql.NewIntrinsic(traceql.IntrinsicName)
+	parentName.Parent = true
+	require.Equal(t, traceql.NewStaticString("root"), fetched["0000000000000002"].attributes[parentName])
+	require.NotContains(t, fetched["0000000000000004"].attributes, parentName)
+
+	testCases := []struct {
+		query   string
+		spanIDs []string
+	}{
+		{`{ parent.name = "root" && span.foo = "bar" }`, []string{"0000000000000002"}},
+		{`{ parent.resource.service.name = "frontend" && span.foo = "bar" }`, []string{"0000000000000002"}},
+		{`{ parent.span.foo = "bar" }`, []string{"0000000000000004"}},
+		{`{ parent.name = "a" && parent.duration > 1s }`, []string{"0000000000000004"}},
+		{`{ parent.name = "root" || name = "c" }`, []string{"0000000000000002", "0000000000000003", "0000000000000004"}},
+		{`{ parent.name != "root" && span.foo = "bar" }`, []string{"0000000000000004"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			require.ElementsMatch(t, tc.spanIDs, searchSpanIDs(t, b, tc.query))
+		})
+	}
+}
+
+func TestBackendBlockSearchTraceQLStructural(t *testing.T) {
+	// the block doesn't store the nested set values so they are computed at query time
+	b := makeBackendBlockWithTraces(t, []*Trace{relationsTestTrace(test.ValidTraceID(nil), 0)})
+	ctx := context.Background()
+
+	// only the spans matching either side are fetched
+	resp, err := b.Fetch(ctx, traceql.MustExtractFetchSpansRequestWithMetadata(`{ name = "root" } > { span.foo = "bar" }`), common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	var fetched []string
+	for {
+		spanSet, err := resp.Results.Next(ctx)
+		require.NoError(t, err)
+		if spanSet == nil {
+			break
+		}
+		for _, s := range spanSet.Spans {
+			fetched = append(fetched, util.SpanIDToHexString(s.ID()))
+		}
+	}
+	require.ElementsMatch(t, []string{"0000000000000001", "0000000000000002", "0000000000000004"}, fetched)
+
+	testCases := []struct {
+		query   string
+		spanIDs []string
+	}{
+		{`{ name = "root" } > { span.foo = "bar" }`, []string{"0000000000000002"}},
+		{`{ name = "root" } >> { span.foo = "bar" }`, []string{"0000000000000002", "0000000000000004"}},
+		{`{ name = "root" } > { }`, []string{"0000000000000002", "0000000000000003"}},
+		{`{ name = "a" } ~ { }`, []string{"0000000000000003"}},
+		{`{ name = "c" } << { }`, []string{"0000000000000001", "0000000000000002"}},
+		{`{ name = "root" } !> { span.foo = "bar" }`, []string{"0000000000000004"}},
+		{`{ name = "a" } !>> { }`, []string{"0000000000000001", "0000000000000002", "0000000000000003"}},
+		{`{ name = "b" } !~ { span.foo = "bar" }`, []string{"0000000000000004"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			require.ElementsMatch(t, tc.spanIDs, searchSpanIDs(t, b, tc.query))
+		})
+	}
+}
+
+// searchSpanIDs runs the query through the engine and returns the IDs of the matching spans
+func searchSpanIDs(t testing.TB, b *backendBlock, query string) []string {
+	e := traceql.NewEngine()
+	resp, err := e.ExecuteSearch(context.Background(), &tempopb.SearchRequest{Query: query, SpansPerSpanSet: 100}, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	}))
+	require.NoError(t, err)
+
+	var spanIDs []string
+	for _, tr := range resp.Traces {
+		for _, s := range tr.SpanSet.Spans {
+			spanIDs = append(spanIDs, s.SpanID)
+		}
+	}
+	return spanIDs
+}
+
+// relationsTestTrace returns a trace with the spans
+//
+//	root (frontend)
+//	├── a (backend, foo=bar)
+//	│   └── c (backend, foo=bar)
+//	└── b (backend)
+//
+// and as many more children of b as requested
+func relationsTestTrace(id common.ID, extraSpans int) *Trace {
+	spanID := func(i int) []byte {
+		return []byte{0, 0, 0, 0, 0, 0, byte(i >> 8), byte(i)}
+	}
+	makeSpan := func(i, parent int, name string, attrs ...*v1_common.KeyValue) *v1.Span {
+		s := &v1.Span{
+			SpanId:            spanID(i),
+			Name:              name,
+			StartTimeUnixNano: uint64(i) * uint64(time.Second),
+			EndTimeUnixNano:   uint64(i+2) * uint64(time.Second),
+			Attributes:        attrs,
+		}
+		if parent > 0 {
+			s.ParentSpanId = spanID(parent)
+		}
+		return s
+	}
+	makeBatch := func(service string, spans ...*v1.Span) *v1.ResourceSpans {
+		return &v1.ResourceSpans{
+			Resource: &v1_resource.Resource{
+				Attributes: []*v1_common.KeyValue{
+					{Key: LabelServiceName, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: service}}},
+				},
+			},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: spans}},
+		}
+	}
+	foo := &v1_common.KeyValue{Key: "foo", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "bar"}}}
+
+	backendSpans := []*v1.Span{
+		makeSpan(2, 1, "a", foo),
+		makeSpan(3, 1, "b"),
+		makeSpan(4, 2, "c", foo),
+	}
+	for i := 0; i < extraSpans; i++ {
+		backendSpans = append(backendSpans, makeSpan(5+i, 3, "d"))
+	}
+
+	tr := &tempopb.Trace{
+		Batches: []*v1.ResourceSpans{
+			makeBatch("frontend", makeSpan(1, 0, "root")),
+			makeBatch("backend", backendSpans...),
+		},
+	}
+	return traceToParquet(id, tr, nil)
+}
+
 func makeReq(conditions ...traceql.Condition) traceql.FetchSpansRequest {
 	return traceql.FetchSpansRequest{
 		Conditions: conditions,
@@ -400,6 +696,7 @@ func fullyPopulatedTestTrace(id common.ID) *Trace {
 				},
 				ScopeSpans: []ScopeSpans{
 					{
+						Scope: InstrumentationScope{Name: "scope-1", Version: "version-1"},
 						Spans: []Span{
 							{
 								SpanID:                 []byte("spanid"),
@@ -532,3 +829,30 @@ func BenchmarkBackendBlockTraceQL(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkBackendBlockTraceQLRelations(b *testing.B) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"span", "{ span.foo = `bar` }"},
+		{"parent", "{ parent.name = `a` && span.foo = `bar` }"},
+		{"parentResource", "{ parent.resource.service.name = `frontend` && span.foo = `bar` }"},
+		{"structural", "{ name = `a` } > { span.foo = `bar` }"},
+	}
+
+	traces := make([]*Trace, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		traces = append(traces, relationsTestTrace(test.ValidTraceID(nil), 100))
+	}
+	block := makeBackendBlockWithTraces(b, traces)
+
+	for _, tc := range testCases {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				searchSpanIDs(b, block, tc.query)
+			}
+		})
+	}
+}
@@ -619,6 +619,25 @@ func (b *walBlock) SearchTagValuesV2(ctx context.Context, tag traceql.Attribute,
 	return nil
 }
 
+func (b *walBlock) SearchTagValueCounts(ctx context.Context, tag traceql.Attribute, cb common.TagValueCountCallback, _ common.SearchOptions) error {
+	for i, blockFlush := range b.readFlushes() {
+		file, err := blockFlush.file()
+		if err != nil {
+			return fmt.Errorf("error opening file %s: %w", blockFlush.path, err)
+		}
+
+		defer file.Close()
+		pf := file.parquetFile
+
+		err = searchTagValueCounts(ctx, tag, cb, pf)
+		if err != nil {
+			return fmt.Errorf("error searching block [%s %d]: %w", b.meta.BlockID.String(), i, err)
+		}
+	}
+
+	return nil
+}
+
 func (b *walBlock) Fetch(ctx context.Context, req traceql.FetchSpansRequest, opts common.SearchOptions) (traceql.FetchSpansResponse, error) {
 	// todo: this same method is called in backendBlock.Fetch. is there anyway to share this?
 	err := checkConditions(req.Conditions)
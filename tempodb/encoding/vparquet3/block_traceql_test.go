@@ -0,0 +1,926 @@
+package vparquet3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestOne(t *testing.T) {
+	wantTr := fullyPopulatedTestTrace(nil)
+	b := makeBackendBlockWithTraces(t, []*Trace{wantTr})
+	ctx := context.Background()
+	req := traceql.MustExtractFetchSpansRequestWithMetadata(`{ span.foo = "bar" || duration > 1s }`)
+
+	req.StartTimeUnixNanos = uint64(1000 * time.Second)
+	req.EndTimeUnixNanos = uint64(1001 * time.Second)
+
+	resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+	require.NoError(t, err, "search request:", req)
+
+	spanSet, err := resp.Results.Next(ctx)
+	require.NoError(t, err, "search request:", req)
+
+	fmt.Println("-----------")
+	fmt.Println(resp.Results.(*spansetIterator).iter)
+	fmt.Println("-----------")
+	fmt.Println(spanSet)
+}
+
+func TestConditionColumns(t *testing.T) {
+	var (
+		durationGt1s = traceql.Condition{Attribute: traceql.NewIntrinsic(traceql.IntrinsicDuration), Op: traceql.OpGreater, Operands: traceql.Operands{traceql.NewStaticDuration(time.Second)}}
+		rootService  = traceql.Condition{Attribute: traceql.NewIntrinsic(traceql.IntrinsicTraceRootService), Op: traceql.OpEqual, Operands: traceql.Operands{traceql.NewStaticString("foo")}}
+	)
+
+	testCases := []struct {
+		cond          traceql.Condition
+		allConditions bool
+		columns       []common.ExplainedColumn
+	}{
+		{durationGt1s, false, []common.ExplainedColumn{{Column: columnPathSpanDuration, PushedDown: true}}},
+		{rootService, true, []common.ExplainedColumn{{Column: columnPathRootServiceName, PushedDown: true}}},
+		// trace level predicates are only pushed down if all conditions must match
+		{rootService, false, []common.ExplainedColumn{{Column: columnPathRootServiceName}}},
+		{traceql.Condition{Attribute: traceql.NewScopedAttribute(traceql.AttributeScopeSpan, false, LabelHTTPStatusCode), Op: traceql.OpEqual, Operands: traceql.Operands{traceql.NewStaticInt(500)}}, false, []common.ExplainedColumn{{Column: columnPathSpanHTTPStatusCode, PushedDown: true}}},
+		// the type doesn't match the dedicated column
+		{traceql.Condition{Attribute: traceql.NewScopedAttribute(traceql.AttributeScopeSpan, false, LabelHTTPStatusCode), Op: traceql.OpEqual, Operands: traceql.Operands{traceql.NewStaticString("500")}}, false, []common.ExplainedColumn{
+			{Column: columnPathSpanAttrKey, PushedDown: true},
+			{Column: columnPathSpanAttrString, PushedDown: true},
+		}},
+		// arrays are never stored in the dedicated columns
+		{traceql.Condition{Attribute: traceql.NewScopedAttribute(traceql.AttributeScopeSpan, false, LabelHTTPMethod), Op: traceql.OpContains, Operands: traceql.Operands{traceql.NewStaticString("GET")}}, false, []common.ExplainedColumn{
+			{Column: columnPathSpanAttrKey, PushedDown: true},
+			{Column: columnPathSpanAttrStringArray, PushedDown: true},
+		}},
+		// unscoped attributes are looked up at the span and resource level
+		{traceql.Condition{Attribute: traceql.NewAttribute(LabelServiceName), Op: traceql.OpEqual, Operands: traceql.Operands{traceql.NewStaticString("foo")}}, false, []common.ExplainedColumn{
+			{Column: columnPathResourceServiceName, PushedDown: true},
+			{Column: columnPathSpanAttrKey, PushedDown: true},
+			{Column: columnPathSpanAttrString, PushedDown: true},
+		}},
+		// event names are only filtered if all conditions must match
+		{traceql.Condition{Attribute: traceql.NewScopedAttribute(traceql.AttributeScopeEvent, false, LabelName), Op: traceql.OpEqual, Operands: traceql.Operands{traceql.NewStaticString("foo")}}, true, []common.ExplainedColumn{
+			{Column: columnPathSpanEventName, PushedDown: true},
+		}},
+		{traceql.Condition{Attribute: traceql.NewScopedAttribute(traceql.AttributeScopeEvent, false, "exception.type")}, false, []common.ExplainedColumn{
+			{Column: columnPathSpanEventAttrKey, PushedDown: true},
+			{Column: columnPathSpanEventAttrValue},
+			{Column: columnPathSpanEventName},
+		}},
+		// span relationships are read from the IDs, parent IDs and stored nested set values of all spans
+		{traceql.Condition{Attribute: traceql.NewIntrinsic(traceql.IntrinsicStructuralChild)}, false, []common.ExplainedColumn{
+			{Column: columnPathSpanNestedSetLeft},
+			{Column: columnPathSpanNestedSetRight},
+			{Column: columnPathSpanParentID},
+			{Column: columnPathSpanParentSpanID},
+			{Column: columnPathSpanID},
+		}},
+		{traceql.Condition{Attribute: traceql.NewScopedAttribute(traceql.AttributeScopeLink, false, "foo")}, false, []common.ExplainedColumn{{Column: columnPathSpanLinks}}},
+		{traceql.Condition{Attribute: traceql.NewScopedAttribute(traceql.AttributeScopeInstrumentation, false, LabelVersion)}, false, []common.ExplainedColumn{{Column: columnPathScopeVersion}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cond.String(), func(t *testing.T) {
+			columns, err := ConditionColumns(tc.cond, tc.allConditions)
+			require.NoError(t, err)
+			require.Equal(t, tc.columns, columns)
+		})
+	}
+
+	_, err := ConditionColumns(traceql.Condition{Attribute: traceql.NewIntrinsic(traceql.IntrinsicDuration), Op: traceql.OpGreater}, false)
+	require.Error(t, err)
+}
+
+func TestBackendBlockSearchTraceQL(t *testing.T) {
+	numTraces := 250
+	traces := make([]*Trace, 0, numTraces)
+	wantTraceIdx := rand.Intn(numTraces)
+	wantTraceID := test.ValidTraceID(nil)
+	for i := 0; i < numTraces; i++ {
+		if i == wantTraceIdx {
+			traces = append(traces, fullyPopulatedTestTrace(wantTraceID))
+			continue
+		}
+
+		id := test.ValidTraceID(nil)
+		tr := traceToParquet(id, test.MakeTrace(1, id), nil)
+		traces = append(traces, tr)
+	}
+
+	b := makeBackendBlockWithTraces(t, traces)
+	ctx := context.Background()
+
+	searchesThatMatch := []traceql.FetchSpansRequest{
+		{}, // Empty request
+		{
+			// Time range inside trace
+			StartTimeUnixNanos: uint64(1100 * time.Second),
+			EndTimeUnixNanos:   uint64(1200 * time.Second),
+		},
+		{
+			// Time range overlap start
+			StartTimeUnixNanos: uint64(900 * time.Second),
+			EndTimeUnixNanos:   uint64(1100 * time.Second),
+		},
+		{
+			// Time range overlap end
+			StartTimeUnixNanos: uint64(1900 * time.Second),
+			EndTimeUnixNanos:   uint64(2100 * time.Second),
+		},
+		// Intrinsics
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelName + ` = "hello"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` =  100s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` >  99s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` >= 100s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` <  101s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` <= 100s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` <= 100s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = error}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = 2}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = client }`),
+		// Trace-level intrinsics
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration = 100ms}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration > 99ms}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootName = "RootSpan"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName = "RootService"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName =~ "Root.*"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration < 1s && ` + LabelName + ` = "hello"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName = "notRootService" || ` + LabelName + ` = "hello"}`),
+		// Events and links
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.name = "e2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.name =~ "e.*" && ` + LabelName + ` = "hello"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.traceID = "1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.spanID = "0000000000000002" && link.key = "value"}`),
+		// Instrumentation scope
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-1" && instrumentation.version < "version-2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.version = "version-1" && ` + LabelName + ` = "hello"}`),
+		// Arrays
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.tags contains "beta"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.partitions contains 3 && resource.flags contains false}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.weights contains 0.5 || span.tags contains "gamma"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.tags[1] = "beta"}`),
+		// Resource well-known attributes
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "spanservicename"}`), // Overridden at span
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelCluster + ` = "cluster"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelNamespace + ` = "namespace"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelPod + ` = "pod"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelContainer + ` = "container"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelK8sNamespaceName + ` = "k8snamespace"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelK8sClusterName + ` = "k8scluster"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelK8sPodName + ` = "k8spod"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelK8sContainerName + ` = "k8scontainer"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` = "myservice"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelCluster + ` = "cluster"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelNamespace + ` = "namespace"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelPod + ` = "pod"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelContainer + ` = "container"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelK8sNamespaceName + ` = "k8snamespace"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelK8sClusterName + ` = "k8scluster"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelK8sPodName + ` = "k8spod"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelK8sContainerName + ` = "k8scontainer"}`),
+		// Comparing strings
+
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` > "myservic"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` >= "myservic"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` < "myservice1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` <= "myservice1"}`),
+		// Span well-known attributes
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = 500}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPMethod + ` = "get"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPUrl + ` = "url/hello/world"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.` + LabelHTTPStatusCode + ` = 500}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.` + LabelHTTPMethod + ` = "get"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.` + LabelHTTPUrl + ` = "url/hello/world"}`),
+		// Basic data types and operations
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float = 456.78}`),      // Float ==
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float != 456.79}`),     // Float !=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float > 456.7}`),       // Float >
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float >= 456.78}`),     // Float >=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float < 456.781}`),     // Float <
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bool = false}`),        // Bool ==
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bool != true}`),        // Bool !=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar = 123}`),           // Int ==
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar != 124}`),          // Int !=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar > 122}`),           // Int >
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar >= 123}`),          // Int >=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar < 124}`),           // Int <
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar <= 123}`),          // Int <=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "def"}`),         // String ==
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo != "deg"}`),        // String !=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "d.*"}`),        // String Regex
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo !~ "x.*"}`),        // String Not Regex
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.foo = "abc"}`), // Resource-level only
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.foo = "def"}`),     // Span-level only
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo}`),                 // Projection only
+		makeReq(
+			// Matches either condition
+			parse(t, `{.foo = "baz"}`),
+			parse(t, `{.`+LabelHTTPStatusCode+` > 100}`),
+		),
+		makeReq(
+			// Same as above but reversed order
+			parse(t, `{.`+LabelHTTPStatusCode+` > 100}`),
+			parse(t, `{.foo = "baz"}`),
+		),
+		makeReq(
+			// Same attribute with mixed types
+			parse(t, `{.foo > 100}`),
+			parse(t, `{.foo = "def"}`),
+		),
+		makeReq(
+			// Multiple conditions on same well-known attribute, matches either
+			parse(t, `{.`+LabelHTTPStatusCode+` = 500}`),
+			parse(t, `{.`+LabelHTTPStatusCode+` > 500}`),
+		),
+		makeReq(
+			// Mix of duration with other conditions
+			parse(t, `{`+LabelName+` = "hello"}`),   // Match
+			parse(t, `{`+LabelDuration+` < 100s }`), // No match
+		),
+
+		// Edge cases
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.name = "Bob"}`),                                 // Almost conflicts with intrinsic but still works
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` = 123}`),       // service.name doesn't match type of dedicated column
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "spanservicename"}`), // service.name present on span
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = "500ouch"}`),      // http.status_code doesn't match type of dedicated column
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "def"}`),
+		{
+			// Range at unscoped
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{.`+LabelHTTPStatusCode+` >= 500}`),
+				parse(t, `{.`+LabelHTTPStatusCode+` <= 600}`),
+			},
+		},
+		{
+			// Range at span scope
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{span.`+LabelHTTPStatusCode+` >= 500}`),
+				parse(t, `{span.`+LabelHTTPStatusCode+` <= 600}`),
+			},
+		},
+		{
+			// Range at resource scope
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{resource.`+LabelServiceName+` >= 122}`),
+				parse(t, `{resource.`+LabelServiceName+` <= 124}`),
+			},
+		},
+	}
+
+	for _, req := range searchesThatMatch {
+		if req.SecondPass == nil {
+			req.SecondPass = func(s *traceql.Spanset) ([]*traceql.Spanset, error) { return []*traceql.Spanset{s}, nil }
+			req.SecondPassConditions = traceql.SearchMetaConditions()
+		}
+
+		resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+		require.NoError(t, err, "search request:%v", req)
+
+		found := false
+		for {
+			spanSet, err := resp.Results.Next(ctx)
+			require.NoError(t, err, "search request:%v", req)
+			if spanSet == nil {
+				break
+			}
+			found = bytes.Equal(spanSet.TraceID, wantTraceID)
+			if found {
+				break
+			}
+		}
+		require.True(t, found, "search request:%v", req)
+	}
+
+	searchesThatDontMatch := []traceql.FetchSpansRequest{
+		// TODO - Should the below query return data or not?  It does match the resource
+		// makeReq(parse(t, `{.foo = "abc"}`)),                           // This should not return results because the span has overridden this attribute to "def".
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "xyz.*"}`), // Regex IN
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.name = "e3"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.key = "other"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.tags contains "gamma"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.partitions contains 2 && resource.flags contains true}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{instrumentation.name = "scope-1" && instrumentation.version > "version-1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo !~ ".*"}`),                                        // String Not Regex
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.bool = true}`),                                    // Bool not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` >  100s}`),                       // Intrinsic: duration
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = ok}`),                            // Intrinsic: status
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelName + ` = "nothello"}`),                      // Intrinsic: name
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = producer }`),                       // Intrinsic: kind
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "notmyservice"}`),          // Well-known attribute: service.name not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = 200}`),                  // Well-known attribute: http.status_code not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` > 600}`),                  // Well-known attribute: http.status_code not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "xyz" || .` + LabelHTTPStatusCode + " = 1000}"), // Matches neither condition
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{traceDuration > 100ms}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootName = "NotRootSpan"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{rootServiceName = "NotRootService"}`),
+		{
+			// Trace-level intrinsic matches but span-level doesn't
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{rootServiceName = "RootService"}`), // match
+				parse(t, `{`+LabelName+` = "nothello"}`),      // no match
+			},
+		},
+		{
+			// Time range after trace
+			StartTimeUnixNanos: uint64(3000 * time.Second),
+			EndTimeUnixNanos:   uint64(4000 * time.Second),
+		},
+		{
+			// Time range before trace
+			StartTimeUnixNanos: uint64(600 * time.Second),
+			EndTimeUnixNanos:   uint64(700 * time.Second),
+		},
+		{
+			// Matches some conditions but not all
+			// Mix of span-level columns
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{span.foo = "baz"}`),                   // no match
+				parse(t, `{span.`+LabelHTTPStatusCode+` > 100}`), // match
+				parse(t, `{name = "hello"}`),                     // match
+			},
+		},
+		{
+			// Matches some conditions but not all
+			// Only span generic attr lookups
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{span.foo = "baz"}`), // no match
+				parse(t, `{span.bar = 123}`),   // match
+			},
+		},
+		{
+			// Matches some conditions but not all
+			// Mix of span and resource columns
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{resource.cluster = "cluster"}`),     // match
+				parse(t, `{resource.namespace = "namespace"}`), // match
+				parse(t, `{span.foo = "baz"}`),                 // no match
+			},
+		},
+		{
+			// Matches some conditions but not all
+			// Mix of resource columns
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{resource.cluster = "notcluster"}`),  // no match
+				parse(t, `{resource.namespace = "namespace"}`), // match
+				parse(t, `{resource.foo = "abc"}`),             // match
+			},
+		},
+		{
+			// Matches some conditions but not all
+			// Only resource generic attr lookups
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{resource.foo = "abc"}`), // match
+				parse(t, `{resource.bar = 123}`),   // no match
+			},
+		},
+		{
+			// Mix of duration with other conditions
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{`+LabelName+` = "nothello"}`), // No match
+				parse(t, `{`+LabelDuration+` = 100s }`),  // Match
+			},
+		},
+	}
+
+	for _, req := range searchesThatDontMatch {
+		if req.SecondPass == nil {
+			req.SecondPass = func(s *traceql.Spanset) ([]*traceql.Spanset, error) { return []*traceql.Spanset{s}, nil }
+			req.SecondPassConditions = traceql.SearchMetaConditions()
+		}
+
+		resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+		require.NoError(t, err, "search request:", req)
+
+		for {
+			spanSet, err := resp.Results.Next(ctx)
+			require.NoError(t, err, "search request:", req)
+			if spanSet == nil {
+				break
+			}
+			require.NotEqual(t, wantTraceID, spanSet.TraceID, "search request:", req)
+		}
+	}
+}
+
+func TestBackendBlockSearchTraceQLMissing(t *testing.T) {
+	wantTraceID := test.ValidTraceID(nil)
+	b := makeBackendBlockWithTraces(t, []*Trace{fullyPopulatedTestTrace(wantTraceID)})
+	ctx := context.Background()
+
+	testCases := []struct {
+		query   string
+		spanIDs []string
+	}{
+		// spans and batches with the attribute are dropped by the collectors
+		{`{span.foo = nil}`, []string{"spanid2"}},
+		{`{resource.foo = nil}`, []string{"spanid2"}},
+		{`{span.foo = nil || span.bar = 123}`, []string{"spanid", "spanid2"}},
+		{`{resource.` + LabelServiceName + ` = nil}`, nil},
+		// unscoped or mixed with other conditions on the attribute only fetches the attribute.
+		// the other AND-ed conditions still filter, OR-ed they require all spans
+		{`{.foo = nil}`, []string{"spanid", "spanid2"}},
+		{`{.foo = nil && name = "world"}`, []string{"spanid2"}},
+		{`{event.foo = nil && name = "hello"}`, []string{"spanid"}},
+		{`{.foo = nil && resource.service.name = "service2"}`, []string{"spanid2"}},
+		{`{.foo = nil || name = "world"}`, []string{"spanid", "spanid2"}},
+		{`{span.foo = nil || span.foo = "def"}`, []string{"spanid", "spanid2"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			resp, err := b.Fetch(ctx, traceql.MustExtractFetchSpansRequestWithMetadata(tc.query), common.DefaultSearchOptions())
+			require.NoError(t, err)
+
+			var spanIDs []string
+			for {
+				spanSet, err := resp.Results.Next(ctx)
+				require.NoError(t, err)
+				if spanSet == nil {
+					break
+				}
+				for _, s := range spanSet.Spans {
+					spanIDs = append(spanIDs, string(s.ID()))
+				}
+			}
+			require.ElementsMatch(t, tc.spanIDs, spanIDs)
+		})
+	}
+}
+
+func TestBackendBlockSearchTraceQLSample(t *testing.T) {
+	numTraces := 250
+	traces := make([]*Trace, 0, numTraces)
+	wantTraceIDs := map[string]struct{}{}
+	for i := 0; i < numTraces; i++ {
+		id := test.ValidTraceID(nil)
+		traces = append(traces, traceToParquet(id, test.MakeTrace(1, id), nil))
+		if traceql.SampleTrace(id, 0.5) {
+			wantTraceIDs[string(id)] = struct{}{}
+		}
+	}
+
+	b := makeBackendBlockWithTraces(t, traces)
+	ctx := context.Background()
+
+	req := traceql.MustExtractFetchSpansRequestWithMetadata(`{}`)
+	req.Sample = 0.5
+
+	resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	actualTraceIDs := map[string]struct{}{}
+	for {
+		spanSet, err := resp.Results.Next(ctx)
+		require.NoError(t, err)
+		if spanSet == nil {
+			break
+		}
+		actualTraceIDs[string(spanSet.TraceID)] = struct{}{}
+	}
+
+	require.Less(t, len(wantTraceIDs), numTraces)
+	require.Equal(t, wantTraceIDs, actualTraceIDs)
+}
+
+func TestBackendBlockSearchTraceQLParent(t *testing.T) {
+	b := makeBackendBlockWithTraces(t, []*Trace{relationsTestTrace(test.ValidTraceID(nil), 0)})
+	ctx := context.Background()
+
+	// only the spans matching the conditions on the span itself are fetched, the parents are
+	// read from the relations
+	resp, err := b.Fetch(ctx, traceql.MustExtractFetchSpansRequestWithMetadata(`{ parent.name = "root" && span.foo = "bar" }`), common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	fetched := map[string]*span{}
+	for {
+		spanSet, err := resp.Results.Next(ctx)
+		require.NoError(t, err)
+		if spanSet == nil {
+			break
+		}
+		for _, s := range spanSet.Spans {
+			fetched[util.SpanIDToHexString(s.ID())] = s.(*span)
+		}
+	}
+	require.Len(t, fetched, 2)
+	parentName := traceql.NewIntrinsic(traceql.IntrinsicName)
+	parentName.Parent = true
+	require.Equal(t, traceql.NewStaticString("root"), fetched["0000000000000002"].attributes[parentName])
+	require.NotContains(t, fetched["0000000000000004"].attributes, parentName)
+
+	testCases := []struct {
+		query   string
+		spanIDs []string
+	}{
+		{`{ parent.name = "root" && span.foo = "bar" }`, []string{"0000000000000002"}},
+		{`{ parent.resource.service.name = "frontend" && span.foo = "bar" }`, []string{"0000000000000002"}},
+		{`{ parent.span.foo = "bar" }`, []string{"0000000000000004"}},
+		{`{ parent.name = "a" && parent.duration > 1s }`, []string{"0000000000000004"}},
+		{`{ parent.name = "root" || name = "c" }`, []string{"0000000000000002", "0000000000000003", "0000000000000004"}},
+		{`{ parent.name != "root" && span.foo = "bar" }`, []string{"0000000000000004"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			require.ElementsMatch(t, tc.spanIDs, searchSpanIDs(t, b, tc.query))
+		})
+	}
+}
+
+func TestBackendBlockSearchTraceQLStructural(t *testing.T) {
+	// blocks written before the nested set values were stored compute them at query time
+	unset := relationsTestTrace(test.ValidTraceID(nil), 0)
+	for ib := range unset.ResourceSpans {
+		for is := range unset.ResourceSpans[ib].ScopeSpans {
+			for i := range unset.ResourceSpans[ib].ScopeSpans[is].Spans {
+				s := &unset.ResourceSpans[ib].ScopeSpans[is].Spans[i]
+				s.ParentID, s.NestedSetLeft, s.NestedSetRight = 0, 0, 0
+			}
+		}
+	}
+
+	blocks := map[string]*backendBlock{
+		"stored":   makeBackendBlockWithTraces(t, []*Trace{relationsTestTrace(test.ValidTraceID(nil), 0)}),
+		"computed": makeBackendBlockWithTraces(t, []*Trace{unset}),
+	}
+
+	testCases := []struct {
+		query   string
+		spanIDs []string
+	}{
+		{`{ name = "root" } > { span.foo = "bar" }`, []string{"0000000000000002"}},
+		{`{ name = "root" } >> { span.foo = "bar" }`, []string{"0000000000000002", "0000000000000004"}},
+		{`{ name = "root" } > { }`, []string{"0000000000000002", "0000000000000003"}},
+		{`{ name = "a" } ~ { }`, []string{"0000000000000003"}},
+		{`{ name = "c" } << { }`, []string{"0000000000000001", "0000000000000002"}},
+		{`{ name = "root" } !> { span.foo = "bar" }`, []string{"0000000000000004"}},
+		{`{ name = "a" } !>> { }`, []string{"0000000000000001", "0000000000000002", "0000000000000003"}},
+		{`{ name = "b" } !~ { span.foo = "bar" }`, []string{"0000000000000004"}},
+	}
+
+	for name, b := range blocks {
+		// only the spans matching either side are fetched
+		resp, err := b.Fetch(context.Background(), traceql.MustExtractFetchSpansRequestWithMetadata(`{ name = "root" } > { span.foo = "bar" }`), common.DefaultSearchOptions())
+		require.NoError(t, err)
+
+		var fetched []string
+		for {
+			spanSet, err := resp.Results.Next(context.Background())
+			require.NoError(t, err)
+			if spanSet == nil {
+				break
+			}
+			for _, s := range spanSet.Spans {
+				fetched = append(fetched, util.SpanIDToHexString(s.ID()))
+			}
+		}
+		require.ElementsMatch(t, []string{"0000000000000001", "0000000000000002", "0000000000000004"}, fetched, name)
+
+		for _, tc := range testCases {
+			t.Run(name+"/"+tc.query, func(t *testing.T) {
+				require.ElementsMatch(t, tc.spanIDs, searchSpanIDs(t, b, tc.query))
+			})
+		}
+	}
+}
+
+// searchSpanIDs runs the query through the engine and returns the IDs of the matching spans
+func searchSpanIDs(t testing.TB, b *backendBlock, query string) []string {
+	e := traceql.NewEngine()
+	resp, err := e.ExecuteSearch(context.Background(), &tempopb.SearchRequest{Query: query, SpansPerSpanSet: 100}, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	}))
+	require.NoError(t, err)
+
+	var spanIDs []string
+	for _, tr := range resp.Traces {
+		for _, s := range tr.SpanSet.Spans {
+			spanIDs = append(spanIDs, s.SpanID)
+		}
+	}
+	return spanIDs
+}
+
+// relationsTestTrace returns a trace with the spans
+//
+//	root (frontend)
+//	├── a (backend, foo=bar)
+//	│   └── c (backend, foo=bar)
+//	└── b (backend)
+//
+// and as many more children of b as requested
+func relationsTestTrace(id common.ID, extraSpans int) *Trace {
+	spanID := func(i int) []byte {
+		return []byte{0, 0, 0, 0, 0, 0, byte(i >> 8), byte(i)}
+	}
+	makeSpan := func(i, parent int, name string, attrs ...*v1_common.KeyValue) *v1.Span {
+		s := &v1.Span{
+			SpanId:            spanID(i),
+			Name:              name,
+			StartTimeUnixNano: uint64(i) * uint64(time.Second),
+			EndTimeUnixNano:   uint64(i+2) * uint64(time.Second),
+			Attributes:        attrs,
+		}
+		if parent > 0 {
+			s.ParentSpanId = spanID(parent)
+		}
+		return s
+	}
+	makeBatch := func(service string, spans ...*v1.Span) *v1.ResourceSpans {
+		return &v1.ResourceSpans{
+			Resource: &v1_resource.Resource{
+				Attributes: []*v1_common.KeyValue{
+					{Key: LabelServiceName, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: service}}},
+				},
+			},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: spans}},
+		}
+	}
+	foo := &v1_common.KeyValue{Key: "foo", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "bar"}}}
+
+	backendSpans := []*v1.Span{
+		makeSpan(2, 1, "a", foo),
+		makeSpan(3, 1, "b"),
+		makeSpan(4, 2, "c", foo),
+	}
+	for i := 0; i < extraSpans; i++ {
+		backendSpans = append(backendSpans, makeSpan(5+i, 3, "d"))
+	}
+
+	tr := &tempopb.Trace{
+		Batches: []*v1.ResourceSpans{
+			makeBatch("frontend", makeSpan(1, 0, "root")),
+			makeBatch("backend", backendSpans...),
+		},
+	}
+	return traceToParquet(id, tr, nil)
+}
+
+func makeReq(conditions ...traceql.Condition) traceql.FetchSpansRequest {
+	return traceql.FetchSpansRequest{
+		Conditions: conditions,
+		SecondPass: func(s *traceql.Spanset) ([]*traceql.Spanset, error) {
+			return []*traceql.Spanset{s}, nil
+		},
+		SecondPassConditions: traceql.SearchMetaConditions(),
+	}
+}
+
+func parse(t *testing.T, q string) traceql.Condition {
+
+	req, err := traceql.ExtractFetchSpansRequest(q)
+	require.NoError(t, err, "query:", q)
+
+	return req.Conditions[0]
+}
+
+func fullyPopulatedTestTrace(id common.ID) *Trace {
+	// Helper functions to make pointers
+	strPtr := func(s string) *string { return &s }
+	intPtr := func(i int64) *int64 { return &i }
+	fltPtr := func(f float64) *float64 { return &f }
+	boolPtr := func(b bool) *bool { return &b }
+
+	links := tempopb.LinkSlice{
+		Links: []*v1.Span_Link{
+			{
+				TraceId:                []byte{0x01},
+				SpanId:                 []byte{0x02},
+				TraceState:             "state",
+				DroppedAttributesCount: 3,
+				Attributes: []*v1_common.KeyValue{
+					{
+						Key:   "key",
+						Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "value"}},
+					},
+				},
+			},
+		},
+	}
+	linkBytes := make([]byte, links.Size())
+	_, err := links.MarshalTo(linkBytes)
+	if err != nil {
+		panic("failed to marshal links")
+	}
+
+	return &Trace{
+		TraceID:           test.ValidTraceID(id),
+		StartTimeUnixNano: uint64(1000 * time.Second),
+		EndTimeUnixNano:   uint64(2000 * time.Second),
+		DurationNano:      uint64((100 * time.Millisecond).Nanoseconds()),
+		RootServiceName:   "RootService",
+		RootSpanName:      "RootSpan",
+		ResourceSpans: []ResourceSpans{
+			{
+				Resource: Resource{
+					ServiceName:      "myservice",
+					Cluster:          strPtr("cluster"),
+					Namespace:        strPtr("namespace"),
+					Pod:              strPtr("pod"),
+					Container:        strPtr("container"),
+					K8sClusterName:   strPtr("k8scluster"),
+					K8sNamespaceName: strPtr("k8snamespace"),
+					K8sPodName:       strPtr("k8spod"),
+					K8sContainerName: strPtr("k8scontainer"),
+					Attrs: []Attribute{
+						{Key: "foo", Value: strPtr("abc")},
+						{Key: LabelServiceName, ValueInt: intPtr(123)}, // Different type than dedicated column
+						{Key: "flags", ValueBoolArray: []bool{true, false}},
+					},
+				},
+				ScopeSpans: []ScopeSpans{
+					{
+						Scope: InstrumentationScope{Name: "scope-1", Version: "version-1"},
+						Spans: []Span{
+							{
+								SpanID:                 []byte("spanid"),
+								Name:                   "hello",
+								StartTimeUnixNano:      uint64(100 * time.Second),
+								DurationNano:           uint64(100 * time.Second),
+								HttpMethod:             strPtr("get"),
+								HttpUrl:                strPtr("url/hello/world"),
+								HttpStatusCode:         intPtr(500),
+								ParentSpanID:           []byte{},
+								StatusCode:             int(v1.Status_STATUS_CODE_ERROR),
+								StatusMessage:          v1.Status_STATUS_CODE_ERROR.String(),
+								TraceState:             "tracestate",
+								Kind:                   int(v1.Span_SPAN_KIND_CLIENT),
+								DroppedAttributesCount: 42,
+								DroppedEventsCount:     43,
+								Attrs: []Attribute{
+									{Key: "foo", Value: strPtr("def")},
+									{Key: "bar", ValueInt: intPtr(123)},
+									{Key: "float", ValueDouble: fltPtr(456.78)},
+									{Key: "bool", ValueBool: boolPtr(false)},
+									{Key: "tags", ValueStringArray: []string{"alpha", "beta"}},
+									{Key: "partitions", ValueIntArray: []int64{1, 3}},
+									{Key: "weights", ValueDoubleArray: []float64{0.5}},
+
+									// Edge-cases
+									{Key: LabelName, Value: strPtr("Bob")},                    // Conflicts with intrinsic but still looked up by .name
+									{Key: LabelServiceName, Value: strPtr("spanservicename")}, // Overrides resource-level dedicated column
+									{Key: LabelHTTPStatusCode, Value: strPtr("500ouch")},      // Different type than dedicated column
+								},
+								Events: []Event{
+									{TimeUnixNano: 1, Name: "e1", Attrs: []EventAttribute{
+										{Key: "foo", Value: []byte("fake proto encoded data. i hope this never matters")},
+										{Key: "bar", Value: []byte("fake proto encoded data. i hope this never matters")},
+									}},
+									{TimeUnixNano: 2, Name: "e2", Attrs: []EventAttribute{}},
+								},
+								Links: linkBytes,
+							},
+						},
+					},
+				},
+			},
+			{
+				Resource: Resource{
+					ServiceName: "service2",
+				},
+				ScopeSpans: []ScopeSpans{
+					{
+						Spans: []Span{
+							{
+								SpanID: []byte("spanid2"),
+								Name:   "world",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func BenchmarkBackendBlockTraceQL(b *testing.B) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		// span
+		{"spanAttNameNoMatch", "{ span.foo = `bar` }"},
+		{"spanAttValNoMatch", "{ span.bloom = `bar` }"},
+		{"spanAttValMatch", "{ span.bloom > 0 }"},
+		{"spanAttIntrinsicNoMatch", "{ name = `asdfasdf` }"},
+		{"spanAttIntrinsicMatch", "{ name = `gcs.ReadRange` }"},
+		{"spanAttIntrinsicRegexNoMatch", "{ name =~ `asdfasdf` }"},
+		{"spanAttIntrinsicRegexMatch", "{ name =~ `gcs.ReadRange` }"},
+
+		// resource
+		{"resourceAttNameNoMatch", "{ resource.foo = `bar` }"},
+		{"resourceAttValNoMatch", "{ resource.module.path = `bar` }"},
+		{"resourceAttValMatch", "{ resource.os.type = `linux` }"},
+		{"resourceAttIntrinsicNoMatch", "{ resource.service.name = `a` }"},
+		{"resourceAttIntrinsicMatch", "{ resource.service.name = `tempo-query-frontend` }"},
+
+		// mixed
+		{"mixedNameNoMatch", "{ .foo = `bar` }"},
+		{"mixedValNoMatch", "{ .bloom = `bar` }"},
+		{"mixedValMixedMatchAnd", "{ resource.foo = `bar` && name = `gcs.ReadRange` }"},
+		{"mixedValMixedMatchOr", "{ resource.foo = `bar` || name = `gcs.ReadRange` }"},
+		{"mixedValBothMatch", "{ resource.service.name = `query-frontend` && name = `gcs.ReadRange` }"},
+	}
+
+	ctx := context.TODO()
+	tenantID := "1"
+	blockID := uuid.MustParse("000d37d0-1e66-4f4e-bbd4-f85c1deb6e5e")
+
+	r, _, _, err := local.New(&local.Config{
+		Path: path.Join("/home/joe/testblock/"),
+	})
+	require.NoError(b, err)
+
+	rr := backend.NewReader(r)
+	meta, err := rr.BlockMeta(ctx, blockID, tenantID)
+	require.NoError(b, err)
+
+	opts := common.DefaultSearchOptions()
+	opts.StartPage = 10
+	opts.TotalPages = 10
+
+	block := newBackendBlock(meta, rr)
+	_, _, err = block.openForSearch(ctx, opts)
+	require.NoError(b, err)
+
+	for _, tc := range testCases {
+
+		b.Run(tc.name, func(b *testing.B) {
+			b.ResetTimer()
+			bytesRead := 0
+
+			for i := 0; i < b.N; i++ {
+				e := traceql.NewEngine()
+
+				resp, err := e.ExecuteSearch(ctx, &tempopb.SearchRequest{Query: tc.query}, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+					return block.Fetch(ctx, req, opts)
+				}))
+				require.NoError(b, err)
+				require.NotNil(b, resp)
+
+				// Read first 20 results (if any)
+				bytesRead += int(resp.Metrics.InspectedBytes)
+			}
+			b.SetBytes(int64(bytesRead) / int64(b.N))
+			b.ReportMetric(float64(bytesRead)/float64(b.N)/1000.0/1000.0, "MB_io/op")
+		})
+	}
+}
+
+func BenchmarkBackendBlockTraceQLRelations(b *testing.B) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"span", "{ span.foo = `bar` }"},
+		{"parent", "{ parent.name = `a` && span.foo = `bar` }"},
+		{"parentResource", "{ parent.resource.service.name = `frontend` && span.foo = `bar` }"},
+		{"structural", "{ name = `a` } > { span.foo = `bar` }"},
+	}
+
+	traces := make([]*Trace, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		traces = append(traces, relationsTestTrace(test.ValidTraceID(nil), 100))
+	}
+	block := makeBackendBlockWithTraces(b, traces)
+
+	for _, tc := range testCases {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				searchSpanIDs(b, block, tc.query)
+			}
+		})
+	}
+}
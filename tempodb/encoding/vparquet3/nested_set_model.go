@@ -0,0 +1,68 @@
+package vparquet3
+
+// assignNestedSetModelBounds numbers the spans of the trace using the nested set model and stores the
+// values in the ParentID, NestedSetLeft and NestedSetRight columns. Structural queries read them instead
+// of computing the relationships of every span at query time. Numbering starts at 1 so 0 means unset,
+// roots and spans whose parent isn't found in the trace have a ParentID of 0.
+func assignNestedSetModelBounds(trace *Trace) {
+	var (
+		ids      = map[string]struct{}{}
+		children = map[string][]*Span{}
+		roots    []*Span
+	)
+
+	for ib := range trace.ResourceSpans {
+		for is := range trace.ResourceSpans[ib].ScopeSpans {
+			for i := range trace.ResourceSpans[ib].ScopeSpans[is].Spans {
+				ids[string(trace.ResourceSpans[ib].ScopeSpans[is].Spans[i].SpanID)] = struct{}{}
+			}
+		}
+	}
+
+	for ib := range trace.ResourceSpans {
+		for is := range trace.ResourceSpans[ib].ScopeSpans {
+			for i := range trace.ResourceSpans[ib].ScopeSpans[is].Spans {
+				s := &trace.ResourceSpans[ib].ScopeSpans[is].Spans[i]
+				if _, ok := ids[string(s.ParentSpanID)]; len(s.ParentSpanID) == 0 || !ok {
+					roots = append(roots, s)
+					continue
+				}
+				children[string(s.ParentSpanID)] = append(children[string(s.ParentSpanID)], s)
+			}
+		}
+	}
+
+	var (
+		next  int32 = 1
+		visit func(s *Span, parent int32)
+	)
+	visit = func(s *Span, parent int32) {
+		s.ParentID = parent
+		s.NestedSetLeft = next
+		next++
+
+		// deleting the children protects against cycles in malformed traces
+		cs := children[string(s.SpanID)]
+		delete(children, string(s.SpanID))
+		for _, c := range cs {
+			visit(c, s.NestedSetLeft)
+		}
+
+		s.NestedSetRight = next
+		next++
+	}
+
+	for _, root := range roots {
+		visit(root, 0)
+	}
+
+	// spans that are only part of a cycle are never reached from a root. clear values
+	// left over from a reused trace so the query falls back to computing them
+	for _, cs := range children {
+		for _, c := range cs {
+			c.ParentID = 0
+			c.NestedSetLeft = 0
+			c.NestedSetRight = 0
+		}
+	}
+}
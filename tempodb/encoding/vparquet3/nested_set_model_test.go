@@ -0,0 +1,51 @@
+package vparquet3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignNestedSetModelBounds(t *testing.T) {
+	id := func(i byte) []byte {
+		if i == 0 {
+			return nil
+		}
+		return []byte{0, 0, 0, 0, 0, 0, 0, i}
+	}
+	makeSpan := func(i, parent byte) Span {
+		return Span{SpanID: id(i), ParentSpanID: id(parent)}
+	}
+
+	// 1 is the root of 2 and 3, 4 is a child of 2. 5's parent isn't in the trace
+	// and 6 and 7 are each other's parents
+	tr := &Trace{
+		ResourceSpans: []ResourceSpans{
+			{ScopeSpans: []ScopeSpans{{Spans: []Span{makeSpan(1, 0), makeSpan(3, 1)}}}},
+			{ScopeSpans: []ScopeSpans{{Spans: []Span{makeSpan(2, 1), makeSpan(4, 2), makeSpan(5, 9), makeSpan(6, 7), makeSpan(7, 6)}}}},
+		},
+	}
+	// values of a reused trace are overwritten
+	tr.ResourceSpans[1].ScopeSpans[0].Spans[3].NestedSetLeft = 42
+
+	assignNestedSetModelBounds(tr)
+
+	actual := map[byte][3]int32{}
+	for _, rs := range tr.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				actual[s.SpanID[7]] = [3]int32{s.ParentID, s.NestedSetLeft, s.NestedSetRight}
+			}
+		}
+	}
+
+	require.Equal(t, map[byte][3]int32{
+		1: {0, 1, 8},
+		3: {1, 2, 3},
+		2: {1, 4, 7},
+		4: {4, 5, 6},
+		5: {0, 9, 10},
+		6: {0, 0, 0},
+		7: {0, 0, 0},
+	}, actual)
+}
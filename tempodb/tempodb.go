@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	gkLog "github.com/go-kit/log"
@@ -74,10 +75,18 @@ type Writer interface {
 
 type IterateObjectCallback func(id common.ID, obj []byte) bool
 
+// FindCallback is called with the part of a trace found in a block. Calls are serialized.
+type FindCallback func(partialTrace *tempopb.Trace) error
+
 type Reader interface {
 	Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64) ([]*tempopb.Trace, []error, error)
+	FindFunc(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, cb FindCallback) ([]error, error)
 	Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error)
 	Fetch(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchSpansRequest, opts common.SearchOptions) (traceql.FetchSpansResponse, error)
+	SearchTags(ctx context.Context, meta *backend.BlockMeta, scope traceql.AttributeScope, cb common.TagCallback, opts common.SearchOptions) error
+	SearchTagValues(ctx context.Context, meta *backend.BlockMeta, tag string, cb common.TagCallback, opts common.SearchOptions) error
+	SearchTagValuesV2(ctx context.Context, meta *backend.BlockMeta, tag traceql.Attribute, cb common.TagCallbackV2, opts common.SearchOptions) error
+	SearchTagValueCounts(ctx context.Context, meta *backend.BlockMeta, tag traceql.Attribute, cb common.TagValueCountCallback, opts common.SearchOptions) error
 	BlockMetas(tenantID string) []*backend.BlockMeta
 	EnablePolling(sharder blocklist.JobSharder)
 
@@ -162,9 +171,9 @@ func New(cfg *Config, logger gkLog.Logger) (Reader, Writer, Compactor, error) {
 
 	switch cfg.Cache {
 	case "redis":
-		cacheBackend = redis.NewClient(cfg.Redis, cfg.BackgroundCache, logger)
+		cacheBackend = redis.NewClient("tempo", cfg.Redis, cfg.BackgroundCache, logger)
 	case "memcached":
-		cacheBackend = memcached.NewClient(cfg.Memcached, cfg.BackgroundCache, logger)
+		cacheBackend = memcached.NewClient("tempo", cfg.Memcached, cfg.BackgroundCache, logger)
 	}
 
 	if cacheBackend != nil {
@@ -265,6 +274,21 @@ func (rw *readerWriter) BlockMetas(tenantID string) []*backend.BlockMeta {
 }
 
 func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64) ([]*tempopb.Trace, []error, error) {
+	var partialTraces []*tempopb.Trace
+	funcErrs, err := rw.FindFunc(ctx, tenantID, id, blockStart, blockEnd, timeStart, timeEnd, func(partialTrace *tempopb.Trace) error {
+		partialTraces = append(partialTraces, partialTrace)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return partialTraces, funcErrs, nil
+}
+
+// FindFunc passes the parts of the trace found in the blocks to cb as the blocks are read. An error returned by
+// cb fails the block like an error reading it.
+func (rw *readerWriter) FindFunc(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64, cb FindCallback) ([]error, error) {
 	// tracing instrumentation
 	logger := log.WithContext(ctx, log.Logger)
 	span, ctx := opentracing.StartSpanFromContext(ctx, "store.Find")
@@ -272,19 +296,19 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 
 	blockStartUUID, err := uuid.Parse(blockStart)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	blockStartBytes, err := blockStartUUID.MarshalBinary()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	blockEndUUID, err := uuid.Parse(blockEnd)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	blockEndBytes, err := blockEndUUID.MarshalBinary()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// gather appropriate blocks
@@ -307,7 +331,7 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 		}
 	}
 	if len(copiedBlocklist) == 0 {
-		return nil, nil, nil
+		return nil, nil
 	}
 
 	opts := common.DefaultSearchOptions()
@@ -315,8 +339,9 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 		rw.cfg.Search.ApplyToOptions(&opts)
 	}
 
+	var mtx sync.Mutex
 	curTime := time.Now()
-	partialTraces, funcErrs, err := rw.pool.RunJobs(ctx, copiedBlocklist, func(ctx context.Context, payload interface{}) (interface{}, error) {
+	_, funcErrs, err := rw.pool.RunJobs(ctx, copiedBlocklist, func(ctx context.Context, payload interface{}) (interface{}, error) {
 		meta := payload.(*backend.BlockMeta)
 		r := rw.getReaderForBlock(meta, curTime)
 		block, err := encoding.OpenBlock(meta, r)
@@ -330,13 +355,14 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 		}
 
 		level.Info(logger).Log("msg", "searching for trace in block", "findTraceID", hex.EncodeToString(id), "block", meta.BlockID, "found", foundObject != nil)
-		return foundObject, nil
-	})
+		if foundObject == nil {
+			return nil, nil
+		}
 
-	partialTraceObjs := make([]*tempopb.Trace, len(partialTraces))
-	for i := range partialTraces {
-		partialTraceObjs[i] = partialTraces[i].(*tempopb.Trace)
-	}
+		mtx.Lock()
+		defer mtx.Unlock()
+		return nil, cb(foundObject)
+	})
 
 	span.SetTag("blockErrs", len(funcErrs))
 	span.SetTag("liveBlocks", len(blocklist))
@@ -344,7 +370,7 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 	span.SetTag("compactedBlocks", len(compactedBlocklist))
 	span.SetTag("compactedBlocksSearched", compactedBlocksSearched)
 
-	return partialTraceObjs, funcErrs, err
+	return funcErrs, err
 }
 
 // Search the given block.  This method takes the pre-loaded block meta instead of a block ID, which
@@ -369,6 +395,46 @@ func (rw *readerWriter) Fetch(ctx context.Context, meta *backend.BlockMeta, req
 	return block.Fetch(ctx, req, opts)
 }
 
+func (rw *readerWriter) SearchTags(ctx context.Context, meta *backend.BlockMeta, scope traceql.AttributeScope, cb common.TagCallback, opts common.SearchOptions) error {
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return err
+	}
+
+	rw.cfg.Search.ApplyToOptions(&opts)
+	return block.SearchTags(ctx, scope, cb, opts)
+}
+
+func (rw *readerWriter) SearchTagValues(ctx context.Context, meta *backend.BlockMeta, tag string, cb common.TagCallback, opts common.SearchOptions) error {
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return err
+	}
+
+	rw.cfg.Search.ApplyToOptions(&opts)
+	return block.SearchTagValues(ctx, tag, cb, opts)
+}
+
+func (rw *readerWriter) SearchTagValuesV2(ctx context.Context, meta *backend.BlockMeta, tag traceql.Attribute, cb common.TagCallbackV2, opts common.SearchOptions) error {
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return err
+	}
+
+	rw.cfg.Search.ApplyToOptions(&opts)
+	return block.SearchTagValuesV2(ctx, tag, cb, opts)
+}
+
+func (rw *readerWriter) SearchTagValueCounts(ctx context.Context, meta *backend.BlockMeta, tag traceql.Attribute, cb common.TagValueCountCallback, opts common.SearchOptions) error {
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return err
+	}
+
+	rw.cfg.Search.ApplyToOptions(&opts)
+	return block.SearchTagValueCounts(ctx, tag, cb, opts)
+}
+
 func (rw *readerWriter) Shutdown() {
 	// todo: stop blocklist poll
 	rw.pool.Shutdown()
@@ -26,6 +26,7 @@ import (
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
 	"github.com/grafana/tempo/tempodb/wal"
 	"github.com/stretchr/testify/require"
 )
@@ -197,6 +198,30 @@ func testAdvancedTraceQLCompleteBlock(t *testing.T, blockVersion string) {
 				rando(trueConditionsBySpan[0]), rando(trueConditionsBySpan[0]),
 				rando(trueConditionsBySpan[1]), rando(trueConditionsBySpan[1]),
 				durationBySpan[0]+durationBySpan[1])},
+			// parent
+			{Query: "{ parent.name = `RootSpan` && name = `MySpan` }"},
+			{Query: "{ parent.resource.service.name = `RootService` && span.foo = `Bar` }"},
+			{Query: "{ parent.duration = 2s && parent.status = unset }"},
+			// structural
+			{Query: "{ name = `RootSpan` } > { name = `MySpan` }"},
+			{Query: "{ name = `RootSpan` } >> { name = `MySpan` }"},
+			{Query: "{ name = `MySpan` } < { name = `RootSpan` }"},
+			{Query: "{ name = `MySpan` } << { name = `RootSpan` }"},
+			{Query: "{ name = `MySpan` } !> { name = `RootSpan` }"},
+			{Query: "{ name = `MySpan` } !>> { name = `RootSpan` }"},
+			{Query: "{ } !~ { }"},
+			// events and links
+			{Query: "{ event.name = `exception` && event.exception.type = `TimeoutError` }"},
+			{Query: "{ event.name = `retry` && event.attempt > 1 }"},
+			{Query: "{ event.exception.type = `Other` || event.attempt = 2 }"},
+			{Query: "{ link.traceID = `abcd` }"},
+			{Query: "{ link.relationship = `follows` && name = `MySpan` }"},
+			// instrumentation
+			{Query: "{ instrumentation.name = `io.opentelemetry.jdbc` && instrumentation.version < `1.20` }"},
+			{Query: "{ instrumentation.name = `io.opentelemetry.jdbc` && span.foo = `Bar` && resource.service.name = `MyService` }"},
+			// arrays
+			{Query: "{ event.codes contains 504 }"},
+			{Query: "{ event.codes[0] = 503 && name = `MySpan` }"},
 		}
 		searchesThatDontMatch := []*tempopb.SearchRequest{
 			// conditions
@@ -225,6 +250,37 @@ func testAdvancedTraceQLCompleteBlock(t *testing.T, blockVersion string) {
 			{Query: "{ } | min(duration) < 0"},
 			{Query: "{ } | max(duration) < 0"},
 			{Query: "{ } | sum(duration) < 0"},
+			// parent
+			{Query: "{ parent.name = `MySpan` }"},
+			{Query: "{ parent.span.foo = `Bar` }"},
+			{Query: "{ parent.resource.service.name = `MyService` }"},
+			// structural
+			{Query: "{ name = `MySpan` } > { name = `RootSpan` }"},
+			{Query: "{ name = `MySpan` } >> { }"},
+			{Query: "{ name = `RootSpan` } < { }"},
+			{Query: "{ name = `RootSpan` } !>> { name = `MySpan` }"},
+			{Query: "{ } ~ { }"},
+			// events and links
+			{Query: "{ event.name = `exception` && event.attempt = 2 }"},
+			{Query: "{ event.exception.type = `Other` }"},
+			{Query: "{ event.name = `retry` && resource.service.name = `RootService` }"},
+			{Query: "{ link.traceID = `dcba` }"},
+			{Query: "{ link.relationship = `follows` && name = `RootSpan` }"},
+			// instrumentation
+			{Query: "{ instrumentation.name = `io.opentelemetry.jdbc` && name = `RootSpan` }"},
+			{Query: "{ instrumentation.version >= `1.20` }"},
+			// arrays
+			{Query: "{ span.tags contains `gamma` }"},
+			{Query: "{ event.codes contains 500 }"},
+			{Query: "{ event.codes[1] = 503 }"},
+		}
+
+		// only vParquet3 stores the elements of span and resource arrays
+		if meta.Version == vparquet3.VersionString {
+			searchesThatMatch = append(searchesThatMatch,
+				&tempopb.SearchRequest{Query: "{ span.tags contains `beta` }"},
+				&tempopb.SearchRequest{Query: "{ span.tags[0] = `alpha` && resource.ports contains 8080 }"},
+			)
 		}
 
 		for _, req := range searchesThatMatch {
@@ -249,6 +305,22 @@ func testAdvancedTraceQLCompleteBlock(t *testing.T, blockVersion string) {
 			require.NoError(t, err, "search request: %+v", req)
 			require.Nil(t, actualForExpectedMeta(wantMeta, res), "search request: %v", req)
 		}
+
+		// select returns attributes that aren't part of the filter
+		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+			return r.Fetch(ctx, meta, req, common.DefaultSearchOptions())
+		})
+		res, err := e.ExecuteSearch(ctx, &tempopb.SearchRequest{Query: "{ name = `MySpan` } | select(span.http.status_code, resource.k8s.pod.name)"}, fetcher)
+		require.NoError(t, err)
+		actual := actualForExpectedMeta(wantMeta, res)
+		require.NotNil(t, actual)
+		require.Len(t, actual.SpanSet.Spans, 1)
+		selected := map[string]*v1_common.AnyValue{}
+		for _, kv := range actual.SpanSet.Spans[0].Attributes {
+			selected[kv.Key] = kv.Value
+		}
+		require.Equal(t, intKV("", 500).Value, selected["http.status_code"])
+		require.Equal(t, stringKV("", "k8sPod").Value, selected["k8s.pod.name"])
 	})
 }
 
@@ -394,6 +466,13 @@ func intKV(k string, v int) *v1_common.KeyValue {
 	}
 }
 
+func arrayKV(k string, v ...*v1_common.AnyValue) *v1_common.KeyValue {
+	return &v1_common.KeyValue{
+		Key:   k,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: &v1_common.ArrayValue{Values: v}}},
+	}
+}
+
 // Helper function to make a tag search
 func makeReq(k, v string) *tempopb.SearchRequest {
 	return &tempopb.SearchRequest{
@@ -481,10 +560,15 @@ func searchTestSuite() (
 						stringKV("k8s.pod.name", "k8sPod"),
 						stringKV("k8s.container.name", "k8sContainer"),
 						stringKV("bat", "Baz"),
+						arrayKV("ports", intKV("", 8080).Value, intKV("", 9090).Value),
 					},
 				},
 				ScopeSpans: []*v1.ScopeSpans{
 					{
+						Scope: &v1_common.InstrumentationScope{
+							Name:    "io.opentelemetry.jdbc",
+							Version: "1.19.0",
+						},
 						Spans: []*v1.Span{
 							{
 								TraceId:           id,
@@ -501,6 +585,31 @@ func searchTestSuite() (
 									stringKV("http.url", "url/Hello/World"),
 									intKV("http.status_code", 500),
 									stringKV("foo", "Bar"),
+									arrayKV("tags", stringKV("", "alpha").Value, stringKV("", "beta").Value),
+								},
+								Events: []*v1.Span_Event{
+									{
+										Name: "exception",
+										Attributes: []*v1_common.KeyValue{
+											stringKV("exception.type", "TimeoutError"),
+										},
+									},
+									{
+										Name: "retry",
+										Attributes: []*v1_common.KeyValue{
+											intKV("attempt", 2),
+											arrayKV("codes", intKV("", 503).Value, intKV("", 504).Value),
+										},
+									},
+								},
+								Links: []*v1.Span_Link{
+									{
+										TraceId: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xab, 0xcd},
+										SpanId:  []byte{7, 8, 9},
+										Attributes: []*v1_common.KeyValue{
+											stringKV("relationship", "follows"),
+										},
+									},
 								},
 							},
 						},
@@ -519,6 +628,7 @@ func searchTestSuite() (
 							{
 								TraceId:           id,
 								Name:              "RootSpan",
+								SpanId:            []byte{4, 5, 6},
 								StartTimeUnixNano: uint64(1000 * time.Second),
 								EndTimeUnixNano:   uint64(1002 * time.Second),
 								Status:            &v1.Status{},
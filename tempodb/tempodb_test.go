@@ -2,6 +2,7 @@ package tempodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
@@ -109,6 +110,26 @@ func TestDB(t *testing.T) {
 		assert.Nil(t, failedBlocks)
 		assert.True(t, proto.Equal(bFound[0], reqs[i]))
 	}
+
+	// read with a callback
+	for i, id := range ids {
+		var found []*tempopb.Trace
+		failedBlocks, err := r.FindFunc(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0, func(partialTrace *tempopb.Trace) error {
+			found = append(found, partialTrace)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, failedBlocks)
+		assert.Len(t, found, 1)
+		assert.True(t, proto.Equal(found[0], reqs[i]))
+	}
+
+	// errors of the callback fail the block
+	failedBlocks, err := r.FindFunc(context.Background(), testTenantID, ids[0], BlockIDMin, BlockIDMax, 0, 0, func(*tempopb.Trace) error {
+		return errors.New("failed")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []error{errors.New("failed")}, failedBlocks)
 }
 
 func TestNoCompactionWhenCompactionRange0(t *testing.T) {